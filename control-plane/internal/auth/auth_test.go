@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllows(t *testing.T) {
+	tests := []struct {
+		role   string
+		team   string
+		method string
+		path   string
+		want   bool
+	}{
+		{types.APIKeyRoleAdmin, "", http.MethodPost, "/api/v1/keys", true},
+		{types.APIKeyRoleOperator, "", http.MethodGet, "/api/v1/keys", false},
+		{types.APIKeyRoleOperator, "", http.MethodPost, "/api/v1/schedules", true},
+		{types.APIKeyRoleAgent, "", http.MethodPost, "/api/v1/execute/node.reasoner", true},
+		{types.APIKeyRoleAgent, "", http.MethodPost, "/api/v1/nodes/register", true},
+		{types.APIKeyRoleAgent, "", http.MethodPost, "/api/v1/schedules", false},
+		{types.APIKeyRoleAgent, "", http.MethodGet, "/api/ui/v1/nodes/summary", false},
+		{types.APIKeyRoleAgent, "", http.MethodGet, "/api/v1/executionsx", false},
+		{types.APIKeyRoleReadOnly, "", http.MethodGet, "/api/ui/v1/nodes/summary", true},
+		{types.APIKeyRoleReadOnly, "", http.MethodPost, "/api/v1/memory/get", true},
+		{types.APIKeyRoleReadOnly, "", http.MethodPost, "/api/v1/memory/set", false},
+		{types.APIKeyRoleReadOnly, "", http.MethodPost, "/api/v1/execute/node.reasoner", false},
+		{types.APIKeyRoleReadOnly, "", http.MethodGet, "/api/v1/keys", false},
+		{types.APIKeyRoleAdmin, "", http.MethodGet, "/api/v1/memory/export", true},
+		{types.APIKeyRoleOperator, "", http.MethodPost, "/api/v1/memory/restore", false},
+		{types.APIKeyRoleAgent, "", http.MethodPost, "/api/v1/memory/import", false},
+		{types.APIKeyRoleReadOnly, "", http.MethodGet, "/api/v1/memory/export", false},
+		{types.APIKeyRoleAdmin, "", http.MethodPost, "/api/v1/memory/encryption/rotate", true},
+		{types.APIKeyRoleOperator, "", http.MethodGet, "/api/v1/memory/encryption", false},
+		{types.APIKeyRoleAdmin, "team-a", http.MethodGet, "/api/v1/memory/export", false},
+		{types.APIKeyRoleAdmin, "team-a", http.MethodPost, "/api/v1/memory/encryption/rotate", false},
+		{types.APIKeyRoleAdmin, "team-a", http.MethodPost, "/api/v1/memory/set", true},
+		{"unknown", "", http.MethodGet, "/api/v1/nodes", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Allows(&Principal{TeamID: tt.team, Role: tt.role}, tt.method, tt.path), "%s %q %s %s", tt.role, tt.team, tt.method, tt.path)
+	}
+}
+
+func TestPrincipalTeamAccess(t *testing.T) {
+	ctx := context.Background()
+	assert.True(t, CanAccessTeam(ctx, "team-a"), "requests without a principal are unrestricted")
+
+	root := WithPrincipal(ctx, &Principal{Role: types.APIKeyRoleAdmin})
+	assert.Equal(t, "", TeamFrom(root))
+	assert.True(t, CanAccessTeam(root, "team-b"))
+
+	scoped := WithPrincipal(ctx, &Principal{KeyID: "key-1", TeamID: "team-a", Role: types.APIKeyRoleOperator})
+	assert.Equal(t, "team-a", TeamFrom(scoped))
+	assert.True(t, CanAccessTeam(scoped, "team-a"))
+	assert.False(t, CanAccessTeam(scoped, "team-b"))
+}
+
+func TestGenerateSecret(t *testing.T) {
+	first, err := GenerateSecret()
+	require.NoError(t, err)
+	second, err := GenerateSecret()
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+	assert.True(t, strings.HasPrefix(first, secretPrefix))
+	assert.Equal(t, HashSecret(first), HashSecret(first))
+	assert.NotEqual(t, HashSecret(first), HashSecret(second))
+	assert.Len(t, SecretDisplayPrefix(first), len(secretPrefix)+8)
+}
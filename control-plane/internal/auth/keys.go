@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// secretPrefix marks secrets of team-scoped API keys.
+const secretPrefix = "afk_"
+
+// GenerateSecret returns a new random API key secret.
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate api key secret: %w", err)
+	}
+	return secretPrefix + hex.EncodeToString(buf), nil
+}
+
+// HashSecret returns the digest stored for an API key secret.
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// SecretDisplayPrefix returns the part of a secret kept to tell keys apart.
+func SecretDisplayPrefix(secret string) string {
+	const length = len(secretPrefix) + 8
+	if len(secret) <= length {
+		return secret
+	}
+	return secret[:length]
+}
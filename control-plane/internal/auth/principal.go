@@ -0,0 +1,49 @@
+// Package auth describes who is calling the control plane and what their API key
+// allows them to do.
+package auth
+
+import "context"
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	// KeyID is the stored API key used, empty for the shared key from configuration.
+	KeyID string
+	// TeamID is the team the caller is confined to. It is empty for the shared key,
+	// which spans every team.
+	TeamID string
+	Role   string
+}
+
+// TeamScoped reports whether the principal is confined to one team.
+func (p *Principal) TeamScoped() bool {
+	return p != nil && p.TeamID != ""
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the caller.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the caller stored in ctx, or nil when authentication is
+// disabled or the work was not started by a request, e.g. a schedule firing.
+func PrincipalFrom(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
+
+// TeamFrom returns the team the caller in ctx is confined to, or "" when the caller
+// may see every team.
+func TeamFrom(ctx context.Context) string {
+	if principal := PrincipalFrom(ctx); principal.TeamScoped() {
+		return principal.TeamID
+	}
+	return ""
+}
+
+// CanAccessTeam reports whether the caller in ctx may see resources of teamID.
+func CanAccessTeam(ctx context.Context, teamID string) bool {
+	team := TeamFrom(ctx)
+	return team == "" || team == teamID
+}
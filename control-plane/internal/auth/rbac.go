@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+)
+
+// keyManagementPath is reserved for admins.
+const keyManagementPath = "/api/v1/keys"
+
+// memoryAdminPaths are reserved for admins that span every team: they read and write
+// the memory of all teams wholesale, past the access policies of individual keys, or
+// manage the encryption keys every team's memory shares.
+var memoryAdminPaths = []string{
+	"/api/v1/memory/export",
+	"/api/v1/memory/import",
+	"/api/v1/memory/restore",
+	"/api/v1/memory/encryption",
+}
+
+// agentPaths are the API prefixes an agent node uses.
+var agentPaths = []string{
+	"/api/v1/nodes",
+	"/api/v1/actions",
+	"/api/v1/execute",
+	"/api/v1/executions",
+	"/api/v1/workflow",
+	"/api/v1/memory",
+	"/api/v1/discovery",
+	"/api/v1/did",
+	"/api/v1/reasoners",
+	"/api/v1/skills",
+}
+
+// readOnlyPosts are POST endpoints that only read.
+var readOnlyPosts = map[string]struct{}{
+	"/api/v1/memory/get":              {},
+	"/api/v1/memory/vector/search":    {},
+	"/api/v1/executions/batch-status": {},
+	"/api/v1/nodes/status/bulk":       {},
+}
+
+// Allows reports whether principal may call method on path.
+func Allows(principal *Principal, method, path string) bool {
+	role := principal.Role
+	if hasPathPrefix(path, keyManagementPath) {
+		return role == types.APIKeyRoleAdmin
+	}
+	for _, prefix := range memoryAdminPaths {
+		if hasPathPrefix(path, prefix) {
+			return role == types.APIKeyRoleAdmin && !principal.TeamScoped()
+		}
+	}
+	switch role {
+	case types.APIKeyRoleAdmin, types.APIKeyRoleOperator:
+		return true
+	case types.APIKeyRoleAgent:
+		for _, prefix := range agentPaths {
+			if hasPathPrefix(path, prefix) {
+				return true
+			}
+		}
+		return false
+	case types.APIKeyRoleReadOnly:
+		if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+			return true
+		}
+		_, ok := readOnlyPosts[path]
+		return ok && method == http.MethodPost
+	default:
+		return false
+	}
+}
+
+// hasPathPrefix matches whole path segments, so /api/v1/executions does not match
+// /api/v1/execute.
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
@@ -1,7 +1,11 @@
 package cli
 
 import (
+	"bytes"
+	"encoding/json"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -122,6 +126,142 @@ func TestVCCommand(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// TestScheduleCommand tests that schedule subcommands call the control plane API
+func TestScheduleCommand(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	resetCLIStateForTest()
+
+	var (
+		gotMethod string
+		gotPath   string
+		gotAuth   string
+		gotBody   map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath, gotAuth = r.Method, r.URL.Path, r.Header.Get("Authorization")
+		gotBody = nil
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		if r.URL.Path == "/api/v1/schedules/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"schedule missing not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"schedule_id":"sched_1","next_run_at":"2024-03-16T02:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		cmd := NewScheduleCommand()
+		cmd.SetOut(&out)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(append(args, "--server", server.URL, "--token", "secret-token"))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("create", "--name", "nightly", "--cron", "0 2 * * *", "--target", "node-1.report", "--input", `{"day":"{{.ScheduledAt}}"}`)
+	require.NoError(t, err)
+	require.Contains(t, out, "Created schedule sched_1")
+	require.Equal(t, http.MethodPost, gotMethod)
+	require.Equal(t, "/api/v1/schedules", gotPath)
+	require.Equal(t, "Bearer secret-token", gotAuth)
+	require.Equal(t, "0 2 * * *", gotBody["cron_expression"])
+	require.Equal(t, map[string]any{"day": "{{.ScheduledAt}}"}, gotBody["input_template"])
+	require.Equal(t, true, gotBody["enabled"])
+
+	_, err = run("disable", "sched_1")
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPatch, gotMethod)
+	require.Equal(t, "/api/v1/schedules/sched_1", gotPath)
+	require.Equal(t, map[string]any{"enabled": false}, gotBody)
+
+	_, err = run("create", "--name", "nightly", "--cron", "@daily")
+	require.ErrorContains(t, err, "--target is required")
+
+	_, err = run("delete", "missing")
+	require.ErrorContains(t, err, "schedule missing not found")
+}
+
+func TestMemoryCommand(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	resetCLIStateForTest()
+
+	var (
+		gotPath  string
+		gotQuery string
+		gotBody  string
+	)
+	exportBody := `{"kind":"memory","memory":{"key":"a"}}` + "\n" + `{"kind":"vector","vector":{"key":"v"}}` + "\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		switch r.URL.Path {
+		case "/api/v1/memory/export":
+			if r.URL.Query().Get("scope") == "broken" {
+				_, _ = w.Write([]byte(`{"kind":"memory","memory":{"key":"a"}}` + "\n" + `{"kind":"error","error":"disk failure"}` + "\n"))
+				return
+			}
+			_, _ = w.Write([]byte(exportBody))
+		case "/api/v1/memory/import":
+			_, _ = w.Write([]byte(`{"memories":1,"vectors":1,"skipped":0}`))
+		case "/api/v1/memory/restore":
+			_, _ = w.Write([]byte(`{"set":1,"deleted":0,"changes":[{"scope":"session","scope_id":"s1","key":"a","action":"set"}],"unrecoverable":[]}`))
+		case "/api/v1/memory/encryption/rotate":
+			_, _ = w.Write([]byte(`{"key_id":"memory-2","reencrypted":4}`))
+		}
+	}))
+	defer server.Close()
+
+	run := func(stdin string, args ...string) (string, error) {
+		var out bytes.Buffer
+		cmd := NewMemoryCommand()
+		cmd.SetOut(&out)
+		cmd.SetErr(io.Discard)
+		cmd.SetIn(strings.NewReader(stdin))
+		cmd.SetArgs(append(args, "--server", server.URL))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("", "export", "--scope", "session", "--prefix", "plan:", "--no-vectors")
+	require.NoError(t, err)
+	require.Equal(t, exportBody, out)
+	require.Equal(t, "prefix=plan%3A&scope=session&vectors=false", gotQuery)
+
+	file := filepath.Join(t.TempDir(), "memory.ndjson")
+	_, err = run("", "export", "-o", file)
+	require.NoError(t, err)
+	written, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, exportBody, string(written))
+
+	_, err = run("", "export", "--scope", "broken")
+	require.ErrorContains(t, err, "export failed after 1 memories and 0 vectors: disk failure")
+
+	out, err = run(exportBody, "import")
+	require.NoError(t, err)
+	require.Equal(t, "/api/v1/memory/import", gotPath)
+	require.Equal(t, exportBody, gotBody)
+	require.Contains(t, out, "Imported 1 memories and 1 vectors")
+
+	out, err = run("", "restore", "--to", "2024-03-15T10:00:00Z", "--scope-id", "s1", "--dry-run")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"timestamp":"2024-03-15T10:00:00Z","scope_id":"s1","dry_run":true}`, gotBody)
+	require.Contains(t, out, "set    session/s1/a")
+	require.Contains(t, out, "Would set 1 keys and delete 0")
+
+	_, err = run("", "restore", "--to", "yesterday")
+	require.ErrorContains(t, err, "--to must be an RFC 3339 timestamp")
+
+	out, err = run("", "rotate-key", "--reencrypt")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"reencrypt":true}`, gotBody)
+	require.Contains(t, out, "Rotated memory encryption to key memory-2")
+	require.Contains(t, out, "Re-encrypted 4 values")
+}
+
 // TestVersionCommand tests the version command
 func TestVersionCommand(t *testing.T) {
 	t.Setenv("HOME", t.TempDir())
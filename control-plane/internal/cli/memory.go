@@ -0,0 +1,395 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// NewMemoryCommand groups memory backup and restore subcommands.
+func NewMemoryCommand() *cobra.Command {
+	opts := &memoryClientOptions{
+		serverURL: os.Getenv("AGENTFIELD_SERVER"),
+		token:     os.Getenv("AGENTFIELD_TOKEN"),
+		timeout:   10 * time.Minute,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "memory",
+		Short: "Export, import and restore agent memory",
+		Long: `Export, import and restore agent memory, and rotate its encryption key.
+
+Exports are NDJSON, one key-value entry or vector per line, and can be imported into
+the same or another control plane. These commands require an admin API key.`,
+	}
+
+	cmd.PersistentFlags().StringVar(&opts.serverURL, "server", opts.serverURL, "Control plane URL (default: http://localhost:8080 or $AGENTFIELD_SERVER)")
+	cmd.PersistentFlags().StringVar(&opts.token, "token", opts.token, "Bearer token for the control plane (default: $AGENTFIELD_TOKEN)")
+	cmd.PersistentFlags().DurationVar(&opts.timeout, "timeout", opts.timeout, "HTTP timeout for control plane requests")
+	cmd.PersistentFlags().BoolVar(&opts.jsonOutput, "json", false, "Print raw JSON response")
+
+	cmd.AddCommand(newMemoryExportCommand(opts))
+	cmd.AddCommand(newMemoryImportCommand(opts))
+	cmd.AddCommand(newMemoryRestoreCommand(opts))
+	cmd.AddCommand(newMemoryRotateKeyCommand(opts))
+	return cmd
+}
+
+type memoryClientOptions struct {
+	serverURL  string
+	token      string
+	timeout    time.Duration
+	jsonOutput bool
+}
+
+// memorySelection holds the flags that select the memory a command covers.
+type memorySelection struct {
+	scope   string
+	scopeID string
+	prefix  string
+}
+
+func (s *memorySelection) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&s.scope, "scope", "", "Memory scope: workflow, session, actor, reasoner or global (default all)")
+	cmd.Flags().StringVar(&s.scopeID, "scope-id", "", "Scope ID, e.g. a workflow or session ID (default all)")
+	cmd.Flags().StringVar(&s.prefix, "prefix", "", "Only keys starting with this prefix")
+}
+
+func newMemoryExportCommand(opts *memoryClientOptions) *cobra.Command {
+	selection := &memorySelection{}
+	var (
+		output    string
+		noVectors bool
+		asOf      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export [--scope <scope>] [--scope-id <id>] [--prefix <prefix>] [-o <file>]",
+		Short: "Export memory as NDJSON",
+		Long: `Export key-value memory and vectors as NDJSON, to stdout or a file.
+
+With --as-of the key-value memory is exported as it was at that time, rebuilt from the
+memory change history; vectors are left out.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			query := url.Values{}
+			if selection.scope != "" {
+				query.Set("scope", selection.scope)
+			}
+			if selection.scopeID != "" {
+				query.Set("scope_id", selection.scopeID)
+			}
+			if selection.prefix != "" {
+				query.Set("prefix", selection.prefix)
+			}
+			if noVectors {
+				query.Set("vectors", "false")
+			}
+			if asOf != "" {
+				if _, err := time.Parse(time.RFC3339, asOf); err != nil {
+					return fmt.Errorf("--as-of must be an RFC 3339 timestamp: %w", err)
+				}
+				query.Set("as_of", asOf)
+			}
+
+			resp, err := opts.send(http.MethodGet, "/api/v1/memory/export?"+query.Encode(), nil, "")
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+
+			out := cmd.OutOrStdout()
+			if output != "" {
+				file, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("create output file: %w", err)
+				}
+				defer file.Close()
+				out = file
+			}
+
+			counts, err := copyMemoryExport(out, resp.Body)
+			if err != nil {
+				return err
+			}
+			if output != "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d memories and %d vectors to %s\n", counts.memories, counts.vectors, output)
+			}
+			return nil
+		},
+	}
+
+	selection.register(cmd)
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the export to a file instead of stdout")
+	cmd.Flags().BoolVar(&noVectors, "no-vectors", false, "Leave out vectors")
+	cmd.Flags().StringVar(&asOf, "as-of", "", "Export key-value memory as it was at this RFC 3339 time")
+	return cmd
+}
+
+type memoryExportCounts struct {
+	memories int
+	vectors  int
+}
+
+// copyMemoryExport copies an export stream to out line by line, failing on the error
+// record that ends an incomplete export.
+func copyMemoryExport(out io.Writer, stream io.Reader) (memoryExportCounts, error) {
+	var counts memoryExportCounts
+	reader := bufio.NewReader(stream)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var record struct {
+				Kind  string `json:"kind"`
+				Error string `json:"error"`
+			}
+			if jsonErr := json.Unmarshal(line, &record); jsonErr != nil {
+				return counts, fmt.Errorf("decode export: %w", jsonErr)
+			}
+			switch record.Kind {
+			case "error":
+				return counts, fmt.Errorf("export failed after %d memories and %d vectors: %s", counts.memories, counts.vectors, record.Error)
+			case "vector":
+				counts.vectors++
+			default:
+				counts.memories++
+			}
+			if _, writeErr := out.Write(line); writeErr != nil {
+				return counts, fmt.Errorf("write export: %w", writeErr)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return counts, nil
+		}
+		if err != nil {
+			return counts, fmt.Errorf("read export: %w", err)
+		}
+	}
+}
+
+func newMemoryImportCommand(opts *memoryClientOptions) *cobra.Command {
+	var input string
+
+	cmd := &cobra.Command{
+		Use:   "import [-f <file>]",
+		Short: "Import an NDJSON memory export",
+		Long: `Import an NDJSON memory export from stdin or a file. Existing entries with the same
+keys are replaced, and expired entries are skipped.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			body := cmd.InOrStdin()
+			if input != "" {
+				file, err := os.Open(input)
+				if err != nil {
+					return fmt.Errorf("open input file: %w", err)
+				}
+				defer file.Close()
+				body = file
+			}
+
+			resp, err := opts.send(http.MethodPost, "/api/v1/memory/import", body, "application/x-ndjson")
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			var result map[string]any
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return fmt.Errorf("decode response: %w", err)
+			}
+			if opts.jsonOutput {
+				return printJSON(cmd.OutOrStdout(), result)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Imported %s memories and %s vectors (%s expired entries skipped)\n",
+				stringField(result, "memories"), stringField(result, "vectors"), stringField(result, "skipped"))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&input, "file", "f", "", "Read the export from a file instead of stdin")
+	return cmd
+}
+
+func newMemoryRestoreCommand(opts *memoryClientOptions) *cobra.Command {
+	selection := &memorySelection{}
+	var (
+		to     string
+		dryRun bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "restore --to <time> [--scope <scope>] [--scope-id <id>] [--prefix <prefix>]",
+		Short: "Rewind key-value memory to an earlier time",
+		Long: `Rewind key-value memory to an earlier time by replaying the memory change history.
+
+The history is kept for 48 hours, so older times cannot be restored. Keys whose earlier
+value is not in the history, such as access-controlled keys, are reported and left as
+they are.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if to == "" {
+				return fmt.Errorf("--to is required")
+			}
+			timestamp, err := time.Parse(time.RFC3339, to)
+			if err != nil {
+				return fmt.Errorf("--to must be an RFC 3339 timestamp: %w", err)
+			}
+
+			payload := map[string]any{
+				"timestamp": timestamp,
+				"dry_run":   dryRun,
+			}
+			if selection.scope != "" {
+				payload["scope"] = selection.scope
+			}
+			if selection.scopeID != "" {
+				payload["scope_id"] = selection.scopeID
+			}
+			if selection.prefix != "" {
+				payload["key_prefix"] = selection.prefix
+			}
+
+			var result struct {
+				Set     int `json:"set"`
+				Deleted int `json:"deleted"`
+				Changes []struct {
+					Scope   string `json:"scope"`
+					ScopeID string `json:"scope_id"`
+					Key     string `json:"key"`
+					Action  string `json:"action"`
+				} `json:"changes"`
+				Unrecoverable []struct {
+					Scope   string `json:"scope"`
+					ScopeID string `json:"scope_id"`
+					Key     string `json:"key"`
+				} `json:"unrecoverable"`
+			}
+			encoded, err := json.Marshal(payload)
+			if err != nil {
+				return fmt.Errorf("encode payload: %w", err)
+			}
+			resp, err := opts.send(http.MethodPost, "/api/v1/memory/restore", bytes.NewReader(encoded), "application/json")
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return fmt.Errorf("decode response: %w", err)
+			}
+			if opts.jsonOutput {
+				return printJSON(cmd.OutOrStdout(), result)
+			}
+
+			out := cmd.OutOrStdout()
+			for _, change := range result.Changes {
+				fmt.Fprintf(out, "%-6s %s/%s/%s\n", change.Action, change.Scope, change.ScopeID, change.Key)
+			}
+			for _, key := range result.Unrecoverable {
+				fmt.Fprintf(out, "skip   %s/%s/%s (earlier value not in history)\n", key.Scope, key.ScopeID, key.Key)
+			}
+			if dryRun {
+				fmt.Fprintf(out, "Would set %d keys and delete %d to restore %s\n", result.Set, result.Deleted, timestamp.Format(time.RFC3339))
+				return nil
+			}
+			fmt.Fprintf(out, "Set %d keys and deleted %d to restore %s\n", result.Set, result.Deleted, timestamp.Format(time.RFC3339))
+			return nil
+		},
+	}
+
+	selection.register(cmd)
+	cmd.Flags().StringVar(&to, "to", "", "RFC 3339 time to restore memory to")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the changes without making them")
+	return cmd
+}
+
+func newMemoryRotateKeyCommand(opts *memoryClientOptions) *cobra.Command {
+	var reencrypt bool
+
+	cmd := &cobra.Command{
+		Use:   "rotate-key [--reencrypt]",
+		Short: "Rotate the key memory is encrypted with",
+		Long: `Create a new key for encrypting memory values at rest. Values written from now on use
+the new key; values encrypted with older keys stay readable and are moved to the new key
+with --reencrypt. Requires memory encryption to be enabled on the control plane.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			encoded, err := json.Marshal(map[string]any{"reencrypt": reencrypt})
+			if err != nil {
+				return fmt.Errorf("encode payload: %w", err)
+			}
+			resp, err := opts.send(http.MethodPost, "/api/v1/memory/encryption/rotate", bytes.NewReader(encoded), "application/json")
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			var result map[string]any
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return fmt.Errorf("decode response: %w", err)
+			}
+			if opts.jsonOutput {
+				return printJSON(cmd.OutOrStdout(), result)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Rotated memory encryption to key %s\n", stringField(result, "key_id"))
+			if reencrypt {
+				fmt.Fprintf(cmd.OutOrStdout(), "Re-encrypted %s values\n", stringField(result, "reencrypted"))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&reencrypt, "reencrypt", false, "Also re-encrypt stored values with the new key")
+	return cmd
+}
+
+// send sends a request to the control plane and returns the response of a successful
+// one; the caller closes its body.
+func (o *memoryClientOptions) send(method, path string, body io.Reader, contentType string) (*http.Response, error) {
+	server := strings.TrimSpace(o.serverURL)
+	if server == "" {
+		server = "http://localhost:8080"
+	}
+	server = strings.TrimSuffix(server, "/")
+
+	req, err := http.NewRequest(method, server+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if o.token != "" {
+		req.Header.Set("Authorization", "Bearer "+o.token)
+	}
+
+	client := &http.Client{Timeout: o.timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	var apiErr struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+		if apiErr.Message != "" {
+			return nil, fmt.Errorf("request failed (%d): %s: %s", resp.StatusCode, apiErr.Error, apiErr.Message)
+		}
+		return nil, fmt.Errorf("request failed (%d): %s", resp.StatusCode, apiErr.Error)
+	}
+	return nil, fmt.Errorf("request failed (%d): %s", resp.StatusCode, strings.TrimSpace(string(data)))
+}
@@ -105,6 +105,8 @@ func NewRootCommand(runServerFunc func(cmd *cobra.Command, args []string), versi
 	RootCmd.AddCommand(NewMCPCommand())
 	RootCmd.AddCommand(NewVCCommand())
 	RootCmd.AddCommand(NewNodesCommand())
+	RootCmd.AddCommand(NewScheduleCommand())
+	RootCmd.AddCommand(NewMemoryCommand())
 
 	// Add version command
 	RootCmd.AddCommand(NewVersionCommand(versionInfo))
@@ -0,0 +1,399 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// NewScheduleCommand groups cron schedule management subcommands.
+func NewScheduleCommand() *cobra.Command {
+	opts := &scheduleClientOptions{
+		serverURL: os.Getenv("AGENTFIELD_SERVER"),
+		token:     os.Getenv("AGENTFIELD_TOKEN"),
+		timeout:   15 * time.Second,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "schedule",
+		Aliases: []string{"schedules"},
+		Short:   "Manage cron schedules that trigger reasoner executions",
+	}
+
+	cmd.PersistentFlags().StringVar(&opts.serverURL, "server", opts.serverURL, "Control plane URL (default: http://localhost:8080 or $AGENTFIELD_SERVER)")
+	cmd.PersistentFlags().StringVar(&opts.token, "token", opts.token, "Bearer token for the control plane (default: $AGENTFIELD_TOKEN)")
+	cmd.PersistentFlags().DurationVar(&opts.timeout, "timeout", opts.timeout, "HTTP timeout for control plane requests")
+	cmd.PersistentFlags().BoolVar(&opts.jsonOutput, "json", false, "Print raw JSON response")
+
+	cmd.AddCommand(newScheduleCreateCommand(opts))
+	cmd.AddCommand(newScheduleListCommand(opts))
+	cmd.AddCommand(newScheduleGetCommand(opts))
+	cmd.AddCommand(newScheduleUpdateCommand(opts))
+	cmd.AddCommand(newScheduleToggleCommand(opts, "enable", true))
+	cmd.AddCommand(newScheduleToggleCommand(opts, "disable", false))
+	cmd.AddCommand(newScheduleDeleteCommand(opts))
+	cmd.AddCommand(newScheduleRunsCommand(opts))
+	return cmd
+}
+
+type scheduleClientOptions struct {
+	serverURL  string
+	token      string
+	timeout    time.Duration
+	jsonOutput bool
+}
+
+// scheduleFields holds the flags shared by create and update.
+type scheduleFields struct {
+	name          string
+	cron          string
+	timezone      string
+	target        string
+	input         string
+	inputFile     string
+	webhookURL    string
+	webhookSecret string
+}
+
+func (f *scheduleFields) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.name, "name", "", "Schedule name")
+	cmd.Flags().StringVar(&f.cron, "cron", "", "Cron expression, e.g. \"0 2 * * *\" or @hourly")
+	cmd.Flags().StringVar(&f.timezone, "timezone", "", "IANA time zone the cron expression is evaluated in (default UTC)")
+	cmd.Flags().StringVar(&f.target, "target", "", "Target to execute as node_id.reasoner_name")
+	cmd.Flags().StringVar(&f.input, "input", "", "Input template as a JSON object; strings may use {{.ScheduledAt}}, {{.ScheduleID}}, {{.ScheduleName}}")
+	cmd.Flags().StringVar(&f.inputFile, "input-file", "", "Read the input template from a JSON file")
+	cmd.Flags().StringVar(&f.webhookURL, "webhook-url", "", "Webhook notified when each run completes")
+	cmd.Flags().StringVar(&f.webhookSecret, "webhook-secret", "", "Secret used to sign webhook deliveries")
+}
+
+// payload returns the request fields for every flag the user set.
+func (f *scheduleFields) payload(cmd *cobra.Command) (map[string]any, error) {
+	payload := make(map[string]any)
+	flags := cmd.Flags()
+	if flags.Changed("name") {
+		payload["name"] = f.name
+	}
+	if flags.Changed("cron") {
+		payload["cron_expression"] = f.cron
+	}
+	if flags.Changed("timezone") {
+		payload["timezone"] = f.timezone
+	}
+	if flags.Changed("target") {
+		payload["target"] = f.target
+	}
+
+	if flags.Changed("input") && flags.Changed("input-file") {
+		return nil, fmt.Errorf("--input and --input-file are mutually exclusive")
+	}
+	raw := f.input
+	if flags.Changed("input-file") {
+		data, err := os.ReadFile(f.inputFile)
+		if err != nil {
+			return nil, fmt.Errorf("read input file: %w", err)
+		}
+		raw = string(data)
+	}
+	if flags.Changed("input") || flags.Changed("input-file") {
+		var input map[string]any
+		if err := json.Unmarshal([]byte(raw), &input); err != nil {
+			return nil, fmt.Errorf("input must be a JSON object: %w", err)
+		}
+		payload["input_template"] = input
+	}
+
+	if flags.Changed("webhook-secret") && !flags.Changed("webhook-url") {
+		return nil, fmt.Errorf("--webhook-secret requires --webhook-url")
+	}
+	if flags.Changed("webhook-url") {
+		webhook := map[string]any{"url": f.webhookURL}
+		if f.webhookSecret != "" {
+			webhook["secret"] = f.webhookSecret
+		}
+		payload["webhook"] = webhook
+	}
+	return payload, nil
+}
+
+func newScheduleCreateCommand(opts *scheduleClientOptions) *cobra.Command {
+	fields := &scheduleFields{}
+	var disabled bool
+
+	cmd := &cobra.Command{
+		Use:   "create --name <name> --cron <expr> --target <node.reasoner> --input <json>",
+		Short: "Create a schedule",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			for _, flag := range []string{"name", "cron", "target"} {
+				if !cmd.Flags().Changed(flag) {
+					return fmt.Errorf("--%s is required", flag)
+				}
+			}
+			if !cmd.Flags().Changed("input") && !cmd.Flags().Changed("input-file") {
+				return fmt.Errorf("--input or --input-file is required")
+			}
+
+			payload, err := fields.payload(cmd)
+			if err != nil {
+				return err
+			}
+			payload["enabled"] = !disabled
+
+			var schedule map[string]any
+			if err := opts.do(http.MethodPost, "/api/v1/schedules", payload, &schedule); err != nil {
+				return err
+			}
+			if opts.jsonOutput {
+				return printJSON(cmd.OutOrStdout(), schedule)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Created schedule %s (next run: %s)\n", schedule["schedule_id"], stringField(schedule, "next_run_at"))
+			return nil
+		},
+	}
+
+	fields.register(cmd)
+	cmd.Flags().BoolVar(&disabled, "disabled", false, "Create the schedule without enabling it")
+	return cmd
+}
+
+func newScheduleListCommand(opts *scheduleClientOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List schedules",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var response struct {
+				Schedules []map[string]any `json:"schedules"`
+			}
+			if err := opts.do(http.MethodGet, "/api/v1/schedules", nil, &response); err != nil {
+				return err
+			}
+			if opts.jsonOutput {
+				return printJSON(cmd.OutOrStdout(), response)
+			}
+			if len(response.Schedules) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No schedules configured")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tNAME\tCRON\tTARGET\tENABLED\tNEXT RUN\tLAST RUN")
+			for _, schedule := range response.Schedules {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%v\t%s\t%s\n",
+					stringField(schedule, "schedule_id"),
+					stringField(schedule, "name"),
+					stringField(schedule, "cron_expression"),
+					stringField(schedule, "target"),
+					schedule["enabled"],
+					stringField(schedule, "next_run_at"),
+					stringField(schedule, "last_run_at"),
+				)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newScheduleGetCommand(opts *scheduleClientOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <schedule-id>",
+		Short: "Show a schedule",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var schedule map[string]any
+			if err := opts.do(http.MethodGet, "/api/v1/schedules/"+url.PathEscape(args[0]), nil, &schedule); err != nil {
+				return err
+			}
+			return printJSON(cmd.OutOrStdout(), schedule)
+		},
+	}
+}
+
+func newScheduleUpdateCommand(opts *scheduleClientOptions) *cobra.Command {
+	fields := &scheduleFields{}
+	var removeWebhook bool
+
+	cmd := &cobra.Command{
+		Use:   "update <schedule-id>",
+		Short: "Change a schedule; only the flags given are updated",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			payload, err := fields.payload(cmd)
+			if err != nil {
+				return err
+			}
+			if removeWebhook {
+				payload["remove_webhook"] = true
+			}
+			if len(payload) == 0 {
+				return fmt.Errorf("nothing to update")
+			}
+			return opts.updateSchedule(cmd, args[0], payload, "Updated")
+		},
+	}
+
+	fields.register(cmd)
+	cmd.Flags().BoolVar(&removeWebhook, "remove-webhook", false, "Stop notifying the schedule's webhook")
+	return cmd
+}
+
+func newScheduleToggleCommand(opts *scheduleClientOptions, verb string, enabled bool) *cobra.Command {
+	label := strings.ToUpper(verb[:1]) + verb[1:] + "d"
+	return &cobra.Command{
+		Use:   verb + " <schedule-id>",
+		Short: label + " a schedule",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.updateSchedule(cmd, args[0], map[string]any{"enabled": enabled}, label)
+		},
+	}
+}
+
+func newScheduleDeleteCommand(opts *scheduleClientOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <schedule-id>",
+		Short: "Delete a schedule; executions it already fired are kept",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var response map[string]any
+			if err := opts.do(http.MethodDelete, "/api/v1/schedules/"+url.PathEscape(args[0]), nil, &response); err != nil {
+				return err
+			}
+			if opts.jsonOutput {
+				return printJSON(cmd.OutOrStdout(), response)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted schedule %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+func newScheduleRunsCommand(opts *scheduleClientOptions) *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "runs <schedule-id>",
+		Short: "List executions fired by a schedule, newest first",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := fmt.Sprintf("/api/v1/schedules/%s/runs?limit=%d", url.PathEscape(args[0]), limit)
+			var response struct {
+				Runs []map[string]any `json:"runs"`
+			}
+			if err := opts.do(http.MethodGet, path, nil, &response); err != nil {
+				return err
+			}
+			if opts.jsonOutput {
+				return printJSON(cmd.OutOrStdout(), response)
+			}
+			if len(response.Runs) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No runs yet")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "EXECUTION ID\tSTATUS\tSTARTED\tDURATION MS")
+			for _, run := range response.Runs {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+					stringField(run, "execution_id"),
+					stringField(run, "status"),
+					stringField(run, "started_at"),
+					stringField(run, "duration_ms"),
+				)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum number of runs to list")
+	return cmd
+}
+
+func (o *scheduleClientOptions) updateSchedule(cmd *cobra.Command, scheduleID string, payload map[string]any, verb string) error {
+	var schedule map[string]any
+	if err := o.do(http.MethodPatch, "/api/v1/schedules/"+url.PathEscape(scheduleID), payload, &schedule); err != nil {
+		return err
+	}
+	if o.jsonOutput {
+		return printJSON(cmd.OutOrStdout(), schedule)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s schedule %s (next run: %s)\n", verb, scheduleID, stringField(schedule, "next_run_at"))
+	return nil
+}
+
+// do sends a request to the control plane and decodes the JSON response into out.
+func (o *scheduleClientOptions) do(method, path string, payload any, out any) error {
+	server := strings.TrimSpace(o.serverURL)
+	if server == "" {
+		server = "http://localhost:8080"
+	}
+	server = strings.TrimSuffix(server, "/")
+
+	var body io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("encode payload: %w", err)
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, server+path, body)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if o.token != "" {
+		req.Header.Set("Authorization", "Bearer "+o.token)
+	}
+
+	client := &http.Client{Timeout: o.timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+			return fmt.Errorf("request failed (%d): %s", resp.StatusCode, apiErr.Error)
+		}
+		return fmt.Errorf("request failed (%d): %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+func printJSON(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func stringField(values map[string]any, key string) string {
+	value, ok := values[key]
+	if !ok || value == nil {
+		return "-"
+	}
+	return fmt.Sprint(value)
+}
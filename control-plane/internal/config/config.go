@@ -34,6 +34,8 @@ type AgentFieldConfig struct {
 	Port             int                    `yaml:"port"`
 	ExecutionCleanup ExecutionCleanupConfig `yaml:"execution_cleanup" mapstructure:"execution_cleanup"`
 	ExecutionQueue   ExecutionQueueConfig   `yaml:"execution_queue" mapstructure:"execution_queue"`
+	ExecutionLimits  ExecutionLimitsConfig  `yaml:"execution_limits" mapstructure:"execution_limits"`
+	Approvals        ApprovalsConfig        `yaml:"approvals" mapstructure:"approvals"`
 }
 
 // ExecutionCleanupConfig holds configuration for execution cleanup and garbage collection
@@ -53,6 +55,49 @@ type ExecutionQueueConfig struct {
 	WebhookMaxAttempts     int           `yaml:"webhook_max_attempts" mapstructure:"webhook_max_attempts"`
 	WebhookRetryBackoff    time.Duration `yaml:"webhook_retry_backoff" mapstructure:"webhook_retry_backoff"`
 	WebhookMaxRetryBackoff time.Duration `yaml:"webhook_max_retry_backoff" mapstructure:"webhook_max_retry_backoff"`
+	// InstanceSelection spreads executions across the replicas registered under one
+	// node ID: "round_robin" (default) or "least_in_flight".
+	InstanceSelection string `yaml:"instance_selection" mapstructure:"instance_selection"`
+}
+
+// ExecutionLimitsConfig configures admission control for agent executions. Limits are
+// enforced per control plane replica; a zero value disables the corresponding check.
+type ExecutionLimitsConfig struct {
+	// QueueTimeout is how long a request waits for capacity before it is rejected
+	// with 429 Too Many Requests. Zero rejects immediately.
+	QueueTimeout time.Duration            `yaml:"queue_timeout" mapstructure:"queue_timeout"`
+	Node         ExecutionLimit           `yaml:"node" mapstructure:"node"`
+	Reasoner     ExecutionLimit           `yaml:"reasoner" mapstructure:"reasoner"`
+	Caller       ExecutionLimit           `yaml:"caller" mapstructure:"caller"`
+	Overrides    []ExecutionLimitOverride `yaml:"overrides" mapstructure:"overrides"`
+}
+
+// ExecutionLimit bounds concurrent executions and request rate for one scope.
+type ExecutionLimit struct {
+	MaxInFlight       int     `yaml:"max_in_flight" mapstructure:"max_in_flight"`
+	RequestsPerSecond float64 `yaml:"requests_per_second" mapstructure:"requests_per_second"`
+	// Burst is the number of requests admitted at once before RequestsPerSecond
+	// applies. Defaults to RequestsPerSecond rounded up.
+	Burst int `yaml:"burst" mapstructure:"burst"`
+}
+
+// ExecutionLimitOverride replaces the default limit for a single node, reasoner
+// (node plus reasoner) or caller.
+type ExecutionLimitOverride struct {
+	Node           string `yaml:"node" mapstructure:"node"`
+	Reasoner       string `yaml:"reasoner" mapstructure:"reasoner"`
+	Caller         string `yaml:"caller" mapstructure:"caller"`
+	ExecutionLimit `yaml:",inline" mapstructure:",squash"`
+}
+
+// ApprovalsConfig configures the human approvals executions can wait on.
+type ApprovalsConfig struct {
+	// DefaultTimeout bounds how long an approval waits when the request sets no
+	// timeout of its own. Defaults to 24h.
+	DefaultTimeout time.Duration `yaml:"default_timeout" mapstructure:"default_timeout"`
+	// DefaultDecision is applied to approvals that time out: "approved" or
+	// "rejected" (default). Requests may override it.
+	DefaultDecision string `yaml:"default_decision" mapstructure:"default_decision"`
 }
 
 // FeatureConfig holds configuration for enabling/disabling features.
@@ -109,6 +154,8 @@ type CORSConfig struct {
 // AuthConfig holds API authentication configuration.
 type AuthConfig struct {
 	// APIKey is checked against headers or query params. Empty disables auth.
+	// It acts as an admin key spanning every team; team-scoped keys are issued
+	// through /api/v1/keys and stored in the database.
 	APIKey string `yaml:"api_key" mapstructure:"api_key"`
 	// SkipPaths allows bypassing auth for specific endpoints (e.g., health).
 	SkipPaths []string `yaml:"skip_paths" mapstructure:"skip_paths"`
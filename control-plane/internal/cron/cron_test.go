@@ -0,0 +1,74 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleNext(t *testing.T) {
+	base := time.Date(2024, time.March, 15, 10, 30, 45, 0, time.UTC) // Friday
+
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 3, 15, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 3, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 9-17 * * mon-fri", time.Date(2024, 3, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2024, 3, 16, 2, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2024, 3, 17, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either may match.
+		{"0 0 20 * mon", time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC)},
+		{"5,10 4/6 * * *", time.Date(2024, 3, 15, 16, 5, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expr, func(t *testing.T) {
+			schedule, err := Parse(tc.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, schedule.Next(base))
+		})
+	}
+}
+
+func TestScheduleNextUsesLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+	schedule, err := Parse("0 9 * * *")
+	require.NoError(t, err)
+
+	next := schedule.Next(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC).In(loc))
+	assert.Equal(t, time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func TestScheduleNeverFires(t *testing.T) {
+	schedule, err := Parse("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
+
+func TestParseRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * foo *",
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
@@ -24,6 +24,16 @@ func NewEncryptionService(passphrase string) *EncryptionService {
 	}
 }
 
+// NewEncryptionServiceWithKey creates an encryption service using a raw 256-bit key
+func NewEncryptionServiceWithKey(key []byte) (*EncryptionService, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(key))
+	}
+	return &EncryptionService{
+		key: append([]byte(nil), key...),
+	}, nil
+}
+
 // Encrypt encrypts a plaintext string and returns a base64-encoded ciphertext
 func (es *EncryptionService) Encrypt(plaintext string) (string, error) {
 	if plaintext == "" {
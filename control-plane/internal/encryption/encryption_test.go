@@ -310,3 +310,22 @@ func TestEncryptionService_Unicode(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, plaintext, decrypted)
 }
+
+func TestEncryptionService_WithKey(t *testing.T) {
+	_, err := NewEncryptionServiceWithKey([]byte("too-short"))
+	require.Error(t, err)
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	service, err := NewEncryptionServiceWithKey(key)
+	require.NoError(t, err)
+
+	ciphertext, err := service.Encrypt("sensitive-data")
+	require.NoError(t, err)
+
+	// The same key decrypts, whichever service holds it.
+	other, err := NewEncryptionServiceWithKey(key)
+	require.NoError(t, err)
+	decrypted, err := other.Decrypt(ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, "sensitive-data", decrypted)
+}
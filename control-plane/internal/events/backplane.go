@@ -0,0 +1,54 @@
+package events
+
+import (
+	"encoding/json"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+)
+
+// Backplane relays events between control-plane replicas. Payloads are JSON documents;
+// those published on a topic reach the handlers every other replica subscribed to that
+// topic, and a replica never receives its own payloads back.
+type Backplane interface {
+	Publish(topic string, payload []byte) error
+	Subscribe(topic string, handler func(payload []byte))
+}
+
+// Topics the global buses relay their events on.
+const (
+	ExecutionEventsTopic = "execution_events"
+	NodeEventsTopic      = "node_events"
+	ReasonerEventsTopic  = "reasoner_events"
+)
+
+// AttachBackplane relays the global execution, node and reasoner buses through bp.
+func AttachBackplane(bp Backplane) {
+	GlobalExecutionEventBus.UseBackplane(bp, ExecutionEventsTopic)
+	GlobalNodeEventBus.UseBackplane(bp, NodeEventsTopic)
+	GlobalReasonerEventBus.UseBackplane(bp, ReasonerEventsTopic)
+}
+
+// relayEvent publishes event to the other replicas. A failed relay only costs the other
+// replicas this event, so it is logged rather than returned.
+func relayEvent(bp Backplane, topic string, event interface{}) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logger.Logger.Warn().Err(err).Str("topic", topic).Msg("failed to encode event for backplane")
+		return
+	}
+	if err := bp.Publish(topic, payload); err != nil {
+		logger.Logger.Warn().Err(err).Str("topic", topic).Msg("failed to relay event through backplane")
+	}
+}
+
+// subscribeRelayed registers deliver for the events other replicas publish on topic.
+func subscribeRelayed[T any](bp Backplane, topic string, deliver func(T)) {
+	bp.Subscribe(topic, func(payload []byte) {
+		var event T
+		if err := json.Unmarshal(payload, &event); err != nil {
+			logger.Logger.Warn().Err(err).Str("topic", topic).Msg("dropping undecodable backplane event")
+			return
+		}
+		deliver(event)
+	})
+}
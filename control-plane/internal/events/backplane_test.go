@@ -0,0 +1,157 @@
+package events
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBackplane links the replicas of a test: payloads published by one are handed to
+// the handlers of all the others.
+type memoryBackplane struct {
+	network  *memoryNetwork
+	mu       sync.Mutex
+	handlers map[string][]func(payload []byte)
+}
+
+type memoryNetwork struct {
+	mu       sync.Mutex
+	replicas []*memoryBackplane
+}
+
+func (n *memoryNetwork) join() *memoryBackplane {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	bp := &memoryBackplane{network: n, handlers: make(map[string][]func(payload []byte))}
+	n.replicas = append(n.replicas, bp)
+	return bp
+}
+
+func (bp *memoryBackplane) Publish(topic string, payload []byte) error {
+	bp.network.mu.Lock()
+	replicas := append([]*memoryBackplane(nil), bp.network.replicas...)
+	bp.network.mu.Unlock()
+
+	for _, replica := range replicas {
+		if replica == bp {
+			continue
+		}
+		replica.mu.Lock()
+		handlers := append([]func(payload []byte){}, replica.handlers[topic]...)
+		replica.mu.Unlock()
+		for _, handler := range handlers {
+			handler(payload)
+		}
+	}
+	return nil
+}
+
+func (bp *memoryBackplane) Subscribe(topic string, handler func(payload []byte)) {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	bp.handlers[topic] = append(bp.handlers[topic], handler)
+}
+
+func receiveWithin[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case event := <-ch:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+	var zero T
+	return zero
+}
+
+func requireNoEvent[T any](t *testing.T, ch <-chan T) {
+	t.Helper()
+	select {
+	case event := <-ch:
+		t.Fatalf("unexpected event: %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestExecutionEventBus_RelaysThroughBackplane(t *testing.T) {
+	network := &memoryNetwork{}
+	replicaA, replicaB := NewExecutionEventBus(), NewExecutionEventBus()
+	replicaA.UseBackplane(network.join(), ExecutionEventsTopic)
+	replicaB.UseBackplane(network.join(), ExecutionEventsTopic)
+
+	chA := replicaA.Subscribe("a")
+	chB := replicaB.Subscribe("b")
+
+	replicaA.Publish(ExecutionEvent{
+		Type:        ExecutionCompleted,
+		ExecutionID: "exec-1",
+		Status:      "succeeded",
+		Data:        map[string]interface{}{"result_size": 3},
+	})
+
+	local := receiveWithin(t, chA)
+	require.Equal(t, "exec-1", local.ExecutionID)
+
+	remote := receiveWithin(t, chB)
+	require.Equal(t, ExecutionCompleted, remote.Type)
+	require.Equal(t, "exec-1", remote.ExecutionID)
+	require.Equal(t, map[string]interface{}{"result_size": float64(3)}, remote.Data)
+
+	// Relayed events are only delivered, never relayed back.
+	requireNoEvent(t, chA)
+}
+
+func TestNodeEventBus_DoesNotRelayHeartbeats(t *testing.T) {
+	network := &memoryNetwork{}
+	replicaA, replicaB := NewNodeEventBus(), NewNodeEventBus()
+	replicaA.UseBackplane(network.join(), NodeEventsTopic)
+	replicaB.UseBackplane(network.join(), NodeEventsTopic)
+
+	replicaA.Subscribe("a")
+	chB := replicaB.Subscribe("b")
+
+	replicaA.Publish(NodeEvent{Type: NodeHeartbeat, Timestamp: time.Now()})
+	requireNoEvent(t, chB)
+
+	replicaA.Publish(NodeEvent{Type: NodeRegistered, NodeID: "node-relay", Timestamp: time.Now()})
+	remote := receiveWithin(t, chB)
+	require.Equal(t, NodeRegistered, remote.Type)
+	require.Equal(t, "node-relay", remote.NodeID)
+}
+
+func TestReasonerEventBus_RelaysThroughBackplane(t *testing.T) {
+	network := &memoryNetwork{}
+	replicaA, replicaB := NewReasonerEventBus(), NewReasonerEventBus()
+	replicaA.UseBackplane(network.join(), ReasonerEventsTopic)
+	replicaB.UseBackplane(network.join(), ReasonerEventsTopic)
+
+	chB := replicaB.Subscribe("b")
+
+	replicaA.Publish(ReasonerEvent{Type: Heartbeat, Timestamp: time.Now()})
+	requireNoEvent(t, chB)
+
+	replicaA.Publish(ReasonerEvent{Type: ReasonerOnline, ReasonerID: "summarize", NodeID: "node-1", Timestamp: time.Now()})
+	remote := receiveWithin(t, chB)
+	require.Equal(t, ReasonerOnline, remote.Type)
+	require.Equal(t, "summarize", remote.ReasonerID)
+}
+
+func TestEventBus_RelaysThroughBackplane(t *testing.T) {
+	type update struct {
+		ID    string `json:"id"`
+		Count int    `json:"count"`
+	}
+
+	network := &memoryNetwork{}
+	replicaA, replicaB := NewEventBus[*update](), NewEventBus[*update]()
+	replicaA.UseBackplane(network.join(), "updates")
+	replicaB.UseBackplane(network.join(), "updates")
+
+	chB := replicaB.Subscribe("b")
+	replicaA.Publish(&update{ID: "u-1", Count: 2})
+
+	remote := receiveWithin(t, chB)
+	require.Equal(t, &update{ID: "u-1", Count: 2}, remote)
+}
@@ -7,6 +7,8 @@ type EventBus[T any] struct {
 	subscribers map[string]chan T
 	mutex       sync.RWMutex
 	bufferSize  int
+	backplane   Backplane
+	topic       string
 }
 
 // NewEventBus constructs an EventBus with a default buffer for subscriber channels.
@@ -38,8 +40,29 @@ func (bus *EventBus[T]) Unsubscribe(subscriberID string) {
 	}
 }
 
+// UseBackplane relays published events to other replicas through bp on topic and
+// delivers the events they publish there to local subscribers.
+func (bus *EventBus[T]) UseBackplane(bp Backplane, topic string) {
+	subscribeRelayed(bp, topic, bus.deliver)
+
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	bus.backplane, bus.topic = bp, topic
+}
+
 // Publish delivers an event to all subscribers without blocking.
 func (bus *EventBus[T]) Publish(event T) {
+	bus.deliver(event)
+
+	bus.mutex.RLock()
+	bp, topic := bus.backplane, bus.topic
+	bus.mutex.RUnlock()
+	if bp != nil {
+		relayEvent(bp, topic, event)
+	}
+}
+
+func (bus *EventBus[T]) deliver(event T) {
 	bus.mutex.RLock()
 	defer bus.mutex.RUnlock()
 
@@ -18,6 +18,10 @@ const (
 	ExecutionUpdated   ExecutionEventType = "execution_updated"
 	ExecutionCompleted ExecutionEventType = "execution_completed"
 	ExecutionFailed    ExecutionEventType = "execution_failed"
+	ExecutionCancelled ExecutionEventType = "execution_cancelled"
+
+	ExecutionWaitingForApproval ExecutionEventType = "execution_waiting_for_approval"
+	ExecutionApprovalResolved   ExecutionEventType = "execution_approval_resolved"
 )
 
 // ExecutionEvent represents an execution state change event
@@ -35,6 +39,8 @@ type ExecutionEvent struct {
 type ExecutionEventBus struct {
 	subscribers map[string]chan ExecutionEvent
 	mutex       sync.RWMutex
+	backplane   Backplane
+	topic       string
 }
 
 // NewExecutionEventBus creates a new execution event bus
@@ -68,8 +74,30 @@ func (bus *ExecutionEventBus) Unsubscribe(subscriberID string) {
 	}
 }
 
-// Publish broadcasts an event to all subscribers
+// UseBackplane relays published events to other replicas through bp on topic and
+// delivers the events they publish there to local subscribers.
+func (bus *ExecutionEventBus) UseBackplane(bp Backplane, topic string) {
+	subscribeRelayed(bp, topic, bus.deliver)
+
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	bus.backplane, bus.topic = bp, topic
+}
+
+// Publish broadcasts an event to all subscribers, including those on other replicas
+// when the bus uses a backplane
 func (bus *ExecutionEventBus) Publish(event ExecutionEvent) {
+	bus.deliver(event)
+
+	bus.mutex.RLock()
+	bp, topic := bus.backplane, bus.topic
+	bus.mutex.RUnlock()
+	if bp != nil {
+		relayEvent(bp, topic, event)
+	}
+}
+
+func (bus *ExecutionEventBus) deliver(event ExecutionEvent) {
 	bus.mutex.RLock()
 	defer bus.mutex.RUnlock()
 
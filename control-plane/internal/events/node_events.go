@@ -52,6 +52,8 @@ type NodeEvent struct {
 type NodeEventBus struct {
 	subscribers map[string]chan NodeEvent
 	mutex       sync.RWMutex
+	backplane   Backplane
+	topic       string
 }
 
 // NewNodeEventBus creates a new node event bus
@@ -85,17 +87,42 @@ func (bus *NodeEventBus) Unsubscribe(subscriberID string) {
 	}
 }
 
-// Publish broadcasts an event to all subscribers with improved error handling
+// UseBackplane relays published events to other replicas through bp on topic and
+// delivers the events they publish there to local subscribers.
+func (bus *NodeEventBus) UseBackplane(bp Backplane, topic string) {
+	subscribeRelayed(bp, topic, bus.deliver)
+
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	bus.backplane, bus.topic = bp, topic
+}
+
+// Publish broadcasts an event to all subscribers with improved error handling. Events
+// that pass the filter are relayed to other replicas, except heartbeats which every
+// replica sends itself.
 func (bus *NodeEventBus) Publish(event NodeEvent) {
 	bus.mutex.RLock()
-	defer bus.mutex.RUnlock()
+	filtered := bus.shouldFilterEvent(event)
+	bp, topic := bus.backplane, bus.topic
+	bus.mutex.RUnlock()
 
 	// Add event filtering to prevent spam
-	if bus.shouldFilterEvent(event) {
+	if filtered {
 		logger.Logger.Debug().Msgf("[NodeEventBus] Filtering duplicate event: %s for node %s", event.Type, event.NodeID)
 		return
 	}
 
+	bus.deliver(event)
+	if bp != nil && event.Type != NodeHeartbeat {
+		relayEvent(bp, topic, event)
+	}
+}
+
+// deliver hands an event that already passed the filter to the local subscribers.
+func (bus *NodeEventBus) deliver(event NodeEvent) {
+	bus.mutex.RLock()
+	defer bus.mutex.RUnlock()
+
 	successCount := 0
 	for subscriberID, ch := range bus.subscribers {
 		select {
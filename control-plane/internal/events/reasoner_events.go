@@ -32,6 +32,8 @@ type ReasonerEvent struct {
 type ReasonerEventBus struct {
 	subscribers map[string]chan ReasonerEvent
 	mutex       sync.RWMutex
+	backplane   Backplane
+	topic       string
 }
 
 // NewReasonerEventBus creates a new reasoner event bus
@@ -63,8 +65,30 @@ func (bus *ReasonerEventBus) Unsubscribe(subscriberID string) {
 	}
 }
 
-// Publish broadcasts an event to all subscribers
+// UseBackplane relays published events to other replicas through bp on topic and
+// delivers the events they publish there to local subscribers.
+func (bus *ReasonerEventBus) UseBackplane(bp Backplane, topic string) {
+	subscribeRelayed(bp, topic, bus.deliver)
+
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	bus.backplane, bus.topic = bp, topic
+}
+
+// Publish broadcasts an event to all subscribers. Heartbeats stay on this replica since
+// every replica sends its own.
 func (bus *ReasonerEventBus) Publish(event ReasonerEvent) {
+	bus.deliver(event)
+
+	bus.mutex.RLock()
+	bp, topic := bus.backplane, bus.topic
+	bus.mutex.RUnlock()
+	if bp != nil && event.Type != Heartbeat {
+		relayEvent(bp, topic, event)
+	}
+}
+
+func (bus *ReasonerEventBus) deliver(event ReasonerEvent) {
 	bus.mutex.RLock()
 	defer bus.mutex.RUnlock()
 
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+	"github.com/Agent-Field/agentfield/control-plane/internal/services"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+)
+
+// Instance selection strategies for agent nodes with several registered replicas.
+const (
+	InstanceSelectionRoundRobin    = "round_robin"
+	InstanceSelectionLeastInFlight = "least_in_flight"
+)
+
+var (
+	instanceBalancerMu    sync.RWMutex
+	agentInstanceBalancer = newInstanceBalancer(nil, InstanceSelectionRoundRobin)
+)
+
+// ConfigureInstanceSelection sets how executions are spread across the replicas of an
+// agent node. Replicas without a live presence lease are skipped.
+func ConfigureInstanceSelection(presence *services.PresenceManager, strategy string) error {
+	switch strategy {
+	case "":
+		strategy = InstanceSelectionRoundRobin
+	case InstanceSelectionRoundRobin, InstanceSelectionLeastInFlight:
+	default:
+		return fmt.Errorf("unknown instance selection strategy %q", strategy)
+	}
+	balancer := newInstanceBalancer(presence, strategy)
+	instanceBalancerMu.Lock()
+	agentInstanceBalancer = balancer
+	instanceBalancerMu.Unlock()
+	return nil
+}
+
+func currentInstanceBalancer() *instanceBalancer {
+	instanceBalancerMu.RLock()
+	defer instanceBalancerMu.RUnlock()
+	return agentInstanceBalancer
+}
+
+// agentEndpoint is one base URL an execution can be sent to. instanceID is empty for
+// nodes without registered replicas.
+type agentEndpoint struct {
+	instanceID string
+	baseURL    string
+}
+
+// forAgent returns a copy of agent that targets this endpoint.
+func (e agentEndpoint) forAgent(agent *types.AgentNode) *types.AgentNode {
+	copied := *agent
+	copied.BaseURL = e.baseURL
+	return &copied
+}
+
+type instanceBalancer struct {
+	presence *services.PresenceManager
+	strategy string
+
+	mu       sync.Mutex
+	cursors  map[string]uint64 // round robin position per node
+	inFlight map[string]int    // calls in progress per node/instance
+	held     asyncHolds        // asynchronous calls in progress per execution
+}
+
+func newInstanceBalancer(presence *services.PresenceManager, strategy string) *instanceBalancer {
+	return &instanceBalancer{
+		presence: presence,
+		strategy: strategy,
+		cursors:  make(map[string]uint64),
+		inFlight: make(map[string]int),
+	}
+}
+
+// endpoints returns the live replicas of agent in the order they should be tried.
+// Nodes without live replicas fall back to the node's own base URL.
+func (b *instanceBalancer) endpoints(ctx context.Context, store ExecutionStore, agent *types.AgentNode) []agentEndpoint {
+	fallback := []agentEndpoint{{baseURL: agent.BaseURL}}
+	if agent.DeploymentType == "serverless" || (agent.InvocationURL != nil && *agent.InvocationURL != "") {
+		return fallback
+	}
+
+	instances, err := store.ListAgentInstances(ctx, agent.ID)
+	if err != nil {
+		logger.Logger.Warn().Err(err).Str("agent", agent.ID).Msg("failed to list agent instances; using node base URL")
+		return fallback
+	}
+
+	live := make([]agentEndpoint, 0, len(instances))
+	for _, instance := range instances {
+		if instance.BaseURL == "" {
+			continue
+		}
+		if b.presence != nil && !b.presence.InstanceAlive(agent.ID, instance.InstanceID, instance.LastHeartbeat) {
+			continue
+		}
+		live = append(live, agentEndpoint{instanceID: instance.InstanceID, baseURL: instance.BaseURL})
+	}
+	if len(live) == 0 {
+		return fallback
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Rotate so that ties are spread across replicas under either strategy.
+	start := int(b.cursors[agent.ID] % uint64(len(live)))
+	b.cursors[agent.ID]++
+	ordered := make([]agentEndpoint, 0, len(live))
+	ordered = append(ordered, live[start:]...)
+	ordered = append(ordered, live[:start]...)
+
+	if b.strategy == InstanceSelectionLeastInFlight {
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return b.inFlight[instanceKey(agent.ID, ordered[i].instanceID)] < b.inFlight[instanceKey(agent.ID, ordered[j].instanceID)]
+		})
+	}
+	return ordered
+}
+
+// acquire counts a call to an endpoint as in flight until the returned func runs.
+func (b *instanceBalancer) acquire(nodeID string, endpoint agentEndpoint) func() {
+	key := instanceKey(nodeID, endpoint.instanceID)
+	b.mu.Lock()
+	b.inFlight[key]++
+	b.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			if b.inFlight[key]--; b.inFlight[key] <= 0 {
+				delete(b.inFlight, key)
+			}
+			b.mu.Unlock()
+		})
+	}
+}
+
+// holdUntilSettled keeps an acquired call in flight after the agent accepted the
+// execution asynchronously, until settle is called for it or asyncCallHoldLimit passes.
+// Only least_in_flight selection reads the counts, so other strategies release at once.
+func (b *instanceBalancer) holdUntilSettled(executionID string, release func()) {
+	if b.strategy != InstanceSelectionLeastInFlight {
+		release()
+		return
+	}
+	b.held.hold(executionID, release)
+}
+
+// settle releases the asynchronous call held for an execution, if any.
+func (b *instanceBalancer) settle(executionID string) {
+	b.held.settle(executionID)
+}
+
+func instanceKey(nodeID, instanceID string) string {
+	return nodeID + "/" + instanceID
+}
+
+// isConnectError reports whether err means the agent could not be reached at all, so
+// the request is safe to send to another replica.
+func isConnectError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/services"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func newCountingAgentServer(t *testing.T, calls *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func configureTestInstanceSelection(t *testing.T, strategy string) *services.PresenceManager {
+	t.Helper()
+	presence := services.NewPresenceManager(nil, services.PresenceManagerConfig{HeartbeatTTL: time.Minute})
+	require.NoError(t, ConfigureInstanceSelection(presence, strategy))
+	t.Cleanup(func() { _ = ConfigureInstanceSelection(nil, "") })
+	return presence
+}
+
+func TestExecuteHandler_RoundRobinSkipsExpiredInstances(t *testing.T) {
+	var callsA, callsB, callsStale int32
+	serverA := newCountingAgentServer(t, &callsA)
+	serverB := newCountingAgentServer(t, &callsB)
+	stale := newCountingAgentServer(t, &callsStale)
+
+	presence := configureTestInstanceSelection(t, InstanceSelectionRoundRobin)
+	now := time.Now()
+	presence.TouchInstance("node-1", "a", now)
+	presence.TouchInstance("node-1", "b", now)
+	presence.TouchInstance("node-1", "stale", now.Add(-time.Hour))
+
+	agent := &types.AgentNode{ID: "node-1", BaseURL: serverA.URL, Reasoners: []types.ReasonerDefinition{{ID: "reasoner-a"}}}
+	router, store := newRetryTestRouter(t, agent)
+	store.instances = []*types.AgentInstance{
+		{NodeID: "node-1", InstanceID: "a", BaseURL: serverA.URL},
+		{NodeID: "node-1", InstanceID: "b", BaseURL: serverB.URL},
+		{NodeID: "node-1", InstanceID: "stale", BaseURL: stale.URL, LastHeartbeat: now.Add(-time.Hour)},
+	}
+
+	for i := 0; i < 4; i++ {
+		resp := postExecute(t, router, `{"input":{"n":1}}`)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	}
+	require.Equal(t, int32(2), atomic.LoadInt32(&callsA))
+	require.Equal(t, int32(2), atomic.LoadInt32(&callsB))
+	require.Equal(t, int32(0), atomic.LoadInt32(&callsStale))
+}
+
+func TestExecuteHandler_FailsOverWhenInstanceRefusesConnection(t *testing.T) {
+	var calls int32
+	live := newCountingAgentServer(t, &calls)
+	down := httptest.NewServer(http.NotFoundHandler())
+	downURL := down.URL
+	down.Close()
+
+	presence := configureTestInstanceSelection(t, InstanceSelectionRoundRobin)
+	presence.TouchInstance("node-1", "down", time.Now())
+	presence.TouchInstance("node-1", "live", time.Now())
+
+	agent := &types.AgentNode{ID: "node-1", BaseURL: downURL, Reasoners: []types.ReasonerDefinition{{ID: "reasoner-a"}}}
+	router, store := newRetryTestRouter(t, agent)
+	store.instances = []*types.AgentInstance{
+		{NodeID: "node-1", InstanceID: "down", BaseURL: downURL},
+		{NodeID: "node-1", InstanceID: "live", BaseURL: live.URL},
+	}
+
+	for i := 0; i < 3; i++ {
+		resp := postExecute(t, router, `{"input":{"n":1}}`)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	}
+	require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestInstanceBalancer_LeastInFlightPrefersIdleInstance(t *testing.T) {
+	presence := configureTestInstanceSelection(t, InstanceSelectionLeastInFlight)
+	presence.TouchInstance("node-1", "a", time.Now())
+	presence.TouchInstance("node-1", "b", time.Now())
+
+	store := newTestExecutionStorage(nil)
+	store.instances = []*types.AgentInstance{
+		{NodeID: "node-1", InstanceID: "a", BaseURL: "http://a"},
+		{NodeID: "node-1", InstanceID: "b", BaseURL: "http://b"},
+	}
+	agent := &types.AgentNode{ID: "node-1", BaseURL: "http://node"}
+	balancer := currentInstanceBalancer()
+
+	release := balancer.acquire("node-1", agentEndpoint{instanceID: "a"})
+	for i := 0; i < 3; i++ {
+		endpoints := balancer.endpoints(context.Background(), store, agent)
+		require.Len(t, endpoints, 2)
+		require.Equal(t, "b", endpoints[0].instanceID)
+	}
+	release()
+	release()
+
+	busy := balancer.acquire("node-1", agentEndpoint{instanceID: "b"})
+	defer busy()
+	require.Equal(t, "a", balancer.endpoints(context.Background(), store, agent)[0].instanceID)
+
+	// Without any live instance the node's own base URL is used.
+	store.instances = nil
+	endpoints := balancer.endpoints(context.Background(), store, agent)
+	require.Equal(t, []agentEndpoint{{baseURL: "http://node"}}, endpoints)
+
+	require.Error(t, ConfigureInstanceSelection(nil, "random"))
+}
+
+func TestExecuteHandler_LeastInFlightHoldsAsyncCallsUntilSettled(t *testing.T) {
+	accepting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer accepting.Close()
+
+	presence := configureTestInstanceSelection(t, InstanceSelectionLeastInFlight)
+	presence.TouchInstance("node-1", "a", time.Now())
+	presence.TouchInstance("node-1", "b", time.Now())
+
+	agent := &types.AgentNode{ID: "node-1", BaseURL: accepting.URL, Reasoners: []types.ReasonerDefinition{{ID: "reasoner-a"}}}
+	store := newTestExecutionStorage(agent)
+	store.instances = []*types.AgentInstance{
+		{NodeID: "node-1", InstanceID: "a", BaseURL: accepting.URL},
+		{NodeID: "node-1", InstanceID: "b", BaseURL: accepting.URL},
+	}
+	exec := &types.Execution{ExecutionID: "exec-1", RunID: "run-1", AgentNodeID: "node-1", Status: types.ExecutionStatusRunning, StartedAt: time.Now().UTC()}
+	require.NoError(t, store.CreateExecutionRecord(context.Background(), exec))
+
+	controller := newExecutionController(store, services.NewFilePayloadStore(t.TempDir()), nil, 90*time.Second)
+	plan := &preparedExecution{exec: exec, agent: agent, target: &parsedTarget{NodeID: "node-1", TargetName: "reasoner-a"}, requestBody: []byte(`{}`)}
+	_, _, async, err := controller.callAgent(context.Background(), plan)
+	require.NoError(t, err)
+	require.True(t, async)
+
+	balancer := currentInstanceBalancer()
+	busy := balancer.endpoints(context.Background(), store, agent)[1].instanceID
+	require.Equal(t, 1, balancer.inFlight[instanceKey("node-1", busy)], "the accepted execution still counts as in flight")
+
+	router := gin.New()
+	router.PUT("/api/v1/executions/:execution_id/status", UpdateExecutionStatusHandler(store, services.NewFilePayloadStore(t.TempDir()), nil, 90*time.Second))
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/executions/exec-1/status", strings.NewReader(`{"status":"succeeded"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	require.Empty(t, balancer.inFlight)
+	require.Empty(t, balancer.held.held)
+}
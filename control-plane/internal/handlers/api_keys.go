@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+	"github.com/Agent-Field/agentfield/control-plane/internal/utils"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyStore captures the storage operations required by the API key handlers.
+type APIKeyStore interface {
+	CreateAPIKey(ctx context.Context, key *types.APIKey) error
+	GetAPIKey(ctx context.Context, keyID string) (*types.APIKey, error)
+	ListAPIKeys(ctx context.Context, teamID string) ([]*types.APIKey, error)
+	UpdateAPIKey(ctx context.Context, key *types.APIKey) error
+}
+
+// CreateAPIKeyRequest defines a new API key. TeamID defaults to the caller's team and
+// may only name another team when the caller is not confined to one.
+type CreateAPIKeyRequest struct {
+	Name   string `json:"name"`
+	TeamID string `json:"team_id"`
+	Role   string `json:"role"`
+}
+
+// APIKeySecretResponse returns a key together with its secret. The secret is only ever
+// shown in this response.
+type APIKeySecretResponse struct {
+	Key    *types.APIKey `json:"key"`
+	Secret string        `json:"secret"`
+}
+
+// CreateAPIKeyHandler creates a team-scoped API key. Stored keys are only enforced
+// while authentication is enabled, so creating one is refused otherwise: it would
+// confine nobody.
+// POST /api/v1/keys
+func CreateAPIKeyHandler(store APIKeyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CreateAPIKeyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid request body: %v", err)})
+			return
+		}
+
+		ctx := c.Request.Context()
+		if auth.PrincipalFrom(ctx) == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "API keys require authentication; configure api.auth.api_key first"})
+			return
+		}
+		teamID := strings.TrimSpace(req.TeamID)
+		if callerTeam := auth.TeamFrom(ctx); callerTeam != "" {
+			if teamID != "" && teamID != callerTeam {
+				c.JSON(http.StatusForbidden, gin.H{"error": "cannot create keys for another team"})
+				return
+			}
+			teamID = callerTeam
+		}
+		if teamID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "team_id is required"})
+			return
+		}
+		role := strings.TrimSpace(req.Role)
+		if !types.IsValidAPIKeyRole(role) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown role %q", req.Role)})
+			return
+		}
+
+		secret, err := auth.GenerateSecret()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		key := &types.APIKey{
+			KeyID:      utils.GenerateAPIKeyID(),
+			Name:       strings.TrimSpace(req.Name),
+			TeamID:     teamID,
+			Role:       role,
+			Prefix:     auth.SecretDisplayPrefix(secret),
+			SecretHash: auth.HashSecret(secret),
+			CreatedAt:  time.Now().UTC(),
+		}
+		if err := store.CreateAPIKey(ctx, key); err != nil {
+			logger.Logger.Error().Err(err).Str("team_id", teamID).Msg("failed to create API key")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create API key"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, APIKeySecretResponse{Key: key, Secret: secret})
+	}
+}
+
+// ListAPIKeysHandler lists the API keys of the caller's team, or of every team for
+// callers not confined to one. The optional team_id query narrows the latter.
+// GET /api/v1/keys
+func ListAPIKeysHandler(store APIKeyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		teamID := auth.TeamFrom(ctx)
+		if teamID == "" {
+			teamID = strings.TrimSpace(c.Query("team_id"))
+		}
+
+		keys, err := store.ListAPIKeys(ctx, teamID)
+		if err != nil {
+			logger.Logger.Error().Err(err).Msg("failed to list API keys")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list API keys"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"keys": keys, "total": len(keys)})
+	}
+}
+
+// RotateAPIKeyHandler replaces the secret of an API key. The old secret stops working
+// immediately.
+// POST /api/v1/keys/:key_id/rotate
+func RotateAPIKeyHandler(store APIKeyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := loadAPIKey(c, store)
+		if !ok {
+			return
+		}
+		if key.Revoked() {
+			c.JSON(http.StatusConflict, gin.H{"error": "API key is revoked"})
+			return
+		}
+
+		secret, err := auth.GenerateSecret()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		now := time.Now().UTC()
+		key.Prefix = auth.SecretDisplayPrefix(secret)
+		key.SecretHash = auth.HashSecret(secret)
+		key.RotatedAt = &now
+		if err := store.UpdateAPIKey(c.Request.Context(), key); err != nil {
+			logger.Logger.Error().Err(err).Str("key_id", key.KeyID).Msg("failed to rotate API key")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to rotate API key"})
+			return
+		}
+
+		c.JSON(http.StatusOK, APIKeySecretResponse{Key: key, Secret: secret})
+	}
+}
+
+// RevokeAPIKeyHandler revokes an API key. Revoked keys are kept for auditing.
+// DELETE /api/v1/keys/:key_id
+func RevokeAPIKeyHandler(store APIKeyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := loadAPIKey(c, store)
+		if !ok {
+			return
+		}
+		if !key.Revoked() {
+			now := time.Now().UTC()
+			key.RevokedAt = &now
+			if err := store.UpdateAPIKey(c.Request.Context(), key); err != nil {
+				logger.Logger.Error().Err(err).Str("key_id", key.KeyID).Msg("failed to revoke API key")
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke API key"})
+				return
+			}
+		}
+		c.JSON(http.StatusOK, key)
+	}
+}
+
+// loadAPIKey resolves the :key_id parameter. Keys of other teams are reported as not
+// found. It writes the error response and returns false on failure.
+func loadAPIKey(c *gin.Context, store APIKeyStore) (*types.APIKey, bool) {
+	ctx := c.Request.Context()
+	key, err := store.GetAPIKey(ctx, c.Param("key_id"))
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("key_id", c.Param("key_id")).Msg("failed to load API key")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load API key"})
+		return nil, false
+	}
+	if key == nil || !auth.CanAccessTeam(ctx, key.TeamID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		return nil, false
+	}
+	return key, true
+}
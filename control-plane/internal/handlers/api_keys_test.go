@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAPIKeyStore struct {
+	mu   sync.Mutex
+	keys map[string]*types.APIKey
+}
+
+func newFakeAPIKeyStore(keys ...*types.APIKey) *fakeAPIKeyStore {
+	store := &fakeAPIKeyStore{keys: make(map[string]*types.APIKey)}
+	for _, key := range keys {
+		store.keys[key.KeyID] = key
+	}
+	return store
+}
+
+func (s *fakeAPIKeyStore) CreateAPIKey(ctx context.Context, key *types.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clone := *key
+	s.keys[key.KeyID] = &clone
+	return nil
+}
+
+func (s *fakeAPIKeyStore) GetAPIKey(ctx context.Context, keyID string) (*types.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.keys[keyID]
+	if !ok {
+		return nil, nil
+	}
+	clone := *key
+	return &clone, nil
+}
+
+func (s *fakeAPIKeyStore) ListAPIKeys(ctx context.Context, teamID string) ([]*types.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []*types.APIKey
+	for _, key := range s.keys {
+		if teamID == "" || key.TeamID == teamID {
+			clone := *key
+			keys = append(keys, &clone)
+		}
+	}
+	return keys, nil
+}
+
+func (s *fakeAPIKeyStore) UpdateAPIKey(ctx context.Context, key *types.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.keys[key.KeyID]; !ok {
+		return fmt.Errorf("API key %s not found", key.KeyID)
+	}
+	clone := *key
+	s.keys[key.KeyID] = &clone
+	return nil
+}
+
+func newAPIKeyTestRouter(store APIKeyStore, principal *auth.Principal) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	})
+	router.POST("/api/v1/keys", CreateAPIKeyHandler(store))
+	router.GET("/api/v1/keys", ListAPIKeysHandler(store))
+	router.POST("/api/v1/keys/:key_id/rotate", RotateAPIKeyHandler(store))
+	router.DELETE("/api/v1/keys/:key_id", RevokeAPIKeyHandler(store))
+	return router
+}
+
+func serveAPIKeyRequest(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestAPIKeyHandlers_CreateRotateRevoke(t *testing.T) {
+	store := newFakeAPIKeyStore()
+	router := newAPIKeyTestRouter(store, &auth.Principal{KeyID: "key-admin", TeamID: "team-a", Role: types.APIKeyRoleAdmin})
+
+	resp := serveAPIKeyRequest(router, http.MethodPost, "/api/v1/keys", `{"name":"ci","role":"operator"}`)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	var created APIKeySecretResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+	require.Equal(t, "team-a", created.Key.TeamID, "the team defaults to the caller's")
+	require.True(t, strings.HasPrefix(created.Secret, created.Key.Prefix))
+	require.NotContains(t, resp.Body.String(), "secret_hash")
+
+	stored, _ := store.GetAPIKey(context.Background(), created.Key.KeyID)
+	require.Equal(t, auth.HashSecret(created.Secret), stored.SecretHash)
+
+	resp = serveAPIKeyRequest(router, http.MethodPost, "/api/v1/keys/"+created.Key.KeyID+"/rotate", "")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var rotated APIKeySecretResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &rotated))
+	require.NotEqual(t, created.Secret, rotated.Secret)
+	require.NotNil(t, rotated.Key.RotatedAt)
+
+	stored, _ = store.GetAPIKey(context.Background(), created.Key.KeyID)
+	require.Equal(t, auth.HashSecret(rotated.Secret), stored.SecretHash)
+
+	resp = serveAPIKeyRequest(router, http.MethodDelete, "/api/v1/keys/"+created.Key.KeyID, "")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	stored, _ = store.GetAPIKey(context.Background(), created.Key.KeyID)
+	require.True(t, stored.Revoked())
+
+	resp = serveAPIKeyRequest(router, http.MethodPost, "/api/v1/keys/"+created.Key.KeyID+"/rotate", "")
+	require.Equal(t, http.StatusConflict, resp.Code)
+}
+
+func TestAPIKeyHandlers_ValidateRequests(t *testing.T) {
+	scoped := newAPIKeyTestRouter(newFakeAPIKeyStore(), &auth.Principal{TeamID: "team-a", Role: types.APIKeyRoleAdmin})
+	resp := serveAPIKeyRequest(scoped, http.MethodPost, "/api/v1/keys", `{"team_id":"team-b","role":"agent"}`)
+	require.Equal(t, http.StatusForbidden, resp.Code)
+
+	resp = serveAPIKeyRequest(scoped, http.MethodPost, "/api/v1/keys", `{"role":"superuser"}`)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+
+	root := newAPIKeyTestRouter(newFakeAPIKeyStore(), &auth.Principal{Role: types.APIKeyRoleAdmin})
+	resp = serveAPIKeyRequest(root, http.MethodPost, "/api/v1/keys", `{"role":"agent"}`)
+	require.Equal(t, http.StatusBadRequest, resp.Code, "the shared key must name a team")
+
+	resp = serveAPIKeyRequest(root, http.MethodPost, "/api/v1/keys", `{"team_id":"team-b","role":"agent"}`)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+	store := newFakeAPIKeyStore()
+	unauthenticated := newAPIKeyTestRouter(store, nil)
+	resp = serveAPIKeyRequest(unauthenticated, http.MethodPost, "/api/v1/keys", `{"team_id":"team-b","role":"agent"}`)
+	require.Equal(t, http.StatusConflict, resp.Code, "keys are not enforced while authentication is disabled")
+	require.Empty(t, store.keys)
+}
+
+func TestAPIKeyHandlers_HideOtherTeams(t *testing.T) {
+	store := newFakeAPIKeyStore(
+		&types.APIKey{KeyID: "key-a", TeamID: "team-a", Role: types.APIKeyRoleAgent},
+		&types.APIKey{KeyID: "key-b", TeamID: "team-b", Role: types.APIKeyRoleAgent},
+	)
+	router := newAPIKeyTestRouter(store, &auth.Principal{TeamID: "team-a", Role: types.APIKeyRoleAdmin})
+
+	resp := serveAPIKeyRequest(router, http.MethodGet, "/api/v1/keys?team_id=team-b", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var listed struct {
+		Keys []*types.APIKey `json:"keys"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &listed))
+	require.Len(t, listed.Keys, 1)
+	require.Equal(t, "key-a", listed.Keys[0].KeyID)
+
+	resp = serveAPIKeyRequest(router, http.MethodDelete, "/api/v1/keys/key-b", "")
+	require.Equal(t, http.StatusNotFound, resp.Code)
+	other, _ := store.GetAPIKey(context.Background(), "key-b")
+	require.False(t, other.Revoked())
+}
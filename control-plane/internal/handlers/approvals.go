@@ -0,0 +1,435 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
+	"github.com/Agent-Field/agentfield/control-plane/internal/config"
+	"github.com/Agent-Field/agentfield/control-plane/internal/events"
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+	"github.com/Agent-Field/agentfield/control-plane/internal/utils"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultApprovalTimeout  = 24 * time.Hour
+	approvalSweepInterval   = 5 * time.Second
+	approvalSweepBatchSize  = 50
+	defaultApprovalPageSize = 100
+	approvalTimeoutComment  = "approval timed out"
+)
+
+// ApprovalStore captures the storage operations required by human approvals.
+type ApprovalStore interface {
+	ExecutionStore
+	ListAgents(ctx context.Context, filters types.AgentFilters) ([]*types.AgentNode, error)
+	CreateApproval(ctx context.Context, approval *types.Approval) error
+	GetApproval(ctx context.Context, approvalID string) (*types.Approval, error)
+	ListApprovals(ctx context.Context, filter types.ApprovalFilter) ([]*types.Approval, error)
+	ListExpiredApprovals(ctx context.Context, now time.Time, limit int) ([]*types.Approval, error)
+	ResolveApproval(ctx context.Context, approval *types.Approval) (bool, error)
+}
+
+// RequestApprovalRequest is sent by a reasoner that needs a human decision before it
+// continues.
+type RequestApprovalRequest struct {
+	Title       string          `json:"title" binding:"required"`
+	Description string          `json:"description,omitempty"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	// TimeoutSeconds overrides the configured default timeout.
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+	// DefaultDecision overrides the configured decision applied on timeout.
+	DefaultDecision string `json:"default_decision,omitempty"`
+}
+
+// ApprovalDecisionRequest is the optional body of the approve and reject endpoints.
+type ApprovalDecisionRequest struct {
+	Comment   string `json:"comment,omitempty"`
+	DecidedBy string `json:"decided_by,omitempty"`
+}
+
+// ApprovalService creates and resolves the approvals executions wait on, and applies
+// the default decision to approvals that time out. Every replica sweeps expired
+// approvals; resolving is a conditional update, so only one sweep wins.
+type ApprovalService struct {
+	store           ApprovalStore
+	defaultTimeout  time.Duration
+	defaultDecision string
+	interval        time.Duration
+	stopChan        chan struct{}
+	wg              sync.WaitGroup
+	isRunning       bool
+	mu              sync.Mutex
+}
+
+// NewApprovalService creates an approval service from configuration.
+func NewApprovalService(store ApprovalStore, cfg config.ApprovalsConfig) (*ApprovalService, error) {
+	timeout := cfg.DefaultTimeout
+	if timeout <= 0 {
+		timeout = defaultApprovalTimeout
+	}
+	decision := strings.TrimSpace(cfg.DefaultDecision)
+	if decision == "" {
+		decision = types.ApprovalStatusRejected
+	}
+	if !types.IsValidApprovalDecision(decision) {
+		return nil, fmt.Errorf("invalid default approval decision %q: expected %q or %q", cfg.DefaultDecision, types.ApprovalStatusApproved, types.ApprovalStatusRejected)
+	}
+	return &ApprovalService{
+		store:           store,
+		defaultTimeout:  timeout,
+		defaultDecision: decision,
+		interval:        approvalSweepInterval,
+		stopChan:        make(chan struct{}),
+	}, nil
+}
+
+// Start begins sweeping expired approvals.
+func (s *ApprovalService) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isRunning {
+		return nil
+	}
+
+	s.isRunning = true
+	s.wg.Add(1)
+	go s.loop(ctx)
+	return nil
+}
+
+// Stop stops the expiry sweep.
+func (s *ApprovalService) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isRunning {
+		return nil
+	}
+
+	close(s.stopChan)
+	s.wg.Wait()
+	s.isRunning = false
+	return nil
+}
+
+func (s *ApprovalService) loop(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			s.expire(ctx, time.Now())
+		}
+	}
+}
+
+// expire applies the default decision to every approval whose deadline passed.
+func (s *ApprovalService) expire(ctx context.Context, now time.Time) {
+	expired, err := s.store.ListExpiredApprovals(ctx, now, approvalSweepBatchSize)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("failed to list expired approvals")
+		return
+	}
+	for _, approval := range expired {
+		if _, err := s.resolve(ctx, approval, approval.DefaultDecision, approvalTimeoutComment, "", true); err != nil {
+			logger.Logger.Error().Err(err).Str("approval_id", approval.ApprovalID).Msg("failed to expire approval")
+		}
+	}
+}
+
+// RequestApprovalHandler pauses an execution until a human decides.
+// POST /api/v1/executions/:execution_id/approvals
+func (s *ApprovalService) RequestApprovalHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req RequestApprovalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid request body: %v", err)})
+		return
+	}
+	decision := strings.TrimSpace(req.DefaultDecision)
+	if decision == "" {
+		decision = s.defaultDecision
+	}
+	if !types.IsValidApprovalDecision(decision) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("default_decision must be %q or %q", types.ApprovalStatusApproved, types.ApprovalStatusRejected)})
+		return
+	}
+	if req.TimeoutSeconds < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "timeout_seconds must not be negative"})
+		return
+	}
+	timeout := s.defaultTimeout
+	if req.TimeoutSeconds > 0 {
+		timeout = time.Duration(req.TimeoutSeconds) * time.Second
+	}
+
+	exec, err := s.store.GetExecutionRecord(ctx, c.Param("execution_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to load execution: %v", err)})
+		return
+	}
+	if exec == nil || !executionVisible(ctx, s.store, exec) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "execution not found"})
+		return
+	}
+	if types.IsTerminalExecutionStatus(exec.Status) {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("execution already %s", exec.Status)})
+		return
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(timeout)
+	approval := &types.Approval{
+		ApprovalID:      utils.GenerateApprovalID(),
+		ExecutionID:     exec.ExecutionID,
+		RunID:           exec.RunID,
+		AgentNodeID:     exec.AgentNodeID,
+		ReasonerID:      exec.ReasonerID,
+		Title:           strings.TrimSpace(req.Title),
+		Description:     req.Description,
+		Payload:         req.Payload,
+		Status:          types.ApprovalStatusPending,
+		DefaultDecision: decision,
+		ExpiresAt:       &expiresAt,
+		CreatedAt:       now,
+	}
+	if err := s.store.CreateApproval(ctx, approval); err != nil {
+		logger.Logger.Error().Err(err).Str("execution_id", exec.ExecutionID).Msg("failed to create approval")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create approval"})
+		return
+	}
+
+	updated, err := s.store.UpdateExecutionRecord(ctx, exec.ExecutionID, func(current *types.Execution) (*types.Execution, error) {
+		if current == nil || types.IsTerminalExecutionStatus(current.Status) {
+			return nil, nil
+		}
+		current.Status = types.ExecutionStatusWaitingForApproval
+		return current, nil
+	})
+	if err != nil {
+		logger.Logger.Warn().Err(err).Str("execution_id", exec.ExecutionID).Msg("failed to mark execution waiting for approval")
+	}
+	if updated == nil {
+		updated = exec
+	}
+	s.publish(events.ExecutionWaitingForApproval, updated, approval)
+
+	c.JSON(http.StatusCreated, approval)
+}
+
+// ListApprovalsHandler lists approvals, optionally narrowed by the status and
+// execution_id queries.
+// GET /api/v1/approvals
+func (s *ApprovalService) ListApprovalsHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	filter := types.ApprovalFilter{
+		Status:      strings.TrimSpace(c.Query("status")),
+		ExecutionID: strings.TrimSpace(c.Query("execution_id")),
+		Limit:       defaultApprovalPageSize,
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		filter.Limit = limit
+	}
+	if team := auth.TeamFrom(ctx); team != "" {
+		agents, err := s.store.ListAgents(ctx, types.AgentFilters{TeamID: &team})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to resolve team agents: %v", err)})
+			return
+		}
+		filter.AgentNodeIDs = make([]string, 0, len(agents))
+		for _, agent := range agents {
+			filter.AgentNodeIDs = append(filter.AgentNodeIDs, agent.ID)
+		}
+	}
+
+	approvals, err := s.store.ListApprovals(ctx, filter)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("failed to list approvals")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list approvals"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"approvals": approvals, "total": len(approvals)})
+}
+
+// GetApprovalHandler returns one approval. Agents poll it through the execution route
+// until the approval is decided.
+// GET /api/v1/approvals/:approval_id
+// GET /api/v1/executions/:execution_id/approvals/:approval_id
+func (s *ApprovalService) GetApprovalHandler(c *gin.Context) {
+	approval, ok := s.loadApproval(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, approval)
+}
+
+// ApproveHandler approves a pending approval.
+// POST /api/v1/approvals/:approval_id/approve
+func (s *ApprovalService) ApproveHandler(c *gin.Context) {
+	s.decide(c, types.ApprovalStatusApproved)
+}
+
+// RejectHandler rejects a pending approval.
+// POST /api/v1/approvals/:approval_id/reject
+func (s *ApprovalService) RejectHandler(c *gin.Context) {
+	s.decide(c, types.ApprovalStatusRejected)
+}
+
+func (s *ApprovalService) decide(c *gin.Context, decision string) {
+	var req ApprovalDecisionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid request body: %v", err)})
+			return
+		}
+	}
+	approval, ok := s.loadApproval(c)
+	if !ok {
+		return
+	}
+	if approval.Decided() {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("approval already %s", approval.Status), "approval": approval})
+		return
+	}
+
+	ctx := c.Request.Context()
+	decidedBy := strings.TrimSpace(req.DecidedBy)
+	if principal := auth.PrincipalFrom(ctx); decidedBy == "" && principal != nil {
+		decidedBy = principal.KeyID
+	}
+	resolved, err := s.resolve(ctx, approval, decision, strings.TrimSpace(req.Comment), decidedBy, false)
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("approval_id", approval.ApprovalID).Msg("failed to resolve approval")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve approval"})
+		return
+	}
+	if !resolved {
+		current, err := s.store.GetApproval(ctx, approval.ApprovalID)
+		if err != nil || current == nil {
+			current = approval
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("approval already %s", current.Status), "approval": current})
+		return
+	}
+	c.JSON(http.StatusOK, approval)
+}
+
+// resolve records decision on approval and, once the execution has no other pending
+// approvals, returns it to running. It reports false when the approval was already
+// decided elsewhere.
+func (s *ApprovalService) resolve(ctx context.Context, approval *types.Approval, decision, comment, decidedBy string, timedOut bool) (bool, error) {
+	now := time.Now().UTC()
+	approval.Status = decision
+	approval.TimedOut = timedOut
+	approval.DecidedAt = &now
+	approval.Comment = nil
+	if comment != "" {
+		approval.Comment = &comment
+	}
+	approval.DecidedBy = nil
+	if decidedBy != "" {
+		approval.DecidedBy = &decidedBy
+	}
+
+	resolved, err := s.store.ResolveApproval(ctx, approval)
+	if err != nil || !resolved {
+		return false, err
+	}
+
+	pending, err := s.store.ListApprovals(ctx, types.ApprovalFilter{
+		Status:      types.ApprovalStatusPending,
+		ExecutionID: approval.ExecutionID,
+		Limit:       1,
+	})
+	if err != nil {
+		logger.Logger.Warn().Err(err).Str("execution_id", approval.ExecutionID).Msg("failed to check remaining approvals")
+	}
+	var exec *types.Execution
+	if err == nil && len(pending) == 0 {
+		exec, err = s.store.UpdateExecutionRecord(ctx, approval.ExecutionID, func(current *types.Execution) (*types.Execution, error) {
+			if current == nil || current.Status != types.ExecutionStatusWaitingForApproval {
+				return nil, nil
+			}
+			current.Status = types.ExecutionStatusRunning
+			return current, nil
+		})
+		if err != nil {
+			logger.Logger.Warn().Err(err).Str("execution_id", approval.ExecutionID).Msg("failed to resume execution after approval")
+		}
+	}
+	if exec == nil {
+		if exec, err = s.store.GetExecutionRecord(ctx, approval.ExecutionID); err != nil || exec == nil {
+			exec = &types.Execution{ExecutionID: approval.ExecutionID, RunID: approval.RunID, AgentNodeID: approval.AgentNodeID}
+		}
+	}
+	s.publish(events.ExecutionApprovalResolved, exec, approval)
+
+	logger.Logger.Info().
+		Str("approval_id", approval.ApprovalID).
+		Str("execution_id", approval.ExecutionID).
+		Str("decision", decision).
+		Bool("timed_out", timedOut).
+		Msg("approval resolved")
+	return true, nil
+}
+
+// loadApproval resolves the :approval_id parameter, checking it belongs to the
+// :execution_id parameter when the route has one. Approvals of other teams are reported
+// as not found. It writes the error response and returns false on failure.
+func (s *ApprovalService) loadApproval(c *gin.Context) (*types.Approval, bool) {
+	ctx := c.Request.Context()
+	approval, err := s.store.GetApproval(ctx, c.Param("approval_id"))
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("approval_id", c.Param("approval_id")).Msg("failed to load approval")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load approval"})
+		return nil, false
+	}
+	if approval == nil ||
+		(c.Param("execution_id") != "" && approval.ExecutionID != c.Param("execution_id")) ||
+		!agentNodeVisible(ctx, s.store, approval.AgentNodeID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "approval not found"})
+		return nil, false
+	}
+	return approval, true
+}
+
+func (s *ApprovalService) publish(eventType events.ExecutionEventType, exec *types.Execution, approval *types.Approval) {
+	event := events.ExecutionEvent{
+		Type:        eventType,
+		ExecutionID: exec.ExecutionID,
+		WorkflowID:  exec.RunID,
+		AgentNodeID: exec.AgentNodeID,
+		Status:      exec.Status,
+		Timestamp:   time.Now(),
+		Data: map[string]interface{}{
+			"approval": approval,
+		},
+	}
+	if bus := s.store.GetExecutionEventBus(); bus != nil {
+		bus.Publish(event)
+	}
+	events.GlobalExecutionEventBus.Publish(event)
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/config"
+	"github.com/Agent-Field/agentfield/control-plane/internal/events"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type testApprovalStorage struct {
+	*testExecutionStorage
+	approvalMu sync.Mutex
+	approvals  map[string]*types.Approval
+}
+
+func newTestApprovalStorage(agent *types.AgentNode) *testApprovalStorage {
+	return &testApprovalStorage{
+		testExecutionStorage: newTestExecutionStorage(agent),
+		approvals:            make(map[string]*types.Approval),
+	}
+}
+
+func (s *testApprovalStorage) ListAgents(ctx context.Context, filters types.AgentFilters) ([]*types.AgentNode, error) {
+	if s.agent == nil || (filters.TeamID != nil && *filters.TeamID != s.agent.TeamID) {
+		return nil, nil
+	}
+	return []*types.AgentNode{s.agent}, nil
+}
+
+func (s *testApprovalStorage) CreateApproval(ctx context.Context, approval *types.Approval) error {
+	s.approvalMu.Lock()
+	defer s.approvalMu.Unlock()
+	copy := *approval
+	s.approvals[approval.ApprovalID] = &copy
+	return nil
+}
+
+func (s *testApprovalStorage) GetApproval(ctx context.Context, approvalID string) (*types.Approval, error) {
+	s.approvalMu.Lock()
+	defer s.approvalMu.Unlock()
+	approval, ok := s.approvals[approvalID]
+	if !ok {
+		return nil, nil
+	}
+	copy := *approval
+	return &copy, nil
+}
+
+func (s *testApprovalStorage) ListApprovals(ctx context.Context, filter types.ApprovalFilter) ([]*types.Approval, error) {
+	s.approvalMu.Lock()
+	defer s.approvalMu.Unlock()
+	var results []*types.Approval
+	for _, approval := range s.approvals {
+		if filter.Status != "" && approval.Status != filter.Status {
+			continue
+		}
+		if filter.ExecutionID != "" && approval.ExecutionID != filter.ExecutionID {
+			continue
+		}
+		copy := *approval
+		results = append(results, &copy)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].ApprovalID < results[j].ApprovalID })
+	return results, nil
+}
+
+func (s *testApprovalStorage) ListExpiredApprovals(ctx context.Context, now time.Time, limit int) ([]*types.Approval, error) {
+	pending, _ := s.ListApprovals(ctx, types.ApprovalFilter{Status: types.ApprovalStatusPending})
+	var expired []*types.Approval
+	for _, approval := range pending {
+		if approval.ExpiresAt != nil && !approval.ExpiresAt.After(now) {
+			expired = append(expired, approval)
+		}
+	}
+	return expired, nil
+}
+
+func (s *testApprovalStorage) ResolveApproval(ctx context.Context, approval *types.Approval) (bool, error) {
+	s.approvalMu.Lock()
+	defer s.approvalMu.Unlock()
+	current, ok := s.approvals[approval.ApprovalID]
+	if !ok || current.Status != types.ApprovalStatusPending {
+		return false, nil
+	}
+	copy := *approval
+	s.approvals[approval.ApprovalID] = &copy
+	return true, nil
+}
+
+func newApprovalTestRouter(t *testing.T, cfg config.ApprovalsConfig) (*gin.Engine, *testApprovalStorage, *ApprovalService) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	store := newTestApprovalStorage(&types.AgentNode{ID: "node-1", TeamID: "team-a"})
+	require.NoError(t, store.CreateExecutionRecord(context.Background(), &types.Execution{
+		ExecutionID: "exec-1",
+		RunID:       "run-1",
+		AgentNodeID: "node-1",
+		ReasonerID:  "send_email",
+		Status:      types.ExecutionStatusRunning,
+	}))
+	service, err := NewApprovalService(store, cfg)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.POST("/api/v1/executions/:execution_id/approvals", service.RequestApprovalHandler)
+	router.GET("/api/v1/executions/:execution_id/approvals/:approval_id", service.GetApprovalHandler)
+	router.GET("/api/v1/approvals", service.ListApprovalsHandler)
+	router.POST("/api/v1/approvals/:approval_id/approve", service.ApproveHandler)
+	router.POST("/api/v1/approvals/:approval_id/reject", service.RejectHandler)
+	return router, store, service
+}
+
+func serveApprovalRequest(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func requestTestApproval(t *testing.T, router *gin.Engine, body string) types.Approval {
+	t.Helper()
+	resp := serveApprovalRequest(router, http.MethodPost, "/api/v1/executions/exec-1/approvals", body)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	var approval types.Approval
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &approval))
+	return approval
+}
+
+func TestApprovals_PauseAndResumeExecution(t *testing.T) {
+	router, store, _ := newApprovalTestRouter(t, config.ApprovalsConfig{})
+	eventsCh := store.GetExecutionEventBus().Subscribe("approval-test")
+	defer store.GetExecutionEventBus().Unsubscribe("approval-test")
+
+	approval := requestTestApproval(t, router, `{"title":"Send invoice email","payload":{"to":"billing@example.com"}}`)
+	require.Equal(t, types.ApprovalStatusPending, approval.Status)
+	require.Equal(t, types.ApprovalStatusRejected, approval.DefaultDecision)
+	require.NotNil(t, approval.ExpiresAt)
+	require.WithinDuration(t, time.Now().Add(defaultApprovalTimeout), *approval.ExpiresAt, time.Minute)
+
+	exec, _ := store.GetExecutionRecord(context.Background(), "exec-1")
+	require.Equal(t, types.ExecutionStatusWaitingForApproval, exec.Status)
+	event := <-eventsCh
+	require.Equal(t, events.ExecutionWaitingForApproval, event.Type)
+
+	resp := serveApprovalRequest(router, http.MethodGet, "/api/v1/approvals?status=pending", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Contains(t, resp.Body.String(), approval.ApprovalID)
+
+	resp = serveApprovalRequest(router, http.MethodPost, "/api/v1/approvals/"+approval.ApprovalID+"/approve", `{"comment":"looks right","decided_by":"alice"}`)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	resp = serveApprovalRequest(router, http.MethodGet, "/api/v1/executions/exec-1/approvals/"+approval.ApprovalID, "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var decided types.Approval
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &decided))
+	require.Equal(t, types.ApprovalStatusApproved, decided.Status)
+	require.Equal(t, "looks right", *decided.Comment)
+	require.Equal(t, "alice", *decided.DecidedBy)
+	require.False(t, decided.TimedOut)
+
+	exec, _ = store.GetExecutionRecord(context.Background(), "exec-1")
+	require.Equal(t, types.ExecutionStatusRunning, exec.Status)
+	event = <-eventsCh
+	require.Equal(t, events.ExecutionApprovalResolved, event.Type)
+
+	resp = serveApprovalRequest(router, http.MethodPost, "/api/v1/approvals/"+approval.ApprovalID+"/reject", "")
+	require.Equal(t, http.StatusConflict, resp.Code, "an approval is decided once")
+}
+
+func TestApprovals_TimeoutAppliesDefaultDecision(t *testing.T) {
+	router, store, service := newApprovalTestRouter(t, config.ApprovalsConfig{DefaultDecision: types.ApprovalStatusRejected})
+
+	approval := requestTestApproval(t, router, `{"title":"Spend $40","timeout_seconds":60,"default_decision":"approved"}`)
+	service.expire(context.Background(), time.Now())
+	pending, _ := store.GetApproval(context.Background(), approval.ApprovalID)
+	require.Equal(t, types.ApprovalStatusPending, pending.Status, "the deadline has not passed yet")
+
+	service.expire(context.Background(), time.Now().Add(2*time.Minute))
+	expired, _ := store.GetApproval(context.Background(), approval.ApprovalID)
+	require.Equal(t, types.ApprovalStatusApproved, expired.Status)
+	require.True(t, expired.TimedOut)
+
+	exec, _ := store.GetExecutionRecord(context.Background(), "exec-1")
+	require.Equal(t, types.ExecutionStatusRunning, exec.Status)
+}
+
+func TestApprovals_ValidateRequests(t *testing.T) {
+	_, err := NewApprovalService(newTestApprovalStorage(nil), config.ApprovalsConfig{DefaultDecision: "maybe"})
+	require.Error(t, err)
+
+	router, store, _ := newApprovalTestRouter(t, config.ApprovalsConfig{})
+	resp := serveApprovalRequest(router, http.MethodPost, "/api/v1/executions/exec-1/approvals", `{"description":"no title"}`)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = serveApprovalRequest(router, http.MethodPost, "/api/v1/executions/exec-1/approvals", `{"title":"x","default_decision":"maybe"}`)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = serveApprovalRequest(router, http.MethodPost, "/api/v1/executions/missing/approvals", `{"title":"x"}`)
+	require.Equal(t, http.StatusNotFound, resp.Code)
+
+	_, err = store.UpdateExecutionRecord(context.Background(), "exec-1", func(current *types.Execution) (*types.Execution, error) {
+		current.Status = types.ExecutionStatusSucceeded
+		return current, nil
+	})
+	require.NoError(t, err)
+	resp = serveApprovalRequest(router, http.MethodPost, "/api/v1/executions/exec-1/approvals", `{"title":"x"}`)
+	require.Equal(t, http.StatusConflict, resp.Code)
+}
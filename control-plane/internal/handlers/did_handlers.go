@@ -2,12 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+	"github.com/Agent-Field/agentfield/control-plane/internal/services"
 	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
 )
 
@@ -24,6 +26,7 @@ type VCService interface {
 	GetWorkflowVCChain(workflowID string) (*types.WorkflowVCChainResponse, error)
 	CreateWorkflowVC(workflowID, sessionID string, executionVCIDs []string) (*types.WorkflowVC, error)
 	GenerateExecutionVC(ctx *types.ExecutionContext, inputData, outputData []byte, status string, errorMessage *string, durationMS int) (*types.ExecutionVC, error)
+	StoreSignedExecutionVC(vcDocument json.RawMessage) (*types.ExecutionVC, error)
 	QueryExecutionVCs(filters *types.VCFilters) ([]types.ExecutionVC, error)
 	ListWorkflowVCs() ([]*types.WorkflowVC, error)
 	GetExecutionVCByExecutionID(executionID string) (*types.ExecutionVC, error)
@@ -187,7 +190,9 @@ func (h *DIDHandlers) GetDIDStatus(c *gin.Context) {
 	})
 }
 
-// CreateExecutionVC handles execution VC creation requests from Python SDK.
+// CreateExecutionVC handles execution VC creation requests from the SDKs. The
+// Python and TypeScript SDKs send the execution data for the control plane to
+// sign; the Go SDK signs the credential itself and sends it as vc_document.
 // POST /api/v1/execution/vc
 func (h *DIDHandlers) CreateExecutionVC(c *gin.Context) {
 	logger.Logger.Debug().Msg("🔍 DEBUG: CreateExecutionVC handler called")
@@ -202,11 +207,12 @@ func (h *DIDHandlers) CreateExecutionVC(c *gin.Context) {
 			AgentNodeDID string `json:"agent_node_did"`
 			Timestamp    string `json:"timestamp"`
 		} `json:"execution_context"`
-		InputData    []byte  `json:"input_data"`
-		OutputData   []byte  `json:"output_data"`
-		Status       string  `json:"status"`
-		ErrorMessage *string `json:"error_message"`
-		DurationMS   int     `json:"duration_ms"`
+		InputData    []byte          `json:"input_data"`
+		OutputData   []byte          `json:"output_data"`
+		Status       string          `json:"status"`
+		ErrorMessage *string         `json:"error_message"`
+		DurationMS   int             `json:"duration_ms"`
+		VCDocument   json.RawMessage `json:"vc_document"`
 	}
 
 	logger.Logger.Debug().Msg("🔍 DEBUG: About to parse JSON request body")
@@ -228,42 +234,63 @@ func (h *DIDHandlers) CreateExecutionVC(c *gin.Context) {
 		Int("duration_ms", req.DurationMS).
 		Msg("🔍 DEBUG: Successfully parsed VC creation request")
 
-	// Parse timestamp
-	timestamp, err := time.Parse(time.RFC3339, req.ExecutionContext.Timestamp)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid timestamp format",
-			"details": err.Error(),
-		})
-		return
-	}
-
-	// Create execution context
-	execCtx := &types.ExecutionContext{
-		ExecutionID:  req.ExecutionContext.ExecutionID,
-		WorkflowID:   req.ExecutionContext.WorkflowID,
-		SessionID:    req.ExecutionContext.SessionID,
-		CallerDID:    req.ExecutionContext.CallerDID,
-		TargetDID:    req.ExecutionContext.TargetDID,
-		AgentNodeDID: req.ExecutionContext.AgentNodeDID,
-		Timestamp:    timestamp,
-	}
-
-	// Generate execution VC
-	executionVC, err := h.vcService.GenerateExecutionVC(
-		execCtx,
-		req.InputData,
-		req.OutputData,
-		req.Status,
-		req.ErrorMessage,
-		req.DurationMS,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to generate execution VC",
-			"details": err.Error(),
-		})
-		return
+	var executionVC *types.ExecutionVC
+	if len(req.VCDocument) > 0 && string(req.VCDocument) != "null" {
+		// The agent signed the credential; verify and record it as is.
+		var err error
+		executionVC, err = h.vcService.StoreSignedExecutionVC(req.VCDocument)
+		if errors.Is(err, services.ErrInvalidVCSignature) || errors.Is(err, services.ErrVCExecutionMismatch) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid execution VC",
+				"details": err.Error(),
+			})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to store execution VC",
+				"details": err.Error(),
+			})
+			return
+		}
+	} else {
+		// Parse timestamp
+		timestamp, err := time.Parse(time.RFC3339, req.ExecutionContext.Timestamp)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid timestamp format",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		// Create execution context
+		execCtx := &types.ExecutionContext{
+			ExecutionID:  req.ExecutionContext.ExecutionID,
+			WorkflowID:   req.ExecutionContext.WorkflowID,
+			SessionID:    req.ExecutionContext.SessionID,
+			CallerDID:    req.ExecutionContext.CallerDID,
+			TargetDID:    req.ExecutionContext.TargetDID,
+			AgentNodeDID: req.ExecutionContext.AgentNodeDID,
+			Timestamp:    timestamp,
+		}
+
+		// Generate execution VC
+		executionVC, err = h.vcService.GenerateExecutionVC(
+			execCtx,
+			req.InputData,
+			req.OutputData,
+			req.Status,
+			req.ErrorMessage,
+			req.DurationMS,
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to generate execution VC",
+				"details": err.Error(),
+			})
+			return
+		}
 	}
 
 	// If VC generation is disabled by config, return appropriate response
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Agent-Field/agentfield/control-plane/internal/services"
 	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
 
 	"github.com/gin-gonic/gin"
@@ -53,6 +54,7 @@ type fakeVCService struct {
 	workflowChainFn   func(string) (*types.WorkflowVCChainResponse, error)
 	createWorkflowFn  func(string, string, []string) (*types.WorkflowVC, error)
 	generateExecFn    func(*types.ExecutionContext, []byte, []byte, string, *string, int) (*types.ExecutionVC, error)
+	storeSignedFn     func(json.RawMessage) (*types.ExecutionVC, error)
 	queryExecsFn      func(*types.VCFilters) ([]types.ExecutionVC, error)
 	listWorkflowVCsFn func() ([]*types.WorkflowVC, error)
 }
@@ -99,6 +101,13 @@ func (f *fakeVCService) GenerateExecutionVC(ctx *types.ExecutionContext, inputDa
 	}, nil
 }
 
+func (f *fakeVCService) StoreSignedExecutionVC(doc json.RawMessage) (*types.ExecutionVC, error) {
+	if f.storeSignedFn != nil {
+		return f.storeSignedFn(doc)
+	}
+	return &types.ExecutionVC{VCID: "vc-signed", ExecutionID: "exec-1", VCDocument: doc, Signature: "sig"}, nil
+}
+
 func (f *fakeVCService) QueryExecutionVCs(filters *types.VCFilters) ([]types.ExecutionVC, error) {
 	if f.queryExecsFn != nil {
 		return f.queryExecsFn(filters)
@@ -336,3 +345,59 @@ func TestCreateExecutionVC_ReturnsVCInfo(t *testing.T) {
 	require.Equal(t, "vc-1", payload["vc_id"])
 	require.Equal(t, "exec-1", payload["execution_id"])
 }
+
+func TestCreateExecutionVC_StoresSignedDocument(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var stored json.RawMessage
+	vcService := &fakeVCService{
+		generateExecFn: func(*types.ExecutionContext, []byte, []byte, string, *string, int) (*types.ExecutionVC, error) {
+			t.Fatal("signed documents must not be re-issued")
+			return nil, nil
+		},
+		storeSignedFn: func(doc json.RawMessage) (*types.ExecutionVC, error) {
+			stored = doc
+			return &types.ExecutionVC{VCID: "vc-signed", ExecutionID: "exec-1", VCDocument: doc}, nil
+		},
+	}
+	handler := NewDIDHandlers(&fakeDIDService{}, vcService)
+
+	router := gin.New()
+	router.POST("/api/v1/execution/vc", handler.CreateExecutionVC)
+
+	reqBody := `{"vc_document": {"id": "urn:agentfield:vc:vc-signed"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/execution/vc", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.JSONEq(t, `{"id": "urn:agentfield:vc:vc-signed"}`, string(stored))
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &payload))
+	require.Equal(t, "vc-signed", payload["vc_id"])
+}
+
+func TestCreateExecutionVC_RejectsInvalidSignature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	vcService := &fakeVCService{
+		storeSignedFn: func(json.RawMessage) (*types.ExecutionVC, error) {
+			return nil, services.ErrInvalidVCSignature
+		},
+	}
+	handler := NewDIDHandlers(&fakeDIDService{}, vcService)
+
+	router := gin.New()
+	router.POST("/api/v1/execution/vc", handler.CreateExecutionVC)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/execution/vc", strings.NewReader(`{"vc_document": {}}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+}
@@ -233,7 +233,8 @@ func DiscoveryCapabilitiesHandler(storageProvider AgentLister) gin.HandlerFunc {
 			return
 		}
 
-		response := buildDiscoveryResponse(agents, filters)
+		// The cache is shared by all teams, so callers confined to one are filtered here.
+		response := buildDiscoveryResponse(visibleAgents(c.Request.Context(), agents), filters)
 		switch filters.Format {
 		case "xml":
 			xmlBody, err := formatXMLResponse(response)
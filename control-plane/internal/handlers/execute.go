@@ -10,12 +10,12 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"runtime"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
 	"github.com/Agent-Field/agentfield/control-plane/internal/events"
 	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
 	"github.com/Agent-Field/agentfield/control-plane/internal/services"
@@ -28,6 +28,7 @@ import (
 // ExecutionStore captures the storage operations required by the simplified execution handlers.
 type ExecutionStore interface {
 	GetAgent(ctx context.Context, id string) (*types.AgentNode, error)
+	ListAgentInstances(ctx context.Context, nodeID string) ([]*types.AgentInstance, error)
 	CreateExecutionRecord(ctx context.Context, execution *types.Execution) error
 	GetExecutionRecord(ctx context.Context, executionID string) (*types.Execution, error)
 	UpdateExecutionRecord(ctx context.Context, executionID string, update func(*types.Execution) (*types.Execution, error)) (*types.Execution, error)
@@ -37,6 +38,19 @@ type ExecutionStore interface {
 	UpdateWorkflowExecution(ctx context.Context, executionID string, updateFunc func(*types.WorkflowExecution) (*types.WorkflowExecution, error)) error
 	GetWorkflowExecution(ctx context.Context, executionID string) (*types.WorkflowExecution, error)
 	GetExecutionEventBus() *events.ExecutionEventBus
+	EnqueueExecutionAction(ctx context.Context, action *types.ExecutionAction) error
+	GetExecutionAction(ctx context.Context, actionID string) (*types.ExecutionAction, error)
+	ExtendExecutionActionLease(ctx context.Context, actionID string, leaseTTL time.Duration) error
+	CompleteExecutionAction(ctx context.Context, actionID string) (bool, error)
+	EnqueueExecution(ctx context.Context, item *types.QueuedExecution) error
+	ListQueuedExecutions(ctx context.Context, limitPerTarget int) ([]*types.QueuedExecution, error)
+	DeleteQueuedExecution(ctx context.Context, executionID string) error
+	AcquireLock(ctx context.Context, key string, timeout time.Duration) (*types.DistributedLock, error)
+	ReleaseLock(ctx context.Context, lockID string) error
+	RenewLock(ctx context.Context, lockID string) (*types.DistributedLock, error)
+	GetMemory(ctx context.Context, scope, scopeID, key string) (*types.Memory, error)
+	GetCachedResult(ctx context.Context, teamID, reasoner, inputHash string) ([]byte, error)
+	StoreCachedResult(ctx context.Context, teamID, reasoner, inputHash string, result []byte, ttl time.Duration) error
 }
 
 // ExecuteRequest represents an execution request from an agent client.
@@ -44,6 +58,8 @@ type ExecuteRequest struct {
 	Input   map[string]interface{} `json:"input" binding:"required"`
 	Context map[string]interface{} `json:"context,omitempty"`
 	Webhook *WebhookRequest        `json:"webhook,omitempty"`
+	// Retry overrides the retry policy declared by the target reasoner.
+	Retry *types.RetryPolicy `json:"retry,omitempty"`
 }
 
 // WebhookRequest represents webhook registration parameters supplied by the client.
@@ -119,15 +135,6 @@ type executionController struct {
 	timeout    time.Duration
 }
 
-type asyncExecutionJob struct {
-	controller *executionController
-	plan       preparedExecution
-}
-
-type asyncWorkerPool struct {
-	queue chan asyncExecutionJob
-}
-
 type completionJob struct {
 	controller *executionController
 	plan       *preparedExecution
@@ -138,9 +145,6 @@ type completionJob struct {
 }
 
 var (
-	asyncPoolOnce sync.Once
-	asyncPool     *asyncWorkerPool
-
 	completionOnce  sync.Once
 	completionQueue chan completionJob
 )
@@ -199,8 +203,14 @@ func newExecutionController(store ExecutionStore, payloads services.PayloadStore
 }
 
 func (c *executionController) handleSync(ctx *gin.Context) {
+	release, admitted := admitRequest(ctx, admissionMode{rate: true, concurrency: true})
+	if !admitted {
+		return
+	}
+	defer release()
+
 	reqCtx := ctx.Request.Context()
-	plan, err := c.prepareExecution(reqCtx, ctx)
+	plan, err := c.prepareExecution(reqCtx, ctx, false)
 	if err != nil {
 		writeExecutionError(ctx, err)
 		return
@@ -209,7 +219,22 @@ func (c *executionController) handleSync(ctx *gin.Context) {
 	// Emit execution started event with full reasoner context
 	c.publishExecutionStartedEvent(plan)
 
-	resultBody, elapsed, asyncAccepted, callErr := c.callAgent(reqCtx, plan)
+	response, err := c.runSync(reqCtx, plan)
+	if err != nil {
+		writeExecutionError(ctx, err)
+		return
+	}
+
+	ctx.Header("X-Execution-ID", response.ExecutionID)
+	ctx.Header("X-Run-ID", response.RunID)
+	ctx.JSON(http.StatusOK, response)
+}
+
+// runSync calls the agent for a prepared execution and waits until the outcome is
+// persisted. Failed and cancelled executions that were settled by the agent are
+// reported in the response rather than as an error.
+func (c *executionController) runSync(reqCtx context.Context, plan *preparedExecution) (ExecuteResponse, error) {
+	resultBody, elapsed, asyncAccepted, callErr := c.callAgentWithRetry(reqCtx, plan)
 
 	// If agent returned HTTP 202 (async acknowledgment), wait for callback completion
 	if callErr == nil && asyncAccepted {
@@ -227,8 +252,7 @@ func (c *executionController) handleSync(ctx *gin.Context) {
 				Err(waitErr).
 				Str("execution_id", plan.exec.ExecutionID).
 				Msg("failed to wait for async execution completion")
-			writeExecutionError(ctx, waitErr)
-			return
+			return ExecuteResponse{}, waitErr
 		}
 
 		// Build response from completed execution
@@ -249,8 +273,8 @@ func (c *executionController) handleSync(ctx *gin.Context) {
 			finishedAt = time.Now().UTC().Format(time.RFC3339)
 		}
 
-		// Check if execution failed
-		if exec.Status == types.ExecutionStatusFailed {
+		// Check if execution failed or was cancelled while we waited
+		if exec.Status == types.ExecutionStatusFailed || exec.Status == types.ExecutionStatusCancelled {
 			errMsg := "execution failed"
 			if exec.ErrorMessage != nil {
 				errMsg = *exec.ErrorMessage
@@ -264,10 +288,7 @@ func (c *executionController) handleSync(ctx *gin.Context) {
 				FinishedAt:        finishedAt,
 				WebhookRegistered: exec.WebhookRegistered,
 			}
-			ctx.Header("X-Execution-ID", exec.ExecutionID)
-			ctx.Header("X-Run-ID", exec.RunID)
-			ctx.JSON(http.StatusOK, response)
-			return
+			return response, nil
 		}
 
 		// Return successful execution result
@@ -280,10 +301,7 @@ func (c *executionController) handleSync(ctx *gin.Context) {
 			FinishedAt:        finishedAt,
 			WebhookRegistered: exec.WebhookRegistered,
 		}
-		ctx.Header("X-Execution-ID", exec.ExecutionID)
-		ctx.Header("X-Run-ID", exec.RunID)
-		ctx.JSON(http.StatusOK, response)
-		return
+		return response, nil
 	}
 
 	// Agent returned HTTP 200 (synchronous result), process completion normally
@@ -297,20 +315,22 @@ func (c *executionController) handleSync(ctx *gin.Context) {
 	}
 	if err := enqueueCompletion(job); err != nil {
 		logger.Logger.Error().Err(err).Str("execution_id", plan.exec.ExecutionID).Msg("failed to enqueue completion job")
-		writeExecutionError(ctx, err)
-		return
+		return ExecuteResponse{}, err
 	}
 	if err := <-job.done; err != nil {
 		logger.Logger.Error().Err(err).Str("execution_id", plan.exec.ExecutionID).Msg("completion processing failed")
-		writeExecutionError(ctx, err)
-		return
+		return ExecuteResponse{}, err
 	}
 	if callErr != nil {
-		writeExecutionError(ctx, callErr)
-		return
+		// A cancelled execution usually surfaces as an aborted agent call; report the
+		// cancellation rather than the transport error.
+		if exec, err := c.store.GetExecutionRecord(reqCtx, plan.exec.ExecutionID); err == nil && exec != nil && exec.Status == types.ExecutionStatusCancelled {
+			return renderCancelledResponse(exec), nil
+		}
+		return ExecuteResponse{}, callErr
 	}
 
-	response := ExecuteResponse{
+	return ExecuteResponse{
 		ExecutionID:       plan.exec.ExecutionID,
 		RunID:             plan.exec.RunID,
 		Status:            types.ExecutionStatusSucceeded,
@@ -318,42 +338,29 @@ func (c *executionController) handleSync(ctx *gin.Context) {
 		DurationMS:        elapsed.Milliseconds(),
 		FinishedAt:        time.Now().UTC().Format(time.RFC3339),
 		WebhookRegistered: plan.webhookRegistered,
-	}
-
-	ctx.Header("X-Execution-ID", plan.exec.ExecutionID)
-	ctx.Header("X-Run-ID", plan.exec.RunID)
-	ctx.JSON(http.StatusOK, response)
+	}, nil
 }
 
 func (c *executionController) handleAsync(ctx *gin.Context) {
+	// In-flight limits are applied when the queue dispatches the execution.
+	release, admitted := admitRequest(ctx, admissionMode{rate: true})
+	if !admitted {
+		return
+	}
+	release()
+
 	reqCtx := ctx.Request.Context()
-	plan, err := c.prepareExecution(reqCtx, ctx)
+	plan, err := c.prepareExecution(reqCtx, ctx, true)
 	if err != nil {
 		writeExecutionError(ctx, err)
 		return
 	}
 
-	// Emit execution started event with full reasoner context
-	c.publishExecutionStartedEvent(plan)
-
-	pool := getAsyncWorkerPool()
-	job := asyncExecutionJob{
-		controller: c,
-		plan:       *plan,
+	if keys, ok := admissionKeysFromRequest(ctx); ok {
+		plan.caller = keys.caller
 	}
-
-	if ok := pool.submit(job); !ok {
-		queueErr := errors.New("async execution queue is full; retry later")
-		if updateErr := c.failExecution(reqCtx, plan, queueErr, 0, nil); updateErr != nil {
-			logger.Logger.Error().
-				Err(updateErr).
-				Str("execution_id", plan.exec.ExecutionID).
-				Msg("failed to persist execution failure after queue saturation")
-		}
-		logger.Logger.Warn().
-			Str("execution_id", plan.exec.ExecutionID).
-			Msg("async execution rejected due to queue saturation")
-		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": queueErr.Error()})
+	if err := c.submitQueued(reqCtx, plan); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -379,6 +386,25 @@ func (c *executionController) handleAsync(ctx *gin.Context) {
 	ctx.JSON(http.StatusAccepted, response)
 }
 
+// submitQueued announces a prepared async execution and hands it to the durable queue.
+// If the queue cannot persist it, the execution is marked failed.
+func (c *executionController) submitQueued(ctx context.Context, plan *preparedExecution) error {
+	// Emit execution started event with full reasoner context
+	c.publishExecutionStartedEvent(plan)
+
+	if err := c.enqueueExecution(ctx, plan); err != nil {
+		queueErr := fmt.Errorf("persist queued execution: %w", err)
+		if updateErr := c.failExecution(ctx, plan, queueErr, 0, nil); updateErr != nil {
+			logger.Logger.Error().
+				Err(updateErr).
+				Str("execution_id", plan.exec.ExecutionID).
+				Msg("failed to persist execution failure after enqueue error")
+		}
+		return queueErr
+	}
+	return nil
+}
+
 func (c *executionController) handleStatus(ctx *gin.Context) {
 	reqCtx := ctx.Request.Context()
 	executionID := ctx.Param("execution_id")
@@ -392,7 +418,7 @@ func (c *executionController) handleStatus(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to load execution: %v", err)})
 		return
 	}
-	if exec == nil {
+	if exec == nil || !executionVisible(reqCtx, c.store, exec) {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "execution not found"})
 		return
 	}
@@ -419,7 +445,7 @@ func (c *executionController) handleBatchStatus(ctx *gin.Context) {
 			}
 			continue
 		}
-		if exec == nil {
+		if exec == nil || !executionVisible(reqCtx, c.store, exec) {
 			response[id] = ExecutionStatusResponse{
 				ExecutionID: id,
 				Status:      "not_found",
@@ -464,15 +490,32 @@ func (c *executionController) handleStatusUpdate(ctx *gin.Context) {
 		}
 	}
 
+	if auth.TeamFrom(reqCtx) != "" {
+		exec, err := c.store.GetExecutionRecord(reqCtx, executionID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to load execution: %v", err)})
+			return
+		}
+		if exec == nil || !executionVisible(reqCtx, c.store, exec) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "execution not found"})
+			return
+		}
+	}
+
 	resultURI := c.savePayload(reqCtx, resultBytes)
 	isTerminal := types.IsTerminalExecutionStatus(normalizedStatus)
 	var elapsed time.Duration
 	var errorMsg *string
+	alreadyCancelled := false
 
 	updated, err := c.store.UpdateExecutionRecord(reqCtx, executionID, func(current *types.Execution) (*types.Execution, error) {
 		if current == nil {
 			return nil, fmt.Errorf("execution %s not found", executionID)
 		}
+		if current.Status == types.ExecutionStatusCancelled {
+			alreadyCancelled = true
+			return nil, nil
+		}
 
 		current.Status = normalizedStatus
 		if len(resultBytes) > 0 {
@@ -529,6 +572,15 @@ func (c *executionController) handleStatusUpdate(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "execution not found"})
 		return
 	}
+	if isTerminal || alreadyCancelled {
+		settleAsyncExecution(executionID)
+	}
+	if alreadyCancelled {
+		// Agents that finish after a cancellation still report back; keep the cancelled state.
+		logResultAfterCancel(executionID)
+		ctx.JSON(http.StatusOK, renderStatus(updated))
+		return
+	}
 	if elapsed == 0 && updated.DurationMS != nil {
 		elapsed = time.Duration(*updated.DurationMS) * time.Millisecond
 	}
@@ -564,6 +616,8 @@ func (c *executionController) publishExecutionEventWithReasonerInfo(exec *types.
 		eventType = events.ExecutionCompleted
 	case string(types.ExecutionStatusFailed):
 		eventType = events.ExecutionFailed
+	case string(types.ExecutionStatusCancelled):
+		eventType = events.ExecutionCancelled
 	case string(types.ExecutionStatusRunning):
 		eventType = events.ExecutionStarted
 	case "created":
@@ -679,6 +733,13 @@ func (c *executionController) waitForExecutionCompletion(ctx context.Context, ex
 	eventChan := c.eventBus.Subscribe(subscriberID)
 	defer c.eventBus.Unsubscribe(subscriberID)
 
+	// The agent may have reported completion before we subscribed (poll-mode workers
+	// can claim and acknowledge quickly), so check the stored record once up front.
+	if exec, err := c.store.GetExecutionRecord(ctx, executionID); err == nil && exec != nil &&
+		(exec.Status == types.ExecutionStatusSucceeded || exec.Status == types.ExecutionStatusFailed || exec.Status == types.ExecutionStatusCancelled) {
+		return exec, nil
+	}
+
 	// Create timeout timer
 	timer := time.NewTimer(timeout)
 	defer timer.Stop()
@@ -707,7 +768,7 @@ func (c *executionController) waitForExecutionCompletion(ctx context.Context, ex
 			}
 
 			// Check if this is a terminal event
-			if event.Type == events.ExecutionCompleted || event.Type == events.ExecutionFailed {
+			if event.Type == events.ExecutionCompleted || event.Type == events.ExecutionFailed || event.Type == events.ExecutionCancelled {
 				logger.Logger.Debug().
 					Str("execution_id", executionID).
 					Str("event_type", string(event.Type)).
@@ -738,9 +799,32 @@ type preparedExecution struct {
 	targetType        string
 	webhookRegistered bool
 	webhookError      *string
+	retryPolicy       *types.RetryPolicy
+	// caller is the admission caller key a queued execution counts against.
+	caller string
+
+	// stream receives the partial output of agents that answer with an event stream;
+	// nil unless the caller asked for a streaming execution. streamed records that
+	// output has already reached the caller.
+	stream   func(chunk []byte) error
+	streamed bool
+
+	// cacheHit records that the result was served from the reasoner's result cache.
+	cacheHit bool
+}
+
+// executionSubmission is a parsed execution request, whether it arrived over HTTP or
+// was fired by a schedule.
+type executionSubmission struct {
+	target     *parsedTarget
+	request    ExecuteRequest
+	headers    executionHeaders
+	scheduleID *string
 }
 
-func (c *executionController) prepareExecution(ctx context.Context, ginCtx *gin.Context) (*preparedExecution, error) {
+// prepareExecution validates the request and persists the execution record. Queued
+// executions wait in the durable queue until a worker dispatches them.
+func (c *executionController) prepareExecution(ctx context.Context, ginCtx *gin.Context, queued bool) (*preparedExecution, error) {
 	targetParam := ginCtx.Param("target")
 	target, err := parseTarget(targetParam)
 	if err != nil {
@@ -751,9 +835,23 @@ func (c *executionController) prepareExecution(ctx context.Context, ginCtx *gin.
 	if err := ginCtx.ShouldBindJSON(&req); err != nil {
 		return nil, fmt.Errorf("invalid request body: %w", err)
 	}
+
+	return c.prepareSubmission(ctx, executionSubmission{
+		target:  target,
+		request: req,
+		headers: readExecutionHeaders(ginCtx),
+	}, queued)
+}
+
+func (c *executionController) prepareSubmission(ctx context.Context, submission executionSubmission, queued bool) (*preparedExecution, error) {
+	target := submission.target
+	req := submission.request
 	if len(req.Input) == 0 {
 		return nil, errors.New("input is required")
 	}
+	if err := req.Retry.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid retry policy: %w", err)
+	}
 
 	var (
 		sanitizedWebhook *normalizedWebhookConfig
@@ -774,7 +872,7 @@ func (c *executionController) prepareExecution(ctx context.Context, ginCtx *gin.
 	if err != nil {
 		return nil, fmt.Errorf("failed to load agent '%s': %w", target.NodeID, err)
 	}
-	if agent == nil {
+	if agent == nil || !auth.CanAccessTeam(ctx, agent.TeamID) {
 		return nil, fmt.Errorf("agent '%s' not found", target.NodeID)
 	}
 	if agent.DeploymentType == "" && agent.Metadata.Custom != nil {
@@ -795,7 +893,7 @@ func (c *executionController) prepareExecution(ctx context.Context, ginCtx *gin.
 	}
 	target.TargetType = targetType
 
-	headers := readExecutionHeaders(ginCtx)
+	headers := submission.headers
 	runID := headers.runID
 	if runID == "" {
 		runID = utils.GenerateRunID()
@@ -829,11 +927,17 @@ func (c *executionController) prepareExecution(ctx context.Context, ginCtx *gin.
 		CreatedAt:         now,
 		UpdatedAt:         now,
 	}
+	if queued || isPollModeAgent(agent) {
+		// Poll-mode agents pick the work up through /actions/claim, so the
+		// execution stays queued until a worker leases it.
+		exec.Status = types.ExecutionStatusQueued
+	}
 
 	agentPayload := make(map[string]interface{}, len(req.Input))
 	for key, value := range req.Input {
 		agentPayload[key] = value
 	}
+	c.injectMemory(ctx, reasonerMemoryConfig(agent, target), runID, headers, agentPayload)
 
 	var agentPayloadBytes []byte
 	if agent.DeploymentType == "serverless" {
@@ -854,6 +958,7 @@ func (c *executionController) prepareExecution(ctx context.Context, ginCtx *gin.
 	if headers.actorID != nil {
 		exec.ActorID = headers.actorID
 	}
+	exec.ScheduleID = submission.scheduleID
 
 	if err := c.store.CreateExecutionRecord(ctx, exec); err != nil {
 		return nil, fmt.Errorf("create execution record: %w", err)
@@ -896,38 +1001,58 @@ func (c *executionController) prepareExecution(ctx context.Context, ginCtx *gin.
 		targetType:        targetType,
 		webhookRegistered: webhookRegistered,
 		webhookError:      webhookError,
+		retryPolicy:       resolveRetryPolicy(req.Retry, agent, target),
 	}, nil
 }
 
 func (c *executionController) callAgent(ctx context.Context, plan *preparedExecution) ([]byte, time.Duration, bool, error) {
 	start := time.Now()
-	url := buildAgentURL(plan.agent, plan.target)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(plan.requestBody))
-	if err != nil {
-		return nil, 0, false, fmt.Errorf("create agent request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Run-ID", plan.exec.RunID)
-	req.Header.Set("X-Execution-ID", plan.exec.ExecutionID)
-	req.Header.Set("X-Workflow-ID", plan.exec.RunID)
-	if plan.exec.ParentExecutionID != nil {
-		req.Header.Set("X-Parent-Execution-ID", *plan.exec.ParentExecutionID)
-	}
-	if plan.exec.SessionID != nil {
-		req.Header.Set("X-Session-ID", *plan.exec.SessionID)
-	}
-	if plan.exec.ActorID != nil {
-		req.Header.Set("X-Actor-ID", *plan.exec.ActorID)
+	if isPollModeAgent(plan.agent) {
+		if err := c.enqueueAction(ctx, plan); err != nil {
+			return nil, time.Since(start), false, err
+		}
+		return nil, time.Since(start), true, nil
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, time.Since(start), false, fmt.Errorf("agent call failed: %w", err)
+	// Replicas that refuse the connection never saw the request, so the next one is tried.
+	balancer := currentInstanceBalancer()
+	endpoints := balancer.endpoints(ctx, c.store, plan.agent)
+	var (
+		url     string
+		resp    *http.Response
+		release func()
+	)
+	for i, endpoint := range endpoints {
+		url = buildAgentURL(endpoint.forAgent(plan.agent), plan.target)
+		req, err := newAgentRequest(ctx, plan, url)
+		if err != nil {
+			return nil, 0, false, err
+		}
+
+		release = balancer.acquire(plan.agent.ID, endpoint)
+		resp, err = c.httpClient.Do(req)
+		if err == nil {
+			break
+		}
+		release()
+		if !isConnectError(err) || i == len(endpoints)-1 {
+			return nil, time.Since(start), false, fmt.Errorf("agent call failed: %w", err)
+		}
+		logger.Logger.Warn().
+			Err(err).
+			Str("execution_id", plan.exec.ExecutionID).
+			Str("agent", plan.target.NodeID).
+			Str("instance_id", endpoint.instanceID).
+			Msg("agent instance unreachable; failing over")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusAccepted {
+		// The agent keeps working on the execution until it reports a final status.
+		balancer.holdUntilSettled(plan.exec.ExecutionID, release)
+		if exec, err := c.store.GetExecutionRecord(ctx, plan.exec.ExecutionID); err == nil && exec != nil && types.IsTerminalExecutionStatus(exec.Status) {
+			balancer.settle(plan.exec.ExecutionID)
+		}
 		logger.Logger.Info().
 			Str("execution_id", plan.exec.ExecutionID).
 			Str("agent", plan.target.NodeID).
@@ -935,6 +1060,12 @@ func (c *executionController) callAgent(ctx context.Context, plan *preparedExecu
 			Msg("agent acknowledged async execution")
 		return nil, time.Since(start), true, nil
 	}
+	defer release()
+
+	if plan.stream != nil && resp.StatusCode < http.StatusBadRequest && isEventStream(resp.Header.Get("Content-Type")) {
+		body, err := relayAgentStream(resp.Body, plan)
+		return body, time.Since(start), false, err
+	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -951,21 +1082,50 @@ func (c *executionController) callAgent(ctx context.Context, plan *preparedExecu
 	}
 
 	if resp.StatusCode >= http.StatusBadRequest {
-		return body, time.Since(start), false, fmt.Errorf("agent error (%d): %s", resp.StatusCode, truncateForLog(body))
+		return body, time.Since(start), false, &agentStatusError{StatusCode: resp.StatusCode, Body: truncateForLog(body)}
 	}
 
 	return body, time.Since(start), false, nil
 }
 
+func newAgentRequest(ctx context.Context, plan *preparedExecution, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(plan.requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("create agent request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Run-ID", plan.exec.RunID)
+	req.Header.Set("X-Execution-ID", plan.exec.ExecutionID)
+	req.Header.Set("X-Workflow-ID", plan.exec.RunID)
+	if plan.exec.ParentExecutionID != nil {
+		req.Header.Set("X-Parent-Execution-ID", *plan.exec.ParentExecutionID)
+	}
+	if plan.exec.SessionID != nil {
+		req.Header.Set("X-Session-ID", *plan.exec.SessionID)
+	}
+	if plan.exec.ActorID != nil {
+		req.Header.Set("X-Actor-ID", *plan.exec.ActorID)
+	}
+	if plan.stream != nil {
+		req.Header.Set("Accept", "text/event-stream, application/json")
+	}
+	return req, nil
+}
+
 func (c *executionController) completeExecution(ctx context.Context, plan *preparedExecution, result []byte, elapsed time.Duration) error {
 	resultURI := c.savePayload(ctx, result)
 
 	var lastErr error
 	for attempt := 0; attempt < 5; attempt++ {
+		cancelled := false
 		updated, err := c.store.UpdateExecutionRecord(ctx, plan.exec.ExecutionID, func(current *types.Execution) (*types.Execution, error) {
 			if current == nil {
 				return nil, fmt.Errorf("execution %s not found", plan.exec.ExecutionID)
 			}
+			if current.Status == types.ExecutionStatusCancelled {
+				cancelled = true
+				return nil, nil
+			}
 			now := time.Now().UTC()
 			current.Status = types.ExecutionStatusSucceeded
 			current.ResultPayload = json.RawMessage(result)
@@ -977,7 +1137,12 @@ func (c *executionController) completeExecution(ctx context.Context, plan *prepa
 			current.ResultURI = resultURI
 			return current, nil
 		})
+		if err == nil && cancelled {
+			logResultAfterCancel(plan.exec.ExecutionID)
+			return nil
+		}
 		if err == nil {
+			c.cacheResult(ctx, plan, result)
 			c.updateWorkflowExecutionFinalState(
 				ctx,
 				plan.exec.ExecutionID,
@@ -1011,10 +1176,15 @@ func (c *executionController) failExecution(ctx context.Context, plan *preparedE
 	resultURI := c.savePayload(ctx, result)
 	var lastErr error
 	for attempt := 0; attempt < 5; attempt++ {
+		cancelled := false
 		updated, err := c.store.UpdateExecutionRecord(ctx, plan.exec.ExecutionID, func(current *types.Execution) (*types.Execution, error) {
 			if current == nil {
 				return nil, fmt.Errorf("execution %s not found", plan.exec.ExecutionID)
 			}
+			if current.Status == types.ExecutionStatusCancelled {
+				cancelled = true
+				return nil, nil
+			}
 			now := time.Now().UTC()
 			current.Status = types.ExecutionStatusFailed
 			current.ErrorMessage = &errMsg
@@ -1028,6 +1198,10 @@ func (c *executionController) failExecution(ctx context.Context, plan *preparedE
 			current.ResultURI = resultURI
 			return current, nil
 		})
+		if err == nil && cancelled {
+			logResultAfterCancel(plan.exec.ExecutionID)
+			return nil
+		}
 		if err == nil {
 			c.updateWorkflowExecutionFinalState(
 				ctx,
@@ -1059,6 +1233,32 @@ func (c *executionController) failExecution(ctx context.Context, plan *preparedE
 	return lastErr
 }
 
+// logResultAfterCancel records an agent outcome that arrived after the execution was cancelled.
+func logResultAfterCancel(executionID string) {
+	logger.Logger.Info().
+		Str("execution_id", executionID).
+		Msg("ignoring agent result for cancelled execution")
+}
+
+// renderCancelledResponse builds the synchronous response for an execution cancelled mid-flight.
+func renderCancelledResponse(exec *types.Execution) ExecuteResponse {
+	response := ExecuteResponse{
+		ExecutionID:       exec.ExecutionID,
+		RunID:             exec.RunID,
+		Status:            string(exec.Status),
+		ErrorMessage:      exec.ErrorMessage,
+		FinishedAt:        time.Now().UTC().Format(time.RFC3339),
+		WebhookRegistered: exec.WebhookRegistered,
+	}
+	if exec.DurationMS != nil {
+		response.DurationMS = *exec.DurationMS
+	}
+	if exec.CompletedAt != nil {
+		response.FinishedAt = exec.CompletedAt.UTC().Format(time.RFC3339)
+	}
+	return response
+}
+
 func (c *executionController) triggerWebhook(executionID string) {
 	if c.webhooks == nil || executionID == "" {
 		return
@@ -1491,92 +1691,6 @@ func (c *executionController) savePayload(ctx context.Context, data []byte) *str
 	return &uri
 }
 
-func (j asyncExecutionJob) process() {
-	bgCtx := context.Background()
-	resultBody, elapsed, asyncAccepted, callErr := j.controller.callAgent(bgCtx, &j.plan)
-	if callErr == nil && asyncAccepted {
-		logger.Logger.Info().
-			Str("execution_id", j.plan.exec.ExecutionID).
-			Msg("agent accepted execution for async processing")
-		return
-	}
-	job := completionJob{
-		controller: j.controller,
-		plan:       &j.plan,
-		result:     resultBody,
-		elapsed:    elapsed,
-		callErr:    callErr,
-	}
-	if err := enqueueCompletion(job); err != nil {
-		logger.Logger.Error().
-			Err(err).
-			Str("execution_id", j.plan.exec.ExecutionID).
-			Msg("failed to enqueue completion job for async execution")
-		if callErr != nil {
-			if updateErr := j.controller.failExecution(bgCtx, &j.plan, callErr, elapsed, resultBody); updateErr != nil {
-				logger.Logger.Error().
-					Err(updateErr).
-					Str("execution_id", j.plan.exec.ExecutionID).
-					Msg("fallback async failure persistence failed")
-			}
-		} else {
-			if updateErr := j.controller.completeExecution(bgCtx, &j.plan, resultBody, elapsed); updateErr != nil {
-				logger.Logger.Error().
-					Err(updateErr).
-					Str("execution_id", j.plan.exec.ExecutionID).
-					Msg("fallback async completion persistence failed")
-			}
-		}
-	}
-}
-
-func newAsyncWorkerPool(workerCount, queueCapacity int) *asyncWorkerPool {
-	pool := &asyncWorkerPool{
-		queue: make(chan asyncExecutionJob, queueCapacity),
-	}
-
-	for i := 0; i < workerCount; i++ {
-		go func(workerID int) {
-			for job := range pool.queue {
-				job.process()
-			}
-		}(i)
-	}
-
-	logger.Logger.Info().
-		Int("workers", workerCount).
-		Int("queue_capacity", queueCapacity).
-		Msg("async execution worker pool initialized")
-
-	return pool
-}
-
-func (p *asyncWorkerPool) submit(job asyncExecutionJob) bool {
-	select {
-	case p.queue <- job:
-		return true
-	default:
-		return false
-	}
-}
-
-func getAsyncWorkerPool() *asyncWorkerPool {
-	asyncPoolOnce.Do(func() {
-		workerCount := resolveIntFromEnv("AGENTFIELD_EXEC_ASYNC_WORKERS", runtime.NumCPU())
-		if workerCount <= 0 {
-			workerCount = runtime.NumCPU()
-		}
-
-		queueCapacity := resolveIntFromEnv("AGENTFIELD_EXEC_ASYNC_QUEUE_CAPACITY", 1024)
-		if queueCapacity <= 0 {
-			queueCapacity = 1024
-		}
-
-		asyncPool = newAsyncWorkerPool(workerCount, queueCapacity)
-	})
-	return asyncPool
-}
-
 func resolveIntFromEnv(key string, fallback int) int {
 	raw := strings.TrimSpace(os.Getenv(key))
 	if raw == "" {
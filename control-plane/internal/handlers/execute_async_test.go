@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -19,95 +18,118 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestExecuteAsyncHandler_QueueSaturation(t *testing.T) {
-	gin.SetMode(gin.TestMode)
-
-	// Set a small queue capacity for this test
-	// Note: This only works if the pool hasn't been initialized yet
-	// In a real scenario, the pool is initialized once, so this test
-	// verifies the queue saturation logic when the queue is actually full
-	originalCapacity := os.Getenv("AGENTFIELD_EXEC_ASYNC_QUEUE_CAPACITY")
-	defer func() {
-		if originalCapacity != "" {
-			os.Setenv("AGENTFIELD_EXEC_ASYNC_QUEUE_CAPACITY", originalCapacity)
-		} else {
-			os.Unsetenv("AGENTFIELD_EXEC_ASYNC_QUEUE_CAPACITY")
-		}
-	}()
-
-	// Set a very small capacity to make saturation easier to test
-	os.Setenv("AGENTFIELD_EXEC_ASYNC_QUEUE_CAPACITY", "2")
+func TestExecutionQueue_ResumesPersistedExecutions(t *testing.T) {
+	var requestCount int32
+	agentServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+		require.Equal(t, "exec-queued", r.Header.Get("X-Execution-ID"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer agentServer.Close()
 
 	agent := &types.AgentNode{
 		ID:        "node-1",
-		BaseURL:   "http://agent.example",
+		BaseURL:   agentServer.URL,
 		Reasoners: []types.ReasonerDefinition{{ID: "reasoner-a"}},
 	}
-
 	store := newTestExecutionStorage(agent)
 	payloads := services.NewFilePayloadStore(t.TempDir())
 
-	// Get the pool (will be initialized with small capacity)
-	pool := getAsyncWorkerPool()
-
-	// Fill the queue completely - submit more jobs than capacity
-	// Workers will consume some, but we want to ensure queue is full when we make the request
-	queueCapacity := cap(pool.queue)
-
-	// Submit enough jobs to fill the queue (accounting for workers consuming)
-	// We submit more than capacity to ensure queue stays full
-	for i := 0; i < queueCapacity*2; i++ {
-		job := asyncExecutionJob{
-			controller: newExecutionController(store, payloads, nil, 90*time.Second),
-			plan: preparedExecution{
-				exec: &types.Execution{
-					ExecutionID: "test-exec-fill",
-					RunID:       "test-run",
-				},
-			},
-		}
-		if !pool.submit(job) {
-			// Queue is full, good
-			break
-		}
-	}
+	// Simulate an execution accepted before a restart: the record is queued and the
+	// payload is still in the durable queue.
+	seedExecution(t, store, "exec-queued", nil, types.ExecutionStatusQueued)
+	require.NoError(t, store.EnqueueExecution(context.Background(), &types.QueuedExecution{
+		ExecutionID: "exec-queued",
+		NodeID:      "node-1",
+		Target:      "reasoner-a",
+		TargetType:  "reasoner",
+		Payload:     json.RawMessage(`{"foo":"bar"}`),
+	}))
 
-	// Give a tiny moment for queue state to stabilize
-	time.Sleep(10 * time.Millisecond)
+	// Another replica holds the lease, so this one must leave the entry alone.
+	lock, err := store.AcquireLock(context.Background(), executionQueueLockPrefix+"exec-queued", time.Minute)
+	require.NoError(t, err)
 
-	router := gin.New()
-	router.POST("/api/v1/execute/async/:target", ExecuteAsyncHandler(store, payloads, nil, 90*time.Second))
+	StartExecutionQueue(store, payloads, nil, 90*time.Second)
+	t.Cleanup(func() { StopExecutionQueue(store) })
 
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/execute/async/node-1.reasoner-a", strings.NewReader(`{"input":{"foo":"bar"}}`))
-	req.Header.Set("Content-Type", "application/json")
-	resp := httptest.NewRecorder()
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, int32(0), atomic.LoadInt32(&requestCount))
 
-	router.ServeHTTP(resp, req)
+	require.NoError(t, store.ReleaseLock(context.Background(), lock.LockID))
 
-	// The queue might not be full if workers consumed jobs quickly
-	// So we check if we got either 503 (queue full) or 202 (accepted)
-	// If we got 202, the queue wasn't full, which is also a valid test outcome
-	if resp.Code == http.StatusServiceUnavailable {
-		var payload map[string]string
-		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &payload))
-		require.Contains(t, payload["error"], "async execution queue is full")
-
-		// Verify execution was marked as failed
-		records, err := store.QueryExecutionRecords(context.Background(), types.ExecutionFilter{})
-		require.NoError(t, err)
-		if len(records) > 0 {
-			// Find the execution we just created
-			for i := len(records) - 1; i >= 0; i-- {
-				if records[i].Status == types.ExecutionStatusFailed {
-					// Found a failed execution, which is expected for queue saturation
-					return
-				}
-			}
-		}
-	} else {
-		// Queue wasn't full, which is fine - test still validates the code path exists
-		require.Equal(t, http.StatusAccepted, resp.Code)
+	require.Eventually(t, func() bool {
+		record, err := store.GetExecutionRecord(context.Background(), "exec-queued")
+		return err == nil && record != nil && record.Status == types.ExecutionStatusSucceeded
+	}, 3*time.Second, 20*time.Millisecond)
+	require.Equal(t, int32(1), atomic.LoadInt32(&requestCount))
+
+	require.Eventually(t, func() bool {
+		items, err := store.ListQueuedExecutions(context.Background(), 0)
+		return err == nil && len(items) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestExecutionQueue_DropsCancelledEntries(t *testing.T) {
+	var requestCount int32
+	agentServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer agentServer.Close()
+
+	agent := &types.AgentNode{ID: "node-1", BaseURL: agentServer.URL}
+	store := newTestExecutionStorage(agent)
+	payloads := services.NewFilePayloadStore(t.TempDir())
+
+	seedExecution(t, store, "exec-cancelled", nil, types.ExecutionStatusCancelled)
+	require.NoError(t, store.EnqueueExecution(context.Background(), &types.QueuedExecution{
+		ExecutionID: "exec-cancelled",
+		NodeID:      "node-1",
+		Target:      "reasoner-a",
+		TargetType:  "reasoner",
+		Payload:     json.RawMessage(`{}`),
+	}))
+
+	StartExecutionQueue(store, payloads, nil, 90*time.Second)
+	t.Cleanup(func() { StopExecutionQueue(store) })
+
+	require.Eventually(t, func() bool {
+		items, err := store.ListQueuedExecutions(context.Background(), 0)
+		return err == nil && len(items) == 0
+	}, 3*time.Second, 10*time.Millisecond)
+	require.Equal(t, int32(0), atomic.LoadInt32(&requestCount))
+}
+
+func TestDispatchQueued_FailsExecutionOfUnknownAgent(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+	controller := newExecutionController(provider, services.NewFilePayloadStore(t.TempDir()), nil, 5*time.Second)
+
+	require.NoError(t, provider.CreateExecutionRecord(ctx, &types.Execution{
+		ExecutionID: "exec-orphan",
+		RunID:       "run-1",
+		AgentNodeID: "node-gone",
+		ReasonerID:  "reasoner-a",
+		NodeID:      "node-gone",
+		Status:      string(types.ExecutionStatusQueued),
+		StartedAt:   time.Now(),
+	}))
+	item := &types.QueuedExecution{
+		ExecutionID: "exec-orphan",
+		NodeID:      "node-gone",
+		Target:      "reasoner-a",
+		TargetType:  "reasoner",
+		Payload:     json.RawMessage(`{}`),
 	}
+
+	// The entry is settled rather than retried forever.
+	require.NoError(t, controller.dispatchQueued(ctx, item))
+	record, err := provider.GetExecutionRecord(ctx, "exec-orphan")
+	require.NoError(t, err)
+	require.Equal(t, types.ExecutionStatusFailed, types.ExecutionStatus(record.Status))
+	require.NotNil(t, record.ErrorMessage)
+	require.Contains(t, *record.ErrorMessage, "agent 'node-gone' not found")
 }
 
 func TestExecuteAsyncHandler_WithWebhook(t *testing.T) {
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Server-sent event names used by streaming executions, both between the control plane
+// and agents and between the control plane and callers.
+const (
+	streamEventStarted = "started"
+	streamEventChunk   = "chunk"
+	streamEventResult  = "result"
+	streamEventError   = "error"
+)
+
+// ExecuteStreamHandler handles synchronous execution requests whose partial output is
+// relayed to the caller as server-sent events.
+func ExecuteStreamHandler(store ExecutionStore, payloads services.PayloadStore, webhooks services.WebhookDispatcher, timeout time.Duration) gin.HandlerFunc {
+	controller := newExecutionController(store, payloads, webhooks, timeout)
+	return controller.handleStream
+}
+
+// handleStream runs an execution like handleSync but answers with an event stream: a
+// started event, one chunk event per partial output the agent streams back, and a
+// result event carrying the ExecuteResponse once the outcome is persisted. Agents that
+// do not stream only produce the started and result events.
+func (c *executionController) handleStream(ctx *gin.Context) {
+	release, admitted := admitRequest(ctx, admissionMode{rate: true, concurrency: true})
+	if !admitted {
+		return
+	}
+	defer release()
+
+	reqCtx := ctx.Request.Context()
+	plan, err := c.prepareExecution(reqCtx, ctx, false)
+	if err != nil {
+		writeExecutionError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Execution-ID", plan.exec.ExecutionID)
+	ctx.Header("X-Run-ID", plan.exec.RunID)
+	ctx.Status(http.StatusOK)
+	_ = writeStreamEvent(ctx, streamEventStarted, gin.H{
+		"execution_id": plan.exec.ExecutionID,
+		"run_id":       plan.exec.RunID,
+	})
+
+	plan.stream = func(chunk []byte) error {
+		return writeStreamEvent(ctx, streamEventChunk, string(chunk))
+	}
+
+	// Emit execution started event with full reasoner context
+	c.publishExecutionStartedEvent(plan)
+
+	response, err := c.runSync(reqCtx, plan)
+	if err != nil {
+		_ = writeStreamEvent(ctx, streamEventError, gin.H{
+			"execution_id": plan.exec.ExecutionID,
+			"error":        err.Error(),
+		})
+		return
+	}
+	_ = writeStreamEvent(ctx, streamEventResult, response)
+}
+
+// writeStreamEvent writes and flushes one server-sent event. It reports an error once
+// the caller has gone away.
+func writeStreamEvent(ctx *gin.Context, event string, data interface{}) error {
+	if err := ctx.Request.Context().Err(); err != nil {
+		return err
+	}
+	ctx.SSEvent(event, data)
+	ctx.Writer.Flush()
+	return nil
+}
+
+// isEventStream reports whether an agent answered with server-sent events.
+func isEventStream(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "text/event-stream"
+}
+
+// relayAgentStream reads the event stream an agent answers a streaming call with.
+// Chunk events are handed to plan.stream as they arrive, the result event becomes the
+// execution result and an error event fails the call like an HTTP 500 would.
+func relayAgentStream(body io.Reader, plan *preparedExecution) ([]byte, error) {
+	reader := bufio.NewReader(body)
+	var (
+		event string
+		data  bytes.Buffer
+	)
+	for {
+		line, readErr := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case line == "":
+			if data.Len() == 0 {
+				event = ""
+				break
+			}
+			payload := append([]byte(nil), data.Bytes()...)
+			switch event {
+			case "", streamEventChunk:
+				plan.streamed = true
+				if err := plan.stream(payload); err != nil {
+					return nil, fmt.Errorf("relay agent stream: %w", err)
+				}
+			case streamEventResult:
+				return payload, nil
+			case streamEventError:
+				return nil, &agentStatusError{StatusCode: http.StatusInternalServerError, Body: truncateForLog(payload)}
+			}
+			event = ""
+			data.Reset()
+		case strings.HasPrefix(line, ":"):
+			// Comment lines keep idle connections open.
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+
+		if readErr != nil {
+			if errors.Is(readErr, io.EOF) {
+				return nil, errors.New("agent stream ended without a result")
+			}
+			return nil, fmt.Errorf("read agent stream: %w", readErr)
+		}
+	}
+}
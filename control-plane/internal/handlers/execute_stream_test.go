@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/services"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type streamEvent struct {
+	name string
+	data string
+}
+
+func postExecuteStream(t *testing.T, agent *types.AgentNode, body string) (*httptest.ResponseRecorder, []streamEvent, *testExecutionStorage) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	store := newTestExecutionStorage(agent)
+	router := gin.New()
+	router.POST("/api/v1/execute/stream/:target", ExecuteStreamHandler(store, services.NewFilePayloadStore(t.TempDir()), nil, 5*time.Second))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/execute/stream/node-1.reasoner-a", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	var (
+		events  []streamEvent
+		current streamEvent
+	)
+	scanner := bufio.NewScanner(strings.NewReader(resp.Body.String()))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if current.name != "" {
+				events = append(events, current)
+			}
+			current = streamEvent{}
+		case strings.HasPrefix(line, "event:"):
+			current.name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			current.data += strings.TrimPrefix(line, "data:")
+		}
+	}
+	return resp, events, store
+}
+
+func TestExecuteStreamHandler_RelaysChunksAndPersistsResult(t *testing.T) {
+	agentServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Contains(t, r.Header.Get("Accept"), "text/event-stream")
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(": keep-alive\n\n"))
+		_, _ = w.Write([]byte("event: chunk\ndata: {\"token\":\"hel\"}\n\n"))
+		_, _ = w.Write([]byte("event: chunk\r\ndata: {\"token\":\"lo\"}\r\n\r\n"))
+		_, _ = w.Write([]byte("event: result\ndata: {\"text\":\"hello\"}\n\n"))
+	}))
+	defer agentServer.Close()
+
+	agent := &types.AgentNode{ID: "node-1", BaseURL: agentServer.URL, Reasoners: []types.ReasonerDefinition{{ID: "reasoner-a"}}}
+	resp, events, store := postExecuteStream(t, agent, `{"input":{"prompt":"hi"}}`)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.True(t, isEventStream(resp.Header().Get("Content-Type")))
+
+	require.Len(t, events, 4, resp.Body.String())
+	require.Equal(t, streamEventStarted, events[0].name)
+	require.Equal(t, streamEvent{name: streamEventChunk, data: `{"token":"hel"}`}, events[1])
+	require.Equal(t, streamEvent{name: streamEventChunk, data: `{"token":"lo"}`}, events[2])
+	require.Equal(t, streamEventResult, events[3].name)
+
+	var final ExecuteResponse
+	require.NoError(t, json.Unmarshal([]byte(events[3].data), &final))
+	require.Equal(t, string(types.ExecutionStatusSucceeded), final.Status)
+	require.Equal(t, map[string]interface{}{"text": "hello"}, final.Result)
+
+	record, err := store.GetExecutionRecord(context.Background(), final.ExecutionID)
+	require.NoError(t, err)
+	require.Equal(t, types.ExecutionStatusSucceeded, types.ExecutionStatus(record.Status))
+	require.JSONEq(t, `{"text":"hello"}`, string(record.ResultPayload))
+}
+
+func TestExecuteStreamHandler_NonStreamingAgentYieldsSingleResult(t *testing.T) {
+	agentServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer agentServer.Close()
+
+	agent := &types.AgentNode{ID: "node-1", BaseURL: agentServer.URL, Reasoners: []types.ReasonerDefinition{{ID: "reasoner-a"}}}
+	resp, events, _ := postExecuteStream(t, agent, `{"input":{"n":1}}`)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Len(t, events, 2, resp.Body.String())
+	require.Equal(t, streamEventResult, events[1].name)
+	require.Contains(t, events[1].data, `"result":{"ok":true}`)
+}
+
+func TestExecuteStreamHandler_AgentErrorAfterChunksIsNotRetried(t *testing.T) {
+	var calls int32
+	agentServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("event: chunk\ndata: \"partial\"\n\n"))
+		_, _ = w.Write([]byte("event: error\ndata: {\"error\":\"model overloaded\"}\n\n"))
+	}))
+	defer agentServer.Close()
+
+	agent := &types.AgentNode{ID: "node-1", BaseURL: agentServer.URL, Reasoners: []types.ReasonerDefinition{{ID: "reasoner-a"}}}
+	resp, events, store := postExecuteStream(t, agent, `{"input":{"n":1},"retry":{"max_attempts":3,"initial_backoff_ms":1,"retryable_status_codes":[500]}}`)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	require.Len(t, events, 3, resp.Body.String())
+	require.Equal(t, streamEventChunk, events[1].name)
+	require.Equal(t, streamEventError, events[2].name)
+	require.Contains(t, events[2].data, "model overloaded")
+
+	executionID := resp.Header().Get("X-Execution-ID")
+	record, err := store.GetExecutionRecord(context.Background(), executionID)
+	require.NoError(t, err)
+	require.Equal(t, types.ExecutionStatusFailed, types.ExecutionStatus(record.Status))
+}
@@ -121,6 +121,9 @@ func (m *MockStorageProvider) QuerySessions(ctx context.Context, filters types.S
 	return nil, nil
 }
 func (m *MockStorageProvider) SetMemory(ctx context.Context, memory *types.Memory) error { return nil }
+func (m *MockStorageProvider) UpdateMemory(ctx context.Context, scope, scopeID, key string, update func(*types.Memory) (*types.Memory, error)) (*types.Memory, error) {
+	return nil, nil
+}
 func (m *MockStorageProvider) GetMemory(ctx context.Context, scope, scopeID, key string) (*types.Memory, error) {
 	return nil, nil
 }
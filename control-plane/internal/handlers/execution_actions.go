@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxClaimWaitSeconds bounds how long a single claim request may long-poll.
+	maxClaimWaitSeconds = 60
+	// claimPollInterval re-checks storage while long-polling so actions enqueued by
+	// another control plane instance are still picked up.
+	claimPollInterval = time.Second
+)
+
+// actionSignals wakes long-polling claim requests when work is enqueued for their node.
+var actionSignals = newActionNotifier()
+
+type actionNotifier struct {
+	mu      sync.Mutex
+	waiters map[string]map[chan struct{}]struct{}
+}
+
+func newActionNotifier() *actionNotifier {
+	return &actionNotifier{waiters: make(map[string]map[chan struct{}]struct{})}
+}
+
+// subscribe registers interest in new actions for nodeID. The returned func must be
+// called to release the subscription.
+func (n *actionNotifier) subscribe(nodeID string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	n.mu.Lock()
+	if n.waiters[nodeID] == nil {
+		n.waiters[nodeID] = make(map[chan struct{}]struct{})
+	}
+	n.waiters[nodeID][ch] = struct{}{}
+	n.mu.Unlock()
+
+	return ch, func() {
+		n.mu.Lock()
+		delete(n.waiters[nodeID], ch)
+		if len(n.waiters[nodeID]) == 0 {
+			delete(n.waiters, nodeID)
+		}
+		n.mu.Unlock()
+	}
+}
+
+func (n *actionNotifier) notify(nodeID string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for ch := range n.waiters[nodeID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// isPollModeAgent reports whether the agent pulls work through /actions/claim.
+func isPollModeAgent(agent *types.AgentNode) bool {
+	if agent == nil {
+		return false
+	}
+	if agent.DeploymentType == types.DeploymentTypePoll {
+		return true
+	}
+	for _, protocol := range agent.CommunicationConfig.Protocols {
+		if strings.EqualFold(strings.TrimSpace(protocol), types.DeploymentTypePoll) {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueueAction persists the execution as a pending action for a poll-mode agent and
+// wakes any claim request waiting on that node.
+func (c *executionController) enqueueAction(ctx context.Context, plan *preparedExecution) error {
+	action := &types.ExecutionAction{
+		ActionID:          uuid.NewString(),
+		ExecutionID:       plan.exec.ExecutionID,
+		RunID:             plan.exec.RunID,
+		ParentExecutionID: plan.exec.ParentExecutionID,
+		SessionID:         plan.exec.SessionID,
+		ActorID:           plan.exec.ActorID,
+		NodeID:            plan.agent.ID,
+		Target:            plan.target.TargetName,
+		TargetType:        plan.targetType,
+		Input:             json.RawMessage(plan.requestBody),
+	}
+	if err := c.store.EnqueueExecutionAction(ctx, action); err != nil {
+		return fmt.Errorf("enqueue action: %w", err)
+	}
+
+	logger.Logger.Info().
+		Str("execution_id", plan.exec.ExecutionID).
+		Str("action_id", action.ActionID).
+		Str("agent", plan.agent.ID).
+		Msg("execution queued for poll-mode agent")
+
+	actionSignals.notify(plan.agent.ID)
+	return nil
+}
+
+// markActionClaimed moves the execution behind a freshly claimed action from queued to running.
+// It reports false when the execution was cancelled while queued; the action is settled so it
+// is not offered again and must not be handed to the agent.
+func (c *executionController) markActionClaimed(ctx context.Context, action *types.ExecutionAction) bool {
+	transitioned := false
+	cancelled := false
+	updated, err := c.store.UpdateExecutionRecord(ctx, action.ExecutionID, func(current *types.Execution) (*types.Execution, error) {
+		if current == nil {
+			return nil, fmt.Errorf("execution %s not found", action.ExecutionID)
+		}
+		if current.Status == types.ExecutionStatusCancelled {
+			cancelled = true
+			return nil, nil
+		}
+		if current.Status != types.ExecutionStatusQueued {
+			return nil, nil
+		}
+		current.Status = types.ExecutionStatusRunning
+		transitioned = true
+		return current, nil
+	})
+	if err != nil {
+		logger.Logger.Warn().
+			Err(err).
+			Str("execution_id", action.ExecutionID).
+			Str("action_id", action.ActionID).
+			Msg("failed to mark claimed execution as running")
+		return true
+	}
+	if cancelled {
+		if _, err := c.store.CompleteExecutionAction(ctx, action.ActionID); err != nil {
+			logger.Logger.Warn().
+				Err(err).
+				Str("action_id", action.ActionID).
+				Msg("failed to settle action for cancelled execution")
+		}
+		return false
+	}
+	if transitioned && updated != nil {
+		c.markWorkflowExecutionRunning(ctx, action.ExecutionID)
+		c.publishExecutionEvent(updated, types.ExecutionStatusRunning, map[string]interface{}{
+			"action_id": action.ActionID,
+			"attempt":   action.AttemptCount,
+		})
+	}
+	return true
+}
+
+// actionAck carries the fields of an agent acknowledgement that settle an action.
+type actionAck struct {
+	Status     string
+	DurationMS *int
+	Result     json.RawMessage
+	Error      string
+}
+
+// completeActionFromAck settles the execution behind an action using the agent's
+// acknowledgement. Non-terminal statuses only extend the action lease, unless the
+// execution has been cancelled, in which case the action is settled and true is
+// returned so the agent can stop working on it.
+func (c *executionController) completeActionFromAck(ctx context.Context, action *types.ExecutionAction, ack actionAck, leaseTTL time.Duration) (bool, error) {
+	exec, err := c.store.GetExecutionRecord(ctx, action.ExecutionID)
+	if err != nil {
+		return false, fmt.Errorf("load execution: %w", err)
+	}
+	if exec == nil {
+		return false, fmt.Errorf("execution %s not found", action.ExecutionID)
+	}
+	cancelled := exec.Status == types.ExecutionStatusCancelled
+
+	status := types.NormalizeExecutionStatus(ack.Status)
+	if !types.IsTerminalExecutionStatus(status) && !cancelled {
+		return false, c.store.ExtendExecutionActionLease(ctx, action.ActionID, leaseTTL)
+	}
+
+	if !types.IsTerminalExecutionStatus(exec.Status) {
+		agent, err := c.store.GetAgent(ctx, action.NodeID)
+		if err != nil {
+			logger.Logger.Debug().Err(err).Str("node_id", action.NodeID).Msg("failed to load agent for action completion")
+		}
+		plan := &preparedExecution{
+			exec:  exec,
+			agent: agent,
+			target: &parsedTarget{
+				NodeID:     action.NodeID,
+				TargetName: action.Target,
+				TargetType: action.TargetType,
+			},
+			targetType:        action.TargetType,
+			webhookRegistered: exec.WebhookRegistered,
+		}
+
+		var elapsed time.Duration
+		if ack.DurationMS != nil {
+			elapsed = time.Duration(*ack.DurationMS) * time.Millisecond
+		} else if action.ClaimedAt != nil {
+			elapsed = time.Since(*action.ClaimedAt)
+		} else {
+			elapsed = time.Since(exec.StartedAt)
+		}
+
+		var result []byte
+		if len(ack.Result) > 0 && string(ack.Result) != "null" {
+			result = ack.Result
+		}
+
+		if status == types.ExecutionStatusSucceeded {
+			err = c.completeExecution(ctx, plan, result, elapsed)
+		} else {
+			errMsg := strings.TrimSpace(ack.Error)
+			if errMsg == "" {
+				errMsg = fmt.Sprintf("agent reported status '%s'", status)
+			}
+			err = c.failExecution(ctx, plan, errors.New(errMsg), elapsed, result)
+		}
+		if err != nil {
+			return false, fmt.Errorf("complete execution: %w", err)
+		}
+	}
+	settleAsyncExecution(action.ExecutionID)
+
+	if _, err := c.store.CompleteExecutionAction(ctx, action.ActionID); err != nil {
+		return false, err
+	}
+	return cancelled, nil
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/services"
+	"github.com/Agent-Field/agentfield/control-plane/internal/storage"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type claimResponse struct {
+	Items         []types.ExecutionAction `json:"items"`
+	LeaseSeconds  int                     `json:"lease_seconds"`
+	NextPollAfter int                     `json:"next_poll_after"`
+}
+
+func newPollModeRouter(t *testing.T) (*gin.Engine, storage.StorageProvider) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	provider, ctx := setupTestStorage(t)
+	require.NoError(t, provider.RegisterAgent(ctx, &types.AgentNode{
+		ID:             "nat-node",
+		DeploymentType: types.DeploymentTypePoll,
+		Reasoners:      []types.ReasonerDefinition{{ID: "greet"}},
+	}))
+
+	payloads := services.NewFilePayloadStore(t.TempDir())
+	router := gin.New()
+	router.POST("/api/v1/execute/:target", ExecuteHandler(provider, payloads, nil, 5*time.Second))
+	router.POST("/api/v1/execute/async/:target", ExecuteAsyncHandler(provider, payloads, nil, 5*time.Second))
+	router.POST("/api/v1/actions/claim", ClaimActionsHandler(provider, nil, time.Minute))
+	router.POST("/api/v1/nodes/:node_id/actions/ack", NodeActionAckHandler(provider, nil, payloads, nil, time.Minute))
+	return router, provider
+}
+
+func claimOnce(t *testing.T, router *gin.Engine, body string) claimResponse {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/actions/claim", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var decoded claimResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &decoded))
+	return decoded
+}
+
+func ackAction(t *testing.T, router *gin.Engine, nodeID, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/nodes/"+nodeID+"/actions/ack", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestClaimActions_EmptyQueueLongPollsUntilDeadline(t *testing.T) {
+	router, _ := newPollModeRouter(t)
+
+	start := time.Now()
+	resp := claimOnce(t, router, `{"node_id":"nat-node","wait_seconds":1}`)
+	require.Empty(t, resp.Items)
+	require.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+	require.Equal(t, 0, resp.NextPollAfter)
+	require.Equal(t, 60, resp.LeaseSeconds)
+}
+
+func TestPollModeAsyncExecution_ClaimAndAck(t *testing.T) {
+	router, provider := newPollModeRouter(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/execute/async/nat-node.greet", strings.NewReader(`{"input":{"name":"ada"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+
+	var accepted AsyncExecuteResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &accepted))
+
+	claimed := claimOnce(t, router, `{"node_id":"nat-node","max_items":5,"wait_seconds":5}`)
+	require.Len(t, claimed.Items, 1)
+	action := claimed.Items[0]
+	require.Equal(t, accepted.ExecutionID, action.ExecutionID)
+	require.Equal(t, "greet", action.Target)
+	require.Equal(t, "reasoner", action.TargetType)
+	require.JSONEq(t, `{"name":"ada"}`, string(action.Input))
+	require.Equal(t, 0, claimed.NextPollAfter)
+
+	exec, err := provider.GetExecutionRecord(req.Context(), accepted.ExecutionID)
+	require.NoError(t, err)
+	require.Equal(t, types.ExecutionStatusRunning, exec.Status)
+
+	missing := ackAction(t, router, "nat-node", `{"action_id":"missing","status":"succeeded"}`)
+	require.Equal(t, http.StatusNotFound, missing.Code)
+
+	ack := ackAction(t, router, "nat-node", `{"action_id":"`+action.ActionID+`","status":"succeeded","duration_ms":42,"result":{"greeting":"hi ada"}}`)
+	require.Equal(t, http.StatusOK, ack.Code, ack.Body.String())
+
+	exec, err = provider.GetExecutionRecord(req.Context(), accepted.ExecutionID)
+	require.NoError(t, err)
+	require.Equal(t, types.ExecutionStatusSucceeded, exec.Status)
+	require.JSONEq(t, `{"greeting":"hi ada"}`, string(exec.ResultPayload))
+	require.NotNil(t, exec.DurationMS)
+	require.EqualValues(t, 42, *exec.DurationMS)
+
+	// Duplicate acknowledgements are accepted without reapplying the result.
+	dup := ackAction(t, router, "nat-node", `{"action_id":"`+action.ActionID+`","status":"failed","error_message":"late"}`)
+	require.Equal(t, http.StatusOK, dup.Code)
+	exec, err = provider.GetExecutionRecord(req.Context(), accepted.ExecutionID)
+	require.NoError(t, err)
+	require.Equal(t, types.ExecutionStatusSucceeded, exec.Status)
+}
+
+func TestPollModeSyncExecution_WaitsForAck(t *testing.T) {
+	router, _ := newPollModeRouter(t)
+
+	done := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/execute/nat-node.greet", strings.NewReader(`{"input":{"name":"grace"}}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		done <- resp
+	}()
+
+	claimed := claimOnce(t, router, `{"node_id":"nat-node","wait_seconds":5}`)
+	require.Len(t, claimed.Items, 1)
+
+	ack := ackAction(t, router, "nat-node", `{"action_id":"`+claimed.Items[0].ActionID+`","status":"failed","error_message":"boom"}`)
+	require.Equal(t, http.StatusOK, ack.Code, ack.Body.String())
+
+	select {
+	case resp := <-done:
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		var payload ExecuteResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &payload))
+		require.Equal(t, types.ExecutionStatusFailed, payload.Status)
+		require.NotNil(t, payload.ErrorMessage)
+		require.Equal(t, "boom", *payload.ErrorMessage)
+	case <-time.After(5 * time.Second):
+		t.Fatal("sync execution did not complete after ack")
+	}
+}
+
+func TestPollModeCancellation_QueuedAndLeasedActions(t *testing.T) {
+	router, provider := newPollModeRouter(t)
+	router.POST("/api/v1/executions/:execution_id/cancel", CancelExecutionHandler(provider, nil, nil, 5*time.Second))
+
+	enqueue := func() AsyncExecuteResponse {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/execute/async/nat-node.greet", strings.NewReader(`{"input":{"name":"ada"}}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+		var accepted AsyncExecuteResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &accepted))
+		return accepted
+	}
+	cancel := func(executionID string) {
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/api/v1/executions/"+executionID+"/cancel", nil))
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	}
+
+	// Cancelled before a worker claims it: the action is never handed out.
+	queued := enqueue()
+	cancel(queued.ExecutionID)
+	require.Empty(t, claimOnce(t, router, `{"node_id":"nat-node","max_items":5,"wait_seconds":1}`).Items)
+
+	// Cancelled while leased: the next lease renewal tells the worker to stop.
+	leased := enqueue()
+	claimed := claimOnce(t, router, `{"node_id":"nat-node","max_items":5,"wait_seconds":5}`)
+	require.Len(t, claimed.Items, 1)
+	cancel(leased.ExecutionID)
+
+	renew := ackAction(t, router, "nat-node", `{"action_id":"`+claimed.Items[0].ActionID+`","status":"running"}`)
+	require.Equal(t, http.StatusOK, renew.Code, renew.Body.String())
+	var renewed map[string]any
+	require.NoError(t, json.Unmarshal(renew.Body.Bytes(), &renewed))
+	require.Equal(t, true, renewed["cancelled"])
+
+	exec, err := provider.GetExecutionRecord(context.Background(), leased.ExecutionID)
+	require.NoError(t, err)
+	require.Equal(t, types.ExecutionStatusCancelled, exec.Status)
+}
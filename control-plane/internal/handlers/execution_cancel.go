@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+	"github.com/Agent-Field/agentfield/control-plane/internal/services"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// agentCancelTimeout bounds the best-effort request telling an agent to stop work.
+const agentCancelTimeout = 5 * time.Second
+
+// CancelExecutionRequest is the optional body accepted by the cancel endpoint.
+type CancelExecutionRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// CancelExecutionResponse lists every execution that was moved to cancelled.
+type CancelExecutionResponse struct {
+	ExecutionID           string   `json:"execution_id"`
+	Status                string   `json:"status"`
+	CancelledExecutionIDs []string `json:"cancelled_execution_ids"`
+}
+
+// CancelExecutionHandler cancels an execution together with its non-terminal descendants.
+func CancelExecutionHandler(store ExecutionStore, payloads services.PayloadStore, webhooks services.WebhookDispatcher, timeout time.Duration) gin.HandlerFunc {
+	controller := newExecutionController(store, payloads, webhooks, timeout)
+	return controller.handleCancel
+}
+
+func (c *executionController) handleCancel(ctx *gin.Context) {
+	reqCtx := ctx.Request.Context()
+	executionID := ctx.Param("execution_id")
+	if executionID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "execution_id is required"})
+		return
+	}
+
+	var req CancelExecutionRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid request body: %v", err)})
+			return
+		}
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		reason = "execution cancelled"
+	}
+
+	exec, err := c.store.GetExecutionRecord(reqCtx, executionID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to load execution: %v", err)})
+		return
+	}
+	if exec == nil || !executionVisible(reqCtx, c.store, exec) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "execution not found"})
+		return
+	}
+	if types.IsTerminalExecutionStatus(exec.Status) {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":  fmt.Sprintf("execution already %s", exec.Status),
+			"status": exec.Status,
+		})
+		return
+	}
+
+	cancelled, err := c.cancelExecutionTree(reqCtx, exec, reason)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to cancel execution: %v", err)})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, CancelExecutionResponse{
+		ExecutionID:           executionID,
+		Status:                string(types.ExecutionStatusCancelled),
+		CancelledExecutionIDs: cancelled,
+	})
+}
+
+// cancelExecutionTree cancels root and walks ParentExecutionID links breadth-first so
+// child calls made on behalf of the root are stopped as well. Descendants that already
+// finished are left untouched.
+func (c *executionController) cancelExecutionTree(ctx context.Context, root *types.Execution, reason string) ([]string, error) {
+	cancelled := make([]string, 0, 1)
+	visited := map[string]struct{}{root.ExecutionID: {}}
+	queue := []*types.Execution{root}
+
+	for len(queue) > 0 {
+		exec := queue[0]
+		queue = queue[1:]
+
+		ok, err := c.cancelExecution(ctx, exec.ExecutionID, reason)
+		if err != nil {
+			return cancelled, err
+		}
+		if ok {
+			cancelled = append(cancelled, exec.ExecutionID)
+		}
+
+		parentID := exec.ExecutionID
+		children, err := c.store.QueryExecutionRecords(ctx, types.ExecutionFilter{ParentExecutionID: &parentID})
+		if err != nil {
+			return cancelled, fmt.Errorf("query child executions: %w", err)
+		}
+		for _, child := range children {
+			if child == nil {
+				continue
+			}
+			if _, seen := visited[child.ExecutionID]; seen {
+				continue
+			}
+			visited[child.ExecutionID] = struct{}{}
+			queue = append(queue, child)
+		}
+	}
+
+	return cancelled, nil
+}
+
+// cancelExecution marks a single execution cancelled and tells its agent to stop. It
+// returns false when the execution had already reached a terminal state.
+func (c *executionController) cancelExecution(ctx context.Context, executionID, reason string) (bool, error) {
+	transitioned := false
+	updated, err := c.store.UpdateExecutionRecord(ctx, executionID, func(current *types.Execution) (*types.Execution, error) {
+		if current == nil {
+			return nil, fmt.Errorf("execution %s not found", executionID)
+		}
+		if types.IsTerminalExecutionStatus(current.Status) {
+			return nil, nil
+		}
+		now := time.Now().UTC()
+		current.Status = types.ExecutionStatusCancelled
+		current.ErrorMessage = &reason
+		current.CompletedAt = pointerTime(now)
+		if !current.StartedAt.IsZero() {
+			current.DurationMS = pointerInt64(now.Sub(current.StartedAt).Milliseconds())
+		}
+		transitioned = true
+		return current, nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("update execution %s: %w", executionID, err)
+	}
+	if !transitioned || updated == nil {
+		return false, nil
+	}
+	settleAsyncExecution(executionID)
+
+	var elapsed time.Duration
+	if updated.DurationMS != nil {
+		elapsed = time.Duration(*updated.DurationMS) * time.Millisecond
+	}
+	c.updateWorkflowExecutionFinalState(ctx, executionID, types.ExecutionStatusCancelled, nil, elapsed, &reason)
+	if updated.WebhookRegistered {
+		c.triggerWebhook(executionID)
+	}
+	c.publishExecutionEvent(updated, string(types.ExecutionStatusCancelled), map[string]interface{}{
+		"error": reason,
+	})
+
+	logger.Logger.Info().
+		Str("execution_id", executionID).
+		Str("agent", updated.AgentNodeID).
+		Msg("execution cancelled")
+
+	c.notifyAgentCancellation(ctx, updated)
+	return true, nil
+}
+
+// notifyAgentCancellation asks the agent running exec to abandon it. Poll-mode agents
+// learn about the cancellation on their next claim or lease renewal instead, and
+// serverless agents cannot be interrupted, so both are skipped. The execution's
+// replica is not recorded, so every live replica is asked and the others answer 404.
+func (c *executionController) notifyAgentCancellation(ctx context.Context, exec *types.Execution) {
+	agent, err := c.store.GetAgent(ctx, exec.AgentNodeID)
+	if err != nil || agent == nil {
+		logger.Logger.Debug().Err(err).Str("execution_id", exec.ExecutionID).Msg("skipping agent cancel notification: agent not found")
+		return
+	}
+	if isPollModeAgent(agent) || agent.DeploymentType == "serverless" {
+		return
+	}
+	for _, endpoint := range currentInstanceBalancer().endpoints(ctx, c.store, agent) {
+		base := strings.TrimSuffix(endpoint.baseURL, "/")
+		if base == "" {
+			continue
+		}
+		cancelURL := fmt.Sprintf("%s/executions/%s/cancel", base, url.PathEscape(exec.ExecutionID))
+
+		go func(instanceID string) {
+			notifyCtx, cancel := context.WithTimeout(context.Background(), agentCancelTimeout)
+			defer cancel()
+			if err := c.postAgentCancel(notifyCtx, cancelURL); err != nil {
+				logger.Logger.Warn().
+					Err(err).
+					Str("execution_id", exec.ExecutionID).
+					Str("agent", agent.ID).
+					Str("instance_id", instanceID).
+					Msg("failed to notify agent of cancellation")
+			}
+		}(endpoint.instanceID)
+	}
+}
+
+func (c *executionController) postAgentCancel(ctx context.Context, cancelURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cancelURL, nil)
+	if err != nil {
+		return fmt.Errorf("create cancel request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// 404 means the agent has already finished or never saw the execution.
+	if resp.StatusCode >= http.StatusBadRequest && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("agent returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/services"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func seedExecution(t *testing.T, store *testExecutionStorage, id string, parent *string, status string) {
+	t.Helper()
+	now := time.Now().UTC()
+	require.NoError(t, store.CreateExecutionRecord(context.Background(), &types.Execution{
+		ExecutionID:       id,
+		RunID:             "run-1",
+		ParentExecutionID: parent,
+		AgentNodeID:       "node-1",
+		ReasonerID:        "reasoner-a",
+		Status:            status,
+		StartedAt:         now.Add(-time.Second),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}))
+}
+
+func TestCancelExecutionHandler_CascadesToDescendants(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var (
+		mu       sync.Mutex
+		notified []string
+	)
+	agentServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		notified = append(notified, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer agentServer.Close()
+
+	agent := &types.AgentNode{
+		ID:        "node-1",
+		BaseURL:   agentServer.URL,
+		Reasoners: []types.ReasonerDefinition{{ID: "reasoner-a"}},
+	}
+	store := newTestExecutionStorage(agent)
+
+	seedExecution(t, store, "root", nil, types.ExecutionStatusRunning)
+	seedExecution(t, store, "child", pointerString("root"), types.ExecutionStatusRunning)
+	seedExecution(t, store, "grandchild", pointerString("child"), types.ExecutionStatusQueued)
+	seedExecution(t, store, "finished", pointerString("root"), types.ExecutionStatusSucceeded)
+	seedExecution(t, store, "unrelated", nil, types.ExecutionStatusRunning)
+
+	router := gin.New()
+	router.POST("/api/v1/executions/:execution_id/cancel", CancelExecutionHandler(store, services.NewFilePayloadStore(t.TempDir()), nil, 90*time.Second))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/executions/root/cancel", strings.NewReader(`{"reason":"user aborted"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var payload CancelExecutionResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &payload))
+	require.Equal(t, types.ExecutionStatusCancelled, payload.Status)
+	require.Equal(t, []string{"root", "child", "grandchild"}, payload.CancelledExecutionIDs)
+
+	for _, id := range []string{"root", "child", "grandchild"} {
+		exec, err := store.GetExecutionRecord(context.Background(), id)
+		require.NoError(t, err)
+		require.Equal(t, types.ExecutionStatusCancelled, exec.Status, id)
+		require.NotNil(t, exec.CompletedAt)
+		require.NotNil(t, exec.ErrorMessage)
+		require.Equal(t, "user aborted", *exec.ErrorMessage)
+	}
+	for id, status := range map[string]string{"finished": types.ExecutionStatusSucceeded, "unrelated": types.ExecutionStatusRunning} {
+		exec, err := store.GetExecutionRecord(context.Background(), id)
+		require.NoError(t, err)
+		require.Equal(t, status, exec.Status, id)
+	}
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(notified) == 3
+	}, 2*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	require.ElementsMatch(t, []string{
+		"POST /executions/root/cancel",
+		"POST /executions/child/cancel",
+		"POST /executions/grandchild/cancel",
+	}, notified)
+	mu.Unlock()
+
+	// A second cancel conflicts because the execution is already terminal.
+	again := httptest.NewRecorder()
+	router.ServeHTTP(again, httptest.NewRequest(http.MethodPost, "/api/v1/executions/root/cancel", nil))
+	require.Equal(t, http.StatusConflict, again.Code)
+
+	missing := httptest.NewRecorder()
+	router.ServeHTTP(missing, httptest.NewRequest(http.MethodPost, "/api/v1/executions/nope/cancel", nil))
+	require.Equal(t, http.StatusNotFound, missing.Code)
+}
+
+func TestUpdateExecutionStatusHandler_KeepsCancelledState(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	agent := &types.AgentNode{ID: "node-1", BaseURL: "http://agent.example"}
+	store := newTestExecutionStorage(agent)
+	payloads := services.NewFilePayloadStore(t.TempDir())
+	seedExecution(t, store, "exec-1", nil, types.ExecutionStatusCancelled)
+
+	router := gin.New()
+	router.POST("/api/v1/executions/:execution_id/status", UpdateExecutionStatusHandler(store, payloads, nil, 90*time.Second))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/executions/exec-1/status", strings.NewReader(`{"status":"succeeded","result":{"late":true}}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	exec, err := store.GetExecutionRecord(context.Background(), "exec-1")
+	require.NoError(t, err)
+	require.Equal(t, types.ExecutionStatusCancelled, exec.Status)
+	require.Nil(t, exec.ResultPayload)
+}
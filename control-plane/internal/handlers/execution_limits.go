@@ -0,0 +1,375 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
+	"github.com/Agent-Field/agentfield/control-plane/internal/config"
+	"github.com/Agent-Field/agentfield/control-plane/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// limiterSweepThreshold bounds the number of idle per-caller scopes kept in memory.
+const limiterSweepThreshold = 1024
+
+const (
+	limitScopeNode     = "node"
+	limitScopeReasoner = "reasoner"
+	limitScopeCaller   = "caller"
+)
+
+var (
+	executionAdmissionMu sync.RWMutex
+	executionAdmission   = newAdmissionController(config.ExecutionLimitsConfig{})
+)
+
+// ConfigureExecutionLimits installs the admission limits applied by the execute
+// handlers and the async execution queue. In-flight counts are reset.
+func ConfigureExecutionLimits(cfg config.ExecutionLimitsConfig) {
+	limiter := newAdmissionController(cfg)
+	executionAdmissionMu.Lock()
+	executionAdmission = limiter
+	executionAdmissionMu.Unlock()
+}
+
+func currentAdmissionController() *admissionController {
+	executionAdmissionMu.RLock()
+	defer executionAdmissionMu.RUnlock()
+	return executionAdmission
+}
+
+// admissionKeys identifies the scopes an execution counts against.
+type admissionKeys struct {
+	nodeID   string
+	reasoner string
+	caller   string
+}
+
+// admissionMode selects which checks an admission applies. Sync executions take a
+// rate token and hold an in-flight slot; async submissions only take a rate token
+// and their slot is taken when the queue dispatches them.
+type admissionMode struct {
+	rate        bool
+	concurrency bool
+}
+
+// errAdmissionRejected is returned when an execution exceeds a limit and cannot wait.
+type errAdmissionRejected struct {
+	scope      string
+	reason     string
+	retryAfter time.Duration
+}
+
+func (e *errAdmissionRejected) Error() string {
+	return fmt.Sprintf("%s %s limit exceeded", e.scope, e.reason)
+}
+
+// admissionController enforces concurrency and token bucket rate limits per
+// node, reasoner and caller.
+type admissionController struct {
+	cfg     config.ExecutionLimitsConfig
+	enabled bool
+
+	mu      sync.Mutex
+	scopes  map[string]*limitScope
+	release chan struct{} // closed and replaced whenever a slot frees up
+	held    asyncHolds    // slots of asynchronously accepted executions
+}
+
+type limitScope struct {
+	limit    config.ExecutionLimit
+	inFlight int
+	tokens   float64
+	refillAt time.Time
+}
+
+func newAdmissionController(cfg config.ExecutionLimitsConfig) *admissionController {
+	enabled := limitConfigured(cfg.Node) || limitConfigured(cfg.Reasoner) || limitConfigured(cfg.Caller)
+	for _, override := range cfg.Overrides {
+		enabled = enabled || limitConfigured(override.ExecutionLimit)
+	}
+	return &admissionController{
+		cfg:     cfg,
+		enabled: enabled,
+		scopes:  make(map[string]*limitScope),
+		release: make(chan struct{}),
+	}
+}
+
+func limitConfigured(limit config.ExecutionLimit) bool {
+	return limit.MaxInFlight > 0 || limit.RequestsPerSecond > 0
+}
+
+// limitFor resolves the limit for one scope, preferring a matching override.
+func (l *admissionController) limitFor(scope string, keys admissionKeys) config.ExecutionLimit {
+	for _, override := range l.cfg.Overrides {
+		switch scope {
+		case limitScopeNode:
+			if override.Node == keys.nodeID && override.Reasoner == "" && override.Caller == "" {
+				return override.ExecutionLimit
+			}
+		case limitScopeReasoner:
+			if override.Node == keys.nodeID && override.Reasoner == keys.reasoner && override.Reasoner != "" {
+				return override.ExecutionLimit
+			}
+		case limitScopeCaller:
+			if override.Caller != "" && override.Caller == keys.caller {
+				return override.ExecutionLimit
+			}
+		}
+	}
+	switch scope {
+	case limitScopeNode:
+		return l.cfg.Node
+	case limitScopeReasoner:
+		return l.cfg.Reasoner
+	default:
+		return l.cfg.Caller
+	}
+}
+
+// admit blocks until the execution fits every applicable limit or wait elapses. The
+// returned release func must be called once the execution settles.
+func (l *admissionController) admit(ctx context.Context, keys admissionKeys, mode admissionMode, wait time.Duration) (func(), error) {
+	if l == nil || !l.enabled {
+		return func() {}, nil
+	}
+
+	deadline := time.Now().Add(wait)
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		scopes := l.resolveScopes(keys, now)
+		rejection := l.check(scopes, mode)
+		if rejection == nil {
+			l.take(scopes, mode)
+			l.mu.Unlock()
+			if mode.concurrency {
+				services.RecordWorkerAcquire(keys.nodeID)
+				return l.releaseFunc(scopes, keys.nodeID), nil
+			}
+			return func() {}, nil
+		}
+		released := l.release
+		l.mu.Unlock()
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 || (rejection.reason == "rate" && rejection.retryAfter > remaining) {
+			services.RecordGatewayBackpressure(rejection.scope + "_" + rejection.reason + "_limited")
+			return nil, rejection
+		}
+
+		pause := remaining
+		if rejection.reason == "rate" {
+			pause = rejection.retryAfter
+		}
+		timer := time.NewTimer(pause)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-released:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// holdUntilSettled keeps the slots an admission took after the agent accepted the
+// execution asynchronously, until settle is called for it or asyncCallHoldLimit passes.
+func (l *admissionController) holdUntilSettled(executionID string, release func()) {
+	if l == nil || !l.enabled {
+		release()
+		return
+	}
+	l.held.hold(executionID, release)
+}
+
+// settle releases the slots held for an execution, if any.
+func (l *admissionController) settle(executionID string) {
+	if l == nil {
+		return
+	}
+	l.held.settle(executionID)
+}
+
+type resolvedScope struct {
+	name  string
+	state *limitScope
+}
+
+func (l *admissionController) resolveScopes(keys admissionKeys, now time.Time) []resolvedScope {
+	if len(l.scopes) > limiterSweepThreshold {
+		l.sweep(now)
+	}
+	resolved := make([]resolvedScope, 0, 3)
+	for _, scope := range []struct {
+		name string
+		key  string
+	}{
+		{limitScopeNode, keys.nodeID},
+		{limitScopeReasoner, keys.nodeID + "." + keys.reasoner},
+		{limitScopeCaller, keys.caller},
+	} {
+		if scope.key == "" {
+			continue
+		}
+		limit := l.limitFor(scope.name, keys)
+		if !limitConfigured(limit) {
+			continue
+		}
+		mapKey := scope.name + ":" + scope.key
+		state, ok := l.scopes[mapKey]
+		if !ok {
+			state = &limitScope{limit: limit, tokens: float64(burstFor(limit)), refillAt: now}
+			l.scopes[mapKey] = state
+		}
+		state.refill(now)
+		resolved = append(resolved, resolvedScope{name: scope.name, state: state})
+	}
+	return resolved
+}
+
+func (l *admissionController) check(scopes []resolvedScope, mode admissionMode) *errAdmissionRejected {
+	var rejection *errAdmissionRejected
+	for _, scope := range scopes {
+		limit := scope.state.limit
+		if mode.concurrency && limit.MaxInFlight > 0 && scope.state.inFlight >= limit.MaxInFlight {
+			// In-flight slots free up when an execution settles; suggest a short retry.
+			return &errAdmissionRejected{scope: scope.name, reason: "concurrency", retryAfter: time.Second}
+		}
+		if mode.rate && limit.RequestsPerSecond > 0 && scope.state.tokens < 1 {
+			wait := time.Duration((1 - scope.state.tokens) / limit.RequestsPerSecond * float64(time.Second))
+			if rejection == nil || wait > rejection.retryAfter {
+				rejection = &errAdmissionRejected{scope: scope.name, reason: "rate", retryAfter: wait}
+			}
+		}
+	}
+	return rejection
+}
+
+func (l *admissionController) take(scopes []resolvedScope, mode admissionMode) {
+	for _, scope := range scopes {
+		if mode.rate && scope.state.limit.RequestsPerSecond > 0 {
+			scope.state.tokens--
+		}
+		if mode.concurrency && scope.state.limit.MaxInFlight > 0 {
+			scope.state.inFlight++
+		}
+	}
+}
+
+func (l *admissionController) releaseFunc(scopes []resolvedScope, nodeID string) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			for _, scope := range scopes {
+				if scope.state.limit.MaxInFlight > 0 && scope.state.inFlight > 0 {
+					scope.state.inFlight--
+				}
+			}
+			close(l.release)
+			l.release = make(chan struct{})
+			l.mu.Unlock()
+			services.RecordWorkerRelease(nodeID)
+		})
+	}
+}
+
+// sweep drops scopes with nothing in flight and a full bucket; they are recreated on
+// demand with identical state.
+func (l *admissionController) sweep(now time.Time) {
+	for key, state := range l.scopes {
+		state.refill(now)
+		if state.inFlight == 0 && state.tokens >= float64(burstFor(state.limit)) {
+			delete(l.scopes, key)
+		}
+	}
+}
+
+func (s *limitScope) refill(now time.Time) {
+	if s.limit.RequestsPerSecond <= 0 {
+		return
+	}
+	elapsed := now.Sub(s.refillAt).Seconds()
+	s.refillAt = now
+	if elapsed <= 0 {
+		return
+	}
+	s.tokens = math.Min(float64(burstFor(s.limit)), s.tokens+elapsed*s.limit.RequestsPerSecond)
+}
+
+func burstFor(limit config.ExecutionLimit) int {
+	if limit.Burst > 0 {
+		return limit.Burst
+	}
+	if burst := int(math.Ceil(limit.RequestsPerSecond)); burst > 0 {
+		return burst
+	}
+	return 1
+}
+
+// admissionKeysFromRequest identifies the target and caller of an execute request.
+// The caller is the ID of the stored API key that authenticated the request. Without
+// one it is the calling agent's DID or node ID when present, else the client IP; those
+// headers are only trusted because no stored key could be held to a limit instead.
+func admissionKeysFromRequest(ctx *gin.Context) (admissionKeys, bool) {
+	target, err := parseTarget(ctx.Param("target"))
+	if err != nil {
+		return admissionKeys{}, false
+	}
+	if principal := auth.PrincipalFrom(ctx.Request.Context()); principal != nil && principal.KeyID != "" {
+		return admissionKeys{nodeID: target.NodeID, reasoner: target.TargetName, caller: principal.KeyID}, true
+	}
+	caller := strings.TrimSpace(ctx.GetHeader("X-Caller-DID"))
+	if caller == "" {
+		caller = strings.TrimSpace(ctx.GetHeader("X-Agent-Node-ID"))
+	}
+	if caller == "" {
+		caller = ctx.ClientIP()
+	}
+	return admissionKeys{nodeID: target.NodeID, reasoner: target.TargetName, caller: caller}, true
+}
+
+// admitRequest applies the configured limits to an execute request. It writes a 429
+// response and returns false when the request is rejected.
+func admitRequest(ctx *gin.Context, mode admissionMode) (func(), bool) {
+	keys, ok := admissionKeysFromRequest(ctx)
+	if !ok {
+		// Invalid targets are reported by request validation.
+		return func() {}, true
+	}
+
+	limiter := currentAdmissionController()
+	release, err := limiter.admit(ctx.Request.Context(), keys, mode, limiter.cfg.QueueTimeout)
+	if err == nil {
+		return release, true
+	}
+
+	var rejected *errAdmissionRejected
+	if errors.As(err, &rejected) {
+		retryAfter := int(math.Ceil(rejected.retryAfter.Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+		ctx.JSON(http.StatusTooManyRequests, gin.H{
+			"error":               rejected.Error(),
+			"scope":               rejected.scope,
+			"retry_after_seconds": retryAfter,
+		})
+		return nil, false
+	}
+	ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": fmt.Sprintf("admission cancelled: %v", err)})
+	return nil, false
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
+	"github.com/Agent-Field/agentfield/control-plane/internal/config"
+	"github.com/Agent-Field/agentfield/control-plane/internal/services"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func configureTestExecutionLimits(t *testing.T, cfg config.ExecutionLimitsConfig) {
+	t.Helper()
+	ConfigureExecutionLimits(cfg)
+	t.Cleanup(func() { ConfigureExecutionLimits(config.ExecutionLimitsConfig{}) })
+}
+
+func TestExecuteHandler_RateLimitReturnsRetryAfter(t *testing.T) {
+	agentServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer agentServer.Close()
+
+	configureTestExecutionLimits(t, config.ExecutionLimitsConfig{
+		Caller: config.ExecutionLimit{RequestsPerSecond: 0.5, Burst: 1},
+	})
+	agent := &types.AgentNode{ID: "node-1", BaseURL: agentServer.URL, Reasoners: []types.ReasonerDefinition{{ID: "reasoner-a"}}}
+	router, _ := newRetryTestRouter(t, agent)
+
+	resp := postExecute(t, router, `{"input":{"foo":"bar"}}`)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	resp = postExecute(t, router, `{"input":{"foo":"bar"}}`)
+	require.Equal(t, http.StatusTooManyRequests, resp.Code, resp.Body.String())
+	require.Equal(t, "2", resp.Header().Get("Retry-After"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Equal(t, limitScopeCaller, body["scope"])
+	require.Equal(t, float64(2), body["retry_after_seconds"])
+}
+
+func TestAdmissionKeysFromRequest_PrefersStoredKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	keysFor := func(principal *auth.Principal, callerDID string) admissionKeys {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/execute/node-1.reasoner-a", nil)
+		c.Request.Header.Set("X-Caller-DID", callerDID)
+		if principal != nil {
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		}
+		c.Params = gin.Params{{Key: "target", Value: "node-1.reasoner-a"}}
+		keys, ok := admissionKeysFromRequest(c)
+		require.True(t, ok)
+		return keys
+	}
+
+	// A stored key is limited as itself whatever DID it claims.
+	stored := &auth.Principal{KeyID: "key-1", Role: types.APIKeyRoleOperator}
+	require.Equal(t, "key-1", keysFor(stored, "did:key:a").caller)
+	require.Equal(t, "key-1", keysFor(stored, "did:key:b").caller)
+
+	require.Equal(t, "did:key:a", keysFor(&auth.Principal{Role: types.APIKeyRoleAdmin}, "did:key:a").caller)
+	require.Equal(t, "did:key:a", keysFor(nil, "did:key:a").caller)
+}
+
+func TestAdmissionController_ConcurrencyWaitsForRelease(t *testing.T) {
+	limiter := newAdmissionController(config.ExecutionLimitsConfig{
+		Node: config.ExecutionLimit{MaxInFlight: 1},
+	})
+	keys := admissionKeys{nodeID: "node-1", reasoner: "r", caller: "c"}
+	mode := admissionMode{concurrency: true}
+
+	release, err := limiter.admit(context.Background(), keys, mode, 0)
+	require.NoError(t, err)
+
+	_, err = limiter.admit(context.Background(), keys, mode, 20*time.Millisecond)
+	var rejected *errAdmissionRejected
+	require.True(t, errors.As(err, &rejected))
+	require.Equal(t, limitScopeNode, rejected.scope)
+	require.Equal(t, "concurrency", rejected.reason)
+
+	// Another node is unaffected.
+	other, err := limiter.admit(context.Background(), admissionKeys{nodeID: "node-2"}, mode, 0)
+	require.NoError(t, err)
+	other()
+
+	time.AfterFunc(30*time.Millisecond, release)
+	second, err := limiter.admit(context.Background(), keys, mode, 2*time.Second)
+	require.NoError(t, err)
+	second()
+	second() // Releasing twice must not free a second slot.
+
+	first, err := limiter.admit(context.Background(), keys, mode, 0)
+	require.NoError(t, err)
+	_, err = limiter.admit(context.Background(), keys, mode, 0)
+	require.Error(t, err)
+	first()
+}
+
+func TestAdmissionController_OverridesReplaceDefaults(t *testing.T) {
+	limiter := newAdmissionController(config.ExecutionLimitsConfig{
+		Reasoner: config.ExecutionLimit{MaxInFlight: 1},
+		Overrides: []config.ExecutionLimitOverride{
+			{Node: "node-1", Reasoner: "bulk", ExecutionLimit: config.ExecutionLimit{MaxInFlight: 3}},
+			{Caller: "did:key:trusted", ExecutionLimit: config.ExecutionLimit{RequestsPerSecond: 100}},
+		},
+	})
+
+	bulk := admissionKeys{nodeID: "node-1", reasoner: "bulk"}
+	require.Equal(t, 3, limiter.limitFor(limitScopeReasoner, bulk).MaxInFlight)
+	require.Equal(t, 1, limiter.limitFor(limitScopeReasoner, admissionKeys{nodeID: "node-2", reasoner: "bulk"}).MaxInFlight)
+	require.Equal(t, float64(100), limiter.limitFor(limitScopeCaller, admissionKeys{caller: "did:key:trusted"}).RequestsPerSecond)
+
+	mode := admissionMode{concurrency: true}
+	for i := 0; i < 3; i++ {
+		_, err := limiter.admit(context.Background(), bulk, mode, 0)
+		require.NoError(t, err)
+	}
+	_, err := limiter.admit(context.Background(), bulk, mode, 0)
+	require.Error(t, err)
+}
+
+func TestDispatchQueued_DefersWhileNodeAtCapacity(t *testing.T) {
+	agentServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer agentServer.Close()
+
+	configureTestExecutionLimits(t, config.ExecutionLimitsConfig{
+		Node: config.ExecutionLimit{MaxInFlight: 1},
+	})
+	agent := &types.AgentNode{ID: "node-1", BaseURL: agentServer.URL, Reasoners: []types.ReasonerDefinition{{ID: "reasoner-a"}}}
+	store := newTestExecutionStorage(agent)
+	controller := newExecutionController(store, services.NewFilePayloadStore(t.TempDir()), nil, 5*time.Second)
+
+	ctx := context.Background()
+	require.NoError(t, store.CreateExecutionRecord(ctx, &types.Execution{
+		ExecutionID: "exec-limited",
+		RunID:       "run-1",
+		AgentNodeID: "node-1",
+		ReasonerID:  "reasoner-a",
+		NodeID:      "node-1",
+		Status:      string(types.ExecutionStatusQueued),
+		StartedAt:   time.Now(),
+	}))
+	item := &types.QueuedExecution{
+		ExecutionID: "exec-limited",
+		NodeID:      "node-1",
+		Target:      "reasoner-a",
+		TargetType:  "reasoner",
+		Payload:     json.RawMessage(`{"foo":"bar"}`),
+	}
+
+	release, err := currentAdmissionController().admit(ctx, admissionKeys{nodeID: "node-1"}, admissionMode{concurrency: true}, 0)
+	require.NoError(t, err)
+	require.ErrorIs(t, controller.dispatchQueued(ctx, item), errExecutionDeferred)
+
+	record, err := store.GetExecutionRecord(ctx, "exec-limited")
+	require.NoError(t, err)
+	require.Equal(t, types.ExecutionStatusQueued, types.ExecutionStatus(record.Status))
+
+	release()
+	require.NoError(t, controller.dispatchQueued(ctx, item))
+	record, err = store.GetExecutionRecord(ctx, "exec-limited")
+	require.NoError(t, err)
+	require.Equal(t, types.ExecutionStatusSucceeded, types.ExecutionStatus(record.Status))
+}
+
+func TestDispatchQueued_HoldsCallerSlotUntilAsyncExecutionSettles(t *testing.T) {
+	agentServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer agentServer.Close()
+
+	configureTestExecutionLimits(t, config.ExecutionLimitsConfig{
+		Caller: config.ExecutionLimit{MaxInFlight: 1},
+	})
+	agent := &types.AgentNode{ID: "node-1", BaseURL: agentServer.URL, Reasoners: []types.ReasonerDefinition{{ID: "reasoner-a"}}}
+	store := newTestExecutionStorage(agent)
+	controller := newExecutionController(store, services.NewFilePayloadStore(t.TempDir()), nil, 5*time.Second)
+
+	ctx := context.Background()
+	items := make([]*types.QueuedExecution, 0, 2)
+	for _, id := range []string{"exec-1", "exec-2"} {
+		require.NoError(t, store.CreateExecutionRecord(ctx, &types.Execution{
+			ExecutionID: id,
+			RunID:       "run-1",
+			AgentNodeID: "node-1",
+			ReasonerID:  "reasoner-a",
+			NodeID:      "node-1",
+			Status:      string(types.ExecutionStatusQueued),
+			StartedAt:   time.Now(),
+		}))
+		items = append(items, &types.QueuedExecution{
+			ExecutionID: id,
+			NodeID:      "node-1",
+			Target:      "reasoner-a",
+			TargetType:  "reasoner",
+			Payload:     json.RawMessage(`{}`),
+			Caller:      "key-1",
+		})
+	}
+
+	require.NoError(t, controller.dispatchQueued(ctx, items[0]))
+	require.ErrorIs(t, controller.dispatchQueued(ctx, items[1]), errExecutionDeferred, "the accepted execution still holds the caller's slot")
+
+	router := gin.New()
+	router.PUT("/api/v1/executions/:execution_id/status", UpdateExecutionStatusHandler(store, services.NewFilePayloadStore(t.TempDir()), nil, 90*time.Second))
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/executions/exec-1/status", strings.NewReader(`{"status":"succeeded"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	require.NoError(t, controller.dispatchQueued(ctx, items[1]))
+	record, err := store.GetExecutionRecord(ctx, "exec-2")
+	require.NoError(t, err)
+	require.Equal(t, types.ExecutionStatusRunning, types.ExecutionStatus(record.Status))
+}
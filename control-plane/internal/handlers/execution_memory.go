@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+)
+
+// A reasoner's MemoryConfig is applied when its executions are dispatched. The keys it
+// lists in AutoInject are looked up like a hierarchical memory read, from the workflow
+// scope out to the global one, and handed to the reasoner in the reserved
+// injectedMemoryField of its input. Access-controlled values are never injected, as
+// the policy of the reasoner reading them cannot be checked here.
+//
+// With CacheResults set, successful results are kept for resultCacheTTL, keyed by a
+// hash of the reasoner input including the injected memory, and an execution with the
+// same input is settled with the kept result instead of calling the agent. Results live
+// in a store of their own, scoped to the team that owns the agent, rather than in user
+// memory.
+
+const (
+	// injectedMemoryField holds the auto-injected memory in the agent payload, keyed
+	// by memory key. Keys found in no scope are left out, and the field is omitted
+	// when none was found.
+	injectedMemoryField = "__memory"
+	resultCacheTTL      = time.Hour
+)
+
+// memoryInjectionScopes is the lookup order of auto-injected keys.
+var memoryInjectionScopes = []string{"workflow", "session", "actor", "global"}
+
+// reasonerMemoryConfig returns the memory configuration of the targeted reasoner, or
+// nil when the target is not a reasoner.
+func reasonerMemoryConfig(agent *types.AgentNode, target *parsedTarget) *types.MemoryConfig {
+	if agent == nil || target == nil || target.TargetType != "reasoner" {
+		return nil
+	}
+	for i := range agent.Reasoners {
+		if agent.Reasoners[i].ID == target.TargetName {
+			return &agent.Reasoners[i].MemoryConfig
+		}
+	}
+	return nil
+}
+
+// injectMemory adds the memory keys config declares to payload.
+func (c *executionController) injectMemory(ctx context.Context, config *types.MemoryConfig, runID string, headers executionHeaders, payload map[string]interface{}) {
+	if config == nil || len(config.AutoInject) == 0 {
+		return
+	}
+
+	scopeIDs := map[string]string{
+		"workflow": runID,
+		"global":   "global",
+	}
+	if headers.sessionID != nil {
+		scopeIDs["session"] = *headers.sessionID
+	}
+	if headers.actorID != nil {
+		scopeIDs["actor"] = *headers.actorID
+	}
+
+	injected := make(map[string]json.RawMessage, len(config.AutoInject))
+	for _, key := range config.AutoInject {
+		for _, scope := range memoryInjectionScopes {
+			scopeID := scopeIDs[scope]
+			if scopeID == "" {
+				continue
+			}
+			memory, err := c.store.GetMemory(ctx, scope, teamMemoryScopeID(ctx, scopeID), key)
+			if err != nil || memory == nil {
+				continue
+			}
+			if !memoryAccessRestricted(memory.AccessLevel, memory.Metadata.AccessControl) {
+				injected[key] = memory.Data
+			}
+			break
+		}
+	}
+	if len(injected) > 0 {
+		payload[injectedMemoryField] = injected
+	}
+}
+
+// resultCacheEntry locates the cached result of a plan's reasoner input. It reports
+// false when the reasoner does not cache results or the input is unknown, as for
+// executions settled from a poll-mode acknowledgement.
+func resultCacheEntry(plan *preparedExecution) (reasoner, inputHash string, ok bool) {
+	config := reasonerMemoryConfig(plan.agent, plan.target)
+	if config == nil || !config.CacheResults || len(plan.requestBody) == 0 {
+		return "", "", false
+	}
+
+	input := plan.requestBody
+	if plan.agent.DeploymentType == "serverless" {
+		// The serverless envelope carries the execution IDs, which differ every time.
+		var envelope struct {
+			Input json.RawMessage `json:"input"`
+		}
+		if err := json.Unmarshal(plan.requestBody, &envelope); err != nil || len(envelope.Input) == 0 {
+			return "", "", false
+		}
+		input = envelope.Input
+	}
+	sum := sha256.Sum256(input)
+	return plan.agent.ID + "." + plan.target.TargetName, hex.EncodeToString(sum[:]), true
+}
+
+// cachedResult returns the cached result for the plan's input, or nil. Access to the
+// agent is checked per team before dispatch, so the cache of the agent's team is shared
+// by everyone who may call the reasoner.
+func (c *executionController) cachedResult(ctx context.Context, plan *preparedExecution) []byte {
+	reasoner, inputHash, ok := resultCacheEntry(plan)
+	if !ok {
+		return nil
+	}
+	result, err := c.store.GetCachedResult(ctx, plan.agent.TeamID, reasoner, inputHash)
+	if err != nil || result == nil {
+		return nil
+	}
+	plan.cacheHit = true
+	logger.Logger.Debug().
+		Str("execution_id", plan.exec.ExecutionID).
+		Str("reasoner", plan.target.TargetName).
+		Msg("serving reasoner result from cache")
+	return result
+}
+
+// cacheResult keeps a successful result for later executions with the same input.
+func (c *executionController) cacheResult(ctx context.Context, plan *preparedExecution, result []byte) {
+	if plan.cacheHit || !json.Valid(result) {
+		return
+	}
+	reasoner, inputHash, ok := resultCacheEntry(plan)
+	if !ok {
+		return
+	}
+	if err := c.store.StoreCachedResult(ctx, plan.agent.TeamID, reasoner, inputHash, result, resultCacheTTL); err != nil {
+		logger.Logger.Warn().Err(err).Str("execution_id", plan.exec.ExecutionID).Msg("failed to cache reasoner result")
+	}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/services"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteHandler_InjectsDeclaredMemory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var received map[string]interface{}
+	agentServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &received))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer agentServer.Close()
+
+	agent := &types.AgentNode{
+		ID:      "node-1",
+		BaseURL: agentServer.URL,
+		Reasoners: []types.ReasonerDefinition{{
+			ID:           "reasoner-a",
+			MemoryConfig: types.MemoryConfig{AutoInject: []string{"profile", "locale", "secret", "missing"}},
+		}},
+	}
+	store := newTestExecutionStorage(agent)
+	ctx := context.Background()
+	require.NoError(t, store.SetMemory(ctx, &types.Memory{Scope: "global", ScopeID: "global", Key: "profile", Data: json.RawMessage(`{"name":"global"}`)}))
+	require.NoError(t, store.SetMemory(ctx, &types.Memory{Scope: "session", ScopeID: "sess-1", Key: "profile", Data: json.RawMessage(`{"name":"session"}`)}))
+	require.NoError(t, store.SetMemory(ctx, &types.Memory{Scope: "actor", ScopeID: "user-1", Key: "locale", Data: json.RawMessage(`"de"`)}))
+	require.NoError(t, store.SetMemory(ctx, &types.Memory{Scope: "global", ScopeID: "global", Key: "secret", Data: json.RawMessage(`"x"`), AccessLevel: types.MemoryAccessPrivate}))
+
+	router := gin.New()
+	router.POST("/api/v1/execute/:target", ExecuteHandler(store, services.NewFilePayloadStore(t.TempDir()), nil, 90*time.Second))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/execute/node-1.reasoner-a", strings.NewReader(`{"input":{"foo":"bar"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Session-ID", "sess-1")
+	req.Header.Set("X-Actor-ID", "user-1")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	require.Equal(t, "bar", received["foo"])
+	require.Equal(t, map[string]interface{}{
+		"profile": map[string]interface{}{"name": "session"},
+		"locale":  "de",
+	}, received[injectedMemoryField], "the narrowest scope wins and protected or missing keys are left out")
+
+	var envelope ExecuteResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &envelope))
+	record, err := store.GetExecutionRecord(ctx, envelope.ExecutionID)
+	require.NoError(t, err)
+	require.NotContains(t, string(record.InputPayload), injectedMemoryField, "injected memory is not stored with the execution")
+}
+
+func TestExecuteHandler_CachesReasonerResults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var calls int32
+	agentServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"call":` + string(rune('0'+n)) + `}`))
+	}))
+	defer agentServer.Close()
+
+	agent := &types.AgentNode{
+		ID:      "node-1",
+		TeamID:  "team-a",
+		BaseURL: agentServer.URL,
+		Reasoners: []types.ReasonerDefinition{{
+			ID:           "reasoner-a",
+			MemoryConfig: types.MemoryConfig{CacheResults: true},
+		}},
+	}
+	store := newTestExecutionStorage(agent)
+	router := gin.New()
+	router.POST("/api/v1/execute/:target", ExecuteHandler(store, services.NewFilePayloadStore(t.TempDir()), nil, 90*time.Second))
+
+	execute := func(body string) ExecuteResponse {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/execute/node-1.reasoner-a", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+		var envelope ExecuteResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &envelope))
+		return envelope
+	}
+
+	first := execute(`{"input":{"a":1,"b":2}}`)
+	second := execute(`{"input":{"b":2,"a":1}}`)
+	third := execute(`{"input":{"a":2}}`)
+
+	require.Equal(t, map[string]interface{}{"call": float64(1)}, first.Result)
+	require.Equal(t, map[string]interface{}{"call": float64(1)}, second.Result, "the same input is served from the cache")
+	require.Equal(t, map[string]interface{}{"call": float64(2)}, third.Result)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	record, err := store.GetExecutionRecord(context.Background(), second.ExecutionID)
+	require.NoError(t, err)
+	require.Equal(t, types.ExecutionStatusSucceeded, record.Status)
+	require.JSONEq(t, `{"call":1}`, string(record.ResultPayload))
+
+	require.Empty(t, store.memory, "cached results stay out of user memory")
+	require.Len(t, store.cachedResults, 2)
+	for key := range store.cachedResults {
+		require.True(t, strings.HasPrefix(key, "team-a|node-1.reasoner-a|"))
+	}
+}
+
+func TestExecuteHandler_OmitsInjectedMemoryWhenNoneFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var received map[string]interface{}
+	agentServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer agentServer.Close()
+
+	agent := &types.AgentNode{
+		ID:      "node-1",
+		BaseURL: agentServer.URL,
+		Reasoners: []types.ReasonerDefinition{{
+			ID:           "reasoner-a",
+			MemoryConfig: types.MemoryConfig{AutoInject: []string{"missing"}},
+		}},
+	}
+	store := newTestExecutionStorage(agent)
+	router := gin.New()
+	router.POST("/api/v1/execute/:target", ExecuteHandler(store, services.NewFilePayloadStore(t.TempDir()), nil, 90*time.Second))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/execute/node-1.reasoner-a", strings.NewReader(`{"input":{"foo":"bar"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, map[string]interface{}{"foo": "bar"}, received)
+}
+
+func TestResultCacheEntry_SkipsUnknownInput(t *testing.T) {
+	plan := &preparedExecution{
+		agent: &types.AgentNode{
+			ID:        "node-1",
+			Reasoners: []types.ReasonerDefinition{{ID: "reasoner-a", MemoryConfig: types.MemoryConfig{CacheResults: true}}},
+		},
+		target: &parsedTarget{NodeID: "node-1", TargetName: "reasoner-a", TargetType: "reasoner"},
+	}
+	_, _, ok := resultCacheEntry(plan)
+	require.False(t, ok, "executions settled from an acknowledgement carry no input")
+
+	plan.requestBody = []byte(`{"a":1}`)
+	reasoner, _, ok := resultCacheEntry(plan)
+	require.True(t, ok)
+	require.Equal(t, "node-1.reasoner-a", reasoner)
+}
@@ -0,0 +1,366 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+	"github.com/Agent-Field/agentfield/control-plane/internal/services"
+	"github.com/Agent-Field/agentfield/control-plane/internal/storage"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+)
+
+const (
+	// executionQueueLeaseTTL is how long a worker owns a queued execution without
+	// renewing. RenewLock extends leases by the storage default of 30 seconds.
+	executionQueueLeaseTTL      = 30 * time.Second
+	executionQueueRenewInterval = 10 * time.Second
+	// executionQueuePollInterval re-scans storage so entries enqueued by other
+	// control plane replicas, or orphaned by a crashed worker, are picked up.
+	executionQueuePollInterval = time.Second
+	// executionQueueBatchSize is how many entries of each target a scan picks up.
+	executionQueueBatchSize  = 64
+	executionQueueLockPrefix = "execution-queue:"
+)
+
+// errExecutionDeferred leaves a queued execution in storage until its target has
+// in-flight capacity.
+var errExecutionDeferred = errors.New("execution deferred by in-flight limit")
+
+var (
+	executionQueuesMu sync.Mutex
+	executionQueues   = make(map[ExecutionStore]*executionQueue)
+)
+
+// executionQueue dispatches async executions persisted in storage. Entries stay in
+// storage until their dispatch settles, and a storage lock per entry keeps replicas
+// from dispatching the same execution twice.
+type executionQueue struct {
+	controller *executionController
+	jobs       chan *types.QueuedExecution
+	wake       chan struct{}
+	stop       chan struct{}
+	stopOnce   sync.Once
+	wg         sync.WaitGroup
+
+	mu       sync.Mutex
+	inflight map[string]struct{}
+}
+
+// StartExecutionQueue starts dispatching queued async executions for store. Entries
+// accepted before a restart are resumed immediately. Calling it again for the same
+// store returns without starting a second dispatcher.
+func StartExecutionQueue(store ExecutionStore, payloads services.PayloadStore, webhooks services.WebhookDispatcher, timeout time.Duration) {
+	executionQueueFor(newExecutionController(store, payloads, webhooks, timeout))
+}
+
+// StopExecutionQueue stops the dispatcher for store and waits for in-flight dispatches
+// to settle. Queued entries remain in storage for the next start.
+func StopExecutionQueue(store ExecutionStore) {
+	executionQueuesMu.Lock()
+	queue, ok := executionQueues[store]
+	delete(executionQueues, store)
+	executionQueuesMu.Unlock()
+	if ok {
+		queue.shutdown()
+	}
+}
+
+// executionQueueFor returns the dispatcher for the controller's store, starting one
+// on first use.
+func executionQueueFor(c *executionController) *executionQueue {
+	executionQueuesMu.Lock()
+	defer executionQueuesMu.Unlock()
+	if queue, ok := executionQueues[c.store]; ok {
+		return queue
+	}
+
+	workers := resolveIntFromEnv("AGENTFIELD_EXEC_ASYNC_WORKERS", runtime.NumCPU())
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	queue := &executionQueue{
+		controller: c,
+		jobs:       make(chan *types.QueuedExecution),
+		wake:       make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+		inflight:   make(map[string]struct{}),
+	}
+	for i := 0; i < workers; i++ {
+		queue.wg.Add(1)
+		go queue.work()
+	}
+	queue.wg.Add(1)
+	go queue.scan()
+	executionQueues[c.store] = queue
+
+	logger.Logger.Info().
+		Int("workers", workers).
+		Msg("durable async execution queue started")
+	return queue
+}
+
+// enqueueExecution persists the prepared execution for dispatch by the queue workers.
+func (c *executionController) enqueueExecution(ctx context.Context, plan *preparedExecution) error {
+	item := &types.QueuedExecution{
+		ExecutionID: plan.exec.ExecutionID,
+		NodeID:      plan.agent.ID,
+		Target:      plan.target.TargetName,
+		TargetType:  plan.targetType,
+		Payload:     plan.requestBody,
+		RetryPolicy: plan.retryPolicy,
+		Caller:      plan.caller,
+		CreatedAt:   plan.exec.CreatedAt,
+	}
+	if err := c.store.EnqueueExecution(ctx, item); err != nil {
+		return err
+	}
+	executionQueueFor(c).notify()
+	return nil
+}
+
+func (q *executionQueue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *executionQueue) shutdown() {
+	q.stopOnce.Do(func() { close(q.stop) })
+	q.wg.Wait()
+}
+
+func (q *executionQueue) scan() {
+	defer q.wg.Done()
+	ticker := time.NewTicker(executionQueuePollInterval)
+	defer ticker.Stop()
+
+	for {
+		items, err := q.controller.store.ListQueuedExecutions(context.Background(), executionQueueBatchSize)
+		if err != nil {
+			logger.Logger.Warn().Err(err).Msg("failed to list queued executions")
+		}
+		for _, item := range items {
+			if !q.markInflight(item.ExecutionID) {
+				continue
+			}
+			select {
+			case q.jobs <- item:
+			case <-q.stop:
+				return
+			}
+		}
+
+		select {
+		case <-q.stop:
+			return
+		case <-q.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (q *executionQueue) work() {
+	defer q.wg.Done()
+	for {
+		select {
+		case <-q.stop:
+			return
+		case item := <-q.jobs:
+			q.process(item)
+			q.clearInflight(item.ExecutionID)
+		}
+	}
+}
+
+func (q *executionQueue) markInflight(executionID string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.inflight[executionID]; ok {
+		return false
+	}
+	q.inflight[executionID] = struct{}{}
+	return true
+}
+
+func (q *executionQueue) clearInflight(executionID string) {
+	q.mu.Lock()
+	delete(q.inflight, executionID)
+	q.mu.Unlock()
+}
+
+// process leases a queued execution and dispatches it while renewing the lease. An
+// entry whose lease is held elsewhere is skipped; it is retried on a later scan if
+// the holder disappears before settling it.
+func (q *executionQueue) process(item *types.QueuedExecution) {
+	ctx := context.Background()
+	store := q.controller.store
+
+	lock, err := store.AcquireLock(ctx, executionQueueLockPrefix+item.ExecutionID, executionQueueLeaseTTL)
+	if err != nil || lock == nil {
+		return
+	}
+	defer func() {
+		if err := store.ReleaseLock(ctx, lock.LockID); err != nil {
+			logger.Logger.Debug().Err(err).Str("execution_id", item.ExecutionID).Msg("failed to release execution queue lock")
+		}
+	}()
+
+	stopRenew := make(chan struct{})
+	renewDone := make(chan struct{})
+	go func() {
+		defer close(renewDone)
+		ticker := time.NewTicker(executionQueueRenewInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopRenew:
+				return
+			case <-ticker.C:
+				if _, err := store.RenewLock(ctx, lock.LockID); err != nil {
+					logger.Logger.Warn().Err(err).Str("execution_id", item.ExecutionID).Msg("failed to renew execution queue lease")
+				}
+			}
+		}
+	}()
+
+	err = q.controller.dispatchQueued(ctx, item)
+	close(stopRenew)
+	<-renewDone
+	if errors.Is(err, errExecutionDeferred) {
+		// The target is at its in-flight limit; a later scan retries the entry.
+		return
+	}
+	if err != nil {
+		// Leave the entry queued; it is retried once the lease is released.
+		logger.Logger.Error().Err(err).Str("execution_id", item.ExecutionID).Msg("failed to dispatch queued execution")
+		return
+	}
+
+	if err := store.DeleteQueuedExecution(ctx, item.ExecutionID); err != nil {
+		logger.Logger.Error().Err(err).Str("execution_id", item.ExecutionID).Msg("failed to remove dispatched execution from queue")
+	}
+}
+
+// dispatchQueued calls the agent for a queued execution and settles the outcome. A
+// non-nil error means the entry should be retried.
+func (c *executionController) dispatchQueued(ctx context.Context, item *types.QueuedExecution) error {
+	exec, err := c.store.GetExecutionRecord(ctx, item.ExecutionID)
+	if err != nil {
+		return fmt.Errorf("load execution: %w", err)
+	}
+	if exec == nil || types.IsTerminalExecutionStatus(exec.Status) {
+		// Cancelled or otherwise settled while queued.
+		return nil
+	}
+
+	agent, err := c.store.GetAgent(ctx, item.NodeID)
+	if err != nil && !errors.Is(err, storage.ErrAgentNotFound) {
+		return fmt.Errorf("load agent: %w", err)
+	}
+
+	plan := &preparedExecution{
+		exec:        exec,
+		requestBody: item.Payload,
+		agent:       agent,
+		target: &parsedTarget{
+			NodeID:     item.NodeID,
+			TargetName: item.Target,
+			TargetType: item.TargetType,
+		},
+		targetType:        item.TargetType,
+		webhookRegistered: exec.WebhookRegistered,
+		retryPolicy:       item.RetryPolicy,
+	}
+	if agent == nil {
+		return c.failExecution(ctx, plan, fmt.Errorf("agent '%s' not found", item.NodeID), 0, nil)
+	}
+
+	admission := currentAdmissionController()
+	keys := admissionKeys{nodeID: item.NodeID, reasoner: item.Target, caller: item.Caller}
+	release, err := admission.admit(ctx, keys, admissionMode{concurrency: true}, 0)
+	if err != nil {
+		return errExecutionDeferred
+	}
+
+	if !isPollModeAgent(agent) {
+		if err := c.markExecutionRunning(ctx, exec.ExecutionID); err != nil {
+			release()
+			return err
+		}
+	}
+
+	resultBody, elapsed, asyncAccepted, callErr := c.callAgentWithRetry(ctx, plan)
+	if callErr == nil && asyncAccepted {
+		// The slots stay taken until the agent reports a final status.
+		admission.holdUntilSettled(exec.ExecutionID, release)
+		if current, err := c.store.GetExecutionRecord(ctx, exec.ExecutionID); err == nil && current != nil && types.IsTerminalExecutionStatus(current.Status) {
+			admission.settle(exec.ExecutionID)
+		}
+		logger.Logger.Info().
+			Str("execution_id", exec.ExecutionID).
+			Msg("agent accepted execution for async processing")
+		return nil
+	}
+
+	defer release()
+
+	job := completionJob{
+		controller: c,
+		plan:       plan,
+		result:     resultBody,
+		elapsed:    elapsed,
+		callErr:    callErr,
+		done:       make(chan error, 1),
+	}
+	if err := enqueueCompletion(job); err != nil {
+		return processCompletionJob(job)
+	}
+	return <-job.done
+}
+
+// markExecutionRunning moves a queued execution, and its workflow execution, to running.
+func (c *executionController) markExecutionRunning(ctx context.Context, executionID string) error {
+	_, err := c.store.UpdateExecutionRecord(ctx, executionID, func(current *types.Execution) (*types.Execution, error) {
+		if current == nil {
+			return nil, fmt.Errorf("execution %s not found", executionID)
+		}
+		if current.Status != types.ExecutionStatusQueued {
+			return nil, nil
+		}
+		current.Status = types.ExecutionStatusRunning
+		return current, nil
+	})
+	if err != nil {
+		return fmt.Errorf("mark execution running: %w", err)
+	}
+	c.markWorkflowExecutionRunning(ctx, executionID)
+	return nil
+}
+
+// markWorkflowExecutionRunning mirrors a queued to running transition onto the workflow
+// execution so later terminal updates pass the workflow state machine.
+func (c *executionController) markWorkflowExecutionRunning(ctx context.Context, executionID string) {
+	err := c.store.UpdateWorkflowExecution(ctx, executionID, func(current *types.WorkflowExecution) (*types.WorkflowExecution, error) {
+		if current == nil {
+			return nil, errors.New("workflow execution not found")
+		}
+		if current.Status == string(types.ExecutionStatusQueued) {
+			current.Status = string(types.ExecutionStatusRunning)
+			current.UpdatedAt = time.Now().UTC()
+		}
+		return current, nil
+	})
+	if err != nil {
+		logger.Logger.Warn().
+			Err(err).
+			Str("execution_id", executionID).
+			Msg("failed to mark workflow execution running")
+	}
+}
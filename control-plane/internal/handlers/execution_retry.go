@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+)
+
+// agentStatusError is returned by callAgent when the agent answers with an HTTP error
+// status, so retry policies can match on the code.
+type agentStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *agentStatusError) Error() string {
+	return fmt.Sprintf("agent error (%d): %s", e.StatusCode, e.Body)
+}
+
+// resolveRetryPolicy picks the request policy when present and falls back to the one the
+// target reasoner declared at registration.
+func resolveRetryPolicy(requested *types.RetryPolicy, agent *types.AgentNode, target *parsedTarget) *types.RetryPolicy {
+	if requested != nil {
+		return requested
+	}
+	if agent == nil || target == nil || target.TargetType != "reasoner" {
+		return nil
+	}
+	for _, reasoner := range agent.Reasoners {
+		if reasoner.ID == target.TargetName {
+			return reasoner.RetryPolicy
+		}
+	}
+	return nil
+}
+
+// callAgentWithRetry calls the agent and retries failures the execution's retry policy
+// marks as transient. Every retry is recorded on the execution before backing off.
+// Poll-mode agents are not called directly, so their dispatch is never retried here, and
+// a stream that failed after relaying output to the caller is not replayed. A result
+// the reasoner cached for the same input is returned without calling the agent at all.
+func (c *executionController) callAgentWithRetry(ctx context.Context, plan *preparedExecution) ([]byte, time.Duration, bool, error) {
+	if result := c.cachedResult(ctx, plan); result != nil {
+		return result, 0, false, nil
+	}
+	if plan.retryPolicy == nil || isPollModeAgent(plan.agent) {
+		return c.callAgent(ctx, plan)
+	}
+
+	policy := plan.retryPolicy.WithDefaults()
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		body, _, asyncAccepted, err := c.callAgent(ctx, plan)
+		if err == nil || plan.streamed || attempt >= policy.MaxAttempts || !isRetryableAgentError(policy, err) {
+			return body, time.Since(start), asyncAccepted, err
+		}
+
+		delay := retryBackoff(policy, attempt)
+		if !c.recordRetryAttempt(ctx, plan, attempt, err, delay) {
+			return body, time.Since(start), asyncAccepted, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return body, time.Since(start), asyncAccepted, err
+		case <-timer.C:
+		}
+	}
+}
+
+// recordRetryAttempt stores the retry count on the execution and its workflow execution.
+// It reports false when the execution is no longer running, e.g. it was cancelled
+// between attempts, in which case no further attempt should be made.
+func (c *executionController) recordRetryAttempt(ctx context.Context, plan *preparedExecution, retryCount int, callErr error, delay time.Duration) bool {
+	executionID := plan.exec.ExecutionID
+	stillRunning := false
+	updated, err := c.store.UpdateExecutionRecord(ctx, executionID, func(current *types.Execution) (*types.Execution, error) {
+		if current == nil {
+			return nil, fmt.Errorf("execution %s not found", executionID)
+		}
+		if types.IsTerminalExecutionStatus(current.Status) {
+			return nil, nil
+		}
+		stillRunning = true
+		current.RetryCount = retryCount
+		return current, nil
+	})
+	if err != nil {
+		logger.Logger.Warn().Err(err).Str("execution_id", executionID).Msg("failed to record retry attempt")
+		return false
+	}
+	if !stillRunning {
+		return false
+	}
+
+	if err := c.store.UpdateWorkflowExecution(ctx, executionID, func(current *types.WorkflowExecution) (*types.WorkflowExecution, error) {
+		if current == nil {
+			return nil, errors.New("workflow execution not found")
+		}
+		current.RetryCount = retryCount
+		current.UpdatedAt = time.Now().UTC()
+		return current, nil
+	}); err != nil {
+		logger.Logger.Debug().Err(err).Str("execution_id", executionID).Msg("failed to record retry attempt on workflow execution")
+	}
+
+	c.publishExecutionEvent(updated, "retrying", map[string]interface{}{
+		"retry_count":   retryCount,
+		"error":         callErr.Error(),
+		"next_retry_ms": delay.Milliseconds(),
+	})
+
+	logger.Logger.Warn().
+		Err(callErr).
+		Str("execution_id", executionID).
+		Str("agent", plan.target.NodeID).
+		Int("retry", retryCount).
+		Dur("backoff", delay).
+		Msg("retrying agent call")
+	return true
+}
+
+// isRetryableAgentError reports whether policy allows retrying the agent call failure.
+func isRetryableAgentError(policy types.RetryPolicy, err error) bool {
+	var statusErr *agentStatusError
+	if errors.As(err, &statusErr) {
+		return slices.Contains(policy.RetryableStatusCodes, statusErr.StatusCode)
+	}
+	class := classifyAgentCallError(err)
+	return class != "" && slices.Contains(policy.RetryableErrors, class)
+}
+
+// classifyAgentCallError maps a transport failure to a RetryPolicy error class. It
+// returns "" for failures that are not worth retrying, such as a cancelled request.
+func classifyAgentCallError(err error) string {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return ""
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return types.RetryErrorTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return types.RetryErrorTimeout
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) {
+		return types.RetryErrorConnection
+	}
+	return ""
+}
+
+// retryBackoff returns the delay before the retry following the given attempt.
+func retryBackoff(policy types.RetryPolicy, attempt int) time.Duration {
+	delay := float64(policy.InitialBackoffMS) * math.Pow(policy.BackoffMultiplier, float64(attempt-1))
+	if delay > float64(policy.MaxBackoffMS) {
+		delay = float64(policy.MaxBackoffMS)
+	}
+	return time.Duration(delay) * time.Millisecond
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/services"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func newRetryTestRouter(t *testing.T, agent *types.AgentNode) (*gin.Engine, *testExecutionStorage) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	store := newTestExecutionStorage(agent)
+	router := gin.New()
+	router.POST("/api/v1/execute/:target", ExecuteHandler(store, services.NewFilePayloadStore(t.TempDir()), nil, 5*time.Second))
+	return router, store
+}
+
+func postExecute(t *testing.T, router *gin.Engine, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/execute/node-1.reasoner-a", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestExecuteHandler_RetriesTransientAgentErrors(t *testing.T) {
+	var calls int32
+	agentServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(`cold start`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer agentServer.Close()
+
+	// The reasoner declares the policy at registration; the request does not override it.
+	agent := &types.AgentNode{
+		ID:      "node-1",
+		BaseURL: agentServer.URL,
+		Reasoners: []types.ReasonerDefinition{{
+			ID:          "reasoner-a",
+			RetryPolicy: &types.RetryPolicy{MaxAttempts: 3, InitialBackoffMS: 5},
+		}},
+	}
+	router, store := newRetryTestRouter(t, agent)
+
+	resp := postExecute(t, router, `{"input":{"foo":"bar"}}`)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var envelope ExecuteResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &envelope))
+	require.Equal(t, types.ExecutionStatusSucceeded, envelope.Status)
+	require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	record, err := store.GetExecutionRecord(context.Background(), envelope.ExecutionID)
+	require.NoError(t, err)
+	require.Equal(t, 2, record.RetryCount)
+
+	workflowExec, err := store.GetWorkflowExecution(context.Background(), envelope.ExecutionID)
+	require.NoError(t, err)
+	require.Equal(t, 2, workflowExec.RetryCount)
+}
+
+func TestExecuteHandler_RequestRetryPolicyStopsOnNonRetryableStatus(t *testing.T) {
+	var calls int32
+	agentServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer agentServer.Close()
+
+	agent := &types.AgentNode{
+		ID:        "node-1",
+		BaseURL:   agentServer.URL,
+		Reasoners: []types.ReasonerDefinition{{ID: "reasoner-a"}},
+	}
+	router, store := newRetryTestRouter(t, agent)
+
+	resp := postExecute(t, router, `{"input":{"foo":"bar"},"retry":{"max_attempts":4,"initial_backoff_ms":1,"retryable_status_codes":[503]}}`)
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	records, err := store.QueryExecutionRecords(context.Background(), types.ExecutionFilter{})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, types.ExecutionStatusFailed, records[0].Status)
+	require.Equal(t, 0, records[0].RetryCount)
+}
+
+func TestExecuteHandler_RejectsInvalidRetryPolicy(t *testing.T) {
+	agent := &types.AgentNode{
+		ID:        "node-1",
+		BaseURL:   "http://agent.example",
+		Reasoners: []types.ReasonerDefinition{{ID: "reasoner-a"}},
+	}
+	router, _ := newRetryTestRouter(t, agent)
+
+	resp := postExecute(t, router, `{"input":{"foo":"bar"},"retry":{"max_attempts":3,"retryable_errors":["dns"]}}`)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Contains(t, resp.Body.String(), "invalid retry policy")
+}
+
+func TestIsRetryableAgentError(t *testing.T) {
+	policy := types.RetryPolicy{MaxAttempts: 3}.WithDefaults()
+
+	require.True(t, isRetryableAgentError(policy, &agentStatusError{StatusCode: http.StatusBadGateway}))
+	require.False(t, isRetryableAgentError(policy, &agentStatusError{StatusCode: http.StatusBadRequest}))
+	require.True(t, isRetryableAgentError(policy, context.DeadlineExceeded))
+	require.False(t, isRetryableAgentError(policy, context.Canceled))
+	require.False(t, isRetryableAgentError(policy, errors.New("boom")))
+
+	timeoutsOnly := types.RetryPolicy{MaxAttempts: 3, RetryableErrors: []string{types.RetryErrorTimeout}}.WithDefaults()
+	_, dialErr := http.Get("http://127.0.0.1:1")
+	require.Error(t, dialErr)
+	require.True(t, isRetryableAgentError(policy, dialErr))
+	require.False(t, isRetryableAgentError(timeoutsOnly, dialErr))
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := types.RetryPolicy{MaxAttempts: 5, InitialBackoffMS: 100, MaxBackoffMS: 300, BackoffMultiplier: 2}
+	require.Equal(t, 100*time.Millisecond, retryBackoff(policy, 1))
+	require.Equal(t, 200*time.Millisecond, retryBackoff(policy, 2))
+	require.Equal(t, 300*time.Millisecond, retryBackoff(policy, 3))
+}
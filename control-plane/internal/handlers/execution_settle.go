@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+)
+
+// asyncCallHoldLimit bounds how long an asynchronous call counts as in flight when its
+// execution never reports back. It matches the default stale execution timeout.
+const asyncCallHoldLimit = 30 * time.Minute
+
+// asyncHolds keeps the release funcs of executions an agent accepted asynchronously
+// until the executions settle, or until asyncCallHoldLimit passes.
+type asyncHolds struct {
+	mu   sync.Mutex
+	held map[string]heldCall
+}
+
+// heldCall is an asynchronous call that counts as in flight until its execution settles.
+type heldCall struct {
+	release func()
+	timer   *time.Timer
+}
+
+// hold registers release to run when the execution settles. A second hold for the
+// same execution releases the earlier one.
+func (h *asyncHolds) hold(executionID string, release func()) {
+	timer := time.AfterFunc(asyncCallHoldLimit, func() { h.settle(executionID) })
+	h.mu.Lock()
+	if h.held == nil {
+		h.held = make(map[string]heldCall)
+	}
+	previous, ok := h.held[executionID]
+	h.held[executionID] = heldCall{release: release, timer: timer}
+	h.mu.Unlock()
+	if ok {
+		previous.timer.Stop()
+		previous.release()
+	}
+}
+
+// settle runs the release held for an execution, if any.
+func (h *asyncHolds) settle(executionID string) {
+	h.mu.Lock()
+	held, ok := h.held[executionID]
+	delete(h.held, executionID)
+	h.mu.Unlock()
+	if ok {
+		held.timer.Stop()
+		held.release()
+	}
+}
+
+// settleAsyncExecution frees the instance and admission slots an asynchronously
+// accepted execution holds. It is called once the execution completes, fails or is
+// cancelled.
+func settleAsyncExecution(executionID string) {
+	currentInstanceBalancer().settle(executionID)
+	currentAdmissionController().settle(executionID)
+}
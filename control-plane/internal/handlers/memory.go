@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 // MemoryStorage captures the storage operations required by memory handlers.
 type MemoryStorage interface {
 	SetMemory(ctx context.Context, memory *types.Memory) error
+	UpdateMemory(ctx context.Context, scope, scopeID, key string, update func(*types.Memory) (*types.Memory, error)) (*types.Memory, error)
 	GetMemory(ctx context.Context, scope, scopeID, key string) (*types.Memory, error)
 	DeleteMemory(ctx context.Context, scope, scopeID, key string) error
 	ListMemory(ctx context.Context, scope, scopeID string) ([]*types.Memory, error)
@@ -25,6 +27,9 @@ type MemoryStorage interface {
 	DeleteVector(ctx context.Context, scope, scopeID, key string) error
 	DeleteVectorsByPrefix(ctx context.Context, scope, scopeID, prefix string) (int, error)
 	SimilaritySearch(ctx context.Context, scope, scopeID string, queryEmbedding []float32, topK int, filters map[string]interface{}) ([]*types.VectorSearchResult, error)
+	HybridSearch(ctx context.Context, scope, scopeID string, query types.VectorSearchQuery) ([]*types.VectorSearchResult, error)
+	GetExecutionRecord(ctx context.Context, executionID string) (*types.Execution, error)
+	GetAgent(ctx context.Context, id string) (*types.AgentNode, error)
 }
 
 // SetMemoryRequest defines the structure for setting a memory value.
@@ -32,6 +37,20 @@ type SetMemoryRequest struct {
 	Key   string      `json:"key" binding:"required"`
 	Data  interface{} `json:"data" binding:"required"`
 	Scope *string     `json:"scope,omitempty"`
+	// TTLSeconds expires the value that many seconds after this write. Without it the
+	// memory_retention of the reasoner whose execution writes (X-Execution-ID) applies,
+	// and the value never expires when there is none.
+	TTLSeconds *int64 `json:"ttl_seconds,omitempty"`
+	// IfVersion makes the write conditional on the stored version; 0 means the key must
+	// not exist yet. A mismatch fails with 409.
+	IfVersion *int64 `json:"if_version,omitempty"`
+	// AccessLevel and AccessControl declare who may access the value from now on. A
+	// write declaring neither keeps the key's current policy.
+	AccessLevel   string                       `json:"access_level,omitempty"`
+	AccessControl *types.AccessControlMetadata `json:"access_control,omitempty"`
+	// Encrypt stores the value encrypted at rest, as values in encrypted scopes always
+	// are. A value once encrypted stays encrypted.
+	Encrypt bool `json:"encrypt,omitempty"`
 }
 
 // GetMemoryRequest defines the structure for getting a memory value.
@@ -74,15 +93,38 @@ func SetMemoryHandler(storageProvider MemoryStorage) gin.HandlerFunc {
 			return
 		}
 		logger.Logger.Debug().Msgf("🔍 MEMORY_HANDLER_DEBUG: Request parsed successfully: key=%s", req.Key)
+		if req.TTLSeconds != nil && *req.TTLSeconds <= 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_request",
+				Message: "ttl_seconds must be positive",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+
+		accessLevel, accessControl, err := memoryWritePolicy(c, req.AccessLevel, req.AccessControl)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_request",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		declaresPolicy := req.AccessLevel != "" || req.AccessControl != nil
 
 		scope, scopeID := resolveScope(c, req.Scope)
 		logger.Logger.Debug().Msgf("🔍 MEMORY_HANDLER_DEBUG: Scope resolved: scope=%s, scopeID=%s", scope, scopeID)
 
 		// Get existing memory value for event publishing
 		logger.Logger.Debug().Msg("🔍 MEMORY_HANDLER_DEBUG: Getting existing memory value...")
-		var previousData json.RawMessage
+		var existing *types.Memory
 		if existingMemory, err := storageProvider.GetMemory(ctx, scope, scopeID, req.Key); err == nil {
-			previousData = existingMemory.Data
+			existing = existingMemory
+			if err := authorizeMemoryWrite(c, storageProvider, memoryTarget(existing)); err != nil {
+				writeMemoryAccessDenied(c, err)
+				return
+			}
 		}
 		logger.Logger.Debug().Msg("🔍 MEMORY_HANDLER_DEBUG: Existing memory check completed")
 
@@ -101,29 +143,66 @@ func SetMemoryHandler(storageProvider MemoryStorage) gin.HandlerFunc {
 
 		now := time.Now()
 		memory := &types.Memory{
-			Scope:     scope,
-			ScopeID:   scopeID,
-			Key:       req.Key,
-			Data:      dataJSON,
-			CreatedAt: now,
-			UpdatedAt: now,
+			Scope:       scope,
+			ScopeID:     scopeID,
+			Key:         req.Key,
+			Data:        dataJSON,
+			AccessLevel: accessLevel,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		memory.Metadata.AccessControl = accessControl
+		if req.Encrypt {
+			memory.Metadata.Encryption = &types.EncryptionMetadata{Encrypted: true}
+		}
+		if !declaresPolicy {
+			keepMemoryPolicy(memory, existing)
+		}
+		if req.TTLSeconds != nil {
+			ttl := time.Duration(*req.TTLSeconds) * time.Second
+			memory.TTL = &ttl
+		} else {
+			memory.TTL = memoryRetentionTTL(c, storageProvider)
 		}
 		logger.Logger.Debug().Msg("🔍 MEMORY_HANDLER_DEBUG: Memory object created")
 
 		logger.Logger.Debug().Msg("🔍 MEMORY_HANDLER_DEBUG: Calling storageProvider.SetMemory...")
-		if err := storageProvider.SetMemory(ctx, memory); err != nil {
-			logger.Logger.Debug().Err(err).Msg("🔍 MEMORY_HANDLER_DEBUG: SetMemory failed")
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:   "storage_error",
-				Message: err.Error(),
-				Code:    http.StatusInternalServerError,
+		if req.IfVersion != nil {
+			_, err = storageProvider.UpdateMemory(ctx, scope, scopeID, req.Key, func(current *types.Memory) (*types.Memory, error) {
+				if err := checkMemoryVersion(current, *req.IfVersion); err != nil {
+					return nil, err
+				}
+				existing = current
+				if current != nil {
+					if err := checkMemoryAccess(c, memoryTarget(current)); err != nil {
+						return nil, err
+					}
+					if !declaresPolicy {
+						keepMemoryPolicy(memory, current)
+					}
+				}
+				return memory, nil
 			})
+		} else {
+			err = storageProvider.SetMemory(ctx, memory)
+		}
+		if err != nil {
+			logger.Logger.Debug().Err(err).Msg("🔍 MEMORY_HANDLER_DEBUG: SetMemory failed")
+			var denied *memoryAccessDeniedError
+			if errors.As(err, &denied) {
+				recordMemoryAccess(c, storageProvider, memoryTarget(memory), "access_denied")
+			}
+			writeMemoryWriteError(c, err)
 			return
 		}
 		logger.Logger.Debug().Msg("🔍 MEMORY_HANDLER_DEBUG: SetMemory completed successfully")
 
 		// Publish memory change event
 		logger.Logger.Debug().Msg("🔍 MEMORY_HANDLER_DEBUG: Creating memory change event...")
+		var previousData json.RawMessage
+		if existing != nil {
+			previousData = existing.Data
+		}
 		event := &types.MemoryChangeEvent{
 			Type:         "memory_change",
 			Scope:        scope,
@@ -132,12 +211,9 @@ func SetMemoryHandler(storageProvider MemoryStorage) gin.HandlerFunc {
 			Action:       "set",
 			Data:         dataJSON,
 			PreviousData: previousData,
-			Metadata: types.EventMetadata{
-				AgentID:    c.GetHeader("X-Agent-Node-ID"),
-				ActorID:    c.GetHeader("X-Actor-ID"),
-				WorkflowID: c.GetHeader("X-Workflow-ID"),
-			},
+			Metadata:     memoryEventMetadata(c),
 		}
+		redactProtectedMemoryEvent(event, memory, existing)
 
 		// Store event (don't fail the request if event storage fails)
 		logger.Logger.Debug().Msg("🔍 MEMORY_HANDLER_DEBUG: Storing event...")
@@ -181,6 +257,10 @@ func GetMemoryHandler(storageProvider MemoryStorage) gin.HandlerFunc {
 				})
 				return
 			}
+			if err := authorizeMemoryRead(c, storageProvider, memoryTarget(memory)); err != nil {
+				writeMemoryAccessDenied(c, err)
+				return
+			}
 			c.JSON(http.StatusOK, memory)
 			return
 		}
@@ -190,11 +270,12 @@ func GetMemoryHandler(storageProvider MemoryStorage) gin.HandlerFunc {
 		for _, scope := range scopes {
 			scopeID := getScopeID(c, scope)
 			if scopeID != "" || scope == "global" {
-				if scope == "global" {
-					scopeID = "global" // Use a consistent ID for global scope
-				}
 				memory, err := storageProvider.GetMemory(ctx, scope, scopeID, req.Key)
 				if err == nil {
+					if err := authorizeMemoryRead(c, storageProvider, memoryTarget(memory)); err != nil {
+						writeMemoryAccessDenied(c, err)
+						return
+					}
 					c.JSON(http.StatusOK, memory)
 					return
 				}
@@ -227,8 +308,15 @@ func DeleteMemoryHandler(storageProvider MemoryStorage) gin.HandlerFunc {
 
 		// Get existing memory value for event publishing
 		var previousData json.RawMessage
-		if existingMemory, err := storageProvider.GetMemory(ctx, scope, scopeID, req.Key); err == nil {
-			previousData = existingMemory.Data
+		existing, err := storageProvider.GetMemory(ctx, scope, scopeID, req.Key)
+		if err == nil {
+			if err := authorizeMemoryWrite(c, storageProvider, memoryTarget(existing)); err != nil {
+				writeMemoryAccessDenied(c, err)
+				return
+			}
+			previousData = existing.Data
+		} else {
+			existing = nil
 		}
 
 		if err := storageProvider.DeleteMemory(ctx, scope, scopeID, req.Key); err != nil {
@@ -249,12 +337,9 @@ func DeleteMemoryHandler(storageProvider MemoryStorage) gin.HandlerFunc {
 			Action:       "delete",
 			Data:         nil, // No new data for delete
 			PreviousData: previousData,
-			Metadata: types.EventMetadata{
-				AgentID:    c.GetHeader("X-Agent-Node-ID"),
-				ActorID:    c.GetHeader("X-Actor-ID"),
-				WorkflowID: c.GetHeader("X-Workflow-ID"),
-			},
+			Metadata:     memoryEventMetadata(c),
 		}
+		redactProtectedMemoryEvent(event, existing)
 
 		// Store event (don't fail the request if event storage fails)
 		if err := storageProvider.StoreEvent(ctx, event); err != nil {
@@ -294,45 +379,81 @@ func ListMemoryHandler(storageProvider MemoryStorage) gin.HandlerFunc {
 			return
 		}
 
-		if memories == nil {
-			memories = []*types.Memory{}
+		visible := make([]*types.Memory, 0, len(memories))
+		for _, memory := range memories {
+			if authorizeMemoryRead(c, storageProvider, memoryTarget(memory)) == nil {
+				visible = append(visible, memory)
+			}
 		}
 
-		c.JSON(http.StatusOK, memories)
+		c.JSON(http.StatusOK, visible)
+	}
+}
+
+// memoryRetentionTTL returns the TTL the memory_retention of the writing execution's
+// reasoner gives values written without one, or nil when the request names no known
+// execution or the reasoner keeps its memory.
+func memoryRetentionTTL(c *gin.Context, storageProvider MemoryStorage) *time.Duration {
+	executionID := c.GetHeader("X-Execution-ID")
+	if executionID == "" {
+		return nil
+	}
+	ctx := c.Request.Context()
+	exec, err := storageProvider.GetExecutionRecord(ctx, executionID)
+	if err != nil || exec == nil {
+		return nil
+	}
+	agent, err := storageProvider.GetAgent(ctx, exec.AgentNodeID)
+	if err != nil || agent == nil {
+		return nil
+	}
+	for _, reasoner := range agent.Reasoners {
+		if reasoner.ID != exec.ReasonerID {
+			continue
+		}
+		ttl, err := reasoner.MemoryConfig.RetentionTTL()
+		if err != nil {
+			logger.Logger.Warn().Err(err).Str("agent", agent.ID).Str("reasoner", reasoner.ID).Msg("ignoring memory retention")
+			return nil
+		}
+		return ttl
 	}
+	return nil
 }
 
-// resolveScope determines the memory scope and scope ID to use.
+// resolveScope determines the memory scope and scope ID to use. Scope IDs of callers
+// confined to a team are namespaced by that team.
 func resolveScope(c *gin.Context, explicitScope *string) (string, string) {
 	if explicitScope != nil {
 		return *explicitScope, getScopeID(c, *explicitScope)
 	}
 
+	ctx := c.Request.Context()
 	if id := c.GetHeader("X-Workflow-ID"); id != "" {
-		return "workflow", id
+		return "workflow", teamMemoryScopeID(ctx, id)
 	}
 	if id := c.GetHeader("X-Session-ID"); id != "" {
-		return "session", id
+		return "session", teamMemoryScopeID(ctx, id)
 	}
 	if id := c.GetHeader("X-Actor-ID"); id != "" {
-		return "actor", id
+		return "actor", teamMemoryScopeID(ctx, id)
 	}
 
-	return "global", "global"
+	return "global", teamMemoryScopeID(ctx, "global")
 }
 
 // getScopeID retrieves the appropriate ID for a given scope from headers.
 func getScopeID(c *gin.Context, scope string) string {
+	var scopeID string
 	switch scope {
 	case "workflow":
-		return c.GetHeader("X-Workflow-ID")
+		scopeID = c.GetHeader("X-Workflow-ID")
 	case "session":
-		return c.GetHeader("X-Session-ID")
+		scopeID = c.GetHeader("X-Session-ID")
 	case "actor":
-		return c.GetHeader("X-Actor-ID")
+		scopeID = c.GetHeader("X-Actor-ID")
 	case "global":
-		return "global"
-	default:
-		return ""
+		scopeID = "global"
 	}
+	return teamMemoryScopeID(c.Request.Context(), scopeID)
 }
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Memory access policies are declared on writes with access_level and access_control
+// and enforced on every later read and write of the key:
+//
+//   - private memory is only accessible to the stored API key that wrote it or, for
+//     requests no stored key authenticated, to the agent node named by X-Agent-Node-ID;
+//   - required_roles limits access to API keys with one of the roles and refuses
+//     unauthenticated callers;
+//   - team_restricted limits access to callers confined to the writer's team;
+//   - audit_access records every read as a memory_access event.
+//
+// Admin keys bypass the first two. Denied accesses are always recorded. Change events of
+// protected memory omit its values, which would otherwise be readable from the event
+// history.
+
+// memoryAccessDeniedError rejects an access the memory's policy does not allow.
+type memoryAccessDeniedError struct {
+	reason string
+}
+
+func (e *memoryAccessDeniedError) Error() string {
+	return "access denied: " + e.reason
+}
+
+// memoryAccessTarget is a stored memory value or vector together with its policy.
+type memoryAccessTarget struct {
+	scope   string
+	scopeID string
+	key     string
+	level   string
+	control *types.AccessControlMetadata
+}
+
+func memoryTarget(memory *types.Memory) memoryAccessTarget {
+	return memoryAccessTarget{
+		scope:   memory.Scope,
+		scopeID: memory.ScopeID,
+		key:     memory.Key,
+		level:   memory.AccessLevel,
+		control: memory.Metadata.AccessControl,
+	}
+}
+
+func vectorTarget(record *types.VectorRecord) memoryAccessTarget {
+	return memoryAccessTarget{
+		scope:   record.Scope,
+		scopeID: record.ScopeID,
+		key:     record.Key,
+		level:   record.AccessLevel,
+		control: record.AccessControl,
+	}
+}
+
+func vectorResultTarget(result *types.VectorSearchResult) memoryAccessTarget {
+	return memoryAccessTarget{
+		scope:   result.Scope,
+		scopeID: result.ScopeID,
+		key:     result.Key,
+		level:   result.AccessLevel,
+		control: result.AccessControl,
+	}
+}
+
+// memoryAccessRestricted reports whether a policy restricts access at all.
+func memoryAccessRestricted(level string, control *types.AccessControlMetadata) bool {
+	return level == types.MemoryAccessPrivate || control != nil
+}
+
+// memoryWritePolicy validates the policy a write declares and records the writer as its
+// owner. A write declaring no policy returns an empty one.
+func memoryWritePolicy(c *gin.Context, level string, control *types.AccessControlMetadata) (string, *types.AccessControlMetadata, error) {
+	switch level {
+	case "", types.MemoryAccessPublic, types.MemoryAccessPrivate:
+	default:
+		return "", nil, fmt.Errorf("access_level must be %q or %q", types.MemoryAccessPublic, types.MemoryAccessPrivate)
+	}
+	if control != nil {
+		for _, role := range control.RequiredRoles {
+			if !types.IsValidAPIKeyRole(role) {
+				return "", nil, fmt.Errorf("unknown role %q in required_roles", role)
+			}
+		}
+	}
+	if !memoryAccessRestricted(level, control) {
+		return level, nil, nil
+	}
+
+	policy := &types.AccessControlMetadata{}
+	if control != nil {
+		policy.RequiredRoles = control.RequiredRoles
+		policy.TeamRestricted = control.TeamRestricted
+		policy.AuditAccess = control.AuditAccess
+	}
+	if principal := auth.PrincipalFrom(c.Request.Context()); principal != nil {
+		policy.OwnerKeyID = principal.KeyID
+		policy.OwnerTeamID = principal.TeamID
+	}
+	if policy.OwnerKeyID == "" {
+		policy.OwnerAgentID = c.GetHeader("X-Agent-Node-ID")
+	}
+	if level == types.MemoryAccessPrivate && policy.OwnerAgentID == "" && policy.OwnerKeyID == "" {
+		return "", nil, errors.New("private memory requires an X-Agent-Node-ID header or a stored API key to own it")
+	}
+	return level, policy, nil
+}
+
+// checkMemoryAccess returns an error when the caller may not access target.
+func checkMemoryAccess(c *gin.Context, target memoryAccessTarget) error {
+	if !memoryAccessRestricted(target.level, target.control) {
+		return nil
+	}
+	ctx := c.Request.Context()
+	principal := auth.PrincipalFrom(ctx)
+	admin := principal != nil && principal.Role == types.APIKeyRoleAdmin
+	control := target.control
+	if control == nil {
+		control = &types.AccessControlMetadata{}
+	}
+
+	if control.TeamRestricted && auth.TeamFrom(ctx) != control.OwnerTeamID {
+		return &memoryAccessDeniedError{reason: "memory is restricted to its writer's team"}
+	}
+	if admin {
+		return nil
+	}
+	if len(control.RequiredRoles) > 0 {
+		if principal == nil {
+			return &memoryAccessDeniedError{reason: "memory requires an API key with one of its required roles"}
+		}
+		if !slices.Contains(control.RequiredRoles, principal.Role) {
+			return &memoryAccessDeniedError{reason: fmt.Sprintf("role %q is not allowed", principal.Role)}
+		}
+	}
+	if target.level == types.MemoryAccessPrivate && !ownsPrivateMemory(c, principal, control) {
+		return &memoryAccessDeniedError{reason: "memory is private to its writer"}
+	}
+	return nil
+}
+
+// ownsPrivateMemory reports whether the caller wrote memory with the given policy. A
+// stored API key identifies the caller on its own; the X-Agent-Node-ID header, which any
+// caller can set, only counts for requests no stored key authenticated.
+func ownsPrivateMemory(c *gin.Context, principal *auth.Principal, control *types.AccessControlMetadata) bool {
+	if principal != nil && principal.KeyID != "" {
+		return control.OwnerKeyID != "" && principal.KeyID == control.OwnerKeyID
+	}
+	return control.OwnerAgentID != "" && c.GetHeader("X-Agent-Node-ID") == control.OwnerAgentID
+}
+
+// authorizeMemoryRead checks a read of target, recording the denial or, when the policy
+// asks for it, the access.
+func authorizeMemoryRead(c *gin.Context, storageProvider MemoryStorage, target memoryAccessTarget) error {
+	if err := checkMemoryAccess(c, target); err != nil {
+		recordMemoryAccess(c, storageProvider, target, "access_denied")
+		return err
+	}
+	if target.control != nil && target.control.AuditAccess {
+		recordMemoryAccess(c, storageProvider, target, "access")
+	}
+	return nil
+}
+
+// authorizeMemoryWrite checks a write over target, recording a denial.
+func authorizeMemoryWrite(c *gin.Context, storageProvider MemoryStorage, target memoryAccessTarget) error {
+	if err := checkMemoryAccess(c, target); err != nil {
+		recordMemoryAccess(c, storageProvider, target, "access_denied")
+		return err
+	}
+	return nil
+}
+
+// recordMemoryAccess stores a memory_access event. Access events go to the history only;
+// they are not published to change subscribers.
+func recordMemoryAccess(c *gin.Context, storageProvider MemoryStorage, target memoryAccessTarget, action string) {
+	event := &types.MemoryChangeEvent{
+		Type:     "memory_access",
+		Scope:    target.scope,
+		ScopeID:  target.scopeID,
+		Key:      target.key,
+		Action:   action,
+		Metadata: memoryEventMetadata(c),
+	}
+	if err := storageProvider.StoreEvent(c.Request.Context(), event); err != nil {
+		logger.Logger.Warn().Err(err).Str("action", action).Msg("Warning: Failed to store memory access event")
+	}
+}
+
+// memoryEventMetadata identifies the caller of a memory request in its events.
+func memoryEventMetadata(c *gin.Context) types.EventMetadata {
+	return types.EventMetadata{
+		AgentID:    c.GetHeader("X-Agent-Node-ID"),
+		ActorID:    c.GetHeader("X-Actor-ID"),
+		WorkflowID: c.GetHeader("X-Workflow-ID"),
+	}
+}
+
+// visibleVectorResults drops the search results the caller may not read, recording
+// them as denied, and keeps at most topK of the rest.
+func visibleVectorResults(c *gin.Context, storageProvider MemoryStorage, results []*types.VectorSearchResult, topK int) []*types.VectorSearchResult {
+	visible := make([]*types.VectorSearchResult, 0, len(results))
+	for _, result := range results {
+		if len(visible) == topK {
+			break
+		}
+		if authorizeMemoryRead(c, storageProvider, vectorResultTarget(result)) == nil {
+			visible = append(visible, result)
+		}
+	}
+	return visible
+}
+
+// keepMemoryPolicy gives next the access policy of current, the value it replaces.
+func keepMemoryPolicy(next, current *types.Memory) {
+	if current == nil {
+		return
+	}
+	next.AccessLevel = current.AccessLevel
+	next.Metadata.AccessControl = current.Metadata.AccessControl
+}
+
+// redactProtectedMemoryEvent drops the values from a change event when the memory
+// before or after the change is protected or encrypted, so the event history never
+// holds them in plaintext.
+func redactProtectedMemoryEvent(event *types.MemoryChangeEvent, memories ...*types.Memory) {
+	for _, memory := range memories {
+		if memory != nil && memory.Protected() {
+			event.Data, event.PreviousData = nil, nil
+			return
+		}
+	}
+}
+
+// writeMemoryAccessDenied responds to a denied memory access.
+func writeMemoryAccessDenied(c *gin.Context, err error) {
+	c.JSON(http.StatusForbidden, ErrorResponse{
+		Error:   "forbidden",
+		Message: err.Error(),
+		Code:    http.StatusForbidden,
+	})
+}
+
+// searchVisibleVectors runs search with growing limits until topK results the caller
+// may read are found or the matches run out.
+func searchVisibleVectors(c *gin.Context, storageProvider MemoryStorage, topK int, search func(ctx context.Context, limit int) ([]*types.VectorSearchResult, error)) ([]*types.VectorSearchResult, error) {
+	const maxRounds = 4
+	limit := topK
+	for round := 1; ; round++ {
+		results, err := search(c.Request.Context(), limit)
+		if err != nil {
+			return nil, err
+		}
+		readable := 0
+		for _, result := range results {
+			if checkMemoryAccess(c, vectorResultTarget(result)) == nil {
+				readable++
+			}
+		}
+		if readable >= topK || len(results) < limit || round == maxRounds {
+			return visibleVectorResults(c, storageProvider, results, topK), nil
+		}
+		limit *= 2
+	}
+}
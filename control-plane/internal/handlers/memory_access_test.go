@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryCaller is the identity a test request is sent as.
+type memoryCaller struct {
+	agentID   string
+	principal *auth.Principal
+}
+
+func serveMemoryRequest(handler gin.HandlerFunc, method, body string, caller memoryCaller) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Handle(method, "/memory", func(c *gin.Context) {
+		if caller.principal != nil {
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), caller.principal))
+		}
+	}, handler)
+
+	req := httptest.NewRequest(method, "/memory?scope=session", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Session-ID", "sess-1")
+	if caller.agentID != "" {
+		req.Header.Set("X-Agent-Node-ID", caller.agentID)
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func accessEvents(storage *memoryStorageStub, action string) []*types.MemoryChangeEvent {
+	var events []*types.MemoryChangeEvent
+	for _, event := range storage.events {
+		if event.Type == "memory_access" && event.Action == action {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func TestMemoryAccess_PrivateMemory(t *testing.T) {
+	storage := newMemoryStorageStub()
+	owner := memoryCaller{agentID: "planner"}
+	other := memoryCaller{agentID: "researcher"}
+
+	resp := serveMemoryRequest(SetMemoryHandler(storage), http.MethodPost, `{"key":"plan","data":"secret","scope":"session","access_level":"private"}`, owner)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	stored, err := storage.GetMemory(context.Background(), "session", "sess-1", "plan")
+	require.NoError(t, err)
+	require.Equal(t, types.MemoryAccessPrivate, stored.AccessLevel)
+	require.Equal(t, "planner", stored.Metadata.AccessControl.OwnerAgentID)
+	require.Nil(t, storage.events[0].Data, "change events of private memory carry no values")
+
+	get := `{"key":"plan","scope":"session"}`
+	require.Equal(t, http.StatusOK, serveMemoryRequest(GetMemoryHandler(storage), http.MethodPost, get, owner).Code)
+	resp = serveMemoryRequest(GetMemoryHandler(storage), http.MethodPost, get, other)
+	require.Equal(t, http.StatusForbidden, resp.Code)
+	require.Contains(t, resp.Body.String(), "private to its writer")
+	// Without a scope the lookup stops at the first scope holding the key.
+	require.Equal(t, http.StatusForbidden, serveMemoryRequest(GetMemoryHandler(storage), http.MethodPost, `{"key":"plan"}`, other).Code)
+
+	denied := accessEvents(storage, "access_denied")
+	require.Len(t, denied, 2)
+	require.Equal(t, "researcher", denied[0].Metadata.AgentID)
+	require.Equal(t, "plan", denied[0].Key)
+
+	resp = serveMemoryRequest(ListMemoryHandler(storage), http.MethodGet, "", other)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.JSONEq(t, `[]`, resp.Body.String())
+
+	// Writes by anyone but the owner are denied too; the owner's keep the policy.
+	overwrite := `{"key":"plan","data":"mine","scope":"session"}`
+	require.Equal(t, http.StatusForbidden, serveMemoryRequest(SetMemoryHandler(storage), http.MethodPost, overwrite, other).Code)
+	require.Equal(t, http.StatusForbidden, serveMemoryRequest(DeleteMemoryHandler(storage), http.MethodPost, get, other).Code)
+	require.Equal(t, http.StatusForbidden, serveMemoryRequest(AppendMemoryHandler(storage), http.MethodPost, `{"key":"plan","scope":"session","values":[1]}`, other).Code)
+	require.Equal(t, http.StatusOK, serveMemoryRequest(SetMemoryHandler(storage), http.MethodPost, overwrite, owner).Code)
+	stored, err = storage.GetMemory(context.Background(), "session", "sess-1", "plan")
+	require.NoError(t, err)
+	require.Equal(t, types.MemoryAccessPrivate, stored.AccessLevel)
+
+	// Admin keys may read anything.
+	admin := memoryCaller{principal: &auth.Principal{KeyID: "key-admin", Role: types.APIKeyRoleAdmin}}
+	require.Equal(t, http.StatusOK, serveMemoryRequest(GetMemoryHandler(storage), http.MethodPost, get, admin).Code)
+}
+
+func TestMemoryAccess_RequiredRolesAndAudit(t *testing.T) {
+	storage := newMemoryStorageStub()
+	operator := memoryCaller{principal: &auth.Principal{KeyID: "key-op", Role: types.APIKeyRoleOperator}}
+	readOnly := memoryCaller{principal: &auth.Principal{KeyID: "key-ro", Role: types.APIKeyRoleReadOnly}}
+
+	body := `{"key":"budget","data":100,"scope":"session","access_control":{"required_roles":["operator"],"audit_access":true}}`
+	resp := serveMemoryRequest(SetMemoryHandler(storage), http.MethodPost, body, operator)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	get := `{"key":"budget","scope":"session"}`
+	require.Equal(t, http.StatusForbidden, serveMemoryRequest(GetMemoryHandler(storage), http.MethodPost, get, readOnly).Code)
+	require.Equal(t, http.StatusOK, serveMemoryRequest(GetMemoryHandler(storage), http.MethodPost, get, operator).Code)
+
+	require.Equal(t, http.StatusForbidden, serveMemoryRequest(GetMemoryHandler(storage), http.MethodPost, get, memoryCaller{agentID: "planner"}).Code, "unauthenticated callers hold no role")
+
+	require.Len(t, accessEvents(storage, "access_denied"), 2)
+	require.Len(t, accessEvents(storage, "access"), 1)
+	for _, event := range storage.published {
+		require.Equal(t, "memory_change", event.Type, "access events are not published")
+	}
+
+	resp = serveMemoryRequest(SetMemoryHandler(storage), http.MethodPost, `{"key":"k","data":1,"access_control":{"required_roles":["root"]}}`, operator)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = serveMemoryRequest(SetMemoryHandler(storage), http.MethodPost, `{"key":"k","data":1,"access_level":"secret"}`, operator)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = serveMemoryRequest(SetMemoryHandler(storage), http.MethodPost, `{"key":"k","data":1,"access_level":"private"}`, memoryCaller{})
+	require.Equal(t, http.StatusBadRequest, resp.Code, "private memory needs an owner")
+}
+
+func TestMemoryAccess_PrivateMemoryOwnedByStoredKey(t *testing.T) {
+	storage := newMemoryStorageStub()
+	owner := memoryCaller{agentID: "planner", principal: &auth.Principal{KeyID: "key-1", Role: types.APIKeyRoleOperator}}
+	spoofer := memoryCaller{agentID: "planner", principal: &auth.Principal{KeyID: "key-2", Role: types.APIKeyRoleOperator}}
+
+	resp := serveMemoryRequest(SetMemoryHandler(storage), http.MethodPost, `{"key":"plan","data":"secret","scope":"session","access_level":"private"}`, owner)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	stored, err := storage.GetMemory(context.Background(), "session", "sess-1", "plan")
+	require.NoError(t, err)
+	require.Equal(t, "key-1", stored.Metadata.AccessControl.OwnerKeyID)
+	require.Empty(t, stored.Metadata.AccessControl.OwnerAgentID, "the key owns the memory, not the header")
+
+	get := `{"key":"plan","scope":"session"}`
+	require.Equal(t, http.StatusOK, serveMemoryRequest(GetMemoryHandler(storage), http.MethodPost, get, owner).Code)
+	require.Equal(t, http.StatusForbidden, serveMemoryRequest(GetMemoryHandler(storage), http.MethodPost, get, spoofer).Code, "another key cannot claim the owner's agent ID")
+	require.Equal(t, http.StatusForbidden, serveMemoryRequest(GetMemoryHandler(storage), http.MethodPost, get, memoryCaller{agentID: "planner"}).Code)
+}
+
+func TestMemoryAccess_TeamRestricted(t *testing.T) {
+	storage := newMemoryStorageStub()
+	_ = storage.SetMemory(context.Background(), &types.Memory{
+		Scope: "session", ScopeID: "sess-1", Key: "notes", Data: json.RawMessage(`"x"`),
+		Metadata: types.MemoryMetadata{AccessControl: &types.AccessControlMetadata{TeamRestricted: true}},
+	})
+
+	get := `{"key":"notes","scope":"session"}`
+	require.Equal(t, http.StatusOK, serveMemoryRequest(GetMemoryHandler(storage), http.MethodPost, get, memoryCaller{}).Code)
+
+	// A team-scoped admin of another team is still refused.
+	storage.store["session|team:red:sess-1|notes"] = &types.Memory{
+		Scope: "session", ScopeID: "team:red:sess-1", Key: "notes", Data: json.RawMessage(`"x"`),
+		Metadata: types.MemoryMetadata{AccessControl: &types.AccessControlMetadata{TeamRestricted: true, OwnerTeamID: "blue"}},
+	}
+	admin := memoryCaller{principal: &auth.Principal{KeyID: "key-red", TeamID: "red", Role: types.APIKeyRoleAdmin}}
+	require.Equal(t, http.StatusForbidden, serveMemoryRequest(GetMemoryHandler(storage), http.MethodPost, get, admin).Code)
+}
+
+func TestSimilaritySearchHandler_HidesUnreadableVectors(t *testing.T) {
+	private := &types.AccessControlMetadata{OwnerAgentID: "planner"}
+	storage := &vectorStorageStub{searchResults: []*types.VectorSearchResult{
+		{Key: "private", Score: 0.99, AccessLevel: types.MemoryAccessPrivate, AccessControl: private},
+		{Key: "public", Score: 0.9},
+	}}
+
+	body := `{"query_embedding":[1,0],"top_k":1}`
+	resp := serveMemoryRequest(SimilaritySearchHandler(storage), http.MethodPost, body, memoryCaller{agentID: "researcher"})
+	require.Equal(t, http.StatusOK, resp.Code)
+	var results []types.VectorSearchResult
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &results))
+	require.Len(t, results, 1)
+	require.Equal(t, "public", results[0].Key)
+
+	resp = serveMemoryRequest(SimilaritySearchHandler(storage), http.MethodPost, body, memoryCaller{agentID: "planner"})
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &results))
+	require.Equal(t, "private", results[0].Key)
+
+	storage.getResult = &types.VectorRecord{Scope: "session", ScopeID: "sess-1", Key: "private", AccessLevel: types.MemoryAccessPrivate, AccessControl: private}
+	resp = serveMemoryRequest(SetVectorHandler(storage), http.MethodPost, `{"key":"private","embedding":[1,0]}`, memoryCaller{agentID: "researcher"})
+	require.Equal(t, http.StatusForbidden, resp.Code)
+	resp = serveMemoryRequest(SetVectorHandler(storage), http.MethodPost, `{"key":"private","embedding":[0,1]}`, memoryCaller{agentID: "planner"})
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, types.MemoryAccessPrivate, storage.setRecord.AccessLevel, "the policy is kept")
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MemoryKeyManager manages the keys memory values are encrypted with at rest.
+type MemoryKeyManager interface {
+	CurrentMemoryKey() (keyID string, key []byte, err error)
+	RotateMemoryKey() (string, error)
+	MemoryKeyIDs() ([]string, error)
+}
+
+// MemoryReencrypter rewrites stored memory values with the current encryption key.
+type MemoryReencrypter interface {
+	ReencryptMemory(ctx context.Context) (int, error)
+}
+
+// MemoryEncryptionStatusResponse describes how memory is encrypted.
+type MemoryEncryptionStatusResponse struct {
+	// Scopes are encrypted in full; other values only when written with encrypt set.
+	Scopes       []string `json:"scopes"`
+	CurrentKeyID string   `json:"current_key_id"`
+	// KeyIDs lists every memory key from oldest to newest. Older keys are kept so the
+	// values encrypted with them stay readable.
+	KeyIDs []string `json:"key_ids"`
+}
+
+// MemoryKeyRotationRequest rotates the memory key.
+type MemoryKeyRotationRequest struct {
+	// Reencrypt moves every stored value to the new key before responding.
+	Reencrypt bool `json:"reencrypt,omitempty"`
+}
+
+// MemoryReencryptionResponse reports a rotation or re-encryption.
+type MemoryReencryptionResponse struct {
+	KeyID       string `json:"key_id,omitempty"`
+	Reencrypted int    `json:"reencrypted"`
+}
+
+// GetMemoryEncryptionHandler reports the encrypted scopes and the memory keys.
+func GetMemoryEncryptionHandler(keys MemoryKeyManager, scopes []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentID, _, err := keys.CurrentMemoryKey()
+		if err != nil {
+			writeMemoryKeyError(c, err)
+			return
+		}
+		keyIDs, err := keys.MemoryKeyIDs()
+		if err != nil {
+			writeMemoryKeyError(c, err)
+			return
+		}
+		if scopes == nil {
+			scopes = []string{}
+		}
+		c.JSON(http.StatusOK, MemoryEncryptionStatusResponse{
+			Scopes:       scopes,
+			CurrentKeyID: currentID,
+			KeyIDs:       keyIDs,
+		})
+	}
+}
+
+// RotateMemoryKeyHandler creates a new memory key for values written from now on, and
+// optionally re-encrypts the stored values with it.
+func RotateMemoryKeyHandler(keys MemoryKeyManager, storageProvider MemoryReencrypter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req MemoryKeyRotationRequest
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_request",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+
+		keyID, err := keys.RotateMemoryKey()
+		if err != nil {
+			writeMemoryKeyError(c, err)
+			return
+		}
+		resp := MemoryReencryptionResponse{KeyID: keyID}
+		if req.Reencrypt {
+			if resp.Reencrypted, err = storageProvider.ReencryptMemory(c.Request.Context()); err != nil {
+				c.JSON(http.StatusInternalServerError, ErrorResponse{
+					Error:   "storage_error",
+					Message: "rotated to key " + keyID + " but re-encryption failed: " + err.Error(),
+					Code:    http.StatusInternalServerError,
+				})
+				return
+			}
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// ReencryptMemoryHandler re-encrypts the stored values that are not yet encrypted with
+// the current key, such as after a rotation or after a scope became encrypted.
+func ReencryptMemoryHandler(storageProvider MemoryReencrypter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		count, err := storageProvider.ReencryptMemory(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "storage_error",
+				Message: err.Error(),
+				Code:    http.StatusInternalServerError,
+			})
+			return
+		}
+		c.JSON(http.StatusOK, MemoryReencryptionResponse{Reencrypted: count})
+	}
+}
+
+func writeMemoryKeyError(c *gin.Context, err error) {
+	c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error:   "keystore_error",
+		Message: err.Error(),
+		Code:    http.StatusInternalServerError,
+	})
+}
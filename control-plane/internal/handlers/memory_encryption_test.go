@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryKeysStub struct {
+	ids []string
+}
+
+func (k *memoryKeysStub) CurrentMemoryKey() (string, []byte, error) {
+	return k.ids[len(k.ids)-1], make([]byte, 32), nil
+}
+
+func (k *memoryKeysStub) RotateMemoryKey() (string, error) {
+	k.ids = append(k.ids, fmt.Sprintf("memory-%d", len(k.ids)+1))
+	return k.ids[len(k.ids)-1], nil
+}
+
+func (k *memoryKeysStub) MemoryKeyIDs() ([]string, error) {
+	return k.ids, nil
+}
+
+type reencrypterStub struct {
+	calls int
+}
+
+func (r *reencrypterStub) ReencryptMemory(ctx context.Context) (int, error) {
+	r.calls++
+	return 3, nil
+}
+
+func TestMemoryEncryptionHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	keys := &memoryKeysStub{ids: []string{"memory-1"}}
+	store := &reencrypterStub{}
+	router := gin.New()
+	router.GET("/memory/encryption", GetMemoryEncryptionHandler(keys, []string{"global"}))
+	router.POST("/memory/encryption/rotate", RotateMemoryKeyHandler(keys, store))
+	router.POST("/memory/encryption/reencrypt", ReencryptMemoryHandler(store))
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := send(http.MethodPost, "/memory/encryption/rotate", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.JSONEq(t, `{"key_id":"memory-2","reencrypted":0}`, resp.Body.String())
+	require.Zero(t, store.calls, "rotation alone leaves stored values as they are")
+
+	resp = send(http.MethodPost, "/memory/encryption/rotate", `{"reencrypt":true}`)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.JSONEq(t, `{"key_id":"memory-3","reencrypted":3}`, resp.Body.String())
+
+	resp = send(http.MethodGet, "/memory/encryption", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var status MemoryEncryptionStatusResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &status))
+	require.Equal(t, MemoryEncryptionStatusResponse{
+		Scopes:       []string{"global"},
+		CurrentKeyID: "memory-3",
+		KeyIDs:       []string{"memory-1", "memory-2", "memory-3"},
+	}, status)
+
+	resp = send(http.MethodPost, "/memory/encryption/reencrypt", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.JSONEq(t, `{"reencrypted":3}`, resp.Body.String())
+	require.Equal(t, 2, store.calls)
+}
+
+func TestSetMemoryHandler_EncryptedValueStaysOutOfEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	storage := newMemoryStorageStub()
+	router := gin.New()
+	router.POST("/memory/set", SetMemoryHandler(storage))
+
+	req := httptest.NewRequest(http.MethodPost, "/memory/set", strings.NewReader(`{"key":"email","data":"ada@example.com","encrypt":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	stored, err := storage.GetMemory(context.Background(), "global", "global", "email")
+	require.NoError(t, err)
+	require.True(t, stored.Encrypted())
+
+	require.Len(t, storage.events, 1)
+	require.Nil(t, storage.events[0].Data)
+	require.Len(t, storage.published, 1)
+	require.Nil(t, storage.published[0].Data)
+}
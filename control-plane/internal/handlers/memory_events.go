@@ -64,7 +64,7 @@ func (h *MemoryEventsHandler) WebSocketHandler(c *gin.Context) {
 
 	// Parse query parameters for filtering
 	scope := c.Query("scope")
-	scopeID := c.Query("scope_id")
+	scopeID := teamMemoryScopeID(ctx, c.Query("scope_id"))
 	patterns := normalizePatterns(c.Query("patterns"))
 
 	// Subscribe to memory changes
@@ -90,6 +90,10 @@ func (h *MemoryEventsHandler) WebSocketHandler(c *gin.Context) {
 
 	// Forward events to the client
 	for event := range eventChan {
+		if !memoryEventVisible(ctx, &event) {
+			continue
+		}
+
 		// Apply pattern matching
 		if len(patterns) > 0 {
 			match := false
@@ -120,7 +124,7 @@ func (h *MemoryEventsHandler) SSEHandler(c *gin.Context) {
 
 	// Parse query parameters for filtering
 	scope := c.Query("scope")
-	scopeID := c.Query("scope_id")
+	scopeID := teamMemoryScopeID(ctx, c.Query("scope_id"))
 	patterns := normalizePatterns(c.Query("patterns"))
 
 	// Subscribe to memory changes
@@ -139,6 +143,10 @@ func (h *MemoryEventsHandler) SSEHandler(c *gin.Context) {
 			// Client disconnected
 			return
 		case event := <-eventChan:
+			if !memoryEventVisible(ctx, &event) {
+				continue
+			}
+
 			// Apply pattern matching
 			if len(patterns) > 0 {
 				match := false
@@ -177,6 +185,7 @@ func GetEventHistoryHandler(storage storage.StorageProvider) gin.HandlerFunc {
 			filter.Scope = &scope
 		}
 		if scopeID := c.Query("scope_id"); scopeID != "" {
+			scopeID = teamMemoryScopeID(ctx, scopeID)
 			filter.ScopeID = &scopeID
 		}
 		if patterns := c.Query("patterns"); patterns != "" {
@@ -199,6 +208,12 @@ func GetEventHistoryHandler(storage storage.StorageProvider) gin.HandlerFunc {
 			return
 		}
 
-		c.JSON(http.StatusOK, events)
+		visible := events[:0]
+		for _, event := range events {
+			if memoryEventVisible(ctx, event) {
+				visible = append(visible, event)
+			}
+		}
+		c.JSON(http.StatusOK, visible)
 	}
 }
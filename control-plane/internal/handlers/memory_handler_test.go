@@ -4,12 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/Agent-Field/agentfield/control-plane/internal/storage"
 	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
 
 	"github.com/gin-gonic/gin"
@@ -23,6 +26,9 @@ type memoryStorageStub struct {
 	published []types.MemoryChangeEvent
 	setErr    error
 	eventErr  error
+
+	executions map[string]*types.Execution
+	agents     map[string]*types.AgentNode
 }
 
 func newMemoryStorageStub() *memoryStorageStub {
@@ -44,6 +50,27 @@ func (m *memoryStorageStub) SetMemory(ctx context.Context, memory *types.Memory)
 	return nil
 }
 
+func (m *memoryStorageStub) UpdateMemory(ctx context.Context, scope, scopeID, key string, update func(*types.Memory) (*types.Memory, error)) (*types.Memory, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k := m.composite(scope, scopeID, key)
+	current := m.store[k]
+	next, err := update(current)
+	if err != nil {
+		return nil, err
+	}
+	if next == nil {
+		return current, nil
+	}
+	next.Scope, next.ScopeID, next.Key = scope, scopeID, key
+	next.Version = 1
+	if current != nil {
+		next.Version = current.Version + 1
+	}
+	m.store[k] = next
+	return next, nil
+}
+
 func (m *memoryStorageStub) GetMemory(ctx context.Context, scope, scopeID, key string) (*types.Memory, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -116,6 +143,26 @@ func (m *memoryStorageStub) SimilaritySearch(ctx context.Context, scope, scopeID
 	return []*types.VectorSearchResult{}, nil
 }
 
+func (m *memoryStorageStub) HybridSearch(ctx context.Context, scope, scopeID string, query types.VectorSearchQuery) ([]*types.VectorSearchResult, error) {
+	return []*types.VectorSearchResult{}, nil
+}
+
+func (m *memoryStorageStub) GetExecutionRecord(ctx context.Context, executionID string) (*types.Execution, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.executions[executionID], nil
+}
+
+func (m *memoryStorageStub) GetAgent(ctx context.Context, id string) (*types.AgentNode, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	agent, ok := m.agents[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", storage.ErrAgentNotFound, id)
+	}
+	return agent, nil
+}
+
 func TestSetMemoryHandler_StoresMemoryAndEvent(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -154,6 +201,31 @@ func TestSetMemoryHandler_StoresMemoryAndEvent(t *testing.T) {
 	require.Equal(t, "set", storage.published[0].Action)
 }
 
+func TestSetMemoryHandler_AppliesTTL(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	storage := newMemoryStorageStub()
+	router := gin.New()
+	router.POST("/memory/set", SetMemoryHandler(storage))
+
+	req := httptest.NewRequest(http.MethodPost, "/memory/set", strings.NewReader(`{"key":"scratch","data":"x","ttl_seconds":90}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	stored, err := storage.GetMemory(context.Background(), "global", "global", "scratch")
+	require.NoError(t, err)
+	require.NotNil(t, stored.TTL)
+	require.Equal(t, 90*time.Second, *stored.TTL)
+
+	req = httptest.NewRequest(http.MethodPost, "/memory/set", strings.NewReader(`{"key":"scratch","data":"x","ttl_seconds":0}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
 func TestGetMemoryHandler_HierarchicalLookup(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
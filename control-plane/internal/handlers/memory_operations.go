@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+	"github.com/Agent-Field/agentfield/control-plane/internal/storage"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MemoryOperationRequest applies an atomic change to a memory value. Only the fields of
+// the requested operation are read.
+type MemoryOperationRequest struct {
+	Key   string  `json:"key" binding:"required"`
+	Scope *string `json:"scope,omitempty"`
+	// By is added by increment; it defaults to 1 and may be negative or fractional.
+	By *json.Number `json:"by,omitempty"`
+	// Values are appended in order by append.
+	Values []json.RawMessage `json:"values,omitempty"`
+	// Patch is an RFC 7386 JSON merge patch applied by merge.
+	Patch json.RawMessage `json:"patch,omitempty"`
+
+	IfVersion *int64 `json:"if_version,omitempty"`
+	// TTLSeconds restarts the value's TTL; without it the value keeps its current expiry,
+	// and a new key gets the memory_retention of the writing reasoner.
+	TTLSeconds *int64 `json:"ttl_seconds,omitempty"`
+}
+
+// memoryOperation computes the new value of a key from its current value, which is nil
+// when the key does not exist.
+type memoryOperation func(current json.RawMessage, req *MemoryOperationRequest) (json.RawMessage, error)
+
+// memoryVersionConflictError rejects a conditional write whose expected version is stale.
+type memoryVersionConflictError struct {
+	expected int64
+	actual   int64
+}
+
+func (e *memoryVersionConflictError) Error() string {
+	return fmt.Sprintf("memory version conflict: expected version %d, found %d", e.expected, e.actual)
+}
+
+// memoryOperationError reports a request that cannot be applied to the stored value.
+type memoryOperationError struct {
+	msg string
+}
+
+func (e *memoryOperationError) Error() string {
+	return e.msg
+}
+
+// IncrementMemoryHandler atomically adds to a numeric memory value. Missing keys start
+// at zero.
+// POST /api/v1/memory/increment
+func IncrementMemoryHandler(storageProvider MemoryStorage) gin.HandlerFunc {
+	return memoryOperationHandler(storageProvider, incrementMemoryValue)
+}
+
+// AppendMemoryHandler atomically appends to a list memory value. Missing keys start as
+// an empty list.
+// POST /api/v1/memory/append
+func AppendMemoryHandler(storageProvider MemoryStorage) gin.HandlerFunc {
+	return memoryOperationHandler(storageProvider, appendMemoryValue)
+}
+
+// MergeMemoryHandler atomically applies a JSON merge patch to a memory value.
+// POST /api/v1/memory/merge
+func MergeMemoryHandler(storageProvider MemoryStorage) gin.HandlerFunc {
+	return memoryOperationHandler(storageProvider, mergeMemoryValue)
+}
+
+func memoryOperationHandler(storageProvider MemoryStorage, apply memoryOperation) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		var req MemoryOperationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_request",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		if req.TTLSeconds != nil && *req.TTLSeconds <= 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_request",
+				Message: "ttl_seconds must be positive",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+
+		scope, scopeID := resolveScope(c, req.Scope)
+		retention := memoryRetentionTTL(c, storageProvider)
+		var previousData json.RawMessage
+		memory, err := storageProvider.UpdateMemory(ctx, scope, scopeID, req.Key, func(current *types.Memory) (*types.Memory, error) {
+			if req.IfVersion != nil {
+				if err := checkMemoryVersion(current, *req.IfVersion); err != nil {
+					return nil, err
+				}
+			}
+
+			next := &types.Memory{}
+			previousData = nil
+			if current != nil {
+				if err := checkMemoryAccess(c, memoryTarget(current)); err != nil {
+					return nil, err
+				}
+				previousData = current.Data
+				next.AccessLevel = current.AccessLevel
+				next.TTL = current.TTL
+				next.ExpiresAt = current.ExpiresAt
+				next.Metadata = current.Metadata
+			}
+			data, err := apply(previousData, &req)
+			if err != nil {
+				return nil, err
+			}
+			next.Data = data
+			if req.TTLSeconds != nil {
+				ttl := time.Duration(*req.TTLSeconds) * time.Second
+				next.TTL = &ttl
+				next.ExpiresAt = nil
+			} else if current == nil {
+				next.TTL = retention
+			}
+			return next, nil
+		})
+		if err != nil {
+			var denied *memoryAccessDeniedError
+			if errors.As(err, &denied) {
+				recordMemoryAccess(c, storageProvider, memoryAccessTarget{scope: scope, scopeID: scopeID, key: req.Key}, "access_denied")
+			}
+			writeMemoryWriteError(c, err)
+			return
+		}
+
+		event := &types.MemoryChangeEvent{
+			Type:         "memory_change",
+			Scope:        scope,
+			ScopeID:      scopeID,
+			Key:          req.Key,
+			Action:       "set",
+			Data:         memory.Data,
+			PreviousData: previousData,
+			Metadata:     memoryEventMetadata(c),
+		}
+		redactProtectedMemoryEvent(event, memory)
+		if err := storageProvider.StoreEvent(ctx, event); err != nil {
+			logger.Logger.Warn().Err(err).Msg("Warning: Failed to store memory change event")
+		} else if err := storageProvider.PublishMemoryChange(ctx, *event); err != nil {
+			logger.Logger.Warn().Err(err).Msg("Warning: Failed to publish memory change event")
+		}
+
+		c.JSON(http.StatusOK, memory)
+	}
+}
+
+// checkMemoryVersion fails unless current is at version expected. Missing keys are at
+// version 0.
+func checkMemoryVersion(current *types.Memory, expected int64) error {
+	var actual int64
+	if current != nil {
+		actual = current.Version
+	}
+	if actual != expected {
+		return &memoryVersionConflictError{expected: expected, actual: actual}
+	}
+	return nil
+}
+
+// writeMemoryWriteError maps a failed memory write to its HTTP response.
+func writeMemoryWriteError(c *gin.Context, err error) {
+	var conflict *memoryVersionConflictError
+	var invalid *memoryOperationError
+	var denied *memoryAccessDeniedError
+	switch {
+	case errors.As(err, &denied):
+		writeMemoryAccessDenied(c, err)
+	case errors.As(err, &conflict):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "version_conflict",
+			Message: err.Error(),
+			Code:    http.StatusConflict,
+		})
+	case errors.As(err, &invalid):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_operation",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	case errors.Is(err, storage.ErrMemoryEncryptionDisabled):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "encryption_disabled",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "storage_error",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+	}
+}
+
+func incrementMemoryValue(current json.RawMessage, req *MemoryOperationRequest) (json.RawMessage, error) {
+	by := json.Number("1")
+	if req.By != nil {
+		by = *req.By
+	}
+	value := json.Number("0")
+	if decoded, err := decodeMemoryValue(current); err != nil {
+		return nil, err
+	} else if decoded != nil {
+		number, ok := decoded.(json.Number)
+		if !ok {
+			return nil, &memoryOperationError{msg: "cannot increment a value that is not a number"}
+		}
+		value = number
+	}
+
+	if a, err := value.Int64(); err == nil {
+		if b, err := by.Int64(); err == nil {
+			sum := a + b
+			if (b > 0 && sum < a) || (b < 0 && sum > a) {
+				return nil, &memoryOperationError{msg: fmt.Sprintf("incrementing %d by %d overflows a 64-bit integer", a, b)}
+			}
+			return json.Marshal(sum)
+		}
+	}
+	a, err := value.Float64()
+	if err != nil {
+		return nil, &memoryOperationError{msg: fmt.Sprintf("invalid stored number %q", value)}
+	}
+	b, err := by.Float64()
+	if err != nil {
+		return nil, &memoryOperationError{msg: fmt.Sprintf("invalid increment %q", by)}
+	}
+	return json.Marshal(a + b)
+}
+
+func appendMemoryValue(current json.RawMessage, req *MemoryOperationRequest) (json.RawMessage, error) {
+	if len(req.Values) == 0 {
+		return nil, &memoryOperationError{msg: "values are required to append"}
+	}
+	list := []interface{}{}
+	decoded, err := decodeMemoryValue(current)
+	if err != nil {
+		return nil, err
+	}
+	if decoded != nil {
+		existing, ok := decoded.([]interface{})
+		if !ok {
+			return nil, &memoryOperationError{msg: "cannot append to a value that is not a list"}
+		}
+		list = existing
+	}
+	for _, value := range req.Values {
+		list = append(list, value)
+	}
+	return json.Marshal(list)
+}
+
+func mergeMemoryValue(current json.RawMessage, req *MemoryOperationRequest) (json.RawMessage, error) {
+	if len(req.Patch) == 0 {
+		return nil, &memoryOperationError{msg: "patch is required to merge"}
+	}
+	patch, err := decodeMemoryValue(req.Patch)
+	if err != nil {
+		return nil, err
+	}
+	target, err := decodeMemoryValue(current)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(applyMergePatch(target, patch))
+}
+
+// applyMergePatch implements RFC 7386: objects merge recursively, null removes a member
+// and anything else replaces the target.
+func applyMergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = applyMergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
+
+// decodeMemoryValue decodes stored JSON keeping numbers exact. Empty input decodes to nil.
+func decodeMemoryValue(raw json.RawMessage) (interface{}, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, &memoryOperationError{msg: fmt.Sprintf("invalid JSON value: %v", err)}
+	}
+	return value, nil
+}
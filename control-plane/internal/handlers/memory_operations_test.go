@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryOperationsRouter() (*gin.Engine, *memoryStorageStub) {
+	gin.SetMode(gin.TestMode)
+	storage := newMemoryStorageStub()
+	router := gin.New()
+	router.POST("/memory/set", SetMemoryHandler(storage))
+	router.POST("/memory/increment", IncrementMemoryHandler(storage))
+	router.POST("/memory/append", AppendMemoryHandler(storage))
+	router.POST("/memory/merge", MergeMemoryHandler(storage))
+	return router, storage
+}
+
+func postMemory(router *gin.Engine, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Workflow-ID", "wf-1")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestSetMemoryHandler_IfVersion(t *testing.T) {
+	router, storage := newMemoryOperationsRouter()
+
+	resp := postMemory(router, "/memory/set", `{"key":"plan","data":"v1","if_version":0}`)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Contains(t, resp.Body.String(), `"version":1`)
+
+	resp = postMemory(router, "/memory/set", `{"key":"plan","data":"v2","if_version":0}`)
+	require.Equal(t, http.StatusConflict, resp.Code, "the key already exists")
+	require.Contains(t, resp.Body.String(), "version_conflict")
+
+	resp = postMemory(router, "/memory/set", `{"key":"plan","data":"v2","if_version":1}`)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	stored, err := storage.GetMemory(context.Background(), "workflow", "wf-1", "plan")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), stored.Version)
+	require.JSONEq(t, `"v2"`, string(stored.Data))
+	require.JSONEq(t, `"v1"`, string(storage.events[len(storage.events)-1].PreviousData))
+}
+
+func TestMemoryOperations_Increment(t *testing.T) {
+	router, storage := newMemoryOperationsRouter()
+
+	require.Equal(t, http.StatusOK, postMemory(router, "/memory/increment", `{"key":"count"}`).Code)
+	require.Equal(t, http.StatusOK, postMemory(router, "/memory/increment", `{"key":"count","by":41}`).Code)
+	stored, _ := storage.GetMemory(context.Background(), "workflow", "wf-1", "count")
+	require.JSONEq(t, `42`, string(stored.Data))
+
+	require.Equal(t, http.StatusOK, postMemory(router, "/memory/increment", `{"key":"count","by":0.5}`).Code)
+	stored, _ = storage.GetMemory(context.Background(), "workflow", "wf-1", "count")
+	require.JSONEq(t, `42.5`, string(stored.Data))
+
+	require.Equal(t, http.StatusOK, postMemory(router, "/memory/set", `{"key":"name","data":"x"}`).Code)
+	resp := postMemory(router, "/memory/increment", `{"key":"name"}`)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Contains(t, resp.Body.String(), "invalid_operation")
+
+	resp = postMemory(router, "/memory/increment", `{"key":"count","if_version":1}`)
+	require.Equal(t, http.StatusConflict, resp.Code)
+}
+
+func TestMemoryOperations_IncrementRejectsInt64Overflow(t *testing.T) {
+	router, storage := newMemoryOperationsRouter()
+
+	ctx := context.Background()
+	require.NoError(t, storage.SetMemory(ctx, &types.Memory{Scope: "workflow", ScopeID: "wf-1", Key: "count", Data: json.RawMessage(`9223372036854775806`)}))
+	require.Equal(t, http.StatusOK, postMemory(router, "/memory/increment", `{"key":"count"}`).Code)
+
+	resp := postMemory(router, "/memory/increment", `{"key":"count"}`)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Contains(t, resp.Body.String(), "overflows")
+	stored, _ := storage.GetMemory(ctx, "workflow", "wf-1", "count")
+	require.JSONEq(t, `9223372036854775807`, string(stored.Data))
+
+	require.NoError(t, storage.SetMemory(ctx, &types.Memory{Scope: "workflow", ScopeID: "wf-1", Key: "low", Data: json.RawMessage(`-9223372036854775808`)}))
+	resp = postMemory(router, "/memory/increment", `{"key":"low","by":-1}`)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestMemoryOperations_Append(t *testing.T) {
+	router, storage := newMemoryOperationsRouter()
+
+	require.Equal(t, http.StatusOK, postMemory(router, "/memory/append", `{"key":"findings","values":["a"]}`).Code)
+	resp := postMemory(router, "/memory/append", `{"key":"findings","values":[{"b":1},12345678901234567]}`)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var memory struct {
+		Data    json.RawMessage `json:"data"`
+		Version int64           `json:"version"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &memory))
+	require.JSONEq(t, `["a",{"b":1},12345678901234567]`, string(memory.Data))
+	require.Equal(t, int64(2), memory.Version)
+	require.Len(t, storage.published, 2)
+	require.Equal(t, "set", storage.published[1].Action)
+
+	require.Equal(t, http.StatusBadRequest, postMemory(router, "/memory/append", `{"key":"findings"}`).Code)
+}
+
+func TestMemoryOperations_MergePatch(t *testing.T) {
+	router, storage := newMemoryOperationsRouter()
+
+	require.Equal(t, http.StatusOK, postMemory(router, "/memory/set", `{"key":"state","data":{"a":1,"nested":{"x":1,"y":2},"drop":true}}`).Code)
+	resp := postMemory(router, "/memory/merge", `{"key":"state","patch":{"b":2,"nested":{"y":null,"z":3},"drop":null}}`)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	stored, _ := storage.GetMemory(context.Background(), "workflow", "wf-1", "state")
+	require.JSONEq(t, `{"a":1,"b":2,"nested":{"x":1,"z":3}}`, string(stored.Data))
+
+	require.Equal(t, http.StatusBadRequest, postMemory(router, "/memory/merge", `{"key":"state"}`).Code)
+}
+
+func TestMemoryOperations_KeepExpiryWithoutTTL(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	provider, ctx := setupTestStorage(t)
+	router := gin.New()
+	router.POST("/memory/set", SetMemoryHandler(provider))
+	router.POST("/memory/increment", IncrementMemoryHandler(provider))
+
+	require.Equal(t, http.StatusOK, postMemory(router, "/memory/set", `{"key":"count","data":1,"ttl_seconds":60}`).Code)
+	stored, err := provider.GetMemory(ctx, "workflow", "wf-1", "count")
+	require.NoError(t, err)
+	require.NotNil(t, stored.ExpiresAt)
+	expiresAt := *stored.ExpiresAt
+
+	time.Sleep(5 * time.Millisecond)
+	require.Equal(t, http.StatusOK, postMemory(router, "/memory/increment", `{"key":"count"}`).Code)
+	stored, err = provider.GetMemory(ctx, "workflow", "wf-1", "count")
+	require.NoError(t, err)
+	require.JSONEq(t, `2`, string(stored.Data))
+	require.True(t, expiresAt.Equal(*stored.ExpiresAt), "an operation without a TTL keeps the expiry")
+
+	require.Equal(t, http.StatusOK, postMemory(router, "/memory/increment", `{"key":"count","ttl_seconds":3600}`).Code)
+	stored, err = provider.GetMemory(ctx, "workflow", "wf-1", "count")
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(time.Hour), *stored.ExpiresAt, 5*time.Second)
+
+	require.Equal(t, http.StatusOK, postMemory(router, "/memory/set", `{"key":"count","data":0}`).Code)
+	stored, err = provider.GetMemory(ctx, "workflow", "wf-1", "count")
+	require.NoError(t, err)
+	require.Nil(t, stored.ExpiresAt, "a set without a TTL never expires")
+}
+
+func TestMemoryWrites_ApplyReasonerRetention(t *testing.T) {
+	router, storage := newMemoryOperationsRouter()
+	storage.executions = map[string]*types.Execution{
+		"exec-session": {ExecutionID: "exec-session", AgentNodeID: "node-1", ReasonerID: "scratch"},
+		"exec-keep":    {ExecutionID: "exec-keep", AgentNodeID: "node-1", ReasonerID: "archive"},
+	}
+	storage.agents = map[string]*types.AgentNode{"node-1": {ID: "node-1", Reasoners: []types.ReasonerDefinition{
+		{ID: "scratch", MemoryConfig: types.MemoryConfig{MemoryRetention: types.MemoryRetentionSession}},
+		{ID: "archive", MemoryConfig: types.MemoryConfig{MemoryRetention: types.MemoryRetentionPersistent}},
+	}}}
+
+	write := func(path, executionID, body string) *types.Memory {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Workflow-ID", "wf-1")
+		req.Header.Set("X-Execution-ID", executionID)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		var key struct {
+			Key string `json:"key"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &key))
+		stored, err := storage.GetMemory(context.Background(), "workflow", "wf-1", key.Key)
+		require.NoError(t, err)
+		return stored
+	}
+
+	stored := write("/memory/set", "exec-session", `{"key":"draft","data":"x"}`)
+	require.NotNil(t, stored.TTL)
+	require.Equal(t, types.SessionMemoryRetention, *stored.TTL)
+
+	stored = write("/memory/set", "exec-session", `{"key":"draft","data":"x","ttl_seconds":60}`)
+	require.Equal(t, time.Minute, *stored.TTL, "an explicit TTL wins")
+
+	stored = write("/memory/increment", "exec-session", `{"key":"count"}`)
+	require.NotNil(t, stored.TTL)
+	require.Equal(t, types.SessionMemoryRetention, *stored.TTL)
+
+	require.Nil(t, write("/memory/set", "exec-keep", `{"key":"report","data":"x"}`).TTL)
+	require.Nil(t, write("/memory/set", "exec-unknown", `{"key":"other","data":"x"}`).TTL)
+}
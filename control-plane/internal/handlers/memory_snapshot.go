@@ -0,0 +1,581 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Memory snapshots back up memory and move it between control planes. An export is
+// NDJSON, one types.MemorySnapshotRecord per line: the key-value entries first, then
+// the vectors. Importing the stream writes every record back.
+//
+// Restore rewinds key-value memory to an earlier moment by replaying the memory change
+// history, and an export can be taken as of such a moment the same way. Both reach back
+// only as far as the event history, which is kept for 48 hours, and cannot recover
+// values whose change events were redacted because the memory was protected. Vectors
+// have no history and are left as they are.
+
+// MemorySnapshotStorage captures the storage operations required by the memory
+// snapshot handlers.
+type MemorySnapshotStorage interface {
+	MemoryStorage
+	ExportMemory(ctx context.Context, filter types.MemorySnapshotFilter, emit func(*types.Memory) error) error
+	ExportVectors(ctx context.Context, filter types.MemorySnapshotFilter, emit func(*types.VectorRecord) error) error
+	GetEventHistory(ctx context.Context, filter types.EventFilter) ([]*types.MemoryChangeEvent, error)
+}
+
+// MemoryImportResponse counts the records an import wrote.
+type MemoryImportResponse struct {
+	Memories int `json:"memories"`
+	Vectors  int `json:"vectors"`
+	// Skipped counts key-value entries that had expired by the time of the import.
+	Skipped int `json:"skipped"`
+}
+
+// MemoryRestoreRequest rewinds the selected key-value memory to Timestamp.
+type MemoryRestoreRequest struct {
+	Timestamp time.Time `json:"timestamp" binding:"required"`
+	Scope     string    `json:"scope,omitempty"`
+	ScopeID   string    `json:"scope_id,omitempty"`
+	KeyPrefix string    `json:"key_prefix,omitempty"`
+	// DryRun reports the changes a restore would make without making them.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// MemoryRestoreKey identifies a key-value entry touched by a restore.
+type MemoryRestoreKey struct {
+	Scope   string `json:"scope"`
+	ScopeID string `json:"scope_id"`
+	Key     string `json:"key"`
+}
+
+// MemoryRestoreChange is one write made by a restore.
+type MemoryRestoreChange struct {
+	MemoryRestoreKey
+	// Action is "set" or "delete".
+	Action string `json:"action"`
+}
+
+// MemoryRestoreResponse reports the outcome of a restore.
+type MemoryRestoreResponse struct {
+	Timestamp time.Time             `json:"timestamp"`
+	DryRun    bool                  `json:"dry_run"`
+	Set       int                   `json:"set"`
+	Deleted   int                   `json:"deleted"`
+	Changes   []MemoryRestoreChange `json:"changes"`
+	// Unrecoverable lists the keys changed since Timestamp whose earlier value is not
+	// in the history. They are left as they are.
+	Unrecoverable []MemoryRestoreKey `json:"unrecoverable"`
+}
+
+// ExportMemoryHandler streams the selected memory as NDJSON. The scope, scope_id and
+// prefix query parameters select the memory; vectors=false leaves out the vectors and
+// as_of exports key-value memory as it was at that RFC 3339 time.
+func ExportMemoryHandler(storageProvider MemorySnapshotStorage) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		filter := memorySnapshotFilter(c, c.Query("scope"), c.Query("scope_id"), c.Query("prefix"))
+
+		includeVectors := true
+		if raw := c.Query("vectors"); raw != "" {
+			parsed, err := strconv.ParseBool(raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Error:   "invalid_request",
+					Message: "vectors must be a boolean",
+					Code:    http.StatusBadRequest,
+				})
+				return
+			}
+			includeVectors = parsed
+		}
+
+		var plan *memoryRestorePlan
+		if raw := c.Query("as_of"); raw != "" {
+			asOf, err := time.Parse(time.RFC3339, raw)
+			if err != nil || asOf.After(time.Now()) {
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Error:   "invalid_request",
+					Message: "as_of must be an RFC 3339 timestamp in the past",
+					Code:    http.StatusBadRequest,
+				})
+				return
+			}
+			if plan, err = loadMemoryRestorePlan(ctx, storageProvider, filter, asOf); err != nil {
+				c.JSON(http.StatusInternalServerError, ErrorResponse{
+					Error:   "storage_error",
+					Message: err.Error(),
+					Code:    http.StatusInternalServerError,
+				})
+				return
+			}
+			includeVectors = false
+		}
+
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Status(http.StatusOK)
+		encoder := json.NewEncoder(c.Writer)
+		writeMemory := func(memory *types.Memory) error {
+			return encoder.Encode(types.MemorySnapshotRecord{Kind: types.MemorySnapshotKindMemory, Memory: memory})
+		}
+
+		err := storageProvider.ExportMemory(ctx, filter, func(memory *types.Memory) error {
+			if !memoryScopeIDVisible(ctx, memory.ScopeID) {
+				return nil
+			}
+			if plan != nil {
+				if memory = plan.asOf(memory); memory == nil {
+					return nil
+				}
+			}
+			return writeMemory(memory)
+		})
+		if err == nil && plan != nil {
+			err = plan.eachMissing(writeMemory)
+		}
+		if err == nil && includeVectors {
+			err = storageProvider.ExportVectors(ctx, filter, func(record *types.VectorRecord) error {
+				if !memoryScopeIDVisible(ctx, record.ScopeID) {
+					return nil
+				}
+				return encoder.Encode(types.MemorySnapshotRecord{Kind: types.MemorySnapshotKindVector, Vector: record})
+			})
+		}
+		if err != nil {
+			// The status is already sent; the error record marks the export incomplete.
+			logger.Logger.Error().Err(err).Msg("Memory export failed")
+			_ = encoder.Encode(types.MemorySnapshotRecord{Kind: types.MemorySnapshotKindError, Error: err.Error()})
+		}
+	}
+}
+
+// ImportMemoryHandler writes back the records of an NDJSON memory export, replacing
+// the entries that already exist. Key-value entries keep the time they had left to
+// live. An invalid record stops the import; the records before it stay imported.
+func ImportMemoryHandler(storageProvider MemorySnapshotStorage) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var response MemoryImportResponse
+		decoder := json.NewDecoder(c.Request.Body)
+		for n := 1; ; n++ {
+			var record types.MemorySnapshotRecord
+			err := decoder.Decode(&record)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err == nil {
+				err = validateMemorySnapshotRecord(c.Request.Context(), &record)
+			}
+			if err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Error:   "invalid_record",
+					Message: fmt.Sprintf("record %d: %v (%s)", n, err, response.summary()),
+					Code:    http.StatusBadRequest,
+				})
+				return
+			}
+
+			if err := importMemorySnapshotRecord(c, storageProvider, &record, &response); err != nil {
+				c.JSON(http.StatusInternalServerError, ErrorResponse{
+					Error:   "storage_error",
+					Message: fmt.Sprintf("record %d: %v (%s)", n, err, response.summary()),
+					Code:    http.StatusInternalServerError,
+				})
+				return
+			}
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func (r MemoryImportResponse) summary() string {
+	return fmt.Sprintf("imported %d memories and %d vectors before it", r.Memories, r.Vectors)
+}
+
+// validateMemorySnapshotRecord rejects records an import cannot write.
+func validateMemorySnapshotRecord(ctx context.Context, record *types.MemorySnapshotRecord) error {
+	var scope, scopeID, key string
+	switch record.Kind {
+	case types.MemorySnapshotKindMemory:
+		if record.Memory == nil {
+			return errors.New("memory record has no memory")
+		}
+		if len(record.Memory.Data) == 0 {
+			return errors.New("memory record has no data")
+		}
+		scope, scopeID, key = record.Memory.Scope, record.Memory.ScopeID, record.Memory.Key
+	case types.MemorySnapshotKindVector:
+		if record.Vector == nil {
+			return errors.New("vector record has no vector")
+		}
+		if len(record.Vector.Embedding) == 0 {
+			return errors.New("vector record has no embedding")
+		}
+		scope, scopeID, key = record.Vector.Scope, record.Vector.ScopeID, record.Vector.Key
+	case types.MemorySnapshotKindError:
+		return fmt.Errorf("the export is incomplete: %s", record.Error)
+	default:
+		return fmt.Errorf("unknown record kind %q", record.Kind)
+	}
+
+	if scope == "" || scopeID == "" || key == "" {
+		return errors.New("scope, scope_id and key are required")
+	}
+	if !memoryScopeIDVisible(ctx, scopeID) {
+		return fmt.Errorf("scope_id %q is outside the caller's team", scopeID)
+	}
+	return nil
+}
+
+func importMemorySnapshotRecord(c *gin.Context, storageProvider MemorySnapshotStorage, record *types.MemorySnapshotRecord, response *MemoryImportResponse) error {
+	ctx := c.Request.Context()
+	if record.Kind == types.MemorySnapshotKindVector {
+		if err := storageProvider.SetVector(ctx, record.Vector); err != nil {
+			return err
+		}
+		response.Vectors++
+		return nil
+	}
+
+	memory := record.Memory
+	now := time.Now()
+	if memory.Expired(now) {
+		response.Skipped++
+		return nil
+	}
+	memory.TTL = nil
+	if memory.ExpiresAt != nil {
+		remaining := memory.ExpiresAt.Sub(now)
+		memory.TTL = &remaining
+	}
+
+	existing, err := storageProvider.GetMemory(ctx, memory.Scope, memory.ScopeID, memory.Key)
+	if err != nil {
+		existing = nil
+	}
+	if err := storageProvider.SetMemory(ctx, memory); err != nil {
+		return err
+	}
+
+	// Imports are recorded like any other write, so a restore can undo them.
+	event := &types.MemoryChangeEvent{
+		Type:     "memory_change",
+		Scope:    memory.Scope,
+		ScopeID:  memory.ScopeID,
+		Key:      memory.Key,
+		Action:   "set",
+		Data:     memory.Data,
+		Metadata: memoryEventMetadata(c),
+	}
+	if existing != nil {
+		event.PreviousData = existing.Data
+	}
+	redactProtectedMemoryEvent(event, memory, existing)
+	recordMemoryChange(ctx, storageProvider, event)
+	response.Memories++
+	return nil
+}
+
+// RestoreMemoryHandler rewinds the selected key-value memory to the request's
+// timestamp. Restored values are written without a TTL and keep the current access
+// policy of their key.
+func RestoreMemoryHandler(storageProvider MemorySnapshotStorage) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		var req MemoryRestoreRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_request",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		if req.Timestamp.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_request",
+				Message: "timestamp must be in the past",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+
+		filter := memorySnapshotFilter(c, req.Scope, req.ScopeID, req.KeyPrefix)
+		plan, err := loadMemoryRestorePlan(ctx, storageProvider, filter, req.Timestamp)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "storage_error",
+				Message: err.Error(),
+				Code:    http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response := MemoryRestoreResponse{
+			Timestamp:     req.Timestamp,
+			DryRun:        req.DryRun,
+			Changes:       []MemoryRestoreChange{},
+			Unrecoverable: []MemoryRestoreKey{},
+		}
+		for _, step := range plan.steps {
+			if step.unrecoverable {
+				response.Unrecoverable = append(response.Unrecoverable, step.key)
+				continue
+			}
+
+			current, err := storageProvider.GetMemory(ctx, step.key.Scope, step.key.ScopeID, step.key.Key)
+			if err != nil {
+				current = nil
+			}
+			change := MemoryRestoreChange{MemoryRestoreKey: step.key, Action: "set"}
+			switch {
+			case step.data == nil && current == nil:
+				continue
+			case step.data == nil:
+				change.Action = "delete"
+			case current != nil && sameMemoryValue(current.Data, step.data):
+				continue
+			}
+
+			if !req.DryRun {
+				if err := restoreMemoryValue(c, storageProvider, step, current); err != nil {
+					c.JSON(http.StatusInternalServerError, ErrorResponse{
+						Error:   "storage_error",
+						Message: fmt.Sprintf("restoring %s/%s/%s after %d changes: %v", step.key.Scope, step.key.ScopeID, step.key.Key, len(response.Changes), err),
+						Code:    http.StatusInternalServerError,
+					})
+					return
+				}
+			}
+			response.Changes = append(response.Changes, change)
+			if change.Action == "delete" {
+				response.Deleted++
+			} else {
+				response.Set++
+			}
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// restoreMemoryValue writes the value step restores over current.
+func restoreMemoryValue(c *gin.Context, storageProvider MemoryStorage, step *memoryRestoreStep, current *types.Memory) error {
+	ctx := c.Request.Context()
+	event := &types.MemoryChangeEvent{
+		Type:     "memory_change",
+		Scope:    step.key.Scope,
+		ScopeID:  step.key.ScopeID,
+		Key:      step.key.Key,
+		Metadata: memoryEventMetadata(c),
+	}
+	if current != nil {
+		event.PreviousData = current.Data
+	}
+
+	if step.data == nil {
+		if err := storageProvider.DeleteMemory(ctx, step.key.Scope, step.key.ScopeID, step.key.Key); err != nil {
+			return err
+		}
+		event.Action = "delete"
+		redactProtectedMemoryEvent(event, current)
+	} else {
+		memory := &types.Memory{
+			Scope:   step.key.Scope,
+			ScopeID: step.key.ScopeID,
+			Key:     step.key.Key,
+			Data:    step.data,
+		}
+		keepMemoryPolicy(memory, current)
+		if err := storageProvider.SetMemory(ctx, memory); err != nil {
+			return err
+		}
+		event.Action = "set"
+		event.Data = step.data
+		redactProtectedMemoryEvent(event, memory, current)
+	}
+	recordMemoryChange(ctx, storageProvider, event)
+	return nil
+}
+
+// recordMemoryChange stores and publishes a memory change event. Failures are logged;
+// they do not undo the change.
+func recordMemoryChange(ctx context.Context, storageProvider MemoryStorage, event *types.MemoryChangeEvent) {
+	if err := storageProvider.StoreEvent(ctx, event); err != nil {
+		logger.Logger.Warn().Err(err).Msg("Warning: Failed to store memory change event")
+	} else if err := storageProvider.PublishMemoryChange(ctx, *event); err != nil {
+		logger.Logger.Warn().Err(err).Msg("Warning: Failed to publish memory change event")
+	}
+}
+
+// memorySnapshotFilter builds the filter of a snapshot request, namespacing the scope
+// ID by the caller's team.
+func memorySnapshotFilter(c *gin.Context, scope, scopeID, keyPrefix string) types.MemorySnapshotFilter {
+	return types.MemorySnapshotFilter{
+		Scope:     scope,
+		ScopeID:   teamMemoryScopeID(c.Request.Context(), scopeID),
+		KeyPrefix: keyPrefix,
+	}
+}
+
+func sameMemoryValue(a, b json.RawMessage) bool {
+	var compactA, compactB bytes.Buffer
+	if json.Compact(&compactA, a) != nil || json.Compact(&compactB, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(compactA.Bytes(), compactB.Bytes())
+}
+
+// memoryRestoreStep is the value a key had at the time of a restore plan.
+type memoryRestoreStep struct {
+	key MemoryRestoreKey
+	// data is nil when the key did not exist at that time.
+	data json.RawMessage
+	// unrecoverable is set when the history does not hold the value.
+	unrecoverable bool
+	// exported is set once an as-of export has seen the key.
+	exported bool
+}
+
+// memoryRestorePlan holds the keys changed since a point in time, sorted by key, with
+// the values they had then. Keys not in the plan have not changed since.
+type memoryRestorePlan struct {
+	steps []*memoryRestoreStep
+	byKey map[MemoryRestoreKey]*memoryRestoreStep
+}
+
+// loadMemoryRestorePlan plans the restore of the memory filter selects to at.
+func loadMemoryRestorePlan(ctx context.Context, storageProvider MemorySnapshotStorage, filter types.MemorySnapshotFilter, at time.Time) (*memoryRestorePlan, error) {
+	var eventFilter types.EventFilter
+	if filter.Scope != "" {
+		eventFilter.Scope = &filter.Scope
+	}
+	if filter.ScopeID != "" {
+		eventFilter.ScopeID = &filter.ScopeID
+	}
+	events, err := storageProvider.GetEventHistory(ctx, eventFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read memory history: %w", err)
+	}
+
+	selected := make([]*types.MemoryChangeEvent, 0, len(events))
+	for _, event := range events {
+		if event.Type == "memory_change" && filter.Matches(event.Scope, event.ScopeID, event.Key) && memoryEventVisible(ctx, event) {
+			selected = append(selected, event)
+		}
+	}
+	return planMemoryRestore(selected, at), nil
+}
+
+// planMemoryRestore works out from the change events of some keys the values they had
+// at at. Each key's value comes from its last event before at or, failing that, from
+// the previous value of its first event after.
+func planMemoryRestore(events []*types.MemoryChangeEvent, at time.Time) *memoryRestorePlan {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	type keyHistory struct {
+		before, after *types.MemoryChangeEvent
+	}
+	histories := make(map[MemoryRestoreKey]*keyHistory)
+	for _, event := range events {
+		key := MemoryRestoreKey{Scope: event.Scope, ScopeID: event.ScopeID, Key: event.Key}
+		history := histories[key]
+		if history == nil {
+			history = &keyHistory{}
+			histories[key] = history
+		}
+		if !event.Timestamp.After(at) {
+			history.before = event
+		} else if history.after == nil {
+			history.after = event
+		}
+	}
+
+	plan := &memoryRestorePlan{byKey: make(map[MemoryRestoreKey]*memoryRestoreStep)}
+	for key, history := range histories {
+		if history.after == nil {
+			continue
+		}
+		step := &memoryRestoreStep{key: key}
+		switch {
+		case history.before != nil && history.before.Action == "set":
+			// A set event without data was redacted.
+			step.data = history.before.Data
+			step.unrecoverable = len(step.data) == 0
+		case history.before != nil:
+			// Deleted or expired.
+		case history.after.Action == "set":
+			step.data = history.after.PreviousData
+			step.unrecoverable = len(history.after.Data) == 0
+		default:
+			step.data = history.after.PreviousData
+			step.unrecoverable = len(step.data) == 0
+		}
+		if len(step.data) == 0 {
+			step.data = nil
+		}
+		plan.steps = append(plan.steps, step)
+		plan.byKey[key] = step
+	}
+
+	sort.Slice(plan.steps, func(i, j int) bool {
+		a, b := plan.steps[i].key, plan.steps[j].key
+		if a.Scope != b.Scope {
+			return a.Scope < b.Scope
+		}
+		if a.ScopeID != b.ScopeID {
+			return a.ScopeID < b.ScopeID
+		}
+		return a.Key < b.Key
+	})
+	return plan
+}
+
+// asOf returns memory as it was at the plan's time, or nil when it did not exist then
+// or its value then is unknown.
+func (p *memoryRestorePlan) asOf(memory *types.Memory) *types.Memory {
+	step, ok := p.byKey[MemoryRestoreKey{Scope: memory.Scope, ScopeID: memory.ScopeID, Key: memory.Key}]
+	if !ok {
+		return memory
+	}
+	step.exported = true
+	if step.unrecoverable || step.data == nil {
+		return nil
+	}
+	past := *memory
+	past.Data = step.data
+	return &past
+}
+
+// eachMissing passes emit the memory that existed at the plan's time but has been
+// deleted since, as far as asOf has not seen it.
+func (p *memoryRestorePlan) eachMissing(emit func(*types.Memory) error) error {
+	for _, step := range p.steps {
+		if step.exported || step.unrecoverable || step.data == nil {
+			continue
+		}
+		memory := &types.Memory{
+			Scope:   step.key.Scope,
+			ScopeID: step.key.ScopeID,
+			Key:     step.key.Key,
+			Data:    step.data,
+		}
+		if err := emit(memory); err != nil {
+			return err
+		}
+	}
+	return nil
+}
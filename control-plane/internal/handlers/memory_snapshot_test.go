@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshotStorageStub adds the export and history operations to memoryStorageStub.
+type snapshotStorageStub struct {
+	*memoryStorageStub
+	vectors []*types.VectorRecord
+}
+
+func newSnapshotStorageStub() *snapshotStorageStub {
+	return &snapshotStorageStub{memoryStorageStub: newMemoryStorageStub()}
+}
+
+func (s *snapshotStorageStub) SetVector(ctx context.Context, record *types.VectorRecord) error {
+	s.vectors = append(s.vectors, record)
+	return nil
+}
+
+func (s *snapshotStorageStub) ExportMemory(ctx context.Context, filter types.MemorySnapshotFilter, emit func(*types.Memory) error) error {
+	s.mu.Lock()
+	keys := make([]string, 0, len(s.store))
+	for key := range s.store {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var memories []*types.Memory
+	for _, key := range keys {
+		memory := s.store[key]
+		if filter.Matches(memory.Scope, memory.ScopeID, memory.Key) {
+			memories = append(memories, memory)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, memory := range memories {
+		if err := emit(memory); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *snapshotStorageStub) ExportVectors(ctx context.Context, filter types.MemorySnapshotFilter, emit func(*types.VectorRecord) error) error {
+	for _, record := range s.vectors {
+		if filter.Matches(record.Scope, record.ScopeID, record.Key) {
+			if err := emit(record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *snapshotStorageStub) GetEventHistory(ctx context.Context, filter types.EventFilter) ([]*types.MemoryChangeEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*types.MemoryChangeEvent(nil), s.events...), nil
+}
+
+func readSnapshot(t *testing.T, body string) []types.MemorySnapshotRecord {
+	t.Helper()
+	var records []types.MemorySnapshotRecord
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		var record types.MemorySnapshotRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+// seedRestoreHistory leaves four keys changed after the returned time:
+//
+//	a: set to 1 before, then to 2 after
+//	b: created after
+//	c: deleted after, with its value in the event
+//	d: overwritten after by a redacted event
+func seedRestoreHistory(storage *snapshotStorageStub) time.Time {
+	base := time.Now().Add(-time.Hour)
+	before, after := base, base.Add(30*time.Minute)
+	event := func(at time.Time, key, action, data, previous string) *types.MemoryChangeEvent {
+		e := &types.MemoryChangeEvent{Type: "memory_change", Timestamp: at, Scope: "session", ScopeID: "s1", Key: key, Action: action}
+		if data != "" {
+			e.Data = json.RawMessage(data)
+		}
+		if previous != "" {
+			e.PreviousData = json.RawMessage(previous)
+		}
+		return e
+	}
+	storage.events = []*types.MemoryChangeEvent{
+		event(after, "a", "set", `2`, `1`),
+		event(before, "a", "set", `1`, ``),
+		event(after, "b", "set", `"new"`, ``),
+		event(after, "c", "delete", ``, `"old"`),
+		event(after, "d", "set", ``, ``),
+		{Type: "memory_access", Timestamp: after, Scope: "session", ScopeID: "s1", Key: "a", Action: "access"},
+	}
+	for key, data := range map[string]string{"a": `2`, "b": `"new"`, "d": `"secret"`, "e": `"untouched"`} {
+		_ = storage.SetMemory(context.Background(), &types.Memory{Scope: "session", ScopeID: "s1", Key: key, Data: json.RawMessage(data)})
+	}
+	return base.Add(15 * time.Minute)
+}
+
+func TestExportMemoryHandler_StreamsNDJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	storage := newSnapshotStorageStub()
+	_ = storage.SetMemory(context.Background(), &types.Memory{Scope: "session", ScopeID: "s1", Key: "plan", Data: json.RawMessage(`{"step":1}`)})
+	_ = storage.SetMemory(context.Background(), &types.Memory{Scope: "session", ScopeID: "s2", Key: "plan", Data: json.RawMessage(`2`)})
+	_ = storage.SetVector(context.Background(), &types.VectorRecord{Scope: "session", ScopeID: "s1", Key: "doc", Embedding: []float32{1, 0}})
+
+	router := gin.New()
+	router.GET("/memory/export", ExportMemoryHandler(storage))
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/memory/export?scope=session&scope_id=s1", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, "application/x-ndjson", resp.Header().Get("Content-Type"))
+
+	records := readSnapshot(t, resp.Body.String())
+	require.Len(t, records, 2)
+	require.Equal(t, types.MemorySnapshotKindMemory, records[0].Kind)
+	require.JSONEq(t, `{"step":1}`, string(records[0].Memory.Data))
+	require.Equal(t, types.MemorySnapshotKindVector, records[1].Kind)
+	require.Equal(t, "doc", records[1].Vector.Key)
+
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/memory/export?vectors=false", nil))
+	require.Len(t, readSnapshot(t, resp.Body.String()), 2)
+
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/memory/export?as_of=yesterday", nil))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestExportMemoryHandler_AsOf(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	storage := newSnapshotStorageStub()
+	at := seedRestoreHistory(storage)
+
+	router := gin.New()
+	router.GET("/memory/export", ExportMemoryHandler(storage))
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/memory/export?as_of="+at.Format(time.RFC3339), nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	values := map[string]string{}
+	for _, record := range readSnapshot(t, resp.Body.String()) {
+		values[record.Memory.Key] = string(record.Memory.Data)
+	}
+	require.Equal(t, map[string]string{"a": `1`, "c": `"old"`, "e": `"untouched"`}, values)
+}
+
+func TestImportMemoryHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	storage := newSnapshotStorageStub()
+	_ = storage.SetMemory(context.Background(), &types.Memory{Scope: "global", ScopeID: "global", Key: "config", Data: json.RawMessage(`"old"`)})
+
+	router := gin.New()
+	router.POST("/memory/import", ImportMemoryHandler(storage))
+
+	expiresSoon := time.Now().Add(time.Hour).UTC().Format(time.RFC3339Nano)
+	expired := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339Nano)
+	body := strings.Join([]string{
+		`{"kind":"memory","memory":{"scope":"global","scope_id":"global","key":"config","data":"new"}}`,
+		`{"kind":"memory","memory":{"scope":"session","scope_id":"s1","key":"scratch","data":1,"expires_at":"` + expiresSoon + `"}}`,
+		`{"kind":"memory","memory":{"scope":"session","scope_id":"s1","key":"stale","data":1,"expires_at":"` + expired + `"}}`,
+		`{"kind":"vector","vector":{"scope":"session","scope_id":"s1","key":"doc","embedding":[1,0]}}`,
+	}, "\n")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/memory/import", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var result MemoryImportResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	require.Equal(t, MemoryImportResponse{Memories: 2, Vectors: 1, Skipped: 1}, result)
+
+	config, err := storage.GetMemory(context.Background(), "global", "global", "config")
+	require.NoError(t, err)
+	require.JSONEq(t, `"new"`, string(config.Data))
+	scratch, err := storage.GetMemory(context.Background(), "session", "s1", "scratch")
+	require.NoError(t, err)
+	require.NotNil(t, scratch.TTL)
+	require.InDelta(t, time.Hour.Seconds(), scratch.TTL.Seconds(), 60)
+	require.Len(t, storage.vectors, 1)
+
+	require.Len(t, storage.events, 2)
+	require.JSONEq(t, `"old"`, string(storage.events[0].PreviousData), "imports can be rolled back")
+
+	body = `{"kind":"memory","memory":{"scope":"global","scope_id":"global","key":"x","data":1}}` + "\n" + `{"kind":"error","error":"disk full"}`
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/memory/import", strings.NewReader(body)))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Contains(t, resp.Body.String(), "record 2: the export is incomplete: disk full (imported 1 memories")
+}
+
+func TestImportMemoryHandler_RejectsOtherTeams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	storage := newSnapshotStorageStub()
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		ctx := auth.WithPrincipal(c.Request.Context(), &auth.Principal{KeyID: "k1", TeamID: "red", Role: types.APIKeyRoleAdmin})
+		c.Request = c.Request.WithContext(ctx)
+	})
+	router.POST("/memory/import", ImportMemoryHandler(storage))
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/memory/import",
+		strings.NewReader(`{"kind":"memory","memory":{"scope":"global","scope_id":"team:blue:global","key":"x","data":1}}`)))
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Contains(t, resp.Body.String(), "outside the caller's team")
+	require.Empty(t, storage.store)
+}
+
+func TestRestoreMemoryHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	storage := newSnapshotStorageStub()
+	at := seedRestoreHistory(storage)
+
+	router := gin.New()
+	router.POST("/memory/restore", RestoreMemoryHandler(storage))
+	restore := func(dryRun bool) MemoryRestoreResponse {
+		payload, _ := json.Marshal(MemoryRestoreRequest{Timestamp: at, Scope: "session", ScopeID: "s1", DryRun: dryRun})
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/memory/restore", strings.NewReader(string(payload))))
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		var result MemoryRestoreResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		return result
+	}
+
+	planned := restore(true)
+	require.Equal(t, 2, planned.Set)
+	require.Equal(t, 1, planned.Deleted)
+	require.Equal(t, []MemoryRestoreChange{
+		{MemoryRestoreKey: MemoryRestoreKey{Scope: "session", ScopeID: "s1", Key: "a"}, Action: "set"},
+		{MemoryRestoreKey: MemoryRestoreKey{Scope: "session", ScopeID: "s1", Key: "b"}, Action: "delete"},
+		{MemoryRestoreKey: MemoryRestoreKey{Scope: "session", ScopeID: "s1", Key: "c"}, Action: "set"},
+	}, planned.Changes)
+	require.Equal(t, []MemoryRestoreKey{{Scope: "session", ScopeID: "s1", Key: "d"}}, planned.Unrecoverable)
+	current, _ := storage.GetMemory(context.Background(), "session", "s1", "a")
+	require.JSONEq(t, `2`, string(current.Data), "a dry run changes nothing")
+
+	eventsBefore := len(storage.events)
+	restored := restore(false)
+	require.Equal(t, planned.Changes, restored.Changes)
+
+	values := map[string]string{}
+	for _, memory := range storage.store {
+		values[memory.Key] = string(memory.Data)
+	}
+	require.Equal(t, map[string]string{"a": `1`, "c": `"old"`, "d": `"secret"`, "e": `"untouched"`}, values)
+	require.Len(t, storage.events, eventsBefore+3, "the restore is recorded in the history")
+}
@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
 	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
 	"github.com/Agent-Field/agentfield/control-plane/internal/services" // Import services package
 	"github.com/Agent-Field/agentfield/control-plane/internal/storage"
@@ -383,12 +384,23 @@ func RegisterNodeHandler(storageProvider storage.StorageProvider, uiService *ser
 
 		logger.Logger.Debug().Msgf("✅ Successfully parsed node data for ID: %s", newNode.ID)
 
+		if err := assignCallerTeam(ctx, &newNode.TeamID); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
 		// Validate the incoming node data
 		if err := validate.Struct(newNode); err != nil {
 			logger.Logger.Error().Err(err).Msg("❌ Validation error")
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed: " + err.Error()})
 			return
 		}
+		for _, reasoner := range newNode.Reasoners {
+			if _, err := reasoner.MemoryConfig.RetentionTTL(); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Validation failed: reasoner %s: %v", reasoner.ID, err)})
+				return
+			}
+		}
 
 		logger.Logger.Debug().Msgf("✅ Node validation passed for ID: %s", newNode.ID)
 
@@ -474,6 +486,10 @@ func RegisterNodeHandler(storageProvider storage.StorageProvider, uiService *ser
 		existingNode, err := storageProvider.GetAgent(ctx, newNode.ID)
 		isReRegistration := false
 		if err == nil && existingNode != nil {
+			if !auth.CanAccessTeam(ctx, existingNode.TeamID) {
+				c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("node '%s' belongs to another team", newNode.ID)})
+				return
+			}
 			isReRegistration = true
 		}
 
@@ -513,6 +529,21 @@ func RegisterNodeHandler(storageProvider storage.StorageProvider, uiService *ser
 		}
 		InvalidateDiscoveryCache()
 
+		if newNode.InstanceID != "" {
+			instance := &types.AgentInstance{
+				NodeID:        newNode.ID,
+				InstanceID:    newNode.InstanceID,
+				BaseURL:       newNode.BaseURL,
+				LastHeartbeat: newNode.LastHeartbeat,
+				RegisteredAt:  newNode.RegisteredAt,
+			}
+			if err := storageProvider.UpsertAgentInstance(ctx, instance); err != nil {
+				logger.Logger.Error().Err(err).Msg("❌ Storage error")
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store node instance: " + err.Error()})
+				return
+			}
+		}
+
 		logger.Logger.Debug().Msgf("✅ Successfully registered node: %s", newNode.ID)
 
 		// Enhanced DID registration integration
@@ -562,6 +593,9 @@ func RegisterNodeHandler(storageProvider storage.StorageProvider, uiService *ser
 
 		if presenceManager != nil {
 			presenceManager.Touch(newNode.ID, time.Now().UTC())
+			if newNode.InstanceID != "" {
+				presenceManager.TouchInstance(newNode.ID, newNode.InstanceID, newNode.LastHeartbeat)
+			}
 		}
 
 		responsePayload := gin.H{
@@ -570,6 +604,10 @@ func RegisterNodeHandler(storageProvider storage.StorageProvider, uiService *ser
 			"node_id": newNode.ID,
 		}
 
+		if newNode.InstanceID != "" {
+			responsePayload["instance_id"] = newNode.InstanceID
+		}
+
 		if newNode.BaseURL != "" {
 			responsePayload["resolved_base_url"] = newNode.BaseURL
 		}
@@ -599,8 +637,10 @@ func ListNodesHandler(storageProvider storage.StorageProvider) gin.HandlerFunc {
 			filters.HealthStatus = &activeStatus
 		}
 
-		// Check for team_id filter parameter
-		if teamID := c.Query("team_id"); teamID != "" {
+		// Check for team_id filter parameter; callers confined to a team only see its nodes
+		if teamID := auth.TeamFrom(ctx); teamID != "" {
+			filters.TeamID = &teamID
+		} else if teamID := c.Query("team_id"); teamID != "" {
 			filters.TeamID = &teamID
 		}
 
@@ -635,7 +675,7 @@ func GetNodeHandler(storageProvider storage.StorageProvider) gin.HandlerFunc {
 		}
 
 		node, err := storageProvider.GetAgent(ctx, nodeID)
-		if err != nil {
+		if err != nil || node == nil || !auth.CanAccessTeam(ctx, node.TeamID) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "node not found"})
 			return
 		}
@@ -669,6 +709,7 @@ func HeartbeatHandler(storageProvider storage.StorageProvider, uiService *servic
 			} `json:"mcp_servers,omitempty"`
 			Timestamp   string `json:"timestamp,omitempty"`
 			HealthScore *int   `json:"health_score,omitempty"` // New: allow agents to report health score
+			InstanceID  string `json:"instance_id,omitempty"`
 		}
 
 		// Read the request body if present
@@ -686,6 +727,9 @@ func HeartbeatHandler(storageProvider storage.StorageProvider, uiService *servic
 		if presenceManager != nil && presenceManager.HasLease(nodeID) {
 			presenceManager.Touch(nodeID, now)
 		}
+		if enhancedHeartbeat.InstanceID != "" {
+			touchAgentInstance(ctx, storageProvider, presenceManager, nodeID, enhancedHeartbeat.InstanceID, now)
+		}
 		needsDBUpdate, cached := heartbeatCache.shouldUpdateDatabase(nodeID, now, enhancedHeartbeat.Status, enhancedHeartbeat.MCPServers)
 
 		if needsDBUpdate {
@@ -1262,7 +1306,7 @@ func RegisterServerlessAgentHandler(storageProvider storage.StorageProvider, uiS
 
 		newNode := types.AgentNode{
 			ID:              discoveryData.NodeID,
-			TeamID:          "default", // Default team for serverless agents
+			TeamID:          serverlessTeam(ctx),
 			BaseURL:         req.InvocationURL,
 			Version:         discoveryData.Version,
 			DeploymentType:  "serverless",
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -36,6 +38,7 @@ func NodeStatusLeaseHandler(storageProvider storage.StorageProvider, statusManag
 			HealthScore *int   `json:"health_score"`
 			// Conditions are accepted for future use but currently ignored by the control plane.
 			Conditions []map[string]interface{} `json:"conditions"`
+			InstanceID string                   `json:"instance_id"`
 		}
 
 		if err := c.ShouldBindJSON(&payload); err != nil {
@@ -87,6 +90,9 @@ func NodeStatusLeaseHandler(storageProvider storage.StorageProvider, statusManag
 		if presenceManager != nil {
 			presenceManager.Touch(nodeID, now)
 		}
+		if payload.InstanceID != "" {
+			touchAgentInstance(ctx, storageProvider, presenceManager, nodeID, payload.InstanceID, now)
+		}
 
 		c.JSON(http.StatusOK, gin.H{
 			"lease_seconds":      int(leaseTTL.Seconds()),
@@ -95,11 +101,13 @@ func NodeStatusLeaseHandler(storageProvider storage.StorageProvider, statusManag
 	}
 }
 
-// NodeActionAckHandler acknowledges actions in push mode. Currently it only renews the lease and logs the payload.
-func NodeActionAckHandler(storageProvider storage.StorageProvider, presenceManager *services.PresenceManager, leaseTTL time.Duration) gin.HandlerFunc {
+// NodeActionAckHandler acknowledges actions handed out through ClaimActionsHandler. Terminal statuses
+// complete the underlying execution record; other statuses keep the action lease alive.
+func NodeActionAckHandler(storageProvider storage.StorageProvider, presenceManager *services.PresenceManager, payloads services.PayloadStore, webhooks services.WebhookDispatcher, leaseTTL time.Duration) gin.HandlerFunc {
 	if leaseTTL <= 0 {
 		leaseTTL = DefaultLeaseTTL
 	}
+	controller := newExecutionController(storageProvider, payloads, webhooks, 0)
 
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
@@ -110,13 +118,13 @@ func NodeActionAckHandler(storageProvider storage.StorageProvider, presenceManag
 		}
 
 		var payload struct {
-			ActionID   string                 `json:"action_id"`
-			Status     string                 `json:"status"`
-			DurationMS *int                   `json:"duration_ms"`
-			ResultRef  string                 `json:"result_ref"`
-			Result     map[string]interface{} `json:"result"`
-			Error      string                 `json:"error_message"`
-			Notes      []string               `json:"notes"`
+			ActionID   string          `json:"action_id"`
+			Status     string          `json:"status"`
+			DurationMS *int            `json:"duration_ms"`
+			ResultRef  string          `json:"result_ref"`
+			Result     json.RawMessage `json:"result"`
+			Error      string          `json:"error_message"`
+			Notes      []string        `json:"notes"`
 		}
 
 		if err := c.ShouldBindJSON(&payload); err != nil {
@@ -149,19 +157,55 @@ func NodeActionAckHandler(storageProvider storage.StorageProvider, presenceManag
 			presenceManager.Touch(nodeID, now)
 		}
 
-		c.JSON(http.StatusOK, gin.H{
+		action, err := storageProvider.GetExecutionAction(ctx, payload.ActionID)
+		if err != nil {
+			logger.Logger.Error().Err(err).Str("action_id", payload.ActionID).Msg("failed to load action during ack")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load action"})
+			return
+		}
+		if action == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "action not found"})
+			return
+		}
+		if action.NodeID != nodeID {
+			c.JSON(http.StatusConflict, gin.H{"error": "action is not assigned to this node"})
+			return
+		}
+
+		cancelled := false
+		if action.Status != types.ExecutionActionStatusCompleted {
+			ack := actionAck{
+				Status:     canonicalStatus,
+				DurationMS: payload.DurationMS,
+				Result:     payload.Result,
+				Error:      payload.Error,
+			}
+			cancelled, err = controller.completeActionFromAck(ctx, action, ack, leaseTTL)
+			if err != nil {
+				logger.Logger.Error().Err(err).Str("action_id", payload.ActionID).Msg("failed to apply action acknowledgement")
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to apply acknowledgement", "details": err.Error()})
+				return
+			}
+		}
+
+		response := gin.H{
 			"lease_seconds":      int(leaseTTL.Seconds()),
 			"next_lease_renewal": now.Add(leaseTTL).Format(time.RFC3339),
-		})
+		}
+		if cancelled {
+			response["cancelled"] = true
+		}
+		c.JSON(http.StatusOK, response)
 	}
 }
 
-// ClaimActionsHandler returns pending actions for poll-mode agents.
-// Currently the scheduler backend is under construction, so this returns an empty queue but still renews leases.
+// ClaimActionsHandler leases queued actions to poll-mode agents. When nothing is pending the request
+// long-polls for up to wait_seconds before returning an empty batch.
 func ClaimActionsHandler(storageProvider storage.StorageProvider, presenceManager *services.PresenceManager, leaseTTL time.Duration) gin.HandlerFunc {
 	if leaseTTL <= 0 {
 		leaseTTL = DefaultLeaseTTL
 	}
+	controller := newExecutionController(storageProvider, nil, nil, 0)
 
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
@@ -185,6 +229,9 @@ func ClaimActionsHandler(storageProvider storage.StorageProvider, presenceManage
 		if payload.MaxItems <= 0 {
 			payload.MaxItems = 1
 		}
+		if payload.WaitSeconds > maxClaimWaitSeconds {
+			payload.WaitSeconds = maxClaimWaitSeconds
+		}
 
 		if _, err := storageProvider.GetAgent(ctx, payload.NodeID); err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "node not found"})
@@ -199,13 +246,30 @@ func ClaimActionsHandler(storageProvider storage.StorageProvider, presenceManage
 			presenceManager.Touch(payload.NodeID, now)
 		}
 
-		nextPoll := payload.WaitSeconds
-		if nextPoll <= 0 {
-			nextPoll = 5
+		items, err := claimActions(ctx, storageProvider, payload.NodeID, payload.MaxItems, leaseTTL, time.Duration(payload.WaitSeconds)*time.Second)
+		if err != nil {
+			logger.Logger.Error().Err(err).Str("node_id", payload.NodeID).Msg("failed to claim actions")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to claim actions"})
+			return
+		}
+		delivered := make([]*types.ExecutionAction, 0, len(items))
+		for _, item := range items {
+			if controller.markActionClaimed(ctx, item) {
+				delivered = append(delivered, item)
+			}
+		}
+		items = delivered
+
+		// Workers that long-polled or received work can claim again immediately;
+		// short polls keep the previous five second cadence.
+		nextPoll := 5
+		if payload.WaitSeconds > 0 || len(items) > 0 {
+			nextPoll = 0
 		}
 
+		now = time.Now().UTC()
 		c.JSON(http.StatusOK, gin.H{
-			"items":              []interface{}{},
+			"items":              items,
 			"lease_seconds":      int(leaseTTL.Seconds()),
 			"next_poll_after":    nextPoll,
 			"next_lease_renewal": now.Add(leaseTTL).Format(time.RFC3339),
@@ -213,6 +277,41 @@ func ClaimActionsHandler(storageProvider storage.StorageProvider, presenceManage
 	}
 }
 
+// claimActions leases pending actions, waiting up to wait for new work when the queue is empty.
+func claimActions(ctx context.Context, storageProvider storage.StorageProvider, nodeID string, limit int, leaseTTL, wait time.Duration) ([]*types.ExecutionAction, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		// Subscribe before querying so an enqueue racing with the claim still wakes us.
+		signal, release := actionSignals.subscribe(nodeID)
+		items, err := storageProvider.ClaimExecutionActions(ctx, nodeID, limit, leaseTTL)
+		if err != nil || len(items) > 0 {
+			release()
+			return items, err
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			release()
+			return []*types.ExecutionAction{}, nil
+		}
+		if remaining > claimPollInterval {
+			remaining = claimPollInterval
+		}
+
+		timer := time.NewTimer(remaining)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			release()
+			return []*types.ExecutionAction{}, nil
+		case <-signal:
+		case <-timer.C:
+		}
+		timer.Stop()
+		release()
+	}
+}
+
 // NodeShutdownHandler processes graceful shutdown notifications from agents.
 func NodeShutdownHandler(storageProvider storage.StorageProvider, statusManager *services.StatusManager, presenceManager *services.PresenceManager) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -231,10 +330,20 @@ func NodeShutdownHandler(storageProvider storage.StorageProvider, statusManager
 		var payload struct {
 			Reason          string `json:"reason"`
 			ExpectedRestart string `json:"expected_restart"`
+			InstanceID      string `json:"instance_id"`
 		}
 		_ = c.ShouldBindJSON(&payload) // best-effort parse; optional fields
 
 		now := time.Now().UTC()
+		if payload.InstanceID != "" && removeAgentInstance(ctx, storageProvider, presenceManager, nodeID, payload.InstanceID) {
+			// Other replicas keep serving the node, so its status is left alone.
+			c.JSON(http.StatusAccepted, gin.H{
+				"lease_seconds":      0,
+				"next_lease_renewal": now.Format(time.RFC3339),
+				"message":            "instance shutdown acknowledged",
+			})
+			return
+		}
 		if presenceManager != nil {
 			presenceManager.Forget(nodeID)
 		}
@@ -264,6 +373,39 @@ func NodeShutdownHandler(storageProvider storage.StorageProvider, statusManager
 	}
 }
 
+// touchAgentInstance renews the lease of one replica of a node.
+func touchAgentInstance(ctx context.Context, storageProvider storage.StorageProvider, presenceManager *services.PresenceManager, nodeID, instanceID string, now time.Time) {
+	if presenceManager != nil {
+		presenceManager.TouchInstance(nodeID, instanceID, now)
+	}
+	if err := storageProvider.TouchAgentInstance(ctx, nodeID, instanceID, now); err != nil {
+		logger.Logger.Warn().Err(err).Str("node_id", nodeID).Str("instance_id", instanceID).Msg("failed to persist instance heartbeat")
+	}
+}
+
+// removeAgentInstance drops a replica that is shutting down and reports whether any
+// other live replica remains registered under the node.
+func removeAgentInstance(ctx context.Context, storageProvider storage.StorageProvider, presenceManager *services.PresenceManager, nodeID, instanceID string) bool {
+	if presenceManager != nil {
+		presenceManager.ForgetInstance(nodeID, instanceID)
+	}
+	if err := storageProvider.DeleteAgentInstance(ctx, nodeID, instanceID); err != nil {
+		logger.Logger.Warn().Err(err).Str("node_id", nodeID).Str("instance_id", instanceID).Msg("failed to remove agent instance")
+	}
+
+	remaining, err := storageProvider.ListAgentInstances(ctx, nodeID)
+	if err != nil {
+		logger.Logger.Warn().Err(err).Str("node_id", nodeID).Msg("failed to list agent instances")
+		return false
+	}
+	for _, instance := range remaining {
+		if presenceManager == nil || presenceManager.InstanceAlive(nodeID, instance.InstanceID, instance.LastHeartbeat) {
+			return true
+		}
+	}
+	return false
+}
+
 func normalizePhase(phase string) (*types.AgentState, *types.AgentLifecycleStatus, error) {
 	if phase == "" {
 		return nil, nil, nil
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+	"github.com/Agent-Field/agentfield/control-plane/internal/services"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+)
+
+const (
+	// scheduleLeaderLockKey elects the single replica that fires schedules. The lock
+	// is renewed well inside the 30 second TTL that RenewLock applies.
+	scheduleLeaderLockKey       = "scheduler-leader"
+	scheduleLeaderLockTTL       = 30 * time.Second
+	scheduleLeaderRenewInterval = 10 * time.Second
+	scheduleTickInterval        = time.Second
+	scheduleBatchSize           = 50
+)
+
+// ScheduleService fires due schedules as async executions. Every replica runs one,
+// but only the holder of the scheduler leader lock fires on a given tick.
+type ScheduleService struct {
+	store      ScheduleStore
+	controller *executionController
+	interval   time.Duration
+	stopChan   chan struct{}
+	wg         sync.WaitGroup
+	isRunning  bool
+	mu         sync.Mutex
+
+	// Leadership state, only touched by the scheduler loop.
+	leaderLock  *types.DistributedLock
+	lastRenewAt time.Time
+}
+
+// NewScheduleService creates a scheduler that submits runs through the durable execution queue.
+func NewScheduleService(store ScheduleStore, payloads services.PayloadStore, webhooks services.WebhookDispatcher, timeout time.Duration) *ScheduleService {
+	return &ScheduleService{
+		store:      store,
+		controller: newExecutionController(store, payloads, webhooks, timeout),
+		interval:   scheduleTickInterval,
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// Start begins the scheduler loop.
+func (s *ScheduleService) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isRunning {
+		return nil
+	}
+
+	s.isRunning = true
+	s.wg.Add(1)
+	go s.loop(ctx)
+	return nil
+}
+
+// Stop stops the scheduler loop and gives up leadership so another replica can take
+// over without waiting for the lock to expire.
+func (s *ScheduleService) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isRunning {
+		return nil
+	}
+
+	close(s.stopChan)
+	s.wg.Wait()
+	s.isRunning = false
+
+	s.resign(context.Background())
+	return nil
+}
+
+func (s *ScheduleService) loop(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			s.tick(ctx, time.Now())
+		}
+	}
+}
+
+// tick fires every schedule that is due at now, if this replica is the leader.
+func (s *ScheduleService) tick(ctx context.Context, now time.Time) {
+	if !s.ensureLeadership(ctx, now) {
+		return
+	}
+
+	due, err := s.store.ListDueSchedules(ctx, now, scheduleBatchSize)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("failed to list due schedules")
+		return
+	}
+	for _, schedule := range due {
+		s.fire(ctx, schedule, now)
+	}
+}
+
+// ensureLeadership acquires or renews the scheduler leader lock and reports whether
+// this replica holds it.
+func (s *ScheduleService) ensureLeadership(ctx context.Context, now time.Time) bool {
+	if s.leaderLock == nil {
+		lock, err := s.store.AcquireLock(ctx, scheduleLeaderLockKey, scheduleLeaderLockTTL)
+		if err != nil || lock == nil {
+			return false
+		}
+		logger.Logger.Info().Str("lock_id", lock.LockID).Msg("acquired scheduler leadership")
+		s.leaderLock = lock
+		s.lastRenewAt = now
+		return true
+	}
+
+	if now.Sub(s.lastRenewAt) < scheduleLeaderRenewInterval {
+		return true
+	}
+	lock, err := s.store.RenewLock(ctx, s.leaderLock.LockID)
+	if err != nil || lock == nil {
+		logger.Logger.Warn().Err(err).Msg("lost scheduler leadership")
+		s.leaderLock = nil
+		return false
+	}
+	s.leaderLock = lock
+	s.lastRenewAt = now
+	return true
+}
+
+func (s *ScheduleService) resign(ctx context.Context) {
+	if s.leaderLock == nil {
+		return
+	}
+	if err := s.store.ReleaseLock(ctx, s.leaderLock.LockID); err != nil {
+		logger.Logger.Debug().Err(err).Msg("failed to release scheduler leader lock")
+	}
+	s.leaderLock = nil
+}
+
+// fire submits one run of schedule. The next run is claimed before submitting, so a
+// leader that dies mid-fire skips that run rather than firing it twice. Ticks missed
+// while no replica was leading collapse into this single run. A schedule edited since
+// it was listed is left alone; the next tick sees the edit.
+func (s *ScheduleService) fire(ctx context.Context, schedule *types.Schedule, now time.Time) {
+	if schedule.NextRunAt == nil {
+		return
+	}
+	scheduledAt := *schedule.NextRunAt
+
+	if err := refreshNextRun(schedule, now); err != nil {
+		// The schedule was validated on write; stop firing it rather than spin.
+		schedule.Enabled = false
+		schedule.NextRunAt = nil
+		schedule.LastError = pointerString(err.Error())
+	}
+	schedule.LastRunAt = pointerTime(now.UTC())
+	claimed, err := s.store.ClaimScheduleRun(ctx, schedule, scheduledAt)
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("schedule_id", schedule.ScheduleID).Msg("failed to advance schedule")
+		return
+	}
+	if !claimed {
+		logger.Logger.Debug().Str("schedule_id", schedule.ScheduleID).Msg("schedule changed before firing; skipping")
+		return
+	}
+	if !schedule.Enabled {
+		return
+	}
+
+	executionID, err := s.submit(ctx, schedule, scheduledAt, now)
+	var lastError, lastExecutionID *string
+	if err != nil {
+		logger.Logger.Warn().Err(err).Str("schedule_id", schedule.ScheduleID).Msg("scheduled execution failed to start")
+		lastError = pointerString(err.Error())
+	}
+	if executionID != "" {
+		lastExecutionID = pointerString(executionID)
+	}
+	if err := s.store.RecordScheduleRun(ctx, schedule.ScheduleID, lastExecutionID, lastError); err != nil {
+		logger.Logger.Error().Err(err).Str("schedule_id", schedule.ScheduleID).Msg("failed to record schedule run")
+	}
+}
+
+// submit renders the schedule's input and enqueues it as an async execution linked
+// back to the schedule. The run acts for the schedule's team, so it may only reach
+// agents that team can. It returns the execution ID whenever a record was created.
+func (s *ScheduleService) submit(ctx context.Context, schedule *types.Schedule, scheduledAt, firedAt time.Time) (string, error) {
+	if schedule.TeamID != "" {
+		ctx = auth.WithPrincipal(ctx, &auth.Principal{TeamID: schedule.TeamID})
+	}
+	target, err := parseTarget(schedule.Target)
+	if err != nil {
+		return "", fmt.Errorf("invalid target: %w", err)
+	}
+	input, err := renderScheduleInput(schedule.InputTemplate, scheduleTemplateData{
+		ScheduleID:   schedule.ScheduleID,
+		ScheduleName: schedule.Name,
+		ScheduledAt:  scheduledAt.UTC().Format(time.RFC3339),
+		FiredAt:      firedAt.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req := ExecuteRequest{Input: input}
+	if schedule.Webhook != nil {
+		req.Webhook = &WebhookRequest{
+			URL:     schedule.Webhook.URL,
+			Secret:  schedule.Webhook.Secret,
+			Headers: schedule.Webhook.Headers,
+		}
+	}
+
+	scheduleID := schedule.ScheduleID
+	plan, err := s.controller.prepareSubmission(ctx, executionSubmission{
+		target:     target,
+		request:    req,
+		scheduleID: &scheduleID,
+	}, true)
+	if err != nil {
+		return "", err
+	}
+	// Scheduled runs share the caller limits of their schedule.
+	plan.caller = "schedule:" + scheduleID
+	if err := s.controller.submitQueued(ctx, plan); err != nil {
+		return plan.exec.ExecutionID, err
+	}
+	return plan.exec.ExecutionID, nil
+}
@@ -0,0 +1,489 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
+	"github.com/Agent-Field/agentfield/control-plane/internal/cron"
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+	"github.com/Agent-Field/agentfield/control-plane/internal/utils"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultScheduleRunsLimit = 50
+	maxScheduleRunsLimit     = 500
+)
+
+// ScheduleStore captures the storage operations required by the schedule handlers
+// and the scheduler that fires them.
+type ScheduleStore interface {
+	ExecutionStore
+	CreateSchedule(ctx context.Context, schedule *types.Schedule) error
+	GetSchedule(ctx context.Context, scheduleID string) (*types.Schedule, error)
+	ListSchedules(ctx context.Context) ([]*types.Schedule, error)
+	UpdateSchedule(ctx context.Context, schedule *types.Schedule) error
+	ClaimScheduleRun(ctx context.Context, schedule *types.Schedule, dueAt time.Time) (bool, error)
+	RecordScheduleRun(ctx context.Context, scheduleID string, executionID, lastError *string) error
+	DeleteSchedule(ctx context.Context, scheduleID string) error
+	ListDueSchedules(ctx context.Context, now time.Time, limit int) ([]*types.Schedule, error)
+}
+
+// CreateScheduleRequest defines a new schedule.
+type CreateScheduleRequest struct {
+	Name           string                 `json:"name"`
+	CronExpression string                 `json:"cron_expression"`
+	Timezone       string                 `json:"timezone,omitempty"`
+	Target         string                 `json:"target"`
+	InputTemplate  map[string]interface{} `json:"input_template"`
+	Webhook        *WebhookRequest        `json:"webhook,omitempty"`
+	Enabled        *bool                  `json:"enabled,omitempty"`
+}
+
+// UpdateScheduleRequest changes an existing schedule. Omitted fields keep their
+// current values.
+type UpdateScheduleRequest struct {
+	Name           *string                `json:"name,omitempty"`
+	CronExpression *string                `json:"cron_expression,omitempty"`
+	Timezone       *string                `json:"timezone,omitempty"`
+	Target         *string                `json:"target,omitempty"`
+	InputTemplate  map[string]interface{} `json:"input_template,omitempty"`
+	Webhook        *WebhookRequest        `json:"webhook,omitempty"`
+	RemoveWebhook  bool                   `json:"remove_webhook,omitempty"`
+	Enabled        *bool                  `json:"enabled,omitempty"`
+}
+
+// ScheduleRunsResponse lists the executions a schedule has fired, newest first.
+type ScheduleRunsResponse struct {
+	ScheduleID string                    `json:"schedule_id"`
+	Runs       []ExecutionStatusResponse `json:"runs"`
+	Total      int                       `json:"total"`
+}
+
+// scheduleTemplateData is available to string values in a schedule's input template.
+type scheduleTemplateData struct {
+	ScheduleID   string
+	ScheduleName string
+	ScheduledAt  string
+	FiredAt      string
+}
+
+// CreateScheduleHandler creates a schedule owned by the caller's team, which must be
+// able to reach the target.
+// POST /api/v1/schedules
+func CreateScheduleHandler(store ScheduleStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		var req CreateScheduleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid request body: %v", err)})
+			return
+		}
+
+		schedule := &types.Schedule{
+			ScheduleID:     utils.GenerateScheduleID(),
+			Name:           strings.TrimSpace(req.Name),
+			CronExpression: strings.TrimSpace(req.CronExpression),
+			TeamID:         auth.TeamFrom(ctx),
+			Timezone:       strings.TrimSpace(req.Timezone),
+			Target:         strings.TrimSpace(req.Target),
+			Enabled:        req.Enabled == nil || *req.Enabled,
+		}
+		if err := applyScheduleInput(schedule, req.InputTemplate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := applyScheduleWebhook(schedule, req.Webhook); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := validateSchedule(schedule); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if !requireScheduleTarget(c, store, schedule) {
+			return
+		}
+		if err := refreshNextRun(schedule, time.Now()); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := store.CreateSchedule(ctx, schedule); err != nil {
+			logger.Logger.Error().Err(err).Str("schedule_id", schedule.ScheduleID).Msg("failed to create schedule")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create schedule"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, renderSchedule(schedule))
+	}
+}
+
+// ListSchedulesHandler lists the schedules of the caller's team.
+// GET /api/v1/schedules
+func ListSchedulesHandler(store ScheduleStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		schedules, err := store.ListSchedules(ctx)
+		if err != nil {
+			logger.Logger.Error().Err(err).Msg("failed to list schedules")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list schedules"})
+			return
+		}
+
+		rendered := make([]*types.Schedule, 0, len(schedules))
+		for _, schedule := range schedules {
+			if auth.CanAccessTeam(ctx, schedule.TeamID) {
+				rendered = append(rendered, renderSchedule(schedule))
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"schedules": rendered, "total": len(rendered)})
+	}
+}
+
+// GetScheduleHandler returns a single schedule.
+// GET /api/v1/schedules/:schedule_id
+func GetScheduleHandler(store ScheduleStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schedule, ok := loadSchedule(c, store)
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, renderSchedule(schedule))
+	}
+}
+
+// UpdateScheduleHandler changes a schedule. The next run is recomputed whenever the
+// cron expression, time zone or enabled flag changes.
+// PATCH /api/v1/schedules/:schedule_id
+func UpdateScheduleHandler(store ScheduleStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req UpdateScheduleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid request body: %v", err)})
+			return
+		}
+		if req.RemoveWebhook && req.Webhook != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "webhook and remove_webhook are mutually exclusive"})
+			return
+		}
+
+		schedule, ok := loadSchedule(c, store)
+		if !ok {
+			return
+		}
+
+		timingChanged := false
+		if req.Name != nil {
+			schedule.Name = strings.TrimSpace(*req.Name)
+		}
+		if req.CronExpression != nil {
+			schedule.CronExpression = strings.TrimSpace(*req.CronExpression)
+			timingChanged = true
+		}
+		if req.Timezone != nil {
+			schedule.Timezone = strings.TrimSpace(*req.Timezone)
+			timingChanged = true
+		}
+		targetChanged := false
+		if req.Target != nil {
+			schedule.Target = strings.TrimSpace(*req.Target)
+			targetChanged = true
+		}
+		if req.Enabled != nil && *req.Enabled != schedule.Enabled {
+			schedule.Enabled = *req.Enabled
+			timingChanged = true
+		}
+		if req.InputTemplate != nil {
+			if err := applyScheduleInput(schedule, req.InputTemplate); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		if req.RemoveWebhook {
+			schedule.Webhook = nil
+		} else if req.Webhook != nil {
+			if err := applyScheduleWebhook(schedule, req.Webhook); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		if err := validateSchedule(schedule); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if targetChanged && !requireScheduleTarget(c, store, schedule) {
+			return
+		}
+		if timingChanged {
+			if err := refreshNextRun(schedule, time.Now()); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		if err := store.UpdateSchedule(c.Request.Context(), schedule); err != nil {
+			logger.Logger.Error().Err(err).Str("schedule_id", schedule.ScheduleID).Msg("failed to update schedule")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update schedule"})
+			return
+		}
+
+		c.JSON(http.StatusOK, renderSchedule(schedule))
+	}
+}
+
+// DeleteScheduleHandler deletes a schedule. Executions it already fired are kept.
+// DELETE /api/v1/schedules/:schedule_id
+func DeleteScheduleHandler(store ScheduleStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schedule, ok := loadSchedule(c, store)
+		if !ok {
+			return
+		}
+		if err := store.DeleteSchedule(c.Request.Context(), schedule.ScheduleID); err != nil {
+			logger.Logger.Error().Err(err).Str("schedule_id", schedule.ScheduleID).Msg("failed to delete schedule")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete schedule"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"schedule_id": schedule.ScheduleID, "deleted": true})
+	}
+}
+
+// ListScheduleRunsHandler lists the executions fired by a schedule, newest first.
+// GET /api/v1/schedules/:schedule_id/runs?limit=50
+func ListScheduleRunsHandler(store ScheduleStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schedule, ok := loadSchedule(c, store)
+		if !ok {
+			return
+		}
+
+		limit := defaultScheduleRunsLimit
+		if raw := c.Query("limit"); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+				return
+			}
+			limit = parsed
+		}
+		if limit > maxScheduleRunsLimit {
+			limit = maxScheduleRunsLimit
+		}
+
+		scheduleID := schedule.ScheduleID
+		records, err := store.QueryExecutionRecords(c.Request.Context(), types.ExecutionFilter{
+			ScheduleID:     &scheduleID,
+			Limit:          limit,
+			SortBy:         "started_at",
+			SortDescending: true,
+		})
+		if err != nil {
+			logger.Logger.Error().Err(err).Str("schedule_id", scheduleID).Msg("failed to list schedule runs")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list schedule runs"})
+			return
+		}
+
+		runs := make([]ExecutionStatusResponse, 0, len(records))
+		for _, record := range records {
+			runs = append(runs, renderStatus(record))
+		}
+		c.JSON(http.StatusOK, ScheduleRunsResponse{ScheduleID: scheduleID, Runs: runs, Total: len(runs)})
+	}
+}
+
+// loadSchedule fetches the schedule named in the path. Schedules of other teams are
+// reported as missing.
+func loadSchedule(c *gin.Context, store ScheduleStore) (*types.Schedule, bool) {
+	scheduleID := c.Param("schedule_id")
+	if scheduleID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "schedule_id is required"})
+		return nil, false
+	}
+	schedule, err := store.GetSchedule(c.Request.Context(), scheduleID)
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("schedule_id", scheduleID).Msg("failed to load schedule")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load schedule"})
+		return nil, false
+	}
+	if schedule == nil || !auth.CanAccessTeam(c.Request.Context(), schedule.TeamID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("schedule %s not found", scheduleID)})
+		return nil, false
+	}
+	return schedule, true
+}
+
+// requireScheduleTarget rejects a schedule whose target agent is outside the caller's
+// team.
+func requireScheduleTarget(c *gin.Context, store ScheduleStore, schedule *types.Schedule) bool {
+	target, err := parseTarget(schedule.Target)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid target: %v", err)})
+		return false
+	}
+	if !agentNodeVisible(c.Request.Context(), store, target.NodeID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("agent '%s' not found", target.NodeID)})
+		return false
+	}
+	return true
+}
+
+func applyScheduleInput(schedule *types.Schedule, input map[string]interface{}) error {
+	if len(input) == 0 {
+		return errors.New("input_template is required")
+	}
+	encoded, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("encode input_template: %w", err)
+	}
+	schedule.InputTemplate = encoded
+	return nil
+}
+
+func applyScheduleWebhook(schedule *types.Schedule, req *WebhookRequest) error {
+	if req == nil {
+		return nil
+	}
+	cfg, err := normalizeWebhookRequest(req)
+	if err != nil {
+		return err
+	}
+	webhook := &types.ScheduleWebhook{URL: cfg.URL, Headers: cfg.Headers}
+	switch {
+	case cfg.Secret != nil:
+		webhook.Secret = *cfg.Secret
+	case schedule.Webhook != nil:
+		// Secrets are never returned, so an update that omits one keeps the current secret.
+		webhook.Secret = schedule.Webhook.Secret
+	}
+	schedule.Webhook = webhook
+	return nil
+}
+
+func validateSchedule(schedule *types.Schedule) error {
+	if schedule.Name == "" {
+		return errors.New("name is required")
+	}
+	if _, err := parseTarget(schedule.Target); err != nil {
+		return fmt.Errorf("invalid target: %w", err)
+	}
+	if _, err := cron.Parse(schedule.CronExpression); err != nil {
+		return fmt.Errorf("invalid cron_expression: %w", err)
+	}
+	if _, err := scheduleLocation(schedule.Timezone); err != nil {
+		return err
+	}
+	// Render once with placeholder values so template errors surface at write time.
+	if _, err := renderScheduleInput(schedule.InputTemplate, scheduleTemplateData{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func scheduleLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+	}
+	return loc, nil
+}
+
+// refreshNextRun sets the schedule's next fire time after now. Disabled schedules, and
+// expressions that never fire, have no next run.
+func refreshNextRun(schedule *types.Schedule, now time.Time) error {
+	schedule.NextRunAt = nil
+	if !schedule.Enabled {
+		return nil
+	}
+	expr, err := cron.Parse(schedule.CronExpression)
+	if err != nil {
+		return fmt.Errorf("invalid cron_expression: %w", err)
+	}
+	loc, err := scheduleLocation(schedule.Timezone)
+	if err != nil {
+		return err
+	}
+	if next := expr.Next(now.In(loc)); !next.IsZero() {
+		schedule.NextRunAt = pointerTime(next.UTC())
+	}
+	return nil
+}
+
+// renderScheduleInput expands Go templates in the string values of a schedule's
+// input template.
+func renderScheduleInput(raw json.RawMessage, data scheduleTemplateData) (map[string]interface{}, error) {
+	var input map[string]interface{}
+	if err := json.Unmarshal(raw, &input); err != nil {
+		return nil, fmt.Errorf("input_template must be a JSON object: %w", err)
+	}
+	rendered, err := renderTemplateValue(input, data)
+	if err != nil {
+		return nil, err
+	}
+	return rendered.(map[string]interface{}), nil
+}
+
+func renderTemplateValue(value interface{}, data scheduleTemplateData) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			rendered, err := renderTemplateValue(item, data)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = rendered
+		}
+		return v, nil
+	case []interface{}:
+		for i, item := range v {
+			rendered, err := renderTemplateValue(item, data)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = rendered
+		}
+		return v, nil
+	case string:
+		if !strings.Contains(v, "{{") {
+			return v, nil
+		}
+		tmpl, err := template.New("input").Option("missingkey=error").Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input_template value %q: %w", v, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("invalid input_template value %q: %w", v, err)
+		}
+		return buf.String(), nil
+	default:
+		return v, nil
+	}
+}
+
+// renderSchedule returns a copy of schedule that is safe to return to clients.
+func renderSchedule(schedule *types.Schedule) *types.Schedule {
+	copied := *schedule
+	if schedule.Webhook != nil {
+		copied.Webhook = &types.ScheduleWebhook{
+			URL:       schedule.Webhook.URL,
+			Headers:   schedule.Webhook.Headers,
+			HasSecret: schedule.Webhook.Secret != "",
+		}
+	}
+	return &copied
+}
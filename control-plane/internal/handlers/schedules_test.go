@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/services"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func newScheduleTestRouter(store *testExecutionStorage) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/schedules", CreateScheduleHandler(store))
+	router.GET("/api/v1/schedules", ListSchedulesHandler(store))
+	router.GET("/api/v1/schedules/:schedule_id", GetScheduleHandler(store))
+	router.PATCH("/api/v1/schedules/:schedule_id", UpdateScheduleHandler(store))
+	router.DELETE("/api/v1/schedules/:schedule_id", DeleteScheduleHandler(store))
+	router.GET("/api/v1/schedules/:schedule_id/runs", ListScheduleRunsHandler(store))
+	return router
+}
+
+func doScheduleRequest(t *testing.T, router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestScheduleHandlers_CRUD(t *testing.T) {
+	store := newTestExecutionStorage(nil)
+	router := newScheduleTestRouter(store)
+
+	resp := doScheduleRequest(t, router, http.MethodPost, "/api/v1/schedules", `{
+		"name": "nightly",
+		"cron_expression": "0 2 * * *",
+		"timezone": "UTC",
+		"target": "node-1.report",
+		"input_template": {"day": "{{.ScheduledAt}}"},
+		"webhook": {"url": "https://example.com/hook", "secret": "s3cret"}
+	}`)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	require.NotContains(t, resp.Body.String(), "s3cret")
+
+	var created types.Schedule
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+	require.True(t, strings.HasPrefix(created.ScheduleID, "sched_"))
+	require.True(t, created.Enabled)
+	require.NotNil(t, created.NextRunAt)
+	require.Equal(t, 2, created.NextRunAt.Hour())
+	require.True(t, created.Webhook.HasSecret)
+
+	path := "/api/v1/schedules/" + created.ScheduleID
+	resp = doScheduleRequest(t, router, http.MethodPatch, path, `{"enabled": false, "webhook": {"url": "https://example.com/other"}}`)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var updated types.Schedule
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &updated))
+	require.False(t, updated.Enabled)
+	require.Nil(t, updated.NextRunAt)
+
+	stored, err := store.GetSchedule(context.Background(), created.ScheduleID)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/other", stored.Webhook.URL)
+	require.Equal(t, "s3cret", stored.Webhook.Secret)
+
+	resp = doScheduleRequest(t, router, http.MethodGet, "/api/v1/schedules", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Contains(t, resp.Body.String(), `"total":1`)
+
+	resp = doScheduleRequest(t, router, http.MethodDelete, path, "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	resp = doScheduleRequest(t, router, http.MethodGet, path, "")
+	require.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestScheduleHandlers_RejectInvalidSchedules(t *testing.T) {
+	router := newScheduleTestRouter(newTestExecutionStorage(nil))
+
+	cases := map[string]string{
+		"invalid cron_expression":      `{"name":"a","cron_expression":"61 * * * *","target":"node-1.r","input_template":{"x":1}}`,
+		"invalid target":               `{"name":"a","cron_expression":"@hourly","target":"node-1","input_template":{"x":1}}`,
+		"invalid timezone":             `{"name":"a","cron_expression":"@hourly","timezone":"Mars/Olympus","target":"node-1.r","input_template":{"x":1}}`,
+		"input_template is required":   `{"name":"a","cron_expression":"@hourly","target":"node-1.r"}`,
+		"invalid input_template value": `{"name":"a","cron_expression":"@hourly","target":"node-1.r","input_template":{"x":"{{.Nope}}"}}`,
+	}
+	for want, body := range cases {
+		resp := doScheduleRequest(t, router, http.MethodPost, "/api/v1/schedules", body)
+		require.Equal(t, http.StatusBadRequest, resp.Code, want)
+		require.Contains(t, resp.Body.String(), want)
+	}
+}
+
+func TestScheduleService_FiresDueScheduleOnLeaderOnly(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []map[string]interface{}
+	)
+	agentServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		bodies = append(bodies, body)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer agentServer.Close()
+
+	agent := &types.AgentNode{
+		ID:        "node-1",
+		BaseURL:   agentServer.URL,
+		Reasoners: []types.ReasonerDefinition{{ID: "report"}},
+	}
+	store := newTestExecutionStorage(agent)
+	payloads := services.NewFilePayloadStore(t.TempDir())
+	t.Cleanup(func() { StopExecutionQueue(store) })
+
+	scheduledAt := time.Date(2024, 3, 15, 2, 0, 0, 0, time.UTC)
+	require.NoError(t, store.CreateSchedule(context.Background(), &types.Schedule{
+		ScheduleID:     "sched-1",
+		Name:           "nightly",
+		CronExpression: "0 2 * * *",
+		Target:         "node-1.report",
+		InputTemplate:  json.RawMessage(`{"day":"{{.ScheduledAt}}","source":"{{.ScheduleName}}"}`),
+		Enabled:        true,
+		NextRunAt:      &scheduledAt,
+	}))
+
+	leader := NewScheduleService(store, payloads, nil, 5*time.Second)
+	follower := NewScheduleService(store, payloads, nil, 5*time.Second)
+	now := scheduledAt.Add(90 * time.Minute)
+
+	leader.tick(context.Background(), now)
+	follower.tick(context.Background(), now)
+	require.NotNil(t, leader.leaderLock)
+	require.Nil(t, follower.leaderLock)
+
+	schedule, err := store.GetSchedule(context.Background(), "sched-1")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), schedule.RunCount)
+	require.Nil(t, schedule.LastError)
+	require.NotNil(t, schedule.LastExecutionID)
+	require.Equal(t, time.Date(2024, 3, 16, 2, 0, 0, 0, time.UTC), *schedule.NextRunAt)
+
+	require.Eventually(t, func() bool {
+		record, err := store.GetExecutionRecord(context.Background(), *schedule.LastExecutionID)
+		return err == nil && record != nil && record.Status == types.ExecutionStatusSucceeded
+	}, 3*time.Second, 20*time.Millisecond)
+
+	record, err := store.GetExecutionRecord(context.Background(), *schedule.LastExecutionID)
+	require.NoError(t, err)
+	require.NotNil(t, record.ScheduleID)
+	require.Equal(t, "sched-1", *record.ScheduleID)
+
+	mu.Lock()
+	require.Len(t, bodies, 1)
+	require.Equal(t, "2024-03-15T02:00:00Z", bodies[0]["day"])
+	require.Equal(t, "nightly", bodies[0]["source"])
+	mu.Unlock()
+
+	// The runs endpoint lists executions linked to the schedule.
+	router := newScheduleTestRouter(store)
+	resp := doScheduleRequest(t, router, http.MethodGet, "/api/v1/schedules/sched-1/runs", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var runs ScheduleRunsResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &runs))
+	require.Equal(t, 1, runs.Total)
+	require.Equal(t, *schedule.LastExecutionID, runs.Runs[0].ExecutionID)
+
+	// Leadership passes to another replica once the leader resigns.
+	leader.resign(context.Background())
+	require.True(t, follower.ensureLeadership(context.Background(), now))
+}
+
+func TestScheduleService_RecordsSubmitErrors(t *testing.T) {
+	store := newTestExecutionStorage(nil)
+	service := NewScheduleService(store, services.NewFilePayloadStore(t.TempDir()), nil, 5*time.Second)
+
+	due := time.Now().Add(-time.Minute)
+	require.NoError(t, store.CreateSchedule(context.Background(), &types.Schedule{
+		ScheduleID:     "sched-missing-agent",
+		Name:           "orphan",
+		CronExpression: "@hourly",
+		Target:         "ghost.report",
+		InputTemplate:  json.RawMessage(`{"x":1}`),
+		Enabled:        true,
+		NextRunAt:      &due,
+	}))
+
+	service.tick(context.Background(), time.Now())
+
+	schedule, err := store.GetSchedule(context.Background(), "sched-missing-agent")
+	require.NoError(t, err)
+	require.NotNil(t, schedule.LastError)
+	require.Contains(t, *schedule.LastError, "agent 'ghost' not found")
+	require.True(t, schedule.NextRunAt.After(time.Now()))
+	require.Nil(t, schedule.LastExecutionID)
+}
+
+func TestScheduleService_SkipsScheduleEditedBeforeFiring(t *testing.T) {
+	store := newTestExecutionStorage(nil)
+	service := NewScheduleService(store, services.NewFilePayloadStore(t.TempDir()), nil, 5*time.Second)
+
+	due := time.Now().Add(-time.Minute)
+	require.NoError(t, store.CreateSchedule(context.Background(), &types.Schedule{
+		ScheduleID:     "sched-1",
+		Name:           "nightly",
+		CronExpression: "@hourly",
+		Target:         "node-1.report",
+		InputTemplate:  json.RawMessage(`{"x":1}`),
+		Enabled:        true,
+		NextRunAt:      &due,
+	}))
+	listed, err := store.ListDueSchedules(context.Background(), time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+
+	edited, err := store.GetSchedule(context.Background(), "sched-1")
+	require.NoError(t, err)
+	edited.Enabled = false
+	require.NoError(t, store.UpdateSchedule(context.Background(), edited))
+
+	service.fire(context.Background(), listed[0], time.Now())
+
+	schedule, err := store.GetSchedule(context.Background(), "sched-1")
+	require.NoError(t, err)
+	require.False(t, schedule.Enabled, "the stale copy does not undo the edit")
+	require.Nil(t, schedule.LastRunAt)
+	require.Zero(t, schedule.RunCount)
+}
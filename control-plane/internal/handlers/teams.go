@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+)
+
+// visibleAgents drops the agents outside the caller's team.
+func visibleAgents(ctx context.Context, agents []*types.AgentNode) []*types.AgentNode {
+	team := auth.TeamFrom(ctx)
+	if team == "" {
+		return agents
+	}
+	visible := make([]*types.AgentNode, 0, len(agents))
+	for _, agent := range agents {
+		if agent != nil && agent.TeamID == team {
+			visible = append(visible, agent)
+		}
+	}
+	return visible
+}
+
+// assignCallerTeam confines a node being registered to the caller's team. It fails when
+// the node claims a different team.
+func assignCallerTeam(ctx context.Context, teamID *string) error {
+	team := auth.TeamFrom(ctx)
+	if team == "" {
+		return nil
+	}
+	if *teamID != "" && *teamID != team {
+		return fmt.Errorf("API key for team '%s' cannot register nodes for team '%s'", team, *teamID)
+	}
+	*teamID = team
+	return nil
+}
+
+// serverlessTeam is the team a serverless agent is registered under: the caller's team,
+// or "default" for callers not confined to one.
+func serverlessTeam(ctx context.Context) string {
+	if team := auth.TeamFrom(ctx); team != "" {
+		return team
+	}
+	return "default"
+}
+
+// executionVisible reports whether the caller's team owns the agent an execution ran
+// on. Executions of agents that no longer exist are only visible to unconfined callers.
+func executionVisible(ctx context.Context, store ExecutionStore, exec *types.Execution) bool {
+	return agentNodeVisible(ctx, store, exec.AgentNodeID)
+}
+
+// agentNodeVisible reports whether the caller's team owns the agent node nodeID.
+func agentNodeVisible(ctx context.Context, store ExecutionStore, nodeID string) bool {
+	if auth.TeamFrom(ctx) == "" {
+		return true
+	}
+	agent, err := store.GetAgent(ctx, nodeID)
+	if err != nil || agent == nil {
+		return false
+	}
+	return auth.CanAccessTeam(ctx, agent.TeamID)
+}
+
+// teamMemoryScopeID namespaces a memory scope ID by the caller's team so teams sharing
+// a control plane never read each other's memory.
+func teamMemoryScopeID(ctx context.Context, scopeID string) string {
+	if team := auth.TeamFrom(ctx); team != "" && scopeID != "" {
+		return "team:" + team + ":" + scopeID
+	}
+	return scopeID
+}
+
+// memoryEventVisible reports whether a memory change event belongs to the caller's team.
+func memoryEventVisible(ctx context.Context, event *types.MemoryChangeEvent) bool {
+	return memoryScopeIDVisible(ctx, event.ScopeID)
+}
+
+// memoryScopeIDVisible reports whether a stored memory scope ID belongs to the caller's
+// team.
+func memoryScopeIDVisible(ctx context.Context, scopeID string) bool {
+	team := auth.TeamFrom(ctx)
+	return team == "" || strings.HasPrefix(scopeID, "team:"+team+":")
+}
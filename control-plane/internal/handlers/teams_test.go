@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
+	"github.com/Agent-Field/agentfield/control-plane/internal/services"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func withTestPrincipal(teamID string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := &auth.Principal{KeyID: "key-" + teamID, TeamID: teamID, Role: types.APIKeyRoleOperator}
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+func TestExecuteHandler_HidesAgentsOfOtherTeams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	agent := &types.AgentNode{
+		ID:        "node-1",
+		TeamID:    "team-a",
+		BaseURL:   "http://127.0.0.1:1",
+		Reasoners: []types.ReasonerDefinition{{ID: "reasoner-a"}},
+	}
+	store := newTestExecutionStorage(agent)
+	router := gin.New()
+	router.Use(withTestPrincipal("team-b"))
+	router.POST("/api/v1/execute/:target", ExecuteHandler(store, services.NewFilePayloadStore(t.TempDir()), nil, 0))
+
+	resp := postExecute(t, router, `{"input":{"n":1}}`)
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+	require.Contains(t, resp.Body.String(), "agent 'node-1' not found", "other teams' agents look unregistered")
+	require.Empty(t, store.executionRecords)
+}
+
+func TestExecutionStatus_HidesExecutionsOfOtherTeams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := newTestExecutionStorage(&types.AgentNode{ID: "node-1", TeamID: "team-a"})
+	require.NoError(t, store.CreateExecutionRecord(context.Background(), &types.Execution{
+		ExecutionID: "exec-1",
+		RunID:       "run-1",
+		AgentNodeID: "node-1",
+		ReasonerID:  "reasoner-a",
+		Status:      types.ExecutionStatusRunning,
+	}))
+
+	for team, want := range map[string]int{"team-a": http.StatusOK, "team-b": http.StatusNotFound} {
+		router := gin.New()
+		router.Use(withTestPrincipal(team))
+		router.GET("/api/v1/executions/:execution_id", GetExecutionStatusHandler(store))
+
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/executions/exec-1", nil))
+		require.Equal(t, want, resp.Code, "team %s", team)
+	}
+}
+
+func TestMemoryHandlers_IsolateTeams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	storage := newMemoryStorageStub()
+
+	serve := func(team, path, body string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(withTestPrincipal(team))
+		router.POST("/memory/set", SetMemoryHandler(storage))
+		router.POST("/memory/get", GetMemoryHandler(storage))
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Workflow-ID", "wf-123")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	require.Equal(t, http.StatusOK, serve("team-a", "/memory/set", `{"key":"alpha","data":1}`).Code)
+	_, err := storage.GetMemory(context.Background(), "workflow", "team:team-a:wf-123", "alpha")
+	require.NoError(t, err, "memory is stored under the team's namespace")
+
+	require.Equal(t, http.StatusOK, serve("team-a", "/memory/get", `{"key":"alpha"}`).Code)
+	require.Equal(t, http.StatusNotFound, serve("team-b", "/memory/get", `{"key":"alpha"}`).Code)
+}
+
+func TestScheduleHandlers_ScopedToCallerTeam(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := newTestExecutionStorage(&types.AgentNode{ID: "node-1", TeamID: "team-a"})
+
+	serve := func(team, method, path, body string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(withTestPrincipal(team))
+		router.POST("/api/v1/schedules", CreateScheduleHandler(store))
+		router.GET("/api/v1/schedules", ListSchedulesHandler(store))
+		router.GET("/api/v1/schedules/:schedule_id", GetScheduleHandler(store))
+		router.PATCH("/api/v1/schedules/:schedule_id", UpdateScheduleHandler(store))
+		router.DELETE("/api/v1/schedules/:schedule_id", DeleteScheduleHandler(store))
+		return doScheduleRequest(t, router, method, path, body)
+	}
+
+	body := `{"name":"nightly","cron_expression":"@daily","target":"node-1.report","input_template":{"x":1},"webhook":{"url":"https://example.com/hook","secret":"s3cret"}}`
+	resp := serve("team-b", http.MethodPost, "/api/v1/schedules", body)
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+	require.Contains(t, resp.Body.String(), "agent 'node-1' not found")
+
+	resp = serve("team-a", http.MethodPost, "/api/v1/schedules", body)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	var created types.Schedule
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+	require.Equal(t, "team-a", created.TeamID)
+
+	path := "/api/v1/schedules/" + created.ScheduleID
+	require.Contains(t, serve("team-b", http.MethodGet, "/api/v1/schedules", "").Body.String(), `"total":0`)
+	require.Contains(t, serve("team-a", http.MethodGet, "/api/v1/schedules", "").Body.String(), `"total":1`)
+	require.Equal(t, http.StatusNotFound, serve("team-b", http.MethodGet, path, "").Code)
+	require.Equal(t, http.StatusNotFound, serve("team-b", http.MethodPatch, path, `{"name":"taken"}`).Code)
+	require.Equal(t, http.StatusNotFound, serve("team-b", http.MethodDelete, path, "").Code)
+
+	resp = serve("team-a", http.MethodPatch, path, `{"target":"node-2.report"}`)
+	require.Equal(t, http.StatusBadRequest, resp.Code, "a team cannot retarget a schedule outside its agents")
+	require.Equal(t, http.StatusOK, serve("team-a", http.MethodDelete, path, "").Code)
+}
+
+func TestScheduleService_FiresForScheduleTeam(t *testing.T) {
+	store := newTestExecutionStorage(&types.AgentNode{
+		ID:        "node-1",
+		TeamID:    "team-a",
+		BaseURL:   "http://127.0.0.1:1",
+		Reasoners: []types.ReasonerDefinition{{ID: "report"}},
+	})
+	service := NewScheduleService(store, services.NewFilePayloadStore(t.TempDir()), nil, 5*time.Second)
+
+	due := time.Now().Add(-time.Minute)
+	for _, team := range []string{"team-a", "team-b"} {
+		require.NoError(t, store.CreateSchedule(context.Background(), &types.Schedule{
+			ScheduleID:     "sched-" + team,
+			Name:           team,
+			CronExpression: "@hourly",
+			TeamID:         team,
+			Target:         "node-1.report",
+			InputTemplate:  json.RawMessage(`{"x":1}`),
+			Enabled:        true,
+			NextRunAt:      &due,
+		}))
+	}
+
+	service.tick(context.Background(), time.Now())
+
+	owned, err := store.GetSchedule(context.Background(), "sched-team-a")
+	require.NoError(t, err)
+	require.Nil(t, owned.LastError)
+	require.NotNil(t, owned.LastExecutionID)
+
+	foreign, err := store.GetSchedule(context.Background(), "sched-team-b")
+	require.NoError(t, err)
+	require.NotNil(t, foreign.LastError)
+	require.Contains(t, *foreign.LastError, "agent 'node-1' not found")
+	require.Nil(t, foreign.LastExecutionID)
+}
+
+func TestWorkflowDAG_HidesRunsOfOtherTeams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	provider, ctx := setupTestStorage(t)
+	require.NoError(t, provider.RegisterAgent(ctx, &types.AgentNode{ID: "node-1", TeamID: "team-a"}))
+	require.NoError(t, provider.CreateExecutionRecord(ctx, &types.Execution{
+		ExecutionID: "exec-1",
+		RunID:       "run-1",
+		AgentNodeID: "node-1",
+		ReasonerID:  "reasoner-a",
+		Status:      types.ExecutionStatusSucceeded,
+		StartedAt:   time.Now().UTC(),
+	}))
+
+	for team, want := range map[string]int{"team-a": http.StatusOK, "team-b": http.StatusNotFound} {
+		router := gin.New()
+		router.Use(withTestPrincipal(team))
+		router.GET("/api/ui/v1/workflows/:workflowId/dag", GetWorkflowDAGHandler(provider))
+
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/ui/v1/workflows/run-1/dag", nil))
+		require.Equal(t, want, resp.Code, "team %s: %s", team, resp.Body.String())
+	}
+}
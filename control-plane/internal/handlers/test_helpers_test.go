@@ -3,7 +3,9 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/Agent-Field/agentfield/control-plane/internal/events"
 	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
@@ -12,11 +14,18 @@ import (
 type testExecutionStorage struct {
 	mu                        sync.Mutex
 	agent                     *types.AgentNode
+	instances                 []*types.AgentInstance
 	workflowExecutions        map[string]*types.WorkflowExecution
 	executionRecords          map[string]*types.Execution
 	runs                      map[string]*types.WorkflowRun
 	steps                     map[string]*types.WorkflowStep
 	webhooks                  map[string]*types.ExecutionWebhook
+	actions                   map[string]*types.ExecutionAction
+	queued                    map[string]*types.QueuedExecution
+	locks                     map[string]*types.DistributedLock
+	schedules                 map[string]*types.Schedule
+	memory                    map[string]*types.Memory
+	cachedResults             map[string][]byte
 	eventBus                  *events.ExecutionEventBus
 	workflowExecutionEventBus *events.EventBus[*types.WorkflowExecutionEvent]
 	workflowRunEventBus       *events.EventBus[*types.WorkflowRunEvent]
@@ -31,6 +40,12 @@ func newTestExecutionStorage(agent *types.AgentNode) *testExecutionStorage {
 		runs:                      make(map[string]*types.WorkflowRun),
 		steps:                     make(map[string]*types.WorkflowStep),
 		webhooks:                  make(map[string]*types.ExecutionWebhook),
+		actions:                   make(map[string]*types.ExecutionAction),
+		queued:                    make(map[string]*types.QueuedExecution),
+		locks:                     make(map[string]*types.DistributedLock),
+		schedules:                 make(map[string]*types.Schedule),
+		memory:                    make(map[string]*types.Memory),
+		cachedResults:             make(map[string][]byte),
 		eventBus:                  events.NewExecutionEventBus(),
 		workflowExecutionEventBus: events.NewEventBus[*types.WorkflowExecutionEvent](),
 		workflowRunEventBus:       events.NewEventBus[*types.WorkflowRunEvent](),
@@ -45,6 +60,19 @@ func (s *testExecutionStorage) GetAgent(ctx context.Context, id string) (*types.
 	return nil, nil
 }
 
+func (s *testExecutionStorage) ListAgentInstances(ctx context.Context, nodeID string) ([]*types.AgentInstance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var instances []*types.AgentInstance
+	for _, instance := range s.instances {
+		if instance.NodeID == nodeID {
+			copied := *instance
+			instances = append(instances, &copied)
+		}
+	}
+	return instances, nil
+}
+
 func (s *testExecutionStorage) StoreWorkflowExecution(ctx context.Context, execution *types.WorkflowExecution) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -272,8 +300,274 @@ func (s *testExecutionStorage) QueryExecutionRecords(ctx context.Context, filter
 		if filter.RunID != nil && *filter.RunID != exec.RunID {
 			continue
 		}
+		if filter.ParentExecutionID != nil && (exec.ParentExecutionID == nil || *filter.ParentExecutionID != *exec.ParentExecutionID) {
+			continue
+		}
+		if filter.ScheduleID != nil && (exec.ScheduleID == nil || *filter.ScheduleID != *exec.ScheduleID) {
+			continue
+		}
 		copy := *exec
 		results = append(results, &copy)
 	}
 	return results, nil
 }
+
+func (s *testExecutionStorage) EnqueueExecutionAction(ctx context.Context, action *types.ExecutionAction) error {
+	if action == nil {
+		return fmt.Errorf("action cannot be nil")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copy := *action
+	copy.Status = types.ExecutionActionStatusPending
+	copy.CreatedAt = time.Now().UTC()
+	s.actions[action.ActionID] = &copy
+	return nil
+}
+
+func (s *testExecutionStorage) GetExecutionAction(ctx context.Context, actionID string) (*types.ExecutionAction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	action, ok := s.actions[actionID]
+	if !ok {
+		return nil, nil
+	}
+	copy := *action
+	return &copy, nil
+}
+
+func (s *testExecutionStorage) ExtendExecutionActionLease(ctx context.Context, actionID string, leaseTTL time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	action, ok := s.actions[actionID]
+	if !ok || action.Status != types.ExecutionActionStatusLeased {
+		return fmt.Errorf("execution action '%s' is not leased", actionID)
+	}
+	expires := time.Now().UTC().Add(leaseTTL)
+	action.LeaseExpiresAt = &expires
+	return nil
+}
+
+func (s *testExecutionStorage) CompleteExecutionAction(ctx context.Context, actionID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	action, ok := s.actions[actionID]
+	if !ok || action.Status == types.ExecutionActionStatusCompleted {
+		return false, nil
+	}
+	now := time.Now().UTC()
+	action.Status = types.ExecutionActionStatusCompleted
+	action.CompletedAt = &now
+	action.LeaseExpiresAt = nil
+	return true, nil
+}
+
+func (s *testExecutionStorage) EnqueueExecution(ctx context.Context, item *types.QueuedExecution) error {
+	if item == nil {
+		return fmt.Errorf("queued execution cannot be nil")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copy := *item
+	if copy.CreatedAt.IsZero() {
+		copy.CreatedAt = time.Now().UTC()
+	}
+	s.queued[item.ExecutionID] = &copy
+	return nil
+}
+
+func (s *testExecutionStorage) ListQueuedExecutions(ctx context.Context, limitPerTarget int) ([]*types.QueuedExecution, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all := make([]*types.QueuedExecution, 0, len(s.queued))
+	for _, item := range s.queued {
+		copy := *item
+		all = append(all, &copy)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].CreatedAt.Before(all[j].CreatedAt) })
+	perTarget := make(map[string]int)
+	items := make([]*types.QueuedExecution, 0, len(all))
+	for _, item := range all {
+		key := item.NodeID + "." + item.Target
+		if limitPerTarget > 0 && perTarget[key] == limitPerTarget {
+			continue
+		}
+		perTarget[key]++
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (s *testExecutionStorage) DeleteQueuedExecution(ctx context.Context, executionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.queued, executionID)
+	return nil
+}
+
+func (s *testExecutionStorage) AcquireLock(ctx context.Context, key string, timeout time.Duration) (*types.DistributedLock, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	if existing, ok := s.locks[key]; ok && existing.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("lock '%s' is already held", key)
+	}
+	lock := &types.DistributedLock{LockID: "lock-" + key, Key: key, Holder: "test", ExpiresAt: now.Add(timeout), CreatedAt: now}
+	s.locks[key] = lock
+	copy := *lock
+	return &copy, nil
+}
+
+func (s *testExecutionStorage) ReleaseLock(ctx context.Context, lockID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, lock := range s.locks {
+		if lock.LockID == lockID {
+			delete(s.locks, key)
+			return nil
+		}
+	}
+	return fmt.Errorf("lock '%s' not found", lockID)
+}
+
+func (s *testExecutionStorage) RenewLock(ctx context.Context, lockID string) (*types.DistributedLock, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, lock := range s.locks {
+		if lock.LockID == lockID {
+			lock.ExpiresAt = time.Now().UTC().Add(30 * time.Second)
+			copy := *lock
+			return &copy, nil
+		}
+	}
+	return nil, fmt.Errorf("lock '%s' not found", lockID)
+}
+
+func (s *testExecutionStorage) CreateSchedule(ctx context.Context, schedule *types.Schedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.schedules[schedule.ScheduleID]; exists {
+		return fmt.Errorf("schedule %s already exists", schedule.ScheduleID)
+	}
+	copy := *schedule
+	s.schedules[schedule.ScheduleID] = &copy
+	return nil
+}
+
+func (s *testExecutionStorage) GetSchedule(ctx context.Context, scheduleID string) (*types.Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	schedule, ok := s.schedules[scheduleID]
+	if !ok {
+		return nil, nil
+	}
+	copy := *schedule
+	return &copy, nil
+}
+
+func (s *testExecutionStorage) ListSchedules(ctx context.Context) ([]*types.Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	schedules := make([]*types.Schedule, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		copy := *schedule
+		schedules = append(schedules, &copy)
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].ScheduleID < schedules[j].ScheduleID })
+	return schedules, nil
+}
+
+func (s *testExecutionStorage) UpdateSchedule(ctx context.Context, schedule *types.Schedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.schedules[schedule.ScheduleID]; !ok {
+		return fmt.Errorf("schedule %s not found", schedule.ScheduleID)
+	}
+	schedule.UpdatedAt = time.Now().UTC()
+	copy := *schedule
+	s.schedules[schedule.ScheduleID] = &copy
+	return nil
+}
+
+func (s *testExecutionStorage) ClaimScheduleRun(ctx context.Context, schedule *types.Schedule, dueAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, ok := s.schedules[schedule.ScheduleID]
+	if !ok || current.NextRunAt == nil || !current.NextRunAt.Equal(dueAt) || !current.UpdatedAt.Equal(schedule.UpdatedAt) {
+		return false, nil
+	}
+	current.Enabled = schedule.Enabled
+	current.NextRunAt = schedule.NextRunAt
+	current.LastRunAt = schedule.LastRunAt
+	current.LastError = schedule.LastError
+	return true, nil
+}
+
+func (s *testExecutionStorage) RecordScheduleRun(ctx context.Context, scheduleID string, executionID, lastError *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, ok := s.schedules[scheduleID]
+	if !ok {
+		return nil
+	}
+	current.RunCount++
+	if executionID != nil {
+		current.LastExecutionID = executionID
+	}
+	current.LastError = lastError
+	return nil
+}
+
+func (s *testExecutionStorage) DeleteSchedule(ctx context.Context, scheduleID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.schedules, scheduleID)
+	return nil
+}
+
+func (s *testExecutionStorage) ListDueSchedules(ctx context.Context, now time.Time, limit int) ([]*types.Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	due := make([]*types.Schedule, 0)
+	for _, schedule := range s.schedules {
+		if schedule.Enabled && schedule.NextRunAt != nil && !schedule.NextRunAt.After(now) {
+			copy := *schedule
+			due = append(due, &copy)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextRunAt.Before(*due[j].NextRunAt) })
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (s *testExecutionStorage) GetMemory(ctx context.Context, scope, scopeID, key string) (*types.Memory, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	memory, ok := s.memory[scope+"|"+scopeID+"|"+key]
+	if !ok {
+		return nil, fmt.Errorf("memory with key '%s' not found in scope '%s' for ID '%s'", key, scope, scopeID)
+	}
+	return memory, nil
+}
+
+func (s *testExecutionStorage) SetMemory(ctx context.Context, memory *types.Memory) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.memory[memory.Scope+"|"+memory.ScopeID+"|"+memory.Key] = memory
+	return nil
+}
+
+func (s *testExecutionStorage) GetCachedResult(ctx context.Context, teamID, reasoner, inputHash string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cachedResults[teamID+"|"+reasoner+"|"+inputHash], nil
+}
+
+func (s *testExecutionStorage) StoreCachedResult(ctx context.Context, teamID, reasoner, inputHash string, result []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cachedResults[teamID+"|"+reasoner+"|"+inputHash] = append([]byte(nil), result...)
+	return nil
+}
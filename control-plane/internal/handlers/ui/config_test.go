@@ -116,6 +116,134 @@ func (m *MockStorageProvider) ListWorkflowExecutionEvents(ctx context.Context, e
 	return nil, nil
 }
 
+func (m *MockStorageProvider) EnqueueExecutionAction(ctx context.Context, action *types.ExecutionAction) error {
+	return nil
+}
+
+func (m *MockStorageProvider) ClaimExecutionActions(ctx context.Context, nodeID string, limit int, leaseTTL time.Duration) ([]*types.ExecutionAction, error) {
+	return nil, nil
+}
+
+func (m *MockStorageProvider) GetExecutionAction(ctx context.Context, actionID string) (*types.ExecutionAction, error) {
+	return nil, nil
+}
+
+func (m *MockStorageProvider) ExtendExecutionActionLease(ctx context.Context, actionID string, leaseTTL time.Duration) error {
+	return nil
+}
+
+func (m *MockStorageProvider) CompleteExecutionAction(ctx context.Context, actionID string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockStorageProvider) EnqueueExecution(ctx context.Context, item *types.QueuedExecution) error {
+	return nil
+}
+
+func (m *MockStorageProvider) ListQueuedExecutions(ctx context.Context, limit int) ([]*types.QueuedExecution, error) {
+	return nil, nil
+}
+
+func (m *MockStorageProvider) DeleteQueuedExecution(ctx context.Context, executionID string) error {
+	return nil
+}
+
+func (m *MockStorageProvider) GetCachedResult(ctx context.Context, teamID, reasoner, inputHash string) ([]byte, error) {
+	return nil, nil
+}
+
+func (m *MockStorageProvider) StoreCachedResult(ctx context.Context, teamID, reasoner, inputHash string, result []byte, ttl time.Duration) error {
+	return nil
+}
+
+func (m *MockStorageProvider) CreateSchedule(ctx context.Context, schedule *types.Schedule) error {
+	return nil
+}
+
+func (m *MockStorageProvider) GetSchedule(ctx context.Context, scheduleID string) (*types.Schedule, error) {
+	return nil, nil
+}
+
+func (m *MockStorageProvider) ListSchedules(ctx context.Context) ([]*types.Schedule, error) {
+	return nil, nil
+}
+
+func (m *MockStorageProvider) UpdateSchedule(ctx context.Context, schedule *types.Schedule) error {
+	return nil
+}
+
+func (m *MockStorageProvider) ClaimScheduleRun(ctx context.Context, schedule *types.Schedule, dueAt time.Time) (bool, error) {
+	return false, nil
+}
+
+func (m *MockStorageProvider) RecordScheduleRun(ctx context.Context, scheduleID string, executionID, lastError *string) error {
+	return nil
+}
+
+func (m *MockStorageProvider) DeleteSchedule(ctx context.Context, scheduleID string) error {
+	return nil
+}
+
+func (m *MockStorageProvider) ListDueSchedules(ctx context.Context, now time.Time, limit int) ([]*types.Schedule, error) {
+	return nil, nil
+}
+
+func (m *MockStorageProvider) UpsertAgentInstance(ctx context.Context, instance *types.AgentInstance) error {
+	return nil
+}
+
+func (m *MockStorageProvider) ListAgentInstances(ctx context.Context, nodeID string) ([]*types.AgentInstance, error) {
+	return nil, nil
+}
+
+func (m *MockStorageProvider) TouchAgentInstance(ctx context.Context, nodeID, instanceID string, seenAt time.Time) error {
+	return nil
+}
+
+func (m *MockStorageProvider) DeleteAgentInstance(ctx context.Context, nodeID, instanceID string) error {
+	return nil
+}
+
+func (m *MockStorageProvider) CreateAPIKey(ctx context.Context, key *types.APIKey) error {
+	return nil
+}
+
+func (m *MockStorageProvider) GetAPIKey(ctx context.Context, keyID string) (*types.APIKey, error) {
+	return nil, nil
+}
+
+func (m *MockStorageProvider) GetAPIKeyBySecretHash(ctx context.Context, secretHash string) (*types.APIKey, error) {
+	return nil, nil
+}
+
+func (m *MockStorageProvider) ListAPIKeys(ctx context.Context, teamID string) ([]*types.APIKey, error) {
+	return nil, nil
+}
+
+func (m *MockStorageProvider) UpdateAPIKey(ctx context.Context, key *types.APIKey) error {
+	return nil
+}
+
+func (m *MockStorageProvider) CreateApproval(ctx context.Context, approval *types.Approval) error {
+	return nil
+}
+
+func (m *MockStorageProvider) GetApproval(ctx context.Context, approvalID string) (*types.Approval, error) {
+	return nil, nil
+}
+
+func (m *MockStorageProvider) ListApprovals(ctx context.Context, filter types.ApprovalFilter) ([]*types.Approval, error) {
+	return nil, nil
+}
+
+func (m *MockStorageProvider) ListExpiredApprovals(ctx context.Context, now time.Time, limit int) ([]*types.Approval, error) {
+	return nil, nil
+}
+
+func (m *MockStorageProvider) ResolveApproval(ctx context.Context, approval *types.Approval) (bool, error) {
+	return false, nil
+}
+
 func (m *MockStorageProvider) StoreWorkflowRunEvent(ctx context.Context, event *types.WorkflowRunEvent) error {
 	return nil
 }
@@ -208,6 +336,14 @@ func (m *MockStorageProvider) SetMemory(ctx context.Context, memory *types.Memor
 	return args.Error(0)
 }
 
+func (m *MockStorageProvider) UpdateMemory(ctx context.Context, scope, scopeID, key string, update func(*types.Memory) (*types.Memory, error)) (*types.Memory, error) {
+	args := m.Called(ctx, scope, scopeID, key, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*types.Memory), args.Error(1)
+}
+
 func (m *MockStorageProvider) GetMemory(ctx context.Context, scope, scopeID, key string) (*types.Memory, error) {
 	args := m.Called(ctx, scope, scopeID, key)
 	if args.Get(0) == nil {
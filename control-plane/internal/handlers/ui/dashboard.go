@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
 	"github.com/Agent-Field/agentfield/control-plane/internal/core/interfaces"
 	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
 	"github.com/Agent-Field/agentfield/control-plane/internal/storage"
@@ -380,9 +381,11 @@ func (c *EnhancedDashboardCache) Set(key string, data *EnhancedDashboardResponse
 func (h *DashboardHandler) GetDashboardSummaryHandler(c *gin.Context) {
 	ctx := c.Request.Context()
 	now := time.Now().UTC()
+	// The cache holds the summary of every team, so team-scoped callers bypass it.
+	cacheable := auth.TeamFrom(ctx) == ""
 
 	// Check cache first
-	if cachedData, found := h.cache.Get(); found {
+	if cachedData, found := h.cache.Get(); cacheable && found {
 		logger.Logger.Debug().Msg("Returning cached dashboard summary")
 		c.JSON(http.StatusOK, cachedData)
 		return
@@ -464,7 +467,9 @@ func (h *DashboardHandler) GetDashboardSummaryHandler(c *gin.Context) {
 	}
 
 	// Cache the response
-	h.cache.Set(response)
+	if cacheable {
+		h.cache.Set(response)
+	}
 
 	c.JSON(http.StatusOK, response)
 }
@@ -544,8 +549,8 @@ func (h *DashboardHandler) GetEnhancedDashboardSummaryHandler(c *gin.Context) {
 	// Check if comparison is requested
 	enableComparison := c.Query("compare") == "true"
 
-	// Generate cache key and check cache
-	cacheKey := generateCacheKey(startTime, endTime, enableComparison)
+	// Generate cache key and check cache. Each team has its own entries.
+	cacheKey := auth.TeamFrom(ctx) + "|" + generateCacheKey(startTime, endTime, enableComparison)
 	if cached, found := h.enhancedCache.Get(cacheKey, preset); found {
 		logger.Logger.Debug().Str("key", cacheKey).Msg("Returning cached enhanced dashboard summary")
 		c.JSON(http.StatusOK, cached)
@@ -560,6 +565,11 @@ func (h *DashboardHandler) GetEnhancedDashboardSummaryHandler(c *gin.Context) {
 		SortBy:         "started_at",
 		SortDescending: false,
 	}
+	if err := restrictExecutionsToTeam(ctx, h.storage, &filters); err != nil {
+		logger.Logger.Error().Err(err).Msg("failed to list team agents for enhanced dashboard")
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to load agent data"})
+		return
+	}
 
 	executions, err := h.store.QueryExecutionRecords(ctx, filters)
 	if err != nil {
@@ -568,7 +578,7 @@ func (h *DashboardHandler) GetEnhancedDashboardSummaryHandler(c *gin.Context) {
 		return
 	}
 
-	agents, err := h.storage.ListAgents(ctx, types.AgentFilters{})
+	agents, err := h.storage.ListAgents(ctx, teamAgentFilters(ctx))
 	if err != nil {
 		logger.Logger.Error().Err(err).Msg("failed to list agents for enhanced dashboard")
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to load agent data"})
@@ -576,12 +586,14 @@ func (h *DashboardHandler) GetEnhancedDashboardSummaryHandler(c *gin.Context) {
 	}
 
 	statusRunning := string(types.ExecutionStatusRunning)
-	runningExecutions, err := h.store.QueryExecutionRecords(ctx, types.ExecutionFilter{
+	runningFilter := types.ExecutionFilter{
 		Status:         &statusRunning,
 		Limit:          12,
 		SortBy:         "started_at",
 		SortDescending: true,
-	})
+		AgentNodeIDs:   filters.AgentNodeIDs,
+	}
+	runningExecutions, err := h.store.QueryExecutionRecords(ctx, runningFilter)
 	if err != nil {
 		logger.Logger.Error().Err(err).Msg("failed to query running executions for enhanced dashboard")
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to load active workflow data"})
@@ -624,6 +636,7 @@ func (h *DashboardHandler) GetEnhancedDashboardSummaryHandler(c *gin.Context) {
 			Limit:          50000,
 			SortBy:         "started_at",
 			SortDescending: false,
+			AgentNodeIDs:   filters.AgentNodeIDs,
 		}
 
 		prevExecutions, err := h.store.QueryExecutionRecords(ctx, prevFilters)
@@ -1353,7 +1366,7 @@ func maxTime(current time.Time, candidate time.Time) time.Time {
 // getAgentsSummary collects agent statistics
 func (h *DashboardHandler) getAgentsSummary(ctx context.Context) (AgentsSummary, error) {
 	// Get all registered agents
-	agents, err := h.storage.ListAgents(ctx, types.AgentFilters{})
+	agents, err := h.storage.ListAgents(ctx, teamAgentFilters(ctx))
 	if err != nil {
 		return AgentsSummary{}, err
 	}
@@ -1379,6 +1392,10 @@ func (h *DashboardHandler) getExecutionsSummaryAndSuccessRate(ctx context.Contex
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	yesterday := today.AddDate(0, 0, -1)
 	tomorrow := today.AddDate(0, 0, 1)
+	agentNodeIDs, err := teamAgentIDs(ctx, h.storage)
+	if err != nil {
+		return ExecutionsSummary{}, 0, err
+	}
 
 	// Get today's executions
 	todayFilters := types.ExecutionFilter{
@@ -1387,6 +1404,7 @@ func (h *DashboardHandler) getExecutionsSummaryAndSuccessRate(ctx context.Contex
 		Limit:          10000,
 		SortBy:         "started_at",
 		SortDescending: false,
+		AgentNodeIDs:   agentNodeIDs,
 	}
 	todayExecutions, err := h.store.QueryExecutionRecords(ctx, todayFilters)
 	if err != nil {
@@ -1400,6 +1418,7 @@ func (h *DashboardHandler) getExecutionsSummaryAndSuccessRate(ctx context.Contex
 		Limit:          10000,
 		SortBy:         "started_at",
 		SortDescending: false,
+		AgentNodeIDs:   agentNodeIDs,
 	}
 	yesterdayExecutions, err := h.store.QueryExecutionRecords(ctx, yesterdayFilters)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
 	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
 	"github.com/Agent-Field/agentfield/control-plane/internal/storage"
 	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
@@ -100,9 +101,11 @@ func (c *ExecutionTimelineCache) Set(data *ExecutionTimelineResponse) {
 // GET /api/ui/v1/executions/timeline
 func (h *ExecutionTimelineHandler) GetExecutionTimelineHandler(c *gin.Context) {
 	ctx := c.Request.Context()
+	// The cache holds the timeline of every team, so team-scoped callers bypass it.
+	cacheable := auth.TeamFrom(ctx) == ""
 
 	// Check cache first
-	if cachedData, found := h.cache.Get(); found {
+	if cachedData, found := h.cache.Get(); cacheable && found {
 		logger.Logger.Debug().Msg("Returning cached execution timeline data")
 		c.JSON(http.StatusOK, cachedData)
 		return
@@ -126,7 +129,9 @@ func (h *ExecutionTimelineHandler) GetExecutionTimelineHandler(c *gin.Context) {
 	}
 
 	// Cache the response
-	h.cache.Set(response)
+	if cacheable {
+		h.cache.Set(response)
+	}
 
 	c.JSON(http.StatusOK, response)
 }
@@ -147,6 +152,9 @@ func (h *ExecutionTimelineHandler) generateTimelineData(ctx context.Context) ([]
 		SortBy:         "started_at",
 		SortDescending: false,
 	}
+	if err := restrictExecutionsToTeam(ctx, h.storage, &filters); err != nil {
+		return nil, TimelineSummary{}, fmt.Errorf("failed to list team agents: %w", err)
+	}
 
 	executions, err := h.store.QueryExecutionRecords(ctx, filters)
 	if err != nil {
@@ -193,7 +201,7 @@ func (h *ExecutionTimelineHandler) generateTimelineData(ctx context.Context) ([]
 				dataPoint.Successful++
 			case string(types.ExecutionStatusFailed):
 				dataPoint.Failed++
-			case string(types.ExecutionStatusRunning), string(types.ExecutionStatusPending), string(types.ExecutionStatusQueued):
+			case string(types.ExecutionStatusRunning), string(types.ExecutionStatusPending), string(types.ExecutionStatusQueued), string(types.ExecutionStatusWaitingForApproval):
 				dataPoint.Running++
 			}
 
@@ -84,6 +84,7 @@ func (h *ExecutionHandler) StreamWorkflowNodeNotesHandler(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
+	visibility := newStreamVisibility(ctx, h.storage)
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
 
@@ -105,6 +106,9 @@ func (h *ExecutionHandler) StreamWorkflowNodeNotesHandler(c *gin.Context) {
 			if !ok {
 				return
 			}
+			if !visibility.visible(event.AgentNodeID) {
+				continue
+			}
 			if payload, err := json.Marshal(event); err == nil {
 				if !writeSSE(c, payload) {
 					return
@@ -222,6 +226,11 @@ func (h *ExecutionHandler) ListExecutionsHandler(c *gin.Context) {
 		return
 	}
 
+	if !agentVisible(ctx, h.storage, agentID) {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "agent not found"})
+		return
+	}
+
 	page := parsePositiveIntOrDefault(c.Query("page"), 1)
 	pageSize := parseBoundedIntOrDefault(c.Query("pageSize"), 10, 1, 100)
 	status := strings.TrimSpace(c.Query("status"))
@@ -291,7 +300,7 @@ func (h *ExecutionHandler) GetExecutionDetailsHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to load execution: " + err.Error()})
 		return
 	}
-	if exec == nil || exec.AgentNodeID != agentID {
+	if exec == nil || exec.AgentNodeID != agentID || !agentVisible(ctx, h.storage, agentID) {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: "execution not found for this agent"})
 		return
 	}
@@ -341,6 +350,10 @@ func (h *ExecutionHandler) GetExecutionsSummaryHandler(c *gin.Context) {
 	if sessionID != "" {
 		filter.SessionID = &sessionID
 	}
+	if err := restrictExecutionsToTeam(ctx, h.storage, &filter); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to resolve team agents: " + err.Error()})
+		return
+	}
 
 	execs, queryErr := h.store.QueryExecutionRecords(ctx, filter)
 	if queryErr != nil {
@@ -405,6 +418,10 @@ func (h *ExecutionHandler) GetExecutionStatsHandler(c *gin.Context) {
 	if runID != "" {
 		filter.RunID = &runID
 	}
+	if err := restrictExecutionsToTeam(ctx, h.storage, &filter); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to resolve team agents: " + err.Error()})
+		return
+	}
 
 	execs, err := h.store.QueryExecutionRecords(ctx, filter)
 	if err != nil {
@@ -429,7 +446,7 @@ func (h *ExecutionHandler) GetExecutionStatsHandler(c *gin.Context) {
 			stats.SuccessfulCount++
 		case string(types.ExecutionStatusFailed):
 			stats.FailedCount++
-		case string(types.ExecutionStatusRunning), string(types.ExecutionStatusPending), string(types.ExecutionStatusQueued):
+		case string(types.ExecutionStatusRunning), string(types.ExecutionStatusPending), string(types.ExecutionStatusQueued), string(types.ExecutionStatusWaitingForApproval):
 			stats.RunningCount++
 		}
 
@@ -482,6 +499,10 @@ func (h *ExecutionHandler) GetEnhancedExecutionsHandler(c *gin.Context) {
 			filter.StartTime = &ts
 		}
 	}
+	if err := restrictExecutionsToTeam(ctx, h.storage, &filter); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to resolve team agents: " + err.Error()})
+		return
+	}
 
 	executions, err := h.store.QueryExecutionRecords(ctx, filter)
 	if err != nil {
@@ -551,7 +572,7 @@ func (h *ExecutionHandler) GetExecutionDetailsGlobalHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to load execution: " + err.Error()})
 		return
 	}
-	if exec == nil {
+	if exec == nil || !agentVisible(ctx, h.storage, exec.AgentNodeID) {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: "execution not found"})
 		return
 	}
@@ -618,6 +639,7 @@ func (h *ExecutionHandler) StreamExecutionEventsHandler(c *gin.Context) {
 	defer eventBus.Unsubscribe(subscriberID)
 
 	ctx := c.Request.Context()
+	visibility := newStreamVisibility(ctx, h.storage)
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
 
@@ -639,6 +661,9 @@ func (h *ExecutionHandler) StreamExecutionEventsHandler(c *gin.Context) {
 			if !ok {
 				return
 			}
+			if !visibility.visible(event.AgentNodeID) {
+				continue
+			}
 			if payload, err := json.Marshal(event); err == nil {
 				if !writeSSE(c, payload) {
 					return
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
 	"github.com/Agent-Field/agentfield/control-plane/internal/events"
 	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
 	"github.com/Agent-Field/agentfield/control-plane/internal/services"
@@ -31,6 +32,15 @@ func (h *NodesHandler) GetNodesSummaryHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get nodes summary"})
 		return
 	}
+	if team := auth.TeamFrom(ctx); team != "" {
+		visible := summaries[:0]
+		for _, summary := range summaries {
+			if summary.TeamID == team {
+				visible = append(visible, summary)
+			}
+		}
+		summaries, count = visible, len(visible)
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"nodes": summaries,
 		"count": count,
@@ -47,7 +57,7 @@ func (h *NodesHandler) GetNodeDetailsHandler(c *gin.Context) {
 
 	ctx := c.Request.Context()
 	details, err := h.service.GetNodeDetailsWithPackageInfo(ctx, nodeID)
-	if err != nil {
+	if err != nil || details.AgentNode == nil || !auth.CanAccessTeam(ctx, details.TeamID) {
 		// TODO: Differentiate between not found and other errors
 		c.JSON(http.StatusNotFound, gin.H{"error": "node not found or failed to retrieve details"})
 		return
@@ -87,6 +97,7 @@ func (h *NodesHandler) StreamNodeEventsHandler(c *gin.Context) {
 
 	// Set up context for handling client disconnection
 	ctx := c.Request.Context()
+	visibility := newStreamVisibility(ctx, agentGetterFunc(h.service.GetNodeDetails))
 
 	// Send periodic heartbeat to keep connection alive
 	heartbeatTicker := time.NewTicker(30 * time.Second)
@@ -98,6 +109,11 @@ func (h *NodesHandler) StreamNodeEventsHandler(c *gin.Context) {
 	for {
 		select {
 		case event := <-eventChan:
+			// Events without a node, such as heartbeats, carry nothing team-specific.
+			if event.NodeID != "" && !visibility.visible(event.NodeID) {
+				continue
+			}
+
 			// Marshal event to JSON
 			eventData, err := json.Marshal(event)
 			if err != nil {
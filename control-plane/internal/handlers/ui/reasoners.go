@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
 	"github.com/Agent-Field/agentfield/control-plane/internal/events"
 	"github.com/Agent-Field/agentfield/control-plane/internal/storage"
 	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
@@ -97,6 +98,9 @@ func (h *ReasonersHandler) GetAllReasonersHandler(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
+	if team := auth.TeamFrom(ctx); team != "" {
+		filters.TeamID = &team
+	}
 	nodes, err := h.storage.ListAgents(ctx, filters)
 	if err != nil {
 		fmt.Printf("❌ Error listing agents for reasoners: %v\n", err)
@@ -234,7 +238,7 @@ func (h *ReasonersHandler) GetReasonerDetailsHandler(c *gin.Context) {
 	// Get the node
 	ctx := c.Request.Context()
 	node, err := h.storage.GetAgent(ctx, nodeID)
-	if err != nil {
+	if err != nil || node == nil || !auth.CanAccessTeam(ctx, node.TeamID) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "node not found"})
 		return
 	}
@@ -334,8 +338,13 @@ func (h *ReasonersHandler) GetPerformanceMetricsHandler(c *gin.Context) {
 		return
 	}
 
-	// Get real performance metrics from storage
 	ctx := c.Request.Context()
+	if !agentVisible(ctx, h.storage, parts[0]) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "reasoner not found"})
+		return
+	}
+
+	// Get real performance metrics from storage
 	metrics, err := h.storage.GetReasonerPerformanceMetrics(ctx, reasonerID)
 	if err != nil {
 		fmt.Printf("❌ Error getting performance metrics for reasoner %s: %v\n", reasonerID, err)
@@ -378,8 +387,13 @@ func (h *ReasonersHandler) GetExecutionHistoryHandler(c *gin.Context) {
 		return
 	}
 
-	// Get real execution history from storage
 	ctx := c.Request.Context()
+	if !agentVisible(ctx, h.storage, parts[0]) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "reasoner not found"})
+		return
+	}
+
+	// Get real execution history from storage
 	history, err := h.storage.GetReasonerExecutionHistory(ctx, reasonerID, page, limit)
 	if err != nil {
 		fmt.Printf("❌ Error getting execution history for reasoner %s: %v\n", reasonerID, err)
@@ -408,6 +422,11 @@ func (h *ReasonersHandler) GetExecutionTemplatesHandler(c *gin.Context) {
 		return
 	}
 
+	if !agentVisible(c.Request.Context(), h.storage, parts[0]) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "reasoner not found"})
+		return
+	}
+
 	// For now, return mock data since we don't have template storage yet
 	// TODO: Implement actual template storage and retrieval
 	templates := []ExecutionTemplate{
@@ -445,6 +464,11 @@ func (h *ReasonersHandler) SaveExecutionTemplateHandler(c *gin.Context) {
 		return
 	}
 
+	if !agentVisible(c.Request.Context(), h.storage, parts[0]) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "reasoner not found"})
+		return
+	}
+
 	var template struct {
 		Name        string                 `json:"name"`
 		Description string                 `json:"description"`
@@ -500,6 +524,7 @@ func (h *ReasonersHandler) StreamReasonerEventsHandler(c *gin.Context) {
 
 	// Set up context for handling client disconnection
 	ctx := c.Request.Context()
+	visibility := newStreamVisibility(ctx, h.storage)
 
 	// Send periodic heartbeat to keep connection alive
 	heartbeatTicker := time.NewTicker(30 * time.Second)
@@ -531,6 +556,10 @@ func (h *ReasonersHandler) StreamReasonerEventsHandler(c *gin.Context) {
 				return
 			}
 
+			if !visibility.visible(event.NodeID) {
+				continue
+			}
+
 			// Convert event to JSON and send
 			if eventJSON, err := event.ToJSON(); err == nil {
 				if !writeSSE(c, []byte(eventJSON)) {
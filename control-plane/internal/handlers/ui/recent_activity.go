@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
 	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
 	"github.com/Agent-Field/agentfield/control-plane/internal/storage"
 	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
@@ -88,9 +89,11 @@ func (c *RecentActivityCache) Set(data *RecentActivityResponse) {
 // GET /api/ui/v1/executions/recent
 func (h *RecentActivityHandler) GetRecentActivityHandler(c *gin.Context) {
 	ctx := c.Request.Context()
+	// The cache holds the activity of every team, so team-scoped callers bypass it.
+	cacheable := auth.TeamFrom(ctx) == ""
 
 	// Check cache first
-	if cachedData, found := h.cache.Get(); found {
+	if cachedData, found := h.cache.Get(); cacheable && found {
 		logger.Logger.Debug().Msg("Returning cached recent activity data")
 		c.JSON(http.StatusOK, cachedData)
 		return
@@ -113,7 +116,9 @@ func (h *RecentActivityHandler) GetRecentActivityHandler(c *gin.Context) {
 	}
 
 	// Cache the response
-	h.cache.Set(response)
+	if cacheable {
+		h.cache.Set(response)
+	}
 
 	c.JSON(http.StatusOK, response)
 }
@@ -127,6 +132,9 @@ func (h *RecentActivityHandler) getRecentExecutions(ctx context.Context) ([]Acti
 		SortBy:         "started_at",
 		SortDescending: true,
 	}
+	if err := restrictExecutionsToTeam(ctx, h.storage, &filters); err != nil {
+		return nil, err
+	}
 
 	executions, err := h.store.QueryExecutionRecords(ctx, filters)
 	if err != nil {
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"context"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+)
+
+type agentLister interface {
+	ListAgents(ctx context.Context, filters types.AgentFilters) ([]*types.AgentNode, error)
+}
+
+type agentGetter interface {
+	GetAgent(ctx context.Context, id string) (*types.AgentNode, error)
+}
+
+// agentGetterFunc adapts a lookup function, such as UIService.GetNodeDetails, to
+// agentGetter.
+type agentGetterFunc func(ctx context.Context, id string) (*types.AgentNode, error)
+
+func (f agentGetterFunc) GetAgent(ctx context.Context, id string) (*types.AgentNode, error) {
+	return f(ctx, id)
+}
+
+// teamAgentFilters returns agent filters confined to the caller's team.
+func teamAgentFilters(ctx context.Context) types.AgentFilters {
+	var filters types.AgentFilters
+	if team := auth.TeamFrom(ctx); team != "" {
+		filters.TeamID = &team
+	}
+	return filters
+}
+
+// teamAgentIDs returns the IDs of the agents of the caller's team, or nil when the
+// caller is not confined to a team.
+func teamAgentIDs(ctx context.Context, store agentLister) ([]string, error) {
+	team := auth.TeamFrom(ctx)
+	if team == "" {
+		return nil, nil
+	}
+	agents, err := store.ListAgents(ctx, types.AgentFilters{TeamID: &team})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(agents))
+	for _, agent := range agents {
+		ids = append(ids, agent.ID)
+	}
+	return ids, nil
+}
+
+// restrictExecutionsToTeam confines an execution query to the agents of the caller's
+// team.
+func restrictExecutionsToTeam(ctx context.Context, store agentLister, filter *types.ExecutionFilter) error {
+	ids, err := teamAgentIDs(ctx, store)
+	if err != nil {
+		return err
+	}
+	if ids != nil {
+		filter.AgentNodeIDs = ids
+	}
+	return nil
+}
+
+// agentVisible reports whether the caller's team owns agentID.
+func agentVisible(ctx context.Context, store agentGetter, agentID string) bool {
+	if auth.TeamFrom(ctx) == "" {
+		return true
+	}
+	agent, err := store.GetAgent(ctx, agentID)
+	return err == nil && agent != nil && auth.CanAccessTeam(ctx, agent.TeamID)
+}
+
+// streamVisibility decides which agents' events an event stream may forward to its
+// caller. Answers are kept for the life of the stream, as agents rarely change team.
+type streamVisibility struct {
+	ctx   context.Context
+	store agentGetter
+	known map[string]bool
+}
+
+func newStreamVisibility(ctx context.Context, store agentGetter) *streamVisibility {
+	return &streamVisibility{ctx: ctx, store: store, known: make(map[string]bool)}
+}
+
+// visible reports whether events of agentID may be forwarded. Events that name no
+// agent are only forwarded to callers not confined to a team.
+func (v *streamVisibility) visible(agentID string) bool {
+	if auth.TeamFrom(v.ctx) == "" {
+		return true
+	}
+	if agentID == "" {
+		return false
+	}
+	if visible, ok := v.known[agentID]; ok {
+		return visible
+	}
+	visible := agentVisible(v.ctx, v.store, agentID)
+	v.known[agentID] = visible
+	return visible
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
+	"github.com/Agent-Field/agentfield/control-plane/internal/storage"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+// teamAgentStore serves a fixed set of agents and counts lookups.
+type teamAgentStore struct {
+	agents  []*types.AgentNode
+	lookups int
+}
+
+func (s *teamAgentStore) ListAgents(ctx context.Context, filters types.AgentFilters) ([]*types.AgentNode, error) {
+	var agents []*types.AgentNode
+	for _, agent := range s.agents {
+		if filters.TeamID == nil || agent.TeamID == *filters.TeamID {
+			agents = append(agents, agent)
+		}
+	}
+	return agents, nil
+}
+
+func (s *teamAgentStore) GetAgent(ctx context.Context, id string) (*types.AgentNode, error) {
+	s.lookups++
+	for _, agent := range s.agents {
+		if agent.ID == id {
+			return agent, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", storage.ErrAgentNotFound, id)
+}
+
+func teamContext(team string) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{KeyID: "key-" + team, TeamID: team, Role: types.APIKeyRoleOperator})
+}
+
+func TestRestrictExecutionsToTeam(t *testing.T) {
+	store := &teamAgentStore{agents: []*types.AgentNode{{ID: "node-a", TeamID: "team-a"}, {ID: "node-b", TeamID: "team-b"}}}
+
+	var filter types.ExecutionFilter
+	require.NoError(t, restrictExecutionsToTeam(context.Background(), store, &filter))
+	require.Nil(t, filter.AgentNodeIDs, "unconfined callers see every agent")
+
+	require.NoError(t, restrictExecutionsToTeam(teamContext("team-a"), store, &filter))
+	require.Equal(t, []string{"node-a"}, filter.AgentNodeIDs)
+
+	filter = types.ExecutionFilter{}
+	require.NoError(t, restrictExecutionsToTeam(teamContext("team-c"), store, &filter))
+	require.NotNil(t, filter.AgentNodeIDs)
+	require.Empty(t, filter.AgentNodeIDs, "a team without agents sees no executions")
+}
+
+func TestStreamVisibility(t *testing.T) {
+	store := &teamAgentStore{agents: []*types.AgentNode{{ID: "node-a", TeamID: "team-a"}, {ID: "node-b", TeamID: "team-b"}}}
+
+	unconfined := newStreamVisibility(context.Background(), store)
+	require.True(t, unconfined.visible("node-b"))
+	require.True(t, unconfined.visible(""))
+
+	confined := newStreamVisibility(teamContext("team-a"), store)
+	require.True(t, confined.visible("node-a"))
+	require.False(t, confined.visible("node-b"))
+	require.False(t, confined.visible("missing"))
+	require.False(t, confined.visible(""), "events naming no agent stay hidden from teams")
+
+	lookups := store.lookups
+	require.True(t, confined.visible("node-a"))
+	require.Equal(t, lookups, store.lookups, "answers are cached for the stream")
+}
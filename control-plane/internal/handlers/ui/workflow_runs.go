@@ -122,6 +122,11 @@ func (h *WorkflowRunHandler) ListWorkflowRunsHandler(c *gin.Context) {
 		}
 	}
 
+	if err := restrictExecutionsToTeam(ctx, h.storage, &filter); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list team agents"})
+		return
+	}
+
 	// Use the efficient aggregation method that scales to millions of nodes
 	runAggregations, totalRuns, err := h.storage.QueryRunSummaries(ctx, filter)
 	if err != nil {
@@ -254,6 +259,10 @@ func (h *WorkflowRunHandler) GetWorkflowRunDetailHandler(c *gin.Context) {
 		SortDescending: false,
 		Limit:          10000,
 	}
+	if err := restrictExecutionsToTeam(ctx, h.storage, &filter); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list team agents"})
+		return
+	}
 
 	executions, err := h.storage.QueryExecutionRecords(ctx, filter)
 	if err != nil {
@@ -376,7 +385,8 @@ func summarizeRun(runID string, executions []*types.Execution) WorkflowRunSummar
 		summary.StatusCounts[normalized]++
 		if normalized == string(types.ExecutionStatusRunning) ||
 			normalized == string(types.ExecutionStatusPending) ||
-			normalized == string(types.ExecutionStatusQueued) {
+			normalized == string(types.ExecutionStatusQueued) ||
+			normalized == string(types.ExecutionStatusWaitingForApproval) {
 			active++
 		}
 		if exec.CompletedAt != nil {
@@ -406,6 +416,10 @@ func (h *WorkflowRunHandler) loadRunSummary(ctx context.Context, runID string) *
 		Limit:  1,
 		Offset: 0,
 	}
+	if err := restrictExecutionsToTeam(ctx, h.storage, &filter); err != nil {
+		logger.Logger.Warn().Str("run_id", runID).Err(err).Msg("failed to list team agents")
+		return nil
+	}
 
 	summaries, _, err := h.storage.QueryRunSummaries(ctx, filter)
 	if err != nil {
@@ -462,7 +476,7 @@ func buildAPIExecutions(nodes []handlers.WorkflowDAGNode) []apiWorkflowExecution
 		pendingChildren := 0
 		for _, child := range children {
 			switch types.NormalizeExecutionStatus(child.Status) {
-			case string(types.ExecutionStatusRunning):
+			case string(types.ExecutionStatusRunning), string(types.ExecutionStatusWaitingForApproval):
 				activeChildren++
 			case string(types.ExecutionStatusPending), string(types.ExecutionStatusQueued):
 				pendingChildren++
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"strings"
 
 	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+	"github.com/Agent-Field/agentfield/control-plane/internal/storage"
 	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
 	"github.com/gin-gonic/gin"
 )
@@ -13,7 +16,13 @@ type SetVectorRequest struct {
 	Key       string                 `json:"key" binding:"required"`
 	Embedding []float32              `json:"embedding" binding:"required"`
 	Metadata  map[string]interface{} `json:"metadata"`
-	Scope     *string                `json:"scope,omitempty"`
+	// Text is the source text of the embedding, indexed for keyword search.
+	Text  string  `json:"text,omitempty"`
+	Scope *string `json:"scope,omitempty"`
+	// AccessLevel and AccessControl restrict who may read the vector, as for memory
+	// values. A write declaring neither keeps the vector's current policy.
+	AccessLevel   string                       `json:"access_level,omitempty"`
+	AccessControl *types.AccessControlMetadata `json:"access_control,omitempty"`
 }
 
 // DeleteVectorRequest removes a vector by key.
@@ -28,12 +37,20 @@ type DeleteNamespaceRequest struct {
 	Scope     *string `json:"scope,omitempty"`
 }
 
-// VectorSearchRequest describes a similarity search query.
+// VectorSearchRequest describes a similarity search query. Filters select on metadata
+// with the operators described in the storage package, e.g. {"year": {"$gte": 2024}}.
+// A query with QueryText matches the stored source text by keyword; with an embedding
+// as well, the keyword and vector rankings are fused with reciprocal rank fusion.
 type VectorSearchRequest struct {
-	QueryEmbedding []float32              `json:"query_embedding" binding:"required"`
+	QueryEmbedding []float32              `json:"query_embedding"`
+	QueryText      string                 `json:"query_text,omitempty"`
 	TopK           int                    `json:"top_k"`
 	Filters        map[string]interface{} `json:"filters"`
 	Scope          *string                `json:"scope,omitempty"`
+	// RRFK is the rank constant of the fusion (default 60); KeywordWeight scales the
+	// keyword ranking's contribution (default 1).
+	RRFK          int     `json:"rrf_k,omitempty"`
+	KeywordWeight float64 `json:"keyword_weight,omitempty"`
 }
 
 // SetVectorHandler stores or updates a vector embedding.
@@ -57,13 +74,46 @@ func SetVectorHandler(storage MemoryStorage) gin.HandlerFunc {
 			return
 		}
 
+		accessLevel, accessControl, err := memoryWritePolicy(c, req.AccessLevel, req.AccessControl)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_request",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+
 		scope, scopeID := resolveScope(c, req.Scope)
 		record := &types.VectorRecord{
-			Scope:     scope,
-			ScopeID:   scopeID,
-			Key:       req.Key,
-			Embedding: req.Embedding,
-			Metadata:  req.Metadata,
+			Scope:         scope,
+			ScopeID:       scopeID,
+			Key:           req.Key,
+			Embedding:     req.Embedding,
+			Metadata:      req.Metadata,
+			Text:          req.Text,
+			AccessLevel:   accessLevel,
+			AccessControl: accessControl,
+		}
+
+		existing, err := storage.GetVector(c.Request.Context(), scope, scopeID, req.Key)
+		if err != nil {
+			logger.Logger.Error().Err(err).Msg("failed to get vector")
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "storage_error",
+				Message: err.Error(),
+				Code:    http.StatusInternalServerError,
+			})
+			return
+		}
+		if existing != nil {
+			if err := authorizeMemoryWrite(c, storage, vectorTarget(existing)); err != nil {
+				writeMemoryAccessDenied(c, err)
+				return
+			}
+			if req.AccessLevel == "" && req.AccessControl == nil {
+				record.AccessLevel, record.AccessControl = existing.AccessLevel, existing.AccessControl
+			}
 		}
 
 		if err := storage.SetVector(c.Request.Context(), record); err != nil {
@@ -124,6 +174,10 @@ func GetVectorHandler(storage MemoryStorage) gin.HandlerFunc {
 			})
 			return
 		}
+		if err := authorizeMemoryRead(c, storage, vectorTarget(record)); err != nil {
+			writeMemoryAccessDenied(c, err)
+			return
+		}
 
 		c.JSON(http.StatusOK, record)
 	}
@@ -155,6 +209,23 @@ func DeleteVectorHandler(storage MemoryStorage) gin.HandlerFunc {
 		}
 
 		scope, scopeID := resolveScope(c, scopePtr)
+		existing, err := storage.GetVector(c.Request.Context(), scope, scopeID, key)
+		if err != nil {
+			logger.Logger.Error().Err(err).Msg("failed to get vector")
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "storage_error",
+				Message: err.Error(),
+				Code:    http.StatusInternalServerError,
+			})
+			return
+		}
+		if existing != nil {
+			if err := authorizeMemoryWrite(c, storage, vectorTarget(existing)); err != nil {
+				writeMemoryAccessDenied(c, err)
+				return
+			}
+		}
+
 		if err := storage.DeleteVector(c.Request.Context(), scope, scopeID, key); err != nil {
 			logger.Logger.Error().Err(err).Msg("failed to delete vector")
 			c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -225,10 +296,27 @@ func SimilaritySearchHandler(storage MemoryStorage) gin.HandlerFunc {
 			return
 		}
 
-		if len(req.QueryEmbedding) == 0 {
+		req.QueryText = strings.TrimSpace(req.QueryText)
+		if len(req.QueryEmbedding) == 0 && req.QueryText == "" {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_request",
+				Message: "query_embedding cannot be empty without query_text",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		if err := validateVectorFilters(req.Filters); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_request",
+				Message: "invalid filters: " + err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		if req.RRFK < 0 || req.KeywordWeight < 0 {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
 				Error:   "invalid_request",
-				Message: "query_embedding cannot be empty",
+				Message: "rrf_k and keyword_weight cannot be negative",
 				Code:    http.StatusBadRequest,
 			})
 			return
@@ -239,14 +327,21 @@ func SimilaritySearchHandler(storage MemoryStorage) gin.HandlerFunc {
 		}
 
 		scope, scopeID := resolveScope(c, req.Scope)
-		results, err := storage.SimilaritySearch(
-			c.Request.Context(),
-			scope,
-			scopeID,
-			req.QueryEmbedding,
-			req.TopK,
-			req.Filters,
-		)
+		// Results the caller may not read are dropped, so the search asks for more
+		// until enough readable ones are found.
+		results, err := searchVisibleVectors(c, storage, req.TopK, func(ctx context.Context, limit int) ([]*types.VectorSearchResult, error) {
+			if req.QueryText == "" {
+				return storage.SimilaritySearch(ctx, scope, scopeID, req.QueryEmbedding, limit, req.Filters)
+			}
+			return storage.HybridSearch(ctx, scope, scopeID, types.VectorSearchQuery{
+				Embedding:     req.QueryEmbedding,
+				Text:          req.QueryText,
+				TopK:          limit,
+				Filters:       req.Filters,
+				RRFK:          req.RRFK,
+				KeywordWeight: req.KeywordWeight,
+			})
+		})
 		if err != nil {
 			logger.Logger.Error().Err(err).Msg("vector search failed")
 			c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -260,3 +355,9 @@ func SimilaritySearchHandler(storage MemoryStorage) gin.HandlerFunc {
 		c.JSON(http.StatusOK, results)
 	}
 }
+
+// validateVectorFilters rejects malformed filters up front so they surface as bad
+// requests rather than storage errors.
+func validateVectorFilters(filters map[string]interface{}) error {
+	return storage.ValidateVectorFilter(filters)
+}
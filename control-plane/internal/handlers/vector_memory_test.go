@@ -26,6 +26,7 @@ type vectorStorageStub struct {
 	searchQuery   []float32
 	searchTopK    int
 	searchFilters map[string]interface{}
+	hybridQuery   *types.VectorSearchQuery
 	// GetVector fields
 	getResult *types.VectorRecord
 	getErr    error
@@ -43,6 +44,10 @@ func (v *vectorStorageStub) SetMemory(ctx context.Context, memory *types.Memory)
 	return nil
 }
 
+func (v *vectorStorageStub) UpdateMemory(ctx context.Context, scope, scopeID, key string, update func(*types.Memory) (*types.Memory, error)) (*types.Memory, error) {
+	return nil, nil
+}
+
 func (v *vectorStorageStub) GetMemory(ctx context.Context, scope, scopeID, key string) (*types.Memory, error) {
 	return nil, errors.New("not implemented")
 }
@@ -75,6 +80,10 @@ func (v *vectorStorageStub) SetVector(ctx context.Context, record *types.VectorR
 		Key:       record.Key,
 		Embedding: append([]float32(nil), record.Embedding...),
 		Metadata:  cloneMetadata(record.Metadata),
+		Text:      record.Text,
+
+		AccessLevel:   record.AccessLevel,
+		AccessControl: record.AccessControl,
 	}
 	return nil
 }
@@ -121,6 +130,26 @@ func (v *vectorStorageStub) SimilaritySearch(ctx context.Context, scope, scopeID
 	return v.searchResults, nil
 }
 
+func (v *vectorStorageStub) GetExecutionRecord(ctx context.Context, executionID string) (*types.Execution, error) {
+	return nil, nil
+}
+
+func (v *vectorStorageStub) GetAgent(ctx context.Context, id string) (*types.AgentNode, error) {
+	return nil, nil
+}
+
+func (v *vectorStorageStub) HybridSearch(ctx context.Context, scope, scopeID string, query types.VectorSearchQuery) ([]*types.VectorSearchResult, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.searchScope = scope
+	v.searchScopeID = scopeID
+	v.hybridQuery = &query
+	if v.searchErr != nil {
+		return nil, v.searchErr
+	}
+	return v.searchResults, nil
+}
+
 func cloneMetadata(input map[string]interface{}) map[string]interface{} {
 	if input == nil {
 		return nil
@@ -319,6 +348,76 @@ func TestSimilaritySearchHandler_StorageError(t *testing.T) {
 	require.Contains(t, resp.Body.String(), "storage_error")
 }
 
+func TestSimilaritySearchHandler_QueryTextUsesHybridSearch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	storage := &vectorStorageStub{
+		searchResults: []*types.VectorSearchResult{{Key: "ticket-1", Text: "refund for AB-1234"}},
+	}
+	router := gin.New()
+	router.POST("/vectors/search", SimilaritySearchHandler(storage))
+
+	body := `{"query_embedding":[0.1,0.2],"query_text":" AB-1234 ","top_k":5,"rrf_k":20,"keyword_weight":2,"filters":{"source":"tickets"}}`
+	req := httptest.NewRequest(http.MethodPost, "/vectors/search", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Session-ID", "session-1")
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Nil(t, storage.searchQuery, "hybrid queries do not go through SimilaritySearch")
+	require.NotNil(t, storage.hybridQuery)
+	require.Equal(t, types.VectorSearchQuery{
+		Embedding:     []float32{0.1, 0.2},
+		Text:          "AB-1234",
+		TopK:          5,
+		Filters:       map[string]interface{}{"source": "tickets"},
+		RRFK:          20,
+		KeywordWeight: 2,
+	}, *storage.hybridQuery)
+	require.Contains(t, resp.Body.String(), `"text":"refund for AB-1234"`)
+}
+
+func TestSimilaritySearchHandler_QueryTextWithoutEmbedding(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	storage := &vectorStorageStub{}
+	router := gin.New()
+	router.POST("/vectors/search", SimilaritySearchHandler(storage))
+
+	body := `{"query_text":"AB-1234"}`
+	req := httptest.NewRequest(http.MethodPost, "/vectors/search", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.NotNil(t, storage.hybridQuery)
+	require.Empty(t, storage.hybridQuery.Embedding)
+	require.Equal(t, 10, storage.hybridQuery.TopK)
+}
+
+func TestSimilaritySearchHandler_InvalidFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	storage := &vectorStorageStub{}
+	router := gin.New()
+	router.POST("/vectors/search", SimilaritySearchHandler(storage))
+
+	body := `{"query_embedding":[0.1],"filters":{"year":{"$between":[2020,2024]}}}`
+	req := httptest.NewRequest(http.MethodPost, "/vectors/search", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Contains(t, resp.Body.String(), "unsupported filter operator")
+	require.Nil(t, storage.searchQuery)
+}
+
 // GetVectorHandler tests
 
 func TestGetVectorHandler_ReturnsVectorWithMetadata(t *testing.T) {
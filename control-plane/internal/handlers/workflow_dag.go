@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
 	"github.com/Agent-Field/agentfield/control-plane/internal/storage"
 	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
 
@@ -17,6 +18,7 @@ import (
 type executionRecordProvider interface {
 	QueryExecutionRecords(ctx context.Context, filter types.ExecutionFilter) ([]*types.Execution, error)
 	GetExecutionRecord(ctx context.Context, executionID string) (*types.Execution, error)
+	ListAgents(ctx context.Context, filters types.AgentFilters) ([]*types.AgentNode, error)
 }
 
 type executionGraphService struct {
@@ -36,6 +38,7 @@ type WorkflowDAGNode struct {
 	StartedAt         string                `json:"started_at"`
 	CompletedAt       *string               `json:"completed_at,omitempty"`
 	DurationMS        *int64                `json:"duration_ms,omitempty"`
+	RetryCount        int                   `json:"retry_count"`
 	ParentExecutionID *string               `json:"parent_execution_id,omitempty"`
 	WorkflowDepth     int                   `json:"workflow_depth"`
 	Children          []WorkflowDAGNode     `json:"children"`
@@ -73,6 +76,7 @@ type WorkflowDAGLightweightNode struct {
 	StartedAt         string  `json:"started_at"`
 	CompletedAt       *string `json:"completed_at,omitempty"`
 	DurationMS        *int64  `json:"duration_ms,omitempty"`
+	RetryCount        int     `json:"retry_count"`
 	WorkflowDepth     int     `json:"workflow_depth"`
 }
 
@@ -171,6 +175,10 @@ func (s *executionGraphService) handleGetWorkflowChildren(c *gin.Context) {
 		SortBy:            "started_at",
 		SortDescending:    false,
 	}
+	if err := s.restrictToTeam(ctx, &filter); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to resolve team agents: %v", err)})
+		return
+	}
 	executions, err := s.store.QueryExecutionRecords(ctx, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to query executions: %v", err)})
@@ -209,6 +217,10 @@ func (s *executionGraphService) handleGetSessionWorkflows(c *gin.Context) {
 		SortBy:         "started_at",
 		SortDescending: false,
 	}
+	if err := s.restrictToTeam(ctx, &filter); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to resolve team agents: %v", err)})
+		return
+	}
 	executions, err := s.store.QueryExecutionRecords(ctx, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to query executions: %v", err)})
@@ -262,9 +274,30 @@ func (s *executionGraphService) loadRunExecutions(ctx context.Context, runID str
 		SortBy:         "started_at",
 		SortDescending: false,
 	}
+	if err := s.restrictToTeam(ctx, &filter); err != nil {
+		return nil, err
+	}
 	return s.store.QueryExecutionRecords(ctx, filter)
 }
 
+// restrictToTeam confines an execution query to the agents of the caller's team, so
+// runs of other teams read as not found.
+func (s *executionGraphService) restrictToTeam(ctx context.Context, filter *types.ExecutionFilter) error {
+	team := auth.TeamFrom(ctx)
+	if team == "" {
+		return nil
+	}
+	agents, err := s.store.ListAgents(ctx, types.AgentFilters{TeamID: &team})
+	if err != nil {
+		return fmt.Errorf("resolve team agents: %w", err)
+	}
+	filter.AgentNodeIDs = make([]string, 0, len(agents))
+	for _, agent := range agents {
+		filter.AgentNodeIDs = append(filter.AgentNodeIDs, agent.ID)
+	}
+	return nil
+}
+
 func buildExecutionDAG(executions []*types.Execution) (WorkflowDAGNode, []WorkflowDAGNode, string, string, *string, *string, int) {
 	execMap := make(map[string]*types.Execution, len(executions))
 	childrenMap := make(map[string][]*types.Execution)
@@ -488,6 +521,7 @@ func executionToDAGNode(exec *types.Execution, depth int) WorkflowDAGNode {
 		StartedAt:         started,
 		CompletedAt:       completed,
 		DurationMS:        exec.DurationMS,
+		RetryCount:        exec.RetryCount,
 		ParentExecutionID: exec.ParentExecutionID,
 		WorkflowDepth:     depth,
 		Notes:             []types.ExecutionNote{},
@@ -501,7 +535,7 @@ func deriveOverallStatus(executions []*types.Execution) string {
 	for _, exec := range executions {
 		status := types.NormalizeExecutionStatus(exec.Status)
 		switch status {
-		case string(types.ExecutionStatusRunning), string(types.ExecutionStatusPending), string(types.ExecutionStatusQueued):
+		case string(types.ExecutionStatusRunning), string(types.ExecutionStatusPending), string(types.ExecutionStatusQueued), string(types.ExecutionStatusWaitingForApproval):
 			hasRunning = true
 		case string(types.ExecutionStatusFailed):
 			hasFailed = true
@@ -534,6 +568,7 @@ func executionToLightweightNode(exec *types.Execution, depth int) WorkflowDAGLig
 		StartedAt:         started,
 		CompletedAt:       completed,
 		DurationMS:        exec.DurationMS,
+		RetryCount:        exec.RetryCount,
 		WorkflowDepth:     depth,
 	}
 }
@@ -1,19 +1,34 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
 	"github.com/gin-gonic/gin"
 )
 
+// APIKeyLookup resolves stored, team-scoped API keys by the hash of their secret.
+type APIKeyLookup interface {
+	GetAPIKeyBySecretHash(ctx context.Context, secretHash string) (*types.APIKey, error)
+}
+
 // AuthConfig mirrors server configuration for HTTP authentication.
 type AuthConfig struct {
 	APIKey    string
 	SkipPaths []string
+	// Keys resolves stored API keys. When nil only APIKey is accepted.
+	Keys APIKeyLookup
 }
 
 // APIKeyAuth enforces API key authentication via header, bearer token, or query param.
+// The configured key acts as an admin across all teams; stored keys are confined to
+// their team and role. The caller is attached to the request context as an
+// auth.Principal.
 func APIKeyAuth(config AuthConfig) gin.HandlerFunc {
 	skipPathSet := make(map[string]struct{}, len(config.SkipPaths))
 	for _, p := range config.SkipPaths {
@@ -63,7 +78,8 @@ func APIKeyAuth(config AuthConfig) gin.HandlerFunc {
 			apiKey = c.Query("api_key")
 		}
 
-		if apiKey != config.APIKey {
+		principal := resolvePrincipal(c.Request.Context(), config, apiKey)
+		if principal == nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"error":   "unauthorized",
 				"message": "invalid or missing API key",
@@ -71,6 +87,43 @@ func APIKeyAuth(config AuthConfig) gin.HandlerFunc {
 			return
 		}
 
+		if !auth.Allows(principal, c.Request.Method, c.Request.URL.Path) {
+			message := "API key role '" + principal.Role + "' may not access this endpoint"
+			if principal.TeamScoped() && principal.Role == types.APIKeyRoleAdmin {
+				message = "team-scoped API keys may not access this endpoint"
+			}
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":   "forbidden",
+				"message": message,
+			})
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
 		c.Next()
 	}
 }
+
+// resolvePrincipal maps a presented key to its principal, or nil if the key is unknown
+// or revoked.
+func resolvePrincipal(ctx context.Context, config AuthConfig, apiKey string) *auth.Principal {
+	if apiKey == "" {
+		return nil
+	}
+	if apiKey == config.APIKey {
+		return &auth.Principal{Role: types.APIKeyRoleAdmin}
+	}
+	if config.Keys == nil {
+		return nil
+	}
+
+	key, err := config.Keys.GetAPIKeyBySecretHash(ctx, auth.HashSecret(apiKey))
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("failed to look up API key")
+		return nil
+	}
+	if key == nil || key.Revoked() {
+		return nil
+	}
+	return &auth.Principal{KeyID: key.KeyID, TeamID: key.TeamID, Role: key.Role}
+}
@@ -1,10 +1,15 @@
 package middleware
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/auth"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -284,3 +289,75 @@ func TestAPIKeyAuth_MultipleSkipPaths(t *testing.T) {
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
+
+type stubKeyLookup map[string]*types.APIKey
+
+func (s stubKeyLookup) GetAPIKeyBySecretHash(ctx context.Context, secretHash string) (*types.APIKey, error) {
+	return s[secretHash], nil
+}
+
+func TestAPIKeyAuth_StoredKeysCarryTeamAndRole(t *testing.T) {
+	revokedAt := time.Now()
+	keys := stubKeyLookup{
+		auth.HashSecret("afk_operator"): {KeyID: "key-op", TeamID: "team-a", Role: types.APIKeyRoleOperator},
+		auth.HashSecret("afk_reader"):   {KeyID: "key-ro", TeamID: "team-a", Role: types.APIKeyRoleReadOnly},
+		auth.HashSecret("afk_admin"):    {KeyID: "key-admin", TeamID: "team-a", Role: types.APIKeyRoleAdmin},
+		auth.HashSecret("afk_revoked"):  {KeyID: "key-old", TeamID: "team-a", Role: types.APIKeyRoleAdmin, RevokedAt: &revokedAt},
+	}
+
+	var seen *auth.Principal
+	router := gin.New()
+	router.Use(APIKeyAuth(AuthConfig{APIKey: "secret-key", Keys: keys}))
+	handler := func(c *gin.Context) {
+		seen = auth.PrincipalFrom(c.Request.Context())
+		c.Status(http.StatusOK)
+	}
+	router.GET("/api/v1/nodes", handler)
+	router.POST("/api/v1/execute/:target", handler)
+	router.GET("/api/v1/memory/export", handler)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		key    string
+		want   int
+	}{
+		{"operator executes", http.MethodPost, "/api/v1/execute/node.r", "afk_operator", http.StatusOK},
+		{"reader lists", http.MethodGet, "/api/v1/nodes", "afk_reader", http.StatusOK},
+		{"reader cannot execute", http.MethodPost, "/api/v1/execute/node.r", "afk_reader", http.StatusForbidden},
+		{"team admin lists", http.MethodGet, "/api/v1/nodes", "afk_admin", http.StatusOK},
+		{"team admin cannot export memory", http.MethodGet, "/api/v1/memory/export", "afk_admin", http.StatusForbidden},
+		{"revoked key", http.MethodGet, "/api/v1/nodes", "afk_revoked", http.StatusUnauthorized},
+		{"unknown key", http.MethodGet, "/api/v1/nodes", "afk_unknown", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = nil
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("X-API-Key", tt.key)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.want, w.Code)
+			if tt.want == http.StatusOK {
+				require.NotNil(t, seen)
+				assert.Equal(t, "team-a", seen.TeamID)
+			}
+		})
+	}
+
+	// The configured key is an admin spanning every team.
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/nodes", nil)
+	req.Header.Set("X-API-Key", "secret-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, seen)
+	assert.False(t, seen.TeamScoped())
+	assert.Equal(t, types.APIKeyRoleAdmin, seen.Role)
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/memory/export", nil)
+	req.Header.Set("X-API-Key", "secret-key")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -64,6 +64,8 @@ type AgentFieldServer struct {
 	agentfieldHome  string
 	// Cleanup service
 	cleanupService        *handlers.ExecutionCleanupService
+	scheduleService       *handlers.ScheduleService
+	approvalService       *handlers.ApprovalService
 	payloadStore          services.PayloadStore
 	registryWatcherCancel context.CancelFunc
 	adminGRPCServer       *grpc.Server
@@ -95,6 +97,11 @@ func NewAgentFieldServer(cfg *config.Config) (*AgentFieldServer, error) {
 	if err != nil {
 		return nil, err
 	}
+	if relay, ok := storageProvider.(interface{ EventBackplane() events.Backplane }); ok {
+		if bp := relay.EventBackplane(); bp != nil {
+			events.AttachBackplane(bp)
+		}
+	}
 
 	Router := gin.Default()
 
@@ -224,6 +231,38 @@ func NewAgentFieldServer(cfg *config.Config) (*AgentFieldServer, error) {
 		fmt.Println("⚠️ DID and VC services are DISABLED in configuration")
 	}
 
+	// Encrypt memory values at rest with keys kept in the keystore, which is created here
+	// when DID is disabled.
+	if cfg.Storage.MemoryEncryption.Enabled {
+		if keystoreService == nil {
+			keystoreCfg := &cfg.Features.DID.Keystore
+			if keystoreCfg.Path == "" || keystoreCfg.Path == "./data/keys" {
+				keystoreCfg.Path = dirs.KeysDir
+			}
+			if keystoreCfg.Type == "" {
+				keystoreCfg.Type = "local"
+			}
+			keystoreService, err = services.NewKeystoreService(keystoreCfg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create keystore service: %w", err)
+			}
+		}
+		encrypted, ok := storageProvider.(interface {
+			services.MemoryKeyStore
+			EnableMemoryEncryption(keys storage.MemoryKeyProvider) error
+		})
+		if !ok {
+			return nil, fmt.Errorf("storage provider does not support memory encryption")
+		}
+		// The keys live in storage so that replicas sharing a database share them.
+		if err := keystoreService.UseMemoryKeyStore(context.Background(), encrypted); err != nil {
+			return nil, fmt.Errorf("failed to attach memory keys: %w", err)
+		}
+		if err := encrypted.EnableMemoryEncryption(keystoreService); err != nil {
+			return nil, err
+		}
+	}
+
 	payloadStore := services.NewFilePayloadStore(dirs.PayloadsDir)
 
 	webhookDispatcher := services.NewWebhookDispatcher(storageProvider, services.WebhookDispatcherConfig{
@@ -254,6 +293,23 @@ func NewAgentFieldServer(cfg *config.Config) (*AgentFieldServer, error) {
 	// Initialize execution cleanup service
 	cleanupService := handlers.NewExecutionCleanupService(storageProvider, cfg.AgentField.ExecutionCleanup)
 
+	// Apply per-node, per-reasoner and per-caller execution limits
+	handlers.ConfigureExecutionLimits(cfg.AgentField.ExecutionLimits)
+
+	// Balance executions across replicas registered under the same node ID
+	if err := handlers.ConfigureInstanceSelection(presenceManager, cfg.AgentField.ExecutionQueue.InstanceSelection); err != nil {
+		return nil, err
+	}
+
+	// Initialize the scheduler that fires cron schedules
+	scheduleService := handlers.NewScheduleService(storageProvider, payloadStore, webhookDispatcher, cfg.AgentField.ExecutionQueue.AgentCallTimeout)
+
+	// Initialize human approvals and the sweep applying their timeout decision
+	approvalService, err := handlers.NewApprovalService(storageProvider, cfg.AgentField.Approvals)
+	if err != nil {
+		return nil, err
+	}
+
 	adminPort := cfg.AgentField.Port + 100
 	if envPort := os.Getenv("AGENTFIELD_ADMIN_GRPC_PORT"); envPort != "" {
 		if parsedPort, parseErr := strconv.Atoi(envPort); parseErr == nil {
@@ -281,6 +337,8 @@ func NewAgentFieldServer(cfg *config.Config) (*AgentFieldServer, error) {
 		didRegistry:           didRegistry,
 		agentfieldHome:        agentfieldHome,
 		cleanupService:        cleanupService,
+		scheduleService:       scheduleService,
+		approvalService:       approvalService,
 		payloadStore:          payloadStore,
 		webhookDispatcher:        webhookDispatcher,
 		observabilityForwarder:   observabilityForwarder,
@@ -327,6 +385,23 @@ func (s *AgentFieldServer) Start() error {
 		// Don't fail server startup if cleanup service fails to start
 	}
 
+	// Resume async executions accepted before the last shutdown
+	handlers.StartExecutionQueue(s.storage, s.payloadStore, s.webhookDispatcher, s.config.AgentField.ExecutionQueue.AgentCallTimeout)
+
+	// Fire cron schedules; replicas elect a single leader through a storage lock
+	if s.scheduleService != nil {
+		if err := s.scheduleService.Start(ctx); err != nil {
+			logger.Logger.Error().Err(err).Msg("Failed to start schedule service")
+		}
+	}
+
+	// Apply the default decision to approvals nobody answered in time
+	if s.approvalService != nil {
+		if err := s.approvalService.Start(ctx); err != nil {
+			logger.Logger.Error().Err(err).Msg("Failed to start approval service")
+		}
+	}
+
 	// Start reasoner event heartbeat (30 second intervals)
 	events.StartHeartbeat(30 * time.Second)
 
@@ -442,6 +517,22 @@ func (s *AgentFieldServer) Stop() error {
 		s.registryWatcherCancel = nil
 	}
 
+	if s.approvalService != nil {
+		if err := s.approvalService.Stop(); err != nil {
+			logger.Logger.Error().Err(err).Msg("Failed to stop approval service")
+		}
+	}
+
+	// Stop firing schedules before the queue they submit to
+	if s.scheduleService != nil {
+		if err := s.scheduleService.Stop(); err != nil {
+			logger.Logger.Error().Err(err).Msg("Failed to stop schedule service")
+		}
+	}
+
+	// Stop dispatching queued async executions; pending entries stay in storage
+	handlers.StopExecutionQueue(s.storage)
+
 	// Stop UI service heartbeat
 	if s.uiService != nil {
 		s.uiService.StopHeartbeat()
@@ -656,6 +747,7 @@ func (s *AgentFieldServer) setupRoutes() {
 	s.Router.Use(middleware.APIKeyAuth(middleware.AuthConfig{
 		APIKey:    s.config.API.Auth.APIKey,
 		SkipPaths: s.config.API.Auth.SkipPaths,
+		Keys:      s.storage,
 	}))
 	if s.config.API.Auth.APIKey != "" {
 		logger.Logger.Info().Msg("🔐 API key authentication enabled")
@@ -930,7 +1022,7 @@ func (s *AgentFieldServer) setupRoutes() {
 		agentAPI.POST("/nodes/:node_id/stop", handlers.StopNodeHandler(s.statusManager, s.storage))
 		agentAPI.POST("/nodes/:node_id/lifecycle/status", handlers.UpdateLifecycleStatusHandler(s.storage, s.uiService, s.statusManager))
 		agentAPI.PATCH("/nodes/:node_id/status", handlers.NodeStatusLeaseHandler(s.storage, s.statusManager, s.presenceManager, handlers.DefaultLeaseTTL))
-		agentAPI.POST("/nodes/:node_id/actions/ack", handlers.NodeActionAckHandler(s.storage, s.presenceManager, handlers.DefaultLeaseTTL))
+		agentAPI.POST("/nodes/:node_id/actions/ack", handlers.NodeActionAckHandler(s.storage, s.presenceManager, s.payloadStore, s.webhookDispatcher, handlers.DefaultLeaseTTL))
 		agentAPI.POST("/nodes/:node_id/shutdown", handlers.NodeShutdownHandler(s.storage, s.statusManager, s.presenceManager))
 		agentAPI.POST("/actions/claim", handlers.ClaimActionsHandler(s.storage, s.presenceManager, handlers.DefaultLeaseTTL))
 
@@ -945,9 +1037,33 @@ func (s *AgentFieldServer) setupRoutes() {
 		// Unified execution endpoints (path-based)
 		agentAPI.POST("/execute/:target", handlers.ExecuteHandler(s.storage, s.payloadStore, s.webhookDispatcher, s.config.AgentField.ExecutionQueue.AgentCallTimeout))
 		agentAPI.POST("/execute/async/:target", handlers.ExecuteAsyncHandler(s.storage, s.payloadStore, s.webhookDispatcher, s.config.AgentField.ExecutionQueue.AgentCallTimeout))
+		agentAPI.POST("/execute/stream/:target", handlers.ExecuteStreamHandler(s.storage, s.payloadStore, s.webhookDispatcher, s.config.AgentField.ExecutionQueue.AgentCallTimeout))
 		agentAPI.GET("/executions/:execution_id", handlers.GetExecutionStatusHandler(s.storage))
 		agentAPI.POST("/executions/batch-status", handlers.BatchExecutionStatusHandler(s.storage))
 		agentAPI.POST("/executions/:execution_id/status", handlers.UpdateExecutionStatusHandler(s.storage, s.payloadStore, s.webhookDispatcher, s.config.AgentField.ExecutionQueue.AgentCallTimeout))
+		agentAPI.POST("/executions/:execution_id/cancel", handlers.CancelExecutionHandler(s.storage, s.payloadStore, s.webhookDispatcher, s.config.AgentField.ExecutionQueue.AgentCallTimeout))
+
+		// Human approvals; agents request and poll them through their execution
+		agentAPI.POST("/executions/:execution_id/approvals", s.approvalService.RequestApprovalHandler)
+		agentAPI.GET("/executions/:execution_id/approvals/:approval_id", s.approvalService.GetApprovalHandler)
+		agentAPI.GET("/approvals", s.approvalService.ListApprovalsHandler)
+		agentAPI.GET("/approvals/:approval_id", s.approvalService.GetApprovalHandler)
+		agentAPI.POST("/approvals/:approval_id/approve", s.approvalService.ApproveHandler)
+		agentAPI.POST("/approvals/:approval_id/reject", s.approvalService.RejectHandler)
+
+		// Cron schedules
+		agentAPI.POST("/schedules", handlers.CreateScheduleHandler(s.storage))
+		agentAPI.GET("/schedules", handlers.ListSchedulesHandler(s.storage))
+		agentAPI.GET("/schedules/:schedule_id", handlers.GetScheduleHandler(s.storage))
+		agentAPI.PATCH("/schedules/:schedule_id", handlers.UpdateScheduleHandler(s.storage))
+		agentAPI.DELETE("/schedules/:schedule_id", handlers.DeleteScheduleHandler(s.storage))
+		agentAPI.GET("/schedules/:schedule_id/runs", handlers.ListScheduleRunsHandler(s.storage))
+
+		// Team-scoped API keys
+		agentAPI.POST("/keys", handlers.CreateAPIKeyHandler(s.storage))
+		agentAPI.GET("/keys", handlers.ListAPIKeysHandler(s.storage))
+		agentAPI.POST("/keys/:key_id/rotate", handlers.RotateAPIKeyHandler(s.storage))
+		agentAPI.DELETE("/keys/:key_id", handlers.RevokeAPIKeyHandler(s.storage))
 
 		// Execution notes endpoints for app.note() feature
 		agentAPI.POST("/executions/note", handlers.AddExecutionNoteHandler(s.storage))
@@ -961,6 +1077,17 @@ func (s *AgentFieldServer) setupRoutes() {
 		agentAPI.POST("/memory/get", handlers.GetMemoryHandler(s.storage))
 		agentAPI.POST("/memory/delete", handlers.DeleteMemoryHandler(s.storage))
 		agentAPI.GET("/memory/list", handlers.ListMemoryHandler(s.storage))
+		agentAPI.POST("/memory/increment", handlers.IncrementMemoryHandler(s.storage))
+		agentAPI.POST("/memory/append", handlers.AppendMemoryHandler(s.storage))
+		agentAPI.POST("/memory/merge", handlers.MergeMemoryHandler(s.storage))
+		agentAPI.GET("/memory/export", handlers.ExportMemoryHandler(s.storage))
+		agentAPI.POST("/memory/import", handlers.ImportMemoryHandler(s.storage))
+		agentAPI.POST("/memory/restore", handlers.RestoreMemoryHandler(s.storage))
+		if reencrypter, ok := s.storage.(handlers.MemoryReencrypter); ok && s.config.Storage.MemoryEncryption.Enabled && s.keystoreService != nil {
+			agentAPI.GET("/memory/encryption", handlers.GetMemoryEncryptionHandler(s.keystoreService, s.config.Storage.MemoryEncryption.Scopes))
+			agentAPI.POST("/memory/encryption/rotate", handlers.RotateMemoryKeyHandler(s.keystoreService, reencrypter))
+			agentAPI.POST("/memory/encryption/reencrypt", handlers.ReencryptMemoryHandler(reencrypter))
+		}
 
 		// Vector Memory endpoints (RESTful)
 		agentAPI.POST("/memory/vector", handlers.SetVectorHandler(s.storage))
@@ -113,6 +113,102 @@ func (s *stubStorage) StoreWorkflowExecutionEvent(ctx context.Context, event *ty
 func (s *stubStorage) ListWorkflowExecutionEvents(ctx context.Context, executionID string, afterSeq *int64, limit int) ([]*types.WorkflowExecutionEvent, error) {
 	return nil, nil
 }
+func (s *stubStorage) EnqueueExecutionAction(ctx context.Context, action *types.ExecutionAction) error {
+	return nil
+}
+func (s *stubStorage) ClaimExecutionActions(ctx context.Context, nodeID string, limit int, leaseTTL time.Duration) ([]*types.ExecutionAction, error) {
+	return nil, nil
+}
+func (s *stubStorage) GetExecutionAction(ctx context.Context, actionID string) (*types.ExecutionAction, error) {
+	return nil, nil
+}
+func (s *stubStorage) ExtendExecutionActionLease(ctx context.Context, actionID string, leaseTTL time.Duration) error {
+	return nil
+}
+func (s *stubStorage) CompleteExecutionAction(ctx context.Context, actionID string) (bool, error) {
+	return false, nil
+}
+func (s *stubStorage) EnqueueExecution(ctx context.Context, item *types.QueuedExecution) error {
+	return nil
+}
+func (s *stubStorage) ListQueuedExecutions(ctx context.Context, limit int) ([]*types.QueuedExecution, error) {
+	return nil, nil
+}
+func (s *stubStorage) DeleteQueuedExecution(ctx context.Context, executionID string) error {
+	return nil
+}
+func (s *stubStorage) GetCachedResult(ctx context.Context, teamID, reasoner, inputHash string) ([]byte, error) {
+	return nil, nil
+}
+func (s *stubStorage) StoreCachedResult(ctx context.Context, teamID, reasoner, inputHash string, result []byte, ttl time.Duration) error {
+	return nil
+}
+func (s *stubStorage) CreateSchedule(ctx context.Context, schedule *types.Schedule) error {
+	return nil
+}
+func (s *stubStorage) GetSchedule(ctx context.Context, scheduleID string) (*types.Schedule, error) {
+	return nil, nil
+}
+func (s *stubStorage) ListSchedules(ctx context.Context) ([]*types.Schedule, error) {
+	return nil, nil
+}
+func (s *stubStorage) UpdateSchedule(ctx context.Context, schedule *types.Schedule) error {
+	return nil
+}
+func (s *stubStorage) ClaimScheduleRun(ctx context.Context, schedule *types.Schedule, dueAt time.Time) (bool, error) {
+	return false, nil
+}
+func (s *stubStorage) RecordScheduleRun(ctx context.Context, scheduleID string, executionID, lastError *string) error {
+	return nil
+}
+func (s *stubStorage) DeleteSchedule(ctx context.Context, scheduleID string) error {
+	return nil
+}
+func (s *stubStorage) ListDueSchedules(ctx context.Context, now time.Time, limit int) ([]*types.Schedule, error) {
+	return nil, nil
+}
+func (s *stubStorage) UpsertAgentInstance(ctx context.Context, instance *types.AgentInstance) error {
+	return nil
+}
+func (s *stubStorage) ListAgentInstances(ctx context.Context, nodeID string) ([]*types.AgentInstance, error) {
+	return nil, nil
+}
+func (s *stubStorage) TouchAgentInstance(ctx context.Context, nodeID, instanceID string, seenAt time.Time) error {
+	return nil
+}
+func (s *stubStorage) DeleteAgentInstance(ctx context.Context, nodeID, instanceID string) error {
+	return nil
+}
+func (s *stubStorage) CreateAPIKey(ctx context.Context, key *types.APIKey) error {
+	return nil
+}
+func (s *stubStorage) GetAPIKey(ctx context.Context, keyID string) (*types.APIKey, error) {
+	return nil, nil
+}
+func (s *stubStorage) GetAPIKeyBySecretHash(ctx context.Context, secretHash string) (*types.APIKey, error) {
+	return nil, nil
+}
+func (s *stubStorage) ListAPIKeys(ctx context.Context, teamID string) ([]*types.APIKey, error) {
+	return nil, nil
+}
+func (s *stubStorage) UpdateAPIKey(ctx context.Context, key *types.APIKey) error {
+	return nil
+}
+func (s *stubStorage) CreateApproval(ctx context.Context, approval *types.Approval) error {
+	return nil
+}
+func (s *stubStorage) GetApproval(ctx context.Context, approvalID string) (*types.Approval, error) {
+	return nil, nil
+}
+func (s *stubStorage) ListApprovals(ctx context.Context, filter types.ApprovalFilter) ([]*types.Approval, error) {
+	return nil, nil
+}
+func (s *stubStorage) ListExpiredApprovals(ctx context.Context, now time.Time, limit int) ([]*types.Approval, error) {
+	return nil, nil
+}
+func (s *stubStorage) ResolveApproval(ctx context.Context, approval *types.Approval) (bool, error) {
+	return false, nil
+}
 func (s *stubStorage) CleanupOldExecutions(ctx context.Context, retentionPeriod time.Duration, batchSize int) (int, error) {
 	return 0, nil
 }
@@ -146,6 +242,9 @@ func (s *stubStorage) QuerySessions(ctx context.Context, filters types.SessionFi
 
 // Memory operations
 func (s *stubStorage) SetMemory(ctx context.Context, memory *types.Memory) error { return nil }
+func (s *stubStorage) UpdateMemory(ctx context.Context, scope, scopeID, key string, update func(*types.Memory) (*types.Memory, error)) (*types.Memory, error) {
+	return nil, nil
+}
 func (s *stubStorage) GetMemory(ctx context.Context, scope, scopeID, key string) (*types.Memory, error) {
 	return nil, nil
 }
@@ -164,6 +263,15 @@ func (s *stubStorage) DeleteVectorsByPrefix(ctx context.Context, scope, scopeID,
 func (s *stubStorage) SimilaritySearch(ctx context.Context, scope, scopeID string, queryEmbedding []float32, topK int, filters map[string]interface{}) ([]*types.VectorSearchResult, error) {
 	return nil, nil
 }
+func (s *stubStorage) HybridSearch(ctx context.Context, scope, scopeID string, query types.VectorSearchQuery) ([]*types.VectorSearchResult, error) {
+	return nil, nil
+}
+func (s *stubStorage) ExportMemory(ctx context.Context, filter types.MemorySnapshotFilter, emit func(*types.Memory) error) error {
+	return nil
+}
+func (s *stubStorage) ExportVectors(ctx context.Context, filter types.MemorySnapshotFilter, emit func(*types.VectorRecord) error) error {
+	return nil
+}
 
 // Event operations
 func (s *stubStorage) StoreEvent(ctx context.Context, event *types.MemoryChangeEvent) error {
@@ -16,7 +16,7 @@ var (
 		Help: "Number of workflow steps currently queued or in-flight for execution.",
 	})
 
-	workerInflightGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{ //nolint:unused // Reserved for future use
+	workerInflightGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "agentfield_worker_inflight",
 		Help: "Number of active worker executions grouped by agent node.",
 	}, []string{"agent"})
@@ -57,12 +57,10 @@ func recordWaiterCount(count int) {
 	waiterInflightGauge.Set(float64(count))
 }
 
-//nolint:unused // Reserved for future use
 func recordWorkerAcquire(agent string) {
 	workerInflightGauge.WithLabelValues(normalizeAgentLabel(agent)).Inc()
 }
 
-//nolint:unused // Reserved for future use
 func recordWorkerRelease(agent string) {
 	workerInflightGauge.WithLabelValues(normalizeAgentLabel(agent)).Dec()
 }
@@ -90,6 +88,16 @@ func RecordGatewayBackpressure(reason string) {
 	incrementBackpressure(reason)
 }
 
+// RecordWorkerAcquire tracks an execution admitted against an agent node's in-flight limit.
+func RecordWorkerAcquire(agent string) {
+	recordWorkerAcquire(agent)
+}
+
+// RecordWorkerRelease tracks an admitted execution settling.
+func RecordWorkerRelease(agent string) {
+	recordWorkerRelease(agent)
+}
+
 func normalizeAgentLabel(agent string) string {
 	agent = strings.TrimSpace(agent)
 	if agent == "" {
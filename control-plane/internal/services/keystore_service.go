@@ -12,19 +12,29 @@ import (
 	"github.com/Agent-Field/agentfield/control-plane/internal/config"
 )
 
+// masterKeyFile holds the key that encrypts every other key in a local keystore, so
+// stored keys can still be read after a restart.
+const masterKeyFile = "keystore.master"
+
 // KeystoreService handles secure storage and management of cryptographic keys.
 type KeystoreService struct {
-	config *config.KeystoreConfig
-	gcm    cipher.AEAD
+	config     *config.KeystoreConfig
+	gcm        cipher.AEAD
+	memoryKeys memoryKeyCache
 }
 
 // NewKeystoreService creates a new keystore service instance.
 func NewKeystoreService(cfg *config.KeystoreConfig) (*KeystoreService, error) {
-	// For now, use a simple AES-GCM encryption with a fixed key
+	// Ensure keystore directory exists
+	if err := os.MkdirAll(cfg.Path, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create keystore directory: %w", err)
+	}
+
+	// For now, use a simple AES-GCM encryption with a master key kept beside the keys
 	// In production, this should use proper key derivation and HSM integration
-	key := make([]byte, 32) // 256-bit key
-	if _, err := rand.Read(key); err != nil {
-		return nil, fmt.Errorf("failed to generate encryption key: %w", err)
+	key, err := loadMasterKey(cfg.Path)
+	if err != nil {
+		return nil, err
 	}
 
 	block, err := aes.NewCipher(key)
@@ -37,17 +47,54 @@ func NewKeystoreService(cfg *config.KeystoreConfig) (*KeystoreService, error) {
 		return nil, fmt.Errorf("failed to create GCM: %w", err)
 	}
 
-	// Ensure keystore directory exists
-	if err := os.MkdirAll(cfg.Path, 0700); err != nil {
-		return nil, fmt.Errorf("failed to create keystore directory: %w", err)
-	}
-
 	return &KeystoreService{
 		config: cfg,
 		gcm:    gcm,
 	}, nil
 }
 
+// loadMasterKey reads the keystore's master key, generating it on first use.
+func loadMasterKey(dir string) ([]byte, error) {
+	path := filepath.Join(dir, masterKeyFile)
+	key, err := os.ReadFile(path)
+	if err == nil {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("master key %s must be 32 bytes, got %d", path, len(key))
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read master key: %w", err)
+	}
+
+	key = make([]byte, 32) // 256-bit key
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate encryption key: %w", err)
+	}
+
+	// The key is written to a temporary file and linked into place, which fails if
+	// another process created the master key first; that key is used instead.
+	tmp, err := os.CreateTemp(dir, masterKeyFile+".*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create master key file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(key); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to write master key: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write master key: %w", err)
+	}
+	if err := os.Link(tmp.Name(), path); err != nil {
+		if os.IsExist(err) {
+			return loadMasterKey(dir)
+		}
+		return nil, fmt.Errorf("failed to store master key: %w", err)
+	}
+	return key, nil
+}
+
 // StoreKey stores a key securely in the keystore.
 func (ks *KeystoreService) StoreKey(keyID string, keyData []byte) error {
 	if ks.config.Type != "local" {
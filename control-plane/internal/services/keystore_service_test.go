@@ -2,8 +2,12 @@ package services
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"sync"
 	"testing"
 
 	"github.com/Agent-Field/agentfield/control-plane/internal/config"
@@ -68,3 +72,151 @@ func TestKeystoreServiceRejectsNonLocal(t *testing.T) {
 	_, err = svc.ListKeys()
 	require.Error(t, err)
 }
+
+func TestKeystoreServiceKeysSurviveRestart(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.KeystoreConfig{Path: t.TempDir(), Type: "local"}
+	svc, err := NewKeystoreService(cfg)
+	require.NoError(t, err)
+	require.NoError(t, svc.StoreKey("agent-secret", []byte("super-secret")))
+
+	restarted, err := NewKeystoreService(cfg)
+	require.NoError(t, err)
+	retrieved, err := restarted.RetrieveKey("agent-secret")
+	require.NoError(t, err)
+	require.Equal(t, []byte("super-secret"), retrieved)
+
+	keys, err := restarted.ListKeys()
+	require.NoError(t, err)
+	require.Equal(t, []string{"agent-secret"}, keys, "the master key is not listed")
+}
+
+func TestKeystoreServiceMemoryKeys(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.KeystoreConfig{Path: t.TempDir(), Type: "local"}
+	svc, err := NewKeystoreService(cfg)
+	require.NoError(t, err)
+
+	firstID, firstKey, err := svc.CurrentMemoryKey()
+	require.NoError(t, err)
+	require.Len(t, firstKey, 32)
+	sameID, _, err := svc.CurrentMemoryKey()
+	require.NoError(t, err)
+	require.Equal(t, firstID, sameID)
+
+	rotatedID, err := svc.RotateMemoryKey()
+	require.NoError(t, err)
+	require.NotEqual(t, firstID, rotatedID)
+	currentID, currentKey, err := svc.CurrentMemoryKey()
+	require.NoError(t, err)
+	require.Equal(t, rotatedID, currentID)
+	require.NotEqual(t, firstKey, currentKey)
+
+	ids, err := svc.MemoryKeyIDs()
+	require.NoError(t, err)
+	require.Equal(t, []string{firstID, rotatedID}, ids)
+
+	// Another instance, as after a restart, reads the same keys.
+	restarted, err := NewKeystoreService(cfg)
+	require.NoError(t, err)
+	currentID, _, err = restarted.CurrentMemoryKey()
+	require.NoError(t, err)
+	require.Equal(t, rotatedID, currentID)
+	key, err := restarted.MemoryKey(firstID)
+	require.NoError(t, err)
+	require.Equal(t, firstKey, key)
+
+	require.NoError(t, svc.StoreKey("agent-secret", []byte("super-secret")))
+	_, err = svc.MemoryKey("agent-secret")
+	require.Error(t, err, "keys outside the memory keys are not handed out")
+}
+
+// memoryKeyStoreStub stands in for the shared database in the memory key tests.
+type memoryKeyStoreStub struct {
+	mu   sync.Mutex
+	keys map[string][]byte
+}
+
+func (s *memoryKeyStoreStub) StoreMemoryKey(ctx context.Context, keyID string, wrapped []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keys == nil {
+		s.keys = make(map[string][]byte)
+	}
+	if _, ok := s.keys[keyID]; !ok {
+		s.keys[keyID] = wrapped
+	}
+	return nil
+}
+
+func (s *memoryKeyStoreStub) GetMemoryKey(ctx context.Context, keyID string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wrapped, ok := s.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("memory key '%s' not found", keyID)
+	}
+	return wrapped, nil
+}
+
+func (s *memoryKeyStoreStub) ListMemoryKeyIDs(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0, len(s.keys))
+	for id := range s.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func TestKeystoreServiceSharesMemoryKeysThroughStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := &memoryKeyStoreStub{}
+
+	// A keystore that created a memory key before the store was attached copies it over.
+	firstDir := t.TempDir()
+	first, err := NewKeystoreService(&config.KeystoreConfig{Path: firstDir, Type: "local"})
+	require.NoError(t, err)
+	localID, localKey, err := first.CurrentMemoryKey()
+	require.NoError(t, err)
+	require.NoError(t, first.UseMemoryKeyStore(ctx, store))
+	rotatedID, err := first.RotateMemoryKey()
+	require.NoError(t, err)
+
+	ids, err := store.ListMemoryKeyIDs(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{localID, rotatedID}, ids)
+	for _, wrapped := range store.keys {
+		require.NotContains(t, string(wrapped), string(localKey), "keys are stored wrapped")
+	}
+
+	// A replica with its own directory but the same master key reads every key.
+	secondDir := t.TempDir()
+	master, err := os.ReadFile(filepath.Join(firstDir, masterKeyFile))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(secondDir, masterKeyFile), master, 0600))
+	second, err := NewKeystoreService(&config.KeystoreConfig{Path: secondDir, Type: "local"})
+	require.NoError(t, err)
+	require.NoError(t, second.UseMemoryKeyStore(ctx, store))
+	currentID, currentKey, err := second.CurrentMemoryKey()
+	require.NoError(t, err)
+	require.Equal(t, rotatedID, currentID)
+	_, firstCurrent, err := first.CurrentMemoryKey()
+	require.NoError(t, err)
+	require.Equal(t, firstCurrent, currentKey)
+	key, err := second.MemoryKey(localID)
+	require.NoError(t, err)
+	require.Equal(t, localKey, key)
+
+	// A replica with another master key cannot unwrap the keys and is refused.
+	other, err := NewKeystoreService(&config.KeystoreConfig{Path: t.TempDir(), Type: "local"})
+	require.NoError(t, err)
+	err = other.UseMemoryKeyStore(ctx, store)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), masterKeyFile)
+}
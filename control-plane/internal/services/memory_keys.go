@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryKeyPrefix marks the keystore keys that encrypt memory values. The rest of the
+// ID is the key's creation time, so IDs sort from oldest to newest.
+const memoryKeyPrefix = "memory-"
+
+// memoryKeyRefresh is how long the current memory key is trusted before the keystore
+// is checked for a newer one, such as a key rotated by another replica.
+const memoryKeyRefresh = time.Minute
+
+// MemoryKeyStore keeps wrapped memory keys in the database the control plane replicas
+// share. The storage provider implements it.
+type MemoryKeyStore interface {
+	StoreMemoryKey(ctx context.Context, keyID string, wrapped []byte) error
+	GetMemoryKey(ctx context.Context, keyID string) ([]byte, error)
+	ListMemoryKeyIDs(ctx context.Context) ([]string, error)
+}
+
+// memoryKeyCache keeps decrypted memory keys so that memory reads and writes do not
+// touch the keystore files or the key store.
+type memoryKeyCache struct {
+	mu        sync.Mutex
+	store     MemoryKeyStore // nil keeps the keys in the keystore directory
+	currentID string
+	checkedAt time.Time
+	keys      map[string][]byte
+}
+
+// UseMemoryKeyStore moves the memory keys to store, so replicas sharing the database
+// share the keys; memory keys already in the keystore directory are copied over. The
+// keys are wrapped with the keystore master key, so replicas must share its master
+// key file. A keystore with another master key is refused here instead of failing
+// every read of encrypted memory.
+func (ks *KeystoreService) UseMemoryKeyStore(ctx context.Context, store MemoryKeyStore) error {
+	ks.memoryKeys.mu.Lock()
+	defer ks.memoryKeys.mu.Unlock()
+
+	local, err := ks.listMemoryKeys()
+	if err != nil {
+		return err
+	}
+	for _, keyID := range local {
+		key, err := ks.RetrieveKey(keyID)
+		if err != nil {
+			return fmt.Errorf("failed to load memory key '%s': %w", keyID, err)
+		}
+		wrapped, err := ks.EncryptData(key)
+		if err != nil {
+			return err
+		}
+		if err := store.StoreMemoryKey(ctx, keyID, wrapped); err != nil {
+			return err
+		}
+	}
+
+	ids, err := store.ListMemoryKeyIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, keyID := range ids {
+		wrapped, err := store.GetMemoryKey(ctx, keyID)
+		if err != nil {
+			return err
+		}
+		if _, err := ks.DecryptData(wrapped); err != nil {
+			return fmt.Errorf("memory key '%s' was stored with another keystore master key; every replica must use the same %s: %w", keyID, masterKeyFile, err)
+		}
+	}
+
+	ks.memoryKeys.store = store
+	ks.memoryKeys.currentID = ""
+	ks.memoryKeys.keys = nil
+	return nil
+}
+
+// CurrentMemoryKey returns the key new memory values are encrypted with, creating the
+// first memory key when there is none.
+func (ks *KeystoreService) CurrentMemoryKey() (string, []byte, error) {
+	ks.memoryKeys.mu.Lock()
+	defer ks.memoryKeys.mu.Unlock()
+
+	if ks.memoryKeys.currentID == "" || time.Since(ks.memoryKeys.checkedAt) > memoryKeyRefresh {
+		ids, err := ks.currentMemoryKeyIDs()
+		if err != nil {
+			return "", nil, err
+		}
+		if len(ids) == 0 {
+			if _, err := ks.createMemoryKey(); err != nil {
+				return "", nil, err
+			}
+		} else {
+			ks.memoryKeys.currentID = ids[len(ids)-1]
+			ks.memoryKeys.checkedAt = time.Now()
+		}
+	}
+
+	key, err := ks.loadMemoryKey(ks.memoryKeys.currentID)
+	if err != nil {
+		return "", nil, err
+	}
+	return ks.memoryKeys.currentID, key, nil
+}
+
+// MemoryKey returns the memory key with the given ID.
+func (ks *KeystoreService) MemoryKey(keyID string) ([]byte, error) {
+	if !strings.HasPrefix(keyID, memoryKeyPrefix) {
+		return nil, fmt.Errorf("key '%s' is not a memory key", keyID)
+	}
+
+	ks.memoryKeys.mu.Lock()
+	defer ks.memoryKeys.mu.Unlock()
+	return ks.loadMemoryKey(keyID)
+}
+
+// RotateMemoryKey creates a new memory key and makes it current. Values encrypted with
+// older keys stay readable until they are re-encrypted.
+func (ks *KeystoreService) RotateMemoryKey() (string, error) {
+	ks.memoryKeys.mu.Lock()
+	defer ks.memoryKeys.mu.Unlock()
+	return ks.createMemoryKey()
+}
+
+// MemoryKeyIDs lists the IDs of the memory keys from oldest to newest.
+func (ks *KeystoreService) MemoryKeyIDs() ([]string, error) {
+	ks.memoryKeys.mu.Lock()
+	defer ks.memoryKeys.mu.Unlock()
+	return ks.currentMemoryKeyIDs()
+}
+
+// currentMemoryKeyIDs lists the memory keys in use, from oldest to newest. It must be
+// called with the cache lock held.
+func (ks *KeystoreService) currentMemoryKeyIDs() ([]string, error) {
+	if ks.memoryKeys.store != nil {
+		return ks.memoryKeys.store.ListMemoryKeyIDs(context.Background())
+	}
+	return ks.listMemoryKeys()
+}
+
+// listMemoryKeys lists the memory keys in the keystore directory.
+func (ks *KeystoreService) listMemoryKeys() ([]string, error) {
+	keys, err := ks.ListKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, keyID := range keys {
+		if strings.HasPrefix(keyID, memoryKeyPrefix) {
+			ids = append(ids, keyID)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// createMemoryKey must be called with the cache lock held.
+func (ks *KeystoreService) createMemoryKey() (string, error) {
+	key := make([]byte, 32) // 256-bit key
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate memory key: %w", err)
+	}
+
+	keyID := memoryKeyPrefix + time.Now().UTC().Format("20060102T150405.000000000Z")
+	if ks.memoryKeys.store != nil {
+		wrapped, err := ks.EncryptData(key)
+		if err != nil {
+			return "", err
+		}
+		if err := ks.memoryKeys.store.StoreMemoryKey(context.Background(), keyID, wrapped); err != nil {
+			return "", err
+		}
+	} else if err := ks.StoreKey(keyID, key); err != nil {
+		return "", err
+	}
+
+	if ks.memoryKeys.keys == nil {
+		ks.memoryKeys.keys = make(map[string][]byte)
+	}
+	ks.memoryKeys.keys[keyID] = key
+	ks.memoryKeys.currentID = keyID
+	ks.memoryKeys.checkedAt = time.Now()
+	return keyID, nil
+}
+
+// loadMemoryKey must be called with the cache lock held.
+func (ks *KeystoreService) loadMemoryKey(keyID string) ([]byte, error) {
+	if key, ok := ks.memoryKeys.keys[keyID]; ok {
+		return key, nil
+	}
+
+	var (
+		key []byte
+		err error
+	)
+	if ks.memoryKeys.store != nil {
+		var wrapped []byte
+		if wrapped, err = ks.memoryKeys.store.GetMemoryKey(context.Background(), keyID); err == nil {
+			key, err = ks.DecryptData(wrapped)
+		}
+	} else {
+		key, err = ks.RetrieveKey(keyID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load memory key '%s': %w", keyID, err)
+	}
+	if ks.memoryKeys.keys == nil {
+		ks.memoryKeys.keys = make(map[string][]byte)
+	}
+	ks.memoryKeys.keys[keyID] = key
+	return key, nil
+}
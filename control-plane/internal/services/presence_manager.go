@@ -31,6 +31,10 @@ type PresenceManager struct {
 	stopCh   chan struct{}
 	stopOnce sync.Once
 
+	// instances holds the last heartbeat of each replica, keyed by node ID then
+	// instance ID. Instance expiry does not change node status.
+	instances map[string]map[string]time.Time
+
 	expireCallback func(string)
 }
 
@@ -52,6 +56,7 @@ func NewPresenceManager(statusManager *StatusManager, config PresenceManagerConf
 		statusManager: statusManager,
 		config:        config,
 		leases:        make(map[string]*presenceLease),
+		instances:     make(map[string]map[string]time.Time),
 		stopCh:        make(chan struct{}),
 	}
 }
@@ -91,6 +96,43 @@ func (pm *PresenceManager) HasLease(nodeID string) bool {
 	return exists
 }
 
+// TouchInstance renews the lease of one replica of a node.
+func (pm *PresenceManager) TouchInstance(nodeID, instanceID string, seenAt time.Time) {
+	pm.mu.Lock()
+	leases, exists := pm.instances[nodeID]
+	if !exists {
+		leases = make(map[string]time.Time)
+		pm.instances[nodeID] = leases
+	}
+	leases[instanceID] = seenAt
+	pm.mu.Unlock()
+}
+
+// ForgetInstance drops the lease of one replica of a node.
+func (pm *PresenceManager) ForgetInstance(nodeID, instanceID string) {
+	pm.mu.Lock()
+	if leases, exists := pm.instances[nodeID]; exists {
+		delete(leases, instanceID)
+		if len(leases) == 0 {
+			delete(pm.instances, nodeID)
+		}
+	}
+	pm.mu.Unlock()
+}
+
+// InstanceAlive reports whether a replica has been seen within the heartbeat TTL.
+// lastHeartbeat is the persisted heartbeat, which covers replicas that reported to
+// another control plane instance or before a restart.
+func (pm *PresenceManager) InstanceAlive(nodeID, instanceID string, lastHeartbeat time.Time) bool {
+	pm.mu.RLock()
+	seen := pm.instances[nodeID][instanceID]
+	pm.mu.RUnlock()
+	if lastHeartbeat.After(seen) {
+		seen = lastHeartbeat
+	}
+	return time.Since(seen) < pm.config.HeartbeatTTL
+}
+
 func (pm *PresenceManager) SetExpireCallback(fn func(string)) {
 	pm.mu.Lock()
 	pm.expireCallback = fn
@@ -162,6 +204,18 @@ func (pm *PresenceManager) checkExpirations() {
 			}
 		}
 	}
+	if pm.config.HardEvictTTL > 0 {
+		for nodeID, leases := range pm.instances {
+			for instanceID, seen := range leases {
+				if now.Sub(seen) >= pm.config.HardEvictTTL {
+					delete(leases, instanceID)
+				}
+			}
+			if len(leases) == 0 {
+				delete(pm.instances, nodeID)
+			}
+		}
+	}
 	pm.mu.Unlock()
 
 	for _, nodeID := range expired {
@@ -131,6 +131,26 @@ func TestPresenceManager_HasLease(t *testing.T) {
 	require.False(t, pm.HasLease(nodeID))
 }
 
+func TestPresenceManager_InstanceLeases(t *testing.T) {
+	pm, _ := setupPresenceManagerTest(t)
+
+	now := time.Now()
+	pm.TouchInstance("node-1", "a", now)
+	pm.TouchInstance("node-1", "b", now.Add(-10*time.Second))
+
+	require.True(t, pm.InstanceAlive("node-1", "a", time.Time{}))
+	require.False(t, pm.InstanceAlive("node-1", "b", time.Time{}))
+	// A newer persisted heartbeat wins over a stale in-memory lease.
+	require.True(t, pm.InstanceAlive("node-1", "b", now))
+	require.False(t, pm.InstanceAlive("node-1", "unknown", time.Time{}))
+
+	pm.ForgetInstance("node-1", "a")
+	require.False(t, pm.InstanceAlive("node-1", "a", time.Time{}))
+
+	// Instance leases never touch the node lease.
+	require.False(t, pm.HasLease("node-1"))
+}
+
 func TestPresenceManager_SetExpireCallback(t *testing.T) {
 	pm, _ := setupPresenceManagerTest(t)
 
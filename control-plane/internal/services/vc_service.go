@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -16,6 +17,14 @@ import (
 	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
 )
 
+// ErrInvalidVCSignature is returned when a credential signed by an agent does
+// not verify against the issuer's registered public key.
+var ErrInvalidVCSignature = errors.New("invalid VC signature")
+
+// ErrVCExecutionMismatch is returned when a credential signed by an agent names an
+// execution that agent did not run.
+var ErrVCExecutionMismatch = errors.New("VC does not belong to the execution's agent node")
+
 // VCService handles verifiable credential generation, verification, and management.
 type VCService struct {
 	config     *config.DIDConfig
@@ -239,6 +248,108 @@ func (s *VCService) GenerateExecutionVC(ctx *types.ExecutionContext, inputData,
 	return executionVC, nil
 }
 
+// StoreSignedExecutionVC records an execution VC that an agent signed with its
+// own key, as the Go SDK does for the reasoners it serves. The signature must
+// verify against the public key of the issuer DID, which has to be one the
+// control plane issued.
+func (s *VCService) StoreSignedExecutionVC(vcDocument json.RawMessage) (*types.ExecutionVC, error) {
+	if !s.config.Enabled {
+		return nil, fmt.Errorf("DID system is disabled")
+	}
+	if !s.config.VCRequirements.RequireVCForExecution {
+		return nil, nil
+	}
+
+	var vcDoc types.VCDocument
+	if err := json.Unmarshal(vcDocument, &vcDoc); err != nil {
+		return nil, fmt.Errorf("failed to parse VC document: %w", err)
+	}
+	if err := s.validateVCStructure(&vcDoc); err != nil {
+		return nil, fmt.Errorf("invalid VC document: %w", err)
+	}
+	if vcDoc.CredentialSubject.ExecutionID == "" {
+		return nil, fmt.Errorf("invalid VC document: missing execution id")
+	}
+
+	issuerIdentity, err := s.didService.ResolveDID(vcDoc.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve issuer DID: %w", err)
+	}
+	valid, err := s.verifyVCSignature(&vcDoc, issuerIdentity)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidVCSignature, err)
+	}
+	if !valid {
+		return nil, ErrInvalidVCSignature
+	}
+	if err := s.checkExecutionSigner(&vcDoc); err != nil {
+		return nil, err
+	}
+
+	vcDocBytes, err := json.Marshal(vcDoc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal VC document: %w", err)
+	}
+
+	subject := vcDoc.CredentialSubject
+	executionVC := &types.ExecutionVC{
+		VCID:         strings.TrimPrefix(vcDoc.ID, "urn:agentfield:vc:"),
+		ExecutionID:  subject.ExecutionID,
+		WorkflowID:   subject.WorkflowID,
+		SessionID:    subject.SessionID,
+		IssuerDID:    vcDoc.Issuer,
+		TargetDID:    subject.Target.DID,
+		CallerDID:    subject.Caller.DID,
+		VCDocument:   json.RawMessage(vcDocBytes),
+		Signature:    vcDoc.Proof.ProofValue,
+		DocumentSize: int64(len(vcDocBytes)),
+		InputHash:    subject.Execution.InputHash,
+		OutputHash:   subject.Execution.OutputHash,
+		Status:       types.NormalizeExecutionStatus(subject.Execution.Status),
+		CreatedAt:    time.Now(),
+	}
+
+	if s.ShouldPersistExecutionVC() {
+		if err := s.vcStorage.StoreExecutionVC(context.Background(), executionVC); err != nil {
+			return nil, fmt.Errorf("failed to store execution VC: %w", err)
+		}
+	}
+
+	return executionVC, nil
+}
+
+// checkExecutionSigner ensures the issuer and target of a credential an agent signed are
+// DIDs of the agent node that ran the execution, so a valid key of one agent cannot
+// attest to the executions of another.
+func (s *VCService) checkExecutionSigner(vcDoc *types.VCDocument) error {
+	executionID := vcDoc.CredentialSubject.ExecutionID
+	exec, err := s.vcStorage.GetExecutionRecord(context.Background(), executionID)
+	if err != nil {
+		return fmt.Errorf("failed to load execution %s: %w", executionID, err)
+	}
+	if exec == nil {
+		return fmt.Errorf("%w: execution %s not found", ErrVCExecutionMismatch, executionID)
+	}
+	agentInfo, err := s.didService.GetExistingAgentDID(exec.AgentNodeID)
+	if err != nil {
+		return fmt.Errorf("%w: agent node %s has no DID", ErrVCExecutionMismatch, exec.AgentNodeID)
+	}
+
+	owned := map[string]bool{agentInfo.DID: true}
+	for _, reasoner := range agentInfo.Reasoners {
+		owned[reasoner.DID] = true
+	}
+	for _, skill := range agentInfo.Skills {
+		owned[skill.DID] = true
+	}
+	for _, did := range []string{vcDoc.Issuer, vcDoc.CredentialSubject.Target.DID} {
+		if !owned[did] {
+			return fmt.Errorf("%w: %s is not a DID of agent node %s", ErrVCExecutionMismatch, did, exec.AgentNodeID)
+		}
+	}
+	return nil
+}
+
 // VerifyVC verifies a verifiable credential.
 func (s *VCService) VerifyVC(vcDocument json.RawMessage) (*types.VCVerificationResponse, error) {
 	if !s.config.Enabled {
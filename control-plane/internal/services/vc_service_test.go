@@ -389,6 +389,117 @@ func TestVCService_GenerateExecutionVC_LongErrorMessage(t *testing.T) {
 	require.Contains(t, vcDoc.CredentialSubject.Execution.ErrorMessage, "...[truncated]")
 }
 
+func TestVCService_StoreSignedExecutionVC(t *testing.T) {
+	vcService, didService, provider, ctx := setupVCTestEnvironment(t)
+
+	regResp, err := didService.RegisterAgent(&types.DIDRegistrationRequest{
+		AgentNodeID: "agent-signed",
+		Reasoners:   []types.ReasonerDefinition{{ID: "reasoner1"}},
+	})
+	require.NoError(t, err)
+	require.True(t, regResp.Success)
+	require.NoError(t, provider.CreateExecutionRecord(ctx, &types.Execution{
+		ExecutionID: "exec-signed",
+		RunID:       "workflow-1",
+		AgentNodeID: "agent-signed",
+		ReasonerID:  "reasoner1",
+		NodeID:      "agent-signed",
+		Status:      string(types.ExecutionStatusSucceeded),
+		StartedAt:   time.Now(),
+	}))
+
+	// Sign the credential the way an agent does, with the reasoner's own key.
+	reasoner := regResp.IdentityPackage.ReasonerDIDs["reasoner1"]
+	execCtx := &types.ExecutionContext{
+		ExecutionID:  "exec-signed",
+		WorkflowID:   "workflow-1",
+		SessionID:    "session-1",
+		CallerDID:    regResp.IdentityPackage.AgentDID.DID,
+		TargetDID:    reasoner.DID,
+		AgentNodeDID: regResp.IdentityPackage.AgentDID.DID,
+		Timestamp:    time.Now(),
+	}
+	vcDoc := vcService.createVCDocument(execCtx, &regResp.IdentityPackage.AgentDID, &reasoner, "in-hash", "out-hash", "succeeded", nil, 42)
+	vcDoc.Issuer = reasoner.DID
+	vcDoc.Proof.VerificationMethod = reasoner.DID + "#key-1"
+	vcDoc.Proof.ProofValue, err = vcService.signVC(vcDoc, &reasoner)
+	require.NoError(t, err)
+
+	signed, err := json.Marshal(vcDoc)
+	require.NoError(t, err)
+
+	vc, err := vcService.StoreSignedExecutionVC(signed)
+	require.NoError(t, err)
+	require.NotNil(t, vc)
+	require.Equal(t, "exec-signed", vc.ExecutionID)
+	require.Equal(t, reasoner.DID, vc.IssuerDID)
+	require.Equal(t, reasoner.DID, vc.TargetDID)
+	require.Equal(t, "in-hash", vc.InputHash)
+	require.Equal(t, vcDoc.Proof.ProofValue, vc.Signature)
+
+	storedVC, err := provider.GetExecutionVC(ctx, vc.VCID)
+	require.NoError(t, err)
+	require.Equal(t, "exec-signed", storedVC.ExecutionID)
+
+	// Changing the outcome after signing must be caught.
+	vcDoc.CredentialSubject.Execution.Status = "failed"
+	tampered, err := json.Marshal(vcDoc)
+	require.NoError(t, err)
+
+	_, err = vcService.StoreSignedExecutionVC(tampered)
+	require.ErrorIs(t, err, ErrInvalidVCSignature)
+}
+
+func TestVCService_StoreSignedExecutionVC_RejectsOtherAgentsExecutions(t *testing.T) {
+	vcService, didService, provider, ctx := setupVCTestEnvironment(t)
+
+	packages := map[string]types.DIDIdentityPackage{}
+	for _, nodeID := range []string{"agent-owner", "agent-other"} {
+		regResp, err := didService.RegisterAgent(&types.DIDRegistrationRequest{
+			AgentNodeID: nodeID,
+			Reasoners:   []types.ReasonerDefinition{{ID: "reasoner1"}},
+		})
+		require.NoError(t, err)
+		require.True(t, regResp.Success)
+		packages[nodeID] = regResp.IdentityPackage
+	}
+	require.NoError(t, provider.CreateExecutionRecord(ctx, &types.Execution{
+		ExecutionID: "exec-owned",
+		RunID:       "workflow-1",
+		AgentNodeID: "agent-owner",
+		ReasonerID:  "reasoner1",
+		NodeID:      "agent-owner",
+		Status:      string(types.ExecutionStatusSucceeded),
+		StartedAt:   time.Now(),
+	}))
+
+	other := packages["agent-other"]
+	reasoner := other.ReasonerDIDs["reasoner1"]
+
+	// A valid signature of another agent's reasoner must not attest to the execution.
+	for _, executionID := range []string{"exec-owned", "exec-unknown"} {
+		execCtx := &types.ExecutionContext{
+			ExecutionID:  executionID,
+			WorkflowID:   "workflow-1",
+			CallerDID:    other.AgentDID.DID,
+			TargetDID:    reasoner.DID,
+			AgentNodeDID: other.AgentDID.DID,
+			Timestamp:    time.Now(),
+		}
+		vcDoc := vcService.createVCDocument(execCtx, &other.AgentDID, &reasoner, "in-hash", "out-hash", "succeeded", nil, 42)
+		vcDoc.Issuer = reasoner.DID
+		vcDoc.Proof.VerificationMethod = reasoner.DID + "#key-1"
+		proof, err := vcService.signVC(vcDoc, &reasoner)
+		require.NoError(t, err)
+		vcDoc.Proof.ProofValue = proof
+		signed, err := json.Marshal(vcDoc)
+		require.NoError(t, err)
+
+		_, err = vcService.StoreSignedExecutionVC(signed)
+		require.ErrorIs(t, err, ErrVCExecutionMismatch, executionID)
+	}
+}
+
 func TestVCService_VerifyVC_Success(t *testing.T) {
 	vcService, didService, _, _ := setupVCTestEnvironment(t)
 
@@ -73,6 +73,14 @@ func (s *VCStorage) GetExecutionVC(vcID string) (*types.ExecutionVC, error) {
 	return s.convertVCInfoToExecutionVC(vcInfo)
 }
 
+// GetExecutionRecord fetches the execution a credential attests to.
+func (s *VCStorage) GetExecutionRecord(ctx context.Context, executionID string) (*types.Execution, error) {
+	if s.storageProvider == nil {
+		return nil, fmt.Errorf("no storage provider configured for VC storage")
+	}
+	return s.storageProvider.GetExecutionRecord(ctx, executionID)
+}
+
 // GetExecutionVCsByWorkflow returns all execution VCs associated with a workflow.
 func (s *VCStorage) GetExecutionVCsByWorkflow(workflowID string) ([]types.ExecutionVC, error) {
 	filters := types.VCFilters{WorkflowID: &workflowID}
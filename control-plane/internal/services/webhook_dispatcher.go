@@ -451,6 +451,8 @@ func determineWebhookEvent(status string) string {
 	switch types.NormalizeExecutionStatus(status) {
 	case string(types.ExecutionStatusSucceeded):
 		return types.WebhookEventExecutionCompleted
+	case string(types.ExecutionStatusCancelled):
+		return types.WebhookEventExecutionCancelled
 	default:
 		return types.WebhookEventExecutionFailed
 	}
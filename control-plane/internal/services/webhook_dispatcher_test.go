@@ -584,6 +584,8 @@ func TestWebhookDispatcher_DetermineWebhookEvent(t *testing.T) {
 	}{
 		{"succeeded", "execution.completed"},
 		{"failed", "execution.failed"},
+		{"cancelled", "execution.cancelled"},
+		{"canceled", "execution.cancelled"},
 		{"running", "execution.failed"},   // Non-succeeded defaults to failed
 		{"pending", "execution.failed"},   // Non-succeeded defaults to failed
 		{"unknown", "execution.failed"},   // Non-succeeded defaults to failed
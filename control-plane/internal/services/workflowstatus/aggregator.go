@@ -90,7 +90,7 @@ func AggregateExecutions(executions []*types.WorkflowExecution, steps []*types.W
 		}
 
 		switch normalized {
-		case string(types.ExecutionStatusRunning), string(types.ExecutionStatusQueued), string(types.ExecutionStatusPending):
+		case string(types.ExecutionStatusRunning), string(types.ExecutionStatusQueued), string(types.ExecutionStatusPending), string(types.ExecutionStatusWaitingForApproval):
 			result.ActiveExecutions++
 		}
 
@@ -152,7 +152,7 @@ func AggregateExecutions(executions []*types.WorkflowExecution, steps []*types.W
 
 	if result.ActiveExecutions > 0 {
 		switch baseStatus {
-		case string(types.ExecutionStatusQueued), string(types.ExecutionStatusPending), string(types.ExecutionStatusRunning):
+		case string(types.ExecutionStatusQueued), string(types.ExecutionStatusPending), string(types.ExecutionStatusRunning), string(types.ExecutionStatusWaitingForApproval):
 			result.Status = baseStatus
 		default:
 			result.Status = string(types.ExecutionStatusRunning)
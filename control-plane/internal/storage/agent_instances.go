@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+)
+
+// UpsertAgentInstance records a replica of an agent node, replacing its base URL and
+// heartbeat if the instance registered before.
+func (ls *LocalStorage) UpsertAgentInstance(ctx context.Context, instance *types.AgentInstance) error {
+	if instance == nil {
+		return fmt.Errorf("agent instance is nil")
+	}
+	if strings.TrimSpace(instance.NodeID) == "" || strings.TrimSpace(instance.InstanceID) == "" {
+		return fmt.Errorf("node id and instance id are required")
+	}
+	now := time.Now().UTC()
+	if instance.RegisteredAt.IsZero() {
+		instance.RegisteredAt = now
+	}
+	if instance.LastHeartbeat.IsZero() {
+		instance.LastHeartbeat = now
+	}
+
+	_, err := ls.requireSQLDB().ExecContext(ctx, `
+		INSERT INTO agent_instances (node_id, instance_id, base_url, last_heartbeat, registered_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(node_id, instance_id) DO UPDATE SET
+			base_url = excluded.base_url,
+			last_heartbeat = excluded.last_heartbeat,
+			registered_at = excluded.registered_at`,
+		instance.NodeID,
+		instance.InstanceID,
+		instance.BaseURL,
+		instance.LastHeartbeat,
+		instance.RegisteredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert agent instance: %w", err)
+	}
+	return nil
+}
+
+// ListAgentInstances returns the registered instances of a node ordered by instance ID.
+func (ls *LocalStorage) ListAgentInstances(ctx context.Context, nodeID string) ([]*types.AgentInstance, error) {
+	rows, err := ls.requireSQLDB().QueryContext(ctx, `
+		SELECT node_id, instance_id, base_url, last_heartbeat, registered_at
+		FROM agent_instances
+		WHERE node_id = ?
+		ORDER BY instance_id ASC`, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("list agent instances: %w", err)
+	}
+	defer rows.Close()
+
+	instances := make([]*types.AgentInstance, 0)
+	for rows.Next() {
+		var instance types.AgentInstance
+		if err := rows.Scan(&instance.NodeID, &instance.InstanceID, &instance.BaseURL, &instance.LastHeartbeat, &instance.RegisteredAt); err != nil {
+			return nil, fmt.Errorf("scan agent instance: %w", err)
+		}
+		instances = append(instances, &instance)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate agent instances: %w", err)
+	}
+	return instances, nil
+}
+
+// TouchAgentInstance records a heartbeat for an instance. Unknown instances are ignored.
+func (ls *LocalStorage) TouchAgentInstance(ctx context.Context, nodeID, instanceID string, seenAt time.Time) error {
+	if _, err := ls.requireSQLDB().ExecContext(ctx, `
+		UPDATE agent_instances
+		SET last_heartbeat = ?
+		WHERE node_id = ? AND instance_id = ?`, seenAt.UTC(), nodeID, instanceID); err != nil {
+		return fmt.Errorf("touch agent instance: %w", err)
+	}
+	return nil
+}
+
+// DeleteAgentInstance removes an instance, typically on graceful shutdown. Deleting an
+// instance that is already gone is not an error.
+func (ls *LocalStorage) DeleteAgentInstance(ctx context.Context, nodeID, instanceID string) error {
+	if _, err := ls.requireSQLDB().ExecContext(ctx, `DELETE FROM agent_instances WHERE node_id = ? AND instance_id = ?`, nodeID, instanceID); err != nil {
+		return fmt.Errorf("delete agent instance: %w", err)
+	}
+	return nil
+}
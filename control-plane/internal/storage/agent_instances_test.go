@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentInstances_UpsertTouchAndDelete(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+
+	registered := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, provider.UpsertAgentInstance(ctx, &types.AgentInstance{
+		NodeID:        "node-1",
+		InstanceID:    "b",
+		BaseURL:       "http://10.0.0.2:8001",
+		LastHeartbeat: registered,
+		RegisteredAt:  registered,
+	}))
+	require.NoError(t, provider.UpsertAgentInstance(ctx, &types.AgentInstance{
+		NodeID:     "node-1",
+		InstanceID: "a",
+		BaseURL:    "http://10.0.0.1:8001",
+	}))
+	require.NoError(t, provider.UpsertAgentInstance(ctx, &types.AgentInstance{
+		NodeID:     "node-2",
+		InstanceID: "a",
+		BaseURL:    "http://10.0.0.9:8001",
+	}))
+
+	// Re-registering an instance replaces its base URL.
+	require.NoError(t, provider.UpsertAgentInstance(ctx, &types.AgentInstance{
+		NodeID:        "node-1",
+		InstanceID:    "b",
+		BaseURL:       "http://10.0.0.3:8001",
+		LastHeartbeat: registered,
+		RegisteredAt:  registered,
+	}))
+
+	instances, err := provider.ListAgentInstances(ctx, "node-1")
+	require.NoError(t, err)
+	require.Len(t, instances, 2)
+	assert.Equal(t, "a", instances[0].InstanceID)
+	assert.Equal(t, "b", instances[1].InstanceID)
+	assert.Equal(t, "http://10.0.0.3:8001", instances[1].BaseURL)
+	assert.True(t, instances[1].LastHeartbeat.Equal(registered))
+
+	seen := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, provider.TouchAgentInstance(ctx, "node-1", "b", seen))
+	require.NoError(t, provider.TouchAgentInstance(ctx, "node-1", "missing", seen))
+	instances, err = provider.ListAgentInstances(ctx, "node-1")
+	require.NoError(t, err)
+	assert.True(t, instances[1].LastHeartbeat.Equal(seen))
+
+	require.NoError(t, provider.DeleteAgentInstance(ctx, "node-1", "a"))
+	require.NoError(t, provider.DeleteAgentInstance(ctx, "node-1", "a"))
+	instances, err = provider.ListAgentInstances(ctx, "node-1")
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, "b", instances[0].InstanceID)
+
+	require.Error(t, provider.UpsertAgentInstance(ctx, &types.AgentInstance{NodeID: "node-1"}))
+}
+
+func TestGetAgent_UnknownNodeReturnsErrAgentNotFound(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+
+	agent, err := provider.GetAgent(ctx, "missing")
+	require.ErrorIs(t, err, ErrAgentNotFound)
+	assert.Nil(t, agent)
+}
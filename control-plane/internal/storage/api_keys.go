@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+)
+
+const apiKeyColumns = `key_id, name, team_id, role, prefix, secret_hash, created_at, rotated_at, revoked_at`
+
+// CreateAPIKey inserts a new API key.
+func (ls *LocalStorage) CreateAPIKey(ctx context.Context, key *types.APIKey) error {
+	if key == nil {
+		return fmt.Errorf("api key is nil")
+	}
+	if strings.TrimSpace(key.KeyID) == "" || strings.TrimSpace(key.SecretHash) == "" {
+		return fmt.Errorf("key id and secret hash are required")
+	}
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now().UTC()
+	}
+
+	_, err := ls.requireSQLDB().ExecContext(ctx, `
+		INSERT INTO api_keys (`+apiKeyColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		key.KeyID,
+		key.Name,
+		key.TeamID,
+		key.Role,
+		key.Prefix,
+		key.SecretHash,
+		key.CreatedAt,
+		key.RotatedAt,
+		key.RevokedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert api key: %w", err)
+	}
+	return nil
+}
+
+// GetAPIKey returns the API key with the given ID, or nil if it does not exist.
+func (ls *LocalStorage) GetAPIKey(ctx context.Context, keyID string) (*types.APIKey, error) {
+	row := ls.requireSQLDB().QueryRowContext(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM api_keys
+		WHERE key_id = ?`, keyID)
+	return scanAPIKey(row)
+}
+
+// GetAPIKeyBySecretHash returns the API key whose secret hashes to secretHash, or nil if
+// there is none. Revoked keys are returned too; callers must check Revoked.
+func (ls *LocalStorage) GetAPIKeyBySecretHash(ctx context.Context, secretHash string) (*types.APIKey, error) {
+	row := ls.requireSQLDB().QueryRowContext(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM api_keys
+		WHERE secret_hash = ?`, secretHash)
+	return scanAPIKey(row)
+}
+
+// ListAPIKeys returns the keys of a team ordered by creation time. An empty teamID lists
+// the keys of every team.
+func (ls *LocalStorage) ListAPIKeys(ctx context.Context, teamID string) ([]*types.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys`
+	var args []interface{}
+	if teamID != "" {
+		query += ` WHERE team_id = ?`
+		args = append(args, teamID)
+	}
+	query += ` ORDER BY created_at ASC, key_id ASC`
+
+	rows, err := ls.requireSQLDB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]*types.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// UpdateAPIKey overwrites the mutable fields of an existing key: its name, secret and
+// rotation and revocation times.
+func (ls *LocalStorage) UpdateAPIKey(ctx context.Context, key *types.APIKey) error {
+	if key == nil {
+		return fmt.Errorf("api key is nil")
+	}
+
+	result, err := ls.requireSQLDB().ExecContext(ctx, `
+		UPDATE api_keys SET
+			name = ?,
+			prefix = ?,
+			secret_hash = ?,
+			rotated_at = ?,
+			revoked_at = ?
+		WHERE key_id = ?`,
+		key.Name,
+		key.Prefix,
+		key.SecretHash,
+		key.RotatedAt,
+		key.RevokedAt,
+		key.KeyID,
+	)
+	if err != nil {
+		return fmt.Errorf("update api key: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("api key %s not found", key.KeyID)
+	}
+	return nil
+}
+
+func scanAPIKey(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*types.APIKey, error) {
+	var (
+		key       types.APIKey
+		rotatedAt sql.NullTime
+		revokedAt sql.NullTime
+	)
+	err := scanner.Scan(
+		&key.KeyID,
+		&key.Name,
+		&key.TeamID,
+		&key.Role,
+		&key.Prefix,
+		&key.SecretHash,
+		&key.CreatedAt,
+		&rotatedAt,
+		&revokedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("scan api key: %w", err)
+	}
+	if rotatedAt.Valid {
+		t := rotatedAt.Time.UTC()
+		key.RotatedAt = &t
+	}
+	if revokedAt.Valid {
+		t := revokedAt.Time.UTC()
+		key.RevokedAt = &t
+	}
+	return &key, nil
+}
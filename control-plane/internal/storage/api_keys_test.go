@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeys_CreateListRotateAndRevoke(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+
+	require.NoError(t, provider.CreateAPIKey(ctx, &types.APIKey{
+		KeyID:      "key-a",
+		Name:       "ci",
+		TeamID:     "team-a",
+		Role:       types.APIKeyRoleOperator,
+		Prefix:     "afk_aaaa",
+		SecretHash: "hash-a",
+	}))
+	require.NoError(t, provider.CreateAPIKey(ctx, &types.APIKey{
+		KeyID:      "key-b",
+		Name:       "agents",
+		TeamID:     "team-b",
+		Role:       types.APIKeyRoleAgent,
+		Prefix:     "afk_bbbb",
+		SecretHash: "hash-b",
+	}))
+	require.Error(t, provider.CreateAPIKey(ctx, &types.APIKey{KeyID: "key-c", SecretHash: "hash-a"}), "secret hashes are unique")
+
+	key, err := provider.GetAPIKeyBySecretHash(ctx, "hash-a")
+	require.NoError(t, err)
+	require.NotNil(t, key)
+	require.Equal(t, "key-a", key.KeyID)
+	require.Equal(t, "team-a", key.TeamID)
+	require.False(t, key.Revoked())
+
+	missing, err := provider.GetAPIKeyBySecretHash(ctx, "unknown")
+	require.NoError(t, err)
+	require.Nil(t, missing)
+
+	teamKeys, err := provider.ListAPIKeys(ctx, "team-b")
+	require.NoError(t, err)
+	require.Len(t, teamKeys, 1)
+	require.Equal(t, "key-b", teamKeys[0].KeyID)
+
+	allKeys, err := provider.ListAPIKeys(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, allKeys, 2)
+
+	rotatedAt := time.Now().UTC().Truncate(time.Second)
+	key.SecretHash = "hash-a2"
+	key.Prefix = "afk_a2a2"
+	key.RotatedAt = &rotatedAt
+	require.NoError(t, provider.UpdateAPIKey(ctx, key))
+
+	stale, err := provider.GetAPIKeyBySecretHash(ctx, "hash-a")
+	require.NoError(t, err)
+	require.Nil(t, stale)
+
+	key.RevokedAt = &rotatedAt
+	require.NoError(t, provider.UpdateAPIKey(ctx, key))
+	revoked, err := provider.GetAPIKey(ctx, "key-a")
+	require.NoError(t, err)
+	require.True(t, revoked.Revoked())
+	require.Equal(t, "afk_a2a2", revoked.Prefix)
+	require.WithinDuration(t, rotatedAt, *revoked.RotatedAt, time.Second)
+
+	require.Error(t, provider.UpdateAPIKey(ctx, &types.APIKey{KeyID: "missing"}))
+}
+
+func TestExecutionRecords_FilterByAgentNodeIDs(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+
+	for _, exec := range []*types.Execution{
+		{ExecutionID: "exec-a", RunID: "run-a", AgentNodeID: "node-a", ReasonerID: "r", NodeID: "node-a", Status: types.ExecutionStatusQueued},
+		{ExecutionID: "exec-b", RunID: "run-b", AgentNodeID: "node-b", ReasonerID: "r", NodeID: "node-b", Status: types.ExecutionStatusQueued},
+	} {
+		require.NoError(t, provider.CreateExecutionRecord(ctx, exec))
+	}
+
+	records, err := provider.QueryExecutionRecords(ctx, types.ExecutionFilter{AgentNodeIDs: []string{"node-a", "node-c"}})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, "exec-a", records[0].ExecutionID)
+
+	none, err := provider.QueryExecutionRecords(ctx, types.ExecutionFilter{AgentNodeIDs: []string{}})
+	require.NoError(t, err)
+	require.Empty(t, none, "a team without agents sees no executions")
+}
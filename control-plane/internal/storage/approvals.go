@@ -0,0 +1,246 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+)
+
+const approvalColumns = `
+	approval_id, execution_id, run_id, agent_node_id, reasoner_id, title, description,
+	payload, status, default_decision, expires_at, comment, decided_by, timed_out,
+	created_at, decided_at`
+
+// CreateApproval inserts a new pending approval.
+func (ls *LocalStorage) CreateApproval(ctx context.Context, approval *types.Approval) error {
+	if approval == nil {
+		return fmt.Errorf("approval is nil")
+	}
+	if strings.TrimSpace(approval.ApprovalID) == "" || strings.TrimSpace(approval.ExecutionID) == "" {
+		return fmt.Errorf("approval id and execution id are required")
+	}
+	if approval.Status == "" {
+		approval.Status = types.ApprovalStatusPending
+	}
+	if approval.CreatedAt.IsZero() {
+		approval.CreatedAt = time.Now().UTC()
+	}
+
+	_, err := ls.requireSQLDB().ExecContext(ctx, `
+		INSERT INTO approvals (`+approvalColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		approval.ApprovalID,
+		approval.ExecutionID,
+		approval.RunID,
+		approval.AgentNodeID,
+		approval.ReasonerID,
+		approval.Title,
+		approval.Description,
+		bytesOrNil(approval.Payload),
+		approval.Status,
+		approval.DefaultDecision,
+		approval.ExpiresAt,
+		approval.Comment,
+		approval.DecidedBy,
+		approval.TimedOut,
+		approval.CreatedAt,
+		approval.DecidedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert approval: %w", err)
+	}
+	return nil
+}
+
+// GetApproval returns the approval with the given ID, or nil if it does not exist.
+func (ls *LocalStorage) GetApproval(ctx context.Context, approvalID string) (*types.Approval, error) {
+	row := ls.requireSQLDB().QueryRowContext(ctx, `
+		SELECT `+approvalColumns+`
+		FROM approvals
+		WHERE approval_id = ?`, approvalID)
+	return scanApproval(row)
+}
+
+// ListApprovals returns the approvals matching filter, newest first.
+func (ls *LocalStorage) ListApprovals(ctx context.Context, filter types.ApprovalFilter) ([]*types.Approval, error) {
+	var (
+		where []string
+		args  []interface{}
+	)
+	if filter.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.ExecutionID != "" {
+		where = append(where, "execution_id = ?")
+		args = append(args, filter.ExecutionID)
+	}
+	if filter.AgentNodeIDs != nil {
+		if len(filter.AgentNodeIDs) == 0 {
+			where = append(where, "1 = 0")
+		} else {
+			where = append(where, "agent_node_id IN (?"+strings.Repeat(", ?", len(filter.AgentNodeIDs)-1)+")")
+			for _, id := range filter.AgentNodeIDs {
+				args = append(args, id)
+			}
+		}
+	}
+
+	query := `SELECT ` + approvalColumns + ` FROM approvals`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	query += ` ORDER BY created_at DESC, approval_id ASC`
+	if filter.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := ls.requireSQLDB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list approvals: %w", err)
+	}
+	defer rows.Close()
+	return scanApprovals(rows)
+}
+
+// ListExpiredApprovals returns up to limit pending approvals whose deadline is at or
+// before now, longest overdue first.
+func (ls *LocalStorage) ListExpiredApprovals(ctx context.Context, now time.Time, limit int) ([]*types.Approval, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := ls.requireSQLDB().QueryContext(ctx, `
+		SELECT `+approvalColumns+`
+		FROM approvals
+		WHERE status = ? AND expires_at IS NOT NULL AND expires_at <= ?
+		ORDER BY expires_at ASC
+		LIMIT ?`, types.ApprovalStatusPending, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("list expired approvals: %w", err)
+	}
+	defer rows.Close()
+	return scanApprovals(rows)
+}
+
+// ResolveApproval records the decision carried by approval. Only pending approvals are
+// updated, so concurrent deciders (a reviewer and the expiry sweep on any replica) race
+// safely; it reports whether this call made the decision.
+func (ls *LocalStorage) ResolveApproval(ctx context.Context, approval *types.Approval) (bool, error) {
+	if approval == nil {
+		return false, fmt.Errorf("approval is nil")
+	}
+	if !types.IsValidApprovalDecision(approval.Status) {
+		return false, fmt.Errorf("invalid approval decision %q", approval.Status)
+	}
+	if approval.DecidedAt == nil {
+		now := time.Now().UTC()
+		approval.DecidedAt = &now
+	}
+
+	result, err := ls.requireSQLDB().ExecContext(ctx, `
+		UPDATE approvals SET
+			status = ?,
+			comment = ?,
+			decided_by = ?,
+			timed_out = ?,
+			decided_at = ?
+		WHERE approval_id = ? AND status = ?`,
+		approval.Status,
+		approval.Comment,
+		approval.DecidedBy,
+		approval.TimedOut,
+		approval.DecidedAt,
+		approval.ApprovalID,
+		types.ApprovalStatusPending,
+	)
+	if err != nil {
+		return false, fmt.Errorf("resolve approval: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("resolve approval: %w", err)
+	}
+	return rows > 0, nil
+}
+
+func scanApprovals(rows *sql.Rows) ([]*types.Approval, error) {
+	approvals := make([]*types.Approval, 0)
+	for rows.Next() {
+		approval, err := scanApproval(rows)
+		if err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, approval)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate approvals: %w", err)
+	}
+	return approvals, nil
+}
+
+func scanApproval(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*types.Approval, error) {
+	var (
+		approval    types.Approval
+		reasonerID  sql.NullString
+		description sql.NullString
+		payload     []byte
+		expiresAt   sql.NullTime
+		comment     sql.NullString
+		decidedBy   sql.NullString
+		decidedAt   sql.NullTime
+	)
+
+	err := scanner.Scan(
+		&approval.ApprovalID,
+		&approval.ExecutionID,
+		&approval.RunID,
+		&approval.AgentNodeID,
+		&reasonerID,
+		&approval.Title,
+		&description,
+		&payload,
+		&approval.Status,
+		&approval.DefaultDecision,
+		&expiresAt,
+		&comment,
+		&decidedBy,
+		&approval.TimedOut,
+		&approval.CreatedAt,
+		&decidedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("scan approval: %w", err)
+	}
+
+	approval.ReasonerID = reasonerID.String
+	approval.Description = description.String
+	if len(payload) > 0 {
+		approval.Payload = append([]byte(nil), payload...)
+	}
+	if expiresAt.Valid {
+		t := expiresAt.Time.UTC()
+		approval.ExpiresAt = &t
+	}
+	if comment.Valid {
+		approval.Comment = &comment.String
+	}
+	if decidedBy.Valid {
+		approval.DecidedBy = &decidedBy.String
+	}
+	if decidedAt.Valid {
+		t := decidedAt.Time.UTC()
+		approval.DecidedAt = &t
+	}
+	return &approval, nil
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApprovals_CreateListAndResolveOnce(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+	require.NoError(t, provider.CreateApproval(ctx, &types.Approval{
+		ApprovalID:      "appr-overdue",
+		ExecutionID:     "exec-a",
+		RunID:           "run-a",
+		AgentNodeID:     "node-a",
+		ReasonerID:      "send_email",
+		Title:           "Send the invoice",
+		Payload:         json.RawMessage(`{"to":"billing@example.com"}`),
+		DefaultDecision: types.ApprovalStatusRejected,
+		ExpiresAt:       &past,
+	}))
+	require.NoError(t, provider.CreateApproval(ctx, &types.Approval{
+		ApprovalID:      "appr-open",
+		ExecutionID:     "exec-b",
+		RunID:           "run-b",
+		AgentNodeID:     "node-b",
+		Title:           "Spend $40",
+		DefaultDecision: types.ApprovalStatusApproved,
+		ExpiresAt:       &future,
+	}))
+	require.Error(t, provider.CreateApproval(ctx, &types.Approval{ApprovalID: "appr-x"}), "execution id is required")
+
+	approval, err := provider.GetApproval(ctx, "appr-overdue")
+	require.NoError(t, err)
+	require.NotNil(t, approval)
+	require.Equal(t, types.ApprovalStatusPending, approval.Status)
+	require.Equal(t, "send_email", approval.ReasonerID)
+	require.JSONEq(t, `{"to":"billing@example.com"}`, string(approval.Payload))
+	require.WithinDuration(t, past, *approval.ExpiresAt, time.Second)
+
+	missing, err := provider.GetApproval(ctx, "appr-missing")
+	require.NoError(t, err)
+	require.Nil(t, missing)
+
+	byExecution, err := provider.ListApprovals(ctx, types.ApprovalFilter{ExecutionID: "exec-b"})
+	require.NoError(t, err)
+	require.Len(t, byExecution, 1)
+	require.Equal(t, "appr-open", byExecution[0].ApprovalID)
+
+	byAgent, err := provider.ListApprovals(ctx, types.ApprovalFilter{AgentNodeIDs: []string{"node-a"}})
+	require.NoError(t, err)
+	require.Len(t, byAgent, 1)
+	require.Equal(t, "appr-overdue", byAgent[0].ApprovalID)
+
+	none, err := provider.ListApprovals(ctx, types.ApprovalFilter{AgentNodeIDs: []string{}})
+	require.NoError(t, err)
+	require.Empty(t, none)
+
+	expired, err := provider.ListExpiredApprovals(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	require.Equal(t, "appr-overdue", expired[0].ApprovalID)
+
+	comment := "not this month"
+	approval.Status = types.ApprovalStatusRejected
+	approval.Comment = &comment
+	resolved, err := provider.ResolveApproval(ctx, approval)
+	require.NoError(t, err)
+	require.True(t, resolved)
+
+	approval.Status = types.ApprovalStatusApproved
+	resolved, err = provider.ResolveApproval(ctx, approval)
+	require.NoError(t, err)
+	require.False(t, resolved, "an approval is decided once")
+
+	decided, err := provider.GetApproval(ctx, "appr-overdue")
+	require.NoError(t, err)
+	require.Equal(t, types.ApprovalStatusRejected, decided.Status)
+	require.Equal(t, comment, *decided.Comment)
+	require.NotNil(t, decided.DecidedAt)
+
+	pending, err := provider.ListApprovals(ctx, types.ApprovalFilter{Status: types.ApprovalStatusPending})
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, "appr-open", pending[0].ApprovalID)
+
+	_, err = provider.ResolveApproval(ctx, &types.Approval{ApprovalID: "appr-open", Status: types.ApprovalStatusPending})
+	require.Error(t, err)
+}
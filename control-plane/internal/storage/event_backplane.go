@@ -0,0 +1,331 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/events"
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// postgresBackplaneChannel is the NOTIFY channel replicas exchange events on.
+	postgresBackplaneChannel = "agentfield_events"
+	// postgresNotifyPayloadLimit keeps notifications under the 8000 byte limit Postgres
+	// puts on NOTIFY payloads. Larger envelopes are spilled to event_backplane_messages
+	// and notified by reference.
+	postgresNotifyPayloadLimit = 7900
+	// backplaneSpillRetention is how long spilled payloads are kept for listeners to read.
+	backplaneSpillRetention = 5 * time.Minute
+	backplaneReconnectDelay = 5 * time.Second
+	backplanePublishTimeout = 5 * time.Second
+	backplaneOutboxSize     = 1024
+)
+
+// Backplane topics of the buses owned by storage.
+const (
+	memoryChangesTopic           = "memory_changes"
+	cacheMessagesTopic           = "cache_messages"
+	storageExecutionEventsTopic  = "storage_execution_events"
+	workflowExecutionEventsTopic = "workflow_execution_events"
+)
+
+// backplaneEnvelope is the NOTIFY payload. Ref replaces Payload when the payload was
+// spilled to event_backplane_messages.
+type backplaneEnvelope struct {
+	Origin  string          `json:"origin"`
+	Topic   string          `json:"topic"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	Ref     int64           `json:"ref,omitempty"`
+}
+
+// cacheMessageEnvelope carries a CacheProvider message between replicas.
+type cacheMessageEnvelope struct {
+	Channel string                  `json:"channel"`
+	Event   types.MemoryChangeEvent `json:"event"`
+}
+
+// PostgresBackplane relays events between replicas sharing a Postgres database with
+// LISTEN/NOTIFY. It listens on a dedicated connection that is re-established after
+// failures; events notified while it is down are not replayed.
+type PostgresBackplane struct {
+	db     *sqlDatabase
+	dsn    string
+	origin string
+
+	mu       sync.RWMutex
+	handlers map[string][]func(payload []byte)
+
+	outbox chan backplaneEnvelope
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewPostgresBackplane creates a backplane that notifies through db and listens on its
+// own connection to dsn.
+func NewPostgresBackplane(db *sqlDatabase, dsn string) *PostgresBackplane {
+	return &PostgresBackplane{
+		db:       db,
+		dsn:      dsn,
+		origin:   uuid.NewString(),
+		handlers: make(map[string][]func(payload []byte)),
+		outbox:   make(chan backplaneEnvelope, backplaneOutboxSize),
+	}
+}
+
+// Subscribe registers handler for the payloads other replicas publish on topic.
+func (bp *PostgresBackplane) Subscribe(topic string, handler func(payload []byte)) {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	bp.handlers[topic] = append(bp.handlers[topic], handler)
+}
+
+// Publish queues payload, which must be a JSON document, for the other replicas. It
+// never waits on the database; when the queue is full the payload is dropped.
+func (bp *PostgresBackplane) Publish(topic string, payload []byte) error {
+	select {
+	case bp.outbox <- backplaneEnvelope{Origin: bp.origin, Topic: topic, Payload: payload}:
+		return nil
+	default:
+		return fmt.Errorf("event backplane queue is full, dropping %s event", topic)
+	}
+}
+
+func (bp *PostgresBackplane) notify(ctx context.Context, envelope backplaneEnvelope) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to encode backplane envelope: %w", err)
+	}
+	if len(data) > postgresNotifyPayloadLimit {
+		ref, err := bp.spill(ctx, envelope.Topic, envelope.Payload)
+		if err != nil {
+			return err
+		}
+		envelope.Payload, envelope.Ref = nil, ref
+		if data, err = json.Marshal(envelope); err != nil {
+			return fmt.Errorf("failed to encode backplane envelope: %w", err)
+		}
+	}
+
+	if _, err := bp.db.ExecContext(ctx, "SELECT pg_notify(?, ?)", postgresBackplaneChannel, string(data)); err != nil {
+		return fmt.Errorf("failed to notify backplane: %w", err)
+	}
+	return nil
+}
+
+// spill stores a payload too large to notify and returns its reference. Payloads past
+// their retention are pruned along the way.
+func (bp *PostgresBackplane) spill(ctx context.Context, topic string, payload []byte) (int64, error) {
+	now := time.Now().UTC()
+	if _, err := bp.db.ExecContext(ctx, `DELETE FROM event_backplane_messages WHERE created_at < ?`, now.Add(-backplaneSpillRetention)); err != nil {
+		logger.Logger.Warn().Err(err).Msg("failed to prune spilled backplane messages")
+	}
+
+	var ref int64
+	err := bp.db.QueryRowContext(ctx, `
+		INSERT INTO event_backplane_messages (topic, payload, created_at)
+		VALUES (?, ?, ?)
+		RETURNING id`, topic, payload, now).Scan(&ref)
+	if err != nil {
+		return 0, fmt.Errorf("failed to spill backplane message: %w", err)
+	}
+	return ref, nil
+}
+
+// Start begins sending queued events and listening for those of other replicas.
+func (bp *PostgresBackplane) Start() {
+	bp.stop = make(chan struct{})
+	bp.wg.Add(2)
+	go bp.sendLoop(bp.stop)
+	go bp.listenLoop(bp.stop)
+}
+
+// Stop stops the backplane and waits for it to exit. Events still queued are dropped.
+func (bp *PostgresBackplane) Stop() {
+	if bp.stop == nil {
+		return
+	}
+	close(bp.stop)
+	bp.wg.Wait()
+	bp.stop = nil
+}
+
+func (bp *PostgresBackplane) sendLoop(stop <-chan struct{}) {
+	defer bp.wg.Done()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case envelope := <-bp.outbox:
+			ctx, cancel := context.WithTimeout(context.Background(), backplanePublishTimeout)
+			if err := bp.notify(ctx, envelope); err != nil {
+				logger.Logger.Warn().Err(err).Str("topic", envelope.Topic).Msg("failed to relay event through backplane")
+			}
+			cancel()
+		}
+	}
+}
+
+func (bp *PostgresBackplane) listenLoop(stop <-chan struct{}) {
+	defer bp.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		err := bp.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Logger.Warn().Err(err).Msg("event backplane listener disconnected; reconnecting")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backplaneReconnectDelay):
+		}
+	}
+}
+
+func (bp *PostgresBackplane) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, bp.dsn)
+	if err != nil {
+		return fmt.Errorf("failed to connect backplane listener: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+postgresBackplaneChannel); err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", postgresBackplaneChannel, err)
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		bp.dispatch(ctx, []byte(notification.Payload))
+	}
+}
+
+// dispatch hands a notification to the handlers of its topic, skipping the ones this
+// replica sent.
+func (bp *PostgresBackplane) dispatch(ctx context.Context, data []byte) {
+	var envelope backplaneEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		logger.Logger.Warn().Err(err).Msg("dropping undecodable backplane notification")
+		return
+	}
+	if envelope.Origin == bp.origin {
+		return
+	}
+
+	bp.mu.RLock()
+	handlers := bp.handlers[envelope.Topic]
+	bp.mu.RUnlock()
+	if len(handlers) == 0 {
+		return
+	}
+
+	payload := []byte(envelope.Payload)
+	if envelope.Ref != 0 {
+		if err := bp.db.QueryRowContext(ctx, `SELECT payload FROM event_backplane_messages WHERE id = ?`, envelope.Ref).Scan(&payload); err != nil {
+			logger.Logger.Warn().Err(err).Int64("ref", envelope.Ref).Msg("failed to load spilled backplane message")
+			return
+		}
+	}
+	for _, handler := range handlers {
+		handler(payload)
+	}
+}
+
+// receiveMemoryChange handles a memory change relayed by another replica. The memory
+// cache entry of the key is dropped first, as it holds the value from before the change.
+func (ls *LocalStorage) receiveMemoryChange(payload []byte) {
+	var event types.MemoryChangeEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		logger.Logger.Warn().Err(err).Msg("dropping undecodable memory change from backplane")
+		return
+	}
+	ls.cache.Delete(fmt.Sprintf("%s:%s:%s", event.Scope, event.ScopeID, event.Key))
+	ls.deliverMemoryChange(event)
+}
+
+// startEventBackplane relays memory changes, cache messages and the storage event buses
+// to the other replicas, as selected by the event_backplane setting.
+func (ls *LocalStorage) startEventBackplane(setting string) error {
+	switch setting {
+	case "", "postgres":
+		if ls.mode != "postgres" {
+			if setting == "" {
+				return nil
+			}
+			return fmt.Errorf("the postgres event backplane requires postgres storage")
+		}
+	case "none":
+		return nil
+	default:
+		return fmt.Errorf("unsupported event backplane: %s", setting)
+	}
+
+	bp := NewPostgresBackplane(ls.db, ls.postgresConfig.DSN)
+	bp.Subscribe(memoryChangesTopic, ls.receiveMemoryChange)
+	bp.Subscribe(cacheMessagesTopic, func(payload []byte) {
+		var message cacheMessageEnvelope
+		if err := json.Unmarshal(payload, &message); err != nil {
+			logger.Logger.Warn().Err(err).Msg("dropping undecodable cache message from backplane")
+			return
+		}
+		ls.deliverCacheMessage(message.Channel, message.Event)
+	})
+	ls.eventBus.UseBackplane(bp, storageExecutionEventsTopic)
+	ls.workflowExecutionEventBus.UseBackplane(bp, workflowExecutionEventsTopic)
+
+	bp.Start()
+	ls.backplane = bp
+	return nil
+}
+
+func (ls *LocalStorage) stopEventBackplane() {
+	if ls.backplane != nil {
+		ls.backplane.Stop()
+	}
+}
+
+// relayToReplicas publishes value on topic through the backplane, if there is one.
+func (ls *LocalStorage) relayToReplicas(topic string, value interface{}) {
+	if ls.backplane == nil {
+		return
+	}
+	payload, err := json.Marshal(value)
+	if err != nil {
+		logger.Logger.Warn().Err(err).Str("topic", topic).Msg("failed to encode event for backplane")
+		return
+	}
+	if err := ls.backplane.Publish(topic, payload); err != nil {
+		logger.Logger.Warn().Err(err).Str("topic", topic).Msg("failed to relay event through backplane")
+	}
+}
+
+// EventBackplane returns the backplane relaying events to other replicas, or nil when
+// this replica runs alone.
+func (ls *LocalStorage) EventBackplane() events.Backplane {
+	if ls.backplane == nil {
+		return nil
+	}
+	return ls.backplane
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresBackplane_DispatchSkipsOwnNotifications(t *testing.T) {
+	bp := NewPostgresBackplane(nil, "")
+	var received []string
+	bp.Subscribe(memoryChangesTopic, func(payload []byte) {
+		received = append(received, string(payload))
+	})
+
+	notification := func(origin, topic, payload string) []byte {
+		data, err := json.Marshal(backplaneEnvelope{Origin: origin, Topic: topic, Payload: json.RawMessage(payload)})
+		require.NoError(t, err)
+		return data
+	}
+
+	ctx := context.Background()
+	bp.dispatch(ctx, notification(bp.origin, memoryChangesTopic, `{"key":"own"}`))
+	bp.dispatch(ctx, notification("replica-b", cacheMessagesTopic, `{"key":"other-topic"}`))
+	bp.dispatch(ctx, notification("replica-b", memoryChangesTopic, `{"key":"remote"}`))
+	bp.dispatch(ctx, []byte("not json"))
+
+	require.Equal(t, []string{`{"key":"remote"}`}, received)
+}
+
+func TestPostgresBackplane_PublishDropsWhenQueueIsFull(t *testing.T) {
+	bp := NewPostgresBackplane(nil, "")
+	for i := 0; i < backplaneOutboxSize; i++ {
+		require.NoError(t, bp.Publish(memoryChangesTopic, []byte(`{}`)))
+	}
+	require.Error(t, bp.Publish(memoryChangesTopic, []byte(`{}`)))
+}
+
+func TestLocalStorage_EventBackplaneSetting(t *testing.T) {
+	provider, _ := setupTestStorage(t)
+	ls := provider.(*LocalStorage)
+	require.Nil(t, ls.EventBackplane(), "local mode runs without a backplane by default")
+
+	require.NoError(t, ls.startEventBackplane("none"))
+	require.ErrorContains(t, ls.startEventBackplane("postgres"), "requires postgres storage")
+	require.ErrorContains(t, ls.startEventBackplane("redis"), "unsupported event backplane")
+}
+
+func TestLocalStorage_RelayedMemoryChangeEvictsCache(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+	ls := provider.(*LocalStorage)
+	require.NoError(t, ls.SetMemory(ctx, &types.Memory{Scope: "session", ScopeID: "sess-1", Key: "plan", Data: json.RawMessage(`"v1"`)}))
+	_, cached := ls.cache.Load("session:sess-1:plan")
+	require.True(t, cached)
+
+	ls.receiveMemoryChange([]byte(`{"scope":"session","scope_id":"sess-1","key":"plan","action":"set","data":"v2"}`))
+	_, cached = ls.cache.Load("session:sess-1:plan")
+	require.False(t, cached, "another replica changed the key")
+}
+
+func TestPostgresBackplane_RelaysMemoryChangesBetweenReplicas(t *testing.T) {
+	postgresURL := os.Getenv("POSTGRES_TEST_URL")
+	if postgresURL == "" {
+		t.Skip("POSTGRES_TEST_URL not set, skipping postgres tests")
+	}
+
+	ctx := context.Background()
+	openReplica := func() *LocalStorage {
+		ls := NewPostgresStorage(PostgresStorageConfig{})
+		err := ls.Initialize(ctx, StorageConfig{Mode: "postgres", Postgres: PostgresStorageConfig{DSN: postgresURL}})
+		if err != nil {
+			if strings.Contains(err.Error(), "connection refused") || strings.Contains(err.Error(), "does not exist") {
+				t.Skip("PostgreSQL not available, skipping test")
+			}
+			require.NoError(t, err)
+		}
+		t.Cleanup(func() { _ = ls.Close(ctx) })
+		return ls
+	}
+	replicaA, replicaB := openReplica(), openReplica()
+	require.NotNil(t, replicaA.EventBackplane())
+
+	changes, err := replicaB.SubscribeToMemoryChanges(ctx, "session", "backplane-session")
+	require.NoError(t, err)
+
+	large := strings.Repeat("x", 2*postgresNotifyPayloadLimit)
+	// The listener connects asynchronously, so publish until replica B hears about it.
+	deadline := time.After(10 * time.Second)
+	for {
+		require.NoError(t, replicaA.PublishMemoryChange(ctx, types.MemoryChangeEvent{
+			Scope:   "session",
+			ScopeID: "backplane-session",
+			Key:     "notes",
+			Action:  "set",
+			Data:    json.RawMessage(`"` + large + `"`),
+		}))
+		select {
+		case event := <-changes:
+			require.Equal(t, "notes", event.Key)
+			require.JSONEq(t, `"`+large+`"`, string(event.Data), "payloads over the NOTIFY limit arrive through the spill table")
+			return
+		case <-deadline:
+			t.Fatal("replica B never received the memory change")
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+)
+
+const executionActionColumns = `
+	action_id, execution_id, run_id, parent_execution_id, session_id, actor_id,
+	node_id, target, target_type, input, status, attempt_count,
+	lease_expires_at, claimed_at, completed_at, created_at, updated_at`
+
+// EnqueueExecutionAction persists a pending action for a poll-mode agent node.
+func (ls *LocalStorage) EnqueueExecutionAction(ctx context.Context, action *types.ExecutionAction) error {
+	if action == nil {
+		return fmt.Errorf("execution action is nil")
+	}
+	if strings.TrimSpace(action.ActionID) == "" {
+		return fmt.Errorf("action id is required")
+	}
+	if strings.TrimSpace(action.NodeID) == "" {
+		return fmt.Errorf("node id is required for execution action")
+	}
+
+	now := time.Now().UTC()
+	if action.CreatedAt.IsZero() {
+		action.CreatedAt = now
+	}
+	action.UpdatedAt = now
+	action.Status = types.ExecutionActionStatusPending
+	if action.TargetType == "" {
+		action.TargetType = "reasoner"
+	}
+
+	_, err := ls.requireSQLDB().ExecContext(ctx, `
+		INSERT INTO execution_actions (
+			action_id, execution_id, run_id, parent_execution_id, session_id, actor_id,
+			node_id, target, target_type, input, status, attempt_count,
+			lease_expires_at, claimed_at, completed_at, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, NULL, NULL, NULL, ?, ?)
+	`,
+		action.ActionID,
+		action.ExecutionID,
+		action.RunID,
+		action.ParentExecutionID,
+		action.SessionID,
+		action.ActorID,
+		action.NodeID,
+		action.Target,
+		action.TargetType,
+		bytesOrNil(action.Input),
+		action.Status,
+		action.CreatedAt,
+		action.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("enqueue execution action: %w", err)
+	}
+	return nil
+}
+
+// ClaimExecutionActions leases up to limit pending actions for the node. Actions whose
+// previous lease expired without an acknowledgement are offered again.
+func (ls *LocalStorage) ClaimExecutionActions(ctx context.Context, nodeID string, limit int, leaseTTL time.Duration) ([]*types.ExecutionAction, error) {
+	if limit <= 0 {
+		limit = 1
+	}
+	if leaseTTL <= 0 {
+		leaseTTL = 5 * time.Minute
+	}
+
+	db := ls.requireSQLDB()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer rollbackTx(tx, "ClaimExecutionActions:"+nodeID)
+
+	now := time.Now().UTC()
+	rows, err := tx.QueryContext(ctx, `
+		SELECT action_id
+		FROM execution_actions
+		WHERE node_id = ?
+		  AND (status = ? OR (status = ? AND lease_expires_at <= ?))
+		ORDER BY created_at ASC, action_id
+		LIMIT ?`,
+		nodeID,
+		types.ExecutionActionStatusPending,
+		types.ExecutionActionStatusLeased,
+		now,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list claimable execution actions: %w", err)
+	}
+
+	var candidates []string
+	for rows.Next() {
+		var actionID string
+		if err := rows.Scan(&actionID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan claimable execution action: %w", err)
+		}
+		candidates = append(candidates, actionID)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("iterate claimable execution actions: %w", err)
+	}
+	rows.Close()
+
+	leaseExpiresAt := now.Add(leaseTTL)
+	claimed := make([]*types.ExecutionAction, 0, len(candidates))
+	for _, actionID := range candidates {
+		// The status predicate is repeated so a concurrent claimer that won the row
+		// leaves us with zero affected rows instead of a double lease.
+		result, err := tx.ExecContext(ctx, `
+			UPDATE execution_actions
+			SET status = ?,
+			    attempt_count = attempt_count + 1,
+			    lease_expires_at = ?,
+			    claimed_at = ?,
+			    updated_at = ?
+			WHERE action_id = ?
+			  AND (status = ? OR (status = ? AND lease_expires_at <= ?))`,
+			types.ExecutionActionStatusLeased,
+			leaseExpiresAt,
+			now,
+			now,
+			actionID,
+			types.ExecutionActionStatusPending,
+			types.ExecutionActionStatusLeased,
+			now,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("lease execution action: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("rows affected lease execution action: %w", err)
+		}
+		if affected == 0 {
+			continue
+		}
+
+		row := tx.QueryRowContext(ctx, `SELECT `+executionActionColumns+` FROM execution_actions WHERE action_id = ?`, actionID)
+		action, err := scanExecutionAction(row)
+		if err != nil {
+			return nil, err
+		}
+		if action != nil {
+			claimed = append(claimed, action)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit execution action claim: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// GetExecutionAction fetches a queued action by ID.
+func (ls *LocalStorage) GetExecutionAction(ctx context.Context, actionID string) (*types.ExecutionAction, error) {
+	row := ls.requireSQLDB().QueryRowContext(ctx, `SELECT `+executionActionColumns+` FROM execution_actions WHERE action_id = ?`, actionID)
+	return scanExecutionAction(row)
+}
+
+// ExtendExecutionActionLease pushes the lease deadline of a leased action forward.
+func (ls *LocalStorage) ExtendExecutionActionLease(ctx context.Context, actionID string, leaseTTL time.Duration) error {
+	if leaseTTL <= 0 {
+		leaseTTL = 5 * time.Minute
+	}
+	now := time.Now().UTC()
+	result, err := ls.requireSQLDB().ExecContext(ctx, `
+		UPDATE execution_actions
+		SET lease_expires_at = ?, updated_at = ?
+		WHERE action_id = ? AND status = ?`,
+		now.Add(leaseTTL), now, actionID, types.ExecutionActionStatusLeased,
+	)
+	if err != nil {
+		return fmt.Errorf("extend execution action lease: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("execution action '%s' is not leased", actionID)
+	}
+	return nil
+}
+
+// CompleteExecutionAction marks an action as acknowledged. It reports false when the
+// action had already been completed so callers can treat duplicate acks as no-ops.
+func (ls *LocalStorage) CompleteExecutionAction(ctx context.Context, actionID string) (bool, error) {
+	now := time.Now().UTC()
+	result, err := ls.requireSQLDB().ExecContext(ctx, `
+		UPDATE execution_actions
+		SET status = ?, lease_expires_at = NULL, completed_at = ?, updated_at = ?
+		WHERE action_id = ? AND status <> ?`,
+		types.ExecutionActionStatusCompleted, now, now, actionID, types.ExecutionActionStatusCompleted,
+	)
+	if err != nil {
+		return false, fmt.Errorf("complete execution action: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("rows affected complete execution action: %w", err)
+	}
+	return rows > 0, nil
+}
+
+func scanExecutionAction(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*types.ExecutionAction, error) {
+	var (
+		action                       types.ExecutionAction
+		parentExecutionID, sessionID sql.NullString
+		actorID                      sql.NullString
+		input                        []byte
+		leaseExpiresAt, claimedAt    sql.NullTime
+		completedAt                  sql.NullTime
+	)
+
+	if err := scanner.Scan(
+		&action.ActionID,
+		&action.ExecutionID,
+		&action.RunID,
+		&parentExecutionID,
+		&sessionID,
+		&actorID,
+		&action.NodeID,
+		&action.Target,
+		&action.TargetType,
+		&input,
+		&action.Status,
+		&action.AttemptCount,
+		&leaseExpiresAt,
+		&claimedAt,
+		&completedAt,
+		&action.CreatedAt,
+		&action.UpdatedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("scan execution action: %w", err)
+	}
+
+	if parentExecutionID.Valid {
+		action.ParentExecutionID = &parentExecutionID.String
+	}
+	if sessionID.Valid {
+		action.SessionID = &sessionID.String
+	}
+	if actorID.Valid {
+		action.ActorID = &actorID.String
+	}
+	if len(input) > 0 {
+		action.Input = append(json.RawMessage(nil), input...)
+	}
+	if leaseExpiresAt.Valid {
+		value := leaseExpiresAt.Time.UTC()
+		action.LeaseExpiresAt = &value
+	}
+	if claimedAt.Valid {
+		value := claimedAt.Time.UTC()
+		action.ClaimedAt = &value
+	}
+	if completedAt.Valid {
+		value := completedAt.Time.UTC()
+		action.CompletedAt = &value
+	}
+
+	return &action, nil
+}
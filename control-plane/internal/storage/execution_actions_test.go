@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionActions_ClaimLeasesOldestFirst(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+
+	for _, id := range []string{"action-1", "action-2", "action-3"} {
+		require.NoError(t, provider.EnqueueExecutionAction(ctx, &types.ExecutionAction{
+			ActionID:    id,
+			ExecutionID: "exec-" + id,
+			RunID:       "run-1",
+			NodeID:      "node-1",
+			Target:      "greet",
+			Input:       json.RawMessage(`{"name":"ada"}`),
+		}))
+		time.Sleep(2 * time.Millisecond)
+	}
+	require.NoError(t, provider.EnqueueExecutionAction(ctx, &types.ExecutionAction{
+		ActionID:    "other-node",
+		ExecutionID: "exec-other",
+		RunID:       "run-2",
+		NodeID:      "node-2",
+		Target:      "greet",
+	}))
+
+	claimed, err := provider.ClaimExecutionActions(ctx, "node-1", 2, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	assert.Equal(t, "action-1", claimed[0].ActionID)
+	assert.Equal(t, "action-2", claimed[1].ActionID)
+	assert.Equal(t, types.ExecutionActionStatusLeased, claimed[0].Status)
+	assert.Equal(t, 1, claimed[0].AttemptCount)
+	assert.Equal(t, "reasoner", claimed[0].TargetType)
+	assert.JSONEq(t, `{"name":"ada"}`, string(claimed[0].Input))
+	require.NotNil(t, claimed[0].LeaseExpiresAt)
+
+	remaining, err := provider.ClaimExecutionActions(ctx, "node-1", 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "action-3", remaining[0].ActionID)
+
+	empty, err := provider.ClaimExecutionActions(ctx, "node-1", 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestExecutionActions_ExpiredLeaseIsReoffered(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+
+	require.NoError(t, provider.EnqueueExecutionAction(ctx, &types.ExecutionAction{
+		ActionID:    "action-1",
+		ExecutionID: "exec-1",
+		RunID:       "run-1",
+		NodeID:      "node-1",
+		Target:      "greet",
+	}))
+
+	claimed, err := provider.ClaimExecutionActions(ctx, "node-1", 1, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+
+	time.Sleep(30 * time.Millisecond)
+
+	reclaimed, err := provider.ClaimExecutionActions(ctx, "node-1", 1, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, reclaimed, 1)
+	assert.Equal(t, 2, reclaimed[0].AttemptCount)
+
+	require.NoError(t, provider.ExtendExecutionActionLease(ctx, "action-1", time.Minute))
+}
+
+func TestExecutionActions_CompleteIsIdempotent(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+
+	require.NoError(t, provider.EnqueueExecutionAction(ctx, &types.ExecutionAction{
+		ActionID:    "action-1",
+		ExecutionID: "exec-1",
+		RunID:       "run-1",
+		NodeID:      "node-1",
+		Target:      "greet",
+	}))
+
+	completed, err := provider.CompleteExecutionAction(ctx, "action-1")
+	require.NoError(t, err)
+	assert.True(t, completed)
+
+	completed, err = provider.CompleteExecutionAction(ctx, "action-1")
+	require.NoError(t, err)
+	assert.False(t, completed)
+
+	action, err := provider.GetExecutionAction(ctx, "action-1")
+	require.NoError(t, err)
+	require.NotNil(t, action)
+	assert.Equal(t, types.ExecutionActionStatusCompleted, action.Status)
+	assert.NotNil(t, action.CompletedAt)
+
+	claimed, err := provider.ClaimExecutionActions(ctx, "node-1", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	require.Error(t, provider.ExtendExecutionActionLease(ctx, "action-1", time.Minute))
+
+	missing, err := provider.GetExecutionAction(ctx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+)
+
+// EnqueueExecution persists an accepted async execution until a worker dispatches it.
+func (ls *LocalStorage) EnqueueExecution(ctx context.Context, item *types.QueuedExecution) error {
+	if item == nil {
+		return fmt.Errorf("queued execution is nil")
+	}
+	if strings.TrimSpace(item.ExecutionID) == "" {
+		return fmt.Errorf("execution id is required")
+	}
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = time.Now().UTC()
+	}
+	if item.TargetType == "" {
+		item.TargetType = "reasoner"
+	}
+
+	var policyJSON []byte
+	if item.RetryPolicy != nil {
+		var err error
+		if policyJSON, err = json.Marshal(item.RetryPolicy); err != nil {
+			return fmt.Errorf("marshal retry policy: %w", err)
+		}
+	}
+
+	_, err := ls.requireSQLDB().ExecContext(ctx, `
+		INSERT INTO execution_queue (execution_id, node_id, target, target_type, payload, retry_policy, caller, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		item.ExecutionID,
+		item.NodeID,
+		item.Target,
+		item.TargetType,
+		bytesOrNil(item.Payload),
+		bytesOrNil(policyJSON),
+		item.Caller,
+		item.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("enqueue execution: %w", err)
+	}
+	return nil
+}
+
+// ListQueuedExecutions returns up to limitPerTarget queued executions per target (node and
+// reasoner or skill), oldest first. Limiting per target keeps the backlog of a target
+// at its in-flight limit from hiding the entries of every other target. Callers
+// coordinate ownership of individual entries through AcquireLock.
+func (ls *LocalStorage) ListQueuedExecutions(ctx context.Context, limitPerTarget int) ([]*types.QueuedExecution, error) {
+	if limitPerTarget <= 0 {
+		limitPerTarget = 100
+	}
+
+	rows, err := ls.requireSQLDB().QueryContext(ctx, `
+		SELECT execution_id, node_id, target, target_type, payload, retry_policy, caller, created_at
+		FROM (
+			SELECT execution_id, node_id, target, target_type, payload, retry_policy, caller, created_at,
+				ROW_NUMBER() OVER (PARTITION BY node_id, target ORDER BY created_at ASC) AS target_position
+			FROM execution_queue
+		) ranked
+		WHERE target_position <= ?
+		ORDER BY created_at ASC`, limitPerTarget)
+	if err != nil {
+		return nil, fmt.Errorf("list queued executions: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]*types.QueuedExecution, 0)
+	for rows.Next() {
+		var (
+			item       types.QueuedExecution
+			payload    []byte
+			policyJSON []byte
+		)
+		if err := rows.Scan(&item.ExecutionID, &item.NodeID, &item.Target, &item.TargetType, &payload, &policyJSON, &item.Caller, &item.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan queued execution: %w", err)
+		}
+		if len(payload) > 0 {
+			item.Payload = append([]byte(nil), payload...)
+		}
+		if len(policyJSON) > 0 {
+			var policy types.RetryPolicy
+			if err := json.Unmarshal(policyJSON, &policy); err != nil {
+				return nil, fmt.Errorf("unmarshal retry policy: %w", err)
+			}
+			item.RetryPolicy = &policy
+		}
+		items = append(items, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate queued executions: %w", err)
+	}
+	return items, nil
+}
+
+// DeleteQueuedExecution removes a dispatched execution from the queue. Deleting an
+// entry that is already gone is not an error.
+func (ls *LocalStorage) DeleteQueuedExecution(ctx context.Context, executionID string) error {
+	if _, err := ls.requireSQLDB().ExecContext(ctx, `DELETE FROM execution_queue WHERE execution_id = ?`, executionID); err != nil {
+		return fmt.Errorf("delete queued execution: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionQueue_ListsOldestFirstAndDeletes(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+
+	base := time.Now().UTC().Add(-time.Minute)
+	// Insert out of order; listing must follow created_at.
+	for _, item := range []struct {
+		id     string
+		offset time.Duration
+		policy *types.RetryPolicy
+	}{
+		{"exec-2", time.Second, nil},
+		{"exec-1", 0, &types.RetryPolicy{MaxAttempts: 3, RetryableStatusCodes: []int{502}}},
+		{"exec-3", 2 * time.Second, nil},
+	} {
+		require.NoError(t, provider.EnqueueExecution(ctx, &types.QueuedExecution{
+			ExecutionID: item.id,
+			NodeID:      "node-1",
+			Target:      "greet",
+			Payload:     json.RawMessage(`{"id":"` + item.id + `"}`),
+			RetryPolicy: item.policy,
+			Caller:      "key-" + item.id,
+			CreatedAt:   base.Add(item.offset),
+		}))
+	}
+
+	items, err := provider.ListQueuedExecutions(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "exec-1", items[0].ExecutionID)
+	assert.Equal(t, "exec-2", items[1].ExecutionID)
+	assert.Equal(t, "reasoner", items[0].TargetType)
+	assert.Equal(t, "key-exec-1", items[0].Caller)
+	assert.JSONEq(t, `{"id":"exec-1"}`, string(items[0].Payload))
+	require.NotNil(t, items[0].RetryPolicy)
+	assert.Equal(t, 3, items[0].RetryPolicy.MaxAttempts)
+	assert.Equal(t, []int{502}, items[0].RetryPolicy.RetryableStatusCodes)
+	assert.Nil(t, items[1].RetryPolicy)
+
+	require.NoError(t, provider.DeleteQueuedExecution(ctx, "exec-1"))
+	require.NoError(t, provider.DeleteQueuedExecution(ctx, "exec-1"))
+
+	items, err = provider.ListQueuedExecutions(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "exec-2", items[0].ExecutionID)
+	assert.Equal(t, "exec-3", items[1].ExecutionID)
+
+	require.Error(t, provider.EnqueueExecution(ctx, &types.QueuedExecution{NodeID: "node-1"}))
+}
+
+func TestExecutionQueue_LimitsEachTargetSeparately(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+
+	base := time.Now().UTC().Add(-time.Minute)
+	// A backlog for one target must not hide the newer entry of another.
+	for i, item := range []struct{ id, node, target string }{
+		{"busy-1", "node-1", "greet"},
+		{"busy-2", "node-1", "greet"},
+		{"busy-3", "node-1", "greet"},
+		{"other-target", "node-1", "summarize"},
+		{"other-node", "node-2", "greet"},
+	} {
+		require.NoError(t, provider.EnqueueExecution(ctx, &types.QueuedExecution{
+			ExecutionID: item.id,
+			NodeID:      item.node,
+			Target:      item.target,
+			CreatedAt:   base.Add(time.Duration(i) * time.Second),
+		}))
+	}
+
+	items, err := provider.ListQueuedExecutions(ctx, 2)
+	require.NoError(t, err)
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ExecutionID)
+	}
+	assert.Equal(t, []string{"busy-1", "busy-2", "other-target", "other-node"}, ids)
+}
@@ -38,9 +38,9 @@ func (ls *LocalStorage) CreateExecutionRecord(ctx context.Context, exec *types.E
 			input_uri, result_uri,
 			session_id, actor_id,
 			started_at, completed_at, duration_ms,
-			notes,
+			notes, retry_count, schedule_id,
 			created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	// Serialize notes to JSON
 	var notesJSON []byte
@@ -73,6 +73,8 @@ func (ls *LocalStorage) CreateExecutionRecord(ctx context.Context, exec *types.E
 		exec.CompletedAt,
 		exec.DurationMS,
 		notesJSON,
+		exec.RetryCount,
+		exec.ScheduleID,
 		exec.CreatedAt,
 		exec.UpdatedAt,
 	)
@@ -92,7 +94,7 @@ func (ls *LocalStorage) GetExecutionRecord(ctx context.Context, executionID stri
 		       input_uri, result_uri,
 		       session_id, actor_id,
 		       started_at, completed_at, duration_ms,
-		       notes,
+		       notes, retry_count, schedule_id,
 		       created_at, updated_at
 		FROM executions
 	WHERE execution_id = ?`
@@ -129,7 +131,7 @@ func (ls *LocalStorage) UpdateExecutionRecord(ctx context.Context, executionID s
 		       input_uri, result_uri,
 		       session_id, actor_id,
 		       started_at, completed_at, duration_ms,
-		       notes,
+		       notes, retry_count, schedule_id,
 		       created_at, updated_at
 		FROM executions
 		WHERE execution_id = ?`, executionID)
@@ -180,6 +182,8 @@ func (ls *LocalStorage) UpdateExecutionRecord(ctx context.Context, executionID s
 			completed_at = ?,
 			duration_ms = ?,
 			notes = ?,
+			retry_count = ?,
+			schedule_id = ?,
 			updated_at = ?
 		WHERE execution_id = ?`
 
@@ -203,6 +207,8 @@ func (ls *LocalStorage) UpdateExecutionRecord(ctx context.Context, executionID s
 		updated.CompletedAt,
 		updated.DurationMS,
 		notesJSON,
+		updated.RetryCount,
+		updated.ScheduleID,
 		updated.UpdatedAt,
 		updated.ExecutionID,
 	)
@@ -241,6 +247,16 @@ func (ls *LocalStorage) QueryExecutionRecords(ctx context.Context, filter types.
 		where = append(where, "agent_node_id = ?")
 		args = append(args, *filter.AgentNodeID)
 	}
+	if filter.AgentNodeIDs != nil {
+		if len(filter.AgentNodeIDs) == 0 {
+			where = append(where, "1 = 0")
+		} else {
+			where = append(where, "agent_node_id IN (?"+strings.Repeat(", ?", len(filter.AgentNodeIDs)-1)+")")
+			for _, id := range filter.AgentNodeIDs {
+				args = append(args, id)
+			}
+		}
+	}
 	if filter.ReasonerID != nil {
 		where = append(where, "reasoner_id = ?")
 		args = append(args, *filter.ReasonerID)
@@ -257,6 +273,10 @@ func (ls *LocalStorage) QueryExecutionRecords(ctx context.Context, filter types.
 		where = append(where, "actor_id = ?")
 		args = append(args, *filter.ActorID)
 	}
+	if filter.ScheduleID != nil {
+		where = append(where, "schedule_id = ?")
+		args = append(args, *filter.ScheduleID)
+	}
 	if filter.StartTime != nil {
 		where = append(where, "started_at >= ?")
 		args = append(args, filter.StartTime.UTC())
@@ -274,7 +294,7 @@ func (ls *LocalStorage) QueryExecutionRecords(ctx context.Context, filter types.
 		       input_uri, result_uri,
 		       session_id, actor_id,
 		       started_at, completed_at, duration_ms,
-		       notes,
+		       notes, retry_count, schedule_id,
 		       created_at, updated_at
 		FROM executions`)
 
@@ -364,6 +384,16 @@ func (ls *LocalStorage) QueryRunSummaries(ctx context.Context, filter types.Exec
 		where = append(where, "actor_id = ?")
 		args = append(args, *filter.ActorID)
 	}
+	if filter.AgentNodeIDs != nil {
+		if len(filter.AgentNodeIDs) == 0 {
+			where = append(where, "1 = 0")
+		} else {
+			where = append(where, "agent_node_id IN (?"+strings.Repeat(", ?", len(filter.AgentNodeIDs)-1)+")")
+			for _, id := range filter.AgentNodeIDs {
+				args = append(args, id)
+			}
+		}
+	}
 	if filter.StartTime != nil {
 		where = append(where, "started_at >= ?")
 		args = append(args, filter.StartTime.UTC())
@@ -418,7 +448,7 @@ func (ls *LocalStorage) QueryRunSummaries(ctx context.Context, filter types.Exec
 			SUM(CASE WHEN LOWER(status) = 'running' THEN 1 ELSE 0 END) AS running_count,
 			SUM(CASE WHEN LOWER(status) = 'pending' THEN 1 ELSE 0 END) AS pending_count,
 			SUM(CASE WHEN LOWER(status) = 'queued' THEN 1 ELSE 0 END) AS queued_count,
-			SUM(CASE WHEN LOWER(status) IN ('running','pending','queued') THEN 1 ELSE 0 END) AS active_executions,
+			SUM(CASE WHEN LOWER(status) IN ('running','pending','queued','waiting_for_approval') THEN 1 ELSE 0 END) AS active_executions,
 			MAX(CASE WHEN parent_execution_id IS NULL OR parent_execution_id = '' THEN execution_id END) AS root_execution_id,
 			MAX(CASE WHEN parent_execution_id IS NULL OR parent_execution_id = '' THEN agent_node_id END) AS root_agent_node_id,
 			MAX(CASE WHEN parent_execution_id IS NULL OR parent_execution_id = '' THEN reasoner_id END) AS root_reasoner_id,
@@ -426,7 +456,7 @@ func (ls *LocalStorage) QueryRunSummaries(ctx context.Context, filter types.Exec
 			MAX(actor_id) AS actor_id,
 			CASE
 				WHEN SUM(CASE WHEN LOWER(status) IN ('failed','cancelled','timeout') THEN 1 ELSE 0 END) > 0 THEN 2
-				WHEN SUM(CASE WHEN LOWER(status) IN ('running','pending','queued') THEN 1 ELSE 0 END) > 0 THEN 1
+				WHEN SUM(CASE WHEN LOWER(status) IN ('running','pending','queued','waiting_for_approval') THEN 1 ELSE 0 END) > 0 THEN 1
 				ELSE 0
 			END AS status_rank
 		FROM executions
@@ -706,7 +736,8 @@ func (ls *LocalStorage) getRunAggregation(ctx context.Context, runID string) (*R
 		// Count active executions
 		if normalized == string(types.ExecutionStatusRunning) ||
 			normalized == string(types.ExecutionStatusPending) ||
-			normalized == string(types.ExecutionStatusQueued) {
+			normalized == string(types.ExecutionStatusQueued) ||
+			normalized == string(types.ExecutionStatusWaitingForApproval) {
 			activeCount += count
 		}
 	}
@@ -1039,6 +1070,7 @@ func scanExecution(scanner interface {
 		completedAt                  sql.NullTime
 		durationMS                   sql.NullInt64
 		notesJSON                    []byte
+		scheduleID                   sql.NullString
 	)
 
 	err := scanner.Scan(
@@ -1060,6 +1092,8 @@ func scanExecution(scanner interface {
 		&completedAt,
 		&durationMS,
 		&notesJSON,
+		&exec.RetryCount,
+		&scheduleID,
 		&exec.CreatedAt,
 		&exec.UpdatedAt,
 	)
@@ -1073,6 +1107,9 @@ func scanExecution(scanner interface {
 	if parentExecutionID.Valid {
 		exec.ParentExecutionID = &parentExecutionID.String
 	}
+	if scheduleID.Valid {
+		exec.ScheduleID = &scheduleID.String
+	}
 	if sessionID.Valid {
 		exec.SessionID = &sessionID.String
 	}
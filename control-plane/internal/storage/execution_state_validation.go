@@ -23,14 +23,15 @@ func validateExecutionStateTransition(currentStatus, newStatus string) error {
 	newStatus = types.NormalizeExecutionStatus(newStatus)
 
 	validTransitions := map[string][]string{
-		string(types.ExecutionStatusUnknown):   {string(types.ExecutionStatusPending)},
-		string(types.ExecutionStatusPending):   {string(types.ExecutionStatusQueued), string(types.ExecutionStatusRunning), string(types.ExecutionStatusCancelled)},
-		string(types.ExecutionStatusQueued):    {string(types.ExecutionStatusRunning), string(types.ExecutionStatusCancelled)},
-		string(types.ExecutionStatusRunning):   {string(types.ExecutionStatusSucceeded), string(types.ExecutionStatusFailed), string(types.ExecutionStatusCancelled), string(types.ExecutionStatusTimeout)},
-		string(types.ExecutionStatusSucceeded): {},
-		string(types.ExecutionStatusFailed):    {},
-		string(types.ExecutionStatusCancelled): {},
-		string(types.ExecutionStatusTimeout):   {},
+		string(types.ExecutionStatusUnknown):            {string(types.ExecutionStatusPending)},
+		string(types.ExecutionStatusPending):            {string(types.ExecutionStatusQueued), string(types.ExecutionStatusRunning), string(types.ExecutionStatusCancelled)},
+		string(types.ExecutionStatusQueued):             {string(types.ExecutionStatusRunning), string(types.ExecutionStatusCancelled)},
+		string(types.ExecutionStatusRunning):            {string(types.ExecutionStatusSucceeded), string(types.ExecutionStatusFailed), string(types.ExecutionStatusCancelled), string(types.ExecutionStatusTimeout), string(types.ExecutionStatusWaitingForApproval)},
+		string(types.ExecutionStatusWaitingForApproval): {string(types.ExecutionStatusRunning), string(types.ExecutionStatusSucceeded), string(types.ExecutionStatusFailed), string(types.ExecutionStatusCancelled), string(types.ExecutionStatusTimeout)},
+		string(types.ExecutionStatusSucceeded):          {},
+		string(types.ExecutionStatusFailed):             {},
+		string(types.ExecutionStatusCancelled):          {},
+		string(types.ExecutionStatusTimeout):            {},
 	}
 
 	allowedStates, exists := validTransitions[currentStatus]
@@ -446,6 +446,11 @@ type LocalStorage struct {
 	vectorStore               vectorStore
 	eventBus                  *events.ExecutionEventBus // Event bus for real-time updates
 	workflowExecutionEventBus *events.EventBus[*types.WorkflowExecutionEvent]
+	memoryExpiryStop          chan struct{} // Stops the memory TTL sweep
+	memoryExpiryDone          chan struct{}
+	backplane                 *PostgresBackplane // Relays events to other replicas; nil when alone
+	memoryEncryption          MemoryEncryptionConfig
+	memoryKeys                MemoryKeyProvider // Encrypts memory values; nil when encryption is off
 }
 
 // NewLocalStorage creates a new instance of LocalStorage.
@@ -490,15 +495,26 @@ func (ls *LocalStorage) Initialize(ctx context.Context, config StorageConfig) er
 	ls.postgresConfig = config.Postgres
 	ls.vectorConfig = config.Vector.normalized()
 	ls.vectorMetric = parseDistanceMetric(ls.vectorConfig.Distance)
+	ls.memoryEncryption = config.MemoryEncryption
 
+	var err error
 	switch mode {
 	case "local":
-		return ls.initializeSQLite(ctx)
+		err = ls.initializeSQLite(ctx)
 	case "postgres":
-		return ls.initializePostgres(ctx)
+		err = ls.initializePostgres(ctx)
 	default:
 		return fmt.Errorf("unsupported storage mode: %s", mode)
 	}
+	if err != nil {
+		return err
+	}
+	if err := ls.startEventBackplane(config.EventBackplane); err != nil {
+		return err
+	}
+
+	ls.startMemoryExpiry()
+	return nil
 }
 
 func (ls *LocalStorage) initializeSQLite(ctx context.Context) error {
@@ -896,8 +912,7 @@ func (ls *LocalStorage) createSchema(ctx context.Context) error {
 
 func (ls *LocalStorage) initializeMemoryBuckets() error {
 	if err := ls.kvStore.Update(func(tx *bolt.Tx) error {
-		scopes := []string{"workflow", "session", "actor", "reasoner", "global"}
-		for _, scope := range scopes {
+		for _, scope := range memoryScopes {
 			if _, err := tx.CreateBucketIfNotExists([]byte(scope)); err != nil {
 				return fmt.Errorf("failed to create BoltDB bucket '%s': %w", scope, err)
 			}
@@ -917,11 +932,21 @@ func (ls *LocalStorage) ensurePostgresKeyValueSchema(ctx context.Context) error
                 key TEXT NOT NULL,
                 value JSONB NOT NULL,
                 updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                expires_at TIMESTAMPTZ,
                 PRIMARY KEY (scope, scope_id, key)
         );`
 
-	_, err := ls.db.Exec(createTable)
-	return err
+	statements := []string{
+		createTable,
+		`ALTER TABLE kv_store ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;`,
+		`CREATE INDEX IF NOT EXISTS idx_kv_store_expires_at ON kv_store(expires_at) WHERE expires_at IS NOT NULL;`,
+	}
+	for _, stmt := range statements {
+		if _, err := ls.db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (ls *LocalStorage) ensurePostgresEventSchema(ctx context.Context) error {
@@ -1196,6 +1221,9 @@ func (ls *LocalStorage) ensureSQLiteVectorSchema() error {
 			dimension INTEGER NOT NULL,
 			embedding BLOB NOT NULL,
 			metadata JSON DEFAULT '{}',
+			text TEXT,
+			access_level TEXT,
+			access_control JSON,
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			PRIMARY KEY(scope, scope_id, key)
@@ -1209,6 +1237,33 @@ func (ls *LocalStorage) ensureSQLiteVectorSchema() error {
 			return fmt.Errorf("failed to ensure sqlite vector schema: %w", err)
 		}
 	}
+
+	// Tables created by earlier versions lack the later columns.
+	for _, column := range []string{"text TEXT", "access_level TEXT", "access_control JSON"} {
+		if _, err := ls.db.Exec(`ALTER TABLE memory_vectors ADD COLUMN ` + column + `;`); err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+			return fmt.Errorf("failed to add column %s to memory_vectors: %w", column, err)
+		}
+	}
+
+	// memory_vectors_fts indexes the source text by the rowid of its vector.
+	ftsStatements := []string{
+		`CREATE VIRTUAL TABLE IF NOT EXISTS memory_vectors_fts USING fts5(text);`,
+		`CREATE TRIGGER IF NOT EXISTS memory_vectors_fts_insert AFTER INSERT ON memory_vectors WHEN new.text IS NOT NULL BEGIN
+			INSERT INTO memory_vectors_fts(rowid, text) VALUES (new.rowid, new.text);
+		END;`,
+		`CREATE TRIGGER IF NOT EXISTS memory_vectors_fts_update AFTER UPDATE OF text ON memory_vectors BEGIN
+			DELETE FROM memory_vectors_fts WHERE rowid = old.rowid;
+			INSERT INTO memory_vectors_fts(rowid, text) SELECT new.rowid, new.text WHERE new.text IS NOT NULL;
+		END;`,
+		`CREATE TRIGGER IF NOT EXISTS memory_vectors_fts_delete AFTER DELETE ON memory_vectors BEGIN
+			DELETE FROM memory_vectors_fts WHERE rowid = old.rowid;
+		END;`,
+	}
+	for _, stmt := range ftsStatements {
+		if _, err := ls.db.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to ensure sqlite vector text index: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -1227,6 +1282,12 @@ func (ls *LocalStorage) ensurePostgresVectorSchema(ctx context.Context) error {
 		);`,
 		`CREATE INDEX IF NOT EXISTS idx_memory_vectors_scope ON memory_vectors(scope, scope_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_memory_vectors_metadata ON memory_vectors USING GIN(metadata);`,
+		`ALTER TABLE memory_vectors ADD COLUMN IF NOT EXISTS text TEXT;`,
+		`ALTER TABLE memory_vectors ADD COLUMN IF NOT EXISTS text_search TSVECTOR
+			GENERATED ALWAYS AS (to_tsvector('simple', coalesce(text, ''))) STORED;`,
+		`CREATE INDEX IF NOT EXISTS idx_memory_vectors_text_search ON memory_vectors USING GIN(text_search);`,
+		`ALTER TABLE memory_vectors ADD COLUMN IF NOT EXISTS access_level TEXT;`,
+		`ALTER TABLE memory_vectors ADD COLUMN IF NOT EXISTS access_control JSONB;`,
 	}
 
 	for _, stmt := range statements {
@@ -1243,11 +1304,21 @@ func (ls *LocalStorage) initializeVectorStore() error {
 		return nil
 	}
 
+	switch index := ls.vectorConfig.Index.Type; strings.ToLower(index) {
+	case "", "none", "hnsw":
+	default:
+		return fmt.Errorf("unsupported vector index type: %s", index)
+	}
+
 	switch ls.mode {
 	case "postgres":
 		ls.vectorStore = newPostgresVectorStore(ls.db, ls.vectorMetric)
 	default:
-		ls.vectorStore = newSQLiteVectorStore(ls.db, ls.vectorMetric)
+		store := newSQLiteVectorStore(ls.db, ls.vectorMetric)
+		if ls.vectorConfig.Index.enabled() {
+			store.enableIndex(ls.vectorConfig.Index)
+		}
+		ls.vectorStore = store
 	}
 	return nil
 }
@@ -1683,6 +1754,11 @@ func (ls *LocalStorage) Close(ctx context.Context) error {
 		return fmt.Errorf("context cancelled during close: %w", err)
 	}
 
+	ls.stopMemoryExpiry()
+	ls.stopEventBackplane()
+	if store, ok := ls.vectorStore.(*sqliteVectorStore); ok {
+		store.stopIndex()
+	}
 	if ls.db != nil {
 		if err := ls.db.Close(); err != nil {
 			return fmt.Errorf("failed to close database: %w", err)
@@ -3609,39 +3685,75 @@ func (ls *LocalStorage) QuerySessions(ctx context.Context, filters types.Session
 	return sessions, nil
 }
 
-// SetMemory stores a memory record in BoltDB.
+// SetMemory stores a memory record in BoltDB, replacing any previous value.
 func (ls *LocalStorage) SetMemory(ctx context.Context, memory *types.Memory) error {
+	_, err := ls.UpdateMemory(ctx, memory.Scope, memory.ScopeID, memory.Key, func(*types.Memory) (*types.Memory, error) {
+		return memory, nil
+	})
+	return err
+}
+
+// UpdateMemory atomically replaces a memory record with the result of update, which
+// receives the current record or nil when the key is missing or expired. Returning nil
+// from update leaves the record unchanged. Every write bumps the record's version.
+func (ls *LocalStorage) UpdateMemory(ctx context.Context, scope, scopeID, key string, update func(*types.Memory) (*types.Memory, error)) (*types.Memory, error) {
 	if ls.mode == "postgres" {
-		return ls.setMemoryPostgres(ctx, memory)
+		return ls.updateMemoryPostgres(ctx, scope, scopeID, key, update)
 	}
 
 	// Fast-fail check for BoltDB operations since BoltDB doesn't support mid-flight cancellation
 	if err := ctx.Err(); err != nil {
-		return fmt.Errorf("context cancelled before BoltDB SetMemory operation: %w", err)
+		return nil, fmt.Errorf("context cancelled before BoltDB UpdateMemory operation: %w", err)
 	}
 
-	return ls.kvStore.Update(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket([]byte(memory.Scope))
+	var result *types.Memory
+	err := ls.kvStore.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(scope))
 		if bucket == nil {
-			return fmt.Errorf("BoltDB bucket '%s' not found", memory.Scope)
+			return fmt.Errorf("BoltDB bucket '%s' not found", scope)
+		}
+
+		boltKey := []byte(fmt.Sprintf("%s:%s", scopeID, key))
+		now := time.Now().UTC()
+		var current *types.Memory
+		if data := bucket.Get(boltKey); data != nil {
+			current = &types.Memory{}
+			if err := ls.decodeMemory(data, current); err != nil {
+				return fmt.Errorf("failed to unmarshal memory from BoltDB: %w", err)
+			}
+			if current.Expired(now) {
+				current = nil
+			}
 		}
 
-		key := fmt.Sprintf("%s:%s", memory.ScopeID, memory.Key)
-		data, err := json.Marshal(memory)
+		next, err := update(current)
 		if err != nil {
-			return fmt.Errorf("failed to marshal memory: %w", err)
+			return err
 		}
+		if next == nil {
+			result = current
+			return nil
+		}
+		prepareMemoryWrite(next, current, scope, scopeID, key, now)
 
-		// Store in BoltDB
-		if err := bucket.Put([]byte(key), data); err != nil {
+		data, err := ls.encodeMemory(next, current)
+		if err != nil {
+			return fmt.Errorf("failed to marshal memory: %w", err)
+		}
+		if err := bucket.Put(boltKey, data); err != nil {
 			return fmt.Errorf("failed to put memory in BoltDB: %w", err)
 		}
-
-		// Update cache
-		ls.cache.Store(fmt.Sprintf("%s:%s", memory.Scope, key), memory)
-
+		result = next
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result != nil {
+		ls.cache.Store(fmt.Sprintf("%s:%s:%s", scope, scopeID, key), result)
+	}
+	return result, nil
 }
 
 // GetMemory retrieves a memory record from BoltDB or cache.
@@ -3658,7 +3770,7 @@ func (ls *LocalStorage) GetMemory(ctx context.Context, scope, scopeID, key strin
 	cacheKey := fmt.Sprintf("%s:%s:%s", scope, scopeID, key)
 	if val, ok := ls.cache.Load(cacheKey); ok {
 		if memory, ok := val.(*types.Memory); ok {
-			return memory, nil
+			return ls.unexpiredMemory(cacheKey, memory)
 		}
 	}
 
@@ -3676,7 +3788,7 @@ func (ls *LocalStorage) GetMemory(ctx context.Context, scope, scopeID, key strin
 		}
 
 		memory = &types.Memory{}
-		if err := json.Unmarshal(data, memory); err != nil {
+		if err := ls.decodeMemory(data, memory); err != nil {
 			return fmt.Errorf("failed to unmarshal memory from BoltDB: %w", err)
 		}
 		return nil
@@ -3689,7 +3801,7 @@ func (ls *LocalStorage) GetMemory(ctx context.Context, scope, scopeID, key strin
 	// Store in cache
 	ls.cache.Store(cacheKey, memory)
 
-	return memory, nil
+	return ls.unexpiredMemory(cacheKey, memory)
 }
 
 // DeleteMemory deletes a memory record from BoltDB and cache.
@@ -3733,6 +3845,7 @@ func (ls *LocalStorage) ListMemory(ctx context.Context, scope, scopeID string) (
 		return nil, fmt.Errorf("context cancelled before BoltDB ListMemory operation: %w", err)
 	}
 
+	now := time.Now()
 	memories := []*types.Memory{}
 	err := ls.kvStore.View(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(scope))
@@ -3745,9 +3858,12 @@ func (ls *LocalStorage) ListMemory(ctx context.Context, scope, scopeID string) (
 		prefix := []byte(scopeID + ":")
 		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
 			memory := &types.Memory{}
-			if err := json.Unmarshal(v, memory); err != nil {
+			if err := ls.decodeMemory(v, memory); err != nil {
 				return fmt.Errorf("failed to unmarshal memory from BoltDB: %w", err)
 			}
+			if memory.Expired(now) {
+				continue
+			}
 			memories = append(memories, memory)
 		}
 		return nil
@@ -3825,31 +3941,107 @@ func (ls *LocalStorage) SimilaritySearch(ctx context.Context, scope, scopeID str
 	return ls.vectorStore.Search(ctx, scope, scopeID, queryEmbedding, topK, filters)
 }
 
-func (ls *LocalStorage) setMemoryPostgres(ctx context.Context, memory *types.Memory) error {
+// HybridSearch searches vector memory by embedding, by source text, or by both with the
+// two rankings fused.
+func (ls *LocalStorage) HybridSearch(ctx context.Context, scope, scopeID string, query types.VectorSearchQuery) ([]*types.VectorSearchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := ls.requireVectorStore(); err != nil {
+		return nil, err
+	}
+	return hybridSearch(ctx, ls.vectorStore, scope, scopeID, query)
+}
+
+func (ls *LocalStorage) updateMemoryPostgres(ctx context.Context, scope, scopeID, key string, update func(*types.Memory) (*types.Memory, error)) (*types.Memory, error) {
+	// Creating a missing key cannot be locked in advance; if another writer creates it
+	// first the insert is skipped and the update is retried against the new row.
+	for attempt := 0; attempt < 3; attempt++ {
+		result, inserted, err := ls.tryUpdateMemoryPostgres(ctx, scope, scopeID, key, update)
+		if err != nil || inserted {
+			return result, err
+		}
+	}
+	return nil, fmt.Errorf("memory key '%s' in scope '%s' is being created concurrently", key, scope)
+}
+
+// tryUpdateMemoryPostgres reports false when another writer created the key first.
+func (ls *LocalStorage) tryUpdateMemoryPostgres(ctx context.Context, scope, scopeID, key string, update func(*types.Memory) (*types.Memory, error)) (*types.Memory, bool, error) {
 	if err := ctx.Err(); err != nil {
-		return fmt.Errorf("context cancelled before postgres SetMemory operation: %w", err)
+		return nil, false, fmt.Errorf("context cancelled before postgres UpdateMemory operation: %w", err)
+	}
+
+	tx, err := ls.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer rollbackTx(tx, "UpdateMemory:"+key)
+
+	now := time.Now().UTC()
+	var (
+		current *types.Memory
+		payload []byte
+		exists  = true
+	)
+	row := tx.QueryRowContext(ctx, `SELECT value FROM kv_store WHERE scope = ? AND scope_id = ? AND key = ? FOR UPDATE`, scope, scopeID, key)
+	switch err := row.Scan(&payload); {
+	case err == sql.ErrNoRows:
+		exists = false
+	case err != nil:
+		return nil, false, fmt.Errorf("failed to load memory from postgres: %w", err)
+	default:
+		current = &types.Memory{}
+		if err := ls.decodeMemory(payload, current); err != nil {
+			return nil, false, fmt.Errorf("failed to unmarshal postgres memory payload: %w", err)
+		}
+		if current.Expired(now) {
+			current = nil
+		}
 	}
 
-	payload, err := json.Marshal(memory)
+	next, err := update(current)
 	if err != nil {
-		return fmt.Errorf("failed to marshal memory payload: %w", err)
+		return nil, false, err
+	}
+	if next == nil {
+		return current, true, tx.Commit()
+	}
+	prepareMemoryWrite(next, current, scope, scopeID, key, now)
+
+	payload, err = ls.encodeMemory(next, current)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal memory payload: %w", err)
 	}
 
 	query := `
-        INSERT INTO kv_store(scope, scope_id, key, value, updated_at)
-        VALUES (?, ?, ?, ?, NOW())
+        INSERT INTO kv_store(scope, scope_id, key, value, updated_at, expires_at)
+        VALUES (?, ?, ?, ?, NOW(), ?)
         ON CONFLICT(scope, scope_id, key) DO UPDATE SET
                 value = excluded.value,
-                updated_at = NOW();`
-
-	if _, err := ls.db.ExecContext(ctx, query, memory.Scope, memory.ScopeID, memory.Key, payload); err != nil {
-		return fmt.Errorf("failed to upsert memory in postgres: %w", err)
+                updated_at = NOW(),
+                expires_at = excluded.expires_at;`
+	if !exists {
+		query = `
+        INSERT INTO kv_store(scope, scope_id, key, value, updated_at, expires_at)
+        VALUES (?, ?, ?, ?, NOW(), ?)
+        ON CONFLICT(scope, scope_id, key) DO NOTHING;`
 	}
 
-	cacheKey := fmt.Sprintf("%s:%s:%s", memory.Scope, memory.ScopeID, memory.Key)
-	ls.cache.Store(cacheKey, memory)
+	res, err := tx.ExecContext(ctx, query, scope, scopeID, key, payload, next.ExpiresAt)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to upsert memory in postgres: %w", err)
+	}
+	if !exists {
+		if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+			return nil, false, nil
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("commit memory update: %w", err)
+	}
 
-	return nil
+	ls.cache.Store(fmt.Sprintf("%s:%s:%s", scope, scopeID, key), next)
+	return next, true, nil
 }
 
 func (ls *LocalStorage) getMemoryPostgres(ctx context.Context, scope, scopeID, key string) (*types.Memory, error) {
@@ -3860,7 +4052,7 @@ func (ls *LocalStorage) getMemoryPostgres(ctx context.Context, scope, scopeID, k
 	cacheKey := fmt.Sprintf("%s:%s:%s", scope, scopeID, key)
 	if val, ok := ls.cache.Load(cacheKey); ok {
 		if memory, ok := val.(*types.Memory); ok {
-			return memory, nil
+			return ls.unexpiredMemory(cacheKey, memory)
 		}
 	}
 
@@ -3876,12 +4068,12 @@ func (ls *LocalStorage) getMemoryPostgres(ctx context.Context, scope, scopeID, k
 	}
 
 	memory := &types.Memory{}
-	if err := json.Unmarshal(payload, memory); err != nil {
+	if err := ls.decodeMemory(payload, memory); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal postgres memory payload: %w", err)
 	}
 
 	ls.cache.Store(cacheKey, memory)
-	return memory, nil
+	return ls.unexpiredMemory(cacheKey, memory)
 }
 
 func (ls *LocalStorage) deleteMemoryPostgres(ctx context.Context, scope, scopeID, key string) error {
@@ -3909,8 +4101,8 @@ func (ls *LocalStorage) listMemoryPostgres(ctx context.Context, scope, scopeID s
 		return nil, fmt.Errorf("context cancelled before postgres ListMemory operation: %w", err)
 	}
 
-	query := `SELECT value FROM kv_store WHERE scope = ? AND scope_id = ?`
-	rows, err := ls.db.QueryContext(ctx, query, scope, scopeID)
+	query := `SELECT value FROM kv_store WHERE scope = ? AND scope_id = ? AND (expires_at IS NULL OR expires_at > ?)`
+	rows, err := ls.db.QueryContext(ctx, query, scope, scopeID, time.Now().UTC())
 	if err != nil {
 		return nil, fmt.Errorf("failed to list memory from postgres: %w", err)
 	}
@@ -3924,7 +4116,7 @@ func (ls *LocalStorage) listMemoryPostgres(ctx context.Context, scope, scopeID s
 		}
 
 		memory := &types.Memory{}
-		if err := json.Unmarshal(payload, memory); err != nil {
+		if err := ls.decodeMemory(payload, memory); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal postgres memory payload: %w", err)
 		}
 
@@ -4008,8 +4200,16 @@ func (ls *LocalStorage) Subscribe(channel string) (<-chan CacheMessage, error) {
 	return cacheMsgChannel, nil
 }
 
-// Publish implements the CacheProvider Publish method using local pub/sub.
+// Publish implements the CacheProvider Publish method using local pub/sub. Messages are
+// also relayed to the subscribers of other replicas when there is a backplane.
 func (ls *LocalStorage) Publish(channel string, message interface{}) error {
+	event := message.(types.MemoryChangeEvent) // Assuming message is always MemoryChangeEvent for this channel
+	ls.deliverCacheMessage(channel, event)
+	ls.relayToReplicas(cacheMessagesTopic, cacheMessageEnvelope{Channel: channel, Event: event})
+	return nil
+}
+
+func (ls *LocalStorage) deliverCacheMessage(channel string, event types.MemoryChangeEvent) {
 	ls.mu.RLock()
 	defer ls.mu.RUnlock()
 
@@ -4018,7 +4218,7 @@ func (ls *LocalStorage) Publish(channel string, message interface{}) error {
 		for _, subChannel := range subscribers {
 			// Non-blocking send
 			select {
-			case subChannel <- message.(types.MemoryChangeEvent): // Assuming message is always MemoryChangeEvent for this channel
+			case subChannel <- event:
 				// Sent successfully
 			default:
 				// Subscriber channel is full, drop the message or log a warning
@@ -4026,8 +4226,6 @@ func (ls *LocalStorage) Publish(channel string, message interface{}) error {
 			}
 		}
 	}
-
-	return nil
 }
 
 // publishMemoryChange is an internal helper to publish memory change events.
@@ -4042,6 +4240,12 @@ func subscriberKey(scope, scopeID string) string {
 }
 
 func (ls *LocalStorage) publishMemoryChange(event types.MemoryChangeEvent) {
+	ls.deliverMemoryChange(event)
+	ls.relayToReplicas(memoryChangesTopic, event)
+}
+
+// deliverMemoryChange hands a memory change event to the subscribers of this replica.
+func (ls *LocalStorage) deliverMemoryChange(event types.MemoryChangeEvent) {
 	targets := map[string]struct{}{}
 	keys := []string{
 		subscriberKey(event.Scope, event.ScopeID),
@@ -4162,7 +4366,11 @@ func (ls *LocalStorage) executeRegisterAgent(ctx context.Context, q DBTX, agent
 	return nil
 }
 
-// GetAgent retrieves an agent node record from SQLite by ID.
+// ErrAgentNotFound is returned when no agent node is registered under the requested ID.
+var ErrAgentNotFound = errors.New("agent node not found")
+
+// GetAgent retrieves an agent node record from SQLite by ID. It returns an error
+// wrapping ErrAgentNotFound when the node is not registered.
 func (ls *LocalStorage) GetAgent(ctx context.Context, id string) (*types.AgentNode, error) {
 	// Check context cancellation early
 	if err := ctx.Err(); err != nil {
@@ -4191,7 +4399,7 @@ func (ls *LocalStorage) GetAgent(ctx context.Context, id string) (*types.AgentNo
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("agent node with ID '%s' not found", id)
+			return nil, fmt.Errorf("%w: %s", ErrAgentNotFound, id)
 		}
 		return nil, fmt.Errorf("failed to get agent node with ID '%s': %w", id, err)
 	}
@@ -4464,7 +4672,7 @@ func (ls *LocalStorage) UpdateAgentHealthAtomic(ctx context.Context, id string,
 		if expectedLastHeartbeat != nil {
 			return fmt.Errorf("no rows updated for agent ID '%s' - possible concurrent modification or node not found", id)
 		} else {
-			return fmt.Errorf("agent node with ID '%s' not found", id)
+			return fmt.Errorf("%w: %s", ErrAgentNotFound, id)
 		}
 	}
 
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -13,7 +14,12 @@ import (
 )
 
 const (
-	locksBucket = "locks" //nolint:unused // Reserved for future use
+	locksBucket = "locks"
+	// lockIDsBucket maps lock IDs back to their keys so locks can be released and
+	// renewed by ID, mirroring the postgres lock_id column.
+	lockIDsBucket = "lock_ids"
+
+	defaultLockTTL = 30 * time.Second
 )
 
 // AcquireLock attempts to acquire a distributed lock.
@@ -26,11 +32,38 @@ func (ls *LocalStorage) AcquireLock(ctx context.Context, key string, timeout tim
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
+	if timeout <= 0 {
+		timeout = defaultLockTTL
+	}
 
 	var lock *types.DistributedLock
 	err := ls.kvStore.Update(func(tx *bolt.Tx) error {
-		// Implementation will be added here
-		return nil
+		locks, ids, err := lockBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		if existing, err := decodeLock(locks.Get([]byte(key))); err != nil {
+			return err
+		} else if existing != nil {
+			if existing.ExpiresAt.After(now) {
+				return fmt.Errorf("lock '%s' is already held", key)
+			}
+			if err := ids.Delete([]byte(existing.LockID)); err != nil {
+				return err
+			}
+		}
+
+		lockID := uuid.NewString()
+		lock = &types.DistributedLock{
+			LockID:    lockID,
+			Key:       key,
+			Holder:    lockID,
+			ExpiresAt: now.Add(timeout),
+			CreatedAt: now,
+		}
+		return putLock(locks, ids, lock)
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to acquire lock: %w", err)
@@ -50,8 +83,18 @@ func (ls *LocalStorage) ReleaseLock(ctx context.Context, lockID string) error {
 	}
 
 	return ls.kvStore.Update(func(tx *bolt.Tx) error {
-		// Implementation will be added here
-		return nil
+		locks, ids, err := lockBuckets(tx)
+		if err != nil {
+			return err
+		}
+		key := ids.Get([]byte(lockID))
+		if key == nil {
+			return fmt.Errorf("lock '%s' not found", lockID)
+		}
+		if err := locks.Delete(key); err != nil {
+			return err
+		}
+		return ids.Delete([]byte(lockID))
 	})
 }
 
@@ -68,8 +111,24 @@ func (ls *LocalStorage) RenewLock(ctx context.Context, lockID string) (*types.Di
 
 	var lock *types.DistributedLock
 	err := ls.kvStore.Update(func(tx *bolt.Tx) error {
-		// Implementation will be added here
-		return nil
+		locks, ids, err := lockBuckets(tx)
+		if err != nil {
+			return err
+		}
+		key := ids.Get([]byte(lockID))
+		if key == nil {
+			return fmt.Errorf("lock '%s' not found", lockID)
+		}
+		existing, err := decodeLock(locks.Get(key))
+		if err != nil {
+			return err
+		}
+		if existing == nil || existing.LockID != lockID {
+			return fmt.Errorf("lock '%s' not found", lockID)
+		}
+		existing.ExpiresAt = time.Now().UTC().Add(defaultLockTTL)
+		lock = existing
+		return putLock(locks, ids, lock)
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to renew lock: %w", err)
@@ -90,8 +149,13 @@ func (ls *LocalStorage) GetLockStatus(ctx context.Context, key string) (*types.D
 
 	var lock *types.DistributedLock
 	err := ls.kvStore.View(func(tx *bolt.Tx) error {
-		// Implementation will be added here
-		return nil
+		b := tx.Bucket([]byte(locksBucket))
+		if b == nil {
+			return nil
+		}
+		var err error
+		lock, err = decodeLock(b.Get([]byte(key)))
+		return err
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get lock status: %w", err)
@@ -99,9 +163,43 @@ func (ls *LocalStorage) GetLockStatus(ctx context.Context, key string) (*types.D
 	return lock, nil
 }
 
+func lockBuckets(tx *bolt.Tx) (*bolt.Bucket, *bolt.Bucket, error) {
+	locks, err := tx.CreateBucketIfNotExists([]byte(locksBucket))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create locks bucket: %w", err)
+	}
+	ids, err := tx.CreateBucketIfNotExists([]byte(lockIDsBucket))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create lock ids bucket: %w", err)
+	}
+	return locks, ids, nil
+}
+
+func decodeLock(raw []byte) (*types.DistributedLock, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	var lock types.DistributedLock
+	if err := json.Unmarshal(raw, &lock); err != nil {
+		return nil, fmt.Errorf("failed to decode lock: %w", err)
+	}
+	return &lock, nil
+}
+
+func putLock(locks, ids *bolt.Bucket, lock *types.DistributedLock) error {
+	encoded, err := json.Marshal(lock)
+	if err != nil {
+		return fmt.Errorf("failed to encode lock: %w", err)
+	}
+	if err := locks.Put([]byte(lock.Key), encoded); err != nil {
+		return err
+	}
+	return ids.Put([]byte(lock.LockID), []byte(lock.Key))
+}
+
 func (ls *LocalStorage) acquireLockPostgres(ctx context.Context, key string, timeout time.Duration) (*types.DistributedLock, error) {
 	if timeout <= 0 {
-		timeout = 30 * time.Second
+		timeout = defaultLockTTL
 	}
 
 	expiresAt := time.Now().UTC().Add(timeout)
@@ -160,7 +258,7 @@ func (ls *LocalStorage) releaseLockPostgres(ctx context.Context, lockID string)
 }
 
 func (ls *LocalStorage) renewLockPostgres(ctx context.Context, lockID string) (*types.DistributedLock, error) {
-	expiresAt := time.Now().UTC().Add(30 * time.Second)
+	expiresAt := time.Now().UTC().Add(defaultLockTTL)
 	query := `
         UPDATE distributed_locks
         SET expires_at = ?, updated_at = NOW()
@@ -544,3 +544,49 @@ func setupPostgresTestStorage(t *testing.T) (StorageProvider, context.Context) {
 	// In CI/CD, this would connect to a real PostgreSQL instance
 	return nil, context.Background()
 }
+
+func TestLocalLocks_AcquireRenewRelease(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+
+	lock, err := provider.AcquireLock(ctx, "local-lock", time.Second)
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	assert.Equal(t, "local-lock", lock.Key)
+
+	_, err = provider.AcquireLock(ctx, "local-lock", time.Second)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already held")
+
+	renewed, err := provider.RenewLock(ctx, lock.LockID)
+	require.NoError(t, err)
+	assert.True(t, renewed.ExpiresAt.After(lock.ExpiresAt))
+
+	status, err := provider.GetLockStatus(ctx, "local-lock")
+	require.NoError(t, err)
+	require.NotNil(t, status)
+	assert.Equal(t, lock.LockID, status.LockID)
+
+	require.NoError(t, provider.ReleaseLock(ctx, lock.LockID))
+	require.Error(t, provider.ReleaseLock(ctx, lock.LockID))
+	_, err = provider.RenewLock(ctx, lock.LockID)
+	require.Error(t, err)
+
+	status, err = provider.GetLockStatus(ctx, "local-lock")
+	require.NoError(t, err)
+	assert.Nil(t, status)
+}
+
+func TestLocalLocks_ExpiredLockCanBeReacquired(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+
+	first, err := provider.AcquireLock(ctx, "expiring-lock", 20*time.Millisecond)
+	require.NoError(t, err)
+	time.Sleep(40 * time.Millisecond)
+
+	second, err := provider.AcquireLock(ctx, "expiring-lock", time.Second)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.LockID, second.LockID)
+
+	// The superseded lock id no longer resolves.
+	require.Error(t, provider.ReleaseLock(ctx, first.LockID))
+}
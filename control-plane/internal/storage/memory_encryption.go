@@ -0,0 +1,256 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/encryption"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/boltdb/bolt"
+)
+
+// memoryEncryptionAlgorithm is recorded in the metadata of encrypted memory.
+const memoryEncryptionAlgorithm = "AES-256-GCM"
+
+// ErrMemoryEncryptionDisabled is returned when memory has to be encrypted or decrypted
+// but no memory keys are attached.
+var ErrMemoryEncryptionDisabled = errors.New("memory encryption is not enabled")
+
+// MemoryKeyProvider hands out the keys that encrypt memory values. The keystore service
+// implements it.
+type MemoryKeyProvider interface {
+	CurrentMemoryKey() (keyID string, key []byte, err error)
+	MemoryKey(keyID string) ([]byte, error)
+}
+
+// EnableMemoryEncryption attaches the keys memory values are encrypted with. Only the
+// data of a value is encrypted; its key, scope and metadata stay readable, and vectors,
+// whose metadata and text are searched, are not encrypted at all.
+func (ls *LocalStorage) EnableMemoryEncryption(keys MemoryKeyProvider) error {
+	for _, scope := range ls.memoryEncryption.Scopes {
+		if !slices.Contains(memoryScopes, scope) {
+			return fmt.Errorf("memory encryption scope '%s' is not one of %v", scope, memoryScopes)
+		}
+	}
+	ls.memoryKeys = keys
+	return nil
+}
+
+// encryptsScope reports whether every value in scope is encrypted.
+func (ls *LocalStorage) encryptsScope(scope string) bool {
+	return ls.memoryEncryption.Enabled && slices.Contains(ls.memoryEncryption.Scopes, scope)
+}
+
+// encodeMemory serializes next for storage. Its data is encrypted when its scope is
+// encrypted, when the write asks for it, or when the value it replaces was encrypted;
+// next keeps the plaintext and gains the encryption metadata.
+func (ls *LocalStorage) encodeMemory(next, current *types.Memory) ([]byte, error) {
+	if !next.Encrypted() && (current == nil || !current.Encrypted()) && !ls.encryptsScope(next.Scope) {
+		return json.Marshal(next)
+	}
+	if ls.memoryKeys == nil {
+		return nil, ErrMemoryEncryptionDisabled
+	}
+
+	keyID, key, err := ls.memoryKeys.CurrentMemoryKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load memory key: %w", err)
+	}
+	cipher, err := encryption.NewEncryptionServiceWithKey(key)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := cipher.Encrypt(string(next.Data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt memory '%s': %w", next.Key, err)
+	}
+
+	next.Metadata.Encryption = &types.EncryptionMetadata{
+		Encrypted: true,
+		KeyID:     keyID,
+		Algorithm: memoryEncryptionAlgorithm,
+	}
+	stored := *next
+	if stored.Data, err = json.Marshal(ciphertext); err != nil {
+		return nil, err
+	}
+	return json.Marshal(&stored)
+}
+
+// decodeMemory deserializes a stored value into memory, decrypting its data.
+func (ls *LocalStorage) decodeMemory(data []byte, memory *types.Memory) error {
+	if err := json.Unmarshal(data, memory); err != nil {
+		return err
+	}
+	return ls.decryptMemory(memory)
+}
+
+func (ls *LocalStorage) decryptMemory(memory *types.Memory) error {
+	if !memory.Encrypted() {
+		return nil
+	}
+	if ls.memoryKeys == nil {
+		return ErrMemoryEncryptionDisabled
+	}
+
+	key, err := ls.memoryKeys.MemoryKey(memory.Metadata.Encryption.KeyID)
+	if err != nil {
+		return fmt.Errorf("failed to load memory key: %w", err)
+	}
+	cipher, err := encryption.NewEncryptionServiceWithKey(key)
+	if err != nil {
+		return err
+	}
+	var ciphertext string
+	if err := json.Unmarshal(memory.Data, &ciphertext); err != nil {
+		return fmt.Errorf("encrypted memory '%s' holds no ciphertext: %w", memory.Key, err)
+	}
+	plaintext, err := cipher.Decrypt(ciphertext)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt memory '%s': %w", memory.Key, err)
+	}
+	memory.Data = json.RawMessage(plaintext)
+	return nil
+}
+
+// ReencryptMemory brings stored values in line with the current key and scopes: values
+// encrypted with an older key are encrypted with the current one, and plaintext values
+// in encrypted scopes are encrypted. Versions and timestamps are kept, since the values
+// themselves do not change. It returns the number of values rewritten.
+func (ls *LocalStorage) ReencryptMemory(ctx context.Context) (int, error) {
+	if ls.memoryKeys == nil {
+		return 0, ErrMemoryEncryptionDisabled
+	}
+	keyID, _, err := ls.memoryKeys.CurrentMemoryKey()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load memory key: %w", err)
+	}
+	if ls.mode == "postgres" {
+		return ls.reencryptMemoryPostgres(ctx, keyID)
+	}
+
+	count := 0
+	for _, scope := range memoryScopes {
+		if err := ctx.Err(); err != nil {
+			return count, fmt.Errorf("context cancelled during memory re-encryption: %w", err)
+		}
+
+		var rewritten []string
+		err := ls.kvStore.Update(func(tx *bolt.Tx) error {
+			bucket := tx.Bucket([]byte(scope))
+			if bucket == nil {
+				return nil
+			}
+
+			// BoltDB cursors must not see writes, so the values are put after the scan.
+			updates := map[string][]byte{}
+			c := bucket.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				data, err := ls.reencryptedMemory(v, keyID)
+				if err != nil {
+					return fmt.Errorf("failed to re-encrypt memory '%s' in scope '%s': %w", k, scope, err)
+				}
+				if data != nil {
+					updates[string(k)] = data
+				}
+			}
+			for k, data := range updates {
+				if err := bucket.Put([]byte(k), data); err != nil {
+					return fmt.Errorf("failed to put memory in BoltDB: %w", err)
+				}
+				rewritten = append(rewritten, k)
+			}
+			return nil
+		})
+		if err != nil {
+			return count, err
+		}
+
+		for _, k := range rewritten {
+			ls.cache.Delete(scope + ":" + k)
+		}
+		count += len(rewritten)
+	}
+	return count, nil
+}
+
+// storedMemory is a kv_store row as read for re-encryption.
+type storedMemory struct {
+	key     snapshotKey
+	payload []byte
+}
+
+func (ls *LocalStorage) reencryptMemoryPostgres(ctx context.Context, keyID string) (int, error) {
+	count := 0
+	err := exportPages(func(after *snapshotKey) ([]storedMemory, error) {
+		query, args := snapshotPageQuery("scope, scope_id, key, value", "kv_store", types.MemorySnapshotFilter{}, after)
+		rows, err := ls.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load memory from postgres: %w", err)
+		}
+		defer rows.Close()
+
+		var page []storedMemory
+		for rows.Next() {
+			var row storedMemory
+			if err := rows.Scan(&row.key.scope, &row.key.scopeID, &row.key.key, &row.payload); err != nil {
+				return nil, fmt.Errorf("failed to scan memory row: %w", err)
+			}
+			page = append(page, row)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error iterating memory rows: %w", err)
+		}
+		return page, nil
+	}, func(row storedMemory) snapshotKey {
+		return row.key
+	}, func(row storedMemory) error {
+		data, err := ls.reencryptedMemory(row.payload, keyID)
+		if err != nil {
+			return fmt.Errorf("failed to re-encrypt memory '%s' in scope '%s': %w", row.key.key, row.key.scope, err)
+		}
+		if data == nil {
+			return nil
+		}
+
+		// A row written since it was read is left alone; that write used a current key.
+		res, err := ls.db.ExecContext(ctx, `
+			UPDATE kv_store SET value = ?
+			WHERE scope = ? AND scope_id = ? AND key = ? AND value = ?`,
+			data, row.key.scope, row.key.scopeID, row.key.key, row.payload)
+		if err != nil {
+			return fmt.Errorf("failed to update memory in postgres: %w", err)
+		}
+		if n, err := res.RowsAffected(); err == nil && n > 0 {
+			ls.cache.Delete(fmt.Sprintf("%s:%s:%s", row.key.scope, row.key.scopeID, row.key.key))
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// reencryptedMemory returns the new encoding of a stored value, or nil when the value
+// is already encrypted with keyID or does not need encrypting.
+func (ls *LocalStorage) reencryptedMemory(data []byte, keyID string) ([]byte, error) {
+	memory := &types.Memory{}
+	if err := json.Unmarshal(data, memory); err != nil {
+		return nil, err
+	}
+	if memory.Encrypted() {
+		if memory.Metadata.Encryption.KeyID == keyID {
+			return nil, nil
+		}
+	} else if !ls.encryptsScope(memory.Scope) {
+		return nil, nil
+	}
+
+	if err := ls.decryptMemory(memory); err != nil {
+		return nil, err
+	}
+	return ls.encodeMemory(memory, nil)
+}
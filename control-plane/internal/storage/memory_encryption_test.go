@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+	"github.com/boltdb/bolt"
+	"github.com/stretchr/testify/require"
+)
+
+// testMemoryKeys is an in-memory MemoryKeyProvider.
+type testMemoryKeys struct {
+	keys    map[string][]byte
+	current string
+}
+
+func (k *testMemoryKeys) rotate() string {
+	id := fmt.Sprintf("memory-%d", len(k.keys)+1)
+	k.keys[id] = bytes.Repeat([]byte{byte(len(k.keys) + 1)}, 32)
+	k.current = id
+	return id
+}
+
+func (k *testMemoryKeys) CurrentMemoryKey() (string, []byte, error) {
+	return k.current, k.keys[k.current], nil
+}
+
+func (k *testMemoryKeys) MemoryKey(keyID string) ([]byte, error) {
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %s", keyID)
+	}
+	return key, nil
+}
+
+// storedMemoryValue reads a value from BoltDB as stored, bypassing decryption.
+func storedMemoryValue(t *testing.T, ls *LocalStorage, scope, scopeID, key string) ([]byte, *types.Memory) {
+	t.Helper()
+	var raw []byte
+	require.NoError(t, ls.kvStore.View(func(tx *bolt.Tx) error {
+		raw = append([]byte(nil), tx.Bucket([]byte(scope)).Get([]byte(scopeID+":"+key))...)
+		return nil
+	}))
+	stored := &types.Memory{}
+	require.NoError(t, json.Unmarshal(raw, stored))
+	return raw, stored
+}
+
+func TestLocalStorage_MemoryEncryption(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+	ls := provider.(*LocalStorage)
+	ls.memoryEncryption = MemoryEncryptionConfig{Enabled: true, Scopes: []string{"global"}}
+
+	encrypted := &types.Memory{Scope: "session", ScopeID: "s1", Key: "secret", Data: json.RawMessage(`"card 4111"`)}
+	encrypted.Metadata.Encryption = &types.EncryptionMetadata{Encrypted: true}
+	require.ErrorIs(t, ls.SetMemory(ctx, encrypted), ErrMemoryEncryptionDisabled)
+
+	keys := &testMemoryKeys{keys: map[string][]byte{}}
+	firstKey := keys.rotate()
+	require.NoError(t, ls.EnableMemoryEncryption(keys))
+
+	require.NoError(t, ls.SetMemory(ctx, &types.Memory{Scope: "global", ScopeID: "global", Key: "email", Data: json.RawMessage(`"ada@example.com"`)}))
+	require.NoError(t, ls.SetMemory(ctx, encrypted))
+	require.NoError(t, ls.SetMemory(ctx, &types.Memory{Scope: "session", ScopeID: "s1", Key: "plain", Data: json.RawMessage(`1`)}))
+
+	raw, stored := storedMemoryValue(t, ls, "global", "global", "email")
+	require.NotContains(t, string(raw), "ada@example.com")
+	require.Equal(t, &types.EncryptionMetadata{Encrypted: true, KeyID: firstKey, Algorithm: "AES-256-GCM"}, stored.Metadata.Encryption)
+	raw, _ = storedMemoryValue(t, ls, "session", "s1", "secret")
+	require.NotContains(t, string(raw), "4111")
+	_, stored = storedMemoryValue(t, ls, "session", "s1", "plain")
+	require.False(t, stored.Encrypted())
+
+	// Reads decrypt, from BoltDB as well as from the cache.
+	ls.cache.Delete("global:global:email")
+	memory, err := ls.GetMemory(ctx, "global", "global", "email")
+	require.NoError(t, err)
+	require.JSONEq(t, `"ada@example.com"`, string(memory.Data))
+	require.True(t, memory.Encrypted())
+	memories, err := ls.ListMemory(ctx, "session", "s1")
+	require.NoError(t, err)
+	require.Len(t, memories, 2)
+
+	// A value once encrypted stays encrypted when rewritten without asking.
+	require.NoError(t, ls.SetMemory(ctx, &types.Memory{Scope: "session", ScopeID: "s1", Key: "secret", Data: json.RawMessage(`"card 5500"`)}))
+	raw, stored = storedMemoryValue(t, ls, "session", "s1", "secret")
+	require.NotContains(t, string(raw), "5500")
+	require.True(t, stored.Encrypted())
+
+	// After a rotation, re-encryption moves every value to the new key without a new
+	// version, and also encrypts values of scopes that became encrypted.
+	require.NoError(t, ls.SetMemory(ctx, &types.Memory{Scope: "actor", ScopeID: "a1", Key: "phone", Data: json.RawMessage(`"555-0100"`)}))
+	ls.memoryEncryption.Scopes = append(ls.memoryEncryption.Scopes, "actor")
+	secondKey := keys.rotate()
+	_, before := storedMemoryValue(t, ls, "global", "global", "email")
+
+	count, err := ls.ReencryptMemory(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+	count, err = ls.ReencryptMemory(ctx)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	_, after := storedMemoryValue(t, ls, "global", "global", "email")
+	require.Equal(t, secondKey, after.Metadata.Encryption.KeyID)
+	require.Equal(t, before.Version, after.Version)
+	raw, stored = storedMemoryValue(t, ls, "actor", "a1", "phone")
+	require.NotContains(t, string(raw), "555-0100")
+	require.Equal(t, secondKey, stored.Metadata.Encryption.KeyID)
+
+	memory, err = ls.GetMemory(ctx, "actor", "a1", "phone")
+	require.NoError(t, err)
+	require.JSONEq(t, `"555-0100"`, string(memory.Data))
+
+	var exported []*types.Memory
+	require.NoError(t, ls.ExportMemory(ctx, types.MemorySnapshotFilter{Scope: "global"}, func(memory *types.Memory) error {
+		exported = append(exported, memory)
+		return nil
+	}))
+	require.Len(t, exported, 1)
+	require.JSONEq(t, `"ada@example.com"`, string(exported[0].Data))
+}
+
+func TestLocalStorage_EnableMemoryEncryptionRejectsUnknownScope(t *testing.T) {
+	provider, _ := setupTestStorage(t)
+	ls := provider.(*LocalStorage)
+	ls.memoryEncryption = MemoryEncryptionConfig{Enabled: true, Scopes: []string{"agents"}}
+
+	require.Error(t, ls.EnableMemoryEncryption(&testMemoryKeys{keys: map[string][]byte{}}))
+}
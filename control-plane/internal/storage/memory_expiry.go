@@ -0,0 +1,226 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	// memoryExpirySweepInterval is how often expired memory is purged.
+	memoryExpirySweepInterval = 30 * time.Second
+	// memoryExpiryDeleteBatch caps the keys deleted per BoltDB write transaction.
+	memoryExpiryDeleteBatch = 256
+)
+
+// memoryScopes are the memory scopes, one BoltDB bucket each in local mode.
+var memoryScopes = []string{"workflow", "session", "actor", "reasoner", "global"}
+
+// applyMemoryTTL stamps the expiry of memory from its TTL. A write carrying an expiry
+// keeps it; otherwise a TTL starts over from now, and writes with neither never expire.
+func applyMemoryTTL(memory *types.Memory, now time.Time) {
+	if memory.ExpiresAt != nil {
+		return
+	}
+	if memory.TTL != nil && *memory.TTL > 0 {
+		expiresAt := now.Add(*memory.TTL)
+		memory.ExpiresAt = &expiresAt
+	}
+}
+
+// prepareMemoryWrite fills in the bookkeeping fields of next, which replaces current
+// (nil for a new key).
+func prepareMemoryWrite(next, current *types.Memory, scope, scopeID, key string, now time.Time) {
+	next.Scope, next.ScopeID, next.Key = scope, scopeID, key
+	next.Version = 1
+	if current != nil {
+		next.Version = current.Version + 1
+		next.CreatedAt = current.CreatedAt
+	}
+	if next.CreatedAt.IsZero() {
+		next.CreatedAt = now
+	}
+	next.UpdatedAt = now
+	applyMemoryTTL(next, now)
+}
+
+// unexpiredMemory returns memory, or the not found error when its TTL has elapsed.
+func (ls *LocalStorage) unexpiredMemory(cacheKey string, memory *types.Memory) (*types.Memory, error) {
+	if memory.Expired(time.Now()) {
+		ls.cache.Delete(cacheKey)
+		return nil, fmt.Errorf("memory with key '%s' not found in scope '%s' for ID '%s'", memory.Key, memory.Scope, memory.ScopeID)
+	}
+	return memory, nil
+}
+
+func (ls *LocalStorage) startMemoryExpiry() {
+	ls.memoryExpiryStop = make(chan struct{})
+	ls.memoryExpiryDone = make(chan struct{})
+	go ls.memoryExpiryLoop(ls.memoryExpiryStop, ls.memoryExpiryDone)
+}
+
+func (ls *LocalStorage) stopMemoryExpiry() {
+	if ls.memoryExpiryStop == nil {
+		return
+	}
+	close(ls.memoryExpiryStop)
+	<-ls.memoryExpiryDone
+	ls.memoryExpiryStop = nil
+}
+
+func (ls *LocalStorage) memoryExpiryLoop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(memoryExpirySweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, err := ls.expireMemory(context.Background(), time.Now().UTC()); err != nil {
+				logger.Logger.Warn().Err(err).Msg("failed to purge expired memory")
+			}
+		}
+	}
+}
+
+// expireMemory purges the memory whose TTL elapsed at or before now and emits an
+// expire event for each key. In postgres mode the delete decides which replica reports
+// a key, so every replica may sweep.
+func (ls *LocalStorage) expireMemory(ctx context.Context, now time.Time) (int, error) {
+	var (
+		expired []*types.Memory
+		err     error
+	)
+	if ls.mode == "postgres" {
+		expired, err = ls.purgeExpiredMemoryPostgres(ctx, now)
+	} else {
+		expired, err = ls.purgeExpiredMemoryBolt(now)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	for _, memory := range expired {
+		ls.cache.Delete(fmt.Sprintf("%s:%s:%s", memory.Scope, memory.ScopeID, memory.Key))
+
+		event := &types.MemoryChangeEvent{
+			Type:         "memory_change",
+			Scope:        memory.Scope,
+			ScopeID:      memory.ScopeID,
+			Key:          memory.Key,
+			Action:       "expire",
+			PreviousData: memory.Data,
+		}
+		if memory.Protected() {
+			// The history and change streams carry no data of encrypted or
+			// access-controlled memory, as for any other write.
+			event.PreviousData = nil
+		}
+		if err := ls.StoreEvent(ctx, event); err != nil {
+			logger.Logger.Warn().Err(err).Str("key", memory.Key).Msg("failed to store memory expire event")
+			continue
+		}
+		ls.publishMemoryChange(*event)
+	}
+	return len(expired), nil
+}
+
+// purgeExpiredMemoryBolt finds expired memory in a read transaction, so that sweeps
+// do not hold the BoltDB write lock while scanning every bucket, and deletes it in
+// batches. A key rewritten since the scan is checked again before its delete.
+func (ls *LocalStorage) purgeExpiredMemoryBolt(now time.Time) ([]*types.Memory, error) {
+	if ls.kvStore == nil {
+		return nil, nil
+	}
+
+	type expiredKey struct {
+		scope string
+		key   []byte
+	}
+	var candidates []expiredKey
+	err := ls.kvStore.View(func(tx *bolt.Tx) error {
+		for _, scope := range memoryScopes {
+			bucket := tx.Bucket([]byte(scope))
+			if bucket == nil {
+				continue
+			}
+			err := bucket.ForEach(func(k, v []byte) error {
+				memory := &types.Memory{}
+				if err := json.Unmarshal(v, memory); err == nil && memory.Expired(now) {
+					candidates = append(candidates, expiredKey{scope: scope, key: append([]byte(nil), k...)})
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan BoltDB for expired memory: %w", err)
+	}
+
+	var expired []*types.Memory
+	for start := 0; start < len(candidates); start += memoryExpiryDeleteBatch {
+		batch := candidates[start:min(start+memoryExpiryDeleteBatch, len(candidates))]
+		err := ls.kvStore.Update(func(tx *bolt.Tx) error {
+			for _, candidate := range batch {
+				bucket := tx.Bucket([]byte(candidate.scope))
+				if bucket == nil {
+					continue
+				}
+				memory := &types.Memory{}
+				if err := json.Unmarshal(bucket.Get(candidate.key), memory); err != nil || !memory.Expired(now) {
+					continue
+				}
+				if err := bucket.Delete(candidate.key); err != nil {
+					return fmt.Errorf("failed to delete expired memory from BoltDB: %w", err)
+				}
+				expired = append(expired, memory)
+			}
+			return nil
+		})
+		if err != nil {
+			return expired, err
+		}
+	}
+	return expired, nil
+}
+
+func (ls *LocalStorage) purgeExpiredMemoryPostgres(ctx context.Context, now time.Time) ([]*types.Memory, error) {
+	rows, err := ls.db.QueryContext(ctx, `
+		DELETE FROM kv_store
+		WHERE expires_at IS NOT NULL AND expires_at <= ?
+		RETURNING value`, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge expired memory from postgres: %w", err)
+	}
+	defer rows.Close()
+
+	var expired []*types.Memory
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			return nil, fmt.Errorf("failed to scan expired memory payload: %w", err)
+		}
+		memory := &types.Memory{}
+		if err := json.Unmarshal(payload, memory); err != nil {
+			continue
+		}
+		expired = append(expired, memory)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating expired memory rows: %w", err)
+	}
+	return expired, nil
+}
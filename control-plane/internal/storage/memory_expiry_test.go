@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+	"github.com/boltdb/bolt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryTTL_ExpiredKeysReadAsMissingAndArePurged(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+	ls := provider.(*LocalStorage)
+
+	ttl := time.Minute
+	require.NoError(t, ls.SetMemory(ctx, &types.Memory{Scope: "session", ScopeID: "s-1", Key: "summary", Data: json.RawMessage(`"later"`), TTL: &ttl}))
+	summary, err := ls.GetMemory(ctx, "session", "s-1", "summary")
+	require.NoError(t, err)
+	require.NotNil(t, summary.ExpiresAt)
+	require.WithinDuration(t, time.Now().Add(ttl), *summary.ExpiresAt, 5*time.Second)
+
+	short := time.Millisecond
+	require.NoError(t, ls.SetMemory(ctx, &types.Memory{Scope: "session", ScopeID: "s-1", Key: "scratch", Data: json.RawMessage(`"draft"`), TTL: &short}))
+	require.NoError(t, ls.SetMemory(ctx, &types.Memory{Scope: "session", ScopeID: "s-1", Key: "profile", Data: json.RawMessage(`"kept"`)}))
+	time.Sleep(5 * time.Millisecond)
+
+	changes, err := ls.SubscribeToMemoryChanges(ctx, "session", "s-1")
+	require.NoError(t, err)
+
+	_, err = ls.GetMemory(ctx, "session", "s-1", "scratch")
+	require.Error(t, err, "expired memory reads as missing before the sweep")
+	memories, err := ls.ListMemory(ctx, "session", "s-1")
+	require.NoError(t, err)
+	require.Len(t, memories, 2)
+
+	purged, err := ls.expireMemory(ctx, time.Now().UTC())
+	require.NoError(t, err)
+	require.Equal(t, 1, purged)
+
+	select {
+	case event := <-changes:
+		require.Equal(t, "expire", event.Action)
+		require.Equal(t, "scratch", event.Key)
+		require.JSONEq(t, `"draft"`, string(event.PreviousData))
+	case <-time.After(time.Second):
+		t.Fatal("expected an expire event")
+	}
+
+	history, err := ls.GetEventHistory(ctx, types.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, "expire", history[0].Action)
+
+	purged, err = ls.expireMemory(ctx, time.Now().Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, purged, "only the minute-long TTL is left to expire")
+	_, err = ls.GetMemory(ctx, "session", "s-1", "profile")
+	require.NoError(t, err, "memory without a TTL never expires")
+}
+
+func TestMemoryTTL_PurgesEveryScopeInBatches(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+	ls := provider.(*LocalStorage)
+
+	ttl := time.Millisecond
+	for i := 0; i <= memoryExpiryDeleteBatch; i++ {
+		scope := memoryScopes[i%2]
+		key := fmt.Sprintf("scratch-%d", i)
+		require.NoError(t, ls.SetMemory(ctx, &types.Memory{Scope: scope, ScopeID: "id-1", Key: key, Data: json.RawMessage(`1`), TTL: &ttl}))
+	}
+	require.NoError(t, ls.SetMemory(ctx, &types.Memory{Scope: "global", ScopeID: "global", Key: "kept", Data: json.RawMessage(`1`)}))
+	time.Sleep(5 * time.Millisecond)
+
+	purged, err := ls.expireMemory(ctx, time.Now().UTC())
+	require.NoError(t, err)
+	require.Equal(t, memoryExpiryDeleteBatch+1, purged)
+
+	require.NoError(t, ls.kvStore.View(func(tx *bolt.Tx) error {
+		for _, scope := range memoryScopes[:2] {
+			require.Zero(t, tx.Bucket([]byte(scope)).Stats().KeyN, "expired keys are deleted from %s", scope)
+		}
+		return nil
+	}))
+	_, err = ls.GetMemory(ctx, "global", "global", "kept")
+	require.NoError(t, err)
+}
+
+func TestMemoryTTL_ExpireEventsOmitProtectedData(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+	ls := provider.(*LocalStorage)
+
+	ttl := time.Millisecond
+	require.NoError(t, ls.SetMemory(ctx, &types.Memory{
+		Scope: "session", ScopeID: "s-1", Key: "secret", Data: json.RawMessage(`"token"`), TTL: &ttl,
+		AccessLevel: types.MemoryAccessPrivate,
+	}))
+	restricted := &types.Memory{Scope: "session", ScopeID: "s-1", Key: "roles", Data: json.RawMessage(`"ops only"`), TTL: &ttl}
+	restricted.Metadata.AccessControl = &types.AccessControlMetadata{RequiredRoles: []string{"ops"}}
+	require.NoError(t, ls.SetMemory(ctx, restricted))
+	time.Sleep(5 * time.Millisecond)
+
+	changes, err := ls.SubscribeToMemoryChanges(ctx, "session", "s-1")
+	require.NoError(t, err)
+
+	purged, err := ls.expireMemory(ctx, time.Now().UTC())
+	require.NoError(t, err)
+	require.Equal(t, 2, purged)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case event := <-changes:
+			require.Equal(t, "expire", event.Action)
+			require.Empty(t, event.PreviousData, event.Key)
+		case <-time.After(time.Second):
+			t.Fatal("expected an expire event")
+		}
+	}
+
+	history, err := ls.GetEventHistory(ctx, types.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	for _, event := range history {
+		require.Empty(t, event.PreviousData, event.Key)
+		require.Empty(t, event.Data, event.Key)
+	}
+}
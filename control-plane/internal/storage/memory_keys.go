@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// StoreMemoryKey records a wrapped memory encryption key. Keys are never replaced, so
+// storing a key ID that already exists keeps the first key.
+func (ls *LocalStorage) StoreMemoryKey(ctx context.Context, keyID string, wrapped []byte) error {
+	_, err := ls.requireSQLDB().ExecContext(ctx, `
+		INSERT INTO memory_keys (key_id, wrapped_key, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(key_id) DO NOTHING`,
+		keyID,
+		wrapped,
+		time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("store memory key: %w", err)
+	}
+	return nil
+}
+
+// GetMemoryKey returns the wrapped memory encryption key with the given ID.
+func (ls *LocalStorage) GetMemoryKey(ctx context.Context, keyID string) ([]byte, error) {
+	var wrapped []byte
+	err := ls.requireSQLDB().QueryRowContext(ctx, `
+		SELECT wrapped_key FROM memory_keys WHERE key_id = ?`, keyID).Scan(&wrapped)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("memory key '%s' not found", keyID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get memory key: %w", err)
+	}
+	return wrapped, nil
+}
+
+// ListMemoryKeyIDs returns the IDs of the stored memory encryption keys in ascending
+// order.
+func (ls *LocalStorage) ListMemoryKeyIDs(ctx context.Context) ([]string, error) {
+	rows, err := ls.requireSQLDB().QueryContext(ctx, `
+		SELECT key_id FROM memory_keys ORDER BY key_id ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("list memory keys: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan memory key: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryKeys_StoreGetAndList(t *testing.T) {
+	ls, ctx := setupLocalStorage(t)
+
+	ids, err := ls.ListMemoryKeyIDs(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+
+	require.NoError(t, ls.StoreMemoryKey(ctx, "memory-2", []byte("wrapped-2")))
+	require.NoError(t, ls.StoreMemoryKey(ctx, "memory-1", []byte("wrapped-1")))
+	// Keys are never replaced.
+	require.NoError(t, ls.StoreMemoryKey(ctx, "memory-1", []byte("other")))
+
+	ids, err = ls.ListMemoryKeyIDs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"memory-1", "memory-2"}, ids)
+
+	wrapped, err := ls.GetMemoryKey(ctx, "memory-1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("wrapped-1"), wrapped)
+
+	_, err = ls.GetMemoryKey(ctx, "memory-3")
+	require.Error(t, err)
+}
@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+
+	"github.com/boltdb/bolt"
+)
+
+// snapshotPageSize is how many rows an export reads per query, so a long export does not
+// hold a database connection while its output is written.
+const snapshotPageSize = 500
+
+// snapshotKey is the position of an export in key order.
+type snapshotKey struct {
+	scope   string
+	scopeID string
+	key     string
+}
+
+// ExportMemory passes every unexpired key-value entry selected by filter to emit.
+func (ls *LocalStorage) ExportMemory(ctx context.Context, filter types.MemorySnapshotFilter, emit func(*types.Memory) error) error {
+	if ls.mode == "postgres" {
+		return ls.exportMemoryPostgres(ctx, filter, emit)
+	}
+
+	scopes := memoryScopes
+	if filter.Scope != "" {
+		// Other buckets, such as the events, do not hold memory.
+		if !slices.Contains(memoryScopes, filter.Scope) {
+			return nil
+		}
+		scopes = []string{filter.Scope}
+	}
+	now := time.Now()
+	for _, scope := range scopes {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("context cancelled during memory export: %w", err)
+		}
+
+		// Each scope is read in one transaction and emitted after it ends, so a slow
+		// reader of the export never holds BoltDB open.
+		var memories []*types.Memory
+		err := ls.kvStore.View(func(tx *bolt.Tx) error {
+			bucket := tx.Bucket([]byte(scope))
+			if bucket == nil {
+				return nil
+			}
+
+			var prefix []byte
+			if filter.ScopeID != "" {
+				prefix = []byte(filter.ScopeID + ":" + filter.KeyPrefix)
+			}
+			c := bucket.Cursor()
+			for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+				memory := &types.Memory{}
+				if err := ls.decodeMemory(v, memory); err != nil {
+					return fmt.Errorf("failed to unmarshal memory from BoltDB: %w", err)
+				}
+				if memory.Expired(now) || !filter.Matches(scope, memory.ScopeID, memory.Key) {
+					continue
+				}
+				memories = append(memories, memory)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, memory := range memories {
+			if err := emit(memory); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (ls *LocalStorage) exportMemoryPostgres(ctx context.Context, filter types.MemorySnapshotFilter, emit func(*types.Memory) error) error {
+	now := time.Now()
+	return exportPages(func(after *snapshotKey) ([]*types.Memory, error) {
+		query, args := snapshotPageQuery("scope, scope_id, key, value", "kv_store", filter, after)
+		rows, err := ls.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export memory from postgres: %w", err)
+		}
+		defer rows.Close()
+
+		var page []*types.Memory
+		for rows.Next() {
+			var key snapshotKey
+			var payload []byte
+			if err := rows.Scan(&key.scope, &key.scopeID, &key.key, &payload); err != nil {
+				return nil, fmt.Errorf("failed to scan memory row: %w", err)
+			}
+			memory := &types.Memory{}
+			if err := ls.decodeMemory(payload, memory); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal memory payload: %w", err)
+			}
+			// The columns, not the payload, order the pages.
+			memory.Scope, memory.ScopeID, memory.Key = key.scope, key.scopeID, key.key
+			page = append(page, memory)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error iterating memory rows: %w", err)
+		}
+		return page, nil
+	}, func(memory *types.Memory) snapshotKey {
+		return snapshotKey{memory.Scope, memory.ScopeID, memory.Key}
+	}, func(memory *types.Memory) error {
+		if memory.Expired(now) {
+			return nil
+		}
+		return emit(memory)
+	})
+}
+
+// ExportVectors passes every vector selected by filter to emit. Without a vector store
+// there is nothing to export.
+func (ls *LocalStorage) ExportVectors(ctx context.Context, filter types.MemorySnapshotFilter, emit func(*types.VectorRecord) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !ls.vectorConfig.isEnabled() {
+		return nil
+	}
+	if err := ls.requireVectorStore(); err != nil {
+		return err
+	}
+	return ls.vectorStore.Export(ctx, filter, emit)
+}
+
+// exportPages emits the pages returned by page, each starting after the last record
+// of the one before, until a page comes back short.
+func exportPages[T any](page func(after *snapshotKey) ([]T, error), keyOf func(T) snapshotKey, emit func(T) error) error {
+	var after *snapshotKey
+	for {
+		records, err := page(after)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := emit(record); err != nil {
+				return err
+			}
+		}
+		if len(records) < snapshotPageSize {
+			return nil
+		}
+		last := keyOf(records[len(records)-1])
+		after = &last
+	}
+}
+
+// snapshotPageQuery selects the next page of the rows of table that filter matches, in
+// key order. It works unchanged on SQLite and PostgreSQL.
+func snapshotPageQuery(columns, table string, filter types.MemorySnapshotFilter, after *snapshotKey) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if filter.Scope != "" {
+		conditions = append(conditions, "scope = ?")
+		args = append(args, filter.Scope)
+	}
+	if filter.ScopeID != "" {
+		conditions = append(conditions, "scope_id = ?")
+		args = append(args, filter.ScopeID)
+	}
+	if filter.KeyPrefix != "" {
+		// substr rather than LIKE, which would need the prefix's wildcards escaped.
+		conditions = append(conditions, "substr(key, 1, ?) = ?")
+		args = append(args, utf8.RuneCountInString(filter.KeyPrefix), filter.KeyPrefix)
+	}
+	if after != nil {
+		conditions = append(conditions, "(scope, scope_id, key) > (?, ?, ?)")
+		args = append(args, after.scope, after.scopeID, after.key)
+	}
+
+	query := "SELECT " + columns + " FROM " + table
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY scope, scope_id, key LIMIT %d", snapshotPageSize)
+	return query, args
+}
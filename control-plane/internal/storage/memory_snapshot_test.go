@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage_ExportMemory(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+	ls := provider.(*LocalStorage)
+
+	expired := time.Nanosecond
+	for _, memory := range []*types.Memory{
+		{Scope: "session", ScopeID: "s1", Key: "plan:a", Data: json.RawMessage(`1`)},
+		{Scope: "session", ScopeID: "s1", Key: "plan:b", Data: json.RawMessage(`2`)},
+		{Scope: "session", ScopeID: "s1", Key: "notes", Data: json.RawMessage(`3`)},
+		{Scope: "session", ScopeID: "s10", Key: "plan:c", Data: json.RawMessage(`4`)},
+		{Scope: "session", ScopeID: "s1", Key: "plan:gone", Data: json.RawMessage(`5`), TTL: &expired},
+		{Scope: "global", ScopeID: "global", Key: "plan:d", Data: json.RawMessage(`6`)},
+	} {
+		require.NoError(t, ls.SetMemory(ctx, memory))
+	}
+
+	export := func(filter types.MemorySnapshotFilter) []string {
+		var keys []string
+		require.NoError(t, ls.ExportMemory(ctx, filter, func(memory *types.Memory) error {
+			keys = append(keys, memory.Scope+"/"+memory.ScopeID+"/"+memory.Key)
+			return nil
+		}))
+		return keys
+	}
+
+	require.Equal(t, []string{"session/s1/plan:a", "session/s1/plan:b"},
+		export(types.MemorySnapshotFilter{Scope: "session", ScopeID: "s1", KeyPrefix: "plan:"}))
+	require.ElementsMatch(t, []string{"session/s1/plan:a", "session/s1/plan:b", "session/s10/plan:c", "global/global/plan:d"},
+		export(types.MemorySnapshotFilter{KeyPrefix: "plan:"}))
+	require.Len(t, export(types.MemorySnapshotFilter{}), 5)
+	require.Empty(t, export(types.MemorySnapshotFilter{Scope: "events"}))
+}
+
+func TestLocalStorage_ExportVectors(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+	ls := provider.(*LocalStorage)
+
+	policy := &types.AccessControlMetadata{AuditAccess: true}
+	require.NoError(t, ls.SetVector(ctx, &types.VectorRecord{
+		Scope: "session", ScopeID: "s1", Key: "doc_1", Embedding: []float32{0.5, 1},
+		Metadata: map[string]interface{}{"source": "docs"}, Text: "first", AccessControl: policy,
+	}))
+	require.NoError(t, ls.SetVector(ctx, &types.VectorRecord{Scope: "session", ScopeID: "s1", Key: "docx", Embedding: []float32{1, 0}}))
+	require.NoError(t, ls.SetVector(ctx, &types.VectorRecord{Scope: "session", ScopeID: "s2", Key: "doc_2", Embedding: []float32{0, 1}}))
+
+	var records []*types.VectorRecord
+	require.NoError(t, ls.ExportVectors(ctx, types.MemorySnapshotFilter{ScopeID: "s1", KeyPrefix: "doc_"}, func(record *types.VectorRecord) error {
+		records = append(records, record)
+		return nil
+	}))
+	require.Len(t, records, 1, "the prefix is matched literally, not as a LIKE pattern")
+	require.Equal(t, "doc_1", records[0].Key)
+	require.Equal(t, []float32{0.5, 1}, records[0].Embedding)
+	require.Equal(t, "docs", records[0].Metadata["source"])
+	require.Equal(t, "first", records[0].Text)
+	require.Equal(t, policy, records[0].AccessControl)
+	require.False(t, records[0].CreatedAt.IsZero())
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateMemory_VersionsAndSerializesWrites(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+
+	memory := &types.Memory{Scope: "workflow", ScopeID: "wf-1", Key: "plan", Data: json.RawMessage(`"v1"`)}
+	require.NoError(t, provider.SetMemory(ctx, memory))
+	require.Equal(t, int64(1), memory.Version)
+	createdAt := memory.CreatedAt
+
+	require.NoError(t, provider.SetMemory(ctx, &types.Memory{Scope: "workflow", ScopeID: "wf-1", Key: "plan", Data: json.RawMessage(`"v2"`)}))
+	stored, err := provider.GetMemory(ctx, "workflow", "wf-1", "plan")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), stored.Version)
+	require.True(t, stored.CreatedAt.Equal(createdAt), "rewrites keep the creation time")
+
+	rejected := errors.New("rejected")
+	_, err = provider.UpdateMemory(ctx, "workflow", "wf-1", "plan", func(current *types.Memory) (*types.Memory, error) {
+		return nil, rejected
+	})
+	require.ErrorIs(t, err, rejected)
+
+	unchanged, err := provider.UpdateMemory(ctx, "workflow", "wf-1", "plan", func(current *types.Memory) (*types.Memory, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), unchanged.Version)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := provider.UpdateMemory(ctx, "workflow", "wf-1", "counter", func(current *types.Memory) (*types.Memory, error) {
+				count := 0
+				if current != nil {
+					require.NoError(t, json.Unmarshal(current.Data, &count))
+				}
+				data, _ := json.Marshal(count + 1)
+				return &types.Memory{Data: data}, nil
+			})
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	counter, err := provider.GetMemory(ctx, "workflow", "wf-1", "counter")
+	require.NoError(t, err)
+	require.JSONEq(t, `20`, string(counter.Data))
+	require.Equal(t, int64(20), counter.Version)
+}
@@ -43,6 +43,15 @@ func (ls *LocalStorage) autoMigrateSchema(ctx context.Context) error {
 		&SchemaMigrationModel{},
 		&ExecutionWebhookEventModel{},
 		&ExecutionWebhookModel{},
+		&ExecutionActionModel{},
+		&ExecutionQueueModel{},
+		&ReasonerResultCacheModel{},
+		&ScheduleModel{},
+		&AgentInstanceModel{},
+		&APIKeyModel{},
+		&MemoryKeyModel{},
+		&ApprovalModel{},
+		&EventBackplaneMessageModel{},
 		&ObservabilityWebhookModel{},
 		&ObservabilityDeadLetterQueueModel{},
 	}
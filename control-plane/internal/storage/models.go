@@ -22,6 +22,8 @@ type ExecutionRecordModel struct {
 	CompletedAt       *time.Time `gorm:"column:completed_at"`
 	DurationMS        *int64     `gorm:"column:duration_ms"`
 	Notes             string     `gorm:"column:notes;default:'[]'"`
+	RetryCount        int        `gorm:"column:retry_count;not null;default:0"`
+	ScheduleID        *string    `gorm:"column:schedule_id;index"`
 	CreatedAt         time.Time  `gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt         time.Time  `gorm:"column:updated_at;autoUpdateTime"`
 }
@@ -383,6 +385,146 @@ type ExecutionWebhookModel struct {
 
 func (ExecutionWebhookModel) TableName() string { return "execution_webhooks" }
 
+// ExecutionActionModel stores work queued for poll-mode agents until it is claimed and acknowledged.
+type ExecutionActionModel struct {
+	ActionID          string     `gorm:"column:action_id;primaryKey"`
+	ExecutionID       string     `gorm:"column:execution_id;not null;index"`
+	RunID             string     `gorm:"column:run_id;not null"`
+	ParentExecutionID *string    `gorm:"column:parent_execution_id"`
+	SessionID         *string    `gorm:"column:session_id"`
+	ActorID           *string    `gorm:"column:actor_id"`
+	NodeID            string     `gorm:"column:node_id;not null;index:idx_execution_actions_node_status,priority:1"`
+	Target            string     `gorm:"column:target;not null"`
+	TargetType        string     `gorm:"column:target_type;not null;default:'reasoner'"`
+	Input             []byte     `gorm:"column:input"`
+	Status            string     `gorm:"column:status;not null;default:'pending';index:idx_execution_actions_node_status,priority:2"`
+	AttemptCount      int        `gorm:"column:attempt_count;not null;default:0"`
+	LeaseExpiresAt    *time.Time `gorm:"column:lease_expires_at"`
+	ClaimedAt         *time.Time `gorm:"column:claimed_at"`
+	CompletedAt       *time.Time `gorm:"column:completed_at"`
+	CreatedAt         time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (ExecutionActionModel) TableName() string { return "execution_actions" }
+
+// ExecutionQueueModel persists async executions until a control plane worker has dispatched them.
+type ExecutionQueueModel struct {
+	ExecutionID string    `gorm:"column:execution_id;primaryKey"`
+	NodeID      string    `gorm:"column:node_id;not null"`
+	Target      string    `gorm:"column:target;not null"`
+	TargetType  string    `gorm:"column:target_type;not null;default:'reasoner'"`
+	Payload     []byte    `gorm:"column:payload"`
+	RetryPolicy []byte    `gorm:"column:retry_policy"`
+	Caller      string    `gorm:"column:caller;not null;default:''"`
+	CreatedAt   time.Time `gorm:"column:created_at;not null;index"`
+}
+
+func (ExecutionQueueModel) TableName() string { return "execution_queue" }
+
+// ReasonerResultCacheModel keeps the results of reasoners that cache them, per team and input.
+type ReasonerResultCacheModel struct {
+	TeamID    string    `gorm:"column:team_id;primaryKey"`
+	Reasoner  string    `gorm:"column:reasoner;primaryKey"`
+	InputHash string    `gorm:"column:input_hash;primaryKey"`
+	Result    []byte    `gorm:"column:result;not null"`
+	ExpiresAt time.Time `gorm:"column:expires_at;not null;index"`
+	CreatedAt time.Time `gorm:"column:created_at;not null"`
+}
+
+func (ReasonerResultCacheModel) TableName() string { return "reasoner_result_cache" }
+
+// AgentInstanceModel stores the replicas registered under one agent node ID.
+type AgentInstanceModel struct {
+	NodeID        string    `gorm:"column:node_id;primaryKey"`
+	InstanceID    string    `gorm:"column:instance_id;primaryKey"`
+	BaseURL       string    `gorm:"column:base_url;not null"`
+	LastHeartbeat time.Time `gorm:"column:last_heartbeat;not null"`
+	RegisteredAt  time.Time `gorm:"column:registered_at;not null"`
+}
+
+func (AgentInstanceModel) TableName() string { return "agent_instances" }
+
+// MemoryKeyModel stores the memory encryption keys, wrapped with the keystore master
+// key, so every replica reads the same keys.
+type MemoryKeyModel struct {
+	KeyID      string    `gorm:"column:key_id;primaryKey"`
+	WrappedKey []byte    `gorm:"column:wrapped_key;not null"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null"`
+}
+
+func (MemoryKeyModel) TableName() string { return "memory_keys" }
+
+// APIKeyModel stores team-scoped API keys. Only a hash of each secret is kept.
+type APIKeyModel struct {
+	KeyID      string     `gorm:"column:key_id;primaryKey"`
+	Name       string     `gorm:"column:name;not null"`
+	TeamID     string     `gorm:"column:team_id;not null;index"`
+	Role       string     `gorm:"column:role;not null"`
+	Prefix     string     `gorm:"column:prefix;not null"`
+	SecretHash string     `gorm:"column:secret_hash;not null;uniqueIndex"`
+	CreatedAt  time.Time  `gorm:"column:created_at;not null"`
+	RotatedAt  *time.Time `gorm:"column:rotated_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+}
+
+func (APIKeyModel) TableName() string { return "api_keys" }
+
+// ApprovalModel stores human approvals that executions wait on.
+type ApprovalModel struct {
+	ApprovalID      string     `gorm:"column:approval_id;primaryKey"`
+	ExecutionID     string     `gorm:"column:execution_id;not null;index"`
+	RunID           string     `gorm:"column:run_id;not null"`
+	AgentNodeID     string     `gorm:"column:agent_node_id;not null;index"`
+	ReasonerID      string     `gorm:"column:reasoner_id"`
+	Title           string     `gorm:"column:title;not null"`
+	Description     string     `gorm:"column:description"`
+	Payload         []byte     `gorm:"column:payload"`
+	Status          string     `gorm:"column:status;not null;index:idx_approvals_expiry,priority:1"`
+	DefaultDecision string     `gorm:"column:default_decision;not null"`
+	ExpiresAt       *time.Time `gorm:"column:expires_at;index:idx_approvals_expiry,priority:2"`
+	Comment         *string    `gorm:"column:comment"`
+	DecidedBy       *string    `gorm:"column:decided_by"`
+	TimedOut        bool       `gorm:"column:timed_out;not null;default:false"`
+	CreatedAt       time.Time  `gorm:"column:created_at;not null"`
+	DecidedAt       *time.Time `gorm:"column:decided_at"`
+}
+
+func (ApprovalModel) TableName() string { return "approvals" }
+
+// EventBackplaneMessageModel holds event payloads too large for a NOTIFY, which the
+// Postgres backplane notifies by ID instead.
+type EventBackplaneMessageModel struct {
+	ID        int64     `gorm:"column:id;primaryKey;autoIncrement"`
+	Topic     string    `gorm:"column:topic;not null"`
+	Payload   []byte    `gorm:"column:payload;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;not null;index"`
+}
+
+func (EventBackplaneMessageModel) TableName() string { return "event_backplane_messages" }
+
+// ScheduleModel stores cron schedules that fire async executions.
+type ScheduleModel struct {
+	ScheduleID      string     `gorm:"column:schedule_id;primaryKey"`
+	Name            string     `gorm:"column:name;not null;index"`
+	CronExpression  string     `gorm:"column:cron_expression;not null"`
+	TeamID          string     `gorm:"column:team_id;not null;default:'';index"`
+	Timezone        string     `gorm:"column:timezone"`
+	Target          string     `gorm:"column:target;not null"`
+	InputTemplate   []byte     `gorm:"column:input_template"`
+	Webhook         []byte     `gorm:"column:webhook"`
+	Enabled         bool       `gorm:"column:enabled;not null;default:true;index:idx_schedules_due,priority:1"`
+	NextRunAt       *time.Time `gorm:"column:next_run_at;index:idx_schedules_due,priority:2"`
+	LastRunAt       *time.Time `gorm:"column:last_run_at"`
+	LastExecutionID *string    `gorm:"column:last_execution_id"`
+	LastError       *string    `gorm:"column:last_error"`
+	RunCount        int64      `gorm:"column:run_count;not null;default:0"`
+	CreatedAt       time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (ScheduleModel) TableName() string { return "schedules" }
+
 // ObservabilityWebhookModel represents the global observability webhook configuration.
 // This is a singleton table with only one row (id='global').
 type ObservabilityWebhookModel struct {
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// GetCachedResult returns the unexpired result cached for a reasoner input, or nil.
+func (ls *LocalStorage) GetCachedResult(ctx context.Context, teamID, reasoner, inputHash string) ([]byte, error) {
+	var result []byte
+	err := ls.requireSQLDB().QueryRowContext(ctx, `
+		SELECT result FROM reasoner_result_cache
+		WHERE team_id = ? AND reasoner = ? AND input_hash = ? AND expires_at > ?`,
+		teamID, reasoner, inputHash, time.Now().UTC(),
+	).Scan(&result)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get cached result: %w", err)
+	}
+	return result, nil
+}
+
+// StoreCachedResult keeps a reasoner result for ttl, replacing any earlier result for
+// the same input. Expired entries are pruned along the way.
+func (ls *LocalStorage) StoreCachedResult(ctx context.Context, teamID, reasoner, inputHash string, result []byte, ttl time.Duration) error {
+	now := time.Now().UTC()
+	db := ls.requireSQLDB()
+	if _, err := db.ExecContext(ctx, `DELETE FROM reasoner_result_cache WHERE expires_at <= ?`, now); err != nil {
+		return fmt.Errorf("prune cached results: %w", err)
+	}
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO reasoner_result_cache (team_id, reasoner, input_hash, result, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(team_id, reasoner, input_hash) DO UPDATE SET
+			result = excluded.result,
+			expires_at = excluded.expires_at,
+			created_at = excluded.created_at`,
+		teamID, reasoner, inputHash, result, now.Add(ttl), now,
+	)
+	if err != nil {
+		return fmt.Errorf("store cached result: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultCache_ScopedPerTeamAndExpires(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+
+	require.NoError(t, provider.StoreCachedResult(ctx, "team-a", "node-1.greet", "hash-1", []byte(`{"v":1}`), time.Hour))
+
+	result, err := provider.GetCachedResult(ctx, "team-a", "node-1.greet", "hash-1")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"v":1}`, string(result))
+
+	result, err = provider.GetCachedResult(ctx, "team-b", "node-1.greet", "hash-1")
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	require.NoError(t, provider.StoreCachedResult(ctx, "team-a", "node-1.greet", "hash-1", []byte(`{"v":2}`), time.Hour))
+	result, err = provider.GetCachedResult(ctx, "team-a", "node-1.greet", "hash-1")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"v":2}`, string(result))
+
+	require.NoError(t, provider.StoreCachedResult(ctx, "team-a", "node-1.greet", "hash-2", []byte(`{"v":3}`), -time.Second))
+	result, err = provider.GetCachedResult(ctx, "team-a", "node-1.greet", "hash-2")
+	require.NoError(t, err)
+	assert.Nil(t, result)
+}
@@ -0,0 +1,310 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+)
+
+const scheduleColumns = `
+	schedule_id, name, cron_expression, team_id, timezone, target, input_template, webhook,
+	enabled, next_run_at, last_run_at, last_execution_id, last_error, run_count,
+	created_at, updated_at`
+
+// CreateSchedule inserts a new schedule.
+func (ls *LocalStorage) CreateSchedule(ctx context.Context, schedule *types.Schedule) error {
+	if schedule == nil {
+		return fmt.Errorf("schedule is nil")
+	}
+	if strings.TrimSpace(schedule.ScheduleID) == "" {
+		return fmt.Errorf("schedule id is required")
+	}
+
+	webhookJSON, err := marshalScheduleWebhook(schedule.Webhook)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+
+	_, err = ls.requireSQLDB().ExecContext(ctx, `
+		INSERT INTO schedules (`+scheduleColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		schedule.ScheduleID,
+		schedule.Name,
+		schedule.CronExpression,
+		schedule.TeamID,
+		schedule.Timezone,
+		schedule.Target,
+		bytesOrNil(schedule.InputTemplate),
+		bytesOrNil(webhookJSON),
+		schedule.Enabled,
+		schedule.NextRunAt,
+		schedule.LastRunAt,
+		schedule.LastExecutionID,
+		schedule.LastError,
+		schedule.RunCount,
+		schedule.CreatedAt,
+		schedule.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert schedule: %w", err)
+	}
+	return nil
+}
+
+// GetSchedule returns the schedule with the given ID, or nil if it does not exist.
+func (ls *LocalStorage) GetSchedule(ctx context.Context, scheduleID string) (*types.Schedule, error) {
+	row := ls.requireSQLDB().QueryRowContext(ctx, `
+		SELECT `+scheduleColumns+`
+		FROM schedules
+		WHERE schedule_id = ?`, scheduleID)
+	return scanSchedule(row)
+}
+
+// ListSchedules returns all schedules ordered by creation time.
+func (ls *LocalStorage) ListSchedules(ctx context.Context) ([]*types.Schedule, error) {
+	rows, err := ls.requireSQLDB().QueryContext(ctx, `
+		SELECT `+scheduleColumns+`
+		FROM schedules
+		ORDER BY created_at ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("list schedules: %w", err)
+	}
+	defer rows.Close()
+	return scanSchedules(rows)
+}
+
+// ListDueSchedules returns up to limit enabled schedules whose next run is at or before now,
+// most overdue first.
+func (ls *LocalStorage) ListDueSchedules(ctx context.Context, now time.Time, limit int) ([]*types.Schedule, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	rows, err := ls.requireSQLDB().QueryContext(ctx, `
+		SELECT `+scheduleColumns+`
+		FROM schedules
+		WHERE enabled = ? AND next_run_at IS NOT NULL AND next_run_at <= ?
+		ORDER BY next_run_at ASC
+		LIMIT ?`, true, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("list due schedules: %w", err)
+	}
+	defer rows.Close()
+	return scanSchedules(rows)
+}
+
+// UpdateSchedule overwrites the mutable fields of an existing schedule.
+func (ls *LocalStorage) UpdateSchedule(ctx context.Context, schedule *types.Schedule) error {
+	if schedule == nil {
+		return fmt.Errorf("schedule is nil")
+	}
+
+	webhookJSON, err := marshalScheduleWebhook(schedule.Webhook)
+	if err != nil {
+		return err
+	}
+	schedule.UpdatedAt = time.Now().UTC()
+
+	result, err := ls.requireSQLDB().ExecContext(ctx, `
+		UPDATE schedules SET
+			name = ?,
+			cron_expression = ?,
+			timezone = ?,
+			target = ?,
+			input_template = ?,
+			webhook = ?,
+			enabled = ?,
+			next_run_at = ?,
+			last_run_at = ?,
+			last_execution_id = ?,
+			last_error = ?,
+			run_count = ?,
+			updated_at = ?
+		WHERE schedule_id = ?`,
+		schedule.Name,
+		schedule.CronExpression,
+		schedule.Timezone,
+		schedule.Target,
+		bytesOrNil(schedule.InputTemplate),
+		bytesOrNil(webhookJSON),
+		schedule.Enabled,
+		schedule.NextRunAt,
+		schedule.LastRunAt,
+		schedule.LastExecutionID,
+		schedule.LastError,
+		schedule.RunCount,
+		schedule.UpdatedAt,
+		schedule.ScheduleID,
+	)
+	if err != nil {
+		return fmt.Errorf("update schedule: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("schedule %s not found", schedule.ScheduleID)
+	}
+	return nil
+}
+
+// ClaimScheduleRun advances a due schedule past the run about to be fired, writing only
+// its enabled flag, next and last run times and last error. The schedule must still be
+// as loaded, due at dueAt and not updated since; otherwise nothing changes and false is
+// returned, so an edit made in the meantime is neither lost nor fired under old settings.
+func (ls *LocalStorage) ClaimScheduleRun(ctx context.Context, schedule *types.Schedule, dueAt time.Time) (bool, error) {
+	if schedule == nil {
+		return false, fmt.Errorf("schedule is nil")
+	}
+
+	result, err := ls.requireSQLDB().ExecContext(ctx, `
+		UPDATE schedules SET
+			enabled = ?,
+			next_run_at = ?,
+			last_run_at = ?,
+			last_error = ?
+		WHERE schedule_id = ? AND next_run_at = ? AND updated_at = ?`,
+		schedule.Enabled,
+		schedule.NextRunAt,
+		schedule.LastRunAt,
+		schedule.LastError,
+		schedule.ScheduleID,
+		dueAt,
+		schedule.UpdatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("claim schedule run: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claim schedule run: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// RecordScheduleRun counts a run of a schedule and records its outcome. executionID
+// is nil when no execution record was created, which keeps the previous one.
+func (ls *LocalStorage) RecordScheduleRun(ctx context.Context, scheduleID string, executionID, lastError *string) error {
+	_, err := ls.requireSQLDB().ExecContext(ctx, `
+		UPDATE schedules SET
+			run_count = run_count + 1,
+			last_execution_id = COALESCE(?, last_execution_id),
+			last_error = ?
+		WHERE schedule_id = ?`,
+		executionID,
+		lastError,
+		scheduleID,
+	)
+	if err != nil {
+		return fmt.Errorf("record schedule run: %w", err)
+	}
+	return nil
+}
+
+// DeleteSchedule removes a schedule. Executions it already fired are kept. Deleting a
+// schedule that is already gone is not an error.
+func (ls *LocalStorage) DeleteSchedule(ctx context.Context, scheduleID string) error {
+	if _, err := ls.requireSQLDB().ExecContext(ctx, `DELETE FROM schedules WHERE schedule_id = ?`, scheduleID); err != nil {
+		return fmt.Errorf("delete schedule: %w", err)
+	}
+	return nil
+}
+
+func marshalScheduleWebhook(webhook *types.ScheduleWebhook) ([]byte, error) {
+	if webhook == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(webhook)
+	if err != nil {
+		return nil, fmt.Errorf("marshal schedule webhook: %w", err)
+	}
+	return data, nil
+}
+
+func scanSchedules(rows *sql.Rows) ([]*types.Schedule, error) {
+	schedules := make([]*types.Schedule, 0)
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+func scanSchedule(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*types.Schedule, error) {
+	var (
+		schedule        types.Schedule
+		timezone        sql.NullString
+		inputTemplate   []byte
+		webhookJSON     []byte
+		nextRunAt       sql.NullTime
+		lastRunAt       sql.NullTime
+		lastExecutionID sql.NullString
+		lastError       sql.NullString
+	)
+
+	err := scanner.Scan(
+		&schedule.ScheduleID,
+		&schedule.Name,
+		&schedule.CronExpression,
+		&schedule.TeamID,
+		&timezone,
+		&schedule.Target,
+		&inputTemplate,
+		&webhookJSON,
+		&schedule.Enabled,
+		&nextRunAt,
+		&lastRunAt,
+		&lastExecutionID,
+		&lastError,
+		&schedule.RunCount,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("scan schedule: %w", err)
+	}
+
+	schedule.Timezone = timezone.String
+	if len(inputTemplate) > 0 {
+		schedule.InputTemplate = append([]byte(nil), inputTemplate...)
+	}
+	if len(webhookJSON) > 0 {
+		var webhook types.ScheduleWebhook
+		if err := json.Unmarshal(webhookJSON, &webhook); err != nil {
+			return nil, fmt.Errorf("unmarshal schedule webhook: %w", err)
+		}
+		schedule.Webhook = &webhook
+	}
+	if nextRunAt.Valid {
+		t := nextRunAt.Time.UTC()
+		schedule.NextRunAt = &t
+	}
+	if lastRunAt.Valid {
+		t := lastRunAt.Time.UTC()
+		schedule.LastRunAt = &t
+	}
+	if lastExecutionID.Valid {
+		schedule.LastExecutionID = &lastExecutionID.String
+	}
+	if lastError.Valid {
+		schedule.LastError = &lastError.String
+	}
+	return &schedule, nil
+}
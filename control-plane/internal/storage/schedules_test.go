@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedules_CRUDAndDueListing(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+
+	schedules := []*types.Schedule{
+		{
+			ScheduleID:     "sched-due",
+			Name:           "nightly-report",
+			CronExpression: "0 2 * * *",
+			TeamID:         "team-a",
+			Target:         "node-1.report",
+			InputTemplate:  json.RawMessage(`{"day":"{{.ScheduledAt}}"}`),
+			Webhook:        &types.ScheduleWebhook{URL: "https://example.com/hook", Secret: "s3cret"},
+			Enabled:        true,
+			NextRunAt:      &past,
+		},
+		{ScheduleID: "sched-future", Name: "later", CronExpression: "@hourly", Target: "node-1.report", Enabled: true, NextRunAt: &future},
+		{ScheduleID: "sched-disabled", Name: "off", CronExpression: "@hourly", Target: "node-1.report", Enabled: false, NextRunAt: &past},
+	}
+	for _, schedule := range schedules {
+		require.NoError(t, provider.CreateSchedule(ctx, schedule))
+	}
+
+	got, err := provider.GetSchedule(ctx, "sched-due")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.Equal(t, "nightly-report", got.Name)
+	require.Equal(t, "team-a", got.TeamID)
+	require.JSONEq(t, `{"day":"{{.ScheduledAt}}"}`, string(got.InputTemplate))
+	require.NotNil(t, got.Webhook)
+	require.Equal(t, "s3cret", got.Webhook.Secret)
+	require.True(t, got.NextRunAt.Equal(past))
+
+	missing, err := provider.GetSchedule(ctx, "nope")
+	require.NoError(t, err)
+	require.Nil(t, missing)
+
+	all, err := provider.ListSchedules(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+
+	due, err := provider.ListDueSchedules(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.Equal(t, "sched-due", due[0].ScheduleID)
+
+	execID := "exec-1"
+	got.NextRunAt = &future
+	got.LastRunAt = &now
+	got.LastExecutionID = &execID
+	got.RunCount = 1
+	require.NoError(t, provider.UpdateSchedule(ctx, got))
+
+	due, err = provider.ListDueSchedules(ctx, now, 10)
+	require.NoError(t, err)
+	require.Empty(t, due)
+
+	got, err = provider.GetSchedule(ctx, "sched-due")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), got.RunCount)
+	require.Equal(t, "exec-1", *got.LastExecutionID)
+
+	require.Error(t, provider.UpdateSchedule(ctx, &types.Schedule{ScheduleID: "nope"}))
+
+	require.NoError(t, provider.DeleteSchedule(ctx, "sched-due"))
+	require.NoError(t, provider.DeleteSchedule(ctx, "sched-due"))
+	all, err = provider.ListSchedules(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+}
+
+func TestExecutionRecords_FilterByScheduleID(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+
+	scheduleID := "sched-1"
+	for _, exec := range []*types.Execution{
+		{ExecutionID: "exec-scheduled", RunID: "run-1", AgentNodeID: "node-1", ReasonerID: "r", NodeID: "node-1", Status: types.ExecutionStatusQueued, ScheduleID: &scheduleID},
+		{ExecutionID: "exec-manual", RunID: "run-2", AgentNodeID: "node-1", ReasonerID: "r", NodeID: "node-1", Status: types.ExecutionStatusQueued},
+	} {
+		require.NoError(t, provider.CreateExecutionRecord(ctx, exec))
+	}
+
+	records, err := provider.QueryExecutionRecords(ctx, types.ExecutionFilter{ScheduleID: &scheduleID})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, "exec-scheduled", records[0].ExecutionID)
+	require.Equal(t, scheduleID, *records[0].ScheduleID)
+}
+
+func TestSchedules_ClaimRunOnlyWhileUnchanged(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+
+	past := time.Now().UTC().Add(-time.Minute)
+	require.NoError(t, provider.CreateSchedule(ctx, &types.Schedule{
+		ScheduleID: "sched-1", Name: "nightly", CronExpression: "@hourly", Target: "node-1.report", Enabled: true, NextRunAt: &past,
+	}))
+	require.NoError(t, provider.CreateSchedule(ctx, &types.Schedule{
+		ScheduleID: "sched-2", Name: "edited", CronExpression: "@hourly", Target: "node-1.report", Enabled: true, NextRunAt: &past,
+	}))
+
+	due, err := provider.ListDueSchedules(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	listed := map[string]*types.Schedule{}
+	for _, schedule := range due {
+		listed[schedule.ScheduleID] = schedule
+	}
+
+	// An edit between listing and firing wins over the stale copy.
+	edited, err := provider.GetSchedule(ctx, "sched-2")
+	require.NoError(t, err)
+	edited.Target = "node-2.report"
+	require.NoError(t, provider.UpdateSchedule(ctx, edited))
+
+	next := time.Now().UTC().Add(time.Hour)
+	firedAt := time.Now().UTC()
+	for _, id := range []string{"sched-1", "sched-2"} {
+		schedule := listed[id]
+		dueAt := *schedule.NextRunAt
+		schedule.NextRunAt = &next
+		schedule.LastRunAt = &firedAt
+		claimed, err := provider.ClaimScheduleRun(ctx, schedule, dueAt)
+		require.NoError(t, err)
+		require.Equal(t, id == "sched-1", claimed, id)
+
+		claimed, err = provider.ClaimScheduleRun(ctx, schedule, dueAt)
+		require.NoError(t, err)
+		require.False(t, claimed, "a run is claimed once")
+	}
+
+	got, err := provider.GetSchedule(ctx, "sched-2")
+	require.NoError(t, err)
+	require.Equal(t, "node-2.report", got.Target)
+	require.True(t, got.NextRunAt.Equal(past))
+
+	execID := "exec-1"
+	require.NoError(t, provider.RecordScheduleRun(ctx, "sched-1", &execID, nil))
+	failure := "agent not found"
+	require.NoError(t, provider.RecordScheduleRun(ctx, "sched-1", nil, &failure))
+	got, err = provider.GetSchedule(ctx, "sched-1")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), got.RunCount)
+	require.Equal(t, "exec-1", *got.LastExecutionID)
+	require.Equal(t, failure, *got.LastError)
+	require.True(t, got.NextRunAt.Equal(next))
+}
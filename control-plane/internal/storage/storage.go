@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/Agent-Field/agentfield/control-plane/internal/events"
@@ -61,6 +62,32 @@ type StorageProvider interface {
 	StoreWorkflowExecutionEvent(ctx context.Context, event *types.WorkflowExecutionEvent) error
 	ListWorkflowExecutionEvents(ctx context.Context, executionID string, afterSeq *int64, limit int) ([]*types.WorkflowExecutionEvent, error)
 
+	// Execution actions queued for poll-mode agents
+	EnqueueExecutionAction(ctx context.Context, action *types.ExecutionAction) error
+	ClaimExecutionActions(ctx context.Context, nodeID string, limit int, leaseTTL time.Duration) ([]*types.ExecutionAction, error)
+	GetExecutionAction(ctx context.Context, actionID string) (*types.ExecutionAction, error)
+	ExtendExecutionActionLease(ctx context.Context, actionID string, leaseTTL time.Duration) error
+	CompleteExecutionAction(ctx context.Context, actionID string) (bool, error)
+
+	// Durable queue of accepted async executions awaiting dispatch
+	EnqueueExecution(ctx context.Context, item *types.QueuedExecution) error
+	ListQueuedExecutions(ctx context.Context, limitPerTarget int) ([]*types.QueuedExecution, error)
+	DeleteQueuedExecution(ctx context.Context, executionID string) error
+
+	// Cached reasoner results, kept apart from user memory
+	GetCachedResult(ctx context.Context, teamID, reasoner, inputHash string) ([]byte, error)
+	StoreCachedResult(ctx context.Context, teamID, reasoner, inputHash string, result []byte, ttl time.Duration) error
+
+	// Cron schedules that fire async executions
+	CreateSchedule(ctx context.Context, schedule *types.Schedule) error
+	GetSchedule(ctx context.Context, scheduleID string) (*types.Schedule, error)
+	ListSchedules(ctx context.Context) ([]*types.Schedule, error)
+	UpdateSchedule(ctx context.Context, schedule *types.Schedule) error
+	ClaimScheduleRun(ctx context.Context, schedule *types.Schedule, dueAt time.Time) (bool, error)
+	RecordScheduleRun(ctx context.Context, scheduleID string, executionID, lastError *string) error
+	DeleteSchedule(ctx context.Context, scheduleID string) error
+	ListDueSchedules(ctx context.Context, now time.Time, limit int) ([]*types.Schedule, error)
+
 	// Execution cleanup operations
 	CleanupOldExecutions(ctx context.Context, retentionPeriod time.Duration, batchSize int) (int, error)
 	MarkStaleExecutions(ctx context.Context, staleAfter time.Duration, limit int) (int, error)
@@ -83,6 +110,7 @@ type StorageProvider interface {
 
 	// Memory operations
 	SetMemory(ctx context.Context, memory *types.Memory) error
+	UpdateMemory(ctx context.Context, scope, scopeID, key string, update func(*types.Memory) (*types.Memory, error)) (*types.Memory, error)
 	GetMemory(ctx context.Context, scope, scopeID, key string) (*types.Memory, error)
 	DeleteMemory(ctx context.Context, scope, scopeID, key string) error
 	ListMemory(ctx context.Context, scope, scopeID string) ([]*types.Memory, error)
@@ -91,6 +119,9 @@ type StorageProvider interface {
 	DeleteVector(ctx context.Context, scope, scopeID, key string) error
 	DeleteVectorsByPrefix(ctx context.Context, scope, scopeID, prefix string) (int, error)
 	SimilaritySearch(ctx context.Context, scope, scopeID string, queryEmbedding []float32, topK int, filters map[string]interface{}) ([]*types.VectorSearchResult, error)
+	HybridSearch(ctx context.Context, scope, scopeID string, query types.VectorSearchQuery) ([]*types.VectorSearchResult, error)
+	ExportMemory(ctx context.Context, filter types.MemorySnapshotFilter, emit func(*types.Memory) error) error
+	ExportVectors(ctx context.Context, filter types.MemorySnapshotFilter, emit func(*types.VectorRecord) error) error
 
 	// Event operations
 	StoreEvent(ctx context.Context, event *types.MemoryChangeEvent) error
@@ -111,6 +142,26 @@ type StorageProvider interface {
 	UpdateAgentHeartbeat(ctx context.Context, id string, heartbeatTime time.Time) error
 	UpdateAgentLifecycleStatus(ctx context.Context, id string, status types.AgentLifecycleStatus) error
 
+	// Replicas registered under one agent node ID
+	UpsertAgentInstance(ctx context.Context, instance *types.AgentInstance) error
+	ListAgentInstances(ctx context.Context, nodeID string) ([]*types.AgentInstance, error)
+	TouchAgentInstance(ctx context.Context, nodeID, instanceID string, seenAt time.Time) error
+	DeleteAgentInstance(ctx context.Context, nodeID, instanceID string) error
+
+	// Team-scoped API keys
+	CreateAPIKey(ctx context.Context, key *types.APIKey) error
+	GetAPIKey(ctx context.Context, keyID string) (*types.APIKey, error)
+	GetAPIKeyBySecretHash(ctx context.Context, secretHash string) (*types.APIKey, error)
+	ListAPIKeys(ctx context.Context, teamID string) ([]*types.APIKey, error)
+	UpdateAPIKey(ctx context.Context, key *types.APIKey) error
+
+	// Human approvals executions wait on
+	CreateApproval(ctx context.Context, approval *types.Approval) error
+	GetApproval(ctx context.Context, approvalID string) (*types.Approval, error)
+	ListApprovals(ctx context.Context, filter types.ApprovalFilter) ([]*types.Approval, error)
+	ListExpiredApprovals(ctx context.Context, now time.Time, limit int) ([]*types.Approval, error)
+	ResolveApproval(ctx context.Context, approval *types.Approval) (bool, error)
+
 	// Configuration
 	SetConfig(ctx context.Context, key string, value interface{}) error
 	GetConfig(ctx context.Context, key string) (interface{}, error)
@@ -223,6 +274,21 @@ type StorageConfig struct {
 	Local    LocalStorageConfig    `yaml:"local" mapstructure:"local"`
 	Postgres PostgresStorageConfig `yaml:"postgres" mapstructure:"postgres"`
 	Vector   VectorStoreConfig     `yaml:"vector" mapstructure:"vector"`
+	// EventBackplane relays events between replicas: "postgres" (the default in postgres
+	// mode) uses LISTEN/NOTIFY and "none" keeps events on each replica.
+	EventBackplane string `yaml:"event_backplane" mapstructure:"event_backplane"`
+	// MemoryEncryption encrypts key-value memory values at rest.
+	MemoryEncryption MemoryEncryptionConfig `yaml:"memory_encryption" mapstructure:"memory_encryption"`
+}
+
+// MemoryEncryptionConfig controls at-rest encryption of key-value memory values. The
+// keys are kept in the memory_keys table, wrapped with the keystore master key, and the
+// keystore hands them out once the server attaches it with EnableMemoryEncryption.
+type MemoryEncryptionConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+	// Scopes are encrypted in full, e.g. "global" and "actor". Values in other scopes
+	// are encrypted only when written with encryption requested.
+	Scopes []string `yaml:"scopes" mapstructure:"scopes"`
 }
 
 // PostgresStorageConfig holds configuration for the PostgreSQL storage provider.
@@ -249,8 +315,31 @@ type LocalStorageConfig struct {
 
 // VectorStoreConfig controls vector storage behavior.
 type VectorStoreConfig struct {
-	Enabled  *bool  `yaml:"enabled" mapstructure:"enabled"`
-	Distance string `yaml:"distance" mapstructure:"distance"`
+	Enabled  *bool             `yaml:"enabled" mapstructure:"enabled"`
+	Distance string            `yaml:"distance" mapstructure:"distance"`
+	Index    VectorIndexConfig `yaml:"index" mapstructure:"index"`
+}
+
+// VectorIndexConfig controls the in-process approximate nearest-neighbour index of the
+// local vector store. Postgres relies on pgvector instead.
+type VectorIndexConfig struct {
+	// Type is "hnsw" to search an in-process HNSW graph, or "none" (the default) to scan
+	// every vector of the scope exactly.
+	Type string `yaml:"type" mapstructure:"type"`
+	// M is the number of neighbours each vector links to. More links raise recall and
+	// memory use.
+	M int `yaml:"m" mapstructure:"m"`
+	// EfConstruction is the candidate list size used while inserting.
+	EfConstruction int `yaml:"ef_construction" mapstructure:"ef_construction"`
+	// EfSearch is the candidate list size used while searching; raising it trades
+	// latency for recall.
+	EfSearch int `yaml:"ef_search" mapstructure:"ef_search"`
+	// MinVectors is the scope size below which searches scan exactly anyway.
+	MinVectors int `yaml:"min_vectors" mapstructure:"min_vectors"`
+}
+
+func (cfg VectorIndexConfig) enabled() bool {
+	return strings.EqualFold(cfg.Type, "hnsw")
 }
 
 func (cfg VectorStoreConfig) isEnabled() bool {
@@ -264,6 +353,18 @@ func (cfg VectorStoreConfig) normalized() VectorStoreConfig {
 	if cfg.Distance == "" {
 		cfg.Distance = "cosine"
 	}
+	if cfg.Index.M <= 1 {
+		cfg.Index.M = 16
+	}
+	if cfg.Index.EfConstruction <= 0 {
+		cfg.Index.EfConstruction = 200
+	}
+	if cfg.Index.EfSearch <= 0 {
+		cfg.Index.EfSearch = 64
+	}
+	if cfg.Index.MinVectors <= 0 {
+		cfg.Index.MinVectors = 1000
+	}
 	return cfg
 }
 
@@ -285,6 +386,9 @@ func (sf *StorageFactory) CreateStorage(config StorageConfig) (StorageProvider,
 	}
 
 	config.Vector = config.Vector.normalized()
+	if env := os.Getenv("AGENTFIELD_EVENT_BACKPLANE"); env != "" {
+		config.EventBackplane = env
+	}
 
 	switch mode {
 	case "local":
@@ -292,10 +396,11 @@ func (sf *StorageFactory) CreateStorage(config StorageConfig) (StorageProvider,
 		localStorage.vectorConfig = config.Vector
 		// Pass the full StorageConfig to Initialize
 		if err := localStorage.Initialize(ctx, StorageConfig{
-			Mode:     mode,
-			Local:    config.Local,
-			Postgres: config.Postgres,
-			Vector:   config.Vector,
+			Mode:           mode,
+			Local:          config.Local,
+			Postgres:       config.Postgres,
+			Vector:         config.Vector,
+			EventBackplane: config.EventBackplane,
 		}); err != nil {
 			return nil, nil, fmt.Errorf("failed to initialize local storage: %w", err)
 		}
@@ -305,10 +410,11 @@ func (sf *StorageFactory) CreateStorage(config StorageConfig) (StorageProvider,
 		pgStorage := NewPostgresStorage(config.Postgres)
 		pgStorage.vectorConfig = config.Vector
 		if err := pgStorage.Initialize(ctx, StorageConfig{
-			Mode:     mode,
-			Local:    config.Local,
-			Postgres: config.Postgres,
-			Vector:   config.Vector,
+			Mode:           mode,
+			Local:          config.Local,
+			Postgres:       config.Postgres,
+			Vector:         config.Vector,
+			EventBackplane: config.EventBackplane,
 		}); err != nil {
 			return nil, nil, fmt.Errorf("failed to initialize postgres storage: %w", err)
 		}
@@ -103,6 +103,7 @@ func TestStorageParity_UpdateExecutionRecord(t *testing.T) {
 		e.CompletedAt = &completed
 		duration := int64(1000)
 		e.DurationMS = &duration
+		e.RetryCount = 2
 		return e, nil
 	})
 	require.NoError(t, err)
@@ -116,6 +117,7 @@ func TestStorageParity_UpdateExecutionRecord(t *testing.T) {
 	retrieved, err := store.GetExecutionRecord(ctx, "exec-update-parity")
 	require.NoError(t, err)
 	require.Equal(t, string(types.ExecutionStatusSucceeded), retrieved.Status)
+	require.Equal(t, 2, retrieved.RetryCount)
 }
 
 // TestStorageParity_QueryExecutionRecords tests query behavior parity
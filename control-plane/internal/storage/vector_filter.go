@@ -0,0 +1,480 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Vector search filters are JSON objects in the style of MongoDB queries:
+//
+//	{"source": "docs"}                                  equality
+//	{"doc.type": {"$in": ["pdf", "html"]}}              nested path, set membership
+//	{"published": {"$gte": "2024-01-01", "$lt": "2025"}} range over strings or numbers
+//	{"$or": [{"tenant": "a"}, {"shared": {"$exists": true}}]}
+//
+// Field names are dot-separated paths into the metadata. Supported operators are $eq,
+// $ne, $gt, $gte, $lt, $lte, $in, $nin and $exists, combined with $and and $or. Fields
+// of one object must all match. Equality is type-strict, as JSON equality: 1 matches
+// 1.0 but not "1", and arrays and objects match only when equal as a whole. Range
+// operators compare numbers numerically and strings bytewise, so ISO 8601 timestamps
+// order chronologically; values of other types never match them.
+
+// metadataFilter is a compiled vector search filter. The nil filter matches everything.
+type metadataFilter struct {
+	// Exactly one of all, any or condition is set.
+	all       []*metadataFilter
+	any       []*metadataFilter
+	condition *filterCondition
+}
+
+type filterCondition struct {
+	path  []string
+	op    string
+	value interface{}
+}
+
+var filterOperators = map[string]bool{
+	"$eq": true, "$ne": true,
+	"$gt": true, "$gte": true, "$lt": true, "$lte": true,
+	"$in": true, "$nin": true,
+	"$exists": true,
+}
+
+// ValidateVectorFilter reports whether filters is a well-formed vector search filter.
+func ValidateVectorFilter(filters map[string]interface{}) error {
+	_, err := compileMetadataFilter(filters)
+	return err
+}
+
+func compileMetadataFilter(filters map[string]interface{}) (*metadataFilter, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	return compileFilterObject(filters)
+}
+
+func compileFilterObject(filters map[string]interface{}) (*metadataFilter, error) {
+	// Sorted so the SQL built from a filter is deterministic.
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	node := &metadataFilter{}
+	for _, key := range keys {
+		value := filters[key]
+		switch {
+		case key == "$and" || key == "$or":
+			clauses, err := compileFilterClauses(key, value)
+			if err != nil {
+				return nil, err
+			}
+			if key == "$and" {
+				node.all = append(node.all, &metadataFilter{all: clauses})
+			} else {
+				node.all = append(node.all, &metadataFilter{any: clauses})
+			}
+		case strings.HasPrefix(key, "$"):
+			return nil, fmt.Errorf("unsupported filter operator %q at the top level", key)
+		default:
+			conditions, err := compileFieldFilter(key, value)
+			if err != nil {
+				return nil, err
+			}
+			node.all = append(node.all, conditions...)
+		}
+	}
+	return node, nil
+}
+
+func compileFilterClauses(op string, value interface{}) ([]*metadataFilter, error) {
+	items, ok := value.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("%s requires a non-empty array of filters", op)
+	}
+	clauses := make([]*metadataFilter, 0, len(items))
+	for _, item := range items {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s requires a non-empty array of filters", op)
+		}
+		clause, err := compileFilterObject(object)
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, clause)
+	}
+	return clauses, nil
+}
+
+func compileFieldFilter(field string, value interface{}) ([]*metadataFilter, error) {
+	path := strings.Split(field, ".")
+	for _, segment := range path {
+		if segment == "" {
+			return nil, fmt.Errorf("invalid filter field %q", field)
+		}
+	}
+
+	operators, ok := value.(map[string]interface{})
+	if !ok || !hasOperatorKeys(operators) {
+		return []*metadataFilter{{condition: &filterCondition{path: path, op: "$eq", value: value}}}, nil
+	}
+
+	ops := make([]string, 0, len(operators))
+	for op := range operators {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+
+	conditions := make([]*metadataFilter, 0, len(ops))
+	for _, op := range ops {
+		operand := operators[op]
+		if !filterOperators[op] {
+			return nil, fmt.Errorf("unsupported filter operator %q on %q", op, field)
+		}
+		switch op {
+		case "$in", "$nin":
+			if _, ok := operand.([]interface{}); !ok {
+				return nil, fmt.Errorf("%s on %q requires an array", op, field)
+			}
+		case "$exists":
+			if _, ok := operand.(bool); !ok {
+				return nil, fmt.Errorf("$exists on %q requires a boolean", field)
+			}
+		case "$gt", "$gte", "$lt", "$lte":
+			switch operand.(type) {
+			case string:
+			default:
+				if _, ok := filterNumber(operand); !ok {
+					return nil, fmt.Errorf("%s on %q requires a number or string", op, field)
+				}
+			}
+		}
+		conditions = append(conditions, &metadataFilter{condition: &filterCondition{path: path, op: op, value: operand}})
+	}
+	return conditions, nil
+}
+
+// hasOperatorKeys reports whether object is a set of operators rather than a value to
+// compare against. Mixing the two is an error caught by the operator check.
+func hasOperatorKeys(object map[string]interface{}) bool {
+	for key := range object {
+		if strings.HasPrefix(key, "$") {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *metadataFilter) matches(metadata map[string]interface{}) bool {
+	if f == nil {
+		return true
+	}
+	switch {
+	case f.condition != nil:
+		return f.condition.matches(metadata)
+	case f.any != nil:
+		for _, clause := range f.any {
+			if clause.matches(metadata) {
+				return true
+			}
+		}
+		return false
+	default:
+		for _, clause := range f.all {
+			if !clause.matches(metadata) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func (c *filterCondition) matches(metadata map[string]interface{}) bool {
+	actual, found := lookupMetadataPath(metadata, c.path)
+	switch c.op {
+	case "$exists":
+		return found == c.value.(bool)
+	case "$eq":
+		return found && filterValuesEqual(actual, c.value)
+	case "$ne":
+		return !found || !filterValuesEqual(actual, c.value)
+	case "$in":
+		return found && filterValueIn(actual, c.value.([]interface{}))
+	case "$nin":
+		return !found || !filterValueIn(actual, c.value.([]interface{}))
+	default:
+		if !found {
+			return false
+		}
+		cmp, ok := compareFilterValues(actual, c.value)
+		if !ok {
+			return false
+		}
+		switch c.op {
+		case "$gt":
+			return cmp > 0
+		case "$gte":
+			return cmp >= 0
+		case "$lt":
+			return cmp < 0
+		default:
+			return cmp <= 0
+		}
+	}
+}
+
+func lookupMetadataPath(metadata map[string]interface{}, path []string) (interface{}, bool) {
+	var current interface{} = metadata
+	for _, segment := range path {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = object[segment]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// filterValuesEqual compares decoded JSON values the way Postgres compares JSONB:
+// numbers by value, everything else by type and content.
+func filterValuesEqual(actual, expected interface{}) bool {
+	if expectedNumber, ok := filterNumber(expected); ok {
+		actualNumber, ok := filterNumber(actual)
+		return ok && actualNumber == expectedNumber
+	}
+	switch expected := expected.(type) {
+	case []interface{}:
+		actual, ok := actual.([]interface{})
+		if !ok || len(actual) != len(expected) {
+			return false
+		}
+		for i := range expected {
+			if !filterValuesEqual(actual[i], expected[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		actual, ok := actual.(map[string]interface{})
+		if !ok || len(actual) != len(expected) {
+			return false
+		}
+		for key, value := range expected {
+			if actualValue, found := actual[key]; !found || !filterValuesEqual(actualValue, value) {
+				return false
+			}
+		}
+		return true
+	case string, bool, nil:
+		return actual == expected
+	default:
+		return false
+	}
+}
+
+func filterValueIn(actual interface{}, candidates []interface{}) bool {
+	for _, candidate := range candidates {
+		if filterValuesEqual(actual, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func compareFilterValues(actual, bound interface{}) (int, bool) {
+	if boundString, ok := bound.(string); ok {
+		actualString, ok := actual.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(actualString, boundString), true
+	}
+	boundNumber, _ := filterNumber(bound)
+	actualNumber, ok := filterNumber(actual)
+	if !ok {
+		return 0, false
+	}
+	switch {
+	case actualNumber < boundNumber:
+		return -1, true
+	case actualNumber > boundNumber:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+func filterNumber(value interface{}) (float64, bool) {
+	var number float64
+	switch v := value.(type) {
+	case float64:
+		number = v
+	case float32:
+		number = float64(v)
+	case int:
+		number = float64(v)
+	case int64:
+		number = float64(v)
+	case int32:
+		number = float64(v)
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			return 0, false
+		}
+		number = parsed
+	default:
+		return 0, false
+	}
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, false
+	}
+	return number, true
+}
+
+// postgresFilterSQL renders a filter as a condition on the JSONB metadata column, with
+// its arguments in placeholder order. Equality tests containment, which the GIN index on
+// metadata can answer, and then compares the value itself, as containment alone also
+// accepts arrays and objects holding more than the filter value.
+func postgresFilterSQL(f *metadataFilter, column string) (string, []interface{}, error) {
+	var sb strings.Builder
+	var args []interface{}
+	if err := writePostgresFilter(&sb, &args, f, column); err != nil {
+		return "", nil, err
+	}
+	return sb.String(), args, nil
+}
+
+func writePostgresFilter(sb *strings.Builder, args *[]interface{}, f *metadataFilter, column string) error {
+	switch {
+	case f == nil:
+		sb.WriteString("TRUE")
+	case f.condition != nil:
+		return writePostgresCondition(sb, args, f.condition, column)
+	default:
+		clauses, joiner := f.all, " AND "
+		if f.any != nil {
+			clauses, joiner = f.any, " OR "
+		}
+		if len(clauses) == 0 {
+			sb.WriteString("TRUE")
+			return nil
+		}
+		sb.WriteString("(")
+		for i, clause := range clauses {
+			if i > 0 {
+				sb.WriteString(joiner)
+			}
+			if err := writePostgresFilter(sb, args, clause, column); err != nil {
+				return err
+			}
+		}
+		sb.WriteString(")")
+	}
+	return nil
+}
+
+func writePostgresCondition(sb *strings.Builder, args *[]interface{}, c *filterCondition, column string) error {
+	// element writes the JSONB value at the condition's path, or SQL NULL when absent.
+	element := func() {
+		sb.WriteString(column)
+		for _, segment := range c.path {
+			sb.WriteString(" -> ?")
+			*args = append(*args, segment)
+		}
+	}
+	// equals is never NULL for present metadata: an absent path fails the containment.
+	equals := func(value interface{}) error {
+		document, err := json.Marshal(nestFilterValue(c.path, value))
+		if err != nil {
+			return fmt.Errorf("marshal filter: %w", err)
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("marshal filter: %w", err)
+		}
+		sb.WriteString("(" + column + " @> ?::jsonb AND (")
+		*args = append(*args, string(document))
+		element()
+		sb.WriteString(") = ?::jsonb)")
+		*args = append(*args, string(encoded))
+		return nil
+	}
+	equalsAny := func(values []interface{}) error {
+		if len(values) == 0 {
+			sb.WriteString("FALSE")
+			return nil
+		}
+		sb.WriteString("(")
+		for i, value := range values {
+			if i > 0 {
+				sb.WriteString(" OR ")
+			}
+			if err := equals(value); err != nil {
+				return err
+			}
+		}
+		sb.WriteString(")")
+		return nil
+	}
+
+	switch c.op {
+	case "$exists":
+		sb.WriteString("(")
+		element()
+		if c.value.(bool) {
+			sb.WriteString(") IS NOT NULL")
+		} else {
+			sb.WriteString(") IS NULL")
+		}
+		return nil
+	case "$eq":
+		return equals(c.value)
+	case "$ne":
+		sb.WriteString("NOT ")
+		return equals(c.value)
+	case "$in":
+		return equalsAny(c.value.([]interface{}))
+	case "$nin":
+		sb.WriteString("NOT ")
+		return equalsAny(c.value.([]interface{}))
+	}
+
+	comparator := map[string]string{"$gt": ">", "$gte": ">=", "$lt": "<", "$lte": "<="}[c.op]
+	// CASE guards the cast: Postgres may evaluate AND operands in any order.
+	if bound, ok := c.value.(string); ok {
+		sb.WriteString("CASE WHEN jsonb_typeof(")
+		element()
+		sb.WriteString(") = 'string' THEN (")
+		element()
+		sb.WriteString(` #>> '{}') COLLATE "C" ` + comparator + ` ? ELSE FALSE END`)
+		*args = append(*args, bound)
+		return nil
+	}
+	bound, _ := filterNumber(c.value)
+	sb.WriteString("CASE WHEN jsonb_typeof(")
+	element()
+	sb.WriteString(") = 'number' THEN (")
+	element()
+	sb.WriteString(")::numeric " + comparator + " ? ELSE FALSE END")
+	*args = append(*args, bound)
+	return nil
+}
+
+// nestFilterValue wraps value in objects along path, the document a containment test
+// for that path looks for.
+func nestFilterValue(path []string, value interface{}) map[string]interface{} {
+	document := map[string]interface{}{path[len(path)-1]: value}
+	for i := len(path) - 2; i >= 0; i-- {
+		document = map[string]interface{}{path[i]: document}
+	}
+	return document
+}
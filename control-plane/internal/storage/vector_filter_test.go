@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeFilter(t *testing.T, raw string) map[string]interface{} {
+	t.Helper()
+	var filters map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(raw), &filters))
+	return filters
+}
+
+func TestMetadataFilter_Matches(t *testing.T) {
+	metadata := decodeFilter(t, `{
+		"tenant": "acme",
+		"year": 2024,
+		"published": "2024-03-01T00:00:00Z",
+		"doc": {"type": "pdf", "pages": 12},
+		"deleted": null
+	}`)
+
+	cases := []struct {
+		filter string
+		want   bool
+	}{
+		{`{}`, true},
+		{`{"tenant": "acme"}`, true},
+		{`{"tenant": "other"}`, false},
+		{`{"year": "2024"}`, false},
+		{`{"year": 2024.0}`, true},
+		{`{"doc": {"type": "pdf"}}`, false},
+		{`{"doc": {"type": "pdf", "pages": 12}}`, true},
+		{`{"deleted": null}`, true},
+		{`{"year": {"$in": ["2024"]}}`, false},
+		{`{"year": {"$nin": ["2024"]}}`, true},
+		{`{"doc.type": "pdf"}`, true},
+		{`{"doc.type": {"$in": ["html", "pdf"]}}`, true},
+		{`{"doc.type": {"$nin": ["html", "pdf"]}}`, false},
+		{`{"doc.missing": {"$nin": ["pdf"]}}`, true},
+		{`{"doc.pages": {"$gt": 10, "$lte": 12}}`, true},
+		{`{"doc.pages": {"$gt": 12}}`, false},
+		{`{"doc.type": {"$gt": 1}}`, false},
+		{`{"published": {"$gte": "2024-01-01", "$lt": "2025-01-01"}}`, true},
+		{`{"published": {"$lt": "2024-01-01"}}`, false},
+		{`{"deleted": {"$exists": true}}`, true},
+		{`{"archived": {"$exists": false}}`, true},
+		{`{"tenant": {"$ne": "acme"}}`, false},
+		{`{"archived": {"$ne": true}}`, true},
+		{`{"$or": [{"tenant": "other"}, {"year": {"$gte": 2024}}]}`, true},
+		{`{"$or": [{"tenant": "other"}, {"year": {"$lt": 2024}}]}`, false},
+		{`{"$and": [{"tenant": "acme"}, {"$or": [{"doc.type": "html"}, {"doc.pages": 12}]}]}`, true},
+		{`{"tenant": "acme", "$or": [{"doc.type": "html"}]}`, false},
+	}
+	for _, tc := range cases {
+		filter, err := compileMetadataFilter(decodeFilter(t, tc.filter))
+		require.NoError(t, err, tc.filter)
+		require.Equal(t, tc.want, filter.matches(metadata), tc.filter)
+	}
+}
+
+func TestValidateVectorFilter_RejectsMalformedFilters(t *testing.T) {
+	for _, raw := range []string{
+		`{"$not": {"tenant": "acme"}}`,
+		`{"year": {"$regex": "^20"}}`,
+		`{"year": {"$gt": 2020, "month": 1}}`,
+		`{"year": {"$gt": true}}`,
+		`{"tags": {"$in": "a"}}`,
+		`{"tags": {"$exists": "yes"}}`,
+		`{"$or": []}`,
+		`{"$or": [1]}`,
+		`{"doc..type": "pdf"}`,
+	} {
+		require.Error(t, ValidateVectorFilter(decodeFilter(t, raw)), raw)
+	}
+	require.NoError(t, ValidateVectorFilter(nil))
+}
+
+func TestPostgresFilterSQL(t *testing.T) {
+	filter, err := compileMetadataFilter(decodeFilter(t, `{
+		"tenant": "acme",
+		"doc.pages": {"$gte": 10},
+		"$or": [{"published": {"$lt": "2025"}}, {"draft": {"$exists": false}}],
+		"kind": {"$nin": []}
+	}`))
+	require.NoError(t, err)
+
+	condition, args, err := postgresFilterSQL(filter, "mv.metadata")
+	require.NoError(t, err)
+	require.Equal(t, `(`+
+		`((CASE WHEN jsonb_typeof(mv.metadata -> ?) = 'string' THEN (mv.metadata -> ? #>> '{}') COLLATE "C" < ? ELSE FALSE END) OR ((mv.metadata -> ?) IS NULL))`+
+		` AND CASE WHEN jsonb_typeof(mv.metadata -> ? -> ?) = 'number' THEN (mv.metadata -> ? -> ?)::numeric >= ? ELSE FALSE END`+
+		` AND NOT FALSE`+
+		` AND (mv.metadata @> ?::jsonb AND (mv.metadata -> ?) = ?::jsonb))`, condition)
+	require.Equal(t, []interface{}{
+		"published", "published", "2025", "draft",
+		"doc", "pages", "doc", "pages", float64(10),
+		`{"tenant":"acme"}`, "tenant", `"acme"`,
+	}, args)
+}
+
+func TestLocalStorage_SimilaritySearchWithFilterOperators(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+	ls := provider.(*LocalStorage)
+
+	for i, meta := range []string{
+		`{"tenant": "acme", "doc": {"type": "pdf"}, "published": "2023-06-01"}`,
+		`{"tenant": "acme", "doc": {"type": "html"}, "published": "2024-02-01"}`,
+		`{"tenant": "globex", "doc": {"type": "pdf"}, "published": "2024-05-01"}`,
+	} {
+		require.NoError(t, ls.SetVector(ctx, &types.VectorRecord{
+			Scope: "global", ScopeID: "global", Key: []string{"a", "b", "c"}[i],
+			Embedding: []float32{1, float32(i)}, Metadata: decodeFilter(t, meta),
+		}))
+	}
+
+	results, err := ls.SimilaritySearch(ctx, "global", "global", []float32{1, 0}, 10, decodeFilter(t, `{
+		"$or": [{"tenant": "globex"}, {"doc.type": "html"}],
+		"published": {"$gte": "2024-01-01"}
+	}`))
+	require.NoError(t, err)
+	keys := []string{}
+	for _, result := range results {
+		keys = append(keys, result.Key)
+	}
+	require.ElementsMatch(t, []string{"b", "c"}, keys)
+
+	_, err = ls.SimilaritySearch(ctx, "global", "global", []float32{1, 0}, 10, decodeFilter(t, `{"tenant": {"$like": "a%"}}`))
+	require.ErrorContains(t, err, "unsupported filter operator")
+}
+
+// assertVectorFilterSemantics runs the same filters against a backend, so every backend
+// is held to the one definition of filter equality.
+func assertVectorFilterSemantics(t *testing.T, ctx context.Context, ls *LocalStorage, scopeID string) {
+	t.Helper()
+	for i, meta := range []string{
+		`{"year": 2024, "tags": ["a", "b"], "doc": {"type": "pdf", "pages": 3}}`,
+		`{"year": "2024", "tags": ["a"], "doc": {"type": "pdf"}}`,
+		`{"year": 2024.0, "tags": "a", "draft": null}`,
+	} {
+		require.NoError(t, ls.SetVector(ctx, &types.VectorRecord{
+			Scope: "global", ScopeID: scopeID, Key: []string{"a", "b", "c"}[i],
+			Embedding: []float32{1, float32(i)}, Metadata: decodeFilter(t, meta),
+		}))
+	}
+
+	cases := []struct {
+		filter string
+		want   []string
+	}{
+		{`{"year": 2024}`, []string{"a", "c"}},
+		{`{"year": "2024"}`, []string{"b"}},
+		{`{"year": {"$ne": 2024}}`, []string{"b"}},
+		{`{"year": {"$in": ["2024", 1999]}}`, []string{"b"}},
+		{`{"year": {"$nin": [2024]}}`, []string{"b"}},
+		{`{"tags": ["a"]}`, []string{"b"}},
+		{`{"tags": "a"}`, []string{"c"}},
+		{`{"doc": {"type": "pdf"}}`, []string{"b"}},
+		{`{"doc.type": "pdf"}`, []string{"a", "b"}},
+		{`{"draft": null}`, []string{"c"}},
+		{`{"draft": {"$ne": null}}`, []string{"a", "b"}},
+	}
+	for _, tc := range cases {
+		results, err := ls.SimilaritySearch(ctx, "global", scopeID, []float32{1, 0}, 10, decodeFilter(t, tc.filter))
+		require.NoError(t, err, tc.filter)
+		keys := []string{}
+		for _, result := range results {
+			keys = append(keys, result.Key)
+		}
+		require.ElementsMatch(t, tc.want, keys, tc.filter)
+	}
+}
+
+func TestLocalStorage_VectorFilterSemantics(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+	assertVectorFilterSemantics(t, ctx, provider.(*LocalStorage), "filter-semantics")
+}
+
+func TestPostgresStorage_VectorFilterSemantics(t *testing.T) {
+	postgresURL := os.Getenv("POSTGRES_TEST_URL")
+	if postgresURL == "" {
+		t.Skip("POSTGRES_TEST_URL not set, skipping postgres tests")
+	}
+
+	ctx := context.Background()
+	ls := NewPostgresStorage(PostgresStorageConfig{})
+	err := ls.Initialize(ctx, StorageConfig{Mode: "postgres", Postgres: PostgresStorageConfig{DSN: postgresURL}})
+	if err != nil {
+		if strings.Contains(err.Error(), "connection refused") || strings.Contains(err.Error(), "does not exist") {
+			t.Skip("PostgreSQL not available, skipping test")
+		}
+		require.NoError(t, err)
+	}
+	t.Cleanup(func() { _ = ls.Close(ctx) })
+	assertVectorFilterSemantics(t, ctx, ls, fmt.Sprintf("filter-semantics-%d", time.Now().UnixNano()))
+}
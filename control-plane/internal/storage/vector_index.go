@@ -0,0 +1,479 @@
+package storage
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
+)
+
+// hnswGraph is a hierarchical navigable small world graph over the vectors of one
+// partition. Replaced and deleted vectors stay in the graph as tombstones that searches
+// route through but never return; the graph is compacted once they outnumber the live
+// vectors.
+type hnswGraph struct {
+	metric         VectorDistanceMetric
+	m              int
+	efConstruction int
+	levelMult      float64
+	rng            *rand.Rand
+
+	nodes    []*hnswNode
+	ids      map[string]int32
+	entry    int32
+	maxLevel int
+	deleted  int
+}
+
+type hnswNode struct {
+	key       string
+	vector    []float32
+	neighbors [][]int32
+	deleted   bool
+}
+
+type hnswCandidate struct {
+	id       int32
+	distance float64
+}
+
+func newHNSWGraph(metric VectorDistanceMetric, m, efConstruction int) *hnswGraph {
+	return &hnswGraph{
+		metric:         metric,
+		m:              m,
+		efConstruction: efConstruction,
+		levelMult:      1 / math.Log(float64(m)),
+		rng:            rand.New(rand.NewSource(1)),
+		ids:            make(map[string]int32),
+		entry:          -1,
+	}
+}
+
+// Len returns the number of live vectors.
+func (g *hnswGraph) Len() int {
+	return len(g.ids)
+}
+
+func (g *hnswGraph) distance(a, b []float32) float64 {
+	_, distance := computeSimilarity(g.metric, a, b)
+	return distance
+}
+
+// maxNeighbors is the neighbour budget of a node on level; the base layer gets twice
+// as many links.
+func (g *hnswGraph) maxNeighbors(level int) int {
+	if level == 0 {
+		return 2 * g.m
+	}
+	return g.m
+}
+
+// Upsert inserts the vector for key, replacing any previous one.
+func (g *hnswGraph) Upsert(key string, vector []float32) {
+	g.Delete(key)
+
+	level := int(-math.Log(1-g.rng.Float64()) * g.levelMult)
+	id := int32(len(g.nodes))
+	node := &hnswNode{key: key, vector: vector, neighbors: make([][]int32, level+1)}
+	g.nodes = append(g.nodes, node)
+	g.ids[key] = id
+
+	if g.entry < 0 {
+		g.entry, g.maxLevel = id, level
+		return
+	}
+
+	entry := g.entry
+	for l := g.maxLevel; l > level; l-- {
+		entry = g.greedyClosest(vector, entry, l)
+	}
+	for l := min(level, g.maxLevel); l >= 0; l-- {
+		candidates := g.searchLayer(vector, entry, g.efConstruction, l)
+		neighbors := g.selectNeighbors(candidates, g.maxNeighbors(l))
+		node.neighbors[l] = neighbors
+		for _, neighbor := range neighbors {
+			g.link(neighbor, id, l)
+		}
+		entry = candidates[0].id
+	}
+	if level > g.maxLevel {
+		g.entry, g.maxLevel = id, level
+	}
+}
+
+// link adds a back edge from -> to, re-selecting from's neighbours when over budget.
+func (g *hnswGraph) link(from, to int32, level int) {
+	node := g.nodes[from]
+	node.neighbors[level] = append(node.neighbors[level], to)
+	if len(node.neighbors[level]) <= g.maxNeighbors(level) {
+		return
+	}
+	candidates := make([]hnswCandidate, len(node.neighbors[level]))
+	for i, id := range node.neighbors[level] {
+		candidates[i] = hnswCandidate{id: id, distance: g.distance(node.vector, g.nodes[id].vector)}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+	node.neighbors[level] = g.selectNeighbors(candidates, g.maxNeighbors(level))
+}
+
+// selectNeighbors picks up to n of candidates, nearest first, skipping any that is
+// closer to an already picked neighbour than to the base vector. Keeping links that
+// point in different directions lets searches reach outlying vectors.
+func (g *hnswGraph) selectNeighbors(candidates []hnswCandidate, n int) []int32 {
+	selected := make([]int32, 0, n)
+	for _, candidate := range candidates {
+		if len(selected) == n {
+			break
+		}
+		vector := g.nodes[candidate.id].vector
+		diverse := true
+		for _, id := range selected {
+			if g.distance(vector, g.nodes[id].vector) < candidate.distance {
+				diverse = false
+				break
+			}
+		}
+		if diverse {
+			selected = append(selected, candidate.id)
+		}
+	}
+	return selected
+}
+
+// Delete tombstones the vector stored for key.
+func (g *hnswGraph) Delete(key string) {
+	g.remove(key)
+	g.maybeCompact()
+}
+
+// DeletePrefix tombstones every vector whose key starts with prefix.
+func (g *hnswGraph) DeletePrefix(prefix string) {
+	for key := range g.ids {
+		if strings.HasPrefix(key, prefix) {
+			g.remove(key)
+		}
+	}
+	g.maybeCompact()
+}
+
+func (g *hnswGraph) remove(key string) {
+	id, ok := g.ids[key]
+	if !ok {
+		return
+	}
+	g.nodes[id].deleted = true
+	delete(g.ids, key)
+	g.deleted++
+}
+
+func (g *hnswGraph) maybeCompact() {
+	if g.deleted > len(g.ids) && len(g.nodes) >= 64 {
+		g.compact()
+	}
+}
+
+// compact rebuilds the graph from its live vectors.
+func (g *hnswGraph) compact() {
+	live := make([]*hnswNode, 0, len(g.ids))
+	for _, node := range g.nodes {
+		if !node.deleted {
+			live = append(live, node)
+		}
+	}
+	g.nodes, g.ids, g.entry, g.maxLevel, g.deleted = nil, make(map[string]int32, len(live)), -1, 0, 0
+	for _, node := range live {
+		g.Upsert(node.key, node.vector)
+	}
+}
+
+// Search returns up to k live vectors closest to query, examining at least ef candidates.
+func (g *hnswGraph) Search(query []float32, k, ef int) []hnswCandidate {
+	if g.entry < 0 || k <= 0 {
+		return nil
+	}
+	entry := g.entry
+	for l := g.maxLevel; l > 0; l-- {
+		entry = g.greedyClosest(query, entry, l)
+	}
+
+	// Tombstones take up candidate slots, so widen the search by their share.
+	if g.deleted > 0 && len(g.ids) > 0 {
+		ef += ef * g.deleted / len(g.ids)
+	}
+	candidates := g.searchLayer(query, entry, max(ef, k), 0)
+	results := make([]hnswCandidate, 0, k)
+	for _, candidate := range candidates {
+		if g.nodes[candidate.id].deleted {
+			continue
+		}
+		results = append(results, candidate)
+		if len(results) == k {
+			break
+		}
+	}
+	return results
+}
+
+func (g *hnswGraph) greedyClosest(query []float32, entry int32, level int) int32 {
+	best := entry
+	bestDistance := g.distance(query, g.nodes[entry].vector)
+	for improved := true; improved; {
+		improved = false
+		for _, neighbor := range g.nodes[best].neighbors[level] {
+			if distance := g.distance(query, g.nodes[neighbor].vector); distance < bestDistance {
+				best, bestDistance, improved = neighbor, distance, true
+			}
+		}
+	}
+	return best
+}
+
+// searchLayer returns the ef nodes on level closest to query, nearest first.
+func (g *hnswGraph) searchLayer(query []float32, entry int32, ef, level int) []hnswCandidate {
+	start := hnswCandidate{id: entry, distance: g.distance(query, g.nodes[entry].vector)}
+	visited := map[int32]struct{}{entry: {}}
+	frontier := &candidateHeap{less: func(a, b float64) bool { return a < b }}
+	nearest := &candidateHeap{less: func(a, b float64) bool { return a > b }}
+	heap.Push(frontier, start)
+	heap.Push(nearest, start)
+
+	for frontier.Len() > 0 {
+		current := heap.Pop(frontier).(hnswCandidate)
+		if current.distance > nearest.items[0].distance && nearest.Len() >= ef {
+			break
+		}
+		node := g.nodes[current.id]
+		if level >= len(node.neighbors) {
+			continue
+		}
+		for _, neighbor := range node.neighbors[level] {
+			if _, seen := visited[neighbor]; seen {
+				continue
+			}
+			visited[neighbor] = struct{}{}
+			candidate := hnswCandidate{id: neighbor, distance: g.distance(query, g.nodes[neighbor].vector)}
+			if nearest.Len() < ef || candidate.distance < nearest.items[0].distance {
+				heap.Push(frontier, candidate)
+				heap.Push(nearest, candidate)
+				if nearest.Len() > ef {
+					heap.Pop(nearest)
+				}
+			}
+		}
+	}
+
+	results := nearest.items
+	sort.Slice(results, func(i, j int) bool { return results[i].distance < results[j].distance })
+	return results
+}
+
+// candidateHeap orders candidates by distance; less picks a min- or max-heap.
+type candidateHeap struct {
+	items []hnswCandidate
+	less  func(a, b float64) bool
+}
+
+func (h *candidateHeap) Len() int           { return len(h.items) }
+func (h *candidateHeap) Less(i, j int) bool { return h.less(h.items[i].distance, h.items[j].distance) }
+func (h *candidateHeap) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *candidateHeap) Push(x interface{}) { h.items = append(h.items, x.(hnswCandidate)) }
+func (h *candidateHeap) Pop() interface{} {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
+
+// vectorPartition identifies the vectors one graph holds. Searches only compare vectors
+// of the query's dimension, so each dimension gets its own graph.
+type vectorPartition struct {
+	scope     string
+	scopeID   string
+	dimension int
+}
+
+// vectorIndexOp is a write the index has to apply, recorded while a rebuild runs.
+type vectorIndexOp struct {
+	partition vectorPartition
+	key       string
+	vector    []float32
+	prefix    bool
+}
+
+// vectorIndex keeps an approximate nearest-neighbour graph per partition of
+// memory_vectors. It is rebuilt from the table on startup; until that finishes it
+// reports itself not ready and searches scan the table instead. A failed rebuild
+// leaves it not ready for good, and writes are no longer recorded.
+type vectorIndex struct {
+	config VectorIndexConfig
+	metric VectorDistanceMetric
+
+	mu         sync.RWMutex
+	ready      bool
+	failed     bool
+	partitions map[vectorPartition]*hnswGraph
+	pending    []vectorIndexOp
+}
+
+func newVectorIndex(config VectorIndexConfig, metric VectorDistanceMetric) *vectorIndex {
+	return &vectorIndex{
+		config:     config,
+		metric:     metric,
+		partitions: make(map[vectorPartition]*hnswGraph),
+	}
+}
+
+func (idx *vectorIndex) newGraph() *hnswGraph {
+	return newHNSWGraph(idx.metric, idx.config.M, idx.config.EfConstruction)
+}
+
+// Upsert records the vector stored for key.
+func (idx *vectorIndex) Upsert(scope, scopeID, key string, vector []float32) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.apply(vectorIndexOp{partition: vectorPartition{scope, scopeID, len(vector)}, key: key, vector: vector})
+}
+
+// Delete forgets the vector stored for key, whatever its dimension.
+func (idx *vectorIndex) Delete(scope, scopeID, key string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.apply(vectorIndexOp{partition: vectorPartition{scope: scope, scopeID: scopeID}, key: key})
+}
+
+// DeletePrefix forgets the vectors whose keys start with prefix.
+func (idx *vectorIndex) DeletePrefix(scope, scopeID, prefix string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.apply(vectorIndexOp{partition: vectorPartition{scope: scope, scopeID: scopeID}, key: prefix, prefix: true})
+}
+
+// apply performs op, or queues it for the rebuild in progress. Callers hold mu.
+func (idx *vectorIndex) apply(op vectorIndexOp) {
+	if idx.failed {
+		return
+	}
+	if !idx.ready {
+		idx.pending = append(idx.pending, op)
+		return
+	}
+	applyVectorIndexOp(idx.partitions, op, idx.newGraph)
+}
+
+func applyVectorIndexOp(partitions map[vectorPartition]*hnswGraph, op vectorIndexOp, newGraph func() *hnswGraph) {
+	if op.vector != nil {
+		// A key lives in one partition at a time, so drop it from other dimensions first.
+		for partition, graph := range partitions {
+			if partition.scope == op.partition.scope && partition.scopeID == op.partition.scopeID && partition != op.partition {
+				graph.Delete(op.key)
+			}
+		}
+		graph, ok := partitions[op.partition]
+		if !ok {
+			graph = newGraph()
+			partitions[op.partition] = graph
+		}
+		graph.Upsert(op.key, op.vector)
+		return
+	}
+
+	for partition, graph := range partitions {
+		if partition.scope != op.partition.scope || partition.scopeID != op.partition.scopeID {
+			continue
+		}
+		if op.prefix {
+			graph.DeletePrefix(op.key)
+		} else {
+			graph.Delete(op.key)
+		}
+	}
+}
+
+// vectorIndexHit is a stored vector the index found near a query.
+type vectorIndexHit struct {
+	key    string
+	vector []float32
+}
+
+// Search returns up to k approximate nearest neighbours of query, nearest first. ok is
+// false when the index cannot answer and the caller should scan instead: while the
+// index is being built, or when the partition is too small to be worth it.
+func (idx *vectorIndex) Search(scope, scopeID string, query []float32, k int) (hits []vectorIndexHit, ok bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if !idx.ready {
+		return nil, false
+	}
+	graph := idx.partitions[vectorPartition{scope, scopeID, len(query)}]
+	if graph == nil || graph.Len() < idx.config.MinVectors {
+		return nil, false
+	}
+
+	for _, candidate := range graph.Search(query, k, max(idx.config.EfSearch, k)) {
+		node := graph.nodes[candidate.id]
+		hits = append(hits, vectorIndexHit{key: node.key, vector: node.vector})
+	}
+	return hits, true
+}
+
+// Rebuild loads every stored vector into fresh graphs and swaps them in. Writes made
+// while it runs are replayed on top, so the result matches the table. When it fails
+// the index stays not ready and drops the writes it queued.
+func (idx *vectorIndex) Rebuild(ctx context.Context, db *sqlDatabase) error {
+	idx.mu.Lock()
+	idx.ready, idx.failed = false, false
+	idx.pending = nil
+	idx.mu.Unlock()
+
+	partitions, count, err := idx.load(ctx, db)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if err != nil {
+		// No build will replay the queue, so stop growing it.
+		idx.failed, idx.pending = true, nil
+		return err
+	}
+	for _, op := range idx.pending {
+		applyVectorIndexOp(partitions, op, idx.newGraph)
+	}
+	idx.partitions, idx.pending, idx.ready = partitions, nil, true
+
+	logger.Logger.Info().Int("vectors", count).Int("partitions", len(partitions)).Msg("vector index built")
+	return nil
+}
+
+// load reads every stored vector into fresh graphs.
+func (idx *vectorIndex) load(ctx context.Context, db *sqlDatabase) (map[vectorPartition]*hnswGraph, int, error) {
+	partitions := make(map[vectorPartition]*hnswGraph)
+	rows, err := db.QueryContext(ctx, `SELECT scope, scope_id, key, embedding FROM memory_vectors`)
+	if err != nil {
+		return nil, 0, fmt.Errorf("load vectors for index: %w", err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var scope, scopeID, key string
+		var blob []byte
+		if err := rows.Scan(&scope, &scopeID, &key, &blob); err != nil {
+			return nil, 0, fmt.Errorf("scan vector row: %w", err)
+		}
+		vector, err := decodeEmbedding(blob)
+		if err != nil || len(vector) == 0 {
+			continue
+		}
+		applyVectorIndexOp(partitions, vectorIndexOp{partition: vectorPartition{scope, scopeID, len(vector)}, key: key, vector: vector}, idx.newGraph)
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate vector rows: %w", err)
+	}
+	return partitions, count, nil
+}
@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func randomVector(rng *rand.Rand, dimension int) []float32 {
+	vec := make([]float32, dimension)
+	for i := range vec {
+		vec[i] = rng.Float32()*2 - 1
+	}
+	return vec
+}
+
+func TestHNSWGraph_RecallAgainstExactSearch(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	const dimension, count, queries, k = 32, 2000, 50, 10
+
+	graph := newHNSWGraph(VectorDistanceCosine, 16, 200)
+	results := make([]*types.VectorSearchResult, 0, count)
+	vectors := make(map[string][]float32, count)
+	for i := 0; i < count; i++ {
+		key := fmt.Sprintf("chunk-%d", i)
+		vectors[key] = randomVector(rng, dimension)
+		graph.Upsert(key, vectors[key])
+	}
+
+	found := 0
+	for q := 0; q < queries; q++ {
+		query := randomVector(rng, dimension)
+		results = results[:0]
+		for key, vec := range vectors {
+			score, distance := computeSimilarity(VectorDistanceCosine, query, vec)
+			results = append(results, &types.VectorSearchResult{Key: key, Score: score, Distance: distance})
+		}
+		exact := map[string]bool{}
+		for _, result := range sortAndLimit(results, k) {
+			exact[result.Key] = true
+		}
+		for _, candidate := range graph.Search(query, k, 64) {
+			if exact[graph.nodes[candidate.id].key] {
+				found++
+			}
+		}
+	}
+
+	recall := float64(found) / float64(queries*k)
+	require.GreaterOrEqual(t, recall, 0.9, "recall@%d was %.2f", k, recall)
+}
+
+func TestHNSWGraph_ReplaceDeleteAndCompact(t *testing.T) {
+	graph := newHNSWGraph(VectorDistanceL2, 8, 50)
+	for i := 0; i < 100; i++ {
+		graph.Upsert(fmt.Sprintf("doc-%d", i), []float32{float32(i), 0})
+	}
+	require.Equal(t, 100, graph.Len())
+
+	graph.Upsert("doc-5", []float32{500, 0})
+	nearest := graph.Search([]float32{5, 0}, 1, 16)
+	require.Len(t, nearest, 1)
+	require.NotEqual(t, "doc-5", graph.nodes[nearest[0].id].key, "the replaced vector must not be returned")
+	nearest = graph.Search([]float32{500, 0}, 1, 16)
+	require.Equal(t, "doc-5", graph.nodes[nearest[0].id].key)
+
+	graph.Delete("doc-7")
+	for _, candidate := range graph.Search([]float32{7, 0}, 5, 16) {
+		require.NotEqual(t, "doc-7", graph.nodes[candidate.id].key)
+	}
+
+	graph.DeletePrefix("doc-1")
+	require.Equal(t, 88, graph.Len())
+
+	for i := 20; i < 80; i++ {
+		graph.Delete(fmt.Sprintf("doc-%d", i))
+	}
+	require.Equal(t, 28, graph.Len())
+	require.LessOrEqual(t, graph.deleted, graph.Len(), "tombstones outnumbering live vectors trigger compaction")
+	require.Len(t, graph.nodes, graph.Len()+graph.deleted)
+
+	nearest = graph.Search([]float32{85, 0}, 1, 16)
+	require.Equal(t, "doc-85", graph.nodes[nearest[0].id].key)
+}
+
+func waitForVectorIndex(t *testing.T, store *sqliteVectorStore) {
+	t.Helper()
+	select {
+	case <-store.indexDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("vector index build did not finish")
+	}
+}
+
+func TestSQLiteVectorStore_IndexedSearch(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+	ls := provider.(*LocalStorage)
+
+	store := newSQLiteVectorStore(ls.db, VectorDistanceCosine)
+	require.NoError(t, store.Set(ctx, &types.VectorRecord{
+		Scope: "session", ScopeID: "rag", Key: "before-build",
+		Embedding: []float32{1, 0, 0}, Metadata: map[string]interface{}{"source": "wiki"},
+	}))
+
+	store.enableIndex(VectorStoreConfig{}.normalized().Index)
+	store.index.config.MinVectors = 1
+	t.Cleanup(store.stopIndex)
+	waitForVectorIndex(t, store)
+
+	records := []*types.VectorRecord{
+		{Key: "north", Embedding: []float32{0, 1, 0}, Metadata: map[string]interface{}{"source": "wiki"}},
+		{Key: "north-east", Embedding: []float32{0.7, 0.7, 0}, Metadata: map[string]interface{}{"source": "blog"}},
+		{Key: "up", Embedding: []float32{0, 0, 1}, Metadata: map[string]interface{}{"source": "wiki"}},
+	}
+	for _, record := range records {
+		record.Scope, record.ScopeID = "session", "rag"
+		require.NoError(t, store.Set(ctx, record))
+	}
+
+	results, err := store.Search(ctx, "session", "rag", []float32{0.9, 0.1, 0}, 2, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, "before-build", results[0].Key, "rows stored before the build are indexed")
+	require.Equal(t, "north-east", results[1].Key)
+	require.Equal(t, "wiki", results[0].Metadata["source"])
+	require.False(t, results[0].CreatedAt.IsZero())
+
+	exact, err := store.scan(ctx, "session", "rag", []float32{0.9, 0.1, 0}, 2, nil)
+	require.NoError(t, err)
+	require.Equal(t, exact[0].Score, results[0].Score)
+
+	filtered, err := store.Search(ctx, "session", "rag", []float32{0.7, 0.7, 0}, 1, map[string]interface{}{"source": "wiki"})
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	require.NotEqual(t, "north-east", filtered[0].Key)
+
+	require.NoError(t, store.Delete(ctx, "session", "rag", "before-build"))
+	_, err = store.DeleteByPrefix(ctx, "session", "rag", "north")
+	require.NoError(t, err)
+	results, err = store.Search(ctx, "session", "rag", []float32{0.9, 0.1, 0}, 3, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "up", results[0].Key)
+}
+
+func TestVectorIndex_FailedRebuildStopsQueuingWrites(t *testing.T) {
+	provider, _ := setupTestStorage(t)
+	ls := provider.(*LocalStorage)
+	idx := newVectorIndex(VectorStoreConfig{}.normalized().Index, VectorDistanceCosine)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Error(t, idx.Rebuild(cancelled, ls.db))
+
+	for i := 0; i < 10; i++ {
+		idx.Upsert("session", "rag", fmt.Sprintf("key-%d", i), []float32{1, 0})
+	}
+	require.Empty(t, idx.pending, "no build will replay queued writes")
+	_, ok := idx.Search("session", "rag", []float32{1, 0}, 1)
+	require.False(t, ok, "searches scan the table")
+
+	// A later rebuild brings the index back.
+	require.NoError(t, idx.Rebuild(context.Background(), ls.db))
+	idx.Upsert("session", "rag", "after", []float32{1, 0})
+	require.Equal(t, 1, idx.partitions[vectorPartition{"session", "rag", 2}].Len())
+}
+
+func TestLocalStorage_RejectsUnknownVectorIndex(t *testing.T) {
+	provider, _ := setupTestStorage(t)
+	ls := provider.(*LocalStorage)
+
+	ls.vectorConfig.Index.Type = "ivf"
+	require.ErrorContains(t, ls.initializeVectorStore(), "unsupported vector index type")
+}
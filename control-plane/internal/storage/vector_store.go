@@ -2,7 +2,9 @@ package storage
 
 import (
 	"context"
+	"database/sql"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -19,6 +21,8 @@ type vectorStore interface {
 	Delete(ctx context.Context, scope, scopeID, key string) error
 	DeleteByPrefix(ctx context.Context, scope, scopeID, prefix string) (int, error)
 	Search(ctx context.Context, scope, scopeID string, query []float32, topK int, filters map[string]interface{}) ([]*types.VectorSearchResult, error)
+	KeywordSearch(ctx context.Context, scope, scopeID, text string, topK int, filters map[string]interface{}) ([]*types.VectorSearchResult, error)
+	Export(ctx context.Context, filter types.MemorySnapshotFilter, emit func(*types.VectorRecord) error) error
 }
 
 type VectorDistanceMetric string
@@ -139,20 +143,148 @@ func sortAndLimit(results []*types.VectorSearchResult, topK int) []*types.Vector
 	return results
 }
 
-func metadataMatchesFilters(metadata map[string]interface{}, filters map[string]interface{}) bool {
-	if len(filters) == 0 {
-		return true
+const (
+	defaultRRFK = 60
+	// hybridCandidateFactor is how many results per requested result each search of a
+	// hybrid query contributes to the fusion.
+	hybridCandidateFactor = 4
+	minHybridCandidates   = 20
+)
+
+// hybridSearch answers query from store: by vector, by keyword, or by fusing both when
+// the query has an embedding and text.
+func hybridSearch(ctx context.Context, store vectorStore, scope, scopeID string, query types.VectorSearchQuery) ([]*types.VectorSearchResult, error) {
+	text := strings.TrimSpace(query.Text)
+	switch {
+	case text == "" && len(query.Embedding) == 0:
+		return nil, errors.New("a query embedding or query text is required")
+	case text == "":
+		return store.Search(ctx, scope, scopeID, query.Embedding, query.TopK, query.Filters)
+	case len(query.Embedding) == 0:
+		return store.KeywordSearch(ctx, scope, scopeID, text, query.TopK, query.Filters)
+	}
+
+	candidates := query.TopK * hybridCandidateFactor
+	if candidates < minHybridCandidates {
+		candidates = minHybridCandidates
+	}
+	vectorResults, err := store.Search(ctx, scope, scopeID, query.Embedding, candidates, query.Filters)
+	if err != nil {
+		return nil, err
+	}
+	keywordResults, err := store.KeywordSearch(ctx, scope, scopeID, text, candidates, query.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	k := query.RRFK
+	if k <= 0 {
+		k = defaultRRFK
+	}
+	keywordWeight := query.KeywordWeight
+	if keywordWeight <= 0 {
+		keywordWeight = 1
 	}
-	for key, expected := range filters {
-		actual, ok := metadata[key]
-		if !ok {
-			return false
+	return fuseRankings(vectorResults, keywordResults, k, keywordWeight, query.TopK), nil
+}
+
+// fuseRankings merges vector and keyword results, each ranked best first, with
+// reciprocal rank fusion: a result scores weight/(k+rank) for every list it appears in.
+func fuseRankings(vectorResults, keywordResults []*types.VectorSearchResult, k int, keywordWeight float64, topK int) []*types.VectorSearchResult {
+	fused := make(map[string]*types.VectorSearchResult, len(vectorResults)+len(keywordResults))
+	order := make([]*types.VectorSearchResult, 0, len(vectorResults)+len(keywordResults))
+	add := func(results []*types.VectorSearchResult, weight float64, vector bool) {
+		for rank, result := range results {
+			entry, ok := fused[result.Key]
+			if !ok {
+				copied := *result
+				entry = &copied
+				entry.Score = 0
+				fused[result.Key] = entry
+				order = append(order, entry)
+			}
+			score := result.Score
+			if vector {
+				entry.VectorScore = &score
+				entry.Distance = result.Distance
+			} else {
+				entry.KeywordScore = &score
+				if entry.Text == "" {
+					entry.Text = result.Text
+				}
+			}
+			entry.Score += weight / float64(k+rank+1)
 		}
-		if fmt.Sprintf("%v", actual) != fmt.Sprintf("%v", expected) {
-			return false
+	}
+	add(vectorResults, 1, true)
+	add(keywordResults, keywordWeight, false)
+
+	sort.SliceStable(order, func(i, j int) bool { return order[i].Score > order[j].Score })
+	if topK > 0 && len(order) > topK {
+		order = order[:topK]
+	}
+	return order
+}
+
+// keywordTerms splits a text query into the terms a keyword search matches, any of
+// which may match.
+func keywordTerms(text string) []string {
+	return strings.Fields(text)
+}
+
+// fts5KeywordQuery builds an FTS5 MATCH expression matching any term. Terms are quoted
+// as phrases so identifiers like "AB-1234" match as written rather than parse as
+// operators.
+func fts5KeywordQuery(text string) string {
+	terms := keywordTerms(text)
+	phrases := make([]string, len(terms))
+	for i, term := range terms {
+		phrases[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	return strings.Join(phrases, " OR ")
+}
+
+// vectorAccessColumns receives the access_level and access_control columns of a vector.
+type vectorAccessColumns struct {
+	level   sql.NullString
+	control sql.NullString
+}
+
+func (a *vectorAccessColumns) decode() (string, *types.AccessControlMetadata, error) {
+	if !a.control.Valid || a.control.String == "" {
+		return a.level.String, nil, nil
+	}
+	acl := &types.AccessControlMetadata{}
+	if err := json.Unmarshal([]byte(a.control.String), acl); err != nil {
+		return "", nil, fmt.Errorf("unmarshal access control: %w", err)
+	}
+	return a.level.String, acl, nil
+}
+
+// encodeVectorAccess returns the access_level and access_control column values of
+// record, NULL for a public vector.
+func encodeVectorAccess(record *types.VectorRecord) (interface{}, interface{}, error) {
+	var level, control interface{}
+	if record.AccessLevel != "" {
+		level = record.AccessLevel
+	}
+	if record.AccessControl != nil {
+		data, err := json.Marshal(record.AccessControl)
+		if err != nil {
+			return nil, nil, fmt.Errorf("marshal access control: %w", err)
 		}
+		control = string(data)
+	}
+	return level, control, nil
+}
+
+// nullableVectorText stores records without source text as NULL, keeping them out of
+// the keyword index.
+func nullableVectorText(text string) interface{} {
+	if strings.TrimSpace(text) == "" {
+		return nil
 	}
-	return true
+	return text
 }
 
 func nowUTC() time.Time {
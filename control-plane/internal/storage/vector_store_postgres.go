@@ -38,12 +38,20 @@ func (s *postgresVectorStore) Set(ctx context.Context, record *types.VectorRecor
 		return fmt.Errorf("marshal metadata: %w", err)
 	}
 
+	accessLevel, accessControl, err := encodeVectorAccess(record)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO memory_vectors (scope, scope_id, key, embedding, metadata, created_at, updated_at)
-		VALUES (?, ?, ?, ?::vector, ?::jsonb, ?, ?)
+		INSERT INTO memory_vectors (scope, scope_id, key, embedding, metadata, text, access_level, access_control, created_at, updated_at)
+		VALUES (?, ?, ?, ?::vector, ?::jsonb, ?, ?, ?::jsonb, ?, ?)
 		ON CONFLICT(scope, scope_id, key) DO UPDATE SET
 			embedding = excluded.embedding,
 			metadata = excluded.metadata,
+			text = excluded.text,
+			access_level = excluded.access_level,
+			access_control = excluded.access_control,
 			updated_at = excluded.updated_at
 	`
 
@@ -56,6 +64,9 @@ func (s *postgresVectorStore) Set(ctx context.Context, record *types.VectorRecor
 		record.Key,
 		vectorLiteral(record.Embedding),
 		string(metaJSON),
+		nullableVectorText(record.Text),
+		accessLevel,
+		accessControl,
 		now,
 		now,
 	)
@@ -71,16 +82,18 @@ func (s *postgresVectorStore) Get(ctx context.Context, scope, scopeID, key strin
 	}
 
 	query := `
-		SELECT embedding::text, metadata, created_at, updated_at
+		SELECT embedding::text, metadata, text, access_level, access_control, created_at, updated_at
 		FROM memory_vectors
 		WHERE scope = ? AND scope_id = ? AND key = ?
 	`
 
 	var embeddingStr string
 	var metadataRaw []byte
+	var text sql.NullString
+	var access vectorAccessColumns
 	var createdAt, updatedAt time.Time
 
-	err := s.db.QueryRowContext(ctx, query, scope, scopeID, key).Scan(&embeddingStr, &metadataRaw, &createdAt, &updatedAt)
+	err := s.db.QueryRowContext(ctx, query, scope, scopeID, key).Scan(&embeddingStr, &metadataRaw, &text, &access.level, &access.control, &createdAt, &updatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -88,25 +101,26 @@ func (s *postgresVectorStore) Get(ctx context.Context, scope, scopeID, key strin
 		return nil, fmt.Errorf("get postgres vector: %w", err)
 	}
 
-	// Parse postgres vector literal like "[0.1,0.2,0.3]"
-	embeddingStr = strings.Trim(embeddingStr, "[]")
-	parts := strings.Split(embeddingStr, ",")
-	embedding := make([]float32, len(parts))
-	for i, p := range parts {
-		v, err := strconv.ParseFloat(strings.TrimSpace(p), 32)
-		if err != nil {
-			return nil, fmt.Errorf("parse embedding element: %w", err)
-		}
-		embedding[i] = float32(v)
+	embedding, err := parsePostgresVector(embeddingStr)
+	if err != nil {
+		return nil, err
+	}
+
+	accessLevel, accessControl, err := access.decode()
+	if err != nil {
+		return nil, err
 	}
 
 	record := &types.VectorRecord{
-		Scope:     scope,
-		ScopeID:   scopeID,
-		Key:       key,
-		Embedding: embedding,
-		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
+		Scope:         scope,
+		ScopeID:       scopeID,
+		Key:           key,
+		Embedding:     embedding,
+		Text:          text.String,
+		AccessLevel:   accessLevel,
+		AccessControl: accessControl,
+		CreatedAt:     createdAt,
+		UpdatedAt:     updatedAt,
 	}
 
 	if len(metadataRaw) > 0 {
@@ -118,6 +132,51 @@ func (s *postgresVectorStore) Get(ctx context.Context, scope, scopeID, key strin
 	return record, nil
 }
 
+func (s *postgresVectorStore) Export(ctx context.Context, filter types.MemorySnapshotFilter, emit func(*types.VectorRecord) error) error {
+	return exportPages(func(after *snapshotKey) ([]*types.VectorRecord, error) {
+		query, args := snapshotPageQuery(
+			"scope, scope_id, key, embedding::text, metadata, text, access_level, access_control, created_at, updated_at",
+			"memory_vectors", filter, after)
+		rows, err := s.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("export postgres vectors: %w", err)
+		}
+		defer rows.Close()
+
+		var page []*types.VectorRecord
+		for rows.Next() {
+			record := &types.VectorRecord{}
+			var embeddingStr string
+			var metadataRaw []byte
+			var text sql.NullString
+			var access vectorAccessColumns
+			if err := rows.Scan(&record.Scope, &record.ScopeID, &record.Key, &embeddingStr, &metadataRaw, &text,
+				&access.level, &access.control, &record.CreatedAt, &record.UpdatedAt); err != nil {
+				return nil, fmt.Errorf("scan postgres vector row: %w", err)
+			}
+			if record.Embedding, err = parsePostgresVector(embeddingStr); err != nil {
+				return nil, err
+			}
+			if len(metadataRaw) > 0 {
+				if err := json.Unmarshal(metadataRaw, &record.Metadata); err != nil {
+					return nil, fmt.Errorf("unmarshal metadata: %w", err)
+				}
+			}
+			record.Text = text.String
+			if record.AccessLevel, record.AccessControl, err = access.decode(); err != nil {
+				return nil, err
+			}
+			page = append(page, record)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("iterate postgres vectors: %w", err)
+		}
+		return page, nil
+	}, func(record *types.VectorRecord) snapshotKey {
+		return snapshotKey{record.Scope, record.ScopeID, record.Key}
+	}, emit)
+}
+
 func (s *postgresVectorStore) Delete(ctx context.Context, scope, scopeID, key string) error {
 	if err := ctx.Err(); err != nil {
 		return err
@@ -162,7 +221,7 @@ func (s *postgresVectorStore) Search(ctx context.Context, scope, scopeID string,
 
 	sb := strings.Builder{}
 	sb.WriteString("WITH query_vec AS (SELECT ?::vector AS qv) ")
-	sb.WriteString("SELECT mv.scope, mv.scope_id, mv.key, mv.metadata, mv.created_at, mv.updated_at, ")
+	sb.WriteString("SELECT mv.scope, mv.scope_id, mv.key, mv.metadata, mv.text, mv.access_level, mv.access_control, mv.created_at, mv.updated_at, ")
 	sb.WriteString(scoreExpr)
 	sb.WriteString(" AS score, ")
 	sb.WriteString(distanceExpr)
@@ -170,13 +229,8 @@ func (s *postgresVectorStore) Search(ctx context.Context, scope, scopeID string,
 
 	args := []interface{}{vectorLiteral(query), scope, scopeID}
 
-	for key, val := range filters {
-		filterJSON, err := json.Marshal(map[string]interface{}{key: val})
-		if err != nil {
-			return nil, fmt.Errorf("marshal filter: %w", err)
-		}
-		sb.WriteString(" AND mv.metadata @> ?::jsonb")
-		args = append(args, string(filterJSON))
+	if err := appendPostgresFilter(&sb, &args, filters); err != nil {
+		return nil, err
 	}
 
 	sb.WriteString(" ORDER BY ")
@@ -195,15 +249,85 @@ func (s *postgresVectorStore) Search(ctx context.Context, scope, scopeID string,
 	}
 	defer rows.Close()
 
+	return scanPostgresVectorResults(rows)
+}
+
+// KeywordSearch ranks the records whose source text matches any term of text by
+// ts_rank_cd over the generated text_search column.
+func (s *postgresVectorStore) KeywordSearch(ctx context.Context, scope, scopeID, text string, topK int, filters map[string]interface{}) ([]*types.VectorSearchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	terms := keywordTerms(text)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("query text cannot be empty")
+	}
+
+	// Each term is parsed on its own and the results ORed, so any term matching is
+	// enough, as with the SQLite store.
+	tsQuery := strings.TrimSuffix(strings.Repeat("plainto_tsquery('simple', ?) || ", len(terms)), " || ")
+	args := make([]interface{}, 0, len(terms)+len(filters)+3)
+	for _, term := range terms {
+		args = append(args, term)
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString("WITH query_ts AS (SELECT " + tsQuery + " AS q) ")
+	sb.WriteString("SELECT mv.scope, mv.scope_id, mv.key, mv.metadata, mv.text, mv.access_level, mv.access_control, mv.created_at, mv.updated_at, ")
+	sb.WriteString("ts_rank_cd(mv.text_search, query_ts.q) AS score, -ts_rank_cd(mv.text_search, query_ts.q) AS distance ")
+	sb.WriteString("FROM memory_vectors mv CROSS JOIN query_ts WHERE mv.scope = ? AND mv.scope_id = ? AND mv.text_search @@ query_ts.q")
+	args = append(args, scope, scopeID)
+
+	if err := appendPostgresFilter(&sb, &args, filters); err != nil {
+		return nil, err
+	}
+
+	if topK <= 0 {
+		topK = 10
+	}
+	sb.WriteString(" ORDER BY score DESC LIMIT ?")
+	args = append(args, topK)
+
+	rows, err := s.db.QueryContext(ctx, sb.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("query postgres vector text: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPostgresVectorResults(rows)
+}
+
+// appendPostgresFilter adds filters to a query's WHERE clause.
+func appendPostgresFilter(sb *strings.Builder, args *[]interface{}, filters map[string]interface{}) error {
+	filter, err := compileMetadataFilter(filters)
+	if err != nil || filter == nil {
+		return err
+	}
+	condition, filterArgs, err := postgresFilterSQL(filter, "mv.metadata")
+	if err != nil {
+		return err
+	}
+	sb.WriteString(" AND ")
+	sb.WriteString(condition)
+	*args = append(*args, filterArgs...)
+	return nil
+}
+
+func scanPostgresVectorResults(rows *sql.Rows) ([]*types.VectorSearchResult, error) {
 	results := make([]*types.VectorSearchResult, 0)
 	for rows.Next() {
 		result := &types.VectorSearchResult{}
 		var metadataRaw []byte
+		var text sql.NullString
+		var access vectorAccessColumns
 		if err := rows.Scan(
 			&result.Scope,
 			&result.ScopeID,
 			&result.Key,
 			&metadataRaw,
+			&text,
+			&access.level,
+			&access.control,
 			&result.CreatedAt,
 			&result.UpdatedAt,
 			&result.Score,
@@ -211,6 +335,12 @@ func (s *postgresVectorStore) Search(ctx context.Context, scope, scopeID string,
 		); err != nil {
 			return nil, fmt.Errorf("scan postgres vector: %w", err)
 		}
+		result.Text = text.String
+		accessLevel, accessControl, err := access.decode()
+		if err != nil {
+			return nil, err
+		}
+		result.AccessLevel, result.AccessControl = accessLevel, accessControl
 
 		if len(metadataRaw) > 0 {
 			if err := json.Unmarshal(metadataRaw, &result.Metadata); err != nil {
@@ -248,3 +378,17 @@ func buildPostgresVectorExpressions(metric VectorDistanceMetric, vectorReference
 		return fmt.Sprintf("1 - (%s)", dist), dist
 	}
 }
+
+// parsePostgresVector parses a pgvector literal like "[0.1,0.2,0.3]".
+func parsePostgresVector(literal string) ([]float32, error) {
+	parts := strings.Split(strings.Trim(literal, "[]"), ",")
+	embedding := make([]float32, len(parts))
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 32)
+		if err != nil {
+			return nil, fmt.Errorf("parse embedding element: %w", err)
+		}
+		embedding[i] = float32(v)
+	}
+	return embedding, nil
+}
@@ -5,14 +5,24 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/Agent-Field/agentfield/control-plane/internal/logger"
 	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
 )
 
 type sqliteVectorStore struct {
 	db     *sqlDatabase
 	metric VectorDistanceMetric
+
+	// index answers searches approximately when enabled. writeMu keeps its updates in
+	// the same order as the table writes.
+	index       *vectorIndex
+	writeMu     sync.Mutex
+	indexCancel context.CancelFunc
+	indexDone   chan struct{}
 }
 
 func newSQLiteVectorStore(db *sqlDatabase, metric VectorDistanceMetric) *sqliteVectorStore {
@@ -22,6 +32,30 @@ func newSQLiteVectorStore(db *sqlDatabase, metric VectorDistanceMetric) *sqliteV
 	}
 }
 
+// enableIndex builds an approximate nearest-neighbour index over the stored vectors in
+// the background. Searches scan the table until the build completes.
+func (s *sqliteVectorStore) enableIndex(config VectorIndexConfig) {
+	s.index = newVectorIndex(config, s.metric)
+	ctx, cancel := context.WithCancel(context.Background())
+	s.indexCancel, s.indexDone = cancel, make(chan struct{})
+	go func() {
+		defer close(s.indexDone)
+		if err := s.index.Rebuild(ctx, s.db); err != nil && ctx.Err() == nil {
+			logger.Logger.Error().Err(err).Msg("failed to build vector index; searches will scan")
+		}
+	}()
+}
+
+// stopIndex abandons an index build still in progress.
+func (s *sqliteVectorStore) stopIndex() {
+	if s.indexCancel == nil {
+		return
+	}
+	s.indexCancel()
+	<-s.indexDone
+	s.indexCancel = nil
+}
+
 func (s *sqliteVectorStore) Set(ctx context.Context, record *types.VectorRecord) error {
 	if err := ctx.Err(); err != nil {
 		return err
@@ -37,12 +71,25 @@ func (s *sqliteVectorStore) Set(ctx context.Context, record *types.VectorRecord)
 		return fmt.Errorf("marshal metadata: %w", err)
 	}
 
+	accessLevel, accessControl, err := encodeVectorAccess(record)
+	if err != nil {
+		return err
+	}
+
+	if s.index != nil {
+		s.writeMu.Lock()
+		defer s.writeMu.Unlock()
+	}
+
 	query := `
-		INSERT INTO memory_vectors (scope, scope_id, key, dimension, embedding, metadata, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO memory_vectors (scope, scope_id, key, dimension, embedding, metadata, text, access_level, access_control, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(scope, scope_id, key) DO UPDATE SET
 			embedding = excluded.embedding,
 			metadata = excluded.metadata,
+			text = excluded.text,
+			access_level = excluded.access_level,
+			access_control = excluded.access_control,
 			dimension = excluded.dimension,
 			updated_at = excluded.updated_at
 	`
@@ -56,12 +103,18 @@ func (s *sqliteVectorStore) Set(ctx context.Context, record *types.VectorRecord)
 		len(record.Embedding),
 		encodeEmbedding(record.Embedding),
 		string(metaJSON),
+		nullableVectorText(record.Text),
+		accessLevel,
+		accessControl,
 		now,
 		now,
 	)
 	if err != nil {
 		return fmt.Errorf("set vector: %w", err)
 	}
+	if s.index != nil {
+		s.index.Upsert(record.Scope, record.ScopeID, record.Key, record.Embedding)
+	}
 	return nil
 }
 
@@ -71,14 +124,15 @@ func (s *sqliteVectorStore) Get(ctx context.Context, scope, scopeID, key string)
 	}
 
 	var embeddingBlob []byte
-	var metadataRaw sql.NullString
+	var metadataRaw, text sql.NullString
+	var access vectorAccessColumns
 	var createdAt, updatedAt time.Time
 
 	err := s.db.QueryRowContext(ctx, `
-		SELECT embedding, metadata, created_at, updated_at
+		SELECT embedding, metadata, text, access_level, access_control, created_at, updated_at
 		FROM memory_vectors
 		WHERE scope = ? AND scope_id = ? AND key = ?
-	`, scope, scopeID, key).Scan(&embeddingBlob, &metadataRaw, &createdAt, &updatedAt)
+	`, scope, scopeID, key).Scan(&embeddingBlob, &metadataRaw, &text, &access.level, &access.control, &createdAt, &updatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -99,25 +153,84 @@ func (s *sqliteVectorStore) Get(ctx context.Context, scope, scopeID, key string)
 		}
 	}
 
+	accessLevel, accessControl, err := access.decode()
+	if err != nil {
+		return nil, err
+	}
+
 	return &types.VectorRecord{
-		Scope:     scope,
-		ScopeID:   scopeID,
-		Key:       key,
-		Embedding: embedding,
-		Metadata:  metadata,
-		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
+		Scope:         scope,
+		ScopeID:       scopeID,
+		Key:           key,
+		Embedding:     embedding,
+		Metadata:      metadata,
+		Text:          text.String,
+		AccessLevel:   accessLevel,
+		AccessControl: accessControl,
+		CreatedAt:     createdAt,
+		UpdatedAt:     updatedAt,
 	}, nil
 }
 
+func (s *sqliteVectorStore) Export(ctx context.Context, filter types.MemorySnapshotFilter, emit func(*types.VectorRecord) error) error {
+	return exportPages(func(after *snapshotKey) ([]*types.VectorRecord, error) {
+		query, args := snapshotPageQuery(
+			"scope, scope_id, key, embedding, metadata, text, access_level, access_control, created_at, updated_at",
+			"memory_vectors", filter, after)
+		rows, err := s.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("export vectors: %w", err)
+		}
+		defer rows.Close()
+
+		var page []*types.VectorRecord
+		for rows.Next() {
+			record := &types.VectorRecord{}
+			var embeddingBlob []byte
+			var metadataRaw, text sql.NullString
+			var access vectorAccessColumns
+			if err := rows.Scan(&record.Scope, &record.ScopeID, &record.Key, &embeddingBlob, &metadataRaw, &text,
+				&access.level, &access.control, &record.CreatedAt, &record.UpdatedAt); err != nil {
+				return nil, fmt.Errorf("scan vector row: %w", err)
+			}
+			if record.Embedding, err = decodeEmbedding(embeddingBlob); err != nil {
+				return nil, fmt.Errorf("decode embedding: %w", err)
+			}
+			if metadataRaw.Valid && metadataRaw.String != "" {
+				if err := json.Unmarshal([]byte(metadataRaw.String), &record.Metadata); err != nil {
+					return nil, fmt.Errorf("unmarshal metadata: %w", err)
+				}
+			}
+			record.Text = text.String
+			if record.AccessLevel, record.AccessControl, err = access.decode(); err != nil {
+				return nil, err
+			}
+			page = append(page, record)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("iterate vectors: %w", err)
+		}
+		return page, nil
+	}, func(record *types.VectorRecord) snapshotKey {
+		return snapshotKey{record.Scope, record.ScopeID, record.Key}
+	}, emit)
+}
+
 func (s *sqliteVectorStore) Delete(ctx context.Context, scope, scopeID, key string) error {
 	if err := ctx.Err(); err != nil {
 		return err
 	}
+	if s.index != nil {
+		s.writeMu.Lock()
+		defer s.writeMu.Unlock()
+	}
 	_, err := s.db.ExecContext(ctx, `
 		DELETE FROM memory_vectors
 		WHERE scope = ? AND scope_id = ? AND key = ?
 	`, scope, scopeID, key)
+	if err == nil && s.index != nil {
+		s.index.Delete(scope, scopeID, key)
+	}
 	return err
 }
 
@@ -126,6 +239,10 @@ func (s *sqliteVectorStore) DeleteByPrefix(ctx context.Context, scope, scopeID,
 		return 0, err
 	}
 
+	if s.index != nil {
+		s.writeMu.Lock()
+		defer s.writeMu.Unlock()
+	}
 	result, err := s.db.ExecContext(ctx, `
 		DELETE FROM memory_vectors
 		WHERE scope = ? AND scope_id = ? AND key LIKE ?
@@ -133,6 +250,9 @@ func (s *sqliteVectorStore) DeleteByPrefix(ctx context.Context, scope, scopeID,
 	if err != nil {
 		return 0, err
 	}
+	if s.index != nil {
+		s.index.DeletePrefix(scope, scopeID, prefix)
+	}
 
 	rows, err := result.RowsAffected()
 	if err != nil {
@@ -148,8 +268,205 @@ func (s *sqliteVectorStore) Search(ctx context.Context, scope, scopeID string, q
 	if len(query) == 0 {
 		return nil, fmt.Errorf("query embedding cannot be empty")
 	}
+	filter, err := compileMetadataFilter(filters)
+	if err != nil {
+		return nil, err
+	}
+	if s.index != nil && topK > 0 {
+		if results, ok, err := s.searchIndex(ctx, scope, scopeID, query, topK, filter); err != nil || ok {
+			return results, err
+		}
+	}
+	return s.scan(ctx, scope, scopeID, query, topK, filter)
+}
+
+// KeywordSearch ranks the records whose source text matches any term of text by BM25.
+// Score is the negated BM25 rank, so higher is better as with vector scores.
+func (s *sqliteVectorStore) KeywordSearch(ctx context.Context, scope, scopeID, text string, topK int, filters map[string]interface{}) ([]*types.VectorSearchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	match := fts5KeywordQuery(text)
+	if match == "" {
+		return nil, fmt.Errorf("query text cannot be empty")
+	}
+	filter, err := compileMetadataFilter(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT mv.key, mv.metadata, mv.text, mv.access_level, mv.access_control, mv.created_at, mv.updated_at, bm25(memory_vectors_fts) AS rank
+		FROM memory_vectors_fts
+		JOIN memory_vectors mv ON mv.rowid = memory_vectors_fts.rowid
+		WHERE memory_vectors_fts MATCH ? AND mv.scope = ? AND mv.scope_id = ?
+		ORDER BY rank
+	`, match, scope, scopeID)
+	if err != nil {
+		return nil, fmt.Errorf("query vector text: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]*types.VectorSearchResult, 0)
+	for rows.Next() {
+		var key string
+		var metadataRaw, source sql.NullString
+		var access vectorAccessColumns
+		var createdAt, updatedAt time.Time
+		var rank float64
+		if err := rows.Scan(&key, &metadataRaw, &source, &access.level, &access.control, &createdAt, &updatedAt, &rank); err != nil {
+			return nil, fmt.Errorf("scan vector text row: %w", err)
+		}
+
+		metadata := map[string]interface{}{}
+		if metadataRaw.Valid && metadataRaw.String != "" {
+			if err := json.Unmarshal([]byte(metadataRaw.String), &metadata); err != nil {
+				return nil, fmt.Errorf("unmarshal metadata: %w", err)
+			}
+		}
+		if !filter.matches(metadata) {
+			continue
+		}
+
+		accessLevel, accessControl, err := access.decode()
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, &types.VectorSearchResult{
+			Scope:         scope,
+			ScopeID:       scopeID,
+			Key:           key,
+			Score:         -rank,
+			Distance:      rank,
+			Metadata:      metadata,
+			Text:          source.String,
+			AccessLevel:   accessLevel,
+			AccessControl: accessControl,
+			CreatedAt:     createdAt,
+			UpdatedAt:     updatedAt,
+		})
+		if topK > 0 && len(results) == topK {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// indexFilterOverfetch is how many index candidates a filtered search examines per
+// requested result before falling back to an exact scan.
+const indexFilterOverfetch = 4
+
+// searchIndex answers a search from the approximate index. ok is false when the index
+// cannot answer, or when filters left fewer than topK of its candidates, so the caller
+// scans instead.
+func (s *sqliteVectorStore) searchIndex(ctx context.Context, scope, scopeID string, query []float32, topK int, filter *metadataFilter) (results []*types.VectorSearchResult, ok bool, err error) {
+	k := topK
+	if filter != nil {
+		k = topK * indexFilterOverfetch
+	}
+	hits, ok := s.index.Search(scope, scopeID, query, k)
+	if !ok {
+		return nil, false, nil
+	}
+
+	keys := make([]string, len(hits))
+	for i, hit := range hits {
+		keys[i] = hit.key
+	}
+	rows, err := s.loadVectorRows(ctx, scope, scopeID, keys)
+	if err != nil {
+		return nil, false, err
+	}
+
+	results = make([]*types.VectorSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		row, found := rows[hit.key]
+		if !found || !filter.matches(row.Metadata) {
+			continue
+		}
+		score, distance := computeSimilarity(s.metric, query, hit.vector)
+		results = append(results, &types.VectorSearchResult{
+			Scope:         scope,
+			ScopeID:       scopeID,
+			Key:           hit.key,
+			Score:         score,
+			Distance:      distance,
+			Metadata:      row.Metadata,
+			Text:          row.Text,
+			AccessLevel:   row.AccessLevel,
+			AccessControl: row.AccessControl,
+			CreatedAt:     row.CreatedAt,
+			UpdatedAt:     row.UpdatedAt,
+		})
+	}
+	if filter != nil && len(results) < topK && len(hits) == k {
+		return nil, false, nil
+	}
+	return sortAndLimit(results, topK), true, nil
+}
+
+// loadVectorRows fetches the metadata and timestamps of keys, leaving the embeddings the
+// index already holds.
+func (s *sqliteVectorStore) loadVectorRows(ctx context.Context, scope, scopeID string, keys []string) (map[string]*types.VectorRecord, error) {
+	records := make(map[string]*types.VectorRecord, len(keys))
+	if len(keys) == 0 {
+		return records, nil
+	}
+
+	args := make([]interface{}, 0, len(keys)+2)
+	args = append(args, scope, scopeID)
+	for _, key := range keys {
+		args = append(args, key)
+	}
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT key, embedding, metadata, created_at, updated_at
+		SELECT key, metadata, text, access_level, access_control, created_at, updated_at
+		FROM memory_vectors
+		WHERE scope = ? AND scope_id = ? AND key IN (`+strings.TrimSuffix(strings.Repeat("?,", len(keys)), ",")+`)
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query vectors: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		var metadataRaw, text sql.NullString
+		var access vectorAccessColumns
+		var createdAt, updatedAt time.Time
+		if err := rows.Scan(&key, &metadataRaw, &text, &access.level, &access.control, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("scan vector row: %w", err)
+		}
+		metadata := map[string]interface{}{}
+		if metadataRaw.Valid && metadataRaw.String != "" {
+			if err := json.Unmarshal([]byte(metadataRaw.String), &metadata); err != nil {
+				return nil, fmt.Errorf("unmarshal metadata: %w", err)
+			}
+		}
+		accessLevel, accessControl, err := access.decode()
+		if err != nil {
+			return nil, err
+		}
+		records[key] = &types.VectorRecord{
+			Key:           key,
+			Metadata:      metadata,
+			Text:          text.String,
+			AccessLevel:   accessLevel,
+			AccessControl: accessControl,
+			CreatedAt:     createdAt,
+			UpdatedAt:     updatedAt,
+		}
+	}
+	return records, rows.Err()
+}
+
+// scan computes the similarity of every vector in the scope, giving exact results.
+func (s *sqliteVectorStore) scan(ctx context.Context, scope, scopeID string, query []float32, topK int, filter *metadataFilter) ([]*types.VectorSearchResult, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT key, embedding, metadata, text, access_level, access_control, created_at, updated_at
 		FROM memory_vectors
 		WHERE scope = ? AND scope_id = ?
 	`, scope, scopeID)
@@ -162,10 +479,11 @@ func (s *sqliteVectorStore) Search(ctx context.Context, scope, scopeID string, q
 	for rows.Next() {
 		var key string
 		var embeddingBlob []byte
-		var metadataRaw sql.NullString
+		var metadataRaw, text sql.NullString
+		var access vectorAccessColumns
 		var createdAt, updatedAt time.Time
 
-		if err := rows.Scan(&key, &embeddingBlob, &metadataRaw, &createdAt, &updatedAt); err != nil {
+		if err := rows.Scan(&key, &embeddingBlob, &metadataRaw, &text, &access.level, &access.control, &createdAt, &updatedAt); err != nil {
 			return nil, fmt.Errorf("scan vector row: %w", err)
 		}
 
@@ -184,20 +502,28 @@ func (s *sqliteVectorStore) Search(ctx context.Context, scope, scopeID string, q
 			}
 		}
 
-		if !metadataMatchesFilters(metadata, filters) {
+		if !filter.matches(metadata) {
 			continue
 		}
 
+		accessLevel, accessControl, err := access.decode()
+		if err != nil {
+			return nil, err
+		}
+
 		score, distance := computeSimilarity(s.metric, query, embedding)
 		results = append(results, &types.VectorSearchResult{
-			Scope:     scope,
-			ScopeID:   scopeID,
-			Key:       key,
-			Score:     score,
-			Distance:  distance,
-			Metadata:  metadata,
-			CreatedAt: createdAt,
-			UpdatedAt: updatedAt,
+			Scope:         scope,
+			ScopeID:       scopeID,
+			Key:           key,
+			Score:         score,
+			Distance:      distance,
+			Metadata:      metadata,
+			Text:          text.String,
+			AccessLevel:   accessLevel,
+			AccessControl: accessControl,
+			CreatedAt:     createdAt,
+			UpdatedAt:     updatedAt,
 		})
 	}
 
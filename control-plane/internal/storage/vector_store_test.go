@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Agent-Field/agentfield/control-plane/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func seedHybridVectors(t *testing.T, ctx context.Context, ls *LocalStorage) {
+	t.Helper()
+	records := []*types.VectorRecord{
+		{Key: "refund-policy", Embedding: []float32{1, 0, 0}, Text: "Refunds are issued within 14 days", Metadata: map[string]interface{}{"source": "docs"}},
+		{Key: "ticket-1234", Embedding: []float32{0, 1, 0}, Text: "Customer asked about order AB-1234 refund", Metadata: map[string]interface{}{"source": "tickets"}},
+		{Key: "shipping", Embedding: []float32{0.9, 0.1, 0}, Text: "Shipping takes five business days", Metadata: map[string]interface{}{"source": "docs"}},
+		{Key: "untexted", Embedding: []float32{0.95, 0, 0.05}},
+	}
+	for _, record := range records {
+		record.Scope, record.ScopeID = "session", "hybrid"
+		require.NoError(t, ls.SetVector(ctx, record))
+	}
+}
+
+func TestLocalStorage_KeywordSearch(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+	ls := provider.(*LocalStorage)
+	seedHybridVectors(t, ctx, ls)
+
+	results, err := ls.HybridSearch(ctx, "session", "hybrid", types.VectorSearchQuery{Text: "AB-1234", TopK: 5})
+	require.NoError(t, err)
+	require.Len(t, results, 1, "identifiers match as written")
+	require.Equal(t, "ticket-1234", results[0].Key)
+	require.Equal(t, "Customer asked about order AB-1234 refund", results[0].Text)
+
+	results, err = ls.HybridSearch(ctx, "session", "hybrid", types.VectorSearchQuery{
+		Text:    "refunds shipping",
+		TopK:    5,
+		Filters: map[string]interface{}{"source": "docs"},
+	})
+	require.NoError(t, err)
+	keys := []string{}
+	for _, result := range results {
+		keys = append(keys, result.Key)
+	}
+	require.ElementsMatch(t, []string{"refund-policy", "shipping"}, keys)
+
+	// Replacing a record re-indexes its text.
+	require.NoError(t, ls.SetVector(ctx, &types.VectorRecord{
+		Scope: "session", ScopeID: "hybrid", Key: "ticket-1234",
+		Embedding: []float32{0, 1, 0}, Text: "Order closed",
+	}))
+	results, err = ls.HybridSearch(ctx, "session", "hybrid", types.VectorSearchQuery{Text: "AB-1234"})
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	require.NoError(t, ls.DeleteVector(ctx, "session", "hybrid", "ticket-1234"))
+	results, err = ls.HybridSearch(ctx, "session", "hybrid", types.VectorSearchQuery{Text: "closed"})
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
+
+func TestLocalStorage_HybridSearchFusesRankings(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+	ls := provider.(*LocalStorage)
+	seedHybridVectors(t, ctx, ls)
+
+	vectorOnly, err := ls.HybridSearch(ctx, "session", "hybrid", types.VectorSearchQuery{Embedding: []float32{1, 0, 0}, TopK: 1})
+	require.NoError(t, err)
+	require.Equal(t, "refund-policy", vectorOnly[0].Key)
+	require.Nil(t, vectorOnly[0].KeywordScore)
+
+	// The exact identifier only matches by keyword, which lifts its record above the
+	// vectors that are merely closer to the embedding.
+	results, err := ls.HybridSearch(ctx, "session", "hybrid", types.VectorSearchQuery{
+		Embedding:     []float32{1, 0, 0},
+		Text:          "AB-1234",
+		TopK:          2,
+		KeywordWeight: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, "ticket-1234", results[0].Key)
+	require.NotNil(t, results[0].KeywordScore)
+	require.NotNil(t, results[0].VectorScore)
+	require.Equal(t, "refund-policy", results[1].Key)
+	require.Nil(t, results[1].KeywordScore)
+	require.Greater(t, results[0].Score, results[1].Score)
+
+	_, err = ls.HybridSearch(ctx, "session", "hybrid", types.VectorSearchQuery{Text: "  "})
+	require.ErrorContains(t, err, "query embedding or query text is required")
+}
+
+func TestFuseRankings(t *testing.T) {
+	vector := []*types.VectorSearchResult{{Key: "a", Score: 0.9}, {Key: "b", Score: 0.8}}
+	keyword := []*types.VectorSearchResult{{Key: "b", Score: 3, Text: "b text"}, {Key: "c", Score: 1}}
+
+	fused := fuseRankings(vector, keyword, 60, 1, 0)
+	require.Len(t, fused, 3)
+	require.Equal(t, "b", fused[0].Key, "appearing in both lists beats topping one")
+	require.InDelta(t, 1.0/62+1.0/61, fused[0].Score, 1e-12)
+	require.Equal(t, 0.8, *fused[0].VectorScore)
+	require.Equal(t, 3.0, *fused[0].KeywordScore)
+	require.Equal(t, "b text", fused[0].Text)
+	require.Equal(t, 0.9, vector[0].Score, "inputs are not modified")
+
+	require.Len(t, fuseRankings(vector, keyword, 60, 1, 2), 2)
+}
+
+func TestFTS5KeywordQuery(t *testing.T) {
+	require.Equal(t, `"AB-1234" OR "refund"`, fts5KeywordQuery(" AB-1234  refund "))
+	require.Equal(t, `"a""b"`, fts5KeywordQuery(`a"b`))
+	require.Empty(t, fts5KeywordQuery("   "))
+}
+
+func TestLocalStorage_VectorAccessPolicyRoundTrips(t *testing.T) {
+	provider, ctx := setupTestStorage(t)
+	ls := provider.(*LocalStorage)
+
+	policy := &types.AccessControlMetadata{RequiredRoles: []string{"operator"}, AuditAccess: true, OwnerAgentID: "planner"}
+	require.NoError(t, ls.SetVector(ctx, &types.VectorRecord{
+		Scope: "session", ScopeID: "acl", Key: "plan", Embedding: []float32{1, 0},
+		Text: "quarterly plan", AccessLevel: types.MemoryAccessPrivate, AccessControl: policy,
+	}))
+	require.NoError(t, ls.SetVector(ctx, &types.VectorRecord{
+		Scope: "session", ScopeID: "acl", Key: "open", Embedding: []float32{0, 1},
+	}))
+
+	record, err := ls.GetVector(ctx, "session", "acl", "plan")
+	require.NoError(t, err)
+	require.Equal(t, types.MemoryAccessPrivate, record.AccessLevel)
+	require.Equal(t, policy, record.AccessControl)
+
+	results, err := ls.SimilaritySearch(ctx, "session", "acl", []float32{1, 0}, 2, nil)
+	require.NoError(t, err)
+	require.Equal(t, "plan", results[0].Key)
+	require.Equal(t, policy, results[0].AccessControl)
+	require.Nil(t, results[1].AccessControl)
+
+	results, err = ls.HybridSearch(ctx, "session", "acl", types.VectorSearchQuery{Text: "quarterly"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, types.MemoryAccessPrivate, results[0].AccessLevel)
+}
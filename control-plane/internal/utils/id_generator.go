@@ -28,6 +28,27 @@ func GenerateRunID() string {
 	return fmt.Sprintf("run_%s_%s", timestamp, random)
 }
 
+// GenerateScheduleID generates a new schedule ID.
+func GenerateScheduleID() string {
+	timestamp := time.Now().Format("20060102_150405")
+	random := generateRandomString(8)
+	return fmt.Sprintf("sched_%s_%s", timestamp, random)
+}
+
+// GenerateAPIKeyID generates a new API key ID.
+func GenerateAPIKeyID() string {
+	timestamp := time.Now().Format("20060102_150405")
+	random := generateRandomString(8)
+	return fmt.Sprintf("key_%s_%s", timestamp, random)
+}
+
+// GenerateApprovalID generates a new approval ID.
+func GenerateApprovalID() string {
+	timestamp := time.Now().Format("20060102_150405")
+	random := generateRandomString(8)
+	return fmt.Sprintf("appr_%s_%s", timestamp, random)
+}
+
 // GenerateAgentFieldRequestID generates a new agentfield request ID
 func GenerateAgentFieldRequestID() string {
 	timestamp := time.Now().Format("20060102_150405")
@@ -0,0 +1,13 @@
+package types
+
+import "time"
+
+// AgentInstance is one registered replica of an agent node. Executions targeting
+// the node are balanced across its live instances.
+type AgentInstance struct {
+	NodeID        string    `json:"node_id" db:"node_id"`
+	InstanceID    string    `json:"instance_id" db:"instance_id"`
+	BaseURL       string    `json:"base_url" db:"base_url"`
+	LastHeartbeat time.Time `json:"last_heartbeat" db:"last_heartbeat"`
+	RegisteredAt  time.Time `json:"registered_at" db:"registered_at"`
+}
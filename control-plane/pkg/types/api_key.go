@@ -0,0 +1,46 @@
+package types
+
+import "time"
+
+// API key roles, from most to least privileged.
+const (
+	// APIKeyRoleAdmin may do anything within its team, including managing API keys.
+	APIKeyRoleAdmin = "admin"
+	// APIKeyRoleOperator may use every API except key management.
+	APIKeyRoleOperator = "operator"
+	// APIKeyRoleAgent covers what an agent node needs: registration, heartbeats,
+	// executions, memory and discovery.
+	APIKeyRoleAgent = "agent"
+	// APIKeyRoleReadOnly may only read.
+	APIKeyRoleReadOnly = "read_only"
+)
+
+// IsValidAPIKeyRole reports whether role is one of the known API key roles.
+func IsValidAPIKeyRole(role string) bool {
+	switch role {
+	case APIKeyRoleAdmin, APIKeyRoleOperator, APIKeyRoleAgent, APIKeyRoleReadOnly:
+		return true
+	}
+	return false
+}
+
+// APIKey is a stored credential scoped to one team and role. Only a hash of the secret
+// is kept; the secret itself is returned once, when the key is created or rotated.
+type APIKey struct {
+	KeyID  string `json:"key_id" db:"key_id"`
+	Name   string `json:"name" db:"name"`
+	TeamID string `json:"team_id" db:"team_id"`
+	Role   string `json:"role" db:"role"`
+	// Prefix is the start of the secret, kept so keys can be told apart.
+	Prefix     string `json:"prefix" db:"prefix"`
+	SecretHash string `json:"-" db:"secret_hash"`
+
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty" db:"rotated_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// Revoked reports whether the key may no longer be used.
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
@@ -0,0 +1,59 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Approval statuses. A pending approval is resolved exactly once.
+const (
+	ApprovalStatusPending  = "pending"
+	ApprovalStatusApproved = "approved"
+	ApprovalStatusRejected = "rejected"
+)
+
+// Approval is a human decision an execution waits on before it continues, such as
+// sending an email or spending money.
+type Approval struct {
+	ApprovalID  string `json:"approval_id" db:"approval_id"`
+	ExecutionID string `json:"execution_id" db:"execution_id"`
+	RunID       string `json:"run_id" db:"run_id"`
+	AgentNodeID string `json:"agent_node_id" db:"agent_node_id"`
+	ReasonerID  string `json:"reasoner_id" db:"reasoner_id"`
+	// Title and Description tell the reviewer what is being approved; Payload carries
+	// the details of the action, e.g. the email about to be sent.
+	Title       string          `json:"title" db:"title"`
+	Description string          `json:"description,omitempty" db:"description"`
+	Payload     json.RawMessage `json:"payload,omitempty" db:"payload"`
+	Status      string          `json:"status" db:"status"`
+	// DefaultDecision is applied when ExpiresAt passes without a decision.
+	DefaultDecision string     `json:"default_decision" db:"default_decision"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+
+	Comment   *string `json:"comment,omitempty" db:"comment"`
+	DecidedBy *string `json:"decided_by,omitempty" db:"decided_by"`
+	// TimedOut is set when the decision was taken by default after expiry.
+	TimedOut  bool       `json:"timed_out" db:"timed_out"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	DecidedAt *time.Time `json:"decided_at,omitempty" db:"decided_at"`
+}
+
+// Decided reports whether the approval has been approved or rejected.
+func (a *Approval) Decided() bool {
+	return a.Status != ApprovalStatusPending
+}
+
+// IsValidApprovalDecision reports whether decision is a final approval status.
+func IsValidApprovalDecision(decision string) bool {
+	return decision == ApprovalStatusApproved || decision == ApprovalStatusRejected
+}
+
+// ApprovalFilter narrows ListApprovals. Zero values match everything.
+type ApprovalFilter struct {
+	Status      string
+	ExecutionID string
+	// AgentNodeIDs restricts results to these agents when non-nil; an empty slice
+	// matches nothing.
+	AgentNodeIDs []string
+	Limit        int
+}
@@ -30,10 +30,14 @@ type Execution struct {
 	StartedAt   time.Time  `json:"started_at" db:"started_at"`
 	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at"`
 	DurationMS  *int64     `json:"duration_ms,omitempty" db:"duration_ms"`
+	// RetryCount is the number of agent call attempts made after the first one.
+	RetryCount int `json:"retry_count" db:"retry_count"`
 
 	// Optional metadata
 	SessionID *string `json:"session_id,omitempty" db:"session_id"`
 	ActorID   *string `json:"actor_id,omitempty" db:"actor_id"`
+	// ScheduleID links executions fired by a schedule back to it.
+	ScheduleID *string `json:"schedule_id,omitempty" db:"schedule_id"`
 
 	// Notes for debugging and tracking
 	Notes []ExecutionNote `json:"notes,omitempty" db:"notes"`
@@ -56,12 +60,16 @@ type ExecutionFilter struct {
 	Status            *string
 	SessionID         *string
 	ActorID           *string
+	ScheduleID        *string
 	Limit             int
 	Offset            int
 	StartTime         *time.Time
 	EndTime           *time.Time
 	SortBy            string
 	SortDescending    bool
+	// AgentNodeIDs restricts results to these agents when non-nil; an empty slice
+	// matches nothing.
+	AgentNodeIDs []string
 }
 
 // ExecutionDAGEdge captures a parent→child relationship inside a run. The UI uses
@@ -0,0 +1,38 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// Execution action lifecycle states
+	ExecutionActionStatusPending   = "pending"
+	ExecutionActionStatusLeased    = "leased"
+	ExecutionActionStatusCompleted = "completed"
+
+	// DeploymentTypePoll marks agents that pull work through /actions/claim instead of
+	// receiving HTTP calls on their base URL.
+	DeploymentTypePoll = "poll"
+)
+
+// ExecutionAction is a unit of work queued for a poll-mode agent node.
+type ExecutionAction struct {
+	ActionID          string          `json:"action_id" db:"action_id"`
+	ExecutionID       string          `json:"execution_id" db:"execution_id"`
+	RunID             string          `json:"run_id" db:"run_id"`
+	ParentExecutionID *string         `json:"parent_execution_id,omitempty" db:"parent_execution_id"`
+	SessionID         *string         `json:"session_id,omitempty" db:"session_id"`
+	ActorID           *string         `json:"actor_id,omitempty" db:"actor_id"`
+	NodeID            string          `json:"node_id" db:"node_id"`
+	Target            string          `json:"target" db:"target"`
+	TargetType        string          `json:"type" db:"target_type"`
+	Input             json.RawMessage `json:"input" db:"input"`
+	Status            string          `json:"status" db:"status"`
+	AttemptCount      int             `json:"attempt" db:"attempt_count"`
+	LeaseExpiresAt    *time.Time      `json:"lease_expires_at,omitempty" db:"lease_expires_at"`
+	ClaimedAt         *time.Time      `json:"claimed_at,omitempty" db:"claimed_at"`
+	CompletedAt       *time.Time      `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt         time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,21 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// QueuedExecution is an accepted async execution waiting to be dispatched to its agent.
+// The row is removed once the dispatch has been settled, so anything still present
+// after a restart is resumed.
+type QueuedExecution struct {
+	ExecutionID string          `json:"execution_id" db:"execution_id"`
+	NodeID      string          `json:"node_id" db:"node_id"`
+	Target      string          `json:"target" db:"target"`
+	TargetType  string          `json:"target_type" db:"target_type"`
+	Payload     json.RawMessage `json:"payload" db:"payload"`
+	RetryPolicy *RetryPolicy    `json:"retry_policy,omitempty" db:"retry_policy"`
+	// Caller is the admission caller key the execution counts against once dispatched.
+	Caller    string    `json:"caller,omitempty" db:"caller"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,32 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryConfigRetentionTTL(t *testing.T) {
+	for _, retention := range []string{"", MemoryRetentionPersistent} {
+		ttl, err := MemoryConfig{MemoryRetention: retention}.RetentionTTL()
+		if err != nil || ttl != nil {
+			t.Fatalf("%q: expected no TTL, got %v, %v", retention, ttl, err)
+		}
+	}
+
+	expected := map[string]time.Duration{
+		MemoryRetentionSession: SessionMemoryRetention,
+		"72h":                  72 * time.Hour,
+	}
+	for retention, want := range expected {
+		ttl, err := MemoryConfig{MemoryRetention: retention}.RetentionTTL()
+		if err != nil || ttl == nil || *ttl != want {
+			t.Fatalf("%q: expected %v, got %v, %v", retention, want, ttl, err)
+		}
+	}
+
+	for _, retention := range []string{"forever", "-1h", "0s"} {
+		if _, err := (MemoryConfig{MemoryRetention: retention}).RetentionTTL(); err == nil {
+			t.Fatalf("%q: expected an error", retention)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package types
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Error classes a RetryPolicy may retry in addition to HTTP status codes.
+const (
+	RetryErrorTimeout    = "timeout"
+	RetryErrorConnection = "connection"
+)
+
+// MaxRetryAttempts caps RetryPolicy.MaxAttempts so a misconfigured policy cannot pin a
+// worker on a failing agent indefinitely.
+const MaxRetryAttempts = 10
+
+// Defaults applied to unset RetryPolicy fields.
+const (
+	DefaultRetryInitialBackoffMS  int64   = 500
+	DefaultRetryMaxBackoffMS      int64   = 30_000
+	DefaultRetryBackoffMultiplier float64 = 2
+)
+
+// DefaultRetryableStatusCodes are the gateway errors serverless platforms return for
+// cold starts and transient overload.
+var DefaultRetryableStatusCodes = []int{
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy controls how the control plane retries a failed agent call. It can be
+// declared per reasoner at registration time or supplied with an execute request,
+// which takes precedence.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of calls, including the first one.
+	MaxAttempts          int      `json:"max_attempts"`
+	InitialBackoffMS     int64    `json:"initial_backoff_ms,omitempty"`
+	MaxBackoffMS         int64    `json:"max_backoff_ms,omitempty"`
+	BackoffMultiplier    float64  `json:"backoff_multiplier,omitempty"`
+	RetryableStatusCodes []int    `json:"retryable_status_codes,omitempty"`
+	RetryableErrors      []string `json:"retryable_errors,omitempty"`
+}
+
+// Validate reports whether the policy can be applied.
+func (p *RetryPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.MaxAttempts < 1 || p.MaxAttempts > MaxRetryAttempts {
+		return fmt.Errorf("max_attempts must be between 1 and %d", MaxRetryAttempts)
+	}
+	if p.InitialBackoffMS < 0 || p.MaxBackoffMS < 0 {
+		return fmt.Errorf("backoff durations must not be negative")
+	}
+	if p.BackoffMultiplier != 0 && p.BackoffMultiplier < 1 {
+		return fmt.Errorf("backoff_multiplier must be at least 1")
+	}
+	for _, code := range p.RetryableStatusCodes {
+		if code < 400 || code > 599 {
+			return fmt.Errorf("retryable status code %d is not an HTTP error status", code)
+		}
+	}
+	for _, class := range p.RetryableErrors {
+		if class != RetryErrorTimeout && class != RetryErrorConnection {
+			return fmt.Errorf("unknown retryable error class %q", class)
+		}
+	}
+	return nil
+}
+
+// WithDefaults returns a copy of the policy with unset fields filled in.
+func (p RetryPolicy) WithDefaults() RetryPolicy {
+	if p.MaxAttempts < 1 {
+		p.MaxAttempts = 1
+	}
+	if p.InitialBackoffMS == 0 {
+		p.InitialBackoffMS = DefaultRetryInitialBackoffMS
+	}
+	if p.MaxBackoffMS == 0 {
+		p.MaxBackoffMS = DefaultRetryMaxBackoffMS
+	}
+	if p.BackoffMultiplier == 0 {
+		p.BackoffMultiplier = DefaultRetryBackoffMultiplier
+	}
+	if len(p.RetryableStatusCodes) == 0 {
+		p.RetryableStatusCodes = append([]int(nil), DefaultRetryableStatusCodes...)
+	}
+	if len(p.RetryableErrors) == 0 {
+		p.RetryableErrors = []string{RetryErrorTimeout, RetryErrorConnection}
+	}
+	return p
+}
@@ -0,0 +1,46 @@
+package types
+
+import "testing"
+
+func TestRetryPolicyValidate(t *testing.T) {
+	valid := []*RetryPolicy{
+		nil,
+		{MaxAttempts: 1},
+		{MaxAttempts: MaxRetryAttempts, BackoffMultiplier: 1.5, RetryableStatusCodes: []int{429, 503}, RetryableErrors: []string{RetryErrorTimeout}},
+	}
+	for i, policy := range valid {
+		if err := policy.Validate(); err != nil {
+			t.Fatalf("valid[%d]: unexpected error %v", i, err)
+		}
+	}
+
+	invalid := []*RetryPolicy{
+		{MaxAttempts: 0},
+		{MaxAttempts: MaxRetryAttempts + 1},
+		{MaxAttempts: 2, InitialBackoffMS: -1},
+		{MaxAttempts: 2, BackoffMultiplier: 0.5},
+		{MaxAttempts: 2, RetryableStatusCodes: []int{200}},
+		{MaxAttempts: 2, RetryableErrors: []string{"dns"}},
+	}
+	for i, policy := range invalid {
+		if err := policy.Validate(); err == nil {
+			t.Fatalf("invalid[%d]: expected an error", i)
+		}
+	}
+}
+
+func TestRetryPolicyWithDefaults(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, RetryableStatusCodes: []int{429}}.WithDefaults()
+	if policy.InitialBackoffMS != DefaultRetryInitialBackoffMS || policy.MaxBackoffMS != DefaultRetryMaxBackoffMS {
+		t.Fatalf("backoff defaults not applied: %+v", policy)
+	}
+	if policy.BackoffMultiplier != DefaultRetryBackoffMultiplier {
+		t.Fatalf("multiplier default not applied: %v", policy.BackoffMultiplier)
+	}
+	if len(policy.RetryableStatusCodes) != 1 || policy.RetryableStatusCodes[0] != 429 {
+		t.Fatalf("explicit status codes overwritten: %v", policy.RetryableStatusCodes)
+	}
+	if len(policy.RetryableErrors) != 2 {
+		t.Fatalf("error class defaults not applied: %v", policy.RetryableErrors)
+	}
+}
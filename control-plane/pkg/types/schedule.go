@@ -0,0 +1,43 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Schedule fires an async execution of Target whenever its cron expression matches.
+type Schedule struct {
+	ScheduleID     string `json:"schedule_id" db:"schedule_id"`
+	Name           string `json:"name" db:"name"`
+	CronExpression string `json:"cron_expression" db:"cron_expression"`
+	// TeamID is the team of the caller that created the schedule, which its runs act
+	// for. It is empty for callers not confined to a team.
+	TeamID string `json:"team_id,omitempty" db:"team_id"`
+	// Timezone is the IANA zone the cron expression is evaluated in; empty means UTC.
+	Timezone string `json:"timezone,omitempty" db:"timezone"`
+	// Target is the "node_id.reasoner_or_skill" invoked on each run.
+	Target string `json:"target" db:"target"`
+	// InputTemplate is the execution input. String values may reference
+	// {{.ScheduleID}}, {{.ScheduleName}} and {{.ScheduledAt}}.
+	InputTemplate json.RawMessage  `json:"input_template" db:"input_template"`
+	Webhook       *ScheduleWebhook `json:"webhook,omitempty" db:"webhook"`
+	Enabled       bool             `json:"enabled" db:"enabled"`
+
+	NextRunAt       *time.Time `json:"next_run_at,omitempty" db:"next_run_at"`
+	LastRunAt       *time.Time `json:"last_run_at,omitempty" db:"last_run_at"`
+	LastExecutionID *string    `json:"last_execution_id,omitempty" db:"last_execution_id"`
+	LastError       *string    `json:"last_error,omitempty" db:"last_error"`
+	RunCount        int64      `json:"run_count" db:"run_count"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ScheduleWebhook is registered on every execution a schedule fires.
+type ScheduleWebhook struct {
+	URL     string            `json:"url"`
+	Secret  string            `json:"secret,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// HasSecret replaces Secret in API responses.
+	HasSecret bool `json:"has_secret,omitempty"`
+}
@@ -14,6 +14,9 @@ const (
 	ExecutionStatusFailed    ExecutionStatus = "failed"
 	ExecutionStatusCancelled ExecutionStatus = "cancelled"
 	ExecutionStatusTimeout   ExecutionStatus = "timeout"
+	// ExecutionStatusWaitingForApproval marks a running execution paused until a human
+	// approves or rejects an action.
+	ExecutionStatusWaitingForApproval ExecutionStatus = "waiting_for_approval"
 )
 
 var canonicalExecutionStatuses = map[ExecutionStatus]struct{}{
@@ -25,6 +28,8 @@ var canonicalExecutionStatuses = map[ExecutionStatus]struct{}{
 	ExecutionStatusFailed:    {},
 	ExecutionStatusCancelled: {},
 	ExecutionStatusTimeout:   {},
+
+	ExecutionStatusWaitingForApproval: {},
 }
 
 var executionStatusAliases = map[string]ExecutionStatus{
@@ -44,6 +49,8 @@ var executionStatusAliases = map[string]ExecutionStatus{
 	"waiting":     ExecutionStatusQueued,
 	"in_progress": ExecutionStatusRunning,
 	"processing":  ExecutionStatusRunning,
+
+	"awaiting_approval": ExecutionStatusWaitingForApproval,
 }
 
 // NormalizeExecutionStatus maps arbitrary status strings onto the canonical execution statuses used by the AgentField platform.
@@ -2,6 +2,8 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -82,19 +84,43 @@ type PerformanceMetadata struct {
 
 // Memory represents a piece of memory stored in the system.
 type Memory struct {
-	Scope       string          `json:"scope" db:"scope"`
-	ScopeID     string          `json:"scope_id" db:"scope_id"`
-	Key         string          `json:"key" db:"key"`
-	Data        json.RawMessage `json:"data" db:"data"`
-	AccessLevel string          `json:"access_level" db:"access_level"`
-
-	TTL       *time.Duration `json:"ttl,omitempty" db:"ttl"`
-	CreatedAt time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at" db:"updated_at"`
+	Scope   string          `json:"scope" db:"scope"`
+	ScopeID string          `json:"scope_id" db:"scope_id"`
+	Key     string          `json:"key" db:"key"`
+	Data    json.RawMessage `json:"data" db:"data"`
+	// AccessLevel is MemoryAccessPublic (the default) or MemoryAccessPrivate; private
+	// memory is only visible to the identity in Metadata.AccessControl that wrote it.
+	AccessLevel string `json:"access_level" db:"access_level"`
+	// Version increases by one on every write; callers pass it back as if_version to
+	// update the value only if nobody else changed it in between.
+	Version int64 `json:"version" db:"version"`
+
+	TTL *time.Duration `json:"ttl,omitempty" db:"ttl"`
+	// ExpiresAt is derived from TTL by writes that do not carry one; expired memory
+	// reads as missing until the background sweep purges it.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
 
 	Metadata MemoryMetadata `json:"metadata" db:"metadata"`
 }
 
+// Expired reports whether the memory's TTL has elapsed at now.
+func (m *Memory) Expired(now time.Time) bool {
+	return m.ExpiresAt != nil && !m.ExpiresAt.After(now)
+}
+
+// Encrypted reports whether the memory's data is encrypted at rest.
+func (m *Memory) Encrypted() bool {
+	return m.Metadata.Encryption != nil && m.Metadata.Encryption.Encrypted
+}
+
+// Protected reports whether the memory's data must stay out of change events and
+// their history: it is encrypted at rest or its access is restricted.
+func (m *Memory) Protected() bool {
+	return m.Encrypted() || m.AccessLevel == MemoryAccessPrivate || m.Metadata.AccessControl != nil
+}
+
 // MemoryMetadata holds extensible metadata for memory.
 type MemoryMetadata struct {
 	Encryption    *EncryptionMetadata    `json:"encryption,omitempty"`
@@ -104,6 +130,39 @@ type MemoryMetadata struct {
 	Custom        map[string]interface{} `json:"custom,omitempty"`
 }
 
+// MemorySnapshotFilter selects the memory covered by an export or restore. Empty fields
+// match everything.
+type MemorySnapshotFilter struct {
+	Scope     string `json:"scope,omitempty"`
+	ScopeID   string `json:"scope_id,omitempty"`
+	KeyPrefix string `json:"key_prefix,omitempty"`
+}
+
+// Matches reports whether the filter selects the given key.
+func (f MemorySnapshotFilter) Matches(scope, scopeID, key string) bool {
+	return (f.Scope == "" || f.Scope == scope) &&
+		(f.ScopeID == "" || f.ScopeID == scopeID) &&
+		strings.HasPrefix(key, f.KeyPrefix)
+}
+
+// Kinds of memory snapshot records.
+const (
+	MemorySnapshotKindMemory = "memory"
+	MemorySnapshotKindVector = "vector"
+	// MemorySnapshotKindError ends an export that failed part way, so a truncated
+	// export is never mistaken for a complete one.
+	MemorySnapshotKindError = "error"
+)
+
+// MemorySnapshotRecord is one line of a memory export: a key-value entry, a vector or
+// an error, as told by Kind.
+type MemorySnapshotRecord struct {
+	Kind   string        `json:"kind"`
+	Memory *Memory       `json:"memory,omitempty"`
+	Vector *VectorRecord `json:"vector,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
 // VectorRecord represents a stored vector embedding.
 type VectorRecord struct {
 	Scope     string                 `json:"scope"`
@@ -111,20 +170,47 @@ type VectorRecord struct {
 	Key       string                 `json:"key"`
 	Embedding []float32              `json:"embedding"`
 	Metadata  map[string]interface{} `json:"metadata"`
-	CreatedAt time.Time              `json:"created_at"`
-	UpdatedAt time.Time              `json:"updated_at"`
+	// Text is the optional source text of the embedding, indexed for keyword search.
+	Text string `json:"text,omitempty"`
+	// AccessLevel and AccessControl restrict who may read the vector, as for Memory.
+	AccessLevel   string                 `json:"access_level,omitempty"`
+	AccessControl *AccessControlMetadata `json:"access_control,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
 }
 
 // VectorSearchResult represents a similarity search hit.
 type VectorSearchResult struct {
-	Scope     string                 `json:"scope"`
-	ScopeID   string                 `json:"scope_id"`
-	Key       string                 `json:"key"`
-	Score     float64                `json:"score"`
-	Distance  float64                `json:"distance"`
-	Metadata  map[string]interface{} `json:"metadata"`
-	CreatedAt time.Time              `json:"created_at"`
-	UpdatedAt time.Time              `json:"updated_at"`
+	Scope   string `json:"scope"`
+	ScopeID string `json:"scope_id"`
+	Key     string `json:"key"`
+	// Score ranks the results: the similarity for vector searches, the text relevance
+	// for keyword searches and the fused rank score for hybrid searches.
+	Score    float64                `json:"score"`
+	Distance float64                `json:"distance"`
+	Metadata map[string]interface{} `json:"metadata"`
+	Text     string                 `json:"text,omitempty"`
+	// VectorScore and KeywordScore break a hybrid score down into the searches that
+	// found the result.
+	VectorScore   *float64               `json:"vector_score,omitempty"`
+	KeywordScore  *float64               `json:"keyword_score,omitempty"`
+	AccessLevel   string                 `json:"access_level,omitempty"`
+	AccessControl *AccessControlMetadata `json:"access_control,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+// VectorSearchQuery describes a search over vector memory. With both an embedding and
+// text the vector and keyword results are fused with reciprocal rank fusion.
+type VectorSearchQuery struct {
+	Embedding []float32
+	Text      string
+	TopK      int
+	Filters   map[string]interface{}
+	// RRFK dampens the advantage of top ranks in the fusion; 60 when unset.
+	RRFK int
+	// KeywordWeight scales the keyword ranks against the vector ranks; 1 when unset.
+	KeywordWeight float64
 }
 
 // EncryptionMetadata holds encryption-related metadata.
@@ -147,11 +233,26 @@ type AnalyticsMetadata struct {
 	LastAccessed time.Time `json:"last_accessed,omitempty"`
 }
 
+// Memory access levels.
+const (
+	MemoryAccessPublic  = "public"
+	MemoryAccessPrivate = "private"
+)
+
 // AccessControlMetadata holds access control metadata for memory.
 type AccessControlMetadata struct {
-	RequiredRoles  []string `json:"required_roles,omitempty"`
-	TeamRestricted bool     `json:"team_restricted"`
-	AuditAccess    bool     `json:"audit_access"`
+	// RequiredRoles limits access to callers whose API key has one of these roles.
+	RequiredRoles []string `json:"required_roles,omitempty"`
+	// TeamRestricted limits access to callers of the writer's team, excluding
+	// administrators outside it.
+	TeamRestricted bool `json:"team_restricted"`
+	// AuditAccess records every read in the memory event history.
+	AuditAccess bool `json:"audit_access"`
+
+	// The identity that wrote the memory, recorded by the control plane.
+	OwnerAgentID string `json:"owner_agent_id,omitempty"`
+	OwnerKeyID   string `json:"owner_key_id,omitempty"`
+	OwnerTeamID  string `json:"owner_team_id,omitempty"`
 }
 
 // AgentNode represents a registered agent service.
@@ -161,6 +262,10 @@ type AgentNode struct {
 	BaseURL string `json:"base_url" db:"base_url"`
 	Version string `json:"version" db:"version"`
 
+	// InstanceID identifies one replica when several processes register under the
+	// same node ID. It is only set on registration requests.
+	InstanceID string `json:"instance_id,omitempty" db:"-"`
+
 	// Serverless support
 	DeploymentType string  `json:"deployment_type" db:"deployment_type"`         // "long_running" or "serverless"
 	InvocationURL  *string `json:"invocation_url,omitempty" db:"invocation_url"` // For serverless agents
@@ -207,6 +312,7 @@ type ReasonerDefinition struct {
 	OutputSchema json.RawMessage `json:"output_schema"`
 	MemoryConfig MemoryConfig    `json:"memory_config"`
 	Tags         []string        `json:"tags,omitempty"`
+	RetryPolicy  *RetryPolicy    `json:"retry_policy,omitempty"`
 }
 
 // SkillDefinition defines a skill provided by an agent node.
@@ -218,9 +324,39 @@ type SkillDefinition struct {
 
 // MemoryConfig defines memory configuration for a reasoner.
 type MemoryConfig struct {
-	AutoInject      []string `json:"auto_inject"`
-	MemoryRetention string   `json:"memory_retention"`
-	CacheResults    bool     `json:"cache_results"`
+	AutoInject []string `json:"auto_inject"`
+	// MemoryRetention expires the memory the reasoner's executions write without a TTL
+	// of their own; see RetentionTTL for its values.
+	MemoryRetention string `json:"memory_retention"`
+	CacheResults    bool   `json:"cache_results"`
+}
+
+// Memory retention values. MemoryRetention may also be a duration such as "72h".
+const (
+	MemoryRetentionPersistent = "persistent"
+	MemoryRetentionSession    = "session"
+	// SessionMemoryRetention is how long "session" memory is kept after its last write.
+	SessionMemoryRetention = 24 * time.Hour
+)
+
+// RetentionTTL returns the TTL given to memory written without one: none for
+// "persistent" or an empty retention, SessionMemoryRetention for "session", and the
+// duration itself otherwise. Anything else is an error.
+func (c MemoryConfig) RetentionTTL() (*time.Duration, error) {
+	var ttl time.Duration
+	switch c.MemoryRetention {
+	case "", MemoryRetentionPersistent:
+		return nil, nil
+	case MemoryRetentionSession:
+		ttl = SessionMemoryRetention
+	default:
+		parsed, err := time.ParseDuration(c.MemoryRetention)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid memory_retention %q: use %q, %q or a positive duration", c.MemoryRetention, MemoryRetentionPersistent, MemoryRetentionSession)
+		}
+		ttl = parsed
+	}
+	return &ttl, nil
 }
 
 // CommunicationConfig defines communication protocols supported by an agent node.
@@ -586,7 +722,7 @@ type MemoryChangeEvent struct {
 	Scope        string          `json:"scope"`
 	ScopeID      string          `json:"scope_id"`
 	Key          string          `json:"key"`
-	Action       string          `json:"action"` // "set" or "delete"
+	Action       string          `json:"action"` // "set", "delete", "expire", or "access"/"access_denied" for memory_access events
 	Data         json.RawMessage `json:"data,omitempty"`
 	PreviousData json.RawMessage `json:"previous_data,omitempty"`
 	Metadata     EventMetadata   `json:"metadata"`
@@ -12,6 +12,7 @@ const (
 	// Execution webhook event types
 	WebhookEventExecutionCompleted = "execution.completed"
 	WebhookEventExecutionFailed    = "execution.failed"
+	WebhookEventExecutionCancelled = "execution.cancelled"
 )
 
 // ExecutionWebhook captures the persisted webhook registration metadata for an execution.
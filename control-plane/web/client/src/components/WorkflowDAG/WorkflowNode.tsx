@@ -28,6 +28,7 @@ interface WorkflowNodeData {
   started_at: string;
   completed_at?: string;
   duration_ms?: number;
+  retry_count?: number;
   workflow_depth: number;
   selected?: boolean;
   task_name?: string;
@@ -567,6 +568,13 @@ export const WorkflowNode = memo(({ data, selected }: WorkflowNodeProps) => {
               </span>
             </div>
 
+            {data.retry_count ? (
+              <div className="flex items-center justify-between gap-6">
+                <span className="text-muted-foreground">Retries:</span>
+                <span className="font-mono text-foreground">{data.retry_count}</span>
+              </div>
+            ) : null}
+
             <div className="flex items-center justify-between gap-6">
               <span className="flex items-center gap-1 text-muted-foreground">
                 <Calendar size={12} />
@@ -622,6 +630,7 @@ export const WorkflowNode = memo(({ data, selected }: WorkflowNodeProps) => {
     prevProps.data.execution_id === nextProps.data.execution_id &&
     prevProps.data.status === nextProps.data.status &&
     prevProps.data.duration_ms === nextProps.data.duration_ms &&
+    prevProps.data.retry_count === nextProps.data.retry_count &&
     prevProps.data.task_name === nextProps.data.task_name &&
     prevProps.data.agent_name === nextProps.data.agent_name &&
     prevProps.data.started_at === nextProps.data.started_at &&
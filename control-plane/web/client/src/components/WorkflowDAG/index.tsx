@@ -49,6 +49,7 @@ interface WorkflowDAGNode {
   started_at: string;
   completed_at?: string;
   duration_ms?: number;
+  retry_count?: number;
   parent_workflow_id?: string;
   parent_execution_id?: string;
   workflow_depth: number;
@@ -99,6 +100,7 @@ function mapLightweightNode(
     started_at: node.started_at,
     completed_at: node.completed_at,
     duration_ms: node.duration_ms,
+    retry_count: node.retry_count,
     parent_execution_id: node.parent_execution_id,
     workflow_depth: node.workflow_depth,
   };
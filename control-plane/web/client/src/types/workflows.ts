@@ -94,6 +94,7 @@ export interface WorkflowTimelineNode {
   started_at: string;
   completed_at?: string;
   duration_ms?: number;
+  retry_count?: number;
   parent_workflow_id?: string;
   parent_execution_id?: string;
   workflow_depth: number;
@@ -125,6 +126,7 @@ export interface WorkflowDAGLightweightNode {
   started_at: string;
   completed_at?: string;
   duration_ms?: number;
+  retry_count?: number;
   workflow_depth: number;
 }
 
@@ -39,6 +39,12 @@ type ExecutionContext struct {
 	AgentNodeID       string
 	ReasonerName      string
 	StartedAt         time.Time
+
+	// CallerDID, TargetDID and AgentNodeDID carry the DIDs the control plane
+	// sends for executions when its DID system is enabled.
+	CallerDID    string
+	TargetDID    string
+	AgentNodeDID string
 }
 
 func init() {
@@ -98,6 +104,13 @@ func WithDescription(desc string) ReasonerOption {
 	}
 }
 
+// WithRetryPolicy asks the control plane to retry failed calls to this reasoner.
+func WithRetryPolicy(policy types.RetryPolicy) ReasonerOption {
+	return func(r *Reasoner) {
+		r.RetryPolicy = &policy
+	}
+}
+
 // Reasoner represents a single handler exposed by the agent.
 type Reasoner struct {
 	Name         string
@@ -109,6 +122,10 @@ type Reasoner struct {
 	DefaultCLI   bool
 	CLIFormatter func(context.Context, any, error)
 	Description  string
+	RetryPolicy  *types.RetryPolicy
+
+	// validateInput is set for reasoners registered with RegisterTyped.
+	validateInput func(map[string]any) error
 }
 
 // Config drives Agent behaviour.
@@ -121,6 +138,9 @@ type Config struct {
 	PublicURL      string
 	Token          string
 	DeploymentType string
+	// InstanceID distinguishes replicas that run under the same NodeID. The control
+	// plane spreads calls across live instances; leave empty for a single process.
+	InstanceID string
 
 	LeaseRefreshInterval time.Duration
 	DisableLeaseLoop     bool
@@ -136,6 +156,10 @@ type Config struct {
 	// MemoryBackend allows plugging in a custom memory storage backend.
 	// If nil, an in-memory backend is used (data lost on restart).
 	MemoryBackend MemoryBackend
+
+	// DisableDID stops the agent from requesting a DID identity package when it
+	// registers. Without one, the executions it serves are not signed.
+	DisableDID bool
 }
 
 // CLIConfig controls CLI behaviour and presentation.
@@ -156,6 +180,7 @@ type Agent struct {
 	client     *client.Client
 	httpClient *http.Client
 	reasoners  map[string]*Reasoner
+	skills     map[string]*Skill
 	aiClient   *ai.Client // AI/LLM client
 	memory     *Memory    // Memory system for state management
 
@@ -166,12 +191,19 @@ type Agent struct {
 	logger    *log.Logger
 
 	router      http.Handler
+	mux         *http.ServeMux
 	handlerOnce sync.Once
 
 	initMu        sync.Mutex
 	initialized   bool
 	leaseLoopOnce sync.Once
 
+	inflightMu sync.Mutex
+	inflight   map[string]context.CancelFunc
+
+	identityMu sync.RWMutex
+	identity   *types.DIDIdentityPackage
+
 	defaultCLIReasoner string
 }
 
@@ -220,10 +252,12 @@ func New(cfg Config) (*Agent, error) {
 		cfg:        cfg,
 		httpClient: httpClient,
 		reasoners:  make(map[string]*Reasoner),
+		skills:     make(map[string]*Skill),
 		aiClient:   aiClient,
 		memory:     NewMemory(cfg.MemoryBackend),
 		stopLease:  make(chan struct{}),
 		logger:     cfg.Logger,
+		inflight:   make(map[string]context.CancelFunc),
 	}
 
 	if strings.TrimSpace(cfg.AgentFieldURL) != "" {
@@ -283,6 +317,8 @@ func (ec ExecutionContext) ChildContext(agentNodeID, reasonerName string) Execut
 		AgentNodeID:       agentNodeID,
 		ReasonerName:      reasonerName,
 		StartedAt:         time.Now(),
+		CallerDID:         ec.TargetDID,
+		AgentNodeDID:      ec.AgentNodeDID,
 	}
 }
 
@@ -332,6 +368,9 @@ func (a *Agent) RegisterReasoner(name string, handler HandlerFunc, opts ...Reaso
 	if handler == nil {
 		panic("nil handler supplied")
 	}
+	if _, exists := a.skills[name]; exists {
+		panic(fmt.Sprintf("reasoner %q conflicts with a skill of the same name", name))
+	}
 
 	meta := &Reasoner{
 		Name:         name,
@@ -368,13 +407,14 @@ func (a *Agent) Initialize(ctx context.Context) error {
 		return errors.New("AgentFieldURL is required when running in server mode")
 	}
 
-	if len(a.reasoners) == 0 {
-		return errors.New("no reasoners registered")
+	if len(a.reasoners) == 0 && len(a.skills) == 0 {
+		return errors.New("no reasoners or skills registered")
 	}
 
 	if err := a.registerNode(ctx); err != nil {
 		return fmt.Errorf("register node: %w", err)
 	}
+	a.registerDID(ctx)
 
 	if err := a.markReady(ctx); err != nil {
 		a.logger.Printf("warn: initial status update failed: %v", err)
@@ -431,16 +471,18 @@ func (a *Agent) registerNode(ctx context.Context) error {
 			ID:           reasoner.Name,
 			InputSchema:  reasoner.InputSchema,
 			OutputSchema: reasoner.OutputSchema,
+			RetryPolicy:  reasoner.RetryPolicy,
 		})
 	}
 
 	payload := types.NodeRegistrationRequest{
-		ID:        a.cfg.NodeID,
-		TeamID:    a.cfg.TeamID,
-		BaseURL:   strings.TrimSuffix(a.cfg.PublicURL, "/"),
-		Version:   a.cfg.Version,
-		Reasoners: reasoners,
-		Skills:    []types.SkillDefinition{},
+		ID:         a.cfg.NodeID,
+		InstanceID: a.cfg.InstanceID,
+		TeamID:     a.cfg.TeamID,
+		BaseURL:    strings.TrimSuffix(a.cfg.PublicURL, "/"),
+		Version:    a.cfg.Version,
+		Reasoners:  reasoners,
+		Skills:     a.skillDefinitions(),
 		CommunicationConfig: types.CommunicationConfig{
 			Protocols:         []string{"http"},
 			HeartbeatInterval: "0s",
@@ -475,6 +517,7 @@ func (a *Agent) markReady(ctx context.Context) error {
 	_, err := a.client.UpdateStatus(ctx, a.cfg.NodeID, types.NodeStatusUpdate{
 		Phase:       "ready",
 		HealthScore: &score,
+		InstanceID:  a.cfg.InstanceID,
 	})
 	return err
 }
@@ -548,14 +591,14 @@ func (a *Agent) HandleServerlessEvent(ctx context.Context, event map[string]any,
 	execCtx := a.buildExecutionContextFromServerless(&http.Request{Header: http.Header{}}, event, reasoner)
 	ctx = contextWithExecution(ctx, execCtx)
 
-	handler, ok := a.reasoners[reasoner]
+	handler, ok := a.targetHandler(reasoner, stringFromMap(event, "type", "target_type"))
 	if !ok {
 		return map[string]any{"error": "reasoner not found"}, http.StatusNotFound, nil
 	}
 
-	result, err := handler.Handler(ctx, input)
+	result, err := a.invoke(ctx, reasoner, handler, input)
 	if err != nil {
-		return map[string]any{"error": err.Error()}, http.StatusInternalServerError, nil
+		return map[string]any{"error": err.Error()}, errorStatus(err), nil
 	}
 
 	// Normalize to map for consistent JSON responses.
@@ -573,11 +616,22 @@ func (a *Agent) handler() http.Handler {
 		mux.HandleFunc("/execute", a.handleExecute)
 		mux.HandleFunc("/execute/", a.handleExecute)
 		mux.HandleFunc("/reasoners/", a.handleReasoner)
+		mux.HandleFunc("/skills/", a.handleSkill)
+		mux.HandleFunc("/executions/", a.handleExecutionCancel)
+		a.mux = mux
 		a.router = mux
 	})
 	return a.router
 }
 
+// Handle mounts an extra handler on the agent's HTTP server, next to the
+// built-in routes. Packages such as mcp use it to expose their own endpoints.
+// Like http.ServeMux.Handle, it panics if the pattern is already registered.
+func (a *Agent) Handle(pattern string, handler http.Handler) {
+	a.handler()
+	a.mux.Handle(pattern, handler)
+}
+
 func (a *Agent) healthHandler(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]any{"status": "ok"})
 }
@@ -601,6 +655,15 @@ func (a *Agent) discoveryPayload() map[string]any {
 		})
 	}
 
+	skills := make([]map[string]any, 0, len(a.skills))
+	for _, skill := range a.skills {
+		skills = append(skills, map[string]any{
+			"id":           skill.Name,
+			"input_schema": rawToMap(skill.InputSchema),
+			"tags":         skill.Tags,
+		})
+	}
+
 	deployment := strings.TrimSpace(a.cfg.DeploymentType)
 	if deployment == "" {
 		deployment = "long_running"
@@ -611,7 +674,7 @@ func (a *Agent) discoveryPayload() map[string]any {
 		"version":         a.cfg.Version,
 		"deployment_type": deployment,
 		"reasoners":       reasoners,
-		"skills":          []map[string]any{},
+		"skills":          skills,
 	}
 }
 
@@ -646,7 +709,7 @@ func (a *Agent) handleExecute(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	reasoner, ok := a.reasoners[reasonerName]
+	handler, ok := a.targetHandler(reasonerName, stringFromMap(payload, "type", "target_type"))
 	if !ok {
 		http.NotFound(w, r)
 		return
@@ -654,12 +717,14 @@ func (a *Agent) handleExecute(w http.ResponseWriter, r *http.Request) {
 
 	input := extractInputFromServerless(payload)
 	execCtx := a.buildExecutionContextFromServerless(r, payload, reasonerName)
-	ctx := contextWithExecution(r.Context(), execCtx)
+	ctx, release := a.trackExecution(r.Context(), execCtx.ExecutionID)
+	defer release()
+	ctx = contextWithExecution(ctx, execCtx)
 
-	result, err := reasoner.Handler(ctx, input)
+	result, err := a.invoke(ctx, reasonerName, handler, input)
 	if err != nil {
 		a.logger.Printf("reasoner %s failed: %v", reasonerName, err)
-		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
+		writeJSON(w, errorStatus(err), map[string]any{"error": err.Error()})
 		return
 	}
 
@@ -701,6 +766,9 @@ func (a *Agent) buildExecutionContextFromServerless(r *http.Request, payload map
 		AgentNodeID:       a.cfg.NodeID,
 		ReasonerName:      reasonerName,
 		StartedAt:         time.Now(),
+		CallerDID:         strings.TrimSpace(r.Header.Get("X-Caller-DID")),
+		TargetDID:         strings.TrimSpace(r.Header.Get("X-Target-DID")),
+		AgentNodeDID:      strings.TrimSpace(r.Header.Get("X-Agent-Node-DID")),
 	}
 
 	if ctxMap, ok := payload["execution_context"].(map[string]any); ok {
@@ -722,6 +790,15 @@ func (a *Agent) buildExecutionContextFromServerless(r *http.Request, payload map
 		if execCtx.ActorID == "" {
 			execCtx.ActorID = stringFromMap(ctxMap, "actor_id", "actorId")
 		}
+		if execCtx.CallerDID == "" {
+			execCtx.CallerDID = stringFromMap(ctxMap, "caller_did", "callerDid")
+		}
+		if execCtx.TargetDID == "" {
+			execCtx.TargetDID = stringFromMap(ctxMap, "target_did", "targetDid")
+		}
+		if execCtx.AgentNodeDID == "" {
+			execCtx.AgentNodeDID = stringFromMap(ctxMap, "agent_node_did", "agentNodeDid")
+		}
 	}
 
 	if execCtx.RunID == "" {
@@ -767,6 +844,10 @@ func (a *Agent) handleReasoner(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("invalid JSON: %v", err), http.StatusBadRequest)
 		return
 	}
+	if err := reasoner.checkInput(input); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		return
+	}
 
 	execCtx := ExecutionContext{
 		RunID:             r.Header.Get("X-Run-ID"),
@@ -778,6 +859,9 @@ func (a *Agent) handleReasoner(w http.ResponseWriter, r *http.Request) {
 		AgentNodeID:       a.cfg.NodeID,
 		ReasonerName:      name,
 		StartedAt:         time.Now(),
+		CallerDID:         r.Header.Get("X-Caller-DID"),
+		TargetDID:         r.Header.Get("X-Target-DID"),
+		AgentNodeDID:      r.Header.Get("X-Agent-Node-DID"),
 	}
 	if execCtx.WorkflowID == "" {
 		execCtx.WorkflowID = execCtx.RunID
@@ -786,11 +870,11 @@ func (a *Agent) handleReasoner(w http.ResponseWriter, r *http.Request) {
 		execCtx.RootWorkflowID = execCtx.WorkflowID
 	}
 
-	ctx := contextWithExecution(r.Context(), execCtx)
-
 	// In serverless mode we want a synchronous execution so the control plane can return
 	// the result immediately; skip the async path even if an execution ID is present.
-	if a.cfg.DeploymentType != "serverless" && execCtx.ExecutionID != "" && strings.TrimSpace(a.cfg.AgentFieldURL) != "" {
+	// Streaming executions also run synchronously so chunks reach the open response.
+	streaming := wantsEventStream(r)
+	if !streaming && a.cfg.DeploymentType != "serverless" && execCtx.ExecutionID != "" && strings.TrimSpace(a.cfg.AgentFieldURL) != "" {
 		go a.executeReasonerAsync(reasoner, cloneInputMap(input), execCtx)
 		writeJSON(w, http.StatusAccepted, map[string]any{
 			"status":        "processing",
@@ -801,13 +885,22 @@ func (a *Agent) handleReasoner(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := reasoner.Handler(ctx, input)
+	ctx, release := a.trackExecution(r.Context(), execCtx.ExecutionID)
+	defer release()
+	ctx = contextWithExecution(ctx, execCtx)
+
+	if streaming {
+		a.streamReasoner(ctx, w, reasoner, input)
+		return
+	}
+
+	result, err := a.invoke(ctx, name, reasoner.Handler, input)
 	if err != nil {
 		a.logger.Printf("reasoner %s failed: %v", name, err)
 		response := map[string]any{
 			"error": err.Error(),
 		}
-		writeJSON(w, http.StatusInternalServerError, response)
+		writeJSON(w, errorStatus(err), response)
 		return
 	}
 
@@ -815,7 +908,9 @@ func (a *Agent) handleReasoner(w http.ResponseWriter, r *http.Request) {
 }
 
 func (a *Agent) executeReasonerAsync(reasoner *Reasoner, input map[string]any, execCtx ExecutionContext) {
-	ctx := contextWithExecution(context.Background(), execCtx)
+	ctx, release := a.trackExecution(context.Background(), execCtx.ExecutionID)
+	defer release()
+	ctx = contextWithExecution(ctx, execCtx)
 	start := time.Now()
 
 	defer func() {
@@ -836,7 +931,7 @@ func (a *Agent) executeReasonerAsync(reasoner *Reasoner, input map[string]any, e
 		}
 	}()
 
-	result, err := reasoner.Handler(ctx, input)
+	result, err := a.invoke(ctx, reasoner.Name, reasoner.Handler, input)
 	payload := map[string]any{
 		"execution_id":  execCtx.ExecutionID,
 		"run_id":        execCtx.RunID,
@@ -845,7 +940,10 @@ func (a *Agent) executeReasonerAsync(reasoner *Reasoner, input map[string]any, e
 		"reasoner_name": reasoner.Name,
 	}
 
-	if err != nil {
+	if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+		payload["status"] = "cancelled"
+		payload["error"] = err.Error()
+	} else if err != nil {
 		payload["status"] = "failed"
 		payload["error"] = err.Error()
 	} else {
@@ -858,6 +956,63 @@ func (a *Agent) executeReasonerAsync(reasoner *Reasoner, input map[string]any, e
 	}
 }
 
+// trackExecution derives a cancellable context for an in-flight execution so the
+// control plane can stop it through POST /executions/{id}/cancel. The returned func
+// must be called once the handler returns.
+func (a *Agent) trackExecution(parent context.Context, executionID string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+	if executionID == "" {
+		return ctx, cancel
+	}
+
+	a.inflightMu.Lock()
+	a.inflight[executionID] = cancel
+	a.inflightMu.Unlock()
+
+	return ctx, func() {
+		a.inflightMu.Lock()
+		delete(a.inflight, executionID)
+		a.inflightMu.Unlock()
+		cancel()
+	}
+}
+
+// cancelExecution cancels the handler context of an in-flight execution. It reports
+// false when the execution is not running on this agent.
+func (a *Agent) cancelExecution(executionID string) bool {
+	a.inflightMu.Lock()
+	cancel, ok := a.inflight[executionID]
+	a.inflightMu.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+func (a *Agent) handleExecutionCancel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rest := strings.TrimPrefix(r.URL.Path, "/executions/")
+	executionID, action, ok := strings.Cut(rest, "/")
+	if !ok || action != "cancel" || executionID == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	if !a.cancelExecution(executionID) {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "execution not running"})
+		return
+	}
+	a.logger.Printf("execution %s cancelled by control plane", executionID)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"execution_id": executionID,
+		"status":       "cancelled",
+	})
+}
+
 func (a *Agent) sendExecutionStatus(executionID string, payload map[string]any) error {
 	base := strings.TrimSpace(a.cfg.AgentFieldURL)
 	if executionID == "" || base == "" {
@@ -932,6 +1087,7 @@ func (a *Agent) Call(ctx context.Context, target string, input map[string]any) (
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("X-Run-ID", runID)
+	req.Header.Set("X-Agent-Node-ID", a.cfg.NodeID)
 	if execCtx.ExecutionID != "" {
 		req.Header.Set("X-Parent-Execution-ID", execCtx.ExecutionID)
 	}
@@ -944,6 +1100,9 @@ func (a *Agent) Call(ctx context.Context, target string, input map[string]any) (
 	if execCtx.ActorID != "" {
 		req.Header.Set("X-Actor-ID", execCtx.ActorID)
 	}
+	if callerDID := a.callerDID(execCtx); callerDID != "" {
+		req.Header.Set("X-Caller-DID", callerDID)
+	}
 	if a.cfg.Token != "" {
 		req.Header.Set("Authorization", "Bearer "+a.cfg.Token)
 	}
@@ -1117,6 +1276,15 @@ func (a *Agent) buildChildContext(parent ExecutionContext, reasonerName string)
 	return parent.ChildContext(a.cfg.NodeID, reasonerName)
 }
 
+// errorStatus maps a handler error to the HTTP status answering it.
+func errorStatus(err error) int {
+	var invalid *InputValidationError
+	if errors.As(err, &invalid) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
 func writeJSON(w http.ResponseWriter, status int, payload any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -1157,7 +1325,7 @@ func (a *Agent) startLeaseLoop() {
 func (a *Agent) shutdown(ctx context.Context) error {
 	close(a.stopLease)
 
-	if _, err := a.client.Shutdown(ctx, a.cfg.NodeID, types.ShutdownRequest{Reason: "shutdown"}); err != nil {
+	if _, err := a.client.Shutdown(ctx, a.cfg.NodeID, types.ShutdownRequest{Reason: "shutdown", InstanceID: a.cfg.InstanceID}); err != nil {
 		a.logger.Printf("failed to notify shutdown: %v", err)
 	}
 
@@ -242,6 +242,38 @@ func TestInitialize(t *testing.T) {
 	assert.True(t, agent.initialized)
 }
 
+func TestInitialize_RegistersRetryPolicy(t *testing.T) {
+	var registered types.NodeRegistrationRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/nodes" {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&registered))
+			json.NewEncoder(w).Encode(types.NodeRegistrationResponse{ID: "node-1", Success: true})
+			return
+		}
+		json.NewEncoder(w).Encode(types.LeaseResponse{LeaseSeconds: 120})
+	}))
+	defer server.Close()
+
+	agent, err := New(Config{
+		NodeID:           "node-1",
+		Version:          "1.0.0",
+		AgentFieldURL:    server.URL,
+		Logger:           log.New(io.Discard, "", 0),
+		DisableLeaseLoop: true,
+	})
+	require.NoError(t, err)
+
+	agent.RegisterReasoner("flaky", func(ctx context.Context, input map[string]any) (any, error) {
+		return nil, nil
+	}, WithRetryPolicy(types.RetryPolicy{MaxAttempts: 4, RetryableStatusCodes: []int{502}}))
+
+	require.NoError(t, agent.Initialize(context.Background()))
+	require.Len(t, registered.Reasoners, 1)
+	require.NotNil(t, registered.Reasoners[0].RetryPolicy)
+	assert.Equal(t, 4, registered.Reasoners[0].RetryPolicy.MaxAttempts)
+	assert.Equal(t, []int{502}, registered.Reasoners[0].RetryPolicy.RetryableStatusCodes)
+}
+
 func TestInitialize_NoReasoners(t *testing.T) {
 	cfg := Config{
 		NodeID:        "node-1",
@@ -255,7 +287,7 @@ func TestInitialize_NoReasoners(t *testing.T) {
 
 	err = agent.Initialize(context.Background())
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "no reasoners registered")
+	assert.Contains(t, err.Error(), "no reasoners or skills registered")
 }
 
 func TestHandler(t *testing.T) {
@@ -321,6 +353,71 @@ func TestHandleReasoner_Sync(t *testing.T) {
 	assert.Equal(t, float64(42), result["value"]) // JSON numbers are float64
 }
 
+func TestHandleReasoner_StreamsChunks(t *testing.T) {
+	cfg := Config{
+		NodeID:        "node-1",
+		Version:       "1.0.0",
+		AgentFieldURL: "https://api.example.com",
+		Logger:        log.New(io.Discard, "", 0),
+	}
+
+	agent, err := New(cfg)
+	require.NoError(t, err)
+
+	agent.RegisterReasoner("test", func(ctx context.Context, input map[string]any) (any, error) {
+		for _, token := range []string{"hel", "lo"} {
+			if err := EmitChunk(ctx, map[string]any{"token": token}); err != nil {
+				return nil, err
+			}
+		}
+		return map[string]any{"text": "hello"}, nil
+	})
+
+	server := httptest.NewServer(agent.handler())
+	defer server.Close()
+
+	// An execution ID would normally select the async path; streaming stays synchronous.
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/reasoners/test", bytes.NewReader([]byte(`{}`)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream, application/json")
+	req.Header.Set("X-Execution-ID", "exec-1")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "event: chunk\ndata: {\"token\":\"hel\"}\n\n"+
+		"event: chunk\ndata: {\"token\":\"lo\"}\n\n"+
+		"event: result\ndata: {\"text\":\"hello\"}\n\n", string(body))
+}
+
+func TestHandleReasoner_StreamReportsError(t *testing.T) {
+	agent, err := New(Config{NodeID: "node-1", Version: "1.0.0", Logger: log.New(io.Discard, "", 0)})
+	require.NoError(t, err)
+
+	agent.RegisterReasoner("test", func(ctx context.Context, input map[string]any) (any, error) {
+		_ = EmitChunk(ctx, "partial")
+		return nil, assert.AnError
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/reasoners/test", strings.NewReader(`{}`))
+	req.Header.Set("Accept", "text/event-stream")
+	w := httptest.NewRecorder()
+	agent.handler().ServeHTTP(w, req)
+
+	assert.Equal(t, "event: chunk\ndata: \"partial\"\n\n"+
+		"event: error\ndata: {\"error\":\""+assert.AnError.Error()+"\"}\n\n", w.Body.String())
+}
+
+func TestEmitChunk_NoopWithoutStream(t *testing.T) {
+	assert.NoError(t, EmitChunk(context.Background(), "ignored"))
+}
+
 func TestHandleReasoner_NotFound(t *testing.T) {
 	cfg := Config{
 		NodeID:        "node-1",
@@ -407,6 +504,7 @@ func TestCall(t *testing.T) {
 		if strings.Contains(r.URL.Path, "/execute/") {
 			// Verify headers
 			assert.Equal(t, "run-1", r.Header.Get("X-Run-ID"))
+			assert.Equal(t, "node-1", r.Header.Get("X-Agent-Node-ID"))
 			assert.Equal(t, "parent-exec", r.Header.Get("X-Parent-Execution-ID"))
 			assert.Equal(t, "session-1", r.Header.Get("X-Session-ID"))
 			assert.Equal(t, "actor-1", r.Header.Get("X-Actor-ID"))
@@ -732,6 +830,75 @@ func TestHandleReasonerAsyncPostsStatus(t *testing.T) {
 	}
 }
 
+func TestHandleExecutionCancel_CancelsHandlerContext(t *testing.T) {
+	callbackCh := make(chan map[string]any, 1)
+	callbackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err == nil {
+			callbackCh <- payload
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer callbackServer.Close()
+
+	agent, err := New(Config{
+		NodeID:        "node-1",
+		Version:       "1.0.0",
+		AgentFieldURL: callbackServer.URL,
+		Logger:        log.New(io.Discard, "[test] ", 0),
+	})
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	agent.RegisterReasoner("slow", func(ctx context.Context, input map[string]any) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	server := httptest.NewServer(agent.handler())
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/reasoners/slow", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	req.Header.Set("X-Execution-ID", "exec-cancel")
+	req.Header.Set("X-Run-ID", "run-1")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	resp.Body.Close()
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler did not start")
+	}
+
+	cancelResp, err := http.Post(server.URL+"/executions/exec-cancel/cancel", "application/json", nil)
+	require.NoError(t, err)
+	cancelResp.Body.Close()
+	assert.Equal(t, http.StatusOK, cancelResp.StatusCode)
+
+	select {
+	case payload := <-callbackCh:
+		assert.Equal(t, "exec-cancel", payload["execution_id"])
+		assert.Equal(t, "cancelled", payload["status"])
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for cancelled status")
+	}
+
+	// Once the handler has returned the execution is no longer tracked.
+	assert.Eventually(t, func() bool {
+		unknown, err := http.Post(server.URL+"/executions/exec-cancel/cancel", "application/json", nil)
+		if err != nil {
+			return false
+		}
+		unknown.Body.Close()
+		return unknown.StatusCode == http.StatusNotFound
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
 func TestChildContext(t *testing.T) {
 	parent := ExecutionContext{
 		RunID:          "run-1",
@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Agent-Field/agentfield/sdk/go/types"
+)
+
+// approvalPollInterval is how often RequestApproval checks for a decision.
+var approvalPollInterval = 2 * time.Second
+
+// RequestApproval pauses the current execution until a human approves or rejects req
+// in the AgentField control plane, and returns the decided approval. While it waits
+// the execution is reported as waiting_for_approval. If nobody decides before the
+// timeout, the control plane applies the default decision and sets TimedOut.
+//
+// It must be called from within a reasoner so the execution ID is known. Approvals
+// can take hours, so reasoners that request them should be invoked asynchronously;
+// synchronous callers give up long before a human responds.
+//
+// Example usage:
+//
+//	approval, err := a.RequestApproval(ctx, types.ApprovalRequest{
+//		Title:   "Send invoice email",
+//		Payload: draft,
+//	})
+//	if err != nil {
+//		return nil, err
+//	}
+//	if !approval.Approved() {
+//		return map[string]any{"sent": false}, nil
+//	}
+func (a *Agent) RequestApproval(ctx context.Context, req types.ApprovalRequest) (*types.Approval, error) {
+	if a.client == nil {
+		return nil, errors.New("AgentFieldURL is required to request approvals")
+	}
+	if strings.TrimSpace(req.Title) == "" {
+		return nil, errors.New("approval title is required")
+	}
+	executionID := executionContextFrom(ctx).ExecutionID
+	if executionID == "" {
+		return nil, errors.New("approvals can only be requested from within an execution")
+	}
+
+	approval, err := a.client.RequestApproval(ctx, executionID, req)
+	if err != nil {
+		return nil, fmt.Errorf("request approval: %w", err)
+	}
+
+	ticker := time.NewTicker(approvalPollInterval)
+	defer ticker.Stop()
+	for approval.Status == types.ApprovalStatusPending {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+		approval, err = a.client.GetApproval(ctx, executionID, approval.ApprovalID)
+		if err != nil {
+			return nil, fmt.Errorf("poll approval: %w", err)
+		}
+	}
+	return approval, nil
+}
@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/sdk/go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useFastApprovalPolling(t *testing.T) {
+	t.Helper()
+	previous := approvalPollInterval
+	approvalPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { approvalPollInterval = previous })
+}
+
+func TestRequestApproval_WaitsForDecision(t *testing.T) {
+	useFastApprovalPolling(t)
+
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		approval := types.Approval{ApprovalID: "appr-1", ExecutionID: "exec-1", Status: types.ApprovalStatusPending}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/executions/exec-1/approvals":
+			var req types.ApprovalRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "Send invoice", req.Title)
+			assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/executions/exec-1/approvals/appr-1":
+			if atomic.AddInt32(&polls, 1) >= 2 {
+				comment := "ok"
+				approval.Status = types.ApprovalStatusApproved
+				approval.Comment = &comment
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(approval)
+	}))
+	defer server.Close()
+
+	a, err := New(Config{NodeID: "node-1", Version: "1.0.0", AgentFieldURL: server.URL, Token: "secret"})
+	require.NoError(t, err)
+
+	ctx := contextWithExecution(context.Background(), ExecutionContext{ExecutionID: "exec-1"})
+	approval, err := a.RequestApproval(ctx, types.ApprovalRequest{Title: "Send invoice"})
+	require.NoError(t, err)
+	assert.True(t, approval.Approved())
+	assert.Equal(t, "ok", *approval.Comment)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&polls))
+}
+
+func TestRequestApproval_Errors(t *testing.T) {
+	useFastApprovalPolling(t)
+
+	offline, err := New(Config{NodeID: "node-1", Version: "1.0.0"})
+	require.NoError(t, err)
+	ctx := contextWithExecution(context.Background(), ExecutionContext{ExecutionID: "exec-1"})
+	_, err = offline.RequestApproval(ctx, types.ApprovalRequest{Title: "x"})
+	assert.ErrorContains(t, err, "AgentFieldURL")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(types.Approval{ApprovalID: "appr-1", Status: types.ApprovalStatusPending})
+	}))
+	defer server.Close()
+	a, err := New(Config{NodeID: "node-1", Version: "1.0.0", AgentFieldURL: server.URL})
+	require.NoError(t, err)
+
+	_, err = a.RequestApproval(context.Background(), types.ApprovalRequest{Title: "x"})
+	assert.ErrorContains(t, err, "within an execution")
+
+	_, err = a.RequestApproval(ctx, types.ApprovalRequest{})
+	assert.ErrorContains(t, err, "title is required")
+
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = a.RequestApproval(waitCtx, types.ApprovalRequest{Title: "x"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
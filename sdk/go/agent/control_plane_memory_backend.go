@@ -41,6 +41,7 @@ type memoryAPIResponse struct {
 	Data      any    `json:"data"`
 	Scope     string `json:"scope"`
 	ScopeID   string `json:"scope_id"`
+	Version   int64  `json:"version"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
@@ -68,6 +69,10 @@ func (b *ControlPlaneMemoryBackend) Set(scope MemoryScope, scopeID, key string,
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusForbidden {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%w: %s", ErrMemoryAccessDenied, strings.TrimSpace(string(msg)))
+	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		msg, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("memory set failed: status=%d body=%s", resp.StatusCode, strings.TrimSpace(string(msg)))
@@ -75,10 +80,36 @@ func (b *ControlPlaneMemoryBackend) Set(scope MemoryScope, scopeID, key string,
 	return nil
 }
 
+func (b *ControlPlaneMemoryBackend) SetWithAccess(scope MemoryScope, scopeID, key string, value any, policy MemoryAccessPolicy) error {
+	level := "public"
+	if policy.Private {
+		level = "private"
+	}
+	body := map[string]any{
+		"key":          key,
+		"data":         value,
+		"access_level": level,
+	}
+	if len(policy.RequiredRoles) > 0 || policy.TeamRestricted || policy.AuditAccess {
+		body["access_control"] = map[string]any{
+			"required_roles":  policy.RequiredRoles,
+			"team_restricted": policy.TeamRestricted,
+			"audit_access":    policy.AuditAccess,
+		}
+	}
+	_, err := b.write(scope, scopeID, "set", body)
+	return err
+}
+
 func (b *ControlPlaneMemoryBackend) Get(scope MemoryScope, scopeID, key string) (any, bool, error) {
+	val, _, found, err := b.GetVersioned(scope, scopeID, key)
+	return val, found, err
+}
+
+func (b *ControlPlaneMemoryBackend) GetVersioned(scope MemoryScope, scopeID, key string) (any, int64, bool, error) {
 	endpoint, err := url.JoinPath(b.baseURL, "/api/v1/memory/get")
 	if err != nil {
-		return nil, false, err
+		return nil, 0, false, err
 	}
 
 	body := map[string]any{
@@ -87,29 +118,129 @@ func (b *ControlPlaneMemoryBackend) Get(scope MemoryScope, scopeID, key string)
 	}
 	req, err := http.NewRequest(http.MethodPost, endpoint, mustJSONReader(body))
 	if err != nil {
-		return nil, false, err
+		return nil, 0, false, err
 	}
 	b.applyHeaders(req, scope, scopeID)
 
 	resp, err := b.httpClient.Do(req)
 	if err != nil {
-		return nil, false, err
+		return nil, 0, false, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, false, nil
+		return nil, 0, false, nil
+	}
+	if resp.StatusCode == http.StatusForbidden {
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, 0, false, fmt.Errorf("%w: %s", ErrMemoryAccessDenied, strings.TrimSpace(string(msg)))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, 0, false, fmt.Errorf("memory get failed: status=%d body=%s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var mem memoryAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&mem); err != nil {
+		return nil, 0, false, err
+	}
+	return mem.Data, mem.Version, true, nil
+}
+
+func (b *ControlPlaneMemoryBackend) SetIfVersion(scope MemoryScope, scopeID, key string, value any, version int64) (int64, error) {
+	mem, err := b.write(scope, scopeID, "set", map[string]any{
+		"key":        key,
+		"data":       value,
+		"if_version": version,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return mem.Version, nil
+}
+
+func (b *ControlPlaneMemoryBackend) Increment(scope MemoryScope, scopeID, key string, by float64) (float64, error) {
+	mem, err := b.write(scope, scopeID, "increment", map[string]any{
+		"key": key,
+		"by":  by,
+	})
+	if err != nil {
+		return 0, err
+	}
+	total, ok := mem.Data.(float64)
+	if !ok {
+		return 0, fmt.Errorf("memory increment returned %T, want a number", mem.Data)
+	}
+	return total, nil
+}
+
+func (b *ControlPlaneMemoryBackend) Append(scope MemoryScope, scopeID, key string, values ...any) ([]any, error) {
+	if values == nil {
+		values = []any{}
+	}
+	mem, err := b.write(scope, scopeID, "append", map[string]any{
+		"key":    key,
+		"values": values,
+	})
+	if err != nil {
+		return nil, err
+	}
+	list, ok := mem.Data.([]any)
+	if !ok {
+		return nil, fmt.Errorf("memory append returned %T, want a list", mem.Data)
+	}
+	return list, nil
+}
+
+func (b *ControlPlaneMemoryBackend) MergePatch(scope MemoryScope, scopeID, key string, patch map[string]any) (any, error) {
+	mem, err := b.write(scope, scopeID, "merge", map[string]any{
+		"key":   key,
+		"patch": patch,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mem.Data, nil
+}
+
+// write posts a memory write to /api/v1/memory/{op} and returns the stored record.
+func (b *ControlPlaneMemoryBackend) write(scope MemoryScope, scopeID, op string, body map[string]any) (*memoryAPIResponse, error) {
+	endpoint, err := url.JoinPath(b.baseURL, "/api/v1/memory", op)
+	if err != nil {
+		return nil, err
+	}
+
+	body["scope"] = b.apiScope(scope)
+	req, err := http.NewRequest(http.MethodPost, endpoint, mustJSONReader(body))
+	if err != nil {
+		return nil, err
+	}
+	b.applyHeaders(req, scope, scopeID)
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusConflict {
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w: %s", ErrMemoryVersionConflict, strings.TrimSpace(string(msg)))
+	}
+	if resp.StatusCode == http.StatusForbidden {
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w: %s", ErrMemoryAccessDenied, strings.TrimSpace(string(msg)))
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		msg, _ := io.ReadAll(resp.Body)
-		return nil, false, fmt.Errorf("memory get failed: status=%d body=%s", resp.StatusCode, strings.TrimSpace(string(msg)))
+		return nil, fmt.Errorf("memory %s failed: status=%d body=%s", op, resp.StatusCode, strings.TrimSpace(string(msg)))
 	}
 
 	var mem memoryAPIResponse
 	if err := json.NewDecoder(resp.Body).Decode(&mem); err != nil {
-		return nil, false, err
+		return nil, err
 	}
-	return mem.Data, true, nil
+	return &mem, nil
 }
 
 func (b *ControlPlaneMemoryBackend) Delete(scope MemoryScope, scopeID, key string) error {
@@ -183,6 +314,12 @@ func (b *ControlPlaneMemoryBackend) List(scope MemoryScope, scopeID string) ([]s
 }
 
 func (b *ControlPlaneMemoryBackend) SetVector(scope MemoryScope, scopeID, key string, embedding []float64, metadata map[string]any) error {
+	return b.SetVectorWithText(scope, scopeID, key, embedding, "", metadata)
+}
+
+// SetVectorWithText stores a vector with its source text, which the control plane indexes
+// for keyword search.
+func (b *ControlPlaneMemoryBackend) SetVectorWithText(scope MemoryScope, scopeID, key string, embedding []float64, text string, metadata map[string]any) error {
 	endpoint, err := url.JoinPath(b.baseURL, "/api/v1/memory/vector")
 	if err != nil {
 		return err
@@ -200,6 +337,9 @@ func (b *ControlPlaneMemoryBackend) SetVector(scope MemoryScope, scopeID, key st
 		"metadata":  metadata,
 		"scope":     b.apiScope(scope),
 	}
+	if text != "" {
+		body["text"] = text
+	}
 	req, err := http.NewRequest(http.MethodPost, endpoint, mustJSONReader(body))
 	if err != nil {
 		return err
@@ -275,15 +415,26 @@ func (b *ControlPlaneMemoryBackend) SearchVector(scope MemoryScope, scopeID stri
 	}
 
 	body := map[string]any{
-		"query_embedding": embeddingF32,
-		"top_k":           opts.Limit,
-		"threshold":       opts.Threshold,
-		"filters":         opts.Filters,
-		"scope":           b.apiScope(scope),
+		"top_k":     opts.Limit,
+		"threshold": opts.Threshold,
+		"filters":   opts.Filters,
+		"scope":     b.apiScope(scope),
+	}
+	if len(embeddingF32) > 0 {
+		body["query_embedding"] = embeddingF32
 	}
 	if opts.Scope != "" {
 		body["scope"] = b.apiScope(opts.Scope)
 	}
+	if opts.Text != "" {
+		body["query_text"] = opts.Text
+		if opts.RRFK > 0 {
+			body["rrf_k"] = opts.RRFK
+		}
+		if opts.KeywordWeight > 0 {
+			body["keyword_weight"] = opts.KeywordWeight
+		}
+	}
 
 	req, err := http.NewRequest(http.MethodPost, endpoint, mustJSONReader(body))
 	if err != nil {
@@ -308,6 +459,7 @@ func (b *ControlPlaneMemoryBackend) SearchVector(scope MemoryScope, scopeID stri
 		Metadata map[string]any `json:"metadata"`
 		Scope    string         `json:"scope"`
 		ScopeID  string         `json:"scope_id"`
+		Text     string         `json:"text"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&apiResults); err != nil {
 		return nil, err
@@ -321,6 +473,7 @@ func (b *ControlPlaneMemoryBackend) SearchVector(scope MemoryScope, scopeID stri
 			Metadata: r.Metadata,
 			Scope:    MemoryScope(r.Scope),
 			ScopeID:  r.ScopeID,
+			Text:     r.Text,
 		}
 	}
 	return results, nil
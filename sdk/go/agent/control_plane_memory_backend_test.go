@@ -1,7 +1,9 @@
 package agent
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -113,3 +115,161 @@ func TestControlPlaneMemoryBackend_ListReturnsKeys(t *testing.T) {
 		t.Fatalf("keys = %#v", keys)
 	}
 }
+
+func TestControlPlaneMemoryBackend_AtomicOperations(t *testing.T) {
+	var gotPaths []string
+	var gotBodies []map[string]any
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPaths = append(gotPaths, r.URL.Path)
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		gotBodies = append(gotBodies, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/memory/set":
+			if body["if_version"] != float64(3) {
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"error":"version_conflict"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"key":"plan","data":"v4","version":4}`))
+		case "/api/v1/memory/increment":
+			_, _ = w.Write([]byte(`{"key":"count","data":7,"version":7}`))
+		case "/api/v1/memory/append":
+			_, _ = w.Write([]byte(`{"key":"log","data":["a","b"],"version":2}`))
+		case "/api/v1/memory/merge":
+			_, _ = w.Write([]byte(`{"key":"state","data":{"a":1},"version":5}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	scoped := NewMemory(NewControlPlaneMemoryBackend(srv.URL, "", "agent-1")).Scoped(ScopeWorkflow, "wf-1")
+	ctx := context.Background()
+
+	version, err := scoped.SetIfVersion(ctx, "plan", "v4", 3)
+	if err != nil || version != 4 {
+		t.Fatalf("SetIfVersion = %d, %v", version, err)
+	}
+	if _, err := scoped.SetIfVersion(ctx, "plan", "v5", 2); !errors.Is(err, ErrMemoryVersionConflict) {
+		t.Fatalf("stale SetIfVersion error = %v", err)
+	}
+	if total, err := scoped.Increment(ctx, "count", 2); err != nil || total != 7 {
+		t.Fatalf("Increment = %v, %v", total, err)
+	}
+	if list, err := scoped.Append(ctx, "log", "b"); err != nil || len(list) != 2 {
+		t.Fatalf("Append = %v, %v", list, err)
+	}
+	if merged, err := scoped.MergePatch(ctx, "state", map[string]any{"b": nil}); err != nil || merged == nil {
+		t.Fatalf("MergePatch = %v, %v", merged, err)
+	}
+
+	want := []string{"/api/v1/memory/set", "/api/v1/memory/set", "/api/v1/memory/increment", "/api/v1/memory/append", "/api/v1/memory/merge"}
+	if strings.Join(gotPaths, ",") != strings.Join(want, ",") {
+		t.Fatalf("paths = %v", gotPaths)
+	}
+	if gotBodies[2]["by"] != float64(2) || gotBodies[2]["scope"] != "workflow" {
+		t.Fatalf("increment body = %v", gotBodies[2])
+	}
+	if patch, _ := gotBodies[4]["patch"].(map[string]any); patch == nil || patch["b"] != nil {
+		t.Fatalf("merge body = %v", gotBodies[4])
+	}
+}
+
+func TestScopedMemory_AtomicRequiresSupportingBackend(t *testing.T) {
+	scoped := NewMemory(NewInMemoryBackend()).Scoped(ScopeGlobal, "global")
+	if _, err := scoped.Increment(context.Background(), "count", 1); !errors.Is(err, ErrAtomicMemoryUnsupported) {
+		t.Fatalf("Increment error = %v", err)
+	}
+}
+
+func TestControlPlaneMemoryBackend_HybridVectorSearch(t *testing.T) {
+	var bodies []map[string]any
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/memory/vector":
+			_, _ = w.Write([]byte(`{"key":"ticket-1"}`))
+		case "/api/v1/memory/vector/search":
+			_, _ = w.Write([]byte(`[{"key":"ticket-1","score":0.03,"text":"order AB-1234","scope":"session","scope_id":"s-1"}]`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	b := NewControlPlaneMemoryBackend(srv.URL, "", "agent-1")
+	scoped := NewMemory(b).Scoped(ScopeSession, "s-1")
+	ctx := context.Background()
+
+	if err := scoped.SetVectorWithText(ctx, "ticket-1", []float64{0.1}, "order AB-1234", nil); err != nil {
+		t.Fatalf("SetVectorWithText: %v", err)
+	}
+	if bodies[0]["text"] != "order AB-1234" {
+		t.Fatalf("set body = %v", bodies[0])
+	}
+
+	results, err := scoped.SearchVector(ctx, nil, SearchOptions{Limit: 3, Text: "AB-1234", RRFK: 30, KeywordWeight: 1.5})
+	if err != nil {
+		t.Fatalf("SearchVector: %v", err)
+	}
+	search := bodies[1]
+	if _, ok := search["query_embedding"]; ok {
+		t.Fatalf("keyword-only search sent an embedding: %v", search)
+	}
+	if search["query_text"] != "AB-1234" || search["rrf_k"] != float64(30) || search["keyword_weight"] != 1.5 {
+		t.Fatalf("search body = %v", search)
+	}
+	if len(results) != 1 || results[0].Text != "order AB-1234" {
+		t.Fatalf("results = %+v", results)
+	}
+}
+
+func TestControlPlaneMemoryBackend_AccessPolicy(t *testing.T) {
+	var setBody map[string]any
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/memory/set":
+			_ = json.NewDecoder(r.Body).Decode(&setBody)
+			_, _ = w.Write([]byte(`{"key":"plan","data":"secret","version":1}`))
+		case "/api/v1/memory/get":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":"forbidden"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	scoped := NewMemory(NewControlPlaneMemoryBackend(srv.URL, "", "agent-1")).Scoped(ScopeSession, "s-1")
+	ctx := context.Background()
+
+	err := scoped.SetWithAccess(ctx, "plan", "secret", MemoryAccessPolicy{Private: true, AuditAccess: true})
+	if err != nil {
+		t.Fatalf("SetWithAccess error = %v", err)
+	}
+	if setBody["access_level"] != "private" {
+		t.Fatalf("set body = %v", setBody)
+	}
+	if acl, _ := setBody["access_control"].(map[string]any); acl == nil || acl["audit_access"] != true {
+		t.Fatalf("set body = %v", setBody)
+	}
+
+	if _, err := scoped.Get(ctx, "plan"); !errors.Is(err, ErrMemoryAccessDenied) {
+		t.Fatalf("Get error = %v", err)
+	}
+
+	local := NewMemory(NewInMemoryBackend()).Scoped(ScopeGlobal, "global")
+	if err := local.SetWithAccess(ctx, "plan", "x", MemoryAccessPolicy{Private: true}); !errors.Is(err, ErrAccessControlUnsupported) {
+		t.Fatalf("in-memory SetWithAccess error = %v", err)
+	}
+}
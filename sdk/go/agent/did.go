@@ -0,0 +1,161 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Agent-Field/agentfield/sdk/go/client"
+	"github.com/Agent-Field/agentfield/sdk/go/did"
+	"github.com/Agent-Field/agentfield/sdk/go/types"
+)
+
+// vcSubmitTimeout bounds the background submission of an execution credential.
+const vcSubmitTimeout = 10 * time.Second
+
+// Identity returns the DID identity package the control plane issued to the
+// agent, or nil when the agent has none: DID is disabled on either side or the
+// agent has not initialized yet. The package holds private keys.
+func (a *Agent) Identity() *types.DIDIdentityPackage {
+	a.identityMu.RLock()
+	defer a.identityMu.RUnlock()
+	return a.identity
+}
+
+// registerDID requests the agent's identity package. The agent keeps running
+// without one, so failures are only logged.
+func (a *Agent) registerDID(ctx context.Context) {
+	if a.cfg.DisableDID {
+		return
+	}
+
+	reasoners := make([]types.ReasonerDefinition, 0, len(a.reasoners))
+	for _, reasoner := range a.reasoners {
+		reasoners = append(reasoners, types.ReasonerDefinition{ID: reasoner.Name})
+	}
+	resp, err := a.client.RegisterDID(ctx, types.DIDRegistrationRequest{
+		AgentNodeID: a.cfg.NodeID,
+		Reasoners:   reasoners,
+		Skills:      a.skillDefinitions(),
+	})
+	if err != nil {
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			a.logger.Printf("DID is disabled on the control plane; executions will not be signed")
+			return
+		}
+		a.logger.Printf("warn: DID registration failed: %v", err)
+		return
+	}
+
+	a.identityMu.Lock()
+	a.identity = &resp.IdentityPackage
+	a.identityMu.Unlock()
+	a.logger.Printf("node %s registered DID %s", a.cfg.NodeID, resp.IdentityPackage.AgentDID.DID)
+}
+
+// invoke runs the handler of a reasoner or skill for an execution the agent
+// serves and records the outcome in a signed execution credential.
+func (a *Agent) invoke(ctx context.Context, name string, handler HandlerFunc, input map[string]any) (any, error) {
+	start := time.Now()
+	result, err := handler(ctx, input)
+	a.issueExecutionVC(ctx, name, input, result, err, start)
+	return result, err
+}
+
+// issueExecutionVC signs a credential for an execution of name with the key of
+// that reasoner or skill and submits it in the background. Nothing happens
+// without an identity package.
+func (a *Agent) issueExecutionVC(ctx context.Context, name string, input map[string]any, result any, runErr error, start time.Time) {
+	pkg := a.Identity()
+	if pkg == nil || a.client == nil {
+		return
+	}
+	target, ok := did.FunctionIdentity(pkg, name)
+	if !ok {
+		return
+	}
+
+	execCtx := executionContextFrom(ctx)
+	callerDID := execCtx.CallerDID
+	if callerDID == "" {
+		callerDID = pkg.AgentDID.DID
+	}
+	callerType := "agent"
+	if caller, ok := componentByDID(pkg, callerDID); ok {
+		callerType = caller.ComponentType
+	}
+
+	status := "succeeded"
+	errorMessage := ""
+	if runErr != nil {
+		status = "failed"
+		if errors.Is(ctx.Err(), context.Canceled) {
+			status = "cancelled"
+		}
+		errorMessage = runErr.Error()
+	}
+
+	inputData, _ := json.Marshal(input)
+	outputData, _ := json.Marshal(result)
+	doc, err := did.IssueExecutionVC(did.Execution{
+		ExecutionID:  execCtx.ExecutionID,
+		WorkflowID:   execCtx.WorkflowID,
+		SessionID:    execCtx.SessionID,
+		CallerDID:    callerDID,
+		CallerType:   callerType,
+		AgentNodeDID: pkg.AgentDID.DID,
+		Target:       target,
+		Input:        inputData,
+		Output:       outputData,
+		Status:       status,
+		ErrorMessage: errorMessage,
+		StartedAt:    start,
+		Duration:     time.Since(start),
+	})
+	if err != nil {
+		a.logger.Printf("warn: sign execution VC for %s: %v", execCtx.ExecutionID, err)
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), vcSubmitTimeout)
+		defer cancel()
+		if _, err := a.client.SubmitExecutionVC(ctx, doc); err != nil {
+			a.logger.Printf("warn: submit execution VC for %s: %v", execCtx.ExecutionID, err)
+		}
+	}()
+}
+
+// callerDID is the DID the agent presents when it calls out from execCtx: that
+// of the reasoner or skill making the call, or the agent's own.
+func (a *Agent) callerDID(execCtx ExecutionContext) string {
+	if execCtx.TargetDID != "" {
+		return execCtx.TargetDID
+	}
+	pkg := a.Identity()
+	if pkg == nil {
+		return ""
+	}
+	if identity, ok := did.FunctionIdentity(pkg, execCtx.ReasonerName); ok {
+		return identity.DID
+	}
+	return pkg.AgentDID.DID
+}
+
+// componentByDID finds the identity of pkg that owns a DID.
+func componentByDID(pkg *types.DIDIdentityPackage, didValue string) (types.DIDIdentity, bool) {
+	if pkg.AgentDID.DID == didValue {
+		return pkg.AgentDID, true
+	}
+	for _, identities := range []map[string]types.DIDIdentity{pkg.ReasonerDIDs, pkg.SkillDIDs} {
+		for _, identity := range identities {
+			if identity.DID == didValue {
+				return identity, true
+			}
+		}
+	}
+	return types.DIDIdentity{}, false
+}
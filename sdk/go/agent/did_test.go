@@ -0,0 +1,183 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/sdk/go/did"
+	"github.com/Agent-Field/agentfield/sdk/go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testIdentity(t *testing.T, didValue, componentType, function string) types.DIDIdentity {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	x := base64.RawURLEncoding.EncodeToString(pub)
+	d := base64.RawURLEncoding.EncodeToString(priv.Seed())
+	return types.DIDIdentity{
+		DID:           didValue,
+		PrivateKeyJWK: fmt.Sprintf(`{"kty":"OKP","crv":"Ed25519","d":%q,"x":%q}`, d, x),
+		PublicKeyJWK:  fmt.Sprintf(`{"kty":"OKP","crv":"Ed25519","x":%q}`, x),
+		ComponentType: componentType,
+		FunctionName:  function,
+	}
+}
+
+// didControlPlane fakes the registration and DID endpoints. A nil identity
+// package makes the DID routes answer 404, as when DID is disabled.
+func didControlPlane(t *testing.T, pkg *types.DIDIdentityPackage) (*httptest.Server, <-chan types.VCDocument, *int) {
+	t.Helper()
+	vcs := make(chan types.VCDocument, 4)
+	didRegistrations := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/nodes":
+			writeJSON(w, http.StatusOK, types.NodeRegistrationResponse{ID: "node-1", Success: true})
+		case strings.HasSuffix(r.URL.Path, "/status"):
+			writeJSON(w, http.StatusOK, types.LeaseResponse{LeaseSeconds: 120})
+		case pkg == nil:
+			http.NotFound(w, r)
+		case r.URL.Path == "/api/v1/did/register":
+			didRegistrations++
+			var req types.DIDRegistrationRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "node-1", req.AgentNodeID)
+			writeJSON(w, http.StatusOK, types.DIDRegistrationResponse{Success: true, IdentityPackage: *pkg})
+		case r.URL.Path == "/api/v1/execution/vc":
+			var req types.ExecutionVCRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			var doc types.VCDocument
+			assert.NoError(t, json.Unmarshal(req.VCDocument, &doc))
+			vcs <- doc
+			writeJSON(w, http.StatusOK, map[string]any{"vc_id": "vc-1"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, vcs, &didRegistrations
+}
+
+func TestInitialize_RegistersDIDAndSignsExecutions(t *testing.T) {
+	pkg := &types.DIDIdentityPackage{
+		AgentDID:     testIdentity(t, "did:key:node", "agent", ""),
+		ReasonerDIDs: map[string]types.DIDIdentity{"greet": testIdentity(t, "did:key:greet", "reasoner", "greet")},
+		SkillDIDs:    map[string]types.DIDIdentity{},
+	}
+	controlPlane, vcs, _ := didControlPlane(t, pkg)
+
+	a, err := New(Config{
+		NodeID:           "node-1",
+		Version:          "1.0.0",
+		AgentFieldURL:    controlPlane.URL,
+		DeploymentType:   "serverless",
+		Logger:           log.New(io.Discard, "", 0),
+		DisableLeaseLoop: true,
+	})
+	require.NoError(t, err)
+	a.RegisterReasoner("greet", func(ctx context.Context, input map[string]any) (any, error) {
+		return map[string]any{"greeting": fmt.Sprintf("hi %v", input["name"])}, nil
+	})
+
+	require.NoError(t, a.Initialize(context.Background()))
+	require.NotNil(t, a.Identity())
+	assert.Equal(t, "did:key:node", a.Identity().AgentDID.DID)
+
+	req := httptest.NewRequest(http.MethodPost, "/reasoners/greet", bytes.NewReader([]byte(`{"name":"ada"}`)))
+	req.Header.Set("X-Execution-ID", "exec-1")
+	req.Header.Set("X-Workflow-ID", "wf-1")
+	req.Header.Set("X-Caller-DID", "did:key:remote")
+	resp := httptest.NewRecorder()
+	a.Handler().ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var doc types.VCDocument
+	select {
+	case doc = <-vcs:
+	case <-time.After(2 * time.Second):
+		t.Fatal("execution VC was not submitted")
+	}
+
+	assert.Equal(t, "did:key:greet", doc.Issuer)
+	subject := doc.CredentialSubject
+	assert.Equal(t, "exec-1", subject.ExecutionID)
+	assert.Equal(t, "wf-1", subject.WorkflowID)
+	assert.Equal(t, types.VCCaller{DID: "did:key:remote", Type: "agent", AgentNodeDID: "did:key:node"}, subject.Caller)
+	assert.Equal(t, "greet", subject.Target.FunctionName)
+	assert.Equal(t, "succeeded", subject.Execution.Status)
+	assert.Equal(t, did.Hash([]byte(`{"name":"ada"}`)), subject.Execution.InputHash)
+	require.NoError(t, did.Verify(&doc, pkg.ReasonerDIDs["greet"].PublicKeyJWK))
+}
+
+func TestInitialize_DIDDisabledOnControlPlane(t *testing.T) {
+	controlPlane, vcs, _ := didControlPlane(t, nil)
+
+	a, err := New(Config{
+		NodeID:           "node-1",
+		Version:          "1.0.0",
+		AgentFieldURL:    controlPlane.URL,
+		DeploymentType:   "serverless",
+		Logger:           log.New(io.Discard, "", 0),
+		DisableLeaseLoop: true,
+	})
+	require.NoError(t, err)
+	a.RegisterReasoner("greet", func(ctx context.Context, input map[string]any) (any, error) {
+		return map[string]any{"ok": true}, nil
+	})
+
+	require.NoError(t, a.Initialize(context.Background()))
+	assert.Nil(t, a.Identity())
+
+	req := httptest.NewRequest(http.MethodPost, "/reasoners/greet", bytes.NewReader([]byte(`{}`)))
+	resp := httptest.NewRecorder()
+	a.Handler().ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Empty(t, vcs)
+}
+
+func TestInitialize_DisableDID(t *testing.T) {
+	pkg := &types.DIDIdentityPackage{AgentDID: testIdentity(t, "did:key:node", "agent", "")}
+	controlPlane, _, registrations := didControlPlane(t, pkg)
+
+	a, err := New(Config{
+		NodeID:           "node-1",
+		Version:          "1.0.0",
+		AgentFieldURL:    controlPlane.URL,
+		Logger:           log.New(io.Discard, "", 0),
+		DisableLeaseLoop: true,
+		DisableDID:       true,
+	})
+	require.NoError(t, err)
+	a.RegisterReasoner("greet", func(ctx context.Context, input map[string]any) (any, error) { return nil, nil })
+
+	require.NoError(t, a.Initialize(context.Background()))
+	assert.Nil(t, a.Identity())
+	assert.Zero(t, *registrations)
+}
+
+func TestCallerDID(t *testing.T) {
+	a, err := New(Config{NodeID: "node-1", Version: "1.0.0", Logger: log.New(io.Discard, "", 0)})
+	require.NoError(t, err)
+	assert.Empty(t, a.callerDID(ExecutionContext{ReasonerName: "greet"}))
+
+	a.identity = &types.DIDIdentityPackage{
+		AgentDID:     types.DIDIdentity{DID: "did:key:node"},
+		ReasonerDIDs: map[string]types.DIDIdentity{"greet": {DID: "did:key:greet"}},
+	}
+	assert.Equal(t, "did:key:target", a.callerDID(ExecutionContext{TargetDID: "did:key:target"}))
+	assert.Equal(t, "did:key:greet", a.callerDID(ExecutionContext{ReasonerName: "greet"}))
+	assert.Equal(t, "did:key:node", a.callerDID(ExecutionContext{}))
+}
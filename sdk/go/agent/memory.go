@@ -3,6 +3,7 @@ package agent
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"sync"
 )
 
@@ -42,12 +43,90 @@ type MemoryBackend interface {
 	DeleteVector(scope MemoryScope, scopeID, key string) error
 }
 
+// AtomicMemoryBackend is implemented by backends that can update values shared between
+// agents without losing concurrent writes. Every write increases a key's version;
+// missing keys are at version 0.
+type AtomicMemoryBackend interface {
+	// GetVersioned retrieves a value together with its version.
+	GetVersioned(scope MemoryScope, scopeID, key string) (value any, version int64, found bool, err error)
+	// SetIfVersion stores a value only if the key is still at version and returns the new
+	// version. It fails with ErrMemoryVersionConflict otherwise.
+	SetIfVersion(scope MemoryScope, scopeID, key string, value any, version int64) (int64, error)
+	// Increment adds by to a numeric value and returns the result.
+	Increment(scope MemoryScope, scopeID, key string, by float64) (float64, error)
+	// Append adds values to the end of a list value and returns the list.
+	Append(scope MemoryScope, scopeID, key string, values ...any) ([]any, error)
+	// MergePatch applies an RFC 7386 JSON merge patch and returns the merged value.
+	MergePatch(scope MemoryScope, scopeID, key string, patch map[string]any) (any, error)
+}
+
+// VectorTextBackend is implemented by backends that keep the source text of vectors,
+// which lets SearchVector match records by keyword through SearchOptions.Text.
+type VectorTextBackend interface {
+	// SetVectorWithText stores a vector embedding together with the text it embeds.
+	SetVectorWithText(scope MemoryScope, scopeID, key string, embedding []float64, text string, metadata map[string]any) error
+}
+
+// MemoryAccessPolicy restricts who may access a value after it is written. The control
+// plane records the writing agent node and API key as the value's owner; API keys with
+// the admin role bypass Private and RequiredRoles.
+type MemoryAccessPolicy struct {
+	// Private limits access to the owner.
+	Private bool
+	// RequiredRoles limits access to API keys with one of these roles.
+	RequiredRoles []string
+	// TeamRestricted limits access to callers of the owner's team.
+	TeamRestricted bool
+	// AuditAccess records every read in the memory event history.
+	AuditAccess bool
+}
+
+// AccessControlledMemoryBackend is implemented by backends that enforce access policies
+// on the values they store. Reads and writes the policy refuses fail with
+// ErrMemoryAccessDenied; values written by Set keep their policy.
+type AccessControlledMemoryBackend interface {
+	// SetWithAccess stores a value and replaces its access policy.
+	SetWithAccess(scope MemoryScope, scopeID, key string, value any, policy MemoryAccessPolicy) error
+}
+
+var (
+	// ErrMemoryVersionConflict is returned by SetIfVersion when the key changed since
+	// the expected version was read.
+	ErrMemoryVersionConflict = errors.New("memory version conflict")
+	// ErrAtomicMemoryUnsupported is returned by atomic operations on backends that do
+	// not implement AtomicMemoryBackend.
+	ErrAtomicMemoryUnsupported = errors.New("memory backend does not support atomic operations")
+	// ErrVectorTextUnsupported is returned by SetVectorWithText on backends that do not
+	// implement VectorTextBackend.
+	ErrVectorTextUnsupported = errors.New("memory backend does not store vector text")
+	// ErrMemoryAccessDenied is returned when a value's access policy refuses the caller.
+	ErrMemoryAccessDenied = errors.New("memory access denied")
+	// ErrAccessControlUnsupported is returned by SetWithAccess on backends that do not
+	// implement AccessControlledMemoryBackend.
+	ErrAccessControlUnsupported = errors.New("memory backend does not support access control")
+)
+
 // SearchOptions defines parameters for similarity search.
+//
+// Setting Text matches it against the source text stored with SetVectorWithText. With an
+// embedding as well, the keyword and vector rankings are combined with reciprocal rank
+// fusion; without one, results are ranked by keyword alone.
 type SearchOptions struct {
-	Limit     int            `json:"limit"`
-	Threshold float64        `json:"threshold"`
-	Filters   map[string]any `json:"filters"`
-	Scope     MemoryScope    `json:"scope"`
+	Limit     int     `json:"limit"`
+	Threshold float64 `json:"threshold"`
+	// Filters select results by metadata. Values match by JSON equality, so 1 matches
+	// 1.0 but not "1", or by operators:
+	// $eq, $ne, $gt, $gte, $lt, $lte, $in, $nin and $exists, e.g.
+	// {"published": {"$gte": "2024-01-01"}}. Dotted fields such as "doc.type" reach
+	// into nested metadata, and $and/$or take arrays of filters.
+	Filters map[string]any `json:"filters"`
+	Scope   MemoryScope    `json:"scope"`
+	Text    string         `json:"text,omitempty"`
+	// RRFK is the fusion's rank constant; larger values flatten the advantage of top
+	// ranks. Zero uses the server default of 60.
+	RRFK int `json:"rrf_k,omitempty"`
+	// KeywordWeight scales the keyword ranking against the vector ranking. Zero uses 1.
+	KeywordWeight float64 `json:"keyword_weight,omitempty"`
 }
 
 // VectorSearchResult represents a single result from a similarity search.
@@ -57,6 +136,7 @@ type VectorSearchResult struct {
 	Metadata map[string]any `json:"metadata"`
 	Scope    MemoryScope    `json:"scope"`
 	ScopeID  string         `json:"scope_id"`
+	Text     string         `json:"text,omitempty"`
 }
 
 // Memory provides hierarchical state management for agent handlers.
@@ -154,6 +234,18 @@ func (m *Memory) SetVector(ctx context.Context, key string, embedding []float64,
 	return m.backend.SetVector(ScopeSession, scopeID, key, embedding, metadata)
 }
 
+// SetWithAccess stores a value in the session scope (default scope) with an access
+// policy restricting who may read or change it.
+func (m *Memory) SetWithAccess(ctx context.Context, key string, value any, policy MemoryAccessPolicy) error {
+	return m.SessionScope().SetWithAccess(ctx, key, value, policy)
+}
+
+// SetVectorWithText stores a vector and its source text in the session scope (default
+// scope), making it findable by keyword as well as by similarity.
+func (m *Memory) SetVectorWithText(ctx context.Context, key string, embedding []float64, text string, metadata map[string]any) error {
+	return m.SessionScope().SetVectorWithText(ctx, key, embedding, text, metadata)
+}
+
 // GetVector retrieves a vector from the session scope (default scope).
 func (m *Memory) GetVector(ctx context.Context, key string) (embedding []float64, metadata map[string]any, err error) {
 	execCtx := ExecutionContextFrom(ctx)
@@ -302,6 +394,15 @@ func (s *ScopedMemory) SetVector(ctx context.Context, key string, embedding []fl
 	return s.backend.SetVector(s.scope, s.getID(ctx), key, embedding, metadata)
 }
 
+// SetVectorWithText stores a vector and its source text in this scope.
+func (s *ScopedMemory) SetVectorWithText(ctx context.Context, key string, embedding []float64, text string, metadata map[string]any) error {
+	backend, ok := s.backend.(VectorTextBackend)
+	if !ok {
+		return ErrVectorTextUnsupported
+	}
+	return backend.SetVectorWithText(s.scope, s.getID(ctx), key, embedding, text, metadata)
+}
+
 // GetVector retrieves a vector from this scope.
 func (s *ScopedMemory) GetVector(ctx context.Context, key string) (embedding []float64, metadata map[string]any, err error) {
 	embedding, metadata, found, err := s.backend.GetVector(s.scope, s.getID(ctx), key)
@@ -324,6 +425,84 @@ func (s *ScopedMemory) DeleteVector(ctx context.Context, key string) error {
 	return s.backend.DeleteVector(s.scope, s.getID(ctx), key)
 }
 
+// SetWithAccess stores a value with an access policy restricting who may read or change
+// it.
+func (s *ScopedMemory) SetWithAccess(ctx context.Context, key string, value any, policy MemoryAccessPolicy) error {
+	backend, ok := s.backend.(AccessControlledMemoryBackend)
+	if !ok {
+		return ErrAccessControlUnsupported
+	}
+	return backend.SetWithAccess(s.scope, s.getID(ctx), key, value, policy)
+}
+
+// GetVersioned retrieves a value and its version for a later SetIfVersion.
+// Returns version 0 if the key does not exist.
+func (s *ScopedMemory) GetVersioned(ctx context.Context, key string) (any, int64, error) {
+	backend, err := s.atomicBackend()
+	if err != nil {
+		return nil, 0, err
+	}
+	val, version, _, err := backend.GetVersioned(s.scope, s.getID(ctx), key)
+	return val, version, err
+}
+
+// SetIfVersion stores a value only if nobody wrote the key since version was read,
+// and returns the new version. Pass 0 to create a key that must not exist yet.
+//
+// Example usage:
+//
+//	for {
+//		plan, version, err := scoped.GetVersioned(ctx, "plan")
+//		// ... compute next from plan ...
+//		_, err = scoped.SetIfVersion(ctx, "plan", next, version)
+//		if !errors.Is(err, agent.ErrMemoryVersionConflict) {
+//			return err
+//		}
+//	}
+func (s *ScopedMemory) SetIfVersion(ctx context.Context, key string, value any, version int64) (int64, error) {
+	backend, err := s.atomicBackend()
+	if err != nil {
+		return 0, err
+	}
+	return backend.SetIfVersion(s.scope, s.getID(ctx), key, value, version)
+}
+
+// Increment atomically adds by to a numeric value, starting from 0 for missing keys.
+func (s *ScopedMemory) Increment(ctx context.Context, key string, by float64) (float64, error) {
+	backend, err := s.atomicBackend()
+	if err != nil {
+		return 0, err
+	}
+	return backend.Increment(s.scope, s.getID(ctx), key, by)
+}
+
+// Append atomically adds values to the end of a list, creating it for missing keys.
+func (s *ScopedMemory) Append(ctx context.Context, key string, values ...any) ([]any, error) {
+	backend, err := s.atomicBackend()
+	if err != nil {
+		return nil, err
+	}
+	return backend.Append(s.scope, s.getID(ctx), key, values...)
+}
+
+// MergePatch atomically applies a JSON merge patch to an object value. Nil values in
+// patch remove fields.
+func (s *ScopedMemory) MergePatch(ctx context.Context, key string, patch map[string]any) (any, error) {
+	backend, err := s.atomicBackend()
+	if err != nil {
+		return nil, err
+	}
+	return backend.MergePatch(s.scope, s.getID(ctx), key, patch)
+}
+
+func (s *ScopedMemory) atomicBackend() (AtomicMemoryBackend, error) {
+	backend, ok := s.backend.(AtomicMemoryBackend)
+	if !ok {
+		return nil, ErrAtomicMemoryUnsupported
+	}
+	return backend, nil
+}
+
 // GetTyped retrieves a value and unmarshals it into the provided type.
 // This is useful when storing complex objects as JSON.
 func (s *ScopedMemory) GetTyped(ctx context.Context, key string, dest any) error {
@@ -363,6 +542,7 @@ type InMemoryBackend struct {
 type vectorRecord struct {
 	embedding []float64
 	metadata  map[string]any
+	text      string
 }
 
 // NewInMemoryBackend creates a new in-memory storage backend.
@@ -433,6 +613,11 @@ func (b *InMemoryBackend) List(scope MemoryScope, scopeID string) ([]string, err
 
 // SetVector stores a vector.
 func (b *InMemoryBackend) SetVector(scope MemoryScope, scopeID, key string, embedding []float64, metadata map[string]any) error {
+	return b.SetVectorWithText(scope, scopeID, key, embedding, "", metadata)
+}
+
+// SetVectorWithText stores a vector with its source text.
+func (b *InMemoryBackend) SetVectorWithText(scope MemoryScope, scopeID, key string, embedding []float64, text string, metadata map[string]any) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
@@ -443,6 +628,7 @@ func (b *InMemoryBackend) SetVector(scope MemoryScope, scopeID, key string, embe
 	b.vectorData[ck][key] = vectorRecord{
 		embedding: embedding,
 		metadata:  metadata,
+		text:      text,
 	}
 	return nil
 }
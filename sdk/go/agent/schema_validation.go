@@ -0,0 +1,148 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// InputValidationError reports a reasoner input that does not match the
+// reasoner's input schema. The agent answers such inputs with 400 Bad Request
+// without running the handler.
+type InputValidationError struct {
+	Reasoner string
+	Problems []string
+}
+
+func (e *InputValidationError) Error() string {
+	return fmt.Sprintf("invalid input for reasoner %s: %s", e.Reasoner, strings.Join(e.Problems, "; "))
+}
+
+// payloadSchema is a decoded JSON schema. It understands the keywords the
+// derived schemas use: type, enum, properties, required, additionalProperties
+// and items.
+type payloadSchema map[string]any
+
+func compileSchema(raw json.RawMessage) (payloadSchema, error) {
+	var schema payloadSchema
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, fmt.Errorf("decode schema: %w", err)
+	}
+	return schema, nil
+}
+
+// validate checks a value decoded from JSON and returns the problems found,
+// each prefixed with the path of the offending value.
+func (s payloadSchema) validate(value any, path string) []string {
+	if s == nil {
+		return nil
+	}
+	if !s.typeMatches(value) {
+		return []string{fmt.Sprintf("%s: expected %v, got %s", path, s["type"], jsonTypeName(value))}
+	}
+	if enum, ok := s["enum"].([]any); ok && !containsValue(enum, value) {
+		return []string{fmt.Sprintf("%s: %v is not one of %v", path, value, enum)}
+	}
+
+	var problems []string
+	switch v := value.(type) {
+	case map[string]any:
+		properties, _ := s["properties"].(map[string]any)
+		if required, ok := s["required"].([]any); ok {
+			for _, name := range required {
+				if key, ok := name.(string); ok {
+					if _, present := v[key]; !present {
+						problems = append(problems, fmt.Sprintf("%s: missing required field %q", path, key))
+					}
+				}
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			childPath := path + "." + key
+			if property, ok := properties[key].(map[string]any); ok {
+				problems = append(problems, payloadSchema(property).validate(v[key], childPath)...)
+				continue
+			}
+			switch additional := s["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					problems = append(problems, fmt.Sprintf("%s: unknown field", childPath))
+				}
+			case map[string]any:
+				problems = append(problems, payloadSchema(additional).validate(v[key], childPath)...)
+			}
+		}
+	case []any:
+		if items, ok := s["items"].(map[string]any); ok {
+			for i, item := range v {
+				problems = append(problems, payloadSchema(items).validate(item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+	return problems
+}
+
+func (s payloadSchema) typeMatches(value any) bool {
+	switch typ := s["type"].(type) {
+	case string:
+		return jsonTypeMatches(typ, value)
+	case []any:
+		for _, candidate := range typ {
+			if name, ok := candidate.(string); ok && jsonTypeMatches(name, value) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+func jsonTypeMatches(typ string, value any) bool {
+	switch typ {
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	default:
+		return jsonTypeName(value) == typ
+	}
+}
+
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func containsValue(values []any, value any) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
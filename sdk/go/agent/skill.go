@@ -0,0 +1,157 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Agent-Field/agentfield/sdk/go/types"
+)
+
+// SkillOption applies metadata to a skill registration.
+type SkillOption func(*Skill)
+
+// WithSkillInputSchema overrides the auto-generated input schema of a skill.
+func WithSkillInputSchema(raw json.RawMessage) SkillOption {
+	return func(s *Skill) {
+		if len(raw) > 0 {
+			s.InputSchema = raw
+		}
+	}
+}
+
+// WithSkillTags tags a skill for discovery.
+func WithSkillTags(tags ...string) SkillOption {
+	return func(s *Skill) {
+		s.Tags = append(s.Tags, tags...)
+	}
+}
+
+// WithSkillDescription adds a human-readable description of the skill.
+func WithSkillDescription(desc string) SkillOption {
+	return func(s *Skill) {
+		s.Description = desc
+	}
+}
+
+// Skill is a deterministic function exposed by the agent, such as a plain tool
+// function. Unlike a reasoner it does not involve an LLM and always runs
+// synchronously, answering the control plane with its result.
+type Skill struct {
+	Name        string
+	Handler     HandlerFunc
+	InputSchema json.RawMessage
+	Tags        []string
+	Description string
+}
+
+// RegisterSkill makes a handler available at /skills/{name}. Skills and
+// reasoners share one namespace on the control plane, so the name must not be
+// taken by a reasoner.
+func (a *Agent) RegisterSkill(name string, handler HandlerFunc, opts ...SkillOption) {
+	if handler == nil {
+		panic("nil handler supplied")
+	}
+	if _, exists := a.reasoners[name]; exists {
+		panic(fmt.Sprintf("skill %q conflicts with a reasoner of the same name", name))
+	}
+
+	meta := &Skill{
+		Name:        name,
+		Handler:     handler,
+		InputSchema: json.RawMessage(`{"type":"object","additionalProperties":true}`),
+		Tags:        []string{},
+	}
+	for _, opt := range opts {
+		opt(meta)
+	}
+
+	a.skills[name] = meta
+}
+
+func (a *Agent) skillDefinitions() []types.SkillDefinition {
+	skills := make([]types.SkillDefinition, 0, len(a.skills))
+	for _, skill := range a.skills {
+		skills = append(skills, types.SkillDefinition{
+			ID:          skill.Name,
+			InputSchema: skill.InputSchema,
+			Tags:        skill.Tags,
+		})
+	}
+	return skills
+}
+
+// targetHandler finds the handler for an /execute target of the given type, which
+// the control plane sends as "reasoner" or "skill". Without a type, reasoners take
+// precedence, matching how the control plane resolves targets.
+func (a *Agent) targetHandler(name, targetType string) (HandlerFunc, bool) {
+	targetType = strings.ToLower(targetType)
+	if targetType != "skill" {
+		if reasoner, ok := a.reasoners[name]; ok {
+			return reasoner.Handler, true
+		}
+	}
+	if targetType != "reasoner" {
+		if skill, ok := a.skills[name]; ok {
+			return skill.Handler, true
+		}
+	}
+	return nil, false
+}
+
+func (a *Agent) handleSkill(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/skills/")
+	skill, ok := a.skills[name]
+	if name == "" || !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	defer r.Body.Close()
+	var input map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, fmt.Sprintf("invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	execCtx := ExecutionContext{
+		RunID:             r.Header.Get("X-Run-ID"),
+		ExecutionID:       r.Header.Get("X-Execution-ID"),
+		ParentExecutionID: r.Header.Get("X-Parent-Execution-ID"),
+		SessionID:         r.Header.Get("X-Session-ID"),
+		ActorID:           r.Header.Get("X-Actor-ID"),
+		WorkflowID:        r.Header.Get("X-Workflow-ID"),
+		AgentNodeID:       a.cfg.NodeID,
+		ReasonerName:      name,
+		StartedAt:         time.Now(),
+		CallerDID:         r.Header.Get("X-Caller-DID"),
+		TargetDID:         r.Header.Get("X-Target-DID"),
+		AgentNodeDID:      r.Header.Get("X-Agent-Node-DID"),
+	}
+	if execCtx.WorkflowID == "" {
+		execCtx.WorkflowID = execCtx.RunID
+	}
+	if execCtx.RootWorkflowID == "" {
+		execCtx.RootWorkflowID = execCtx.WorkflowID
+	}
+
+	ctx, release := a.trackExecution(r.Context(), execCtx.ExecutionID)
+	defer release()
+	ctx = contextWithExecution(ctx, execCtx)
+
+	result, err := a.invoke(ctx, name, skill.Handler, input)
+	if err != nil {
+		a.logger.Printf("skill %s failed: %v", name, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Agent-Field/agentfield/sdk/go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSkillTestAgent(t *testing.T, agentFieldURL string) *Agent {
+	t.Helper()
+	a, err := New(Config{
+		NodeID:           "node-1",
+		Version:          "1.0.0",
+		AgentFieldURL:    agentFieldURL,
+		Logger:           log.New(io.Discard, "", 0),
+		DisableLeaseLoop: true,
+	})
+	require.NoError(t, err)
+	a.RegisterSkill("add", func(ctx context.Context, input map[string]any) (any, error) {
+		return map[string]any{
+			"sum":   input["a"].(float64) + input["b"].(float64),
+			"skill": ExecutionContextFrom(ctx).ReasonerName,
+		}, nil
+	},
+		WithSkillTags("math"),
+		WithSkillDescription("Adds a and b"),
+		WithSkillInputSchema(json.RawMessage(`{"type":"object","properties":{"a":{"type":"number"},"b":{"type":"number"}}}`)))
+	return a
+}
+
+func TestRegisterSkill(t *testing.T) {
+	a := newSkillTestAgent(t, "https://api.example.com")
+
+	skill := a.skills["add"]
+	require.NotNil(t, skill)
+	assert.Equal(t, []string{"math"}, skill.Tags)
+	assert.Equal(t, "Adds a and b", skill.Description)
+
+	assert.Panics(t, func() { a.RegisterSkill("nil", nil) })
+	assert.Panics(t, func() {
+		a.RegisterReasoner("add", func(ctx context.Context, input map[string]any) (any, error) { return nil, nil })
+	})
+	a.RegisterReasoner("think", func(ctx context.Context, input map[string]any) (any, error) { return nil, nil })
+	assert.Panics(t, func() {
+		a.RegisterSkill("think", func(ctx context.Context, input map[string]any) (any, error) { return nil, nil })
+	})
+}
+
+func TestInitialize_RegistersSkills(t *testing.T) {
+	var registered types.NodeRegistrationRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/nodes" {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&registered))
+			json.NewEncoder(w).Encode(types.NodeRegistrationResponse{ID: "node-1", Success: true})
+			return
+		}
+		json.NewEncoder(w).Encode(types.LeaseResponse{LeaseSeconds: 120})
+	}))
+	defer server.Close()
+
+	a := newSkillTestAgent(t, server.URL)
+	require.NoError(t, a.Initialize(context.Background()), "an agent with only skills can register")
+
+	assert.Empty(t, registered.Reasoners)
+	require.Len(t, registered.Skills, 1)
+	assert.Equal(t, "add", registered.Skills[0].ID)
+	assert.Equal(t, []string{"math"}, registered.Skills[0].Tags)
+	assert.JSONEq(t, `{"type":"object","properties":{"a":{"type":"number"},"b":{"type":"number"}}}`, string(registered.Skills[0].InputSchema))
+
+	payload := a.discoveryPayload()
+	assert.Equal(t, []map[string]any{{
+		"id":           "add",
+		"input_schema": map[string]any{"type": "object", "properties": map[string]any{"a": map[string]any{"type": "number"}, "b": map[string]any{"type": "number"}}},
+		"tags":         []string{"math"},
+	}}, payload["skills"])
+}
+
+func TestHandleSkill(t *testing.T) {
+	a := newSkillTestAgent(t, "https://api.example.com")
+	server := httptest.NewServer(a.Handler())
+	defer server.Close()
+
+	post := func(path, body string) (int, map[string]any) {
+		req, err := http.NewRequest(http.MethodPost, server.URL+path, bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		// Skills run synchronously even when the control plane passes an execution ID.
+		req.Header.Set("X-Execution-ID", "exec-1")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var result map[string]any
+		_ = json.NewDecoder(resp.Body).Decode(&result)
+		return resp.StatusCode, result
+	}
+
+	status, result := post("/skills/add", `{"a":2,"b":3}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, map[string]any{"sum": float64(5), "skill": "add"}, result)
+
+	status, _ = post("/skills/missing", `{}`)
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = post("/reasoners/add", `{}`)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, result = post("/execute", `{"target":"add","type":"skill","input":{"a":1,"b":1}}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, float64(2), result["sum"])
+
+	status, _ = post("/execute", `{"target":"add","type":"reasoner","input":{}}`)
+	assert.Equal(t, http.StatusNotFound, status, "a reasoner target does not fall back to skills")
+}
@@ -0,0 +1,109 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type chunkStreamKey struct{}
+
+// chunkStream writes server-sent events to a caller that requested a streaming
+// execution. Writes after the final event are dropped.
+type chunkStream struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+	closed  bool
+}
+
+func (s *chunkStream) write(event string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode %s event: %w", event, err)
+	}
+	return s.writeEncoded(event, data, false)
+}
+
+// finish writes the final event and closes the stream. A result that cannot be
+// encoded is reported as an error event so the caller is not left waiting.
+func (s *chunkStream) finish(event string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		event = "error"
+		data, _ = json.Marshal(map[string]any{"error": fmt.Sprintf("encode result: %v", err)})
+	}
+	return s.writeEncoded(event, data, true)
+}
+
+func (s *chunkStream) writeEncoded(event string, data []byte, last bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errors.New("execution stream already closed")
+	}
+	s.closed = last
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+	return nil
+}
+
+// EmitChunk sends a piece of partial output from a reasoner to the caller. Chunks are
+// only delivered when the reasoner runs for a streaming execution
+// (POST /api/v1/execute/stream/{target}); otherwise EmitChunk does nothing, so
+// reasoners can emit chunks unconditionally. The reasoner's return value is still the
+// execution result.
+//
+// Example usage:
+//
+//	chunks, errs := a.AIStream(ctx, prompt)
+//	for chunk := range chunks {
+//	    if len(chunk.Choices) > 0 {
+//	        _ = agent.EmitChunk(ctx, chunk.Choices[0].Delta.Content)
+//	    }
+//	}
+func EmitChunk(ctx context.Context, chunk any) error {
+	stream, ok := ctx.Value(chunkStreamKey{}).(*chunkStream)
+	if !ok || stream == nil {
+		return nil
+	}
+	return stream.write("chunk", chunk)
+}
+
+// wantsEventStream reports whether the control plane asked for a streamed response.
+func wantsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// streamReasoner runs a reasoner synchronously and answers with server-sent events:
+// the chunks it emits, then a result or error event.
+func (a *Agent) streamReasoner(ctx context.Context, w http.ResponseWriter, reasoner *Reasoner, input map[string]any) {
+	flusher, _ := w.(http.Flusher)
+	stream := &chunkStream{w: w, flusher: flusher}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	result, err := a.invoke(context.WithValue(ctx, chunkStreamKey{}, stream), reasoner.Name, reasoner.Handler, input)
+	if err != nil {
+		a.logger.Printf("reasoner %s failed: %v", reasoner.Name, err)
+		err = stream.finish("error", map[string]any{"error": err.Error()})
+	} else {
+		err = stream.finish("result", result)
+	}
+	if err != nil {
+		a.logger.Printf("failed to finish stream for reasoner %s: %v", reasoner.Name, err)
+	}
+}
@@ -0,0 +1,164 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/Agent-Field/agentfield/sdk/go/ai"
+)
+
+const defaultMaxToolRounds = 10
+
+var invalidToolNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// AITool exposes a reasoner to the model in AIWithTools.
+type AITool struct {
+	// Target is a reasoner registered on this agent, which is called with
+	// CallLocal, or a "node.reasoner" target, which is called through the
+	// control plane with Call.
+	Target string
+
+	// Name is the tool name shown to the model. It defaults to Target with
+	// characters the model APIs reject replaced by underscores.
+	Name string
+
+	// Description and Parameters default to the local reasoner's description
+	// and input schema.
+	Description string
+	Parameters  json.RawMessage
+}
+
+// ReasonerTools returns tools for the named reasoners of this agent, or for all
+// of them when no names are given.
+func (a *Agent) ReasonerTools(names ...string) []AITool {
+	if len(names) == 0 {
+		for name := range a.reasoners {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	tools := make([]AITool, 0, len(names))
+	for _, name := range names {
+		tools = append(tools, AITool{Target: name})
+	}
+	return tools
+}
+
+// AIWithTools runs an agentic loop: the model is called with the given tools,
+// every tool call it makes is executed and answered, and the model is called
+// again until it responds without calling tools. A failing tool is reported to
+// the model as {"error": "..."} instead of ending the loop. The loop gives up
+// after ai.WithMaxToolRounds model calls, ten by default.
+//
+// Example usage:
+//
+//	response, err := agent.AIWithTools(ctx, "Summarise ticket 42",
+//	    append(agent.ReasonerTools("fetch_ticket"), AITool{Target: "search.lookup"}),
+//	    ai.WithSystem("You are a support assistant"))
+func (a *Agent) AIWithTools(ctx context.Context, prompt string, tools []AITool, opts ...ai.Option) (*ai.Response, error) {
+	if a.aiClient == nil {
+		return nil, errors.New("AI not configured for this agent; set AIConfig in agent Config")
+	}
+
+	var settings ai.Request
+	for _, opt := range opts {
+		if err := opt(&settings); err != nil {
+			return nil, fmt.Errorf("apply option: %w", err)
+		}
+	}
+	maxRounds := settings.MaxToolRounds
+	if maxRounds <= 0 {
+		maxRounds = defaultMaxToolRounds
+	}
+
+	definitions := make([]ai.Tool, 0, len(tools))
+	targets := make(map[string]AITool, len(tools))
+	for _, tool := range tools {
+		definition, err := a.toolDefinition(tool)
+		if err != nil {
+			return nil, err
+		}
+		name := definition.Function.Name
+		if _, exists := targets[name]; exists {
+			return nil, fmt.Errorf("duplicate tool name %q", name)
+		}
+		targets[name] = tool
+		definitions = append(definitions, definition)
+	}
+	opts = append(opts, ai.WithTools(definitions...))
+
+	messages := []ai.Message{{Role: "user", Content: prompt}}
+	for round := 0; round < maxRounds; round++ {
+		response, err := a.aiClient.CompleteWithMessages(ctx, messages, opts...)
+		if err != nil {
+			return nil, err
+		}
+		calls := response.ToolCalls()
+		if len(calls) == 0 {
+			return response, nil
+		}
+
+		messages = append(messages, response.Choices[0].Message)
+		for _, call := range calls {
+			messages = append(messages, ai.ToolResultMessage(call.ID, a.runTool(ctx, targets, call)))
+		}
+	}
+	return nil, fmt.Errorf("model still calling tools after %d rounds", maxRounds)
+}
+
+func (a *Agent) toolDefinition(tool AITool) (ai.Tool, error) {
+	if tool.Target == "" {
+		return ai.Tool{}, errors.New("tool target is required")
+	}
+
+	name := tool.Name
+	if name == "" {
+		name = invalidToolNameChars.ReplaceAllString(tool.Target, "_")
+	}
+	description := tool.Description
+	parameters := tool.Parameters
+	if reasoner, ok := a.reasoners[tool.Target]; ok {
+		if description == "" {
+			description = reasoner.Description
+		}
+		if len(parameters) == 0 {
+			parameters = reasoner.InputSchema
+		}
+	}
+	if len(parameters) == 0 {
+		return ai.NewTool(name, description, nil)
+	}
+	return ai.NewTool(name, description, parameters)
+}
+
+// runTool executes a tool call and returns the content answering it.
+func (a *Agent) runTool(ctx context.Context, targets map[string]AITool, call ai.ToolCall) string {
+	result, err := a.callTool(ctx, targets, call)
+	if err != nil {
+		result = map[string]any{"error": err.Error()}
+	}
+	content, err := json.Marshal(result)
+	if err != nil {
+		content, _ = json.Marshal(map[string]any{"error": fmt.Sprintf("encode result: %v", err)})
+	}
+	return string(content)
+}
+
+func (a *Agent) callTool(ctx context.Context, targets map[string]AITool, call ai.ToolCall) (any, error) {
+	tool, ok := targets[call.Function.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown tool %q", call.Function.Name)
+	}
+	input := map[string]any{}
+	if err := call.DecodeArguments(&input); err != nil {
+		return nil, err
+	}
+	if _, local := a.reasoners[tool.Target]; local {
+		return a.CallLocal(ctx, tool.Target, input)
+	}
+	return a.Call(ctx, tool.Target, input)
+}
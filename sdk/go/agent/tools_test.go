@@ -0,0 +1,113 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Agent-Field/agentfield/sdk/go/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAIWithTools(t *testing.T) {
+	controlPlane := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/workflow/executions/events" {
+			// Local tool calls report workflow events.
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		assert.Equal(t, "/api/v1/execute/search.lookup", r.URL.Path)
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]any{"q": "go"}, body["input"])
+		_ = json.NewEncoder(w).Encode(map[string]any{"status": "succeeded", "result": map[string]any{"hits": 3}})
+	}))
+	defer controlPlane.Close()
+
+	var requests []ai.Request
+	model := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ai.Request
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+
+		message := ai.Message{Role: "assistant", Content: "done"}
+		if len(requests) == 1 {
+			message = ai.Message{Role: "assistant", ToolCalls: []ai.ToolCall{
+				{ID: "c1", Type: "function", Function: ai.FunctionCall{Name: "double", Arguments: `{"n":21}`}},
+				{ID: "c2", Type: "function", Function: ai.FunctionCall{Name: "search_lookup", Arguments: `{"q":"go"}`}},
+				{ID: "c3", Type: "function", Function: ai.FunctionCall{Name: "fail", Arguments: `{}`}},
+			}}
+		}
+		_ = json.NewEncoder(w).Encode(ai.Response{Choices: []ai.Choice{{Message: message}}})
+	}))
+	defer model.Close()
+
+	a, err := New(Config{
+		NodeID:        "node-1",
+		Version:       "1.0.0",
+		AgentFieldURL: controlPlane.URL,
+		Logger:        log.New(io.Discard, "", 0),
+		AIConfig:      &ai.Config{APIKey: "test-key", BaseURL: model.URL, Model: "gpt-4o"},
+	})
+	require.NoError(t, err)
+	a.RegisterReasoner("double", func(ctx context.Context, input map[string]any) (any, error) {
+		return map[string]any{"result": input["n"].(float64) * 2}, nil
+	}, WithDescription("Doubles n"), WithInputSchema(json.RawMessage(`{"type":"object","properties":{"n":{"type":"number"}}}`)))
+	a.RegisterReasoner("fail", func(ctx context.Context, input map[string]any) (any, error) {
+		return nil, errors.New("boom")
+	})
+
+	tools := append(a.ReasonerTools("double", "fail"), AITool{Target: "search.lookup", Description: "Search"})
+	resp, err := a.AIWithTools(context.Background(), "Go", tools, ai.WithSystem("be brief"))
+	require.NoError(t, err)
+	assert.Equal(t, "done", resp.Text())
+
+	require.Len(t, requests, 2)
+	first := requests[0]
+	require.Len(t, first.Tools, 3)
+	assert.Equal(t, "double", first.Tools[0].Function.Name)
+	assert.Equal(t, "Doubles n", first.Tools[0].Function.Description)
+	assert.JSONEq(t, `{"type":"object","properties":{"n":{"type":"number"}}}`, string(first.Tools[0].Function.Parameters))
+	assert.Equal(t, "search_lookup", first.Tools[2].Function.Name)
+
+	second := requests[1].Messages
+	require.Len(t, second, 6)
+	assert.Equal(t, "system", second[0].Role)
+	assert.Len(t, second[2].ToolCalls, 3)
+	assert.Equal(t, ai.Message{Role: "tool", ToolCallID: "c1", Content: `{"result":42}`}, second[3])
+	assert.Equal(t, ai.Message{Role: "tool", ToolCallID: "c2", Content: `{"hits":3}`}, second[4])
+	assert.Equal(t, ai.Message{Role: "tool", ToolCallID: "c3", Content: `{"error":"boom"}`}, second[5])
+}
+
+func TestAIWithTools_GivesUpAfterMaxRounds(t *testing.T) {
+	calls := 0
+	model := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_ = json.NewEncoder(w).Encode(ai.Response{Choices: []ai.Choice{{Message: ai.Message{
+			Role:      "assistant",
+			ToolCalls: []ai.ToolCall{{ID: "c", Type: "function", Function: ai.FunctionCall{Name: "missing"}}},
+		}}}})
+	}))
+	defer model.Close()
+
+	a, err := New(Config{
+		NodeID:   "node-1",
+		Version:  "1.0.0",
+		Logger:   log.New(io.Discard, "", 0),
+		AIConfig: &ai.Config{APIKey: "test-key", BaseURL: model.URL, Model: "gpt-4o"},
+	})
+	require.NoError(t, err)
+
+	_, err = a.AIWithTools(context.Background(), "loop", []AITool{{Target: "other.tool"}}, ai.WithMaxToolRounds(2))
+	assert.ErrorContains(t, err, "after 2 rounds")
+	assert.Equal(t, 2, calls)
+
+	_, err = a.AIWithTools(context.Background(), "dup", []AITool{{Target: "a.b"}, {Target: "a_b"}})
+	assert.ErrorContains(t, err, "duplicate tool name")
+}
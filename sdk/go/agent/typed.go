@@ -0,0 +1,126 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Agent-Field/agentfield/sdk/go/ai"
+)
+
+// RegisterTyped registers a reasoner whose input and output are Go types. The
+// input and output schemas are derived from the types' json and description
+// tags unless WithInputSchema or WithOutputSchema override them. Inputs that do
+// not match the input schema are rejected with 400 Bad Request before handler
+// runs, and an output that does not match the output schema fails the call.
+// Input keys starting with "__" are reserved for AgentField and not validated.
+//
+// Example usage:
+//
+//	type Query struct {
+//	    Text  string `json:"text" description:"Question to answer"`
+//	    Limit int    `json:"limit,omitempty"`
+//	}
+//	type Answer struct {
+//	    Text string `json:"text"`
+//	}
+//
+//	agent.RegisterTyped(a, "answer", func(ctx context.Context, q Query) (Answer, error) {
+//	    return Answer{Text: "42"}, nil
+//	})
+func RegisterTyped[In, Out any](a *Agent, name string, handler func(ctx context.Context, input In) (Out, error), opts ...ReasonerOption) {
+	if handler == nil {
+		panic("nil handler supplied")
+	}
+
+	inType := reflect.TypeOf((*In)(nil)).Elem()
+	for inType.Kind() == reflect.Ptr {
+		inType = inType.Elem()
+	}
+	if inType.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("typed reasoner %s: input must be a struct, got %v", name, inType))
+	}
+	inputSchema := mustMarshalSchema(name, ai.SchemaFor(inType))
+	outputSchema := mustMarshalSchema(name, ai.SchemaFor(reflect.TypeOf((*Out)(nil)).Elem()))
+
+	var reasoner *Reasoner
+	var output payloadSchema
+	typed := func(ctx context.Context, input map[string]any) (any, error) {
+		if err := reasoner.checkInput(input); err != nil {
+			return nil, err
+		}
+		raw, err := json.Marshal(input)
+		if err != nil {
+			return nil, fmt.Errorf("encode input: %w", err)
+		}
+		var in In
+		if err := json.Unmarshal(raw, &in); err != nil {
+			return nil, &InputValidationError{Reasoner: name, Problems: []string{err.Error()}}
+		}
+
+		out, err := handler(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		if problems := validateValue(output, out, "output"); len(problems) > 0 {
+			return nil, fmt.Errorf("reasoner %s returned output that does not match its schema: %s", name, strings.Join(problems, "; "))
+		}
+		return out, nil
+	}
+
+	opts = append([]ReasonerOption{WithInputSchema(inputSchema), WithOutputSchema(outputSchema)}, opts...)
+	a.RegisterReasoner(name, typed, opts...)
+	reasoner = a.reasoners[name]
+
+	input, err := compileSchema(reasoner.InputSchema)
+	if err != nil {
+		panic(fmt.Sprintf("typed reasoner %s: input schema: %v", name, err))
+	}
+	if output, err = compileSchema(reasoner.OutputSchema); err != nil {
+		panic(fmt.Sprintf("typed reasoner %s: output schema: %v", name, err))
+	}
+	reasoner.validateInput = func(values map[string]any) error {
+		unreserved := make(map[string]any, len(values))
+		for key, value := range values {
+			if !strings.HasPrefix(key, "__") {
+				unreserved[key] = value
+			}
+		}
+		if problems := validateValue(input, unreserved, "input"); len(problems) > 0 {
+			return &InputValidationError{Reasoner: name, Problems: problems}
+		}
+		return nil
+	}
+}
+
+// checkInput validates an input against the reasoner's input schema when the
+// reasoner was registered with RegisterTyped.
+func (r *Reasoner) checkInput(input map[string]any) error {
+	if r == nil || r.validateInput == nil {
+		return nil
+	}
+	return r.validateInput(input)
+}
+
+// validateValue validates a Go value by the JSON encoding/json produces for it.
+func validateValue(schema payloadSchema, value any, path string) []string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", path, err)}
+	}
+	var decoded any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return []string{fmt.Sprintf("%s: %v", path, err)}
+	}
+	return schema.validate(decoded, path)
+}
+
+func mustMarshalSchema(name string, schema map[string]interface{}) json.RawMessage {
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		panic(fmt.Sprintf("typed reasoner %s: encode schema: %v", name, err))
+	}
+	return raw
+}
@@ -0,0 +1,128 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type typedQuery struct {
+	Text  string   `json:"text" description:"Question to answer"`
+	Limit int      `json:"limit,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+type typedAnswer struct {
+	Text  string `json:"text"`
+	Score int    `json:"score"`
+}
+
+func newTypedTestAgent(t *testing.T) (*Agent, *int) {
+	t.Helper()
+	a, err := New(Config{
+		NodeID:        "node-1",
+		Version:       "1.0.0",
+		AgentFieldURL: "https://api.example.com",
+		Logger:        log.New(io.Discard, "", 0),
+	})
+	require.NoError(t, err)
+
+	calls := 0
+	RegisterTyped(a, "answer", func(ctx context.Context, q typedQuery) (typedAnswer, error) {
+		calls++
+		return typedAnswer{Text: q.Text + "!", Score: q.Limit}, nil
+	}, WithDescription("Answers questions"))
+	return a, &calls
+}
+
+func TestRegisterTyped_DerivesSchemas(t *testing.T) {
+	a, _ := newTypedTestAgent(t)
+
+	reasoner := a.reasoners["answer"]
+	require.NotNil(t, reasoner)
+	assert.Equal(t, "Answers questions", reasoner.Description)
+	assert.JSONEq(t, `{
+		"type":"object",
+		"properties":{
+			"text":{"type":"string","description":"Question to answer"},
+			"limit":{"type":"integer"},
+			"tags":{"type":["array","null"],"items":{"type":"string"}}
+		},
+		"required":["text"],
+		"additionalProperties":false
+	}`, string(reasoner.InputSchema))
+	assert.JSONEq(t, `{
+		"type":"object",
+		"properties":{"text":{"type":"string"},"score":{"type":"integer"}},
+		"required":["text","score"],
+		"additionalProperties":false
+	}`, string(reasoner.OutputSchema))
+
+	assert.Panics(t, func() {
+		RegisterTyped(a, "scalar", func(ctx context.Context, in string) (string, error) { return in, nil })
+	})
+}
+
+func TestRegisterTyped_ValidatesInput(t *testing.T) {
+	a, calls := newTypedTestAgent(t)
+	server := httptest.NewServer(a.Handler())
+	defer server.Close()
+
+	post := func(body string) (int, map[string]any) {
+		resp, err := http.Post(server.URL+"/reasoners/answer", "application/json", bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var result map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return resp.StatusCode, result
+	}
+
+	status, result := post(`{"text":"hi","limit":3,"__memory":{"profile":{}}}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, map[string]any{"text": "hi!", "score": float64(3)}, result)
+
+	for body, problem := range map[string]string{
+		`{"limit":3}`:                "missing required field \"text\"",
+		`{"text":"hi","limit":1.5}`:  "input.limit: expected integer, got number",
+		`{"text":"hi","extra":true}`: "input.extra: unknown field",
+		`{"text":"hi","tags":[1]}`:   "input.tags[0]: expected string, got number",
+	} {
+		status, result := post(body)
+		assert.Equal(t, http.StatusBadRequest, status, body)
+		assert.Contains(t, result["error"], problem, body)
+	}
+	assert.Equal(t, 1, *calls, "invalid inputs never reach the handler")
+
+	// Local calls are validated as well.
+	_, err := a.Execute(context.Background(), "answer", map[string]any{"text": 7})
+	var invalid *InputValidationError
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "answer", invalid.Reasoner)
+}
+
+func TestRegisterTyped_ValidatesOutput(t *testing.T) {
+	a, err := New(Config{NodeID: "node-1", Version: "1.0.0", Logger: log.New(io.Discard, "", 0)})
+	require.NoError(t, err)
+
+	type loose struct {
+		Value any `json:"value"`
+	}
+	RegisterTyped(a, "declared", func(ctx context.Context, in loose) (map[string]any, error) {
+		return map[string]any{"value": in.Value}, nil
+	}, WithOutputSchema(json.RawMessage(`{"type":"object","properties":{"value":{"type":"string"}},"required":["value"]}`)))
+
+	out, err := a.Execute(context.Background(), "declared", map[string]any{"value": "ok"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"value": "ok"}, out)
+
+	_, err = a.Execute(context.Background(), "declared", map[string]any{"value": 5})
+	assert.ErrorContains(t, err, "output.value: expected string, got number")
+}
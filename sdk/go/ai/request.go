@@ -10,6 +10,12 @@ import (
 type Message struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+
+	// ToolCalls are the tools an assistant message asks to call.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+
+	// ToolCallID links a "tool" message to the call it answers.
+	ToolCallID string `json:"tool_call_id,omitempty"`
 }
 
 // Request represents an AI completion request.
@@ -35,6 +41,16 @@ type Request struct {
 
 	// Response format for structured outputs
 	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+
+	// Tools the model may call
+	Tools []Tool `json:"tools,omitempty"`
+
+	// ToolChoice is "auto", "none", "required" or a ToolChoiceFunction
+	ToolChoice interface{} `json:"tool_choice,omitempty"`
+
+	// MaxToolRounds limits how often an agentic tool loop calls the model.
+	// It is not sent to the API.
+	MaxToolRounds int `json:"-"`
 }
 
 // ResponseFormat specifies the desired output format.
@@ -154,10 +170,12 @@ func WithSchema(schema interface{}) Option {
 }
 
 // structToJSONSchema converts a Go struct to a JSON schema.
-// This is a simplified version - you may want to use a library like
-// github.com/invopop/jsonschema for production.
+// Nested structs and slice elements are described as well; see SchemaFor.
 func structToJSONSchema(v interface{}) (map[string]interface{}, string, error) {
 	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, "", fmt.Errorf("schema must be a struct, got nil")
+	}
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
@@ -170,55 +188,7 @@ func structToJSONSchema(v interface{}) (map[string]interface{}, string, error) {
 		schemaName = "response"
 	}
 
-	properties := make(map[string]interface{})
-	required := []string{}
-
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		jsonTag := field.Tag.Get("json")
-		if jsonTag == "" || jsonTag == "-" {
-			continue
-		}
-
-		// Parse json tag (e.g., "name,omitempty")
-		fieldName := jsonTag
-		isRequired := true
-		if idx := len(jsonTag); idx > 0 {
-			for j, c := range jsonTag {
-				if c == ',' {
-					fieldName = jsonTag[:j]
-					if len(jsonTag) > j+1 && jsonTag[j+1:] == "omitempty" {
-						isRequired = false
-					}
-					break
-				}
-			}
-		}
-
-		// Build property schema
-		prop := make(map[string]interface{})
-		prop["type"] = goTypeToJSONType(field.Type)
-
-		// Add description from struct tag if present
-		if desc := field.Tag.Get("description"); desc != "" {
-			prop["description"] = desc
-		}
-
-		properties[fieldName] = prop
-
-		if isRequired {
-			required = append(required, fieldName)
-		}
-	}
-
-	schema := map[string]interface{}{
-		"type":                 "object",
-		"properties":           properties,
-		"required":             required,
-		"additionalProperties": false,
-	}
-
-	return schema, schemaName, nil
+	return structSchema(t, map[reflect.Type]bool{}), schemaName, nil
 }
 
 // goTypeToJSONType converts Go types to JSON schema types.
@@ -47,8 +47,9 @@ type StreamDelta struct {
 
 // MessageDelta represents the incremental message content.
 type MessageDelta struct {
-	Role    string `json:"role,omitempty"`
-	Content string `json:"content,omitempty"`
+	Role      string          `json:"role,omitempty"`
+	Content   string          `json:"content,omitempty"`
+	ToolCalls []ToolCallDelta `json:"tool_calls,omitempty"`
 }
 
 // ErrorResponse represents an error from the API.
@@ -71,6 +72,14 @@ func (r *Response) Text() string {
 	return r.Choices[0].Message.Content
 }
 
+// ToolCalls returns the tool calls requested by the first choice.
+func (r *Response) ToolCalls() []ToolCall {
+	if len(r.Choices) == 0 {
+		return nil
+	}
+	return r.Choices[0].Message.ToolCalls
+}
+
 // JSON parses the response content as JSON into the provided destination.
 func (r *Response) JSON(dest interface{}) error {
 	content := r.Text()
@@ -0,0 +1,122 @@
+package ai
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// SchemaFor derives the JSON schema of the values encoding/json produces for t.
+// Struct fields follow their json tags: fields tagged "-" are skipped, fields
+// with omitempty are optional and all others are required. A description tag
+// becomes the property description. Pointers, slices and maps may be null, as
+// encoding/json writes nil ones that way.
+func SchemaFor(t reflect.Type) map[string]interface{} {
+	return typeSchema(t, map[reflect.Type]bool{})
+}
+
+// typeSchema describes t. seen holds the structs being described, so that
+// recursive types end in a plain object instead of looping.
+func typeSchema(t reflect.Type, seen map[reflect.Type]bool) map[string]interface{} {
+	if t == nil {
+		return map[string]interface{}{}
+	}
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		nullable = true
+	}
+	if t.Kind() == reflect.Map || (t.Kind() == reflect.Slice && t != rawMessageType) {
+		nullable = true
+	}
+
+	schema := nonNullSchema(t, seen)
+	if typ, ok := schema["type"].(string); ok && nullable {
+		schema["type"] = []interface{}{typ, "null"}
+	}
+	return schema
+}
+
+func nonNullSchema(t reflect.Type, seen map[reflect.Type]bool) map[string]interface{} {
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == rawMessageType, t.Kind() == reflect.Interface:
+		// Any JSON value.
+		return map[string]interface{}{}
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		// encoding/json writes []byte as a base64 string.
+		return map[string]interface{}{"type": "string"}
+	case t.Kind() == reflect.Slice, t.Kind() == reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem(), seen)}
+	case t.Kind() == reflect.Struct:
+		if seen[t] {
+			return map[string]interface{}{"type": "object"}
+		}
+		return structSchema(t, seen)
+	default:
+		return map[string]interface{}{"type": goTypeToJSONType(t)}
+	}
+}
+
+func structSchema(t reflect.Type, seen map[reflect.Type]bool) map[string]interface{} {
+	seen[t] = true
+	defer delete(seen, t)
+
+	properties := make(map[string]interface{})
+	required := []string{}
+	addStructFields(t, properties, &required, seen)
+
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// addStructFields adds the fields of t to properties, promoting the fields of
+// untagged embedded structs the way encoding/json does.
+func addStructFields(t reflect.Type, properties map[string]interface{}, required *[]string, seen map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(jsonTag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				addStructFields(embedded, properties, required, seen)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := typeSchema(field.Type, seen)
+		if desc := field.Tag.Get("description"); desc != "" {
+			prop["description"] = desc
+		}
+		properties[name] = prop
+
+		if !strings.Contains(","+options+",", ",omitempty,") {
+			*required = append(*required, name)
+		}
+	}
+}
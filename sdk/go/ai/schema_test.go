@@ -0,0 +1,69 @@
+package ai
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaFor(t *testing.T) {
+	type Base struct {
+		ID string `json:"id"`
+	}
+	type Node struct {
+		Name     string  `json:"name"`
+		Children []*Node `json:"children,omitempty"`
+	}
+	type Document struct {
+		Base
+		Title    string            `json:"title"`
+		Created  time.Time         `json:"created"`
+		Payload  json.RawMessage   `json:"payload,omitempty"`
+		Data     []byte            `json:"data,omitempty"`
+		Labels   map[string]string `json:"labels,omitempty"`
+		Parent   *Base             `json:"parent"`
+		Tree     Node              `json:"tree"`
+		Count    int
+		internal string
+		Skipped  string `json:"-"`
+	}
+
+	raw, err := json.Marshal(SchemaFor(reflect.TypeOf(Document{})))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type":"object",
+		"properties":{
+			"id":{"type":"string"},
+			"title":{"type":"string"},
+			"created":{"type":"string","format":"date-time"},
+			"payload":{},
+			"data":{"type":["string","null"]},
+			"labels":{"type":["object","null"]},
+			"parent":{
+				"type":["object","null"],
+				"properties":{"id":{"type":"string"}},
+				"required":["id"],
+				"additionalProperties":false
+			},
+			"tree":{
+				"type":"object",
+				"properties":{
+					"name":{"type":"string"},
+					"children":{"type":["array","null"],"items":{"type":["object","null"]}}
+				},
+				"required":["name"],
+				"additionalProperties":false
+			},
+			"Count":{"type":"integer"}
+		},
+		"required":["id","title","created","parent","tree","Count"],
+		"additionalProperties":false
+	}`, string(raw))
+
+	assert.Equal(t, map[string]interface{}{"type": "string"}, SchemaFor(reflect.TypeOf("")))
+	assert.Equal(t, map[string]interface{}{"type": []interface{}{"integer", "null"}}, SchemaFor(reflect.TypeOf((*int)(nil))))
+}
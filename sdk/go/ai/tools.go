@@ -0,0 +1,201 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Tool describes a function the model may call.
+type Tool struct {
+	Type     string       `json:"type"` // always "function"
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction is the callable part of a Tool.
+type ToolFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+// ToolCall is a call to a tool requested by the model.
+type ToolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function FunctionCall `json:"function"`
+}
+
+// FunctionCall names the called function and carries its JSON-encoded arguments.
+type FunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// ToolCallDelta is a fragment of a tool call in a streaming response. The
+// fragments of one call share an Index; ID and name arrive with the first
+// fragment, and the arguments are spread over all of them.
+type ToolCallDelta struct {
+	Index    int          `json:"index"`
+	ID       string       `json:"id,omitempty"`
+	Type     string       `json:"type,omitempty"`
+	Function FunctionCall `json:"function"`
+}
+
+// ToolChoiceFunction forces the model to call the named tool.
+type ToolChoiceFunction struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name string `json:"name"`
+	} `json:"function"`
+}
+
+// NewTool creates a tool definition. Parameters may be a Go struct, which is
+// converted to a JSON schema like WithSchema does, or a raw JSON schema given
+// as json.RawMessage, []byte or string. A nil parameters value accepts any object.
+func NewTool(name, description string, parameters interface{}) (Tool, error) {
+	if name == "" {
+		return Tool{}, fmt.Errorf("tool name is required")
+	}
+
+	var schema json.RawMessage
+	switch v := parameters.(type) {
+	case nil:
+		schema = json.RawMessage(`{"type":"object","properties":{}}`)
+	case json.RawMessage:
+		schema = v
+	case []byte:
+		schema = json.RawMessage(v)
+	case string:
+		schema = json.RawMessage(v)
+	default:
+		schemaMap, _, err := structToJSONSchema(v)
+		if err != nil {
+			return Tool{}, fmt.Errorf("convert parameters of tool %s: %w", name, err)
+		}
+		schema, err = json.Marshal(schemaMap)
+		if err != nil {
+			return Tool{}, fmt.Errorf("marshal parameters of tool %s: %w", name, err)
+		}
+	}
+	if !json.Valid(schema) {
+		return Tool{}, fmt.Errorf("parameters of tool %s are not valid JSON", name)
+	}
+
+	return Tool{
+		Type: "function",
+		Function: ToolFunction{
+			Name:        name,
+			Description: description,
+			Parameters:  schema,
+		},
+	}, nil
+}
+
+// WithTools makes the given tools available to the model.
+func WithTools(tools ...Tool) Option {
+	return func(r *Request) error {
+		r.Tools = append(r.Tools, tools...)
+		return nil
+	}
+}
+
+// WithToolChoice controls whether the model calls tools: "auto", "none" or
+// "required".
+func WithToolChoice(choice string) Option {
+	return func(r *Request) error {
+		switch choice {
+		case "auto", "none", "required":
+			r.ToolChoice = choice
+			return nil
+		default:
+			return fmt.Errorf("invalid tool choice %q", choice)
+		}
+	}
+}
+
+// WithToolChoiceFunction forces the model to call the named tool.
+func WithToolChoiceFunction(name string) Option {
+	return func(r *Request) error {
+		choice := ToolChoiceFunction{Type: "function"}
+		choice.Function.Name = name
+		r.ToolChoice = choice
+		return nil
+	}
+}
+
+// WithMaxToolRounds limits how often an agentic tool loop calls the model
+// before giving up.
+func WithMaxToolRounds(rounds int) Option {
+	return func(r *Request) error {
+		if rounds <= 0 {
+			return fmt.Errorf("max tool rounds must be positive, got %d", rounds)
+		}
+		r.MaxToolRounds = rounds
+		return nil
+	}
+}
+
+// DecodeArguments parses the call's arguments into dest.
+func (c ToolCall) DecodeArguments(dest interface{}) error {
+	args := c.Function.Arguments
+	if args == "" {
+		args = "{}"
+	}
+	if err := json.Unmarshal([]byte(args), dest); err != nil {
+		return fmt.Errorf("decode arguments of tool %s: %w", c.Function.Name, err)
+	}
+	return nil
+}
+
+// ToolResultMessage answers a tool call with the tool's output.
+func ToolResultMessage(callID, content string) Message {
+	return Message{Role: "tool", ToolCallID: callID, Content: content}
+}
+
+// ToolCallAccumulator assembles complete tool calls from streaming chunks.
+type ToolCallAccumulator struct {
+	calls map[int]*ToolCall
+}
+
+// Add merges the tool call fragments of a chunk's first choice.
+func (a *ToolCallAccumulator) Add(chunk StreamChunk) {
+	if len(chunk.Choices) == 0 {
+		return
+	}
+	for _, delta := range chunk.Choices[0].Delta.ToolCalls {
+		if a.calls == nil {
+			a.calls = make(map[int]*ToolCall)
+		}
+		call, ok := a.calls[delta.Index]
+		if !ok {
+			call = &ToolCall{Type: "function"}
+			a.calls[delta.Index] = call
+		}
+		if delta.ID != "" {
+			call.ID = delta.ID
+		}
+		if delta.Type != "" {
+			call.Type = delta.Type
+		}
+		if delta.Function.Name != "" {
+			call.Function.Name = delta.Function.Name
+		}
+		call.Function.Arguments += delta.Function.Arguments
+	}
+}
+
+// ToolCalls returns the calls assembled so far, in stream order.
+func (a *ToolCallAccumulator) ToolCalls() []ToolCall {
+	indexes := make([]int, 0, len(a.calls))
+	for index := range a.calls {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	calls := make([]ToolCall, 0, len(indexes))
+	for _, index := range indexes {
+		calls = append(calls, *a.calls[index])
+	}
+	return calls
+}
@@ -0,0 +1,132 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTool(t *testing.T) {
+	type WeatherArgs struct {
+		City  string `json:"city" description:"City name"`
+		Units string `json:"units,omitempty"`
+	}
+
+	tool, err := NewTool("get_weather", "Current weather", WeatherArgs{})
+	require.NoError(t, err)
+	assert.Equal(t, "function", tool.Type)
+	assert.Equal(t, "get_weather", tool.Function.Name)
+	assert.JSONEq(t, `{
+		"type":"object",
+		"properties":{"city":{"type":"string","description":"City name"},"units":{"type":"string"}},
+		"required":["city"],
+		"additionalProperties":false
+	}`, string(tool.Function.Parameters))
+
+	tool, err = NewTool("lookup", "", json.RawMessage(`{"type":"object"}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"object"}`, string(tool.Function.Parameters))
+
+	tool, err = NewTool("ping", "", nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"object","properties":{}}`, string(tool.Function.Parameters))
+
+	_, err = NewTool("", "", nil)
+	assert.Error(t, err)
+	_, err = NewTool("broken", "", "{not json")
+	assert.Error(t, err)
+	_, err = NewTool("scalar", "", 42)
+	assert.Error(t, err)
+}
+
+func TestToolOptions(t *testing.T) {
+	tool, err := NewTool("ping", "", nil)
+	require.NoError(t, err)
+
+	req := &Request{}
+	require.NoError(t, WithTools(tool)(req))
+	require.NoError(t, WithToolChoice("required")(req))
+	assert.Equal(t, []Tool{tool}, req.Tools)
+	assert.Equal(t, "required", req.ToolChoice)
+
+	require.NoError(t, WithToolChoiceFunction("ping")(req))
+	body, err := json.Marshal(req.ToolChoice)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"function","function":{"name":"ping"}}`, string(body))
+
+	assert.Error(t, WithToolChoice("sometimes")(req))
+	assert.Error(t, WithMaxToolRounds(0)(req))
+	require.NoError(t, WithMaxToolRounds(3)(req))
+
+	body, err = json.Marshal(req)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "3", "max tool rounds is not sent to the API")
+}
+
+func TestComplete_WithToolCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "auto", req["tool_choice"])
+		assert.Len(t, req["tools"], 1)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id":"chatcmpl-1",
+			"choices":[{"index":0,"finish_reason":"tool_calls","message":{
+				"role":"assistant","content":null,
+				"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Paris\"}"}}]
+			}}]
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(&Config{APIKey: "test-key", BaseURL: server.URL, Model: "gpt-4o"})
+	require.NoError(t, err)
+	tool, err := NewTool("get_weather", "", nil)
+	require.NoError(t, err)
+
+	resp, err := client.Complete(context.Background(), "Weather in Paris?", WithTools(tool), WithToolChoice("auto"))
+	require.NoError(t, err)
+
+	calls := resp.ToolCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "call_1", calls[0].ID)
+	var args struct {
+		City string `json:"city"`
+	}
+	require.NoError(t, calls[0].DecodeArguments(&args))
+	assert.Equal(t, "Paris", args.City)
+	assert.Empty(t, resp.Text())
+}
+
+func TestToolCallAccumulator(t *testing.T) {
+	stream := strings.Join([]string{
+		`data: {"choices":[{"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":""}}]}}]}`,
+		`data: {"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":"}}]}}]}`,
+		`data: {"choices":[{"delta":{"tool_calls":[{"index":1,"id":"call_2","type":"function","function":{"name":"get_time","arguments":"{}"}}]}}]}`,
+		`data: {"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]}}]}`,
+		`data: [DONE]`,
+	}, "\n\n") + "\n\n"
+
+	decoder := NewSSEDecoder(strings.NewReader(stream))
+	var acc ToolCallAccumulator
+	for {
+		chunk, err := decoder.Decode()
+		if err != nil {
+			break
+		}
+		acc.Add(chunk)
+	}
+
+	assert.Equal(t, []ToolCall{
+		{ID: "call_1", Type: "function", Function: FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+		{ID: "call_2", Type: "function", Function: FunctionCall{Name: "get_time", Arguments: `{}`}},
+	}, acc.ToolCalls())
+}
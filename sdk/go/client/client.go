@@ -106,7 +106,18 @@ func (c *Client) UpdateStatus(ctx context.Context, nodeID string, payload types.
 	return &resp, nil
 }
 
-// AcknowledgeAction notifies the control plane that a pushed action completed.
+// ClaimActions leases queued actions for a poll-mode node. When WaitSeconds is set the
+// control plane holds the request open until work arrives or the wait elapses, so it
+// must stay below the HTTP client timeout.
+func (c *Client) ClaimActions(ctx context.Context, payload types.ActionClaimRequest) (*types.ActionClaimResponse, error) {
+	var resp types.ActionClaimResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/actions/claim", payload, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AcknowledgeAction notifies the control plane that a claimed action completed.
 func (c *Client) AcknowledgeAction(ctx context.Context, nodeID string, payload types.ActionAckRequest) (*types.LeaseResponse, error) {
 	var resp types.LeaseResponse
 	route := fmt.Sprintf("/api/v1/nodes/%s/actions/ack", url.PathEscape(nodeID))
@@ -126,6 +137,75 @@ func (c *Client) Shutdown(ctx context.Context, nodeID string, payload types.Shut
 	return &resp, nil
 }
 
+// RequestApproval pauses an execution until a human approves or rejects req.
+func (c *Client) RequestApproval(ctx context.Context, executionID string, req types.ApprovalRequest) (*types.Approval, error) {
+	var resp types.Approval
+	route := fmt.Sprintf("/api/v1/executions/%s/approvals", url.PathEscape(executionID))
+	if err := c.do(ctx, http.MethodPost, route, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetApproval fetches the current state of an approval requested by an execution.
+func (c *Client) GetApproval(ctx context.Context, executionID, approvalID string) (*types.Approval, error) {
+	var resp types.Approval
+	route := fmt.Sprintf("/api/v1/executions/%s/approvals/%s", url.PathEscape(executionID), url.PathEscape(approvalID))
+	if err := c.do(ctx, http.MethodGet, route, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RegisterDID requests the identity package of a node and its reasoners and skills.
+// The control plane only serves DID routes when its DID system is enabled and
+// answers 404 otherwise.
+func (c *Client) RegisterDID(ctx context.Context, req types.DIDRegistrationRequest) (*types.DIDRegistrationResponse, error) {
+	var resp types.DIDRegistrationResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/did/register", req, &resp); err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("did registration failed: %s", resp.Error)
+	}
+	return &resp, nil
+}
+
+// ResolveDID fetches the public key and component of a DID.
+func (c *Client) ResolveDID(ctx context.Context, did string) (*types.DIDResolution, error) {
+	var resp types.DIDResolution
+	route := fmt.Sprintf("/api/v1/did/resolve/%s", url.PathEscape(did))
+	if err := c.do(ctx, http.MethodGet, route, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SubmitExecutionVC records an execution credential the agent signed itself. The
+// control plane rejects it with 400 unless it verifies against the issuer's key and
+// the issuer belongs to the agent node that ran the execution.
+func (c *Client) SubmitExecutionVC(ctx context.Context, doc *types.VCDocument) (*types.ExecutionVCResponse, error) {
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("encode vc document: %w", err)
+	}
+	var resp types.ExecutionVCResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/execution/vc", types.ExecutionVCRequest{VCDocument: raw}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// VerifyVC asks the control plane to verify a credential against the issuer's
+// registered key.
+func (c *Client) VerifyVC(ctx context.Context, vcDocument json.RawMessage) (*types.VCVerificationResponse, error) {
+	var resp types.VCVerificationResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/did/verify", types.VCVerificationRequest{VCDocument: vcDocument}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 func (c *Client) do(ctx context.Context, method string, endpoint string, body any, out any) error {
 	u := *c.baseURL
 	rel := strings.TrimPrefix(endpoint, "/")
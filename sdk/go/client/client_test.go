@@ -334,6 +334,39 @@ func TestAcknowledgeAction(t *testing.T) {
 	assert.Equal(t, 60, resp.LeaseSeconds)
 }
 
+func TestClaimActions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/actions/claim", r.URL.Path)
+
+		var payload types.ActionClaimRequest
+		err := json.NewDecoder(r.Body).Decode(&payload)
+		assert.NoError(t, err)
+		assert.Equal(t, "node-1", payload.NodeID)
+		assert.Equal(t, 2, payload.MaxItems)
+		assert.Equal(t, 3, payload.WaitSeconds)
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"items":[{"action_id":"action-1","execution_id":"exec-1","run_id":"run-1","node_id":"node-1","target":"greet","type":"reasoner","input":{"name":"ada"},"attempt":1}],"lease_seconds":300,"next_poll_after":0}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL)
+	require.NoError(t, err)
+
+	resp, err := client.ClaimActions(context.Background(), types.ActionClaimRequest{
+		NodeID:      "node-1",
+		MaxItems:    2,
+		WaitSeconds: 3,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, "action-1", resp.Items[0].ActionID)
+	assert.Equal(t, "greet", resp.Items[0].Target)
+	assert.JSONEq(t, `{"name":"ada"}`, string(resp.Items[0].Input))
+	assert.Equal(t, 300, resp.LeaseSeconds)
+}
+
 func TestShutdown(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, http.MethodPost, r.Method)
@@ -365,6 +398,122 @@ func TestShutdown(t *testing.T) {
 	assert.NotNil(t, resp)
 }
 
+func TestRegisterDID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/did/register", r.URL.Path)
+
+		var payload types.DIDRegistrationRequest
+		err := json.NewDecoder(r.Body).Decode(&payload)
+		assert.NoError(t, err)
+		assert.Equal(t, "node-1", payload.AgentNodeID)
+		require.Len(t, payload.Reasoners, 1)
+		assert.Equal(t, "greet", payload.Reasoners[0].ID)
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"success":true,"identity_package":{"agent_did":{"did":"did:key:agent"},"reasoner_dids":{"greet":{"did":"did:key:greet","function_name":"greet"}},"skill_dids":{},"agentfield_server_id":"af-1"}}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL)
+	require.NoError(t, err)
+
+	resp, err := client.RegisterDID(context.Background(), types.DIDRegistrationRequest{
+		AgentNodeID: "node-1",
+		Reasoners:   []types.ReasonerDefinition{{ID: "greet"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "did:key:agent", resp.IdentityPackage.AgentDID.DID)
+	assert.Equal(t, "did:key:greet", resp.IdentityPackage.ReasonerDIDs["greet"].DID)
+	assert.Equal(t, "af-1", resp.IdentityPackage.AgentFieldServerID)
+}
+
+func TestRegisterDID_Failure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"success":false,"error":"keystore unavailable"}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL)
+	require.NoError(t, err)
+
+	_, err = client.RegisterDID(context.Background(), types.DIDRegistrationRequest{AgentNodeID: "node-1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "keystore unavailable")
+}
+
+func TestResolveDID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/v1/did/resolve/did:key:greet", r.URL.Path)
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"did":"did:key:greet","public_key_jwk":"{}","component_type":"reasoner","function_name":"greet"}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL)
+	require.NoError(t, err)
+
+	resp, err := client.ResolveDID(context.Background(), "did:key:greet")
+	require.NoError(t, err)
+	assert.Equal(t, "reasoner", resp.ComponentType)
+	assert.Equal(t, "greet", resp.FunctionName)
+}
+
+func TestSubmitExecutionVC(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/execution/vc", r.URL.Path)
+
+		var payload types.ExecutionVCRequest
+		err := json.NewDecoder(r.Body).Decode(&payload)
+		assert.NoError(t, err)
+		var doc types.VCDocument
+		assert.NoError(t, json.Unmarshal(payload.VCDocument, &doc))
+		assert.Equal(t, "exec-1", doc.CredentialSubject.ExecutionID)
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"vc_id":"vc-1","execution_id":"exec-1","issuer_did":"did:key:greet"}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL)
+	require.NoError(t, err)
+
+	doc := &types.VCDocument{Issuer: "did:key:greet"}
+	doc.CredentialSubject.ExecutionID = "exec-1"
+	resp, err := client.SubmitExecutionVC(context.Background(), doc)
+	require.NoError(t, err)
+	assert.Equal(t, "vc-1", resp.VCID)
+	assert.Equal(t, "did:key:greet", resp.IssuerDID)
+}
+
+func TestVerifyVC(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/did/verify", r.URL.Path)
+
+		var payload types.VCVerificationRequest
+		err := json.NewDecoder(r.Body).Decode(&payload)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"id":"vc-1"}`, string(payload.VCDocument))
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"valid":true,"issuer_did":"did:key:greet"}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL)
+	require.NoError(t, err)
+
+	resp, err := client.VerifyVC(context.Background(), json.RawMessage(`{"id":"vc-1"}`))
+	require.NoError(t, err)
+	assert.True(t, resp.Valid)
+	assert.Equal(t, "did:key:greet", resp.IssuerDID)
+}
+
 func TestAPIError(t *testing.T) {
 	err := &APIError{
 		StatusCode: 404,
@@ -0,0 +1,234 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Agent-Field/agentfield/sdk/go/types"
+)
+
+// ActionHandler executes a claimed action. The returned value is JSON encoded into
+// the acknowledgement; a non-nil error marks the execution as failed.
+type ActionHandler func(ctx context.Context, action types.ClaimedAction) (any, error)
+
+// WorkerConfig tunes RunWorker.
+type WorkerConfig struct {
+	// NodeID is the poll-mode node the worker claims actions for.
+	NodeID string
+	// MaxItems caps how many actions are claimed per request. Defaults to 1.
+	MaxItems int
+	// Concurrency caps how many actions run at once. Defaults to MaxItems.
+	Concurrency int
+	// WaitSeconds is the long-poll window requested from the control plane.
+	// Defaults to 5 and must stay below the HTTP client timeout.
+	WaitSeconds int
+	// RetryInterval is the pause after a failed claim. Defaults to 2s.
+	RetryInterval time.Duration
+	// Logger receives worker diagnostics. Defaults to stdout.
+	Logger *log.Logger
+}
+
+// RunWorker claims actions for cfg.NodeID, runs handler for each one, and
+// acknowledges the outcome. Running actions have their lease renewed until the
+// handler returns; if a renewal reports the execution was cancelled, the context
+// passed to handler is cancelled. RunWorker blocks until ctx is cancelled and
+// in-flight actions have been acknowledged.
+func (c *Client) RunWorker(ctx context.Context, cfg WorkerConfig, handler ActionHandler) error {
+	if cfg.NodeID == "" {
+		return errors.New("worker node id is required")
+	}
+	if handler == nil {
+		return errors.New("worker handler is required")
+	}
+	if cfg.MaxItems <= 0 {
+		cfg.MaxItems = 1
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = cfg.MaxItems
+	}
+	if cfg.WaitSeconds <= 0 {
+		cfg.WaitSeconds = 5
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = 2 * time.Second
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.New(os.Stdout, "[agentfield-worker] ", log.LstdFlags)
+	}
+
+	slots := make(chan struct{}, cfg.Concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		// Block until at least one slot is free, then grab as many as are idle.
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		reserved := 1
+	reserve:
+		for reserved < cfg.MaxItems {
+			select {
+			case slots <- struct{}{}:
+				reserved++
+			default:
+				break reserve
+			}
+		}
+
+		resp, err := c.ClaimActions(ctx, types.ActionClaimRequest{
+			NodeID:      cfg.NodeID,
+			MaxItems:    reserved,
+			WaitSeconds: cfg.WaitSeconds,
+		})
+		if err != nil {
+			releaseSlots(slots, reserved)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			cfg.Logger.Printf("claim failed: %v", err)
+			if !sleepContext(ctx, cfg.RetryInterval) {
+				return ctx.Err()
+			}
+			continue
+		}
+
+		renewEvery := time.Duration(resp.LeaseSeconds) * time.Second / 2
+		if renewEvery <= 0 {
+			renewEvery = 30 * time.Second
+		}
+
+		for _, action := range resp.Items {
+			wg.Add(1)
+			go func(action types.ClaimedAction) {
+				defer wg.Done()
+				defer releaseSlots(slots, 1)
+				c.runAction(ctx, cfg, action, handler, renewEvery)
+			}(action)
+		}
+		releaseSlots(slots, reserved-len(resp.Items))
+
+		if len(resp.Items) == 0 && resp.NextPollAfter > 0 {
+			if !sleepContext(ctx, time.Duration(resp.NextPollAfter)*time.Second) {
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+func (c *Client) runAction(ctx context.Context, cfg WorkerConfig, action types.ClaimedAction, handler ActionHandler, renewEvery time.Duration) {
+	actionCtx, cancelAction := context.WithCancel(ctx)
+	defer cancelAction()
+
+	stopRenew := make(chan struct{})
+	renewDone := make(chan struct{})
+	var cancelled atomic.Bool
+	go func() {
+		defer close(renewDone)
+		ticker := time.NewTicker(renewEvery)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopRenew:
+				return
+			case <-ticker.C:
+				resp, err := c.AcknowledgeAction(ctx, cfg.NodeID, types.ActionAckRequest{
+					ActionID: action.ActionID,
+					Status:   "running",
+				})
+				if err != nil {
+					cfg.Logger.Printf("lease renewal for action %s failed: %v", action.ActionID, err)
+					continue
+				}
+				if resp.Cancelled {
+					cfg.Logger.Printf("execution %s was cancelled", action.ExecutionID)
+					cancelled.Store(true)
+					cancelAction()
+					return
+				}
+			}
+		}
+	}()
+
+	start := time.Now()
+	result, runErr := safeRunAction(actionCtx, handler, action)
+	close(stopRenew)
+	<-renewDone
+
+	// The control plane already settled a cancelled action; there is nothing to acknowledge.
+	if cancelled.Load() {
+		return
+	}
+
+	duration := int(time.Since(start).Milliseconds())
+	ack := types.ActionAckRequest{
+		ActionID:   action.ActionID,
+		Status:     "succeeded",
+		DurationMS: &duration,
+	}
+	if runErr == nil && result != nil {
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			runErr = fmt.Errorf("encode result: %w", err)
+		} else {
+			ack.Result = encoded
+		}
+	}
+	if runErr != nil {
+		ack.Status = "failed"
+		ack.Error = runErr.Error()
+	}
+
+	// Acknowledge on a detached context so results survive worker shutdown.
+	for attempt := 0; attempt < 3; attempt++ {
+		ackCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		_, err := c.AcknowledgeAction(ackCtx, cfg.NodeID, ack)
+		cancel()
+		if err == nil {
+			return
+		}
+		if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode < 500 {
+			cfg.Logger.Printf("acknowledgement for action %s rejected: %v", action.ActionID, err)
+			return
+		}
+		cfg.Logger.Printf("acknowledgement for action %s failed (attempt %d): %v", action.ActionID, attempt+1, err)
+		if attempt < 2 {
+			time.Sleep(time.Duration(attempt+1) * time.Second)
+		}
+	}
+}
+
+func safeRunAction(ctx context.Context, handler ActionHandler, action types.ClaimedAction) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, action)
+}
+
+func releaseSlots(slots chan struct{}, n int) {
+	for i := 0; i < n; i++ {
+		<-slots
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/sdk/go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunWorker_ClaimsRunsAndAcknowledges(t *testing.T) {
+	var (
+		claims int32
+		mu     sync.Mutex
+		acks   []types.ActionAckRequest
+	)
+	acked := make(chan struct{}, 2)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/actions/claim":
+			var req types.ActionClaimRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "node-1", req.NodeID)
+			assert.Equal(t, 5, req.WaitSeconds)
+
+			w.Header().Set("Content-Type", "application/json")
+			if atomic.AddInt32(&claims, 1) == 1 {
+				_, _ = w.Write([]byte(`{"items":[
+					{"action_id":"a-1","execution_id":"e-1","target":"greet","input":{"name":"ada"}},
+					{"action_id":"a-2","execution_id":"e-2","target":"explode","input":{}}
+				],"lease_seconds":300}`))
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+			_, _ = w.Write([]byte(`{"items":[],"lease_seconds":300,"next_poll_after":0}`))
+		case "/api/v1/nodes/node-1/actions/ack":
+			var ack types.ActionAckRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&ack))
+			mu.Lock()
+			acks = append(acks, ack)
+			mu.Unlock()
+			_, _ = w.Write([]byte(`{"lease_seconds":300}`))
+			acked <- struct{}{}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- client.RunWorker(ctx, WorkerConfig{
+			NodeID:   "node-1",
+			MaxItems: 2,
+			Logger:   log.New(io.Discard, "", 0),
+		}, func(ctx context.Context, action types.ClaimedAction) (any, error) {
+			if action.Target == "explode" {
+				return nil, errors.New("boom")
+			}
+			var input map[string]string
+			require.NoError(t, json.Unmarshal(action.Input, &input))
+			return map[string]string{"greeting": "hi " + input["name"]}, nil
+		})
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-acked:
+		case <-time.After(5 * time.Second):
+			t.Fatal("worker did not acknowledge actions")
+		}
+	}
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	mu.Lock()
+	defer mu.Unlock()
+	byID := make(map[string]types.ActionAckRequest, len(acks))
+	for _, ack := range acks {
+		byID[ack.ActionID] = ack
+	}
+	require.Contains(t, byID, "a-1")
+	require.Contains(t, byID, "a-2")
+	assert.Equal(t, "succeeded", byID["a-1"].Status)
+	assert.JSONEq(t, `{"greeting":"hi ada"}`, string(byID["a-1"].Result))
+	assert.NotNil(t, byID["a-1"].DurationMS)
+	assert.Equal(t, "failed", byID["a-2"].Status)
+	assert.Equal(t, "boom", byID["a-2"].Error)
+}
+
+func TestRunWorker_RequiresNodeAndHandler(t *testing.T) {
+	client, err := New("http://localhost")
+	require.NoError(t, err)
+
+	err = client.RunWorker(context.Background(), WorkerConfig{}, func(context.Context, types.ClaimedAction) (any, error) { return nil, nil })
+	assert.Error(t, err)
+
+	err = client.RunWorker(context.Background(), WorkerConfig{NodeID: "node-1"}, nil)
+	assert.Error(t, err)
+}
+
+func TestRunWorker_CancelledRenewalStopsHandler(t *testing.T) {
+	var (
+		claims int32
+		mu     sync.Mutex
+		acks   []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/actions/claim":
+			if atomic.AddInt32(&claims, 1) == 1 {
+				_, _ = w.Write([]byte(`{"items":[{"action_id":"a-1","execution_id":"e-1","target":"slow"}],"lease_seconds":1}`))
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+			_, _ = w.Write([]byte(`{"items":[],"lease_seconds":1,"next_poll_after":0}`))
+		case "/api/v1/nodes/node-1/actions/ack":
+			var ack types.ActionAckRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&ack))
+			mu.Lock()
+			acks = append(acks, ack.Status)
+			mu.Unlock()
+			_, _ = w.Write([]byte(`{"lease_seconds":1,"cancelled":true}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL)
+	require.NoError(t, err)
+
+	stopped := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- client.RunWorker(ctx, WorkerConfig{
+			NodeID: "node-1",
+			Logger: log.New(io.Discard, "", 0),
+		}, func(ctx context.Context, action types.ClaimedAction) (any, error) {
+			<-ctx.Done()
+			stopped <- ctx.Err()
+			return nil, ctx.Err()
+		})
+	}()
+
+	select {
+	case err := <-stopped:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler context was not cancelled")
+	}
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"running"}, acks)
+}
@@ -0,0 +1,234 @@
+// Package did signs and verifies the execution credentials agents contribute to
+// the AgentField audit chain. Identities come from the control plane, which
+// issues an Ed25519 key pair to every agent node and to each of its reasoners
+// and skills; credentials signed here verify on the control plane and vice versa.
+package did
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Agent-Field/agentfield/sdk/go/types"
+)
+
+// Credential constants shared with the control plane.
+const (
+	ProofType          = "Ed25519Signature2020"
+	ProofPurpose       = "assertionMethod"
+	vcIDPrefix         = "urn:agentfield:vc:"
+	maxErrorMessageLen = 500
+)
+
+var (
+	vcContext = []string{
+		"https://www.w3.org/2018/credentials/v1",
+		"https://agentfield.example.com/contexts/execution/v1",
+	}
+	vcType = []string{"VerifiableCredential", "AgentFieldExecutionCredential"}
+)
+
+// ErrInvalidSignature is returned by Verify when the proof does not match the
+// credential and key.
+var ErrInvalidSignature = errors.New("invalid VC signature")
+
+// Execution describes a finished reasoner or skill call to attest to.
+type Execution struct {
+	ExecutionID string
+	WorkflowID  string
+	SessionID   string
+
+	// CallerDID and CallerType identify who requested the execution.
+	CallerDID  string
+	CallerType string
+	// AgentNodeDID is the DID of the node that served the call.
+	AgentNodeDID string
+	// Target is the identity of the reasoner or skill that ran. Its DID issues
+	// the credential.
+	Target types.DIDIdentity
+
+	// Input and Output are hashed; only their digests enter the credential.
+	Input        []byte
+	Output       []byte
+	Status       string
+	ErrorMessage string
+	StartedAt    time.Time
+	Duration     time.Duration
+}
+
+// Hash returns the digest the credentials carry for a payload: the unpadded
+// base64url encoding of its SHA-256.
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// IssueExecutionVC builds the credential for exec and signs it with the key of
+// exec.Target.
+func IssueExecutionVC(exec Execution) (*types.VCDocument, error) {
+	now := time.Now().UTC()
+	startedAt := exec.StartedAt
+	if startedAt.IsZero() {
+		startedAt = now
+	}
+	errorMessage := exec.ErrorMessage
+	if len(errorMessage) > maxErrorMessageLen {
+		errorMessage = errorMessage[:maxErrorMessageLen] + "...[truncated]"
+	}
+	inputHash, outputHash := Hash(exec.Input), Hash(exec.Output)
+
+	doc := &types.VCDocument{
+		Context:      append([]string(nil), vcContext...),
+		Type:         append([]string(nil), vcType...),
+		ID:           fmt.Sprintf("%svc-%d", vcIDPrefix, now.UnixNano()),
+		Issuer:       exec.Target.DID,
+		IssuanceDate: now.Format(time.RFC3339),
+		CredentialSubject: types.VCCredentialSubject{
+			ExecutionID: exec.ExecutionID,
+			WorkflowID:  exec.WorkflowID,
+			SessionID:   exec.SessionID,
+			Caller: types.VCCaller{
+				DID:          exec.CallerDID,
+				Type:         exec.CallerType,
+				AgentNodeDID: exec.AgentNodeDID,
+			},
+			Target: types.VCTarget{
+				DID:          exec.Target.DID,
+				AgentNodeDID: exec.AgentNodeDID,
+				FunctionName: exec.Target.FunctionName,
+			},
+			Execution: types.VCExecution{
+				InputHash:    inputHash,
+				OutputHash:   outputHash,
+				Timestamp:    startedAt.UTC().Format(time.RFC3339),
+				DurationMS:   int(exec.Duration.Milliseconds()),
+				Status:       exec.Status,
+				ErrorMessage: errorMessage,
+			},
+			Audit: types.VCAudit{
+				InputDataHash:  inputHash,
+				OutputDataHash: outputHash,
+				Metadata: map[string]any{
+					"agentfield_version": "1.0.0",
+					"vc_version":         "1.0",
+				},
+			},
+		},
+	}
+	if err := Sign(doc, exec.Target); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Sign sets the proof of doc, signed with the private key of issuer.
+func Sign(doc *types.VCDocument, issuer types.DIDIdentity) error {
+	key, err := PrivateKey(issuer.PrivateKeyJWK)
+	if err != nil {
+		return err
+	}
+	payload, err := canonical(doc)
+	if err != nil {
+		return err
+	}
+	doc.Proof = types.VCProof{
+		Type:               ProofType,
+		Created:            time.Now().UTC().Format(time.RFC3339),
+		VerificationMethod: issuer.DID + "#key-1",
+		ProofPurpose:       ProofPurpose,
+		ProofValue:         base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, payload)),
+	}
+	return nil
+}
+
+// Verify checks the proof of doc against the issuer's public key, as served by
+// the control plane's DID resolution. It returns ErrInvalidSignature when the
+// credential was altered or signed by another key.
+func Verify(doc *types.VCDocument, publicKeyJWK string) error {
+	key, err := PublicKey(publicKeyJWK)
+	if err != nil {
+		return err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(doc.Proof.ProofValue)
+	if err != nil {
+		return fmt.Errorf("%w: decode proof: %v", ErrInvalidSignature, err)
+	}
+	payload, err := canonical(doc)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(key, payload, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// FunctionIdentity returns the identity of a reasoner or skill of pkg. Reasoners
+// take precedence when both share the name.
+func FunctionIdentity(pkg *types.DIDIdentityPackage, name string) (types.DIDIdentity, bool) {
+	if pkg == nil {
+		return types.DIDIdentity{}, false
+	}
+	if identity, ok := pkg.ReasonerDIDs[name]; ok {
+		return identity, true
+	}
+	identity, ok := pkg.SkillDIDs[name]
+	return identity, ok
+}
+
+// PrivateKey decodes an Ed25519 private key from its JWK, whose "d" member
+// holds the seed.
+func PrivateKey(jwk string) (ed25519.PrivateKey, error) {
+	seed, err := jwkMember(jwk, "d")
+	if err != nil {
+		return nil, err
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid private key JWK: seed is %d bytes", len(seed))
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// PublicKey decodes an Ed25519 public key from its JWK.
+func PublicKey(jwk string) (ed25519.PublicKey, error) {
+	key, err := jwkMember(jwk, "x")
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key JWK: key is %d bytes", len(key))
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+func jwkMember(jwk, name string) ([]byte, error) {
+	var members map[string]any
+	if err := json.Unmarshal([]byte(jwk), &members); err != nil {
+		return nil, fmt.Errorf("parse JWK: %w", err)
+	}
+	value, ok := members[name].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid JWK: missing %q parameter", name)
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("decode JWK %q parameter: %w", name, err)
+	}
+	return decoded, nil
+}
+
+// canonical is the signed form of a credential: its JSON encoding with an
+// empty proof.
+func canonical(doc *types.VCDocument) ([]byte, error) {
+	unsigned := *doc
+	unsigned.Proof = types.VCProof{}
+	payload, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("encode vc document: %w", err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,129 @@
+package did
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Agent-Field/agentfield/sdk/go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newIdentity returns an identity with a fresh key pair encoded the way the
+// control plane encodes it.
+func newIdentity(t *testing.T, didValue, function string) types.DIDIdentity {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	x := base64.RawURLEncoding.EncodeToString(pub)
+	d := base64.RawURLEncoding.EncodeToString(priv.Seed())
+	return types.DIDIdentity{
+		DID:           didValue,
+		PrivateKeyJWK: fmt.Sprintf(`{"kty":"OKP","crv":"Ed25519","d":%q,"x":%q}`, d, x),
+		PublicKeyJWK:  fmt.Sprintf(`{"kty":"OKP","crv":"Ed25519","x":%q}`, x),
+		ComponentType: "reasoner",
+		FunctionName:  function,
+	}
+}
+
+func TestHash(t *testing.T) {
+	sum := sha256.Sum256([]byte(`{"a":1}`))
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(sum[:]), Hash([]byte(`{"a":1}`)))
+}
+
+func TestIssueExecutionVC(t *testing.T) {
+	target := newIdentity(t, "did:key:greet", "greet")
+	started := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	doc, err := IssueExecutionVC(Execution{
+		ExecutionID:  "exec-1",
+		WorkflowID:   "wf-1",
+		SessionID:    "session-1",
+		CallerDID:    "did:key:caller",
+		CallerType:   "agent",
+		AgentNodeDID: "did:key:node",
+		Target:       target,
+		Input:        []byte(`{"name":"ada"}`),
+		Output:       []byte(`{"greeting":"hi ada"}`),
+		Status:       "succeeded",
+		StartedAt:    started,
+		Duration:     1500 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "did:key:greet", doc.Issuer)
+	assert.True(t, strings.HasPrefix(doc.ID, "urn:agentfield:vc:vc-"))
+	assert.Equal(t, []string{"VerifiableCredential", "AgentFieldExecutionCredential"}, doc.Type)
+
+	subject := doc.CredentialSubject
+	assert.Equal(t, "exec-1", subject.ExecutionID)
+	assert.Equal(t, types.VCCaller{DID: "did:key:caller", Type: "agent", AgentNodeDID: "did:key:node"}, subject.Caller)
+	assert.Equal(t, types.VCTarget{DID: "did:key:greet", AgentNodeDID: "did:key:node", FunctionName: "greet"}, subject.Target)
+	assert.Equal(t, Hash([]byte(`{"name":"ada"}`)), subject.Execution.InputHash)
+	assert.Equal(t, subject.Execution.OutputHash, subject.Audit.OutputDataHash)
+	assert.Equal(t, "2025-01-02T03:04:05Z", subject.Execution.Timestamp)
+	assert.Equal(t, 1500, subject.Execution.DurationMS)
+
+	assert.Equal(t, ProofType, doc.Proof.Type)
+	assert.Equal(t, "did:key:greet#key-1", doc.Proof.VerificationMethod)
+	require.NoError(t, Verify(doc, target.PublicKeyJWK))
+}
+
+func TestIssueExecutionVC_TruncatesErrorMessage(t *testing.T) {
+	doc, err := IssueExecutionVC(Execution{
+		ExecutionID:  "exec-1",
+		Target:       newIdentity(t, "did:key:greet", "greet"),
+		Status:       "failed",
+		ErrorMessage: strings.Repeat("x", 600),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("x", 500)+"...[truncated]", doc.CredentialSubject.Execution.ErrorMessage)
+}
+
+func TestIssueExecutionVC_InvalidKey(t *testing.T) {
+	target := types.DIDIdentity{DID: "did:key:greet", PrivateKeyJWK: `{"kty":"OKP"}`}
+	_, err := IssueExecutionVC(Execution{ExecutionID: "exec-1", Target: target})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `missing "d"`)
+}
+
+func TestVerify_DetectsTampering(t *testing.T) {
+	target := newIdentity(t, "did:key:greet", "greet")
+	other := newIdentity(t, "did:key:other", "other")
+
+	doc, err := IssueExecutionVC(Execution{ExecutionID: "exec-1", Target: target, Status: "failed"})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, Verify(doc, other.PublicKeyJWK), ErrInvalidSignature)
+
+	doc.CredentialSubject.Execution.Status = "succeeded"
+	assert.ErrorIs(t, Verify(doc, target.PublicKeyJWK), ErrInvalidSignature)
+
+	doc.Proof.ProofValue = "not base64!"
+	assert.ErrorIs(t, Verify(doc, target.PublicKeyJWK), ErrInvalidSignature)
+}
+
+func TestFunctionIdentity(t *testing.T) {
+	pkg := &types.DIDIdentityPackage{
+		ReasonerDIDs: map[string]types.DIDIdentity{"greet": {DID: "did:key:reasoner"}},
+		SkillDIDs:    map[string]types.DIDIdentity{"greet": {DID: "did:key:skill"}, "add": {DID: "did:key:add"}},
+	}
+
+	identity, ok := FunctionIdentity(pkg, "greet")
+	require.True(t, ok)
+	assert.Equal(t, "did:key:reasoner", identity.DID)
+
+	identity, ok = FunctionIdentity(pkg, "add")
+	require.True(t, ok)
+	assert.Equal(t, "did:key:add", identity.DID)
+
+	_, ok = FunctionIdentity(pkg, "missing")
+	assert.False(t, ok)
+	_, ok = FunctionIdentity(nil, "greet")
+	assert.False(t, ok)
+}
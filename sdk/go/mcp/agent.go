@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Agent-Field/agentfield/sdk/go/agent"
+)
+
+var (
+	invalidSkillChars = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+	repeatedUnderline = regexp.MustCompile(`_+`)
+)
+
+// SkillName is the name under which RegisterSkills exposes a tool: the alias
+// and tool name joined by an underscore, with other characters than letters,
+// digits and underscores replaced. The Python and TypeScript SDKs name MCP
+// skills the same way.
+func SkillName(alias, tool string) string {
+	name := invalidSkillChars.ReplaceAllString(alias+"_"+tool, "_")
+	name = strings.Trim(repeatedUnderline.ReplaceAllString(name, "_"), "_")
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "mcp_" + name
+	}
+	return name
+}
+
+// RegisterSkills registers every tool of the running servers as a skill on a,
+// tagged "mcp" and with the server alias, and returns the skill names. Call it
+// after Start and before the agent registers with the control plane; tools of
+// servers that start later are not added.
+//
+// A skill answers with {"status": "success", "result": ..., "server": alias,
+// "tool": name}, where result is the CallToolResult. A tool that reports a
+// failure fails the skill.
+func (m *Manager) RegisterSkills(a *agent.Agent) []string {
+	var names []string
+	for _, alias := range m.aliases {
+		s := m.servers[alias]
+		tools, _ := m.Tools(alias)
+		for _, tool := range tools {
+			name := SkillName(alias, tool.Name)
+			tags := append([]string{"mcp", alias}, s.config.Tags...)
+			a.RegisterSkill(name, m.skillHandler(alias, tool.Name),
+				agent.WithSkillDescription(tool.Description),
+				agent.WithSkillInputSchema(tool.InputSchema),
+				agent.WithSkillTags(tags...),
+			)
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (m *Manager) skillHandler(alias, tool string) agent.HandlerFunc {
+	return func(ctx context.Context, input map[string]any) (any, error) {
+		arguments := make(map[string]any, len(input))
+		for key, value := range input {
+			if !strings.HasPrefix(key, "__") {
+				arguments[key] = value
+			}
+		}
+
+		result, err := m.CallTool(ctx, alias, tool, arguments)
+		if err != nil {
+			return nil, err
+		}
+		if result.IsError {
+			return nil, fmt.Errorf("mcp tool %s on %s failed: %s", tool, alias, result.Text())
+		}
+		return map[string]any{
+			"status": "success",
+			"result": result,
+			"server": alias,
+			"tool":   tool,
+		}, nil
+	}
+}
+
+// Mount serves the MCP endpoints the control plane queries on a:
+//
+//	GET  /health/mcp                    Health
+//	GET  /mcp/servers/{alias}/tools     the server's tools
+//	POST /mcp/servers/{alias}/restart   Restart
+func (m *Manager) Mount(a *agent.Agent) {
+	a.Handle("/health/mcp", http.HandlerFunc(m.handleHealth))
+	a.Handle("/mcp/servers/", http.HandlerFunc(m.handleServer))
+}
+
+func (m *Manager) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, m.Health(r.Context()))
+}
+
+func (m *Manager) handleServer(w http.ResponseWriter, r *http.Request) {
+	alias, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/mcp/servers/"), "/")
+	if _, err := m.server(alias); err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": err.Error()})
+		return
+	}
+
+	switch {
+	case action == "tools" && r.Method == http.MethodGet:
+		tools, _ := m.Tools(alias)
+		payload := make([]map[string]any, 0, len(tools))
+		for _, tool := range tools {
+			payload = append(payload, map[string]any{
+				"name":         tool.Name,
+				"description":  tool.Description,
+				"input_schema": schemaMap(tool.InputSchema),
+			})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"tools": payload})
+	case action == "restart" && r.Method == http.MethodPost:
+		if err := m.Restart(r.Context(), alias); err != nil {
+			writeJSON(w, http.StatusOK, map[string]any{"success": false, "message": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"success": true, "message": fmt.Sprintf("mcp server %s restarted", alias)})
+	case action == "tools" || action == "restart":
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func schemaMap(raw json.RawMessage) map[string]any {
+	schema := map[string]any{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &schema); err != nil {
+			return map[string]any{}
+		}
+	}
+	return schema
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Agent-Field/agentfield/sdk/go/agent"
+)
+
+func TestSkillName(t *testing.T) {
+	assert.Equal(t, "github_create_issue", SkillName("github", "create_issue"))
+	assert.Equal(t, "my_server_read_file", SkillName("my-server", "read.file"))
+	assert.Equal(t, "files_list", SkillName("files", "__list__"))
+	assert.Equal(t, "mcp_1password_get", SkillName("1password", "get"))
+}
+
+func newMountedAgent(t *testing.T) (*Manager, *httptest.Server) {
+	t.Helper()
+	a, err := agent.New(agent.Config{NodeID: "node-1", Version: "1.0.0", Logger: log.New(io.Discard, "", 0)})
+	require.NoError(t, err)
+
+	cfg := fakeConfig("fake")
+	cfg.Tags = []string{"demo"}
+	m, err := NewManager([]ServerConfig{cfg})
+	require.NoError(t, err)
+	t.Cleanup(func() { m.Close() })
+	require.NoError(t, m.Start(context.Background()))
+
+	assert.Equal(t, []string{"fake_echo", "fake_fail"}, m.RegisterSkills(a))
+	m.Mount(a)
+
+	server := httptest.NewServer(a.Handler())
+	t.Cleanup(server.Close)
+	return m, server
+}
+
+func getJSON(t *testing.T, url string, out any) int {
+	t.Helper()
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	return resp.StatusCode
+}
+
+func postJSON(t *testing.T, url, body string, out any) int {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", bytes.NewReader([]byte(body)))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	return resp.StatusCode
+}
+
+func TestRegisterSkills(t *testing.T) {
+	_, server := newMountedAgent(t)
+
+	var discovery struct {
+		Skills []struct {
+			ID          string         `json:"id"`
+			InputSchema map[string]any `json:"input_schema"`
+			Tags        []string       `json:"tags"`
+		} `json:"skills"`
+	}
+	getJSON(t, server.URL+"/discover", &discovery)
+	require.Len(t, discovery.Skills, 2)
+	for _, skill := range discovery.Skills {
+		assert.Equal(t, []string{"mcp", "fake", "demo"}, skill.Tags)
+		if skill.ID == "fake_echo" {
+			assert.Equal(t, "object", skill.InputSchema["type"])
+			assert.Contains(t, skill.InputSchema["properties"], "text")
+		}
+	}
+
+	var result map[string]any
+	status := postJSON(t, server.URL+"/skills/fake_echo", `{"text":"hello","__memory":{}}`, &result)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "success", result["status"])
+	assert.Equal(t, "fake", result["server"])
+	assert.Equal(t, "echo", result["tool"])
+	toolResult := result["result"].(map[string]any)
+	assert.Equal(t, map[string]any{"text": "hello"}, toolResult["structuredContent"], "reserved keys are not passed to the tool")
+
+	status = postJSON(t, server.URL+"/skills/fake_fail", `{}`, &result)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Contains(t, result["error"], "boom")
+}
+
+func TestMount(t *testing.T) {
+	_, server := newMountedAgent(t)
+
+	var health HealthResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, server.URL+"/health/mcp", &health))
+	require.Len(t, health.Servers, 1)
+	assert.Equal(t, "fake", health.Servers[0].Alias)
+	assert.Equal(t, StatusRunning, health.Servers[0].Status)
+	assert.Equal(t, 2, health.Summary.TotalTools)
+
+	var tools struct {
+		Tools []map[string]any `json:"tools"`
+	}
+	assert.Equal(t, http.StatusOK, getJSON(t, server.URL+"/mcp/servers/fake/tools", &tools))
+	require.Len(t, tools.Tools, 2)
+	assert.Equal(t, "echo", tools.Tools[0]["name"])
+	assert.Equal(t, "Echoes text", tools.Tools[0]["description"])
+	assert.Equal(t, "object", tools.Tools[0]["input_schema"].(map[string]any)["type"])
+
+	var restart map[string]any
+	assert.Equal(t, http.StatusOK, postJSON(t, server.URL+"/mcp/servers/fake/restart", "", &restart))
+	assert.Equal(t, true, restart["success"])
+
+	var missing map[string]any
+	assert.Equal(t, http.StatusNotFound, getJSON(t, server.URL+"/mcp/servers/other/tools", &missing))
+}
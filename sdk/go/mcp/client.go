@@ -0,0 +1,248 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// ProtocolVersion is the MCP revision the client asks servers to speak.
+const ProtocolVersion = "2024-11-05"
+
+// clientInfo identifies the client in the initialize handshake.
+var clientInfo = Implementation{Name: "agentfield-go-sdk", Version: "1.0.0"}
+
+// Implementation names an MCP client or server.
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Tool is a tool advertised by an MCP server.
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"inputSchema,omitempty"`
+}
+
+// Content is one item of a tool result. Text items carry Text, image and
+// audio items carry base64 Data with a MimeType, and embedded resources carry
+// Resource.
+type Content struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	Data     string          `json:"data,omitempty"`
+	MimeType string          `json:"mimeType,omitempty"`
+	Resource json.RawMessage `json:"resource,omitempty"`
+}
+
+// CallToolResult is the result of a tools/call request. IsError reports a
+// failure of the tool itself, as opposed to a protocol error.
+type CallToolResult struct {
+	Content           []Content       `json:"content"`
+	StructuredContent json.RawMessage `json:"structuredContent,omitempty"`
+	IsError           bool            `json:"isError,omitempty"`
+}
+
+// Text joins the text items of the result.
+func (r *CallToolResult) Text() string {
+	var parts []string
+	for _, item := range r.Content {
+		if item.Type == "text" {
+			parts = append(parts, item.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// RPCError is a JSON-RPC error returned by an MCP server.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("mcp error %d: %s", e.Code, e.Message)
+}
+
+// Client is a connection to one MCP server.
+type Client struct {
+	config     ServerConfig
+	transport  transport
+	process    *process
+	port       int
+	serverInfo Implementation
+}
+
+// Connect starts or reaches the server described by cfg and performs the
+// initialize handshake. Servers launched by Connect are stopped by Close.
+func Connect(ctx context.Context, cfg ServerConfig) (*Client, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	c := &Client{config: cfg}
+	switch {
+	case cfg.transport() == TransportStdio:
+		proc, err := startProcess(cfg, 0, true)
+		if err != nil {
+			return nil, err
+		}
+		c.process = proc
+		c.transport = newStdioTransport(proc)
+	case cfg.URL != "":
+		c.transport = newHTTPTransport(cfg.URL)
+	default:
+		port := cfg.Port
+		if port == 0 {
+			free, err := freePort()
+			if err != nil {
+				return nil, fmt.Errorf("mcp server %s: %w", cfg.Alias, err)
+			}
+			port = free
+		}
+		proc, err := startProcess(cfg, port, false)
+		if err != nil {
+			return nil, err
+		}
+		c.process = proc
+		c.port = port
+		c.transport = newHTTPTransport(fmt.Sprintf("http://localhost:%d/mcp", port))
+	}
+
+	if err := c.initialize(ctx); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("mcp server %s: initialize: %w", cfg.Alias, err)
+	}
+	return c, nil
+}
+
+func (c *Client) initialize(ctx context.Context) error {
+	params := map[string]any{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      clientInfo,
+	}
+
+	var result struct {
+		ServerInfo Implementation `json:"serverInfo"`
+	}
+	// A freshly launched HTTP server may need a moment before it listens.
+	startCtx, cancel := context.WithTimeout(ctx, c.config.timeout())
+	defer cancel()
+	var err error
+	for {
+		err = c.call(startCtx, "initialize", params, &result)
+		if err == nil || c.process == nil || c.process.stdio || !isConnectionRefused(err) {
+			break
+		}
+		select {
+		case <-startCtx.Done():
+			return err
+		case <-c.process.done:
+			return c.process.exitError()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	if err != nil {
+		return err
+	}
+	c.serverInfo = result.ServerInfo
+
+	return c.transport.notify(startCtx, "notifications/initialized", nil)
+}
+
+// ServerInfo reports the name and version the server gave during initialize.
+func (c *Client) ServerInfo() Implementation {
+	return c.serverInfo
+}
+
+// ProcessID returns the process ID of a server launched by Connect, or 0 for
+// servers reached by URL.
+func (c *Client) ProcessID() int {
+	if c.process == nil {
+		return 0
+	}
+	return c.process.cmd.Process.Pid
+}
+
+// ListTools returns every tool the server advertises, following pagination.
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	tools := []Tool{}
+	cursor := ""
+	for {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		var page struct {
+			Tools      []Tool `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		if err := c.call(ctx, "tools/list", params, &page); err != nil {
+			return nil, err
+		}
+		tools = append(tools, page.Tools...)
+		if page.NextCursor == "" || page.NextCursor == cursor {
+			return tools, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// CallTool invokes a tool. A tool that reports a failure returns a result with
+// IsError set rather than an error.
+func (c *Client) CallTool(ctx context.Context, name string, arguments map[string]any) (*CallToolResult, error) {
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+	var result CallToolResult
+	if err := c.call(ctx, "tools/call", map[string]any{"name": name, "arguments": arguments}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Ping checks that the server still answers.
+func (c *Client) Ping(ctx context.Context) error {
+	return c.call(ctx, "ping", nil, nil)
+}
+
+// Close ends the session and stops a server launched by Connect.
+func (c *Client) Close() error {
+	err := c.transport.close()
+	if c.process != nil {
+		c.process.stop()
+	}
+	return err
+}
+
+func (c *Client) call(ctx context.Context, method string, params any, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, c.config.timeout())
+	defer cancel()
+
+	raw, err := c.transport.call(ctx, method, params)
+	if err != nil {
+		return err
+	}
+	if result == nil || len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, result); err != nil {
+		return fmt.Errorf("decode %s result: %w", method, err)
+	}
+	return nil
+}
+
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("find free port: %w", err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Stdio(t *testing.T) {
+	ctx := context.Background()
+	client, err := Connect(ctx, fakeConfig("fake"))
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.Equal(t, Implementation{Name: "fake", Version: "0.1.0"}, client.ServerInfo())
+	assert.NotZero(t, client.ProcessID())
+	require.NoError(t, client.Ping(ctx))
+
+	tools, err := client.ListTools(ctx)
+	require.NoError(t, err)
+	require.Len(t, tools, 2, "both pages are listed")
+	assert.Equal(t, "echo", tools[0].Name)
+	assert.Equal(t, "fail", tools[1].Name)
+
+	result, err := client.CallTool(ctx, "echo", map[string]any{"text": "hello"})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "hello", result.Text())
+	assert.JSONEq(t, `{"text":"hello"}`, string(result.StructuredContent))
+
+	result, err = client.CallTool(ctx, "fail", nil)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "boom", result.Text())
+
+	_, err = client.CallTool(ctx, "missing", nil)
+	var rpcErr *RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, -32602, rpcErr.Code)
+}
+
+func TestClient_StdioServerExit(t *testing.T) {
+	client, err := Connect(context.Background(), fakeConfig("fake"))
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.CallTool(context.Background(), "crash", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server exited")
+	assert.Contains(t, err.Error(), "crashed on purpose")
+}
+
+func TestClient_HTTP(t *testing.T) {
+	fake := newFakeHTTPServer(t)
+	ctx := context.Background()
+
+	client, err := Connect(ctx, ServerConfig{Alias: "remote", URL: fake.URL})
+	require.NoError(t, err)
+	assert.Zero(t, client.ProcessID())
+
+	tools, err := client.ListTools(ctx)
+	require.NoError(t, err)
+	assert.Len(t, tools, 2)
+
+	result, err := client.CallTool(ctx, "echo", map[string]any{"text": "over sse"})
+	require.NoError(t, err)
+	assert.Equal(t, "over sse", result.Text())
+
+	require.NoError(t, client.Close())
+	sessions, closed := fake.seenSessions()
+	assert.Equal(t, "", sessions[0], "initialize is sent without a session")
+	for _, session := range sessions[1:] {
+		assert.Equal(t, "session-1", session)
+	}
+	assert.True(t, closed, "closing ends the session")
+}
+
+func TestClient_HTTPProcess(t *testing.T) {
+	cfg := fakeConfig("local")
+	cfg.Transport = TransportHTTP
+	cfg.Environment = map[string]string{fakeServerEnv: "http", "PORT": "{{port}}"}
+
+	client, err := Connect(context.Background(), cfg)
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.NotZero(t, client.port)
+	assert.NotZero(t, client.ProcessID())
+	tools, err := client.ListTools(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, tools, 2)
+}
+
+func TestReadEventStream(t *testing.T) {
+	stream := "event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{\"a\":1}}\n\n" +
+		": keep-alive\n\n" +
+		"data: {\"jsonrpc\":\"2.0\",\n" +
+		"data:  \"id\":2,\"result\":{\"b\":2}}\n"
+
+	msg, err := readEventStream(strings.NewReader(stream), json.RawMessage("2"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"b":2}`, string(msg.Result), "multi-line data is joined and the final event is read at EOF")
+
+	_, err = readEventStream(strings.NewReader(stream), json.RawMessage("3"))
+	assert.Error(t, err)
+}
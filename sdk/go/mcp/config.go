@@ -0,0 +1,173 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// TransportStdio talks to a local server over its stdin and stdout.
+	TransportStdio = "stdio"
+	// TransportHTTP talks to a server over streamable HTTP.
+	TransportHTTP = "http"
+
+	defaultTimeout = 30 * time.Second
+)
+
+// ServerConfig describes an MCP server. It uses the same keys as the entries
+// under dependencies.mcp_servers in agentfield.yaml, which `af add --mcp`
+// writes.
+type ServerConfig struct {
+	Alias       string `yaml:"alias"`
+	Description string `yaml:"description,omitempty"`
+
+	// URL connects to a running HTTP server. Run launches a local server with
+	// `sh -c` instead. Exactly one of them is normally set.
+	URL string `yaml:"url,omitempty"`
+	Run string `yaml:"run,omitempty"`
+
+	// Transport is "stdio" or "http". When empty it is "http" if URL is set and
+	// "stdio" otherwise. A Run command with the http transport is given a free
+	// port through the {{port}} placeholder and reached at /mcp on that port.
+	Transport string `yaml:"transport,omitempty"`
+
+	// WorkingDir defaults to packages/mcp/{alias} when that directory exists
+	// and to the project directory otherwise.
+	WorkingDir  string            `yaml:"working_dir,omitempty"`
+	Environment map[string]string `yaml:"environment,omitempty"`
+	// Timeout bounds each request to the server. Zero means 30 seconds.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	Port    int      `yaml:"port,omitempty"`
+	Version string   `yaml:"version,omitempty"`
+	Tags    []string `yaml:"tags,omitempty"`
+
+	// ProjectDir is the directory holding agentfield.yaml. Relative working
+	// directories and the {{project_dir}}, {{data_dir}} and {{server_dir}}
+	// placeholders resolve against it. Empty means the current directory.
+	ProjectDir string `yaml:"-"`
+}
+
+// LoadConfig reads the MCP servers declared in projectDir/agentfield.yaml,
+// sorted by alias. A missing file or section yields no servers.
+func LoadConfig(projectDir string) ([]ServerConfig, error) {
+	data, err := os.ReadFile(filepath.Join(projectDir, "agentfield.yaml"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read agentfield.yaml: %w", err)
+	}
+
+	var file struct {
+		Dependencies struct {
+			MCPServers map[string]ServerConfig `yaml:"mcp_servers"`
+		} `yaml:"dependencies"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse agentfield.yaml: %w", err)
+	}
+
+	configs := make([]ServerConfig, 0, len(file.Dependencies.MCPServers))
+	for key, cfg := range file.Dependencies.MCPServers {
+		if cfg.Alias == "" {
+			cfg.Alias = key
+		}
+		cfg.ProjectDir = projectDir
+		configs = append(configs, cfg)
+	}
+	sort.Slice(configs, func(i, j int) bool { return configs[i].Alias < configs[j].Alias })
+	return configs, nil
+}
+
+func (c ServerConfig) transport() string {
+	if c.Transport != "" {
+		return strings.ToLower(c.Transport)
+	}
+	if c.URL != "" {
+		return TransportHTTP
+	}
+	return TransportStdio
+}
+
+func (c ServerConfig) timeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return defaultTimeout
+}
+
+func (c ServerConfig) validate() error {
+	if c.Alias == "" {
+		return errors.New("mcp server alias is required")
+	}
+	switch c.transport() {
+	case TransportStdio:
+		if c.Run == "" {
+			return fmt.Errorf("mcp server %s: stdio transport requires a run command", c.Alias)
+		}
+	case TransportHTTP:
+		if c.URL == "" && c.Run == "" {
+			return fmt.Errorf("mcp server %s: http transport requires a url or a run command", c.Alias)
+		}
+	default:
+		return fmt.Errorf("mcp server %s: unsupported transport %q", c.Alias, c.Transport)
+	}
+	return nil
+}
+
+// expand replaces the placeholders the control plane supports in run
+// commands, working directories and environment values.
+func (c ServerConfig) expand(value string, port int) string {
+	projectDir := c.ProjectDir
+	if projectDir == "" {
+		projectDir = "."
+	}
+	dataDir := filepath.Join(projectDir, "packages", "mcp")
+	serverDir := filepath.Join(dataDir, c.Alias)
+
+	return strings.NewReplacer(
+		"{{port}}", strconv.Itoa(port),
+		"{{data_dir}}", dataDir,
+		"{{config_file}}", filepath.Join(serverDir, "config.json"),
+		"{{log_file}}", filepath.Join(serverDir, c.Alias+".log"),
+		"{{server_dir}}", serverDir,
+		"{{project_dir}}", projectDir,
+		"{{alias}}", c.Alias,
+	).Replace(value)
+}
+
+func (c ServerConfig) workingDir(port int) string {
+	projectDir := c.ProjectDir
+	if projectDir == "" {
+		projectDir = "."
+	}
+	if c.WorkingDir != "" {
+		dir := c.expand(c.WorkingDir, port)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(projectDir, dir)
+		}
+		return dir
+	}
+	serverDir := filepath.Join(projectDir, "packages", "mcp", c.Alias)
+	if info, err := os.Stat(serverDir); err == nil && info.IsDir() {
+		return serverDir
+	}
+	return projectDir
+}
+
+func (c ServerConfig) environ(port int) []string {
+	env := os.Environ()
+	for key, value := range c.Environment {
+		env = append(env, key+"="+c.expand(value, port))
+	}
+	return env
+}
@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "agentfield.yaml"), []byte(`
+name: demo
+dependencies:
+  mcp_servers:
+    search:
+      alias: search
+      url: https://mcp.example.com/mcp
+      timeout: 5s
+      tags: [web]
+    files:
+      run: npx -y @modelcontextprotocol/server-filesystem {{project_dir}}
+      environment:
+        LOG_FILE: "{{log_file}}"
+`), 0o644))
+
+	configs, err := LoadConfig(dir)
+	require.NoError(t, err)
+	require.Len(t, configs, 2)
+
+	files, search := configs[0], configs[1]
+	assert.Equal(t, "files", files.Alias, "alias defaults to the map key")
+	assert.Equal(t, TransportStdio, files.transport())
+	assert.Equal(t, defaultTimeout, files.timeout())
+	assert.Equal(t, dir, files.ProjectDir)
+	assert.Equal(t, "npx -y @modelcontextprotocol/server-filesystem "+dir, files.expand(files.Run, 0))
+	assert.Contains(t, files.environ(0), "LOG_FILE="+filepath.Join(dir, "packages", "mcp", "files", "files.log"))
+
+	assert.Equal(t, TransportHTTP, search.transport())
+	assert.Equal(t, 5*time.Second, search.timeout())
+	assert.Equal(t, []string{"web"}, search.Tags)
+
+	configs, err = LoadConfig(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, configs)
+}
+
+func TestServerConfig_WorkingDir(t *testing.T) {
+	dir := t.TempDir()
+	cfg := ServerConfig{Alias: "files", Run: "serve", ProjectDir: dir}
+	assert.Equal(t, dir, cfg.workingDir(0))
+
+	serverDir := filepath.Join(dir, "packages", "mcp", "files")
+	require.NoError(t, os.MkdirAll(serverDir, 0o755))
+	assert.Equal(t, serverDir, cfg.workingDir(0), "installed servers run in their package directory")
+
+	cfg.WorkingDir = "tools"
+	assert.Equal(t, filepath.Join(dir, "tools"), cfg.workingDir(0))
+}
+
+func TestServerConfig_Validate(t *testing.T) {
+	assert.NoError(t, ServerConfig{Alias: "a", Run: "serve"}.validate())
+	assert.NoError(t, ServerConfig{Alias: "a", Run: "serve --port {{port}}", Transport: "http"}.validate())
+	assert.Error(t, ServerConfig{Run: "serve"}.validate())
+	assert.Error(t, ServerConfig{Alias: "a"}.validate())
+	assert.Error(t, ServerConfig{Alias: "a", Transport: "http"}.validate())
+	assert.Error(t, ServerConfig{Alias: "a", Run: "serve", Transport: "websocket"}.validate())
+}
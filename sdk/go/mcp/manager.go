@@ -0,0 +1,317 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Server statuses reported by Health. They match the statuses the control
+// plane shows for MCP servers.
+const (
+	StatusRunning  = "running"
+	StatusStopped  = "stopped"
+	StatusError    = "error"
+	StatusStarting = "starting"
+)
+
+// healthCheckTimeout bounds the ping Health sends to each server, so that a
+// hung server does not hold up the control plane's health poll.
+const healthCheckTimeout = 5 * time.Second
+
+// ErrUnknownServer is returned for an alias the manager was not configured with.
+var ErrUnknownServer = errors.New("unknown mcp server")
+
+// ServerHealth is the state of one server as served at /health/mcp.
+type ServerHealth struct {
+	Alias           string     `json:"alias"`
+	Status          string     `json:"status"`
+	ToolCount       int        `json:"tool_count"`
+	StartedAt       *time.Time `json:"started_at"`
+	LastHealthCheck *time.Time `json:"last_health_check"`
+	ErrorMessage    string     `json:"error_message,omitempty"`
+	Port            int        `json:"port,omitempty"`
+	ProcessID       int        `json:"process_id,omitempty"`
+	SuccessRate     float64    `json:"success_rate,omitempty"`
+	AvgResponseTime int        `json:"avg_response_time_ms,omitempty"`
+}
+
+// HealthSummary aggregates the servers of a HealthResponse. OverallHealth is
+// the fraction of servers running, and 1 when there are none.
+type HealthSummary struct {
+	TotalServers   int     `json:"total_servers"`
+	RunningServers int     `json:"running_servers"`
+	TotalTools     int     `json:"total_tools"`
+	OverallHealth  float64 `json:"overall_health"`
+}
+
+// HealthResponse is the body of /health/mcp.
+type HealthResponse struct {
+	Servers []ServerHealth `json:"servers"`
+	Summary HealthSummary  `json:"summary"`
+}
+
+// Manager runs the MCP servers of an agent and keeps track of their health.
+type Manager struct {
+	aliases []string
+	servers map[string]*server
+}
+
+// server is the state the manager keeps for one configured server.
+type server struct {
+	config ServerConfig
+
+	mu              sync.Mutex
+	client          *Client
+	tools           []Tool
+	status          string
+	errorMessage    string
+	startedAt       *time.Time
+	lastHealthCheck *time.Time
+
+	calls        int
+	failures     int
+	totalLatency time.Duration
+}
+
+// NewManager creates a manager for the given servers. Nothing is started until
+// Start is called.
+func NewManager(configs []ServerConfig) (*Manager, error) {
+	m := &Manager{servers: make(map[string]*server, len(configs))}
+	for _, cfg := range configs {
+		if err := cfg.validate(); err != nil {
+			return nil, err
+		}
+		if _, exists := m.servers[cfg.Alias]; exists {
+			return nil, fmt.Errorf("duplicate mcp server alias %q", cfg.Alias)
+		}
+		m.servers[cfg.Alias] = &server{config: cfg, status: StatusStopped}
+		m.aliases = append(m.aliases, cfg.Alias)
+	}
+	sort.Strings(m.aliases)
+	return m, nil
+}
+
+// Start connects to every server and lists its tools. A server that fails to
+// start is marked as errored while the others keep running; the failures are
+// returned joined together.
+func (m *Manager) Start(ctx context.Context) error {
+	errs := make([]error, len(m.aliases))
+	var wg sync.WaitGroup
+	for i, alias := range m.aliases {
+		wg.Add(1)
+		go func(i int, s *server) {
+			defer wg.Done()
+			errs[i] = s.start(ctx)
+		}(i, m.servers[alias])
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Restart stops a server and starts it again, refreshing its tool list.
+func (m *Manager) Restart(ctx context.Context, alias string) error {
+	s, err := m.server(alias)
+	if err != nil {
+		return err
+	}
+	s.stop(StatusStopped)
+	return s.start(ctx)
+}
+
+// Close stops every server.
+func (m *Manager) Close() error {
+	for _, alias := range m.aliases {
+		m.servers[alias].stop(StatusStopped)
+	}
+	return nil
+}
+
+// Aliases lists the configured servers in alias order.
+func (m *Manager) Aliases() []string {
+	return append([]string(nil), m.aliases...)
+}
+
+// Tools returns the tools a server advertised when it last started.
+func (m *Manager) Tools(alias string) ([]Tool, error) {
+	s, err := m.server(alias)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Tool(nil), s.tools...), nil
+}
+
+// CallTool invokes a tool on a running server and records the outcome in the
+// server's success rate and response time.
+func (m *Manager) CallTool(ctx context.Context, alias, tool string, arguments map[string]any) (*CallToolResult, error) {
+	s, err := m.server(alias)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	client := s.client
+	s.mu.Unlock()
+	if client == nil {
+		return nil, fmt.Errorf("mcp server %s is not running", alias)
+	}
+
+	started := time.Now()
+	result, err := client.CallTool(ctx, tool, arguments)
+	s.record(client, time.Since(started), err == nil && !result.IsError, err)
+	return result, err
+}
+
+// Health pings every running server and reports the state of all of them.
+func (m *Manager) Health(ctx context.Context) HealthResponse {
+	var wg sync.WaitGroup
+	for _, alias := range m.aliases {
+		wg.Add(1)
+		go func(s *server) {
+			defer wg.Done()
+			s.check(ctx)
+		}(m.servers[alias])
+	}
+	wg.Wait()
+
+	resp := HealthResponse{Servers: make([]ServerHealth, 0, len(m.aliases))}
+	for _, alias := range m.aliases {
+		health := m.servers[alias].health()
+		resp.Servers = append(resp.Servers, health)
+		resp.Summary.TotalServers++
+		resp.Summary.TotalTools += health.ToolCount
+		if health.Status == StatusRunning {
+			resp.Summary.RunningServers++
+		}
+	}
+	resp.Summary.OverallHealth = 1
+	if resp.Summary.TotalServers > 0 {
+		resp.Summary.OverallHealth = float64(resp.Summary.RunningServers) / float64(resp.Summary.TotalServers)
+	}
+	return resp
+}
+
+func (m *Manager) server(alias string) (*server, error) {
+	s, ok := m.servers[alias]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownServer, alias)
+	}
+	return s, nil
+}
+
+func (s *server) start(ctx context.Context) error {
+	s.mu.Lock()
+	s.status = StatusStarting
+	s.errorMessage = ""
+	s.mu.Unlock()
+
+	client, err := Connect(ctx, s.config)
+	var tools []Tool
+	if err == nil {
+		if tools, err = client.ListTools(ctx); err != nil {
+			client.Close()
+			err = fmt.Errorf("mcp server %s: list tools: %w", s.config.Alias, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.status = StatusError
+		s.errorMessage = err.Error()
+		return err
+	}
+	now := time.Now().UTC()
+	s.client = client
+	s.tools = tools
+	s.status = StatusRunning
+	s.startedAt = &now
+	s.lastHealthCheck = &now
+	s.calls, s.failures, s.totalLatency = 0, 0, 0
+	return nil
+}
+
+func (s *server) stop(status string) {
+	s.mu.Lock()
+	client := s.client
+	s.client = nil
+	s.tools = nil
+	s.status = status
+	s.mu.Unlock()
+
+	if client != nil {
+		client.Close()
+	}
+}
+
+// check pings a running server and marks it as errored if it does not answer.
+func (s *server) check(ctx context.Context) {
+	s.mu.Lock()
+	client := s.client
+	s.mu.Unlock()
+	if client == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	err := client.Ping(ctx)
+
+	now := time.Now().UTC()
+	s.mu.Lock()
+	s.lastHealthCheck = &now
+	if s.client == client {
+		if err != nil {
+			s.status = StatusError
+			s.errorMessage = fmt.Sprintf("health check failed: %v", err)
+		} else {
+			s.status = StatusRunning
+			s.errorMessage = ""
+		}
+	}
+	s.mu.Unlock()
+}
+
+func (s *server) record(client *Client, latency time.Duration, success bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	s.totalLatency += latency
+	if !success {
+		s.failures++
+	}
+	if err != nil && s.client == client && client.process != nil && !client.process.running() {
+		s.status = StatusError
+		s.errorMessage = client.process.exitError().Error()
+	}
+}
+
+func (s *server) health() ServerHealth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	health := ServerHealth{
+		Alias:           s.config.Alias,
+		Status:          s.status,
+		ToolCount:       len(s.tools),
+		StartedAt:       s.startedAt,
+		LastHealthCheck: s.lastHealthCheck,
+		ErrorMessage:    s.errorMessage,
+		Port:            s.config.Port,
+	}
+	if s.client != nil {
+		health.ProcessID = s.client.ProcessID()
+		if s.client.port != 0 {
+			health.Port = s.client.port
+		}
+	}
+	if s.calls > 0 {
+		health.SuccessRate = float64(s.calls-s.failures) / float64(s.calls)
+		health.AvgResponseTime = int((s.totalLatency / time.Duration(s.calls)).Milliseconds())
+	}
+	return health
+}
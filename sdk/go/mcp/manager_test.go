@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewManager_RejectsDuplicateAliases(t *testing.T) {
+	_, err := NewManager([]ServerConfig{fakeConfig("fake"), fakeConfig("fake")})
+	assert.ErrorContains(t, err, "duplicate")
+
+	_, err = NewManager([]ServerConfig{{Alias: "empty"}})
+	assert.Error(t, err)
+}
+
+func TestManager_StartAndHealth(t *testing.T) {
+	broken := ServerConfig{Alias: "broken", Run: "echo 'no such server' >&2; exit 1"}
+	m, err := NewManager([]ServerConfig{fakeConfig("fake"), broken})
+	require.NoError(t, err)
+	defer m.Close()
+
+	ctx := context.Background()
+	err = m.Start(ctx)
+	require.Error(t, err, "failures are reported")
+	assert.Contains(t, err.Error(), "broken")
+	assert.Equal(t, []string{"broken", "fake"}, m.Aliases())
+
+	result, err := m.CallTool(ctx, "fake", "echo", map[string]any{"text": "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "hi", result.Text())
+	_, err = m.CallTool(ctx, "fake", "fail", nil)
+	require.NoError(t, err)
+	_, err = m.CallTool(ctx, "broken", "echo", nil)
+	assert.ErrorContains(t, err, "not running")
+	_, err = m.CallTool(ctx, "unknown", "echo", nil)
+	assert.ErrorIs(t, err, ErrUnknownServer)
+
+	health := m.Health(ctx)
+	require.Len(t, health.Servers, 2)
+	assert.Equal(t, HealthSummary{TotalServers: 2, RunningServers: 1, TotalTools: 2, OverallHealth: 0.5}, health.Summary)
+
+	brokenHealth, fakeHealth := health.Servers[0], health.Servers[1]
+	assert.Equal(t, StatusError, brokenHealth.Status)
+	assert.Contains(t, brokenHealth.ErrorMessage, "no such server")
+	assert.Nil(t, brokenHealth.StartedAt)
+
+	assert.Equal(t, StatusRunning, fakeHealth.Status)
+	assert.Equal(t, 2, fakeHealth.ToolCount)
+	assert.NotNil(t, fakeHealth.StartedAt)
+	assert.NotNil(t, fakeHealth.LastHealthCheck)
+	assert.NotZero(t, fakeHealth.ProcessID)
+	assert.Equal(t, 0.5, fakeHealth.SuccessRate, "a tool reporting an error counts as a failure")
+}
+
+func TestManager_DetectsExitedServer(t *testing.T) {
+	m, err := NewManager([]ServerConfig{fakeConfig("fake")})
+	require.NoError(t, err)
+	defer m.Close()
+
+	ctx := context.Background()
+	require.NoError(t, m.Start(ctx))
+	pid := m.Health(ctx).Servers[0].ProcessID
+
+	_, err = m.CallTool(ctx, "fake", "crash", nil)
+	require.Error(t, err)
+	health := m.Health(ctx)
+	assert.Equal(t, StatusError, health.Servers[0].Status)
+	assert.Contains(t, health.Servers[0].ErrorMessage, "crashed on purpose")
+	assert.Equal(t, 0.0, health.Summary.OverallHealth)
+
+	require.NoError(t, m.Restart(ctx, "fake"))
+	health = m.Health(ctx)
+	assert.Equal(t, StatusRunning, health.Servers[0].Status)
+	assert.Empty(t, health.Servers[0].ErrorMessage)
+	assert.NotEqual(t, pid, health.Servers[0].ProcessID)
+
+	require.NoError(t, m.Close())
+	assert.Equal(t, StatusStopped, m.Health(ctx).Servers[0].Status)
+}
+
+func TestManager_NoServers(t *testing.T) {
+	m, err := NewManager(nil)
+	require.NoError(t, err)
+	require.NoError(t, m.Start(context.Background()))
+
+	health := m.Health(context.Background())
+	assert.Empty(t, health.Servers)
+	assert.Equal(t, 1.0, health.Summary.OverallHealth)
+}
@@ -0,0 +1,186 @@
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+)
+
+// fakeServerEnv makes the test binary act as a stdio MCP server.
+const fakeServerEnv = "AGENTFIELD_FAKE_MCP_SERVER"
+
+func TestMain(m *testing.M) {
+	switch os.Getenv(fakeServerEnv) {
+	case "":
+	case "http":
+		fake := &fakeHTTPServer{}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/mcp", fake.serve)
+		fmt.Fprintln(os.Stderr, http.ListenAndServe(":"+os.Getenv("PORT"), mux))
+		os.Exit(1)
+	default:
+		serveStdio()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeConfig describes a stdio server backed by the test binary.
+func fakeConfig(alias string) ServerConfig {
+	return ServerConfig{
+		Alias:       alias,
+		Run:         fmt.Sprintf("exec %q", os.Args[0]),
+		Environment: map[string]string{fakeServerEnv: "1"},
+		ProjectDir:  os.TempDir(),
+	}
+}
+
+func serveStdio() {
+	out := bufio.NewWriter(os.Stdout)
+	// Servers sometimes log to stdout; the client must skip such lines.
+	fmt.Fprintln(out, "fake server ready")
+	out.Flush()
+
+	in := bufio.NewScanner(os.Stdin)
+	for in.Scan() {
+		var req rpcMessage
+		if err := json.Unmarshal(in.Bytes(), &req); err != nil || len(req.ID) == 0 {
+			continue
+		}
+		if req.Method == "tools/call" && toolName(req) == "crash" {
+			fmt.Fprintln(os.Stderr, "fatal: crashed on purpose")
+			os.Exit(3)
+		}
+		// A notification ahead of the response must not be mistaken for it.
+		json.NewEncoder(out).Encode(rpcMessage{JSONRPC: "2.0", Method: "notifications/message", Params: map[string]any{"level": "info"}})
+		json.NewEncoder(out).Encode(fakeResponse(req))
+		out.Flush()
+	}
+}
+
+func toolName(req rpcMessage) string {
+	params, _ := req.Params.(map[string]any)
+	name, _ := params["name"].(string)
+	return name
+}
+
+// fakeResponse answers a request the way a small MCP server with an echo tool
+// and a failing tool would. The tool list comes in two pages.
+func fakeResponse(req rpcMessage) rpcMessage {
+	resp := rpcMessage{JSONRPC: "2.0", ID: req.ID}
+	params, _ := req.Params.(map[string]any)
+	var result any
+	switch req.Method {
+	case "initialize":
+		result = map[string]any{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": "fake", "version": "0.1.0"},
+		}
+	case "ping":
+		result = map[string]any{}
+	case "tools/list":
+		if params["cursor"] == "page-2" {
+			result = map[string]any{"tools": []any{
+				map[string]any{"name": "fail", "description": "Always fails", "inputSchema": map[string]any{"type": "object"}},
+			}}
+		} else {
+			result = map[string]any{
+				"tools": []any{map[string]any{
+					"name":        "echo",
+					"description": "Echoes text",
+					"inputSchema": map[string]any{"type": "object", "properties": map[string]any{"text": map[string]any{"type": "string"}}},
+				}},
+				"nextCursor": "page-2",
+			}
+		}
+	case "tools/call":
+		arguments, _ := params["arguments"].(map[string]any)
+		switch toolName(req) {
+		case "echo":
+			result = map[string]any{
+				"content":           []any{map[string]any{"type": "text", "text": fmt.Sprint(arguments["text"])}},
+				"structuredContent": arguments,
+			}
+		case "fail":
+			result = map[string]any{"content": []any{map[string]any{"type": "text", "text": "boom"}}, "isError": true}
+		default:
+			resp.Error = &RPCError{Code: -32602, Message: "unknown tool"}
+		}
+	default:
+		resp.Error = &RPCError{Code: -32601, Message: "method not found"}
+	}
+	if result != nil {
+		resp.Result, _ = json.Marshal(result)
+	}
+	return resp
+}
+
+// fakeHTTPServer serves the fake MCP server over streamable HTTP. It hands out
+// a session on initialize, requires it afterwards and answers tool calls with
+// an event stream.
+type fakeHTTPServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	sessions []string
+	closed   bool
+}
+
+func newFakeHTTPServer(t *testing.T) *fakeHTTPServer {
+	t.Helper()
+	fake := &fakeHTTPServer{}
+	fake.Server = httptest.NewServer(http.HandlerFunc(fake.serve))
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+func (f *fakeHTTPServer) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.sessions = append(f.sessions, r.Header.Get("Mcp-Session-Id"))
+	if r.Method == http.MethodDelete {
+		f.closed = true
+	}
+	f.mu.Unlock()
+	if r.Method == http.MethodDelete {
+		return
+	}
+
+	var req rpcMessage
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Method == "initialize" {
+		w.Header().Set("Mcp-Session-Id", "session-1")
+	} else if r.Header.Get("Mcp-Session-Id") != "session-1" {
+		http.Error(w, "missing session", http.StatusBadRequest)
+		return
+	}
+	if len(req.ID) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if req.Method == "tools/call" {
+		w.Header().Set("Content-Type", "text/event-stream")
+		progress, _ := json.Marshal(rpcMessage{JSONRPC: "2.0", Method: "notifications/progress"})
+		response, _ := json.Marshal(fakeResponse(req))
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", progress)
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", response)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(fakeResponse(req))
+}
+
+func (f *fakeHTTPServer) seenSessions() ([]string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.sessions...), f.closed
+}
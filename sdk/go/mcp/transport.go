@@ -0,0 +1,459 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// stopGrace is how long a server gets to exit after its stdin closes before it
+// is killed.
+const stopGrace = 2 * time.Second
+
+// rpcMessage is a JSON-RPC 2.0 request, notification or response.
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  any             `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+func (m *rpcMessage) result() (json.RawMessage, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	return m.Result, nil
+}
+
+type transport interface {
+	call(ctx context.Context, method string, params any) (json.RawMessage, error)
+	notify(ctx context.Context, method string, params any) error
+	close() error
+}
+
+// process is a server launched with `sh -c`. Stdio servers expose their
+// stdin and stdout; output of other servers only feeds the log tail used in
+// error messages.
+type process struct {
+	cmd    *exec.Cmd
+	stdio  bool
+	stdin  io.WriteCloser
+	stdout io.Reader
+	output *tailBuffer
+
+	done     chan struct{}
+	waitErr  error
+	stopOnce sync.Once
+}
+
+func startProcess(cfg ServerConfig, port int, stdio bool) (*process, error) {
+	cmd := exec.Command("sh", "-c", cfg.expand(cfg.Run, port))
+	cmd.Dir = cfg.workingDir(port)
+	cmd.Env = cfg.environ(port)
+	cmd.WaitDelay = stopGrace
+
+	p := &process{cmd: cmd, stdio: stdio, output: &tailBuffer{limit: 4096}, done: make(chan struct{})}
+	cmd.Stderr = p.output
+
+	var stdoutWriter *io.PipeWriter
+	if stdio {
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, fmt.Errorf("mcp server %s: stdin pipe: %w", cfg.Alias, err)
+		}
+		p.stdin = stdin
+		// An io.Pipe rather than cmd.StdoutPipe lets Wait finish copying the
+		// output before the reader sees EOF, so no trailing response is lost.
+		var stdout *io.PipeReader
+		stdout, stdoutWriter = io.Pipe()
+		p.stdout = stdout
+		cmd.Stdout = stdoutWriter
+	} else {
+		cmd.Stdout = p.output
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("mcp server %s: start %q: %w", cfg.Alias, cfg.Run, err)
+	}
+	go func() {
+		p.waitErr = cmd.Wait()
+		close(p.done)
+		if stdoutWriter != nil {
+			stdoutWriter.Close()
+		}
+	}()
+	return p, nil
+}
+
+// exitError describes why the process ended. It must only be called once done
+// is closed.
+func (p *process) exitError() error {
+	msg := "server exited"
+	if p.waitErr != nil {
+		msg += ": " + p.waitErr.Error()
+	}
+	if tail := strings.TrimSpace(p.output.String()); tail != "" {
+		msg += ": " + tail
+	}
+	return errors.New(msg)
+}
+
+// stop closes stdin, which asks a stdio server to exit, and kills the process
+// if it is still running after stopGrace.
+func (p *process) stop() {
+	p.stopOnce.Do(func() {
+		if p.stdin != nil {
+			p.stdin.Close()
+			select {
+			case <-p.done:
+				return
+			case <-time.After(stopGrace):
+			}
+		}
+		p.cmd.Process.Kill()
+		<-p.done
+	})
+}
+
+func (p *process) running() bool {
+	select {
+	case <-p.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// stdioTransport exchanges newline-delimited JSON-RPC messages with a process.
+type stdioTransport struct {
+	proc *process
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	nextID  int64
+	pending map[int64]chan *rpcMessage
+
+	readerDone chan struct{}
+}
+
+func newStdioTransport(proc *process) *stdioTransport {
+	t := &stdioTransport{
+		proc:       proc,
+		pending:    make(map[int64]chan *rpcMessage),
+		readerDone: make(chan struct{}),
+	}
+	go t.readLoop()
+	return t
+}
+
+func (t *stdioTransport) call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	t.mu.Lock()
+	t.nextID++
+	id := t.nextID
+	reply := make(chan *rpcMessage, 1)
+	t.pending[id] = reply
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, id)
+		t.mu.Unlock()
+	}()
+
+	msg := rpcMessage{JSONRPC: "2.0", ID: json.RawMessage(strconv.FormatInt(id, 10)), Method: method, Params: params}
+	if err := t.write(msg); err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-reply:
+		return resp.result()
+	case <-t.readerDone:
+		return nil, t.proc.exitError()
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%s: %w", method, ctx.Err())
+	}
+}
+
+func (t *stdioTransport) notify(ctx context.Context, method string, params any) error {
+	return t.write(rpcMessage{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (t *stdioTransport) write(msg rpcMessage) error {
+	err := t.send(msg)
+	if err == nil {
+		return nil
+	}
+	// A write usually fails because the server exited; prefer reporting why.
+	select {
+	case <-t.readerDone:
+		return t.proc.exitError()
+	case <-time.After(stopGrace):
+		return err
+	}
+}
+
+func (t *stdioTransport) send(msg rpcMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", msg.Method, err)
+	}
+	data = append(data, '\n')
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.proc.stdin.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", msg.Method, err)
+	}
+	return nil
+}
+
+func (t *stdioTransport) readLoop() {
+	defer close(t.readerDone)
+
+	reader := bufio.NewReader(t.proc.stdout)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			t.dispatch(line)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (t *stdioTransport) dispatch(line []byte) {
+	var msg rpcMessage
+	if err := json.Unmarshal(line, &msg); err != nil {
+		// Some servers log to stdout; anything that is not JSON-RPC is ignored.
+		return
+	}
+
+	if msg.Method != "" {
+		if len(msg.ID) > 0 {
+			t.answer(msg)
+		}
+		return
+	}
+
+	id, err := strconv.ParseInt(string(msg.ID), 10, 64)
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	reply, ok := t.pending[id]
+	t.mu.Unlock()
+	if ok {
+		reply <- &msg
+	}
+}
+
+// answer replies to requests the server sends to the client. Only ping is
+// supported, as the client declares no capabilities.
+func (t *stdioTransport) answer(req rpcMessage) {
+	resp := rpcMessage{JSONRPC: "2.0", ID: req.ID}
+	if req.Method == "ping" {
+		resp.Result = json.RawMessage(`{}`)
+	} else {
+		resp.Error = &RPCError{Code: -32601, Message: "method not found: " + req.Method}
+	}
+	// Sent from the read loop, so it must not wait for the loop to end.
+	t.send(resp)
+}
+
+func (t *stdioTransport) close() error {
+	return t.proc.stdin.Close()
+}
+
+// httpTransport posts JSON-RPC messages to a streamable HTTP endpoint. Servers
+// may answer with a JSON body or with a server-sent event stream.
+type httpTransport struct {
+	url    string
+	client *http.Client
+
+	mu        sync.Mutex
+	nextID    int64
+	sessionID string
+}
+
+func newHTTPTransport(url string) *httpTransport {
+	return &httpTransport{url: url, client: &http.Client{}}
+}
+
+func (t *httpTransport) call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	t.mu.Lock()
+	t.nextID++
+	id := json.RawMessage(strconv.FormatInt(t.nextID, 10))
+	t.mu.Unlock()
+
+	resp, err := t.post(ctx, rpcMessage{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var msg *rpcMessage
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		msg, err = readEventStream(resp.Body, id)
+	} else {
+		msg = &rpcMessage{}
+		err = json.NewDecoder(resp.Body).Decode(msg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s response: %w", method, err)
+	}
+	return msg.result()
+}
+
+func (t *httpTransport) notify(ctx context.Context, method string, params any) error {
+	resp, err := t.post(ctx, rpcMessage{JSONRPC: "2.0", Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (t *httpTransport) post(ctx context.Context, msg rpcMessage) (*http.Response, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", msg.Method, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	t.setSessionHeader(req)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if session := resp.Header.Get("Mcp-Session-Id"); session != "" {
+		t.mu.Lock()
+		t.sessionID = session
+		t.mu.Unlock()
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %s returned status %d: %s", msg.Method, t.url, resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	return resp, nil
+}
+
+func (t *httpTransport) setSessionHeader(req *http.Request) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", t.sessionID)
+	}
+}
+
+// close ends the server-side session, if the server started one.
+func (t *httpTransport) close() error {
+	t.mu.Lock()
+	session := t.sessionID
+	t.mu.Unlock()
+	if session == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), stopGrace)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, t.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Mcp-Session-Id", session)
+	if resp, err := t.client.Do(req); err == nil {
+		resp.Body.Close()
+	}
+	return nil
+}
+
+// readEventStream returns the response with the given id from a server-sent
+// event stream, skipping the notifications and requests sent before it.
+func readEventStream(body io.Reader, id json.RawMessage) (*rpcMessage, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var data []string
+	flush := func() *rpcMessage {
+		defer func() { data = data[:0] }()
+		if len(data) == 0 {
+			return nil
+		}
+		var msg rpcMessage
+		if err := json.Unmarshal([]byte(strings.Join(data, "\n")), &msg); err != nil {
+			return nil
+		}
+		if msg.Method == "" && bytes.Equal(msg.ID, id) {
+			return &msg
+		}
+		return nil
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if msg := flush(); msg != nil {
+				return msg, nil
+			}
+			continue
+		}
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			data = append(data, strings.TrimPrefix(value, " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if msg := flush(); msg != nil {
+		return msg, nil
+	}
+	return nil, errors.New("event stream ended without a response")
+}
+
+func isConnectionRefused(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// tailBuffer keeps the last limit bytes written to it.
+type tailBuffer struct {
+	mu    sync.Mutex
+	buf   []byte
+	limit int
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.limit {
+		b.buf = b.buf[len(b.buf)-b.limit:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf)
+}
@@ -0,0 +1,149 @@
+package types
+
+import "encoding/json"
+
+// DIDIdentity is one decentralized identity issued by the control plane. The
+// keys are Ed25519 JSON Web Keys serialized as strings.
+type DIDIdentity struct {
+	DID            string `json:"did"`
+	PrivateKeyJWK  string `json:"private_key_jwk"`
+	PublicKeyJWK   string `json:"public_key_jwk"`
+	DerivationPath string `json:"derivation_path"`
+	ComponentType  string `json:"component_type"`
+	FunctionName   string `json:"function_name,omitempty"`
+}
+
+// DIDIdentityPackage holds the identities of an agent node and of each of its
+// reasoners and skills.
+type DIDIdentityPackage struct {
+	AgentDID           DIDIdentity            `json:"agent_did"`
+	ReasonerDIDs       map[string]DIDIdentity `json:"reasoner_dids"`
+	SkillDIDs          map[string]DIDIdentity `json:"skill_dids"`
+	AgentFieldServerID string                 `json:"agentfield_server_id"`
+}
+
+// DIDRegistrationRequest asks the control plane for the identity package of a node.
+type DIDRegistrationRequest struct {
+	AgentNodeID string               `json:"agent_node_id"`
+	Reasoners   []ReasonerDefinition `json:"reasoners"`
+	Skills      []SkillDefinition    `json:"skills"`
+}
+
+// DIDRegistrationResponse carries the identity package issued to a node.
+type DIDRegistrationResponse struct {
+	Success         bool               `json:"success"`
+	IdentityPackage DIDIdentityPackage `json:"identity_package"`
+	Message         string             `json:"message,omitempty"`
+	Error           string             `json:"error,omitempty"`
+}
+
+// DIDResolution is the public part of an identity, as resolved by the control plane.
+type DIDResolution struct {
+	DID            string `json:"did"`
+	PublicKeyJWK   string `json:"public_key_jwk"`
+	ComponentType  string `json:"component_type"`
+	FunctionName   string `json:"function_name"`
+	DerivationPath string `json:"derivation_path"`
+}
+
+// VCDocument is a W3C verifiable credential attesting to one execution. The
+// field order matches the control plane's, which signs and verifies the JSON
+// encoding of the document without its proof.
+type VCDocument struct {
+	Context           []string            `json:"@context"`
+	Type              []string            `json:"type"`
+	ID                string              `json:"id"`
+	Issuer            string              `json:"issuer"`
+	IssuanceDate      string              `json:"issuanceDate"`
+	CredentialSubject VCCredentialSubject `json:"credentialSubject"`
+	Proof             VCProof             `json:"proof"`
+}
+
+// VCCredentialSubject describes the execution a credential attests to.
+type VCCredentialSubject struct {
+	ExecutionID string      `json:"executionId"`
+	WorkflowID  string      `json:"workflowId"`
+	SessionID   string      `json:"sessionId"`
+	Caller      VCCaller    `json:"caller"`
+	Target      VCTarget    `json:"target"`
+	Execution   VCExecution `json:"execution"`
+	Audit       VCAudit     `json:"audit"`
+}
+
+// VCCaller identifies who requested the execution.
+type VCCaller struct {
+	DID          string `json:"did"`
+	Type         string `json:"type"`
+	AgentNodeDID string `json:"agentNodeDid"`
+}
+
+// VCTarget identifies the reasoner or skill that ran.
+type VCTarget struct {
+	DID          string `json:"did"`
+	AgentNodeDID string `json:"agentNodeDid"`
+	FunctionName string `json:"functionName"`
+}
+
+// VCExecution records the hashed input and output and the outcome of the execution.
+type VCExecution struct {
+	InputHash    string `json:"inputHash"`
+	OutputHash   string `json:"outputHash"`
+	Timestamp    string `json:"timestamp"`
+	DurationMS   int    `json:"durationMs"`
+	Status       string `json:"status"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+// VCAudit carries the audit trail fields of a credential.
+type VCAudit struct {
+	InputDataHash  string         `json:"inputDataHash"`
+	OutputDataHash string         `json:"outputDataHash"`
+	Metadata       map[string]any `json:"metadata"`
+}
+
+// VCProof is the Ed25519 signature over a credential.
+type VCProof struct {
+	Type               string `json:"type"`
+	Created            string `json:"created"`
+	VerificationMethod string `json:"verificationMethod"`
+	ProofPurpose       string `json:"proofPurpose"`
+	ProofValue         string `json:"proofValue"`
+}
+
+// ExecutionVCRequest submits a credential the agent signed to the control plane.
+type ExecutionVCRequest struct {
+	VCDocument json.RawMessage `json:"vc_document"`
+}
+
+// ExecutionVCResponse describes a credential recorded by the control plane.
+// Only Message is set when the control plane does not keep execution credentials.
+type ExecutionVCResponse struct {
+	VCID        string          `json:"vc_id"`
+	ExecutionID string          `json:"execution_id"`
+	WorkflowID  string          `json:"workflow_id"`
+	SessionID   string          `json:"session_id"`
+	IssuerDID   string          `json:"issuer_did"`
+	TargetDID   string          `json:"target_did"`
+	CallerDID   string          `json:"caller_did"`
+	VCDocument  json.RawMessage `json:"vc_document,omitempty"`
+	Signature   string          `json:"signature"`
+	InputHash   string          `json:"input_hash"`
+	OutputHash  string          `json:"output_hash"`
+	Status      string          `json:"status"`
+	CreatedAt   string          `json:"created_at"`
+	Message     string          `json:"message,omitempty"`
+}
+
+// VCVerificationRequest asks the control plane to verify a credential.
+type VCVerificationRequest struct {
+	VCDocument json.RawMessage `json:"vc_document"`
+}
+
+// VCVerificationResponse reports whether a credential verified.
+type VCVerificationResponse struct {
+	Valid     bool   `json:"valid"`
+	IssuerDID string `json:"issuer_did,omitempty"`
+	IssuedAt  string `json:"issued_at,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
@@ -10,6 +10,21 @@ type ReasonerDefinition struct {
 	ID           string          `json:"id"`
 	InputSchema  json.RawMessage `json:"input_schema"`
 	OutputSchema json.RawMessage `json:"output_schema"`
+	RetryPolicy  *RetryPolicy    `json:"retry_policy,omitempty"`
+}
+
+// RetryPolicy tells the control plane how to retry a failed call to a reasoner.
+// Unset fields fall back to server defaults: exponential backoff from 500ms, capped at
+// 30s, retrying 502/503/504 responses, timeouts and connection errors.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of calls, including the first one.
+	MaxAttempts          int     `json:"max_attempts"`
+	InitialBackoffMS     int64   `json:"initial_backoff_ms,omitempty"`
+	MaxBackoffMS         int64   `json:"max_backoff_ms,omitempty"`
+	BackoffMultiplier    float64 `json:"backoff_multiplier,omitempty"`
+	RetryableStatusCodes []int   `json:"retryable_status_codes,omitempty"`
+	// RetryableErrors lists transport error classes: "timeout" and "connection".
+	RetryableErrors []string `json:"retryable_errors,omitempty"`
 }
 
 // SkillDefinition is included for completeness.
@@ -29,6 +44,7 @@ type CommunicationConfig struct {
 // NodeRegistrationRequest is the legacy-compatible registration payload.
 type NodeRegistrationRequest struct {
 	ID                   string               `json:"id"`
+	InstanceID           string               `json:"instance_id,omitempty"`
 	TeamID               string               `json:"team_id"`
 	BaseURL              string               `json:"base_url"`
 	Version              string               `json:"version"`
@@ -60,6 +76,7 @@ type NodeRegistrationResponse struct {
 type NodeStatusUpdate struct {
 	Phase       string `json:"phase"`
 	HealthScore *int   `json:"health_score,omitempty"`
+	InstanceID  string `json:"instance_id,omitempty"`
 }
 
 // LeaseResponse informs the agent how long the lease lasts.
@@ -67,9 +84,13 @@ type LeaseResponse struct {
 	LeaseSeconds     int    `json:"lease_seconds"`
 	NextLeaseRenewal string `json:"next_lease_renewal"`
 	Message          string `json:"message,omitempty"`
+	// Cancelled is set on an action acknowledgement when the execution was
+	// cancelled and the node should stop working on it.
+	Cancelled bool `json:"cancelled,omitempty"`
 }
 
-// ActionAckRequest accompanies push-based workloads.
+// ActionAckRequest settles an action handed to the node, or keeps its lease alive
+// when Status is not terminal.
 type ActionAckRequest struct {
 	ActionID   string          `json:"action_id"`
 	Status     string          `json:"status"`
@@ -80,10 +101,43 @@ type ActionAckRequest struct {
 	Notes      []string        `json:"notes,omitempty"`
 }
 
+// ActionClaimRequest asks the control plane for work queued to a poll-mode node.
+type ActionClaimRequest struct {
+	NodeID      string `json:"node_id"`
+	MaxItems    int    `json:"max_items,omitempty"`
+	WaitSeconds int    `json:"wait_seconds,omitempty"`
+}
+
+// ClaimedAction is a unit of work leased to a poll-mode node. It must be settled
+// with an ActionAckRequest before the lease expires or it will be offered again.
+type ClaimedAction struct {
+	ActionID          string          `json:"action_id"`
+	ExecutionID       string          `json:"execution_id"`
+	RunID             string          `json:"run_id"`
+	ParentExecutionID *string         `json:"parent_execution_id,omitempty"`
+	SessionID         *string         `json:"session_id,omitempty"`
+	ActorID           *string         `json:"actor_id,omitempty"`
+	NodeID            string          `json:"node_id"`
+	Target            string          `json:"target"`
+	Type              string          `json:"type"`
+	Input             json.RawMessage `json:"input"`
+	Attempt           int             `json:"attempt"`
+	LeaseExpiresAt    *time.Time      `json:"lease_expires_at,omitempty"`
+}
+
+// ActionClaimResponse carries the leased actions and the polling cadence.
+type ActionClaimResponse struct {
+	Items            []ClaimedAction `json:"items"`
+	LeaseSeconds     int             `json:"lease_seconds"`
+	NextPollAfter    int             `json:"next_poll_after"`
+	NextLeaseRenewal string          `json:"next_lease_renewal"`
+}
+
 // ShutdownRequest notifies the control plane that the node is draining.
 type ShutdownRequest struct {
 	Reason          string `json:"reason,omitempty"`
 	ExpectedRestart string `json:"expected_restart,omitempty"`
+	InstanceID      string `json:"instance_id,omitempty"`
 }
 
 // WorkflowExecutionEvent mirrors the control plane's event ingestion payload.
@@ -104,3 +158,46 @@ type WorkflowExecutionEvent struct {
 	Error             string                 `json:"error,omitempty"`
 	DurationMS        *int64                 `json:"duration_ms,omitempty"`
 }
+
+// Approval statuses reported by the control plane.
+const (
+	ApprovalStatusPending  = "pending"
+	ApprovalStatusApproved = "approved"
+	ApprovalStatusRejected = "rejected"
+)
+
+// ApprovalRequest asks a human to approve an action before the execution continues.
+type ApprovalRequest struct {
+	Title       string          `json:"title"`
+	Description string          `json:"description,omitempty"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	// TimeoutSeconds and DefaultDecision override the control plane defaults for when
+	// nobody decides in time.
+	TimeoutSeconds  int    `json:"timeout_seconds,omitempty"`
+	DefaultDecision string `json:"default_decision,omitempty"`
+}
+
+// Approval mirrors the control plane's approval record.
+type Approval struct {
+	ApprovalID      string          `json:"approval_id"`
+	ExecutionID     string          `json:"execution_id"`
+	RunID           string          `json:"run_id"`
+	AgentNodeID     string          `json:"agent_node_id"`
+	ReasonerID      string          `json:"reasoner_id"`
+	Title           string          `json:"title"`
+	Description     string          `json:"description,omitempty"`
+	Payload         json.RawMessage `json:"payload,omitempty"`
+	Status          string          `json:"status"`
+	DefaultDecision string          `json:"default_decision"`
+	ExpiresAt       *time.Time      `json:"expires_at,omitempty"`
+	Comment         *string         `json:"comment,omitempty"`
+	DecidedBy       *string         `json:"decided_by,omitempty"`
+	TimedOut        bool            `json:"timed_out"`
+	CreatedAt       time.Time       `json:"created_at"`
+	DecidedAt       *time.Time      `json:"decided_at,omitempty"`
+}
+
+// Approved reports whether the approval was granted.
+func (a *Approval) Approved() bool {
+	return a.Status == ApprovalStatusApproved
+}
@@ -35,6 +35,7 @@ from agentfield.dynamic_skills import DynamicMCPSkillManager
 from agentfield.execution_context import (
     ExecutionContext,
     get_current_context,
+    pop_injected_memory,
     reset_execution_context,
     set_execution_context,
 )
@@ -666,7 +667,8 @@ class Agent(FastAPI):
                 elif parts:
                     reasoner_name = parts[-1]
 
-        input_data = event.get("input") or event.get("input_data", {})
+        input_data = dict(event.get("input") or event.get("input_data", {}))
+        injected_memory = pop_injected_memory(input_data)
         execution_context_data = (
             event.get("execution_context") or event.get("executionContext") or {}
         )
@@ -705,10 +707,12 @@ class Agent(FastAPI):
             workflow_id=workflow_id,
             parent_workflow_id=execution_context_data.get("parent_workflow_id"),
             root_workflow_id=execution_context_data.get("root_workflow_id"),
+            injected_memory=injected_memory,
         )
 
-        # Set execution context
+        # Set execution context; reasoners read it, with the injected memory, via app.ctx
         self._current_execution_context = execution_context
+        context_token = set_execution_context(execution_context)
 
         try:
             # Find and execute the target function
@@ -732,6 +736,7 @@ class Agent(FastAPI):
             return {"statusCode": 500, "body": {"error": str(e)}}
         finally:
             # Clean up execution context
+            reset_execution_context(context_token)
             self._current_execution_context = None
 
     def _handle_discovery(self) -> dict:
@@ -927,6 +932,7 @@ class Agent(FastAPI):
         - session_id: Session identifier (if available)
         - actor_id: Actor/user identifier (if available)
         - parent_execution_id: Parent execution for nested calls
+        - injected_memory: Values of the keys listed in memory_config.auto_inject
 
         Returns:
             ExecutionContext: The current execution context if available.
@@ -1392,6 +1398,14 @@ class Agent(FastAPI):
 
         return decorator
 
+    async def _read_injected_memory(self, request: Request) -> Dict[str, Any]:
+        """Return the memory the control plane injected into the request body."""
+        try:
+            body = await request.json()
+        except Exception:
+            return {}
+        return pop_injected_memory(body)
+
     async def _execute_reasoner_endpoint(
         self,
         *,
@@ -1405,6 +1419,7 @@ class Agent(FastAPI):
         import time
 
         execution_context = ExecutionContext.from_request(request, self.node_id)
+        execution_context.injected_memory = await self._read_injected_memory(request)
         payload_dict = input_model.model_dump()
 
         self._current_execution_context = execution_context
@@ -5,7 +5,7 @@ Minimal execution context helpers for the simplified run-based pipeline.
 import contextvars
 import time
 import uuid
-from dataclasses import dataclass
+from dataclasses import dataclass, field
 from typing import Any, Dict, Optional
 
 
@@ -18,6 +18,10 @@ _CALLER_DID_HEADER = "X-Caller-DID"
 _TARGET_DID_HEADER = "X-Target-DID"
 _AGENT_DID_HEADER = "X-Agent-Node-DID"
 
+# Reserved input field under which the control plane passes the memory a
+# reasoner declares in memory_config.auto_inject.
+INJECTED_MEMORY_FIELD = "__memory"
+
 
 @dataclass
 class ExecutionContext:
@@ -41,6 +45,8 @@ class ExecutionContext:
     parent_workflow_id: Optional[str] = None
     root_workflow_id: Optional[str] = None
     registered: bool = False
+    # Memory values the control plane injected for this execution, by key.
+    injected_memory: Dict[str, Any] = field(default_factory=dict)
 
     def __post_init__(self) -> None:
         if not self.started_at:
@@ -212,6 +218,20 @@ class ExecutionContext:
         return context
 
 
+def pop_injected_memory(payload: Any) -> Dict[str, Any]:
+    """
+    Remove the injected memory field from a reasoner payload and return it.
+
+    The field is not a reasoner parameter, so it is taken out before the payload
+    is bound to the reasoner's signature.
+    """
+
+    if not isinstance(payload, dict):
+        return {}
+    injected = payload.pop(INJECTED_MEMORY_FIELD, None)
+    return injected if isinstance(injected, dict) else {}
+
+
 class ExecutionContextManager:
     """Async-safe access to the current execution context."""
 
@@ -3,6 +3,7 @@ import pytest
 from agentfield.execution_context import (
     ExecutionContext,
     generate_execution_id,
+    pop_injected_memory,
     set_execution_context,
     reset_execution_context,
 )
@@ -60,6 +61,16 @@ def test_child_context_derives_from_parent():
     assert not child.registered
 
 
+@pytest.mark.unit
+def test_pop_injected_memory_removes_reserved_field():
+    payload = {"question": "hi", "__memory": {"profile": {"name": "ada"}}}
+
+    assert pop_injected_memory(payload) == {"profile": {"name": "ada"}}
+    assert payload == {"question": "hi"}
+    assert pop_injected_memory(payload) == {}
+    assert pop_injected_memory(None) == {}
+
+
 @pytest.mark.unit
 def test_generate_execution_id_has_unique_prefix():
     first = generate_execution_id()